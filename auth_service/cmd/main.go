@@ -1,25 +1,47 @@
 package main
 
 import (
+	"context"
 	"database/sql"
+	"flag"
+	"fmt"
+	"net"
 	"net/http"
 	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/cors"
-	"github.com/golang-migrate/migrate/v4"
-	"github.com/golang-migrate/migrate/v4/database/sqlite3"
-	_ "github.com/golang-migrate/migrate/v4/source/file"
 	"github.com/kprf42/dolgova/auth_service/internal/config"
+	grpcdelivery "github.com/kprf42/dolgova/auth_service/internal/delivery/grpc"
 	myHttp "github.com/kprf42/dolgova/auth_service/internal/delivery/http"
+	"github.com/kprf42/dolgova/auth_service/internal/entity"
+	"github.com/kprf42/dolgova/auth_service/internal/forumclient"
+	"github.com/kprf42/dolgova/auth_service/internal/mail"
 	"github.com/kprf42/dolgova/auth_service/internal/repository"
 	"github.com/kprf42/dolgova/auth_service/internal/usecase/auth"
 	"github.com/kprf42/dolgova/auth_service/internal/usecase/jwt"
+	"github.com/kprf42/dolgova/auth_service/migrations"
+	"github.com/kprf42/dolgova/pkg/audit"
+	"github.com/kprf42/dolgova/pkg/csrf"
+	"github.com/kprf42/dolgova/pkg/ipban"
+	"github.com/kprf42/dolgova/pkg/lifecycle"
 	"github.com/kprf42/dolgova/pkg/logger"
+	"github.com/kprf42/dolgova/pkg/mailer"
+	"github.com/kprf42/dolgova/pkg/secheaders"
+	"github.com/kprf42/dolgova/pkg/uploads"
+	authpb "github.com/kprf42/dolgova/proto/auth/v1"
 	_ "github.com/mattn/go-sqlite3"
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
 )
 
 func main() {
+	configPath := flag.String("config", "", "файл конфигурации YAML или JSON; по умолчанию CONFIG_FILE")
+	flag.Parse()
+
 	// Инициализация логгера
 	log, err := logger.New()
 	if err != nil {
@@ -30,10 +52,37 @@ func main() {
 	log.Info("Starting auth service initialization")
 
 	// Загрузка конфигурации
-	cfg, err := config.New()
+	cfg, err := config.New(*configPath)
 	if err != nil {
 		log.Fatal("Failed to load config", logger.Error(err))
 	}
+	if cfg.JWTSecretGenerated {
+		log.Warn("JWT_SECRET is not set, using a random secret: tokens will not survive a restart")
+	}
+
+	// Настройки, применяемые без перезапуска
+	// Уровень логирования по умолчанию задается LOG_LEVEL, runtime.json может его переопределить
+	runtimeDefaults := config.DefaultRuntime()
+	runtimeDefaults.LogLevel = log.Level()
+	runtimeCfg, err := config.NewRuntimeWatcher(cfg.RuntimeConfigPath, runtimeDefaults, log)
+	if err != nil {
+		log.Fatal("Failed to load runtime config", logger.Error(err))
+	}
+	appliedLevel := ""
+	runtimeCfg.OnChange(func(rt config.Runtime) {
+		// Уровень применяется, только если он изменился в файле
+		if rt.LogLevel == appliedLevel {
+			return
+		}
+		appliedLevel = rt.LogLevel
+		if err := log.SetLevel(rt.LogLevel); err != nil {
+			log.Error("Failed to apply log level", logger.String("level", rt.LogLevel), logger.Error(err))
+		}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go runtimeCfg.Watch(ctx, 5*time.Second)
 
 	// Инициализация базы данных
 	db, err := sql.Open("sqlite3", cfg.DBPath)
@@ -52,39 +101,322 @@ func main() {
 	}
 
 	// Применение миграций
-	if err := applyMigrations(db); err != nil {
+	migrator, err := applyMigrations(db)
+	if err != nil {
 		log.Fatal("Failed to apply migrations", logger.Error(err))
 	}
 
+	// Зависимости, без которых сервис не готов принимать запросы (/readyz)
+	readiness := []myHttp.HealthCheck{{Name: "database", Check: db.PingContext}}
+
 	// Инициализация репозиториев
-	userRepo := repository.NewUserRepository(db, log)
+	userRepo, closeUsers, err := newUserRepository(cfg, db, log)
+	if err != nil {
+		log.Fatal("Failed to initialize user store", logger.Error(err))
+	}
+	defer closeUsers()
+	sessions := repository.NewSessionRepository(db, log)
+	// Записи о токенах и сессиях нужны только до истечения их срока; в Redis они истекают сами
+	expiring := []expiringStore{sessions}
+	var tokenStore repository.TokenStore
+	if cfg.RedisURL != "" {
+		rdb, err := newRedisClient(ctx, cfg.RedisURL)
+		if err != nil {
+			log.Fatal("Failed to connect to redis", logger.Error(err))
+		}
+		defer func() {
+			if err := rdb.Close(); err != nil {
+				log.Error("Failed to close redis", logger.Error(err))
+			}
+		}()
+		tokenStore = repository.NewRedisTokenStore(rdb, log)
+		readiness = append(readiness, myHttp.HealthCheck{Name: "redis", Check: func(ctx context.Context) error {
+			return rdb.Ping(ctx).Err()
+		}})
+		log.Info("Using Redis token store")
+	} else {
+		sqliteTokens := repository.NewSQLiteTokenStore(db, log)
+		tokenStore = sqliteTokens
+		expiring = append(expiring, sqliteTokens)
+	}
+	authEvents := repository.NewAuthEventRepository(db, log)
+	auditLog := audit.NewStore(db)
 
 	// Настройка времени жизни токенов
 	accessExpiry := 15 * time.Minute
 	refreshExpiry := 7 * 24 * time.Hour
 
 	// Инициализация use cases
-	authUC := auth.NewAuthUseCase(*userRepo, cfg.JWTSecret, accessExpiry, refreshExpiry, log)
-	jwtService := jwt.NewJWTService(cfg.JWTSecret, accessExpiry, refreshExpiry)
+	// Ключ из JWT_SECRET действует всегда, ключи из JWT_KEYS сменяют его по расписанию
+	signingKeys := []jwt.SigningKey{{ID: jwt.DefaultKeyID, Secret: cfg.JWTSecret}}
+	for _, key := range cfg.JWTKeys {
+		signingKeys = append(signingKeys, jwt.SigningKey{ID: key.ID, Secret: key.Secret, ActiveAt: key.ActiveAt})
+	}
+	jwtService := jwt.NewJWTService(signingKeys, accessExpiry, refreshExpiry, tokenStore.Revoked())
+	log.Info("JWT signing keys loaded",
+		logger.Int("keys", len(signingKeys)),
+		logger.String("signing_kid", jwtService.SigningKeyID(time.Now())))
+
+	passwordPolicy, err := auth.NewPasswordPolicy(auth.PasswordPolicyConfig{
+		MinLength:  cfg.PasswordMinLength,
+		MaxLength:  cfg.PasswordMaxLength,
+		MinClasses: cfg.PasswordMinClasses,
+		BanCommon:  cfg.PasswordBanCommon,
+		BannedFile: cfg.PasswordBannedFile,
+	})
+	if err != nil {
+		log.Fatal("Failed to load password policy", logger.Error(err))
+	}
+
+	authUC := auth.NewAuthUseCase(userRepo, tokenStore, sessions, authEvents, auth.LockoutPolicy{
+		MaxFailures:   cfg.LoginMaxFailures,
+		MaxIPFailures: cfg.LoginMaxIPFailures,
+		Duration:      cfg.LoginLockout,
+	}, passwordPolicy, auditLog, jwtService, log)
+
+	// Перец паролей; хеши без перца или со старым перцем пересчитываются при входе
+	if len(cfg.PasswordPeppers) > 0 {
+		peppers := make([]auth.Pepper, 0, len(cfg.PasswordPeppers))
+		for _, p := range cfg.PasswordPeppers {
+			peppers = append(peppers, auth.Pepper{ID: p.ID, Secret: p.Secret})
+		}
+		hasher, err := auth.NewPasswordHasher(peppers, cfg.PasswordPepperID)
+		if err != nil {
+			log.Fatal("Failed to configure password pepper", logger.Error(err))
+		}
+		authUC.PepperPasswords(hasher)
+		log.Info("Password pepper enabled", logger.String("pepper_id", cfg.PasswordPepperID))
+	}
+
+	go purgeExpiredTokens(ctx, time.Hour, log, expiring...)
+
+	// Внешние провайдеры входа включаются заданием OAuth клиента
+	oauthProviders := auth.NewOAuthProviders()
+	if cfg.GoogleClientID != "" {
+		oauthProviders.Register(auth.NewGoogleOAuth(auth.OAuthConfig{
+			ClientID:     cfg.GoogleClientID,
+			ClientSecret: cfg.GoogleClientSecret,
+			RedirectURL:  cfg.GoogleRedirectURL,
+		}))
+	}
+	if cfg.GitHubClientID != "" {
+		oauthProviders.Register(auth.NewGitHubOAuth(auth.OAuthConfig{
+			ClientID:     cfg.GitHubClientID,
+			ClientSecret: cfg.GitHubClientSecret,
+			RedirectURL:  cfg.GitHubRedirectURL,
+		}))
+	}
+	if names := oauthProviders.Names(); len(names) > 0 {
+		log.Info("OAuth login enabled", logger.Strings("providers", names))
+	}
+
+	// Вход через SAML SSO включается заданием SAML_BASE_URL и списка IdP в файле конфигурации
+	samlProviders, err := newSAMLProviders(ctx, cfg)
+	if err != nil {
+		log.Fatal("Failed to configure SAML", logger.Error(err))
+	}
+	if names := samlProviders.Names(); len(names) > 0 {
+		log.Info("SAML login enabled", logger.Strings("providers", names))
+	}
+
+	// Письма отправляются в фоне с повторными попытками; без MAIL_DRIVER почты нет
+	var mailQueue *mailer.AsyncSender
+	var mails *mail.Mailer
+	if cfg.MailDriver != "" {
+		sender, err := newMailSender(cfg)
+		if err != nil {
+			log.Fatal("Failed to initialize mail sender", logger.Error(err))
+		}
+		mailQueue = mailer.NewAsyncSender(sender, mailer.AsyncConfig{}, log)
+		if mails, err = mail.New(mailQueue); err != nil {
+			log.Fatal("Failed to load mail templates", logger.Error(err))
+		}
+		log.Info("Mail delivery enabled", logger.String("driver", cfg.MailDriver))
+	}
+
+	// Без почты ссылка для входа пишется в лог, поэтому только вне production
+	var magicLinks *auth.MagicLinks
+	if cfg.MagicLinkURL != "" {
+		var linkSender auth.LinkSender
+		switch {
+		case mails != nil:
+			linkSender = mails
+		case cfg.Env != "production":
+			linkSender = logLinkSender{log: log}
+		}
+
+		if linkSender == nil {
+			log.Warn("Magic link login disabled: MAIL_DRIVER is not set")
+		} else {
+			magicLinks = auth.NewMagicLinks(authUC, linkSender, auth.MagicLinkConfig{
+				URL: cfg.MagicLinkURL,
+				TTL: cfg.MagicLinkTTL,
+			})
+			log.Info("Magic link login enabled", logger.String("ttl", cfg.MagicLinkTTL.String()))
+		}
+	}
+
+	// Письма о входе с нового устройства; без почты ссылка из письма тоже пишется в лог
+	var devices *auth.Devices
+	if cfg.SessionRevokeURL != "" {
+		var alertSender auth.DeviceAlertSender
+		switch {
+		case mails != nil:
+			alertSender = mails
+		case cfg.Env != "production":
+			alertSender = logLinkSender{log: log}
+		}
+
+		if alertSender == nil {
+			log.Warn("New device alerts disabled: MAIL_DRIVER is not set")
+		} else {
+			devices = auth.NewDevices(authUC, repository.NewDeviceRepository(db, log), alertSender, cfg.SessionRevokeURL)
+			authUC.NotifyNewDevices(devices)
+			log.Info("New device alerts enabled")
+		}
+	}
+
+	// Аватары: в S3, если задан бакет, иначе на локальном диске с раздачей через /static/avatars
+	var avatars *auth.Avatars
+	var avatarFiles *uploads.LocalStorage
+	switch {
+	case cfg.AvatarS3Bucket != "":
+		avatars = auth.NewAvatars(authUC, uploads.NewS3Storage(uploads.S3Config{
+			Bucket:    cfg.AvatarS3Bucket,
+			Region:    cfg.AvatarS3Region,
+			Endpoint:  cfg.AvatarS3Endpoint,
+			AccessKey: cfg.AvatarS3AccessKey,
+			SecretKey: cfg.AvatarS3SecretKey,
+			PublicURL: cfg.AvatarPublicURL,
+		}))
+		log.Info("Avatar uploads enabled", logger.String("storage", "s3"), logger.String("bucket", cfg.AvatarS3Bucket))
+	case cfg.AvatarPublicURL != "":
+		avatarFiles, err = uploads.NewLocalStorage(cfg.AvatarDir, cfg.AvatarPublicURL)
+		if err != nil {
+			log.Fatal("Failed to init avatar storage", logger.Error(err))
+		}
+		avatars = auth.NewAvatars(authUC, avatarFiles)
+		log.Info("Avatar uploads enabled", logger.String("storage", "local"), logger.String("dir", cfg.AvatarDir))
+	default:
+		log.Warn("Avatar uploads disabled: AVATAR_PUBLIC_URL is not set")
+	}
+
+	// Удаление аккаунта и выгрузка данных затрагивают контент пользователя на форуме, поэтому без форума выключены
+	var deletion *auth.AccountDeletion
+	var exports *auth.DataExports
+	if cfg.ForumGRPCAddr != "" {
+		forumClient, err := forumclient.New(cfg.ForumGRPCAddr, log)
+		if err != nil {
+			log.Fatal("Failed to create forum client", logger.Error(err))
+		}
+		defer forumClient.Close()
+		deletion = auth.NewAccountDeletion(authUC, forumClient)
+		exports, err = auth.NewDataExports(authUC, repository.NewDataExportRepository(db, log), forumClient, auth.DataExportConfig{
+			Dir: cfg.ExportDir,
+			TTL: cfg.ExportTTL,
+		})
+		if err != nil {
+			log.Fatal("Failed to init data exports", logger.Error(err))
+		}
+	} else {
+		log.Warn("Account deletion and data export disabled: FORUM_GRPC_ADDR is not set")
+	}
+
+	apiKeys := auth.NewAPIKeys(authUC, repository.NewAPIKeyRepository(db, log))
+	invites := auth.NewInvites(authUC, repository.NewInviteRepository(db, log))
+	if cfg.InviteOnly {
+		authUC.RequireInvites(invites)
+		log.Info("Registration is invite-only")
+	}
 
 	// Инициализация HTTP обработчиков
-	authHandler := myHttp.NewAuthHTTPHandler(authUC, jwtService)
+	authHandler := myHttp.NewAuthHTTPHandler(authUC, jwtService, auditLog, oauthProviders, samlProviders, magicLinks, avatars, deletion, exports, apiKeys, invites, devices, myHttp.CookieConfig{
+		Enabled: cfg.CookieAuth,
+		Secure:  cfg.CookieSecure,
+		TTL:     accessExpiry,
+	})
+	healthHandler := myHttp.NewHealthHandler(migrator, readiness...)
+
+	// Баны по IP ведет админка форума; список периодически перечитывается из общей БД.
+	// Таблицы может еще не быть, если миграции форума не применялись, - тогда работаем без банов.
+	bans := ipban.NewChecker(ipban.NewStore(db), log)
+	if err := bans.Refresh(ctx); err != nil {
+		log.Error("Failed to load ip bans", logger.Error(err))
+	}
+	go bans.Watch(ctx, 30*time.Second)
+
+	// Адрес из заголовков прокси принимается только от доверенных прокси, иначе клиент обойдет бан
+	// и блокировку входа по адресу, подставив X-Forwarded-For. Список уже проверен в Validate.
+	trustedProxies, _ := ipban.ParsePrefixes(cfg.TrustedProxies)
 
 	// Настройка роутера
 	r := chi.NewRouter()
-	r.Use(cors.Handler(cors.Options{
-		AllowedOrigins:   []string{"http://localhost:3000"},
-		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
-		ExposedHeaders:   []string{"Link"},
-		AllowCredentials: true,
-		MaxAge:           300,
-	}))
+	r.Use(myHttp.Recoverer)
+	r.Use(secheaders.Middleware(securityHeaders(cfg)))
+	r.Use(ipban.RealIP(trustedProxies))
+	r.Use(ipban.Middleware(bans, authHandler.IPBanned))
+	r.Use(corsHandler(runtimeCfg))
+
+	// В режиме cookie-аутентификации изменяющие запросы должны содержать CSRF токен
+	if cfg.CookieAuth {
+		r.Use(csrf.ProtectWith(myHttp.AccessTokenCookie, myHttp.CSRFFailed))
+	}
+
+	// Ошибки роутера в том же формате, что и ошибки обработчиков
+	r.NotFound(myHttp.NotFound)
+	r.MethodNotAllowed(myHttp.MethodNotAllowed)
+
+	// Пробы живости и готовности для оркестратора; /health оставлен для существующих проверок
+	r.Get("/healthz", healthHandler.Live)
+	r.Get("/readyz", healthHandler.Ready)
+	r.Get("/health", healthHandler.Ready)
+
+	if avatarFiles != nil {
+		r.Handle("/static/avatars/*", uploads.Handler(avatarFiles, "/static/avatars", ""))
+	}
 
 	// Маршруты аутентификации
 	r.Route("/auth", func(r chi.Router) {
 		r.Post("/register", authHandler.Register)
 		r.Post("/login", authHandler.Login)
+		r.Post("/refresh", authHandler.Refresh)
+		r.Post("/guest", authHandler.GuestToken)
+		r.Get("/oauth/{provider}", authHandler.OAuthLogin)
+		r.Get("/oauth/{provider}/callback", authHandler.OAuthCallback)
+		r.Get("/saml/{idp}", authHandler.SAMLLogin)
+		r.Get("/saml/{idp}/metadata", authHandler.SAMLMetadata)
+		r.Post("/saml/{idp}/acs", authHandler.SAMLACS)
+		r.With(authHandler.AuthMiddleware).Post("/logout", authHandler.Logout)
+		// Интроспекция для внутренних сервисов, аутентифицированных ключом API
+		r.With(authHandler.APIKeyMiddleware(entity.ScopeTokensValidate)).Post("/introspect", authHandler.Introspect)
+		r.Group(func(r chi.Router) {
+			r.Use(authHandler.AuthMiddleware)
+			r.Get("/sessions", authHandler.ListSessions)
+			r.Delete("/sessions", authHandler.RevokeOtherSessions)
+			r.Delete("/sessions/{id}", authHandler.RevokeSession)
+			r.Get("/me", authHandler.GetProfile)
+			r.Put("/me", authHandler.UpdateProfile)
+			r.Get("/me/activity", authHandler.Activity)
+			if avatars != nil {
+				r.Post("/me/avatar", authHandler.UploadAvatar)
+			}
+			if deletion != nil {
+				r.Delete("/me", authHandler.DeleteAccount)
+			}
+			if exports != nil {
+				r.Get("/me/export", authHandler.RequestExport)
+				r.Get("/me/export/{id}", authHandler.ExportStatus)
+				r.Get("/me/export/{id}/download", authHandler.DownloadExport)
+			}
+		})
+		if magicLinks != nil {
+			r.Post("/magic-link", authHandler.RequestMagicLink)
+			r.Get("/magic-link/callback", authHandler.MagicLinkCallback)
+		}
+		// Ссылка из письма о входе с нового устройства; работает без входа в аккаунт
+		if devices != nil {
+			r.Post("/sessions/revoke-link", authHandler.RevokeSessionByLink)
+		}
 	})
 
 	// Защищенные маршруты
@@ -96,43 +428,317 @@ func main() {
 				map[string]string{"message": "Authenticated user: " + userID},
 				http.StatusOK)
 		})
+
+		// Вход администратора под другим пользователем; use case дополнительно сверяет роль с БД
+		r.With(authHandler.RequireRole("admin")).Post("/admin/impersonate", authHandler.Impersonate)
+		// Приостановка и блокировка аккаунтов; use case дополнительно сверяет роль с БД
+		r.With(authHandler.RequireRole("admin")).Put("/admin/users/{id}/status", authHandler.SetUserStatus)
+
+		// Ключи внутренних сервисов
+		r.Route("/admin/api-keys", func(r chi.Router) {
+			r.Use(authHandler.RequireRole("admin"))
+			r.Get("/", authHandler.ListAPIKeys)
+			r.Post("/", authHandler.CreateAPIKey)
+			r.Post("/{id}/rotate", authHandler.RotateAPIKey)
+			r.Delete("/{id}", authHandler.RevokeAPIKey)
+		})
+
+		// Приглашения для регистрации; выпускать их можно и вне режима INVITE_ONLY
+		r.Route("/admin/invites", func(r chi.Router) {
+			r.Use(authHandler.RequireRole("admin"))
+			r.Get("/", authHandler.ListInvites)
+			r.Post("/", authHandler.CreateInvite)
+			r.Delete("/{id}", authHandler.RevokeInvite)
+		})
 	})
 
 	// Настройка сервера
 	server := &http.Server{
-		Addr:         ":8080",
+		Addr:         ":" + cfg.ServerPort,
 		Handler:      r,
 		ReadTimeout:  5 * time.Second,
 		WriteTimeout: 10 * time.Second,
 		IdleTimeout:  15 * time.Second,
 	}
 
-	log.Info("Starting server on :8080")
-	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-		log.Fatal("Server failed", logger.Error(err))
+	// Настройка gRPC сервера для внутренних сервисов
+	grpcServer := grpc.NewServer()
+	authpb.RegisterAuthServiceServer(grpcServer, grpcdelivery.NewAuthServer(authUC, jwtService))
+
+	// Стандартный health-check и reflection для grpcurl, балансировщиков и проб Kubernetes
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	healthServer.SetServingStatus(authpb.AuthService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(grpcServer, healthServer)
+	reflection.Register(grpcServer)
+
+	// Компоненты останавливаются в обратном порядке регистрации:
+	// health-check -> HTTP -> gRPC -> фоновые задачи
+	lm := lifecycle.New(10*time.Second, log)
+	if mailQueue != nil {
+		// Останавливается последним, чтобы успеть отправить письма последних запросов
+		lm.Add("mailer", nil, mailQueue.Close)
+	}
+	if exports != nil {
+		lm.Add("data-exports", func() error {
+			exports.Run()
+			return nil
+		}, exports.Stop)
+	}
+	lm.Add("grpc-server", func() error {
+		return serveGRPC(grpcServer, cfg.GRPCPort, log)
+	}, func(ctx context.Context) error {
+		return stopGRPC(ctx, grpcServer)
+	})
+	lm.Add("http-server", func() error {
+		log.Info("Starting server", logger.String("addr", server.Addr))
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			return err
+		}
+		return nil
+	}, server.Shutdown)
+	lm.Add("grpc-health", nil, func(context.Context) error {
+		// Сообщаем клиентам health-check, что новые запросы принимать не стоит
+		healthServer.Shutdown()
+		return nil
+	})
+
+	if err := lm.Run(ctx); err != nil {
+		log.Error("Service stopped with errors", logger.Error(err))
 	}
 }
 
-func applyMigrations(db *sql.DB) error {
-	driver, err := sqlite3.WithInstance(db, &sqlite3.Config{})
+// serveGRPC запускает gRPC сервер на заданном порту
+func serveGRPC(server *grpc.Server, port string, log *logger.Logger) error {
+	listener, err := net.Listen("tcp", ":"+port)
 	if err != nil {
-		return err
+		return fmt.Errorf("failed to listen gRPC: %w", err)
 	}
 
-	m, err := migrate.NewWithDatabaseInstance(
-		"file://migrations",
-		"sqlite3", driver)
-	if err != nil {
-		return err
+	log.Info("Starting gRPC server", logger.String("port", port))
+	return server.Serve(listener)
+}
+
+// stopGRPC дожидается завершения активных RPC, а по истечении дедлайна обрывает их
+func stopGRPC(ctx context.Context, server *grpc.Server) error {
+	done := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		server.Stop()
+		return ctx.Err()
 	}
+}
 
-	if err := m.Up(); err != nil && err != migrate.ErrNoChange {
-		return err
+// newMailSender создает отправителя писем согласно MAIL_DRIVER
+func newMailSender(cfg *config.Config) (mailer.Sender, error) {
+	switch cfg.MailDriver {
+	case "smtp":
+		return mailer.NewSMTPSender(mailer.SMTPConfig{
+			Host:     cfg.SMTPHost,
+			Port:     cfg.SMTPPort,
+			Username: cfg.SMTPUsername,
+			Password: cfg.SMTPPassword,
+			From:     cfg.MailFrom,
+		})
+	case "ses":
+		return mailer.NewSESSender(mailer.SESConfig{
+			Region:    cfg.SESRegion,
+			AccessKey: cfg.AvatarS3AccessKey,
+			SecretKey: cfg.AvatarS3SecretKey,
+			From:      cfg.MailFrom,
+		})
+	default:
+		return nil, fmt.Errorf("unknown mail driver %q", cfg.MailDriver)
 	}
+}
+
+// logLinkSender пишет ссылки из писем в лог вместо отправки (для разработки)
+type logLinkSender struct {
+	log *logger.Logger
+}
+
+func (s logLinkSender) SendLoginLink(ctx context.Context, email, link string) error {
+	s.log.Info("Login link", logger.String("email", email), logger.String("link", link))
+	return nil
+}
 
+func (s logLinkSender) SendNewDeviceAlert(ctx context.Context, email string, alert *entity.NewDeviceAlert) error {
+	s.log.Info("New device alert",
+		logger.String("email", email),
+		logger.String("ip", alert.IP),
+		logger.String("user_agent", alert.UserAgent),
+		logger.String("revoke_link", alert.RevokeLink))
 	return nil
 }
 
+// expiringStore хранилище токенов, из которого можно удалить истекшие записи
+type expiringStore interface {
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
+
+// purgeExpiredTokens периодически удаляет истекшие токены из хранилищ, пока не отменен ctx
+func purgeExpiredTokens(ctx context.Context, interval time.Duration, log *logger.Logger, stores ...expiringStore) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			for _, store := range stores {
+				deleted, err := store.DeleteExpired(ctx, now)
+				if err == nil && deleted > 0 {
+					log.Info("Deleted expired tokens", logger.Int64("count", deleted))
+				}
+			}
+		}
+	}
+}
+
+// newSAMLProviders создает SP для каждого IdP из конфигурации; метаданные IdP загружаются при запуске
+func newSAMLProviders(ctx context.Context, cfg *config.Config) (*auth.SAMLProviders, error) {
+	providers := auth.NewSAMLProviders()
+	if cfg.SAMLBaseURL == "" {
+		return providers, nil
+	}
+
+	key, cert, err := auth.LoadSAMLKeyPair(cfg.SAMLCertFile, cfg.SAMLKeyFile)
+	if err != nil {
+		return nil, err
+	}
+	for _, idp := range cfg.SAMLProviders {
+		metadata, err := auth.LoadSAMLMetadata(ctx, idp.Metadata)
+		if err != nil {
+			return nil, fmt.Errorf("idp %s: %w", idp.Name, err)
+		}
+		provider, err := auth.NewSAMLProvider(auth.SAMLConfig{
+			Name:           idp.Name,
+			BaseURL:        cfg.SAMLBaseURL,
+			Key:            key,
+			Certificate:    cert,
+			IDPMetadata:    metadata,
+			EmailAttribute: idp.EmailAttribute,
+			NameAttribute:  idp.NameAttribute,
+			IDAttribute:    idp.IDAttribute,
+			TrustEmail:     idp.TrustEmail,
+			EmailDomains:   idp.EmailDomains,
+		})
+		if err != nil {
+			return nil, err
+		}
+		providers.Register(provider)
+	}
+	return providers, nil
+}
+
+// newRedisClient подключается к Redis по адресу вида redis://[:password@]host:port/db
+func newRedisClient(ctx context.Context, redisURL string) (*redis.Client, error) {
+	opts, err := redis.ParseURL(redisURL)
+	if err != nil {
+		return nil, fmt.Errorf("parse REDIS_URL: %w", err)
+	}
+
+	rdb := redis.NewClient(opts)
+	if err := rdb.Ping(ctx).Err(); err != nil {
+		rdb.Close()
+		return nil, fmt.Errorf("ping redis: %w", err)
+	}
+	return rdb, nil
+}
+
+// newUserRepository создает хранилище пользователей согласно DB_DRIVER. Для postgres открывает
+// отдельное соединение и применяет его миграции; close закрывает это соединение.
+func newUserRepository(cfg *config.Config, db *sql.DB, log *logger.Logger) (repository.UserRepository, func(), error) {
+	if cfg.DBDriver != "postgres" {
+		return repository.NewSQLiteUserRepository(db, log), func() {}, nil
+	}
+
+	pg, err := sql.Open("postgres", cfg.DBDSN)
+	if err != nil {
+		return nil, nil, fmt.Errorf("open postgres: %w", err)
+	}
+	closePG := func() {
+		if err := pg.Close(); err != nil {
+			log.Error("Failed to close postgres", logger.Error(err))
+		}
+	}
+
+	if err := pg.Ping(); err != nil {
+		closePG()
+		return nil, nil, fmt.Errorf("connect to postgres: %w", err)
+	}
+
+	migrator, err := migrations.NewPostgresMigrator(pg)
+	if err == nil {
+		err = migrator.Up()
+	}
+	if err != nil {
+		closePG()
+		return nil, nil, fmt.Errorf("apply postgres migrations: %w", err)
+	}
+
+	// В PostgreSQL только пользователи: остальные хранилища локальны для процесса,
+	// поэтому отозванная на одной реплике сессия или ключ остается действительной на другой
+	log.Warn("Using PostgreSQL user store; sessions, API keys and auth logs stay in local SQLite, multiple replicas are not supported",
+		logger.String("db_path", cfg.DBPath),
+		logger.Bool("redis_token_store", cfg.RedisURL != ""))
+	return repository.NewPostgresUserRepository(pg, db, log), closePG, nil
+}
+
+// corsHandler разрешает origin из runtime настроек на каждый запрос. Запросы с cookie
+// разрешены только явно указанным origin; при "*" остальным отвечает "*" без credentials.
+func corsHandler(runtimeCfg *config.RuntimeWatcher) func(http.Handler) http.Handler {
+	options := cors.Options{
+		AllowedMethods: []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
+		AllowedHeaders: []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
+		ExposedHeaders: []string{"Link"},
+		MaxAge:         300,
+	}
+	listed := options
+	listed.AllowOriginFunc = func(r *http.Request, origin string) bool {
+		settings := runtimeCfg.Current()
+		return settings.ListsOrigin(origin)
+	}
+	listed.AllowCredentials = true
+	wildcard := options
+	wildcard.AllowedOrigins = []string{"*"}
+
+	return func(next http.Handler) http.Handler {
+		withCredentials := cors.Handler(listed)(next)
+		withoutCredentials := cors.Handler(wildcard)(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			settings := runtimeCfg.Current()
+			origin := r.Header.Get("Origin")
+			if !settings.ListsOrigin(origin) && settings.AllowsOrigin(origin) {
+				withoutCredentials.ServeHTTP(w, r)
+				return
+			}
+			withCredentials.ServeHTTP(w, r)
+		})
+	}
+}
+
+func applyMigrations(db *sql.DB) (*migrations.Migrator, error) {
+	migrator, err := migrations.NewMigrator(db)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := migrator.Up(); err != nil {
+		return nil, err
+	}
+
+	return migrator, nil
+}
+
 // package main
 
 // import (
@@ -253,3 +859,12 @@ func applyMigrations(db *sql.DB) error {
 // 	log.Println("Migrations applied successfully")
 // 	return nil
 // }
+
+// securityHeaders заголовки безопасности для окружения с учетом переопределений
+func securityHeaders(cfg *config.Config) secheaders.Config {
+	headers := secheaders.ForEnv(cfg.Env)
+	if cfg.ContentSecurityPolicy != "" {
+		headers.ContentSecurityPolicy = cfg.ContentSecurityPolicy
+	}
+	return headers
+}
@@ -3,17 +3,43 @@ module github.com/kprf42/dolgova/auth_service
 go 1.24.2
 
 require (
+	github.com/crewjam/saml v0.4.14
+	github.com/go-playground/validator/v10 v10.26.0
 	github.com/golang-jwt/jwt/v5 v5.2.2
 	github.com/google/uuid v1.6.0
+	github.com/kprf42/dolgova/pkg/audit v0.0.0-00010101000000-000000000000
+	github.com/kprf42/dolgova/pkg/csrf v0.0.0-00010101000000-000000000000
+	github.com/kprf42/dolgova/pkg/grpcerr v0.0.0-00010101000000-000000000000
+	github.com/kprf42/dolgova/pkg/i18n v0.0.0-00010101000000-000000000000
+	github.com/kprf42/dolgova/pkg/ipban v0.0.0-00010101000000-000000000000
+	github.com/kprf42/dolgova/pkg/lifecycle v0.0.0-00010101000000-000000000000
 	github.com/kprf42/dolgova/pkg/logger v0.0.0-00010101000000-000000000000
+	github.com/kprf42/dolgova/pkg/mailer v0.0.0-00010101000000-000000000000
+	github.com/kprf42/dolgova/pkg/runtimecfg v0.0.0-00010101000000-000000000000
+	github.com/kprf42/dolgova/pkg/secheaders v0.0.0-00010101000000-000000000000
+	github.com/kprf42/dolgova/pkg/uploads v0.0.0-00010101000000-000000000000
 	github.com/kprf42/dolgova/proto v0.0.0-00010101000000-000000000000
+	github.com/lib/pq v1.10.9
+	github.com/redis/go-redis/v9 v9.7.3
 	golang.org/x/crypto v0.37.0
 	google.golang.org/grpc v1.72.1
+	google.golang.org/protobuf v1.36.6
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
+	github.com/beevik/etree v1.1.0 // indirect
+	github.com/cespare/xxhash/v2 v2.3.0 // indirect
+	github.com/dgryski/go-rendezvous v0.0.0-20200823014737-9f7001d12a5f // indirect
+	github.com/gabriel-vasile/mimetype v1.4.8 // indirect
+	github.com/go-playground/locales v0.14.1 // indirect
+	github.com/go-playground/universal-translator v0.18.1 // indirect
 	github.com/hashicorp/errwrap v1.1.0 // indirect
 	github.com/hashicorp/go-multierror v1.1.1 // indirect
+	github.com/jonboulle/clockwork v0.2.2 // indirect
+	github.com/leodido/go-urn v1.4.0 // indirect
+	github.com/mattermost/xml-roundtrip-validator v0.1.0 // indirect
+	github.com/russellhaering/goxmldsig v1.3.0 // indirect
 	go.uber.org/atomic v1.7.0 // indirect
 	go.uber.org/multierr v1.10.0 // indirect
 	go.uber.org/zap v1.27.0 // indirect
@@ -28,9 +54,28 @@ require (
 	golang.org/x/sys v0.32.0 // indirect
 	golang.org/x/text v0.24.0 // indirect
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20250218202821-56aae31c358a // indirect
-	google.golang.org/protobuf v1.36.6 // indirect
 )
 
 replace github.com/kprf42/dolgova/proto => ../proto
 
 replace github.com/kprf42/dolgova/pkg/logger => ../pkg/logger
+
+replace github.com/kprf42/dolgova/pkg/lifecycle => ../pkg/lifecycle
+
+replace github.com/kprf42/dolgova/pkg/csrf => ../pkg/csrf
+
+replace github.com/kprf42/dolgova/pkg/i18n => ../pkg/i18n
+
+replace github.com/kprf42/dolgova/pkg/secheaders => ../pkg/secheaders
+
+replace github.com/kprf42/dolgova/pkg/ipban => ../pkg/ipban
+
+replace github.com/kprf42/dolgova/pkg/audit => ../pkg/audit
+
+replace github.com/kprf42/dolgova/pkg/grpcerr => ../pkg/grpcerr
+
+replace github.com/kprf42/dolgova/pkg/uploads => ../pkg/uploads
+
+replace github.com/kprf42/dolgova/pkg/mailer => ../pkg/mailer
+
+replace github.com/kprf42/dolgova/pkg/runtimecfg => ../pkg/runtimecfg
@@ -2,79 +2,657 @@
 package config
 
 import (
+	"bytes"
+	"cmp"
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
+	"fmt"
+	"net/mail"
+	"net/url"
 	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
 	"time"
+
+	"github.com/kprf42/dolgova/pkg/ipban"
+	"gopkg.in/yaml.v3"
 )
 
 // Config содержит все параметры конфигурации приложения
 type Config struct {
-	JWTSecret     string        `json:"jwt_secret"`     // Секретный ключ для JWT
-	AccessExpiry  time.Duration `json:"access_expiry"`  // Время жизни access токена
-	RefreshExpiry time.Duration `json:"refresh_expiry"` // Время жизни refresh токена
-	DBPath        string        `json:"db_path"`        // Путь к файлу базы данных SQLite
-	ServerPort    string        `json:"server_port"`    // Порт HTTP сервера
-	Env           string        `json:"env"`            // Окружение (development/production)
+	JWTSecret     string        `json:"jwt_secret" yaml:"jwt_secret"`         // Секретный ключ для JWT
+	JWTKeys       []JWTKey      `json:"jwt_keys" yaml:"jwt_keys"`             // Дополнительные ключи подписи по расписанию ротации
+	AccessExpiry  time.Duration `json:"access_expiry" yaml:"access_expiry"`   // Время жизни access токена
+	RefreshExpiry time.Duration `json:"refresh_expiry" yaml:"refresh_expiry"` // Время жизни refresh токена
+	DBPath        string        `json:"db_path" yaml:"db_path"`               // Путь к файлу базы данных SQLite
+	DBDriver      string        `json:"db_driver" yaml:"db_driver"`           // Хранилище users и linked_accounts: sqlite3 (в DB_PATH) или postgres; остальные данные всегда в DB_PATH
+	DBDSN         string        `json:"-" yaml:"db_dsn"`                      // Строка подключения к PostgreSQL (DB_DSN), содержит пароль
+	RedisURL      string        `json:"-" yaml:"redis_url"`                   // Redis для refresh токенов, отозванных jti и счетчиков входов (REDIS_URL); пусто - в SQLite
+	ServerPort    string        `json:"server_port" yaml:"server_port"`       // Порт HTTP сервера
+	GRPCPort      string        `json:"grpc_port" yaml:"grpc_port"`           // Порт gRPC сервера
+	Env           string        `json:"env" yaml:"env"`                       // Окружение (development/production)
+
+	JWTSecretGenerated bool `json:"-" yaml:"-"` // JWT_SECRET не задан, секрет сгенерирован при запуске (только development)
+
+	RuntimeConfigPath string `json:"runtime_config_path" yaml:"runtime_config_path"` // Файл настроек, перечитываемых без перезапуска
+
+	LoginMaxFailures   int           `json:"login_max_failures" yaml:"login_max_failures"`       // Неудачных входов подряд до блокировки пользователя; 0 - без блокировки
+	LoginMaxIPFailures int           `json:"login_max_ip_failures" yaml:"login_max_ip_failures"` // То же для адреса; 0 - не считать по адресу
+	LoginLockout       time.Duration `json:"login_lockout" yaml:"login_lockout"`                 // Длительность блокировки входа
+
+	TrustedProxies string `json:"trusted_proxies" yaml:"trusted_proxies"` // Адреса и подсети прокси через запятую, которым доверяются X-Forwarded-For и X-Real-IP; пусто - никому
+
+	PasswordMinLength  int    `json:"password_min_length" yaml:"password_min_length"`   // Минимальная длина пароля в символах
+	PasswordMaxLength  int    `json:"password_max_length" yaml:"password_max_length"`   // Максимальная длина пароля; bcrypt учитывает не больше 72 байт
+	PasswordMinClasses int    `json:"password_min_classes" yaml:"password_min_classes"` // Классов символов (строчные, заглавные, цифры, прочие) в пароле; 0 - не проверять
+	PasswordBanCommon  bool   `json:"password_ban_common" yaml:"password_ban_common"`   // Запрещать распространенные пароли
+	PasswordBannedFile string `json:"password_banned_file" yaml:"password_banned_file"` // Дополнительный список запрещенных паролей, по одному в строке
+
+	PasswordPeppers  []PasswordPepper `json:"-" yaml:"password_peppers"`                    // Перцы паролей: текущий и прежние, пока остаются хеши с ними (PASSWORD_PEPPERS)
+	PasswordPepperID string           `json:"password_pepper_id" yaml:"password_pepper_id"` // Перец для новых хешей; пусто - без перца
+
+	CookieAuth   bool `json:"cookie_auth" yaml:"cookie_auth"`     // Выдавать токен в HttpOnly cookie (включает CSRF защиту)
+	CookieSecure bool `json:"cookie_secure" yaml:"cookie_secure"` // Cookie только по HTTPS
+
+	ContentSecurityPolicy string `json:"content_security_policy" yaml:"content_security_policy"` // Переопределяет CSP окружения, если задан
+
+	GoogleClientID     string `json:"google_client_id" yaml:"google_client_id"`         // Вход через Google включен, если задан
+	GoogleClientSecret string `json:"google_client_secret" yaml:"google_client_secret"` // Секрет OAuth клиента Google
+	GoogleRedirectURL  string `json:"google_redirect_url" yaml:"google_redirect_url"`   // Адрес /auth/oauth/google/callback, зарегистрированный в Google
+
+	GitHubClientID     string `json:"github_client_id" yaml:"github_client_id"`         // Вход через GitHub включен, если задан
+	GitHubClientSecret string `json:"github_client_secret" yaml:"github_client_secret"` // Секрет OAuth приложения GitHub
+	GitHubRedirectURL  string `json:"github_redirect_url" yaml:"github_redirect_url"`   // Адрес /auth/oauth/github/callback, зарегистрированный в GitHub
+
+	// Вход через SAML SSO включен, если задан SAMLBaseURL; каждый IdP получает адреса /auth/saml/{name}/...
+	SAMLBaseURL   string         `json:"saml_base_url" yaml:"saml_base_url"`   // Внешний адрес auth сервиса, из него строятся entityID и адрес ACS
+	SAMLCertFile  string         `json:"saml_cert_file" yaml:"saml_cert_file"` // Сертификат SP (PEM), публикуется в метаданных
+	SAMLKeyFile   string         `json:"saml_key_file" yaml:"saml_key_file"`   // RSA ключ SP (PEM): подпись запросов и расшифровка утверждений
+	SAMLProviders []SAMLProvider `json:"saml_providers" yaml:"saml_providers"` // IdP; задаются только в файле конфигурации
+
+	InviteOnly bool `json:"invite_only" yaml:"invite_only"` // Регистрация только по приглашениям администратора
+
+	MagicLinkURL string        `json:"magic_link_url" yaml:"magic_link_url"` // Страница входа по ссылке из письма; вход по ссылке включен, если задан
+	MagicLinkTTL time.Duration `json:"magic_link_ttl" yaml:"magic_link_ttl"` // Время жизни ссылки
+
+	SessionRevokeURL string `json:"session_revoke_url" yaml:"session_revoke_url"` // Страница завершения сессии из письма о входе с нового устройства; письма включены, если задан
+
+	MailDriver   string `json:"mail_driver" yaml:"mail_driver"`     // Отправка писем: smtp или ses; пусто - письма пишутся в лог (только вне production)
+	MailFrom     string `json:"mail_from" yaml:"mail_from"`         // Отправитель писем: "Форум <noreply@example.com>"
+	SMTPHost     string `json:"smtp_host" yaml:"smtp_host"`         // SMTP сервер
+	SMTPPort     int    `json:"smtp_port" yaml:"smtp_port"`         // 587 (STARTTLS) или 465 (TLS)
+	SMTPUsername string `json:"smtp_username" yaml:"smtp_username"` // Пусто - без авторизации
+	SMTPPassword string `json:"-" yaml:"smtp_password"`             // SMTP_PASSWORD
+	SESRegion    string `json:"ses_region" yaml:"ses_region"`       // Регион AWS SES; ключи доступа те же, что у бакета аватаров
+
+	// Аватары хранятся в S3, если задан бакет, иначе в каталоге AvatarDir.
+	// Загрузка аватаров включена, если известен публичный адрес файлов.
+	AvatarDir         string `json:"avatar_dir" yaml:"avatar_dir"`                 // Каталог аватаров на диске; файлы отдаются по /static/avatars
+	AvatarPublicURL   string `json:"avatar_public_url" yaml:"avatar_public_url"`   // Адрес, по которому клиенты читают аватары
+	AvatarS3Bucket    string `json:"avatar_s3_bucket" yaml:"avatar_s3_bucket"`     // Бакет S3 для аватаров
+	AvatarS3Region    string `json:"avatar_s3_region" yaml:"avatar_s3_region"`     // Регион бакета
+	AvatarS3Endpoint  string `json:"avatar_s3_endpoint" yaml:"avatar_s3_endpoint"` // API S3-совместимого хранилища; по умолчанию AWS
+	AvatarS3AccessKey string `json:"-" yaml:"aws_access_key_id"`                   // AWS_ACCESS_KEY_ID
+	AvatarS3SecretKey string `json:"-" yaml:"aws_secret_access_key"`               // AWS_SECRET_ACCESS_KEY
+
+	ForumGRPCAddr string `json:"forum_grpc_addr" yaml:"forum_grpc_addr"` // gRPC сервер форума; без него удаление аккаунта и выгрузка данных выключены
+
+	ExportDir string        `json:"export_dir" yaml:"export_dir"` // Каталог архивов выгрузки персональных данных
+	ExportTTL time.Duration `json:"export_ttl" yaml:"export_ttl"` // Сколько готовый архив доступен для скачивания
+}
+
+// SAMLProvider IdP для входа через SAML SSO и соответствие его атрибутов полям пользователя
+type SAMLProvider struct {
+	Name           string `json:"name" yaml:"name"`                       // Имя в маршрутах и в linked_accounts (saml:<name>)
+	Metadata       string `json:"metadata" yaml:"metadata"`               // Метаданные IdP: путь к файлу или http(s) адрес
+	EmailAttribute string `json:"email_attribute" yaml:"email_attribute"` // Атрибут с email; пусто - email, mail и их OID
+	NameAttribute  string `json:"name_attribute" yaml:"name_attribute"`   // Атрибут с именем; пусто - displayName
+	IDAttribute    string `json:"id_attribute" yaml:"id_attribute"`       // Атрибут с постоянным ID; пусто - NameID
+
+	// Email из утверждения считается подтвержденным (вход в существующий аккаунт с тем же email,
+	// регистрация) только если IdP отвечает за адрес: trust_email или домен из email_domains
+	TrustEmail   bool     `json:"trust_email" yaml:"trust_email"`     // Подтверждать email в любом домене
+	EmailDomains []string `json:"email_domains" yaml:"email_domains"` // Домены, email в которых подтверждается
+}
+
+// PasswordPepper секрет, подмешиваемый к паролю перед хешированием; ID хранится вместе с хешем
+type PasswordPepper struct {
+	ID     string `json:"id" yaml:"id"`
+	Secret string `json:"secret" yaml:"secret"`
+}
+
+// JWTKey ключ подписи JWT из расписания ротации
+type JWTKey struct {
+	ID       string    `json:"id" yaml:"id"`               // kid в заголовке токена
+	Secret   string    `json:"secret" yaml:"secret"`       // Секрет HMAC
+	ActiveAt time.Time `json:"active_at" yaml:"active_at"` // С этого момента ключ подписывает новые токены
 }
 
 const (
-	defaultJWTSecret     = "your-strong-secret-key"
 	defaultAccessExpiry  = time.Hour * 1      // 1 час
 	defaultRefreshExpiry = time.Hour * 24 * 7 // 1 неделя
 	defaultDBPath        = "auth.db"
+	defaultDBDriver      = "sqlite3"
 	defaultServerPort    = "8080"
+	defaultGRPCPort      = "50052"
+	defaultRuntimeConfig = "runtime.json"
+
+	defaultLoginMaxFailures   = 5
+	defaultLoginMaxIPFailures = 20
+	defaultLoginLockout       = 15 * time.Minute
+
+	defaultMagicLinkTTL = 15 * time.Minute
+
+	defaultSMTPPort = 587
+
+	defaultAvatarDir = "avatars"
+
+	defaultForumGRPCAddr = "localhost:50051"
+
+	defaultExportDir = "exports"
+	defaultExportTTL = 24 * time.Hour
+
+	defaultPasswordMinLength = 8
+	defaultPasswordMaxLength = 72
+
+	// minProductionSecretLength минимальная длина секрета подписи JWT в production
+	minProductionSecretLength = 32
 )
 
-// New создает конфигурацию в зависимости от окружения
-func New() (*Config, error) {
-	env := getEnv("APP_ENV", "development")
+// samlNamePattern допустимые имена IdP: имя входит в адреса SP
+var samlNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
 
-	switch env {
-	case "production":
-		return newProductionConfig()
-	case "development":
-		return newDevelopmentConfig()
-	default:
-		return nil, errors.New("unknown environment")
+// New загружает конфигурацию. Значения по умолчанию для окружения APP_ENV перекрываются
+// файлом path (YAML или JSON; если path пуст - файлом из CONFIG_FILE, а без него файла нет),
+// а файл - переменными окружения. Окружение можно задать и в файле полем env, но APP_ENV важнее.
+func New(path string) (*Config, error) {
+	if path == "" {
+		path = os.Getenv("CONFIG_FILE")
+	}
+
+	var data []byte
+	var file map[string]any // Ключи файла верхнего уровня
+	if path != "" {
+		var err error
+		if data, err = os.ReadFile(path); err != nil {
+			return nil, fmt.Errorf("config file: %w", err)
+		}
+		if err := yaml.Unmarshal(data, &file); err != nil {
+			return nil, fmt.Errorf("config file %s: %w", path, err)
+		}
+	}
+
+	env, _ := file["env"].(string)
+	env = getEnv("APP_ENV", cmp.Or(env, "development"))
+	cfg, err := Defaults(env)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(file) > 0 {
+		// Неизвестные ключи - скорее всего опечатки, поэтому они считаются ошибкой
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(cfg); err != nil {
+			return nil, fmt.Errorf("config file %s: %w", path, err)
+		}
 	}
+	cfg.Env = env
+
+	// Ошибки разбора переменных попадают в общий отчет вместе с ошибками Validate
+	envErr := cfg.applyEnv()
+
+	if env == "development" {
+		// Адрес аватаров по умолчанию зависит от итогового порта
+		if _, ok := file["avatar_public_url"]; !ok && os.Getenv("AVATAR_PUBLIC_URL") == "" {
+			cfg.AvatarPublicURL = "http://localhost:" + cfg.ServerPort + "/static/avatars"
+		}
+		// Без секрета токены подписываются случайным ключом и перестают действовать после перезапуска
+		if cfg.JWTSecret == "" {
+			if cfg.JWTSecret, err = randomSecret(); err != nil {
+				return nil, err
+			}
+			cfg.JWTSecretGenerated = true
+		}
+	}
+
+	if err := errors.Join(envErr, cfg.Validate()); err != nil {
+		return nil, err
+	}
+	return cfg, nil
 }
 
-// newDevelopmentConfig создает конфигурацию для разработки
-func newDevelopmentConfig() (*Config, error) {
-	return &Config{
-		JWTSecret:     defaultJWTSecret,
+// Defaults возвращает значения по умолчанию для окружения env (development или production).
+// В production не задан секрет JWT, а cookie по умолчанию только для HTTPS.
+func Defaults(env string) (*Config, error) {
+	cfg := &Config{
 		AccessExpiry:  defaultAccessExpiry,
 		RefreshExpiry: defaultRefreshExpiry,
 		DBPath:        defaultDBPath,
+		DBDriver:      defaultDBDriver,
 		ServerPort:    defaultServerPort,
-		Env:           "development",
-	}, nil
+		GRPCPort:      defaultGRPCPort,
+		Env:           env,
+
+		RuntimeConfigPath: defaultRuntimeConfig,
+
+		LoginMaxFailures:   defaultLoginMaxFailures,
+		LoginMaxIPFailures: defaultLoginMaxIPFailures,
+		LoginLockout:       defaultLoginLockout,
+
+		PasswordMinLength: defaultPasswordMinLength,
+		PasswordMaxLength: defaultPasswordMaxLength,
+		PasswordBanCommon: true,
+
+		MagicLinkTTL: defaultMagicLinkTTL,
+
+		SMTPPort: defaultSMTPPort,
+
+		AvatarDir: defaultAvatarDir,
+
+		ExportDir: defaultExportDir,
+		ExportTTL: defaultExportTTL,
+	}
+
+	switch env {
+	case "production":
+		cfg.CookieSecure = true
+	case "development":
+		cfg.ForumGRPCAddr = defaultForumGRPCAddr
+	default:
+		return nil, fmt.Errorf("unknown environment %q", env)
+	}
+	return cfg, nil
+}
+
+// Validate проверяет конфигурацию целиком и возвращает все найденные ошибки сразу,
+// чтобы сервис не запускался с настройками, которые сломаются на первом запросе
+func (c *Config) Validate() error {
+	var errs []error
+
+	if c.JWTSecret == "" {
+		errs = append(errs, errors.New("JWT_SECRET is required"))
+	} else if c.Env == "production" && len(c.JWTSecret) < minProductionSecretLength {
+		errs = append(errs, fmt.Errorf("JWT_SECRET must be at least %d bytes in production", minProductionSecretLength))
+	}
+
+	seenKeys := map[string]bool{"default": true} // kid ключа из JWT_SECRET
+	for _, key := range c.JWTKeys {
+		switch {
+		case seenKeys[key.ID]:
+			errs = append(errs, fmt.Errorf("JWT_KEYS: duplicate key id %q", key.ID))
+		case c.Env == "production" && len(key.Secret) < minProductionSecretLength:
+			errs = append(errs, fmt.Errorf("JWT_KEYS: secret of key %q must be at least %d bytes in production", key.ID, minProductionSecretLength))
+		}
+		seenKeys[key.ID] = true
+	}
+
+	if port, err := strconv.Atoi(c.ServerPort); err != nil || port < 1 || port > 65535 {
+		errs = append(errs, fmt.Errorf("SERVER_PORT %q: expected a port number 1-65535", c.ServerPort))
+	}
+	if port, err := strconv.Atoi(c.GRPCPort); err != nil || port < 1 || port > 65535 {
+		errs = append(errs, fmt.Errorf("GRPC_PORT %q: expected a port number 1-65535", c.GRPCPort))
+	} else if c.GRPCPort == c.ServerPort {
+		errs = append(errs, fmt.Errorf("GRPC_PORT %q: must differ from SERVER_PORT", c.GRPCPort))
+	}
+
+	if info, err := os.Stat(filepath.Dir(c.DBPath)); err != nil {
+		errs = append(errs, fmt.Errorf("DB_PATH directory: %w", err))
+	} else if !info.IsDir() {
+		errs = append(errs, fmt.Errorf("DB_PATH directory %q is not a directory", filepath.Dir(c.DBPath)))
+	}
+
+	switch c.DBDriver {
+	case "sqlite3":
+	case "postgres":
+		if c.DBDSN == "" {
+			errs = append(errs, errors.New("DB_DSN is required with DB_DRIVER=postgres"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("DB_DRIVER %q: expected sqlite3 or postgres", c.DBDriver))
+	}
+
+	if c.AccessExpiry <= 0 {
+		errs = append(errs, fmt.Errorf("ACCESS_EXPIRY %s: must be positive", c.AccessExpiry))
+	}
+	if c.RefreshExpiry <= c.AccessExpiry {
+		errs = append(errs, fmt.Errorf("REFRESH_EXPIRY %s: must be longer than ACCESS_EXPIRY %s", c.RefreshExpiry, c.AccessExpiry))
+	}
+
+	if c.LoginMaxFailures < 0 || c.LoginMaxIPFailures < 0 {
+		errs = append(errs, errors.New("LOGIN_MAX_FAILURES and LOGIN_MAX_IP_FAILURES must not be negative"))
+	}
+	if c.LoginMaxFailures > 0 && c.LoginLockout <= 0 {
+		errs = append(errs, fmt.Errorf("LOGIN_LOCKOUT %s: must be positive", c.LoginLockout))
+	}
+	if _, err := ipban.ParsePrefixes(c.TrustedProxies); err != nil {
+		errs = append(errs, fmt.Errorf("TRUSTED_PROXIES: %w", err))
+	}
+
+	if c.PasswordMinLength < 1 {
+		errs = append(errs, fmt.Errorf("PASSWORD_MIN_LENGTH %d: must be positive", c.PasswordMinLength))
+	}
+	if c.PasswordMaxLength < c.PasswordMinLength || c.PasswordMaxLength > defaultPasswordMaxLength {
+		errs = append(errs, fmt.Errorf("PASSWORD_MAX_LENGTH %d: must be between PASSWORD_MIN_LENGTH and %d", c.PasswordMaxLength, defaultPasswordMaxLength))
+	}
+	if c.PasswordMinClasses < 0 || c.PasswordMinClasses > 4 {
+		errs = append(errs, fmt.Errorf("PASSWORD_MIN_CLASSES %d: must be between 0 and 4", c.PasswordMinClasses))
+	}
+
+	seenPeppers := map[string]bool{}
+	for _, pepper := range c.PasswordPeppers {
+		switch {
+		case pepper.ID == "" || strings.Contains(pepper.ID, "$"):
+			errs = append(errs, fmt.Errorf("PASSWORD_PEPPERS: invalid pepper id %q", pepper.ID))
+		case seenPeppers[pepper.ID]:
+			errs = append(errs, fmt.Errorf("PASSWORD_PEPPERS: duplicate pepper id %q", pepper.ID))
+		case c.Env == "production" && len(pepper.Secret) < minProductionSecretLength:
+			errs = append(errs, fmt.Errorf("PASSWORD_PEPPERS: secret of pepper %q must be at least %d bytes in production", pepper.ID, minProductionSecretLength))
+		}
+		seenPeppers[pepper.ID] = true
+	}
+	if c.PasswordPepperID != "" && !seenPeppers[c.PasswordPepperID] {
+		errs = append(errs, fmt.Errorf("PASSWORD_PEPPER_ID %q: no such pepper in PASSWORD_PEPPERS", c.PasswordPepperID))
+	}
+
+	if c.CookieAuth && c.Env == "production" && !c.CookieSecure {
+		errs = append(errs, errors.New("COOKIE_SECURE must be enabled with COOKIE_AUTH in production"))
+	}
+
+	if c.GoogleClientID != "" && (c.GoogleClientSecret == "" || c.GoogleRedirectURL == "") {
+		errs = append(errs, errors.New("GOOGLE_CLIENT_SECRET and GOOGLE_REDIRECT_URL are required with GOOGLE_CLIENT_ID"))
+	}
+	if c.GitHubClientID != "" && (c.GitHubClientSecret == "" || c.GitHubRedirectURL == "") {
+		errs = append(errs, errors.New("GITHUB_CLIENT_SECRET and GITHUB_REDIRECT_URL are required with GITHUB_CLIENT_ID"))
+	}
+
+	if c.SAMLBaseURL != "" {
+		if u, err := url.Parse(c.SAMLBaseURL); err != nil || !u.IsAbs() {
+			errs = append(errs, fmt.Errorf("SAML_BASE_URL %q: must be an absolute URL", c.SAMLBaseURL))
+		}
+		if c.SAMLCertFile == "" || c.SAMLKeyFile == "" {
+			errs = append(errs, errors.New("SAML_CERT_FILE and SAML_KEY_FILE are required with SAML_BASE_URL"))
+		}
+		if len(c.SAMLProviders) == 0 {
+			errs = append(errs, errors.New("saml_providers: at least one IdP is required with SAML_BASE_URL"))
+		}
+	}
+	seenIdPs := map[string]bool{}
+	for i, idp := range c.SAMLProviders {
+		switch {
+		case !samlNamePattern.MatchString(idp.Name):
+			errs = append(errs, fmt.Errorf("saml_providers[%d]: name %q must match %s", i, idp.Name, samlNamePattern))
+		case seenIdPs[idp.Name]:
+			errs = append(errs, fmt.Errorf("saml_providers[%d]: duplicate name %q", i, idp.Name))
+		}
+		if idp.Metadata == "" {
+			errs = append(errs, fmt.Errorf("saml_providers[%d]: metadata is required", i))
+		}
+		for _, domain := range idp.EmailDomains {
+			if domain == "" || strings.ContainsAny(domain, "@ ") {
+				errs = append(errs, fmt.Errorf("saml_providers[%d]: email domain %q: expected a domain like example.com", i, domain))
+			}
+		}
+		seenIdPs[idp.Name] = true
+	}
+
+	switch c.MailDriver {
+	case "":
+	case "smtp":
+		if c.SMTPHost == "" {
+			errs = append(errs, errors.New("SMTP_HOST is required with MAIL_DRIVER=smtp"))
+		}
+		if c.SMTPPort < 1 || c.SMTPPort > 65535 {
+			errs = append(errs, fmt.Errorf("SMTP_PORT %d: expected a port number 1-65535", c.SMTPPort))
+		}
+	case "ses":
+		if c.SESRegion == "" {
+			errs = append(errs, errors.New("SES_REGION is required with MAIL_DRIVER=ses"))
+		}
+		if c.AvatarS3AccessKey == "" || c.AvatarS3SecretKey == "" {
+			errs = append(errs, errors.New("AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY are required with MAIL_DRIVER=ses"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("MAIL_DRIVER %q: expected smtp or ses", c.MailDriver))
+	}
+	if c.MailDriver != "" {
+		if _, err := mail.ParseAddress(c.MailFrom); err != nil {
+			errs = append(errs, fmt.Errorf("MAIL_FROM %q: %w", c.MailFrom, err))
+		}
+	}
+
+	if c.MagicLinkURL != "" {
+		if u, err := url.Parse(c.MagicLinkURL); err != nil || !u.IsAbs() {
+			errs = append(errs, fmt.Errorf("MAGIC_LINK_URL %q: must be an absolute URL", c.MagicLinkURL))
+		}
+		if c.MagicLinkTTL <= 0 {
+			errs = append(errs, fmt.Errorf("MAGIC_LINK_TTL %s: must be positive", c.MagicLinkTTL))
+		}
+	}
+
+	if c.SessionRevokeURL != "" {
+		if u, err := url.Parse(c.SessionRevokeURL); err != nil || !u.IsAbs() {
+			errs = append(errs, fmt.Errorf("SESSION_REVOKE_URL %q: must be an absolute URL", c.SessionRevokeURL))
+		}
+	}
+
+	// Адрес аватара сохраняется в профиль и должен проходить ту же проверку, что и ссылка из PUT /auth/me
+	if c.AvatarPublicURL != "" {
+		if u, err := url.Parse(c.AvatarPublicURL); err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+			errs = append(errs, fmt.Errorf("AVATAR_PUBLIC_URL %q: must be an absolute http(s) URL", c.AvatarPublicURL))
+		}
+	}
+	if c.AvatarS3Bucket != "" {
+		if c.AvatarS3Region == "" {
+			errs = append(errs, errors.New("AVATAR_S3_REGION is required with AVATAR_S3_BUCKET"))
+		}
+		if c.AvatarS3AccessKey == "" || c.AvatarS3SecretKey == "" {
+			errs = append(errs, errors.New("AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY are required with AVATAR_S3_BUCKET"))
+		}
+		if c.AvatarS3Endpoint != "" && c.AvatarPublicURL == "" {
+			errs = append(errs, errors.New("AVATAR_PUBLIC_URL is required with AVATAR_S3_ENDPOINT"))
+		}
+	}
+
+	if c.ForumGRPCAddr != "" && c.ExportTTL <= 0 {
+		errs = append(errs, fmt.Errorf("EXPORT_TTL %s: must be positive", c.ExportTTL))
+	}
+
+	return errors.Join(errs...)
+}
+
+// applyEnv переопределяет значения заданными переменными окружения и возвращает все ошибки разбора
+func (c *Config) applyEnv() error {
+	errs := []error{
+		envDuration("ACCESS_EXPIRY", &c.AccessExpiry),
+		envDuration("REFRESH_EXPIRY", &c.RefreshExpiry),
+		envInt("LOGIN_MAX_FAILURES", &c.LoginMaxFailures),
+		envInt("LOGIN_MAX_IP_FAILURES", &c.LoginMaxIPFailures),
+		envDuration("LOGIN_LOCKOUT", &c.LoginLockout),
+		envInt("PASSWORD_MIN_LENGTH", &c.PasswordMinLength),
+		envInt("PASSWORD_MAX_LENGTH", &c.PasswordMaxLength),
+		envInt("PASSWORD_MIN_CLASSES", &c.PasswordMinClasses),
+		envBool("PASSWORD_BAN_COMMON", &c.PasswordBanCommon),
+		envBool("COOKIE_AUTH", &c.CookieAuth),
+		envBool("COOKIE_SECURE", &c.CookieSecure),
+		envBool("INVITE_ONLY", &c.InviteOnly),
+		envDuration("MAGIC_LINK_TTL", &c.MagicLinkTTL),
+		envInt("SMTP_PORT", &c.SMTPPort),
+		envDuration("EXPORT_TTL", &c.ExportTTL),
+	}
+
+	// Перцы обычно монтируются из хранилища секретов файлом PASSWORD_PEPPERS_FILE
+	// в том же формате, что и PASSWORD_PEPPERS; и то и другое заменяет перцы из файла конфигурации
+	peppers, ok := os.LookupEnv("PASSWORD_PEPPERS")
+	if file := os.Getenv("PASSWORD_PEPPERS_FILE"); file != "" {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("PASSWORD_PEPPERS_FILE: %w", err))
+		}
+		peppers, ok = strings.TrimSpace(string(data)), err == nil
+	}
+	if ok {
+		list, err := parsePeppers(peppers)
+		c.PasswordPeppers = list
+		errs = append(errs, err)
+	}
+
+	// Ключи из JWT_KEYS заменяют ключи из файла целиком
+	if value, ok := os.LookupEnv("JWT_KEYS"); ok {
+		keys, err := parseJWTKeys(value)
+		c.JWTKeys = keys
+		errs = append(errs, err)
+	}
+
+	for key, dst := range map[string]*string{
+		"JWT_SECRET":              &c.JWTSecret,
+		"DB_PATH":                 &c.DBPath,
+		"DB_DRIVER":               &c.DBDriver,
+		"DB_DSN":                  &c.DBDSN,
+		"REDIS_URL":               &c.RedisURL,
+		"TRUSTED_PROXIES":         &c.TrustedProxies,
+		"SERVER_PORT":             &c.ServerPort,
+		"GRPC_PORT":               &c.GRPCPort,
+		"RUNTIME_CONFIG":          &c.RuntimeConfigPath,
+		"PASSWORD_BANNED_FILE":    &c.PasswordBannedFile,
+		"PASSWORD_PEPPER_ID":      &c.PasswordPepperID,
+		"CONTENT_SECURITY_POLICY": &c.ContentSecurityPolicy,
+		"GOOGLE_CLIENT_ID":        &c.GoogleClientID,
+		"GOOGLE_CLIENT_SECRET":    &c.GoogleClientSecret,
+		"GOOGLE_REDIRECT_URL":     &c.GoogleRedirectURL,
+		"GITHUB_CLIENT_ID":        &c.GitHubClientID,
+		"GITHUB_CLIENT_SECRET":    &c.GitHubClientSecret,
+		"GITHUB_REDIRECT_URL":     &c.GitHubRedirectURL,
+		"SAML_BASE_URL":           &c.SAMLBaseURL,
+		"SAML_CERT_FILE":          &c.SAMLCertFile,
+		"SAML_KEY_FILE":           &c.SAMLKeyFile,
+		"MAGIC_LINK_URL":          &c.MagicLinkURL,
+		"SESSION_REVOKE_URL":      &c.SessionRevokeURL,
+		"MAIL_DRIVER":             &c.MailDriver,
+		"MAIL_FROM":               &c.MailFrom,
+		"SMTP_HOST":               &c.SMTPHost,
+		"SMTP_USERNAME":           &c.SMTPUsername,
+		"SMTP_PASSWORD":           &c.SMTPPassword,
+		"SES_REGION":              &c.SESRegion,
+		"AVATAR_DIR":              &c.AvatarDir,
+		"AVATAR_PUBLIC_URL":       &c.AvatarPublicURL,
+		"AVATAR_S3_BUCKET":        &c.AvatarS3Bucket,
+		"AVATAR_S3_REGION":        &c.AvatarS3Region,
+		"AVATAR_S3_ENDPOINT":      &c.AvatarS3Endpoint,
+		"AWS_ACCESS_KEY_ID":       &c.AvatarS3AccessKey,
+		"AWS_SECRET_ACCESS_KEY":   &c.AvatarS3SecretKey,
+		"FORUM_GRPC_ADDR":         &c.ForumGRPCAddr,
+		"EXPORT_DIR":              &c.ExportDir,
+	} {
+		if value, ok := os.LookupEnv(key); ok {
+			*dst = value
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// randomSecret случайный секрет подписи JWT для запуска без JWT_SECRET
+func randomSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate JWT secret: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// parsePeppers разбирает список перцев вида "id=secret,..."
+func parsePeppers(value string) ([]PasswordPepper, error) {
+	var peppers []PasswordPepper
+	var errs []error
+	for i, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		id, secret, ok := strings.Cut(item, "=")
+		if !ok || id == "" || secret == "" {
+			errs = append(errs, fmt.Errorf("invalid PASSWORD_PEPPERS entry #%d: expected id=secret", i+1))
+			continue
+		}
+		peppers = append(peppers, PasswordPepper{ID: id, Secret: secret})
+	}
+	return peppers, errors.Join(errs...)
+}
+
+// parseJWTKeys разбирает расписание ключей JWT_KEYS вида "kid=secret@2026-01-01T00:00:00Z,...".
+// Время активации можно опустить ("kid=secret"), тогда ключ подписывает токены сразу.
+func parseJWTKeys(value string) ([]JWTKey, error) {
+	var keys []JWTKey
+	var errs []error
+	for i, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		id, rest, ok := strings.Cut(item, "=")
+		if !ok || id == "" || rest == "" {
+			errs = append(errs, fmt.Errorf("invalid JWT_KEYS entry #%d: expected kid=secret[@time]", i+1)) // Без значения: в нем секрет
+			continue
+		}
+
+		key := JWTKey{ID: id, Secret: rest}
+		if at := strings.LastIndex(rest, "@"); at >= 0 {
+			activeAt, err := time.Parse(time.RFC3339, rest[at+1:])
+			if err != nil {
+				errs = append(errs, fmt.Errorf("invalid JWT_KEYS activation time of key %q: expected RFC3339", id))
+				continue
+			}
+			key.Secret, key.ActiveAt = rest[:at], activeAt
+		}
+		keys = append(keys, key)
+	}
+	return keys, errors.Join(errs...)
 }
 
-// newProductionConfig создает конфигурацию для production
-func newProductionConfig() (*Config, error) {
-	jwtSecret := getEnv("JWT_SECRET", "")
-	if jwtSecret == "" {
-		return nil, errors.New("JWT_SECRET is required")
-	}
-
-	return &Config{
-		JWTSecret:     jwtSecret,
-		AccessExpiry:  parseDuration(getEnv("ACCESS_EXPIRY", defaultAccessExpiry.String())),
-		RefreshExpiry: parseDuration(getEnv("REFRESH_EXPIRY", defaultRefreshExpiry.String())),
-		DBPath:        getEnv("DB_PATH", defaultDBPath),
-		ServerPort:    getEnv("SERVER_PORT", defaultServerPort),
-		Env:           "production",
-	}, nil
+// envDuration записывает в dst длительность из переменной окружения key, если она задана
+func envDuration(key string, dst *time.Duration) error {
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		return nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return fmt.Errorf("invalid %s %q: expected a duration", key, value)
+	}
+	*dst = d
+	return nil
 }
 
-// parseDuration преобразует строку в time.Duration с обработкой ошибок
-func parseDuration(s string) time.Duration {
-	d, err := time.ParseDuration(s)
+// envInt записывает в dst целое число из переменной окружения key, если она задана
+func envInt(key string, dst *int) error {
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		return nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return fmt.Errorf("invalid %s %q: expected an integer", key, value)
+	}
+	*dst = n
+	return nil
+}
+
+// envBool записывает в dst логическое значение из переменной окружения key, если она задана
+func envBool(key string, dst *bool) error {
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		return nil
+	}
+	b, err := strconv.ParseBool(value)
 	if err != nil {
-		return defaultAccessExpiry // возвращаем значение по умолчанию при ошибке
+		return fmt.Errorf("invalid %s %q: expected true or false", key, value)
 	}
-	return d
+	*dst = b
+	return nil
 }
 
 // getEnv возвращает значение переменной окружения или значение по умолчанию
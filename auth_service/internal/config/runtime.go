@@ -0,0 +1,47 @@
+package config
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/kprf42/dolgova/pkg/logger"
+	"github.com/kprf42/dolgova/pkg/runtimecfg"
+)
+
+// Runtime настройки, которые можно менять без перезапуска сервиса
+type Runtime struct {
+	LogLevel    string   `json:"log_level"`    // Уровень логирования
+	CORSOrigins []string `json:"cors_origins"` // Разрешенные origin для CORS ("*" - любой, но без cookie)
+}
+
+// DefaultRuntime настройки по умолчанию, если файл конфигурации отсутствует
+func DefaultRuntime() Runtime {
+	return Runtime{
+		LogLevel:    "info",
+		CORSOrigins: []string{"http://localhost:3000"},
+	}
+}
+
+// AllowsOrigin проверяет, разрешен ли origin для CORS: указан явно или разрешены все ("*")
+func (r *Runtime) AllowsOrigin(origin string) bool {
+	return r.ListsOrigin(origin) || slices.Contains(r.CORSOrigins, "*")
+}
+
+// ListsOrigin проверяет, указан ли origin в списке явно. Запросы с cookie разрешаются
+// только таким origin: "*" открыл бы ответы с данными пользователя любому сайту.
+func (r *Runtime) ListsOrigin(origin string) bool {
+	for _, allowed := range r.CORSOrigins {
+		if allowed != "*" && strings.EqualFold(allowed, origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// RuntimeWatcher следит за файлом runtime настроек и применяет изменения на лету
+type RuntimeWatcher = runtimecfg.Watcher[Runtime]
+
+// NewRuntimeWatcher загружает начальные настройки из файла (если он есть)
+func NewRuntimeWatcher(path string, defaults Runtime, log *logger.Logger) (*RuntimeWatcher, error) {
+	return runtimecfg.New(path, defaults, nil, log)
+}
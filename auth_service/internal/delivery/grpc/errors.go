@@ -0,0 +1,84 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net"
+
+	"github.com/kprf42/dolgova/auth_service/internal/entity"
+	"github.com/kprf42/dolgova/pkg/grpcerr"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// errorDomain домен ErrorInfo ошибок сервиса аутентификации
+const errorDomain = "auth.dolgova"
+
+// Причины ошибок (ErrorInfo.reason). Клиенты опираются на них, поэтому значения не меняются.
+const (
+	reasonInvalidRequest     = "INVALID_REQUEST" // Подробности в BadRequest.field_violations
+	reasonUserAlreadyExists  = "USER_ALREADY_EXISTS"
+	reasonInvalidCredentials = "INVALID_CREDENTIALS"
+	reasonInvalidToken       = "INVALID_TOKEN"
+	reasonTokenRevoked       = "TOKEN_REVOKED"
+	reasonAccountLocked      = "ACCOUNT_LOCKED"    // Время окончания блокировки в тексте ошибки
+	reasonAccountSuspended   = "ACCOUNT_SUSPENDED" // Аккаунт приостановлен или заблокирован администратором
+	reasonInviteRequired     = "INVITE_REQUIRED"   // Регистрация только по приглашениям, код не указан
+	reasonInvalidInvite      = "INVALID_INVITE"    // Код приглашения недействителен, истек или исчерпан
+	reasonInternal           = "INTERNAL"
+)
+
+// required добавляет нарушение, если обязательное поле пустое
+func required(violations []*grpcerr.Violation, field, value string) []*grpcerr.Violation {
+	if value == "" {
+		violations = append(violations, grpcerr.Field(field, field+" is required"))
+	}
+	return violations
+}
+
+// invalidArgument ошибка проверки запроса с нарушениями полей
+func invalidArgument(message string, violations ...*grpcerr.Violation) error {
+	return grpcerr.InvalidArgument(errorDomain, reasonInvalidRequest, message, violations...)
+}
+
+// invalidField ошибка проверки одного поля запроса
+func invalidField(field, description string) error {
+	return invalidArgument(description, grpcerr.Field(field, description))
+}
+
+// weakPassword ошибка с нарушением поля password на каждое правило парольной политики
+func weakPassword(err error) error {
+	var policy *entity.PasswordPolicyError
+	if !errors.As(err, &policy) {
+		return invalidField("password", "password does not meet the password policy")
+	}
+	violations := make([]*grpcerr.Violation, 0, len(policy.Violations))
+	for _, v := range policy.Violations {
+		violations = append(violations, grpcerr.Field("password", v.String()))
+	}
+	return invalidArgument("password does not meet the password policy", violations...)
+}
+
+// peerClient сведения о клиенте gRPC: адрес соединения и user-agent из метаданных
+func peerClient(ctx context.Context) entity.ClientInfo {
+	client := entity.ClientInfo{IP: peerIP(ctx)}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if ua := md.Get("user-agent"); len(ua) > 0 {
+			client.UserAgent = ua[0]
+		}
+	}
+	return client
+}
+
+// peerIP адрес клиента gRPC соединения
+func peerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+	ip, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return ip
+}
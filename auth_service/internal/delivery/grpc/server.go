@@ -3,13 +3,16 @@ package auth
 import (
 	"context"
 	"errors"
+	"fmt"
+	"time"
 
 	"github.com/kprf42/dolgova/auth_service/internal/entity"
 	"github.com/kprf42/dolgova/auth_service/internal/usecase/auth"
 	"github.com/kprf42/dolgova/auth_service/internal/usecase/jwt"
-	proto "github.com/kprf42/dolgova/proto/auth"
+	"github.com/kprf42/dolgova/pkg/grpcerr"
+	proto "github.com/kprf42/dolgova/proto/auth/v1"
 	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type AuthServer struct {
@@ -24,22 +27,30 @@ func NewAuthServer(authUC *auth.AuthUseCase, jwtUC jwt.JWTUseCase) *AuthServer {
 
 func (s *AuthServer) Register(ctx context.Context, req *proto.RegisterRequest) (*proto.RegisterResponse, error) {
 	// Валидация запроса
-	if req.GetUsername() == "" || req.GetEmail() == "" || req.GetPassword() == "" {
-		return nil, status.Error(codes.InvalidArgument, "username, email and password are required")
+	var violations []*grpcerr.Violation
+	violations = required(violations, "username", req.GetUsername())
+	violations = required(violations, "email", req.GetEmail())
+	violations = required(violations, "password", req.GetPassword())
+	if len(violations) > 0 {
+		return nil, invalidArgument("username, email and password are required", violations...)
 	}
 
 	// Вызов use case
-	user, err := s.authUC.Register(ctx, req.GetUsername(), req.GetEmail(), req.GetPassword())
+	user, err := s.authUC.Register(ctx, req.GetUsername(), req.GetEmail(), req.GetPassword(), req.GetInviteCode())
 	if err != nil {
 		switch {
 		case errors.Is(err, entity.ErrUserAlreadyExists):
-			return nil, status.Error(codes.AlreadyExists, "user with this email already exists")
+			return nil, grpcerr.New(codes.AlreadyExists, errorDomain, reasonUserAlreadyExists, "user with this email already exists")
 		case errors.Is(err, entity.ErrInvalidEmail):
-			return nil, status.Error(codes.InvalidArgument, "invalid email format")
+			return nil, invalidField("email", "invalid email format")
 		case errors.Is(err, entity.ErrWeakPassword):
-			return nil, status.Error(codes.InvalidArgument, "password must be at least 8 characters")
+			return nil, weakPassword(err)
+		case errors.Is(err, entity.ErrInviteRequired):
+			return nil, grpcerr.New(codes.PermissionDenied, errorDomain, reasonInviteRequired, "invite code is required")
+		case errors.Is(err, entity.ErrInvalidInvite):
+			return nil, grpcerr.New(codes.PermissionDenied, errorDomain, reasonInvalidInvite, "invalid invite code")
 		default:
-			return nil, status.Error(codes.Internal, "failed to register user")
+			return nil, grpcerr.New(codes.Internal, errorDomain, reasonInternal, "failed to register user")
 		}
 	}
 
@@ -50,36 +61,78 @@ func (s *AuthServer) Register(ctx context.Context, req *proto.RegisterRequest) (
 
 func (s *AuthServer) Login(ctx context.Context, req *proto.LoginRequest) (*proto.LoginResponse, error) {
 	// Валидация запроса
-	if req.GetEmail() == "" || req.GetPassword() == "" {
-		return nil, status.Error(codes.InvalidArgument, "email and password are required")
+	var violations []*grpcerr.Violation
+	violations = required(violations, "email", req.GetEmail())
+	violations = required(violations, "password", req.GetPassword())
+	if len(violations) > 0 {
+		return nil, invalidArgument("email and password are required", violations...)
 	}
 
 	// Вызов use case
-	tokens, err := s.authUC.Login(ctx, req.GetEmail(), req.GetPassword())
+	tokens, err := s.authUC.Login(ctx, req.GetEmail(), req.GetPassword(), peerClient(ctx))
+	var locked *entity.AccountLockedError
+	if errors.As(err, &locked) {
+		return nil, grpcerr.New(codes.ResourceExhausted, errorDomain, reasonAccountLocked,
+			"account locked until "+locked.Until.UTC().Format(time.RFC3339))
+	}
+	if errors.Is(err, entity.ErrAccountSuspended) {
+		return nil, grpcerr.New(codes.PermissionDenied, errorDomain, reasonAccountSuspended, err.Error())
+	}
 	if err != nil {
 		// Для безопасности возвращаем одинаковую ошибку
-		return nil, status.Error(codes.Unauthenticated, "invalid credentials")
+		return nil, grpcerr.New(codes.Unauthenticated, errorDomain, reasonInvalidCredentials, "invalid credentials")
 	}
 
 	return &proto.LoginResponse{
 		AccessToken:  tokens.AccessToken,
 		RefreshToken: tokens.RefreshToken,
 		ExpiresIn:    tokens.AtExpires,
+		ExpiresAt:    timestamppb.New(time.Unix(tokens.AtExpires, 0)),
 	}, nil
 }
 
 func (s *AuthServer) ValidateToken(ctx context.Context, req *proto.ValidateTokenRequest) (*proto.ValidateTokenResponse, error) {
 	if req.GetToken() == "" {
-		return nil, status.Error(codes.InvalidArgument, "token is required")
+		return nil, invalidField("token", "token is required")
 	}
 
-	claims, err := s.jwtUC.ValidateToken(req.GetToken())
+	claims, err := s.authUC.ValidateAccessToken(ctx, req.GetToken())
+	if errors.Is(err, entity.ErrTokenRevoked) {
+		return nil, grpcerr.New(codes.Unauthenticated, errorDomain, reasonTokenRevoked, "token revoked")
+	}
+	if errors.Is(err, entity.ErrAccountSuspended) {
+		return nil, grpcerr.New(codes.PermissionDenied, errorDomain, reasonAccountSuspended, err.Error())
+	}
 	if err != nil {
-		return nil, status.Error(codes.Unauthenticated, "invalid token")
+		return nil, grpcerr.New(codes.Unauthenticated, errorDomain, reasonInvalidToken, "invalid token")
 	}
 
 	return &proto.ValidateTokenResponse{
-		UserId: claims.UserID,
-		Valid:  true,
+		UserId:        claims.UserID,
+		Valid:         true,
+		ActingAdminId: claims.ActingAdminID,
+		Role:          claims.Role,
+		Scopes:        claims.Scope,
 	}, nil
 }
+
+func (s *AuthServer) GetUsers(ctx context.Context, req *proto.GetUsersRequest) (*proto.GetUsersResponse, error) {
+	users, err := s.authUC.GetUsers(ctx, req.GetIds())
+	if errors.Is(err, entity.ErrTooManyUsers) {
+		return nil, invalidField("ids", fmt.Sprintf("at most %d ids per request", auth.MaxUsersLookup))
+	}
+	if err != nil {
+		return nil, grpcerr.New(codes.Internal, errorDomain, reasonInternal, "failed to get users")
+	}
+
+	resp := &proto.GetUsersResponse{Users: make([]*proto.User, 0, len(users))}
+	for _, user := range users {
+		resp.Users = append(resp.Users, &proto.User{
+			Id:          user.ID,
+			Username:    user.Username,
+			DisplayName: user.DisplayName,
+			AvatarUrl:   user.AvatarURL,
+		})
+	}
+	return resp, nil
+}
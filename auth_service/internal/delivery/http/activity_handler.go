@@ -0,0 +1,36 @@
+package http
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/kprf42/dolgova/auth_service/internal/entity"
+)
+
+type ActivityResponse struct {
+	Events []*entity.AuthEvent `json:"events"`
+}
+
+// Activity возвращает историю входов текущего пользователя: ?limit=&offset=
+func (h *AuthHTTPHandler) Activity(w http.ResponseWriter, r *http.Request) {
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	userID, _ := r.Context().Value("user_id").(string)
+	events, err := h.authUC.ListActivity(r.Context(), userID, limit, offset)
+	if err != nil {
+		log.Printf("List activity error: %v", err)
+		h.jsonError(w, r, ErrCodeInternal, http.StatusInternalServerError)
+		return
+	}
+
+	h.JsonResponse(w, ActivityResponse{Events: events}, http.StatusOK)
+}
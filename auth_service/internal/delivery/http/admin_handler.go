@@ -0,0 +1,72 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/kprf42/dolgova/auth_service/internal/entity"
+	"github.com/kprf42/dolgova/auth_service/internal/usecase/auth"
+)
+
+// UserStatusRequest изменение статуса аккаунта администратором
+type UserStatusRequest struct {
+	Status string     `json:"status"` // active, suspended или banned
+	Until  *time.Time `json:"until"`  // Окончание приостановки; без него - бессрочно
+	Reason string     `json:"reason"` // Попадает в журнал аудита
+}
+
+// UserStatusResponse статус аккаунта после изменения
+type UserStatusResponse struct {
+	UserID string     `json:"user_id"`
+	Status string     `json:"status"`
+	Until  *time.Time `json:"until,omitempty"`
+	Reason string     `json:"reason,omitempty"`
+}
+
+// SetUserStatus приостанавливает, блокирует или восстанавливает аккаунт пользователя
+func (h *AuthHTTPHandler) SetUserStatus(w http.ResponseWriter, r *http.Request) {
+	var req UserStatusRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.jsonError(w, r, ErrCodeInvalidRequest, http.StatusBadRequest)
+		return
+	}
+
+	adminID, _ := r.Context().Value("user_id").(string)
+	user, err := h.authUC.SetUserStatus(r.Context(), adminID, chi.URLParam(r, "id"), auth.StatusChange{
+		Status: req.Status,
+		Until:  req.Until,
+		Reason: req.Reason,
+	})
+	switch {
+	case errors.Is(err, entity.ErrInvalidStatus):
+		h.jsonError(w, r, ErrCodeInvalidStatus, http.StatusBadRequest)
+		return
+	case errors.Is(err, entity.ErrOwnStatus):
+		h.jsonError(w, r, ErrCodeOwnStatus, http.StatusBadRequest)
+		return
+	case errors.Is(err, entity.ErrNotAdmin):
+		h.jsonError(w, r, ErrCodeForbidden, http.StatusForbidden)
+		return
+	case errors.Is(err, entity.ErrImpersonationForbidden):
+		h.jsonError(w, r, ErrCodeImpersonation, http.StatusForbidden)
+		return
+	case errors.Is(err, entity.ErrUserNotFound):
+		h.jsonError(w, r, ErrCodeUserNotFound, http.StatusNotFound)
+		return
+	case err != nil:
+		log.Printf("Set user status error: %v", err)
+		h.jsonError(w, r, ErrCodeInternal, http.StatusInternalServerError)
+		return
+	}
+
+	h.JsonResponse(w, UserStatusResponse{
+		UserID: user.ID,
+		Status: user.Status,
+		Until:  user.StatusUntil,
+		Reason: user.StatusReason,
+	}, http.StatusOK)
+}
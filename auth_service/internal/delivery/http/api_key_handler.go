@@ -0,0 +1,171 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/kprf42/dolgova/auth_service/internal/entity"
+	"github.com/kprf42/dolgova/auth_service/internal/usecase/auth"
+)
+
+// APIKeyHeader заголовок с ключом внутреннего сервиса
+const APIKeyHeader = "X-API-Key"
+
+// CreateAPIKeyRequest выпуск ключа внутреннего сервиса
+type CreateAPIKeyRequest struct {
+	Name      string   `json:"name"`
+	Scopes    []string `json:"scopes"`
+	ExpiresIn string   `json:"expires_in"` // Срок действия в формате time.Duration, например 720h; пусто - бессрочно
+}
+
+// RotateAPIKeyRequest ротация ключа
+type RotateAPIKeyRequest struct {
+	GracePeriod string `json:"grace_period"` // Сколько еще работает старый ключ, например 24h; пусто - отзывается сразу
+}
+
+// APIKeyResponse ключ; значение Key возвращается только при выпуске и ротации
+type APIKeyResponse struct {
+	*entity.APIKey
+	Key string `json:"key,omitempty"`
+}
+
+type APIKeysResponse struct {
+	Keys []*entity.APIKey `json:"keys"`
+}
+
+// CreateAPIKey выпускает ключ внутреннего сервиса
+func (h *AuthHTTPHandler) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	var req CreateAPIKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.jsonError(w, r, ErrCodeInvalidRequest, http.StatusBadRequest)
+		return
+	}
+	ttl, ok := parseOptionalDuration(req.ExpiresIn)
+	if !ok {
+		h.jsonError(w, r, ErrCodeAPIKeyRequest, http.StatusBadRequest)
+		return
+	}
+
+	adminID, _ := r.Context().Value("user_id").(string)
+	key, raw, err := h.apiKeys.Issue(r.Context(), adminID, auth.APIKeyRequest{
+		Name:   req.Name,
+		Scopes: req.Scopes,
+		TTL:    ttl,
+	})
+	if err != nil {
+		h.apiKeyError(w, r, err)
+		return
+	}
+
+	h.JsonResponse(w, APIKeyResponse{APIKey: key, Key: raw}, http.StatusCreated)
+}
+
+// ListAPIKeys возвращает ключи внутренних сервисов без секретов
+func (h *AuthHTTPHandler) ListAPIKeys(w http.ResponseWriter, r *http.Request) {
+	adminID, _ := r.Context().Value("user_id").(string)
+	keys, err := h.apiKeys.List(r.Context(), adminID)
+	if err != nil {
+		h.apiKeyError(w, r, err)
+		return
+	}
+
+	h.JsonResponse(w, APIKeysResponse{Keys: keys}, http.StatusOK)
+}
+
+// RotateAPIKey выпускает замену ключа; старый ключ работает еще grace_period
+func (h *AuthHTTPHandler) RotateAPIKey(w http.ResponseWriter, r *http.Request) {
+	var req RotateAPIKeyRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.jsonError(w, r, ErrCodeInvalidRequest, http.StatusBadRequest)
+			return
+		}
+	}
+	grace, ok := parseOptionalDuration(req.GracePeriod)
+	if !ok {
+		h.jsonError(w, r, ErrCodeAPIKeyRequest, http.StatusBadRequest)
+		return
+	}
+
+	adminID, _ := r.Context().Value("user_id").(string)
+	key, raw, err := h.apiKeys.Rotate(r.Context(), adminID, chi.URLParam(r, "id"), grace)
+	if err != nil {
+		h.apiKeyError(w, r, err)
+		return
+	}
+
+	h.JsonResponse(w, APIKeyResponse{APIKey: key, Key: raw}, http.StatusCreated)
+}
+
+// RevokeAPIKey отзывает ключ
+func (h *AuthHTTPHandler) RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	adminID, _ := r.Context().Value("user_id").(string)
+	if err := h.apiKeys.Revoke(r.Context(), adminID, chi.URLParam(r, "id")); err != nil {
+		h.apiKeyError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// APIKeyMiddleware пропускает только запросы внутренних сервисов с ключом в заголовке X-API-Key,
+// у которого есть разрешение scope. В контекст кладется api_key_id и имя сервиса.
+func (h *AuthHTTPHandler) APIKeyMiddleware(scope string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			raw := r.Header.Get(APIKeyHeader)
+			if raw == "" {
+				h.jsonError(w, r, ErrCodeAPIKeyRequired, http.StatusUnauthorized)
+				return
+			}
+
+			key, err := h.apiKeys.Authenticate(r.Context(), raw, scope)
+			switch {
+			case errors.Is(err, entity.ErrInvalidAPIKey):
+				h.jsonError(w, r, ErrCodeInvalidAPIKey, http.StatusUnauthorized)
+				return
+			case errors.Is(err, entity.ErrAPIKeyScope):
+				h.jsonError(w, r, ErrCodeAPIKeyScope, http.StatusForbidden)
+				return
+			case err != nil:
+				log.Printf("API key check error: %v", err)
+				h.jsonError(w, r, ErrCodeInternal, http.StatusInternalServerError)
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), "api_key_id", key.ID)
+			ctx = context.WithValue(ctx, "service", key.Name)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+func (h *AuthHTTPHandler) apiKeyError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, entity.ErrInvalidAPIKeyRequest):
+		h.jsonError(w, r, ErrCodeAPIKeyRequest, http.StatusBadRequest)
+	case errors.Is(err, entity.ErrAPIKeyNotFound):
+		h.jsonError(w, r, ErrCodeAPIKeyNotFound, http.StatusNotFound)
+	case errors.Is(err, entity.ErrNotAdmin):
+		h.jsonError(w, r, ErrCodeForbidden, http.StatusForbidden)
+	case errors.Is(err, entity.ErrImpersonationForbidden):
+		h.jsonError(w, r, ErrCodeImpersonation, http.StatusForbidden)
+	default:
+		log.Printf("API key error: %v", err)
+		h.jsonError(w, r, ErrCodeInternal, http.StatusInternalServerError)
+	}
+}
+
+// parseOptionalDuration разбирает неотрицательную длительность; пустая строка - 0
+func parseOptionalDuration(s string) (time.Duration, bool) {
+	if s == "" {
+		return 0, true
+	}
+	d, err := time.ParseDuration(s)
+	return d, err == nil && d >= 0
+}
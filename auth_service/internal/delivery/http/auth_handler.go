@@ -6,25 +6,70 @@ import (
 	"encoding/json"
 	"errors"
 	"log"
+	"net"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/kprf42/dolgova/auth_service/internal/entity"
 	"github.com/kprf42/dolgova/auth_service/internal/usecase/auth"
 	"github.com/kprf42/dolgova/auth_service/internal/usecase/jwt"
+	"github.com/kprf42/dolgova/pkg/audit"
+	"github.com/kprf42/dolgova/pkg/csrf"
 )
 
+// AccessTokenCookie cookie с access токеном в режиме cookie-аутентификации
+const AccessTokenCookie = "access_token"
+
+// CookieConfig настройки выдачи токена в cookie
+type CookieConfig struct {
+	Enabled bool          // Режим cookie-аутентификации
+	Secure  bool          // Cookie только по HTTPS
+	TTL     time.Duration // Время жизни cookie (совпадает с access токеном)
+}
+
 // AuthHTTPHandler объединяет все HTTP-обработчики аутентификации
 type AuthHTTPHandler struct {
-	authUC *auth.AuthUseCase
-	jwtUC  jwt.JWTUseCase
+	authUC     *auth.AuthUseCase
+	jwtUC      jwt.JWTUseCase
+	audit      *audit.Store
+	oauth      *auth.OAuthProviders
+	saml       *auth.SAMLProviders
+	magicLinks *auth.MagicLinks      // nil, если вход по ссылке выключен
+	avatars    *auth.Avatars         // nil, если загрузка аватаров выключена
+	deletion   *auth.AccountDeletion // nil, если удаление аккаунта выключено
+	exports    *auth.DataExports     // nil, если выгрузка данных выключена
+	apiKeys    *auth.APIKeys
+	invites    *auth.Invites
+	devices    *auth.Devices // nil, если письма о входе с нового устройства выключены
+	cookies    CookieConfig
 }
 
-// NewAuthHTTPHandler создает новый экземпляр обработчиков
-func NewAuthHTTPHandler(authUC *auth.AuthUseCase, jwtUC jwt.JWTUseCase) *AuthHTTPHandler {
+// NewAuthHTTPHandler создает новый экземпляр обработчиков. oauth и saml могут быть nil,
+// тогда вход через внешние провайдеры выключен.
+func NewAuthHTTPHandler(authUC *auth.AuthUseCase, jwtUC jwt.JWTUseCase, auditLog *audit.Store, oauth *auth.OAuthProviders, saml *auth.SAMLProviders, magicLinks *auth.MagicLinks, avatars *auth.Avatars, deletion *auth.AccountDeletion, exports *auth.DataExports, apiKeys *auth.APIKeys, invites *auth.Invites, devices *auth.Devices, cookies CookieConfig) *AuthHTTPHandler {
+	if oauth == nil {
+		oauth = auth.NewOAuthProviders()
+	}
+	if saml == nil {
+		saml = auth.NewSAMLProviders()
+	}
 	return &AuthHTTPHandler{
-		authUC: authUC,
-		jwtUC:  jwtUC,
+		authUC:     authUC,
+		jwtUC:      jwtUC,
+		audit:      auditLog,
+		oauth:      oauth,
+		saml:       saml,
+		magicLinks: magicLinks,
+		avatars:    avatars,
+		deletion:   deletion,
+		exports:    exports,
+		apiKeys:    apiKeys,
+		invites:    invites,
+		devices:    devices,
+		cookies:    cookies,
 	}
 }
 
@@ -33,17 +78,27 @@ func (h *AuthHTTPHandler) RegisterRoutes(router chi.Router) {
 	router.Route("/auth", func(r chi.Router) {
 		r.Post("/register", h.Register)
 		r.Post("/login", h.Login)
+		r.Post("/refresh", h.Refresh)
+		r.Get("/oauth/{provider}", h.OAuthLogin)
+		r.Get("/oauth/{provider}/callback", h.OAuthCallback)
+		r.Get("/saml/{idp}", h.SAMLLogin)
+		r.Get("/saml/{idp}/metadata", h.SAMLMetadata)
+		r.Post("/saml/{idp}/acs", h.SAMLACS)
 		r.Group(func(r chi.Router) {
 			r.Use(h.AuthMiddleware)
+			r.Post("/logout", h.Logout)
 		})
 	})
 }
 
-// RegisterRequest структура запроса регистрации
+// RegisterRequest структура запроса регистрации. Длина пароля и его сложность
+// проверяются парольной политикой, которая настраивается без перезапуска.
 type RegisterRequest struct {
-	Username string `json:"username"`
-	Email    string `json:"email"`
-	Password string `json:"password"`
+	Username string `json:"username" validate:"required,max=32"`
+	Email    string `json:"email" validate:"required,email,max=254"`
+	Password string `json:"password" validate:"required"`
+	// InviteCode код приглашения; обязателен в режиме регистрации по приглашениям
+	InviteCode string `json:"invite_code,omitempty"`
 }
 
 // RegisterResponse структура ответа регистрации
@@ -55,32 +110,80 @@ func (h *AuthHTTPHandler) Register(w http.ResponseWriter, r *http.Request) {
 	var req RegisterRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		log.Printf("Decode error: %v", err)
-		h.jsonError(w, "Invalid request body", http.StatusBadRequest)
+		h.jsonError(w, r, ErrCodeInvalidRequest, http.StatusBadRequest)
+		return
+	}
+	req.Username = strings.TrimSpace(req.Username)
+	req.Email = strings.TrimSpace(req.Email)
+	if !h.validateRequest(w, r, &req) {
 		return
 	}
 
-	log.Printf("Register attempt: %+v", req)
+	log.Printf("Register attempt: username=%q email=%q", req.Username, req.Email)
 
-	user, err := h.authUC.Register(r.Context(), req.Username, req.Email, req.Password)
+	user, err := h.authUC.Register(r.Context(), req.Username, req.Email, req.Password, req.InviteCode)
 	if err != nil {
 		log.Printf("Register error: %v", err)
-		h.handleAuthError(w, err)
+		h.handleAuthError(w, r, err)
 		return
 	}
 
 	h.JsonResponse(w, RegisterResponse{UserID: user.ID}, http.StatusCreated)
 }
 
-func (h *AuthHTTPHandler) jsonError(w http.ResponseWriter, message string, statusCode int) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(statusCode)
-	json.NewEncoder(w).Encode(map[string]string{"error": message})
+// jsonError отправляет ошибку с кодом и текстом на языке клиента (Accept-Language)
+func (h *AuthHTTPHandler) jsonError(w http.ResponseWriter, r *http.Request, code string, statusCode int) {
+	WriteError(w, r, statusCode, code)
+}
+
+// errorResponse отправляет ошибку с кодом code и подробностями details
+func (h *AuthHTTPHandler) errorResponse(w http.ResponseWriter, r *http.Request, statusCode int, code string, details *ErrorDetails) {
+	writeErrorResponse(w, r, statusCode, ErrorResponse{Code: code, Details: details})
+}
+
+// lockedError ответ на вход, заблокированный до until после серии неудачных попыток
+func (h *AuthHTTPHandler) lockedError(w http.ResponseWriter, r *http.Request, until time.Time) {
+	retryAfter := int(time.Until(until).Seconds()) + 1
+	w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+	h.errorResponse(w, r, http.StatusTooManyRequests, ErrCodeAccountLocked, &ErrorDetails{LockedUntil: &until})
+}
+
+// suspendedError ответ на запрос пользователя, чей аккаунт приостановлен или заблокирован администратором
+func (h *AuthHTTPHandler) suspendedError(w http.ResponseWriter, r *http.Request, suspended *entity.AccountSuspendedError) {
+	code := ErrCodeAccountSuspended
+	if suspended.Status == entity.UserStatusBanned {
+		code = ErrCodeAccountBanned
+	}
+	h.errorResponse(w, r, http.StatusForbidden, code, &ErrorDetails{SuspendedUntil: suspended.Until})
+}
+
+// clientIP адрес клиента; за доверенным прокси RemoteAddr подменяет ipban.RealIP
+func clientIP(r *http.Request) string {
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return ip
+}
+
+// clientInfo сведения о клиенте для новой или продолжаемой сессии
+func clientInfo(r *http.Request) entity.ClientInfo {
+	return entity.ClientInfo{
+		IP:        clientIP(r),
+		UserAgent: r.UserAgent(),
+	}
+}
+
+// IPBanned ответ на запрос с забаненного адреса
+func (h *AuthHTTPHandler) IPBanned(w http.ResponseWriter, r *http.Request) {
+	h.jsonError(w, r, ErrCodeIPBanned, http.StatusForbidden)
 }
 
 // LoginRequest структура запроса входа
 type LoginRequest struct {
-	Email    string `json:"email"`
-	Password string `json:"password"`
+	Identifier string `json:"identifier" validate:"required_without=Email,max=254"` // Email или имя пользователя
+	Email      string `json:"email" validate:"max=254"`                             // Устаревшее поле; используется, если identifier не задан
+	Password   string `json:"password" validate:"required"`
 }
 
 // LoginResponse структура ответа входа
@@ -88,78 +191,349 @@ type LoginResponse struct {
 	AccessToken  string `json:"access_token"`
 	RefreshToken string `json:"refresh_token"`
 	ExpiresIn    int64  `json:"expires_in"`
+	CSRFToken    string `json:"csrf_token,omitempty"`
 }
 
 // Login обработчик входа пользователя
 func (h *AuthHTTPHandler) Login(w http.ResponseWriter, r *http.Request) {
 	var req LoginRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		h.jsonError(w, r, ErrCodeInvalidRequest, http.StatusBadRequest)
+		return
+	}
+
+	req.Identifier = strings.TrimSpace(req.Identifier)
+	req.Email = strings.TrimSpace(req.Email)
+	if !h.validateRequest(w, r, &req) {
+		return
+	}
+
+	identifier := req.Identifier
+	if identifier == "" {
+		identifier = req.Email
+	}
+
+	tokens, err := h.authUC.Login(r.Context(), identifier, req.Password, clientInfo(r))
+	var locked *entity.AccountLockedError
+	if errors.As(err, &locked) {
+		h.lockedError(w, r, locked.Until)
+		return
+	}
+	var suspended *entity.AccountSuspendedError
+	if errors.As(err, &suspended) {
+		h.suspendedError(w, r, suspended)
+		return
+	}
+	if err != nil {
+		h.jsonError(w, r, ErrCodeInvalidCredentials, http.StatusUnauthorized)
 		return
 	}
 
-	tokens, err := h.authUC.Login(r.Context(), req.Email, req.Password)
+	h.writeTokens(w, r, tokens)
+}
+
+// RefreshRequest структура запроса обновления токенов
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// Refresh обменивает refresh токен на новую пару токенов
+func (h *AuthHTTPHandler) Refresh(w http.ResponseWriter, r *http.Request) {
+	var req RefreshRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.RefreshToken == "" {
+		h.jsonError(w, r, ErrCodeInvalidRequest, http.StatusBadRequest)
+		return
+	}
+
+	tokens, err := h.authUC.Refresh(r.Context(), req.RefreshToken, clientInfo(r))
+	if errors.Is(err, entity.ErrInvalidToken) {
+		h.jsonError(w, r, ErrCodeInvalidToken, http.StatusUnauthorized)
+		return
+	}
+	var suspended *entity.AccountSuspendedError
+	if errors.As(err, &suspended) {
+		h.suspendedError(w, r, suspended)
+		return
+	}
 	if err != nil {
-		http.Error(w, "Invalid credentials", http.StatusUnauthorized)
+		log.Printf("Refresh error: %v", err)
+		h.jsonError(w, r, ErrCodeInternal, http.StatusInternalServerError)
 		return
 	}
 
-	h.JsonResponse(w, LoginResponse{
+	h.writeTokens(w, r, tokens)
+}
+
+// writeTokens отправляет выданные токены, в режиме cookie-аутентификации еще и в cookie с CSRF токеном
+func (h *AuthHTTPHandler) writeTokens(w http.ResponseWriter, r *http.Request, tokens *entity.TokenDetails) {
+	response := LoginResponse{
 		AccessToken:  tokens.AccessToken,
 		RefreshToken: tokens.RefreshToken,
 		ExpiresIn:    tokens.AtExpires,
-	}, http.StatusOK)
+	}
+
+	if h.cookies.Enabled {
+		http.SetCookie(w, &http.Cookie{
+			Name:     AccessTokenCookie,
+			Value:    tokens.AccessToken,
+			Path:     "/",
+			MaxAge:   int(h.cookies.TTL.Seconds()),
+			HttpOnly: true,
+			Secure:   h.cookies.Secure,
+			SameSite: http.SameSiteLaxMode,
+		})
+
+		csrfToken, err := csrf.Issue(w, h.cookies.Secure, h.cookies.TTL)
+		if err != nil {
+			h.jsonError(w, r, ErrCodeInternal, http.StatusInternalServerError)
+			return
+		}
+		response.CSRFToken = csrfToken
+	}
+
+	h.JsonResponse(w, response, http.StatusOK)
+}
+
+// LogoutRequest необязательное тело запроса выхода
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token"` // Отзывается вместе с access токеном
+}
+
+// Logout отзывает текущий access токен и переданный refresh токен
+func (h *AuthHTTPHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	var req LogoutRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.jsonError(w, r, ErrCodeInvalidRequest, http.StatusBadRequest)
+			return
+		}
+	}
+
+	err := h.authUC.Logout(r.Context(), h.accessToken(r), req.RefreshToken)
+	if errors.Is(err, entity.ErrInvalidToken) {
+		h.jsonError(w, r, ErrCodeInvalidToken, http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		log.Printf("Logout error: %v", err)
+		h.jsonError(w, r, ErrCodeInternal, http.StatusInternalServerError)
+		return
+	}
+
+	h.clearAccessCookie(w)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// clearAccessCookie удаляет cookie с access токеном в режиме cookie-аутентификации
+func (h *AuthHTTPHandler) clearAccessCookie(w http.ResponseWriter) {
+	if !h.cookies.Enabled {
+		return
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     AccessTokenCookie,
+		Value:    "",
+		Path:     "/",
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   h.cookies.Secure,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// accessToken токен из заголовка Authorization или, в режиме cookie-аутентификации, из cookie
+func (h *AuthHTTPHandler) accessToken(r *http.Request) string {
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if token == "" && h.cookies.Enabled {
+		if cookie, err := r.Cookie(AccessTokenCookie); err == nil {
+			token = cookie.Value
+		}
+	}
+	return token
 }
 
 // AuthMiddleware middleware для аутентификации
 func (h *AuthHTTPHandler) AuthMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		token := r.Header.Get("Authorization")
+		token := h.accessToken(r)
 		if token == "" {
-			http.Error(w, "Authorization token required", http.StatusUnauthorized)
+			h.jsonError(w, r, ErrCodeTokenRequired, http.StatusUnauthorized)
 			return
 		}
 
-		claims, err := h.jwtUC.ValidateToken(token)
+		claims, err := h.authUC.ValidateAccessToken(r.Context(), token)
+		if errors.Is(err, entity.ErrTokenRevoked) {
+			h.jsonError(w, r, ErrCodeTokenRevoked, http.StatusUnauthorized)
+			return
+		}
+		var suspended *entity.AccountSuspendedError
+		if errors.As(err, &suspended) {
+			h.suspendedError(w, r, suspended)
+			return
+		}
 		if err != nil {
-			http.Error(w, "Invalid token", http.StatusUnauthorized)
+			h.jsonError(w, r, ErrCodeInvalidToken, http.StatusUnauthorized)
+			return
+		}
+		// У гостя нет аккаунта: его токен принимает только форум
+		if claims.IsGuest() {
+			h.jsonError(w, r, ErrCodeGuestToken, http.StatusForbidden)
 			return
 		}
 
 		ctx := context.WithValue(r.Context(), "user_id", claims.UserID)
+		ctx = context.WithValue(ctx, "role", claims.Role)
+		ctx = context.WithValue(ctx, "session_id", claims.SessionID)
+
+		// Запросы под имперсонацией помечаются и пишутся в журнал аудита
+		if claims.ActingAdminID != "" {
+			ctx = audit.WithActingAdmin(ctx, claims.ActingAdminID)
+			audit.Request(h.audit, claims.UserID, claims.ActingAdminID, next, func(err error) {
+				log.Printf("Failed to write audit entry: %v", err)
+			}).ServeHTTP(w, r.WithContext(ctx))
+			return
+		}
+
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
 
-func (h *AuthHTTPHandler) handleAuthError(w http.ResponseWriter, err error) {
-	w.Header().Set("Content-Type", "application/json")
+// RequireRole пропускает только пользователей с одной из ролей roles из claim токена.
+// Должен стоять после AuthMiddleware. Роль в токене обновляется при входе и обновлении токенов.
+func (h *AuthHTTPHandler) RequireRole(roles ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			role, _ := r.Context().Value("role").(string)
+			for _, allowed := range roles {
+				if role == allowed {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+			h.jsonError(w, r, ErrCodeForbidden, http.StatusForbidden)
+		})
+	}
+}
+
+// ImpersonateRequest запрос администратора на вход под другим пользователем
+type ImpersonateRequest struct {
+	UserID string `json:"user_id"`
+	Reason string `json:"reason"` // Попадает в журнал аудита
+}
 
+// ImpersonateResponse короткоживущий access токен целевого пользователя
+type ImpersonateResponse struct {
+	AccessToken   string `json:"access_token"`
+	ExpiresIn     int64  `json:"expires_in"`
+	UserID        string `json:"user_id"`
+	ActingAdminID string `json:"acting_admin_id"`
+}
+
+// GuestTokenResponse гостевой access токен
+type GuestTokenResponse struct {
+	AccessToken string   `json:"access_token"`
+	ExpiresIn   int64    `json:"expires_in"`
+	Scope       []string `json:"scope"`
+}
+
+// GuestToken выдает анонимному посетителю короткоживущий токен для чтения форума и чата
+func (h *AuthHTTPHandler) GuestToken(w http.ResponseWriter, r *http.Request) {
+	tokens, err := h.authUC.IssueGuestToken(r.Context())
+	if err != nil {
+		h.jsonError(w, r, ErrCodeInternal, http.StatusInternalServerError)
+		return
+	}
+
+	h.JsonResponse(w, GuestTokenResponse{
+		AccessToken: tokens.AccessToken,
+		ExpiresIn:   tokens.AtExpires,
+		Scope:       []string{jwt.ScopeReadOnly, jwt.ScopeChat},
+	}, http.StatusOK)
+}
+
+// Impersonate выдает администратору токен пользователя для отладки проблем с аккаунтом
+func (h *AuthHTTPHandler) Impersonate(w http.ResponseWriter, r *http.Request) {
+	var req ImpersonateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.UserID == "" {
+		h.jsonError(w, r, ErrCodeInvalidRequest, http.StatusBadRequest)
+		return
+	}
+
+	adminID, _ := r.Context().Value("user_id").(string)
+	tokens, err := h.authUC.Impersonate(r.Context(), adminID, req.UserID, req.Reason, clientIP(r))
+	switch {
+	case errors.Is(err, entity.ErrNotAdmin):
+		h.jsonError(w, r, ErrCodeForbidden, http.StatusForbidden)
+		return
+	case errors.Is(err, entity.ErrUserNotFound):
+		h.jsonError(w, r, ErrCodeUserNotFound, http.StatusNotFound)
+		return
+	case errors.Is(err, entity.ErrImpersonationForbidden):
+		h.jsonError(w, r, ErrCodeImpersonation, http.StatusForbidden)
+		return
+	case err != nil:
+		h.jsonError(w, r, ErrCodeInternal, http.StatusInternalServerError)
+		return
+	}
+
+	h.JsonResponse(w, ImpersonateResponse{
+		AccessToken:   tokens.AccessToken,
+		ExpiresIn:     tokens.AtExpires,
+		UserID:        req.UserID,
+		ActingAdminID: adminID,
+	}, http.StatusOK)
+}
+
+func (h *AuthHTTPHandler) handleAuthError(w http.ResponseWriter, r *http.Request, err error) {
 	var (
-		message    string
+		code       string
 		statusCode int
+		policy     *entity.PasswordPolicyError
 	)
 
 	switch {
+	case errors.As(err, &policy):
+		h.errorResponse(w, r, http.StatusBadRequest, ErrCodeWeakPassword, &ErrorDetails{Violations: policy.Violations})
+		return
 	case errors.Is(err, entity.ErrUserAlreadyExists):
-		message = "User with this email already exists"
+		code = ErrCodeUserExists
 		statusCode = http.StatusConflict
 	case errors.Is(err, entity.ErrInvalidEmail):
-		message = "Invalid email format"
+		code = ErrCodeInvalidEmail
 		statusCode = http.StatusBadRequest
 	case errors.Is(err, entity.ErrWeakPassword):
-		message = "Password must be at least 8 characters"
+		code = ErrCodeWeakPassword
 		statusCode = http.StatusBadRequest
 	case errors.Is(err, entity.ErrEmptyUsername):
-		message = "Username cannot be empty"
+		code = ErrCodeEmptyUsername
+		statusCode = http.StatusBadRequest
+	case errors.Is(err, entity.ErrUsernameTaken):
+		code = ErrCodeUsernameTaken
+		statusCode = http.StatusConflict
+	case errors.Is(err, entity.ErrInvalidAvatarURL):
+		code = ErrCodeInvalidAvatarURL
 		statusCode = http.StatusBadRequest
+	case errors.Is(err, entity.ErrProfileFieldTooLong):
+		code = ErrCodeFieldTooLong
+		statusCode = http.StatusBadRequest
+	case errors.Is(err, entity.ErrUnsupportedImage):
+		code = ErrCodeUnsupportedImage
+		statusCode = http.StatusUnsupportedMediaType
+	case errors.Is(err, entity.ErrInvalidImage):
+		code = ErrCodeInvalidImage
+		statusCode = http.StatusBadRequest
+	case errors.Is(err, entity.ErrInviteRequired):
+		code = ErrCodeInviteRequired
+		statusCode = http.StatusForbidden
+	case errors.Is(err, entity.ErrInvalidInvite):
+		code = ErrCodeInvalidInvite
+		statusCode = http.StatusForbidden
 	default:
-		message = "Internal server error"
+		code = ErrCodeInternal
 		statusCode = http.StatusInternalServerError
 		log.Printf("Internal error: %v", err)
 	}
 
-	w.WriteHeader(statusCode)
-	json.NewEncoder(w).Encode(map[string]string{"error": message})
+	h.jsonError(w, r, code, statusCode)
 }
 
 // JsonResponse отправка JSON-ответа (экспортированный метод)
@@ -168,228 +542,3 @@ func (h *AuthHTTPHandler) JsonResponse(w http.ResponseWriter, data interface{},
 	w.WriteHeader(statusCode)
 	json.NewEncoder(w).Encode(data)
 }
-
-// // internal/delivery/http/auth_handler.go
-// package http
-
-// import (
-// 	"context"
-// 	"encoding/json"
-// 	"errors"
-// 	"log"
-// 	"net/http"
-
-// 	"github.com/go-chi/chi/v5"
-// 	"github.com/kprf42/dolgova/auth_service/internal/entity"
-// 	"github.com/kprf42/dolgova/auth_service/internal/usecase/auth"
-// 	"github.com/kprf42/dolgova/auth_service/internal/usecase/jwt"
-// )
-
-// // AuthHTTPHandler объединяет все HTTP-обработчики аутентификации
-// type AuthHTTPHandler struct {
-// 	authUC *auth.AuthUseCase
-// 	jwtUC  jwt.JWTUseCase
-// }
-
-// // NewAuthHTTPHandler создает новый экземпляр обработчиков
-// func NewAuthHTTPHandler(authUC *auth.AuthUseCase, jwtUC jwt.JWTUseCase) *AuthHTTPHandler {
-// 	return &AuthHTTPHandler{
-// 		authUC: authUC,
-// 		jwtUC:  jwtUC,
-// 	}
-// }
-
-// // RegisterRoutes настраивает маршруты для аутентификации
-// func (h *AuthHTTPHandler) RegisterRoutes(router chi.Router) {
-// 	router.Route("/auth", func(r chi.Router) {
-// 		r.Post("/register", h.Register)
-// 		r.Post("/login", h.Login)
-// 		r.Group(func(r chi.Router) {
-// 			r.Use(h.AuthMiddleware)
-// 		})
-// 	})
-// }
-
-// // RegisterRequest структура запроса регистрации
-// type RegisterRequest struct {
-// 	Username string `json:"username"`
-// 	Email    string `json:"email"`
-// 	Password string `json:"password"`
-// }
-
-// // RegisterResponse структура ответа регистрации
-// type RegisterResponse struct {
-// 	UserID string `json:"user_id"`
-// }
-
-// func (h *AuthHTTPHandler) Register(w http.ResponseWriter, r *http.Request) {
-// 	var req RegisterRequest
-// 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-// 		log.Printf("Decode error: %v", err)
-// 		h.jsonError(w, "Invalid request body", http.StatusBadRequest)
-// 		return
-// 	}
-
-// 	log.Printf("Register attempt: %+v", req)
-
-// 	user, err := h.authUC.Register(r.Context(), req.Username, req.Email, req.Password)
-// 	if err != nil {
-// 		log.Printf("Register error: %v", err) // Добавлено логирование
-// 		h.handleAuthError(w, err)
-// 		return
-// 	}
-
-// 	h.jsonResponse(w, RegisterResponse{UserID: user.ID}, http.StatusCreated)
-// }
-
-// // Добавьте этот новый метод в ваш AuthHTTPHandler
-// func (h *AuthHTTPHandler) jsonError(w http.ResponseWriter, message string, statusCode int) {
-// 	w.Header().Set("Content-Type", "application/json")
-// 	w.WriteHeader(statusCode)
-// 	json.NewEncoder(w).Encode(map[string]string{"error": message})
-// }
-
-// // Register обработчик регистрации пользователя
-// //func (h *AuthHTTPHandler) Register(w http.ResponseWriter, r *http.Request) {
-// // 	var req RegisterRequest
-// // 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-// // 		http.Error(w, "Invalid request body", http.StatusBadRequest)
-// // 		return
-// // 	}
-
-// // 	user, err := h.authUC.Register(r.Context(), req.Username, req.Email, req.Password)
-// // 	if err != nil {
-// // 		h.handleAuthError(w, err)
-// // 		return
-// // 	}
-
-// // 	h.jsonResponse(w, RegisterResponse{UserID: user.ID}, http.StatusCreated)
-// // }
-
-// // LoginRequest структура запроса входа
-// type LoginRequest struct {
-// 	Email    string `json:"email"`
-// 	Password string `json:"password"`
-// }
-
-// // LoginResponse структура ответа входа
-// type LoginResponse struct {
-// 	AccessToken  string `json:"access_token"`
-// 	RefreshToken string `json:"refresh_token"`
-// 	ExpiresIn    int64  `json:"expires_in"`
-// }
-
-// // Login обработчик входа пользователя
-// func (h *AuthHTTPHandler) Login(w http.ResponseWriter, r *http.Request) {
-// 	var req LoginRequest
-// 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-// 		http.Error(w, "Invalid request body", http.StatusBadRequest)
-// 		return
-// 	}
-
-// 	tokens, err := h.authUC.Login(r.Context(), req.Email, req.Password)
-// 	if err != nil {
-// 		http.Error(w, "Invalid credentials", http.StatusUnauthorized)
-// 		return
-// 	}
-
-// 	h.jsonResponse(w, LoginResponse{
-// 		AccessToken:  tokens.AccessToken,
-// 		RefreshToken: tokens.RefreshToken,
-// 		ExpiresIn:    tokens.AtExpires,
-// 	}, http.StatusOK)
-// }
-
-// // AuthMiddleware middleware для аутентификации
-// func (h *AuthHTTPHandler) AuthMiddleware(next http.Handler) http.Handler {
-// 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-// 		token := r.Header.Get("Authorization")
-// 		if token == "" {
-// 			http.Error(w, "Authorization token required", http.StatusUnauthorized)
-// 			return
-// 		}
-
-// 		claims, err := h.jwtUC.ValidateToken(token)
-// 		if err != nil {
-// 			http.Error(w, "Invalid token", http.StatusUnauthorized)
-// 			return
-// 		}
-
-// 		ctx := context.WithValue(r.Context(), "user_id", claims.UserID)
-// 		next.ServeHTTP(w, r.WithContext(ctx))
-// 	})
-// }
-
-// // handleAuthError обработка ошибок аутентификации
-// //
-// //	func (h *AuthHTTPHandler) handleAuthError(w http.ResponseWriter, err error) {
-// //		switch err {
-// //		case entity.ErrUserAlreadyExists:
-// //			http.Error(w, "User already exists", http.StatusConflict)
-// //		case entity.ErrInvalidEmail:
-// //			http.Error(w, "Invalid email format", http.StatusBadRequest)
-// //		case entity.ErrWeakPassword:
-// //			http.Error(w, "Password must be at least 8 characters", http.StatusBadRequest)
-// //		default:
-// //			http.Error(w, "Internal server error", http.StatusInternalServerError)
-// //		}
-// //	}
-// func (h *AuthHTTPHandler) handleAuthError(w http.ResponseWriter, err error) {
-// 	w.Header().Set("Content-Type", "application/json")
-
-// 	var (
-// 		message    string
-// 		statusCode int
-// 	)
-
-// 	switch {
-// 	case errors.Is(err, entity.ErrUserAlreadyExists):
-// 		message = "User with this email already exists"
-// 		statusCode = http.StatusConflict
-// 	case errors.Is(err, entity.ErrInvalidEmail):
-// 		message = "Invalid email format"
-// 		statusCode = http.StatusBadRequest
-// 	case errors.Is(err, entity.ErrWeakPassword):
-// 		message = "Password must be at least 8 characters"
-// 		statusCode = http.StatusBadRequest
-// 	case errors.Is(err, entity.ErrEmptyUsername):
-// 		message = "Username cannot be empty"
-// 		statusCode = http.StatusBadRequest
-// 	default:
-// 		message = "Internal server error"
-// 		statusCode = http.StatusInternalServerError
-// 		log.Printf("Internal error: %v", err) // Логируем внутренние ошибки
-// 	}
-
-// 	w.WriteHeader(statusCode)
-// 	json.NewEncoder(w).Encode(map[string]string{"error": message})
-// }
-
-// // jsonResponse отправка JSON-ответа
-// func (h *AuthHTTPHandler) jsonResponse(w http.ResponseWriter, data interface{}, statusCode int) {
-// 	w.Header().Set("Content-Type", "application/json")
-// 	w.WriteHeader(statusCode)
-// 	json.NewEncoder(w).Encode(data)
-// }
-
-// // Пример инициализации сервера
-// /*
-// func main() {
-// 	// Инициализация репозиториев и use cases
-// 	db := initDB() // Ваша функция инициализации БД
-// 	userRepo := repository.NewUserRepository(db)
-
-// 	authUC := usecase.NewAuthUseCase(userRepo, "your-secret-key", time.Hour, time.Hour*24*7)
-// 	jwtUC := usecase.NewJWTUseCase("your-secret-key", time.Hour, time.Hour*24*7)
-
-// 	// Создание HTTP обработчиков
-// 	authHandler := NewAuthHTTPHandler(authUC, jwtUC)
-
-// 	// Настройка маршрутов
-// 	r := chi.NewRouter()
-// 	authHandler.RegisterRoutes(r)
-
-// 	// Запуск сервера
-// 	http.ListenAndServe(":8080", r)
-// }
-// */
@@ -0,0 +1,238 @@
+package http
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"runtime/debug"
+	"time"
+
+	"github.com/kprf42/dolgova/auth_service/internal/entity"
+	"github.com/kprf42/dolgova/pkg/i18n"
+)
+
+// Коды ошибок API; текст ошибки переводится по Accept-Language
+const (
+	ErrCodeInvalidRequest     = "invalid_request"
+	ErrCodeInvalidCredentials = "invalid_credentials"
+	ErrCodeUserExists         = "user_exists"
+	ErrCodeInvalidEmail       = "invalid_email"
+	ErrCodeWeakPassword       = "weak_password"
+	ErrCodeEmptyUsername      = "empty_username"
+	ErrCodeTokenRequired      = "token_required"
+	ErrCodeInvalidToken       = "invalid_token"
+	ErrCodeTokenRevoked       = "token_revoked"
+	ErrCodeIPBanned           = "ip_banned"
+	ErrCodeForbidden          = "forbidden"
+	ErrCodeUserNotFound       = "user_not_found"
+	ErrCodeImpersonation      = "impersonation_forbidden"
+	ErrCodeOAuthState         = "invalid_oauth_state"
+	ErrCodeOAuthFailed        = "oauth_failed"
+	ErrCodeEmailNotVerified   = "email_not_verified"
+	ErrCodeUnknownProvider    = "unknown_provider"
+	ErrCodeSAMLFailed         = "saml_failed"
+	ErrCodeAccountLocked      = "account_locked"
+	ErrCodeInvalidMagicLink   = "invalid_magic_link"
+	ErrCodeSessionNotFound    = "session_not_found"
+	ErrCodeUsernameTaken      = "username_taken"
+	ErrCodeInvalidAvatarURL   = "invalid_avatar_url"
+	ErrCodeFieldTooLong       = "field_too_long"
+	ErrCodeUnsupportedImage   = "unsupported_image"
+	ErrCodeInvalidImage       = "invalid_image"
+	ErrCodeFileTooLarge       = "file_too_large"
+	ErrCodeWrongPassword      = "wrong_password"
+	ErrCodeDeletionFailed     = "account_deletion_failed"
+	ErrCodeExportNotFound     = "export_not_found"
+	ErrCodeExportNotReady     = "export_not_ready"
+	ErrCodeExportBusy         = "export_busy"
+	ErrCodeAccountSuspended   = "account_suspended"
+	ErrCodeAccountBanned      = "account_banned"
+	ErrCodeInvalidStatus      = "invalid_status"
+	ErrCodeOwnStatus          = "own_status_change"
+	ErrCodeAPIKeyRequired     = "api_key_required"
+	ErrCodeInvalidAPIKey      = "invalid_api_key"
+	ErrCodeAPIKeyScope        = "api_key_scope"
+	ErrCodeAPIKeyNotFound     = "api_key_not_found"
+	ErrCodeAPIKeyRequest      = "invalid_api_key_request"
+	ErrCodeInviteRequired     = "invite_required"
+	ErrCodeInvalidInvite      = "invalid_invite"
+	ErrCodeInviteNotFound     = "invite_not_found"
+	ErrCodeInviteRequest      = "invalid_invite_request"
+	ErrCodeGuestToken         = "guest_token"
+	ErrCodeCSRF               = "csrf_invalid"
+	ErrCodeValidation         = "validation_failed"
+	ErrCodeNotFound           = "not_found"
+	ErrCodeMethodNotAllowed   = "method_not_allowed"
+	ErrCodeInternal           = "internal_error"
+)
+
+// ErrorResponse тело любого ответа с ошибкой auth API. Клиенты ветвятся по стабильному Code,
+// Message переводится по Accept-Language и предназначен для показа пользователю.
+type ErrorResponse struct {
+	Code    string        `json:"code"`
+	Message string        `json:"message"`
+	Details *ErrorDetails `json:"details,omitempty"`
+}
+
+// ErrorDetails дополнительные сведения об ошибке; заполняются только поля, относящиеся к коду
+type ErrorDetails struct {
+	LockedUntil    *time.Time                 `json:"locked_until,omitempty"`    // Окончание блокировки входа (account_locked)
+	SuspendedUntil *time.Time                 `json:"suspended_until,omitempty"` // Окончание приостановки аккаунта (account_suspended)
+	Violations     []entity.PasswordViolation `json:"violations,omitempty"`      // Нарушенные правила парольной политики (weak_password)
+	Fields         []FieldError               `json:"fields,omitempty"`          // Поля запроса, не прошедшие проверку (validation_failed)
+}
+
+var messages = i18n.NewBundle(i18n.EN).
+	Add(i18n.EN, map[string]string{
+		ErrCodeInvalidRequest:     "Invalid request body",
+		ErrCodeInvalidCredentials: "Invalid credentials",
+		ErrCodeUserExists:         "User with this email already exists",
+		ErrCodeInvalidEmail:       "Invalid email format",
+		ErrCodeWeakPassword:       "Password does not meet the password policy",
+		ErrCodeEmptyUsername:      "Username cannot be empty",
+		ErrCodeTokenRequired:      "Authorization token required",
+		ErrCodeInvalidToken:       "Invalid token",
+		ErrCodeTokenRevoked:       "Token has been revoked, please log in again",
+		ErrCodeIPBanned:           "Access from your address is blocked",
+		ErrCodeForbidden:          "Admin role required",
+		ErrCodeUserNotFound:       "User not found",
+		ErrCodeImpersonation:      "This user cannot be impersonated",
+		ErrCodeOAuthState:         "Login session expired, please try again",
+		ErrCodeOAuthFailed:        "External provider login failed",
+		ErrCodeEmailNotVerified:   "Email of the external account is not verified",
+		ErrCodeUnknownProvider:    "Login provider is not supported",
+		ErrCodeSAMLFailed:         "Single sign-on failed, please try again",
+		ErrCodeAccountLocked:      "Too many failed login attempts, try again later",
+		ErrCodeInvalidMagicLink:   "Login link is invalid, expired or already used",
+		ErrCodeSessionNotFound:    "Session not found",
+		ErrCodeUsernameTaken:      "Username is already taken",
+		ErrCodeInvalidAvatarURL:   "Avatar must be an http(s) URL",
+		ErrCodeFieldTooLong:       "Profile field is too long",
+		ErrCodeUnsupportedImage:   "Avatar must be a JPEG, PNG or GIF image",
+		ErrCodeInvalidImage:       "Avatar image is damaged or too large",
+		ErrCodeFileTooLarge:       "File is too large",
+		ErrCodeWrongPassword:      "Password is incorrect",
+		ErrCodeDeletionFailed:     "Account could not be deleted right now, please try again later",
+		ErrCodeExportNotFound:     "Data export not found or expired",
+		ErrCodeExportNotReady:     "Data export is not ready yet",
+		ErrCodeExportBusy:         "Too many data exports in progress, please try again later",
+		ErrCodeAccountSuspended:   "Account is suspended",
+		ErrCodeAccountBanned:      "Account is banned",
+		ErrCodeInvalidStatus:      "Status must be active, suspended or banned; until is allowed only for a future suspension",
+		ErrCodeOwnStatus:          "You cannot change the status of your own account",
+		ErrCodeAPIKeyRequired:     "API key required in the X-API-Key header",
+		ErrCodeInvalidAPIKey:      "API key is invalid, expired or revoked",
+		ErrCodeAPIKeyScope:        "API key does not have the required scope",
+		ErrCodeAPIKeyNotFound:     "API key not found",
+		ErrCodeAPIKeyRequest:      "API key needs a name and known scopes; durations use the 720h format",
+		ErrCodeInviteRequired:     "Registration is by invitation only, an invite code is required",
+		ErrCodeInvalidInvite:      "Invite code is invalid, expired or already used",
+		ErrCodeInviteNotFound:     "Invite not found",
+		ErrCodeInviteRequest:      "Invite max_uses must be between 1 and 1000; durations use the 720h format",
+		ErrCodeGuestToken:         "Guest tokens only give access to the forum, please log in",
+		ErrCodeCSRF:               "CSRF token missing or invalid",
+		ErrCodeValidation:         "Some fields are missing or invalid",
+		ErrCodeNotFound:           "Resource not found",
+		ErrCodeMethodNotAllowed:   "Method not allowed",
+		ErrCodeInternal:           "Internal server error",
+	}).
+	Add(i18n.RU, map[string]string{
+		ErrCodeInvalidRequest:     "Некорректное тело запроса",
+		ErrCodeInvalidCredentials: "Неверный email или пароль",
+		ErrCodeUserExists:         "Пользователь с таким email уже существует",
+		ErrCodeInvalidEmail:       "Некорректный формат email",
+		ErrCodeWeakPassword:       "Пароль не соответствует требованиям к паролю",
+		ErrCodeEmptyUsername:      "Имя пользователя не может быть пустым",
+		ErrCodeTokenRequired:      "Требуется токен авторизации",
+		ErrCodeInvalidToken:       "Недействительный токен",
+		ErrCodeTokenRevoked:       "Токен отозван, войдите заново",
+		ErrCodeIPBanned:           "Доступ с вашего адреса заблокирован",
+		ErrCodeForbidden:          "Требуется роль администратора",
+		ErrCodeUserNotFound:       "Пользователь не найден",
+		ErrCodeImpersonation:      "Имперсонация этого пользователя запрещена",
+		ErrCodeOAuthState:         "Сеанс входа истек, попробуйте еще раз",
+		ErrCodeOAuthFailed:        "Не удалось войти через внешний сервис",
+		ErrCodeEmailNotVerified:   "Email внешнего аккаунта не подтвержден",
+		ErrCodeUnknownProvider:    "Вход через этот сервис не поддерживается",
+		ErrCodeSAMLFailed:         "Не удалось войти через единый вход, попробуйте еще раз",
+		ErrCodeAccountLocked:      "Слишком много неудачных попыток входа, попробуйте позже",
+		ErrCodeInvalidMagicLink:   "Ссылка для входа недействительна, истекла или уже использована",
+		ErrCodeSessionNotFound:    "Сессия не найдена",
+		ErrCodeUsernameTaken:      "Имя пользователя уже занято",
+		ErrCodeInvalidAvatarURL:   "Аватар должен быть http(s) ссылкой",
+		ErrCodeFieldTooLong:       "Поле профиля слишком длинное",
+		ErrCodeUnsupportedImage:   "Аватар должен быть изображением JPEG, PNG или GIF",
+		ErrCodeInvalidImage:       "Изображение аватара повреждено или слишком большое",
+		ErrCodeFileTooLarge:       "Файл слишком большой",
+		ErrCodeWrongPassword:      "Неверный пароль",
+		ErrCodeDeletionFailed:     "Не удалось удалить аккаунт, попробуйте позже",
+		ErrCodeExportNotFound:     "Выгрузка данных не найдена или истекла",
+		ErrCodeExportNotReady:     "Выгрузка данных еще не готова",
+		ErrCodeExportBusy:         "Слишком много выгрузок данных, попробуйте позже",
+		ErrCodeAccountSuspended:   "Аккаунт приостановлен",
+		ErrCodeAccountBanned:      "Аккаунт заблокирован",
+		ErrCodeInvalidStatus:      "Статус должен быть active, suspended или banned; until допустим только для приостановки в будущем",
+		ErrCodeOwnStatus:          "Нельзя менять статус собственного аккаунта",
+		ErrCodeAPIKeyRequired:     "Требуется ключ API в заголовке X-API-Key",
+		ErrCodeInvalidAPIKey:      "Ключ API недействителен, истек или отозван",
+		ErrCodeAPIKeyScope:        "У ключа API нет нужного разрешения",
+		ErrCodeAPIKeyNotFound:     "Ключ API не найден",
+		ErrCodeAPIKeyRequest:      "Для ключа API нужны имя и известные разрешения; длительности в формате 720h",
+		ErrCodeInviteRequired:     "Регистрация только по приглашениям, нужен код приглашения",
+		ErrCodeInvalidInvite:      "Код приглашения недействителен, истек или уже использован",
+		ErrCodeInviteNotFound:     "Приглашение не найдено",
+		ErrCodeInviteRequest:      "max_uses приглашения должен быть от 1 до 1000; длительности в формате 720h",
+		ErrCodeGuestToken:         "Гостевой токен дает доступ только к форуму, войдите в аккаунт",
+		ErrCodeCSRF:               "CSRF токен отсутствует или неверен",
+		ErrCodeValidation:         "Некоторые поля не заполнены или заполнены неверно",
+		ErrCodeNotFound:           "Ресурс не найден",
+		ErrCodeMethodNotAllowed:   "Метод не поддерживается",
+		ErrCodeInternal:           "Внутренняя ошибка сервера",
+	})
+
+// WriteError отправляет ошибку с кодом code. Используется и вне обработчиков:
+// в middleware и для ответов роутера на неизвестные маршруты.
+func WriteError(w http.ResponseWriter, r *http.Request, statusCode int, code string) {
+	writeErrorResponse(w, r, statusCode, ErrorResponse{Code: code})
+}
+
+// writeErrorResponse отправляет ошибку resp; текст заполняется по коду на языке клиента (Accept-Language)
+func writeErrorResponse(w http.ResponseWriter, r *http.Request, statusCode int, resp ErrorResponse) {
+	lang := messages.Lang(r)
+	resp.Message = messages.Message(lang, resp.Code)
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Language", lang)
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// NotFound ответ роутера на неизвестный маршрут
+func NotFound(w http.ResponseWriter, r *http.Request) {
+	WriteError(w, r, http.StatusNotFound, ErrCodeNotFound)
+}
+
+// MethodNotAllowed ответ роутера на неподдерживаемый метод известного маршрута
+func MethodNotAllowed(w http.ResponseWriter, r *http.Request) {
+	WriteError(w, r, http.StatusMethodNotAllowed, ErrCodeMethodNotAllowed)
+}
+
+// CSRFFailed ответ на изменяющий запрос без верного CSRF токена
+func CSRFFailed(w http.ResponseWriter, r *http.Request) {
+	WriteError(w, r, http.StatusForbidden, ErrCodeCSRF)
+}
+
+// Recoverer перехватывает панику обработчика и отвечает internal_error вместо обрыва соединения
+func Recoverer(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			if rec := recover(); rec != nil {
+				if rec == http.ErrAbortHandler {
+					panic(rec)
+				}
+				log.Printf("Panic: %v\n%s", rec, debug.Stack())
+				WriteError(w, r, http.StatusInternalServerError, ErrCodeInternal)
+			}
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,112 @@
+package http
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/kprf42/dolgova/auth_service/internal/entity"
+)
+
+// DataExportResponse состояние выгрузки персональных данных
+type DataExportResponse struct {
+	*entity.DataExport
+	DownloadURL string `json:"download_url,omitempty"` // Только для готовой выгрузки
+}
+
+func newDataExportResponse(export *entity.DataExport) DataExportResponse {
+	resp := DataExportResponse{DataExport: export}
+	if export.Status == entity.DataExportReady {
+		resp.DownloadURL = exportURL(export.ID) + "/download"
+	}
+	return resp
+}
+
+func exportURL(id string) string {
+	return "/auth/me/export/" + id
+}
+
+// RequestExport возвращает текущую выгрузку данных пользователя или запускает новую.
+// Пока архив собирается, отвечает 202 со ссылкой на состояние в Location.
+func (h *AuthHTTPHandler) RequestExport(w http.ResponseWriter, r *http.Request) {
+	userID, _ := r.Context().Value("user_id").(string)
+
+	export, err := h.exports.Request(r.Context(), userID, h.accessToken(r))
+	switch {
+	case errors.Is(err, entity.ErrUserNotFound):
+		h.jsonError(w, r, ErrCodeUserNotFound, http.StatusNotFound)
+		return
+	case errors.Is(err, entity.ErrImpersonationForbidden):
+		h.jsonError(w, r, ErrCodeImpersonation, http.StatusForbidden)
+		return
+	case errors.Is(err, entity.ErrDataExportBusy):
+		h.jsonError(w, r, ErrCodeExportBusy, http.StatusServiceUnavailable)
+		return
+	case err != nil:
+		log.Printf("Request export error: %v", err)
+		h.jsonError(w, r, ErrCodeInternal, http.StatusInternalServerError)
+		return
+	}
+
+	h.exportResponse(w, export)
+}
+
+// ExportStatus возвращает состояние выгрузки текущего пользователя по id
+func (h *AuthHTTPHandler) ExportStatus(w http.ResponseWriter, r *http.Request) {
+	userID, _ := r.Context().Value("user_id").(string)
+
+	export, err := h.exports.Get(r.Context(), userID, chi.URLParam(r, "id"))
+	switch {
+	case errors.Is(err, entity.ErrDataExportNotFound):
+		h.jsonError(w, r, ErrCodeExportNotFound, http.StatusNotFound)
+		return
+	case err != nil:
+		log.Printf("Export status error: %v", err)
+		h.jsonError(w, r, ErrCodeInternal, http.StatusInternalServerError)
+		return
+	}
+
+	h.exportResponse(w, export)
+}
+
+// DownloadExport отдает готовый ZIP архив выгрузки
+func (h *AuthHTTPHandler) DownloadExport(w http.ResponseWriter, r *http.Request) {
+	userID, _ := r.Context().Value("user_id").(string)
+
+	export, f, err := h.exports.Open(r.Context(), userID, chi.URLParam(r, "id"))
+	switch {
+	case errors.Is(err, entity.ErrDataExportNotFound):
+		h.jsonError(w, r, ErrCodeExportNotFound, http.StatusNotFound)
+		return
+	case errors.Is(err, entity.ErrDataExportNotReady):
+		h.jsonError(w, r, ErrCodeExportNotReady, http.StatusConflict)
+		return
+	case err != nil:
+		log.Printf("Download export error: %v", err)
+		h.jsonError(w, r, ErrCodeInternal, http.StatusInternalServerError)
+		return
+	}
+	defer f.Close()
+
+	var modTime time.Time
+	if export.CompletedAt != nil {
+		modTime = *export.CompletedAt
+	}
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", `attachment; filename="data-export-`+export.CreatedAt.Format("20060102")+`.zip"`)
+	w.Header().Set("Cache-Control", "private, no-store")
+	http.ServeContent(w, r, "", modTime, f)
+}
+
+// exportResponse 200 для завершенной выгрузки и 202 для собираемой
+func (h *AuthHTTPHandler) exportResponse(w http.ResponseWriter, export *entity.DataExport) {
+	status := http.StatusOK
+	if export.Status == entity.DataExportPending || export.Status == entity.DataExportRunning {
+		status = http.StatusAccepted
+		w.Header().Set("Retry-After", "5")
+	}
+	w.Header().Set("Location", exportURL(export.ID))
+	h.JsonResponse(w, newDataExportResponse(export), status)
+}
@@ -0,0 +1,92 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/kprf42/dolgova/auth_service/migrations"
+)
+
+// healthCheckTimeout ограничивает проверку одной зависимости
+const healthCheckTimeout = 2 * time.Second
+
+// HealthCheck проверка зависимости, без которой сервис не готов принимать запросы
+type HealthCheck struct {
+	Name  string
+	Check func(ctx context.Context) error
+}
+
+// HealthHandler обработчик проверки состояния сервиса
+type HealthHandler struct {
+	migrator *migrations.Migrator
+	checks   []HealthCheck
+}
+
+// NewHealthHandler создает новый экземпляр обработчика
+func NewHealthHandler(migrator *migrations.Migrator, checks ...HealthCheck) *HealthHandler {
+	return &HealthHandler{migrator: migrator, checks: checks}
+}
+
+// HealthResponse структура ответа проверки состояния
+type HealthResponse struct {
+	Status       string             `json:"status"`
+	Dependencies map[string]string  `json:"dependencies,omitempty"` // Имя зависимости -> "ok" или текст ошибки
+	Migrations   *migrations.Status `json:"migrations,omitempty"`
+	Error        string             `json:"error,omitempty"`
+}
+
+// Live проверка живости (liveness): процесс запущен и обслуживает HTTP.
+// Зависимости не проверяются, чтобы сбой БД не приводил к перезапуску контейнера.
+func (h *HealthHandler) Live(w http.ResponseWriter, r *http.Request) {
+	writeHealth(w, http.StatusOK, HealthResponse{Status: "ok"})
+}
+
+// Ready проверка готовности (readiness): зависимости отвечают, а схема БД на последней версии.
+// При недоступной зависимости, незавершенных (dirty) или непримененных миграциях возвращает 503.
+func (h *HealthHandler) Ready(w http.ResponseWriter, r *http.Request) {
+	response := HealthResponse{Status: "ok"}
+	statusCode := http.StatusOK
+
+	if len(h.checks) > 0 {
+		response.Dependencies = make(map[string]string, len(h.checks))
+	}
+	for _, check := range h.checks {
+		ctx, cancel := context.WithTimeout(r.Context(), healthCheckTimeout)
+		err := check.Check(ctx)
+		cancel()
+		if err != nil {
+			response.Status = "error"
+			response.Dependencies[check.Name] = err.Error()
+			statusCode = http.StatusServiceUnavailable
+			continue
+		}
+		response.Dependencies[check.Name] = "ok"
+	}
+
+	status, err := h.migrator.Status()
+	switch {
+	case err != nil:
+		response.Status = "error"
+		response.Error = err.Error()
+		statusCode = http.StatusServiceUnavailable
+	case status.Dirty || status.Pending > 0:
+		if response.Status == "ok" {
+			response.Status = "degraded"
+		}
+		response.Migrations = status
+		statusCode = http.StatusServiceUnavailable
+	default:
+		response.Migrations = status
+	}
+
+	writeHealth(w, statusCode, response)
+}
+
+func writeHealth(w http.ResponseWriter, statusCode int, response HealthResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(response)
+}
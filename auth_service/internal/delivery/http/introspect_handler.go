@@ -0,0 +1,76 @@
+package http
+
+import (
+	"encoding/json"
+	"log"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// IntrospectRequest запрос интроспекции в JSON; по RFC 7662 поддерживается и form-urlencoded
+type IntrospectRequest struct {
+	Token string `json:"token"`
+}
+
+// IntrospectResponse ответ в стиле RFC 7662. У недействительного токена заполнен только active.
+type IntrospectResponse struct {
+	Active        bool   `json:"active"`
+	UserID        string `json:"user_id,omitempty"`
+	Sub           string `json:"sub,omitempty"` // Совпадает с user_id
+	Role          string `json:"role,omitempty"`
+	TokenType     string `json:"token_type,omitempty"` // access_token или refresh_token
+	SessionID     string `json:"sid,omitempty"`
+	ActingAdminID string `json:"acting_admin_id,omitempty"`
+	Scope         string `json:"scope,omitempty"` // Ограничения гостевого токена через пробел
+	Exp           int64  `json:"exp,omitempty"`   // Окончание действия, unix время
+}
+
+// Introspect сообщает, действителен ли токен, и возвращает его владельца и срок.
+// Доступен только внутренним сервисам с ключом API, чтобы им не нужен был секрет подписи JWT.
+func (h *AuthHTTPHandler) Introspect(w http.ResponseWriter, r *http.Request) {
+	var token string
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "application/json" {
+		var req IntrospectRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.jsonError(w, r, ErrCodeInvalidRequest, http.StatusBadRequest)
+			return
+		}
+		token = req.Token
+	} else {
+		if err := r.ParseForm(); err != nil {
+			h.jsonError(w, r, ErrCodeInvalidRequest, http.StatusBadRequest)
+			return
+		}
+		token = r.PostForm.Get("token")
+	}
+	if token == "" {
+		h.jsonError(w, r, ErrCodeInvalidRequest, http.StatusBadRequest)
+		return
+	}
+
+	info, err := h.authUC.Introspect(r.Context(), token)
+	if err != nil {
+		log.Printf("Introspect error: %v", err)
+		h.jsonError(w, r, ErrCodeInternal, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	if info == nil {
+		h.JsonResponse(w, IntrospectResponse{Active: false}, http.StatusOK)
+		return
+	}
+	h.JsonResponse(w, IntrospectResponse{
+		Active:        true,
+		UserID:        info.UserID,
+		Sub:           info.UserID,
+		Role:          info.Role,
+		TokenType:     info.TokenType,
+		SessionID:     info.SessionID,
+		ActingAdminID: info.ActingAdminID,
+		Scope:         strings.Join(info.Scope, " "),
+		Exp:           info.ExpiresAt.Unix(),
+	}, http.StatusOK)
+}
@@ -0,0 +1,93 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/kprf42/dolgova/auth_service/internal/entity"
+	"github.com/kprf42/dolgova/auth_service/internal/usecase/auth"
+)
+
+// CreateInviteRequest выпуск приглашения
+type CreateInviteRequest struct {
+	MaxUses   int    `json:"max_uses"`   // Сколько регистраций допускает код; 0 - одна
+	ExpiresIn string `json:"expires_in"` // Срок действия в формате time.Duration, например 168h; пусто - бессрочно
+}
+
+// InviteResponse приглашение; код возвращается только при выпуске
+type InviteResponse struct {
+	*entity.Invite
+	Code string `json:"code,omitempty"`
+}
+
+type InvitesResponse struct {
+	Invites []*entity.Invite `json:"invites"`
+}
+
+// CreateInvite выпускает код приглашения
+func (h *AuthHTTPHandler) CreateInvite(w http.ResponseWriter, r *http.Request) {
+	var req CreateInviteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.jsonError(w, r, ErrCodeInvalidRequest, http.StatusBadRequest)
+		return
+	}
+	ttl, ok := parseOptionalDuration(req.ExpiresIn)
+	if !ok {
+		h.jsonError(w, r, ErrCodeInviteRequest, http.StatusBadRequest)
+		return
+	}
+
+	adminID, _ := r.Context().Value("user_id").(string)
+	invite, code, err := h.invites.Issue(r.Context(), adminID, auth.InviteRequest{
+		MaxUses: req.MaxUses,
+		TTL:     ttl,
+	})
+	if err != nil {
+		h.inviteError(w, r, err)
+		return
+	}
+
+	h.JsonResponse(w, InviteResponse{Invite: invite, Code: code}, http.StatusCreated)
+}
+
+// ListInvites возвращает приглашения без кодов
+func (h *AuthHTTPHandler) ListInvites(w http.ResponseWriter, r *http.Request) {
+	adminID, _ := r.Context().Value("user_id").(string)
+	invites, err := h.invites.List(r.Context(), adminID)
+	if err != nil {
+		h.inviteError(w, r, err)
+		return
+	}
+
+	h.JsonResponse(w, InvitesResponse{Invites: invites}, http.StatusOK)
+}
+
+// RevokeInvite отзывает приглашение
+func (h *AuthHTTPHandler) RevokeInvite(w http.ResponseWriter, r *http.Request) {
+	adminID, _ := r.Context().Value("user_id").(string)
+	if err := h.invites.Revoke(r.Context(), adminID, chi.URLParam(r, "id")); err != nil {
+		h.inviteError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *AuthHTTPHandler) inviteError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, entity.ErrInvalidInviteRequest):
+		h.jsonError(w, r, ErrCodeInviteRequest, http.StatusBadRequest)
+	case errors.Is(err, entity.ErrInviteNotFound):
+		h.jsonError(w, r, ErrCodeInviteNotFound, http.StatusNotFound)
+	case errors.Is(err, entity.ErrNotAdmin):
+		h.jsonError(w, r, ErrCodeForbidden, http.StatusForbidden)
+	case errors.Is(err, entity.ErrImpersonationForbidden):
+		h.jsonError(w, r, ErrCodeImpersonation, http.StatusForbidden)
+	default:
+		log.Printf("Invite error: %v", err)
+		h.jsonError(w, r, ErrCodeInternal, http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,62 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/kprf42/dolgova/auth_service/internal/entity"
+)
+
+// MagicLinkRequest запрос ссылки для входа без пароля
+type MagicLinkRequest struct {
+	Email string `json:"email"`
+}
+
+// RequestMagicLink отправляет ссылку для входа на email.
+// Ответ не зависит от того, зарегистрирован ли адрес.
+func (h *AuthHTTPHandler) RequestMagicLink(w http.ResponseWriter, r *http.Request) {
+	var req MagicLinkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.jsonError(w, r, ErrCodeInvalidRequest, http.StatusBadRequest)
+		return
+	}
+
+	if err := h.magicLinks.Send(r.Context(), req.Email); err != nil {
+		h.handleAuthError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// MagicLinkCallback обменивает токен из ссылки на токены сервиса
+func (h *AuthHTTPHandler) MagicLinkCallback(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		h.jsonError(w, r, ErrCodeTokenRequired, http.StatusBadRequest)
+		return
+	}
+
+	tokens, err := h.magicLinks.Exchange(r.Context(), token, clientInfo(r))
+	var locked *entity.AccountLockedError
+	var suspended *entity.AccountSuspendedError
+	switch {
+	case errors.As(err, &locked):
+		h.lockedError(w, r, locked.Until)
+		return
+	case errors.As(err, &suspended):
+		h.suspendedError(w, r, suspended)
+		return
+	case errors.Is(err, entity.ErrInvalidToken):
+		h.jsonError(w, r, ErrCodeInvalidMagicLink, http.StatusUnauthorized)
+		return
+	case err != nil:
+		log.Printf("Magic link login error: %v", err)
+		h.jsonError(w, r, ErrCodeInternal, http.StatusInternalServerError)
+		return
+	}
+
+	h.writeTokens(w, r, tokens)
+}
@@ -0,0 +1,109 @@
+package http
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/kprf42/dolgova/auth_service/internal/entity"
+	"github.com/kprf42/dolgova/auth_service/internal/usecase/auth"
+)
+
+// oauthStateCookie cookie со значением state, которое провайдер вернет в callback
+const oauthStateCookie = "oauth_state"
+
+// oauthStateTTL время, за которое пользователь должен пройти страницу согласия
+const oauthStateTTL = 600 // секунд
+
+// OAuthLogin перенаправляет на страницу согласия провайдера
+func (h *AuthHTTPHandler) OAuthLogin(w http.ResponseWriter, r *http.Request) {
+	provider, ok := h.oauth.Get(chi.URLParam(r, "provider"))
+	if !ok {
+		h.jsonError(w, r, ErrCodeUnknownProvider, http.StatusNotFound)
+		return
+	}
+
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		h.jsonError(w, r, ErrCodeInternal, http.StatusInternalServerError)
+		return
+	}
+	state := hex.EncodeToString(b)
+
+	h.setOAuthState(w, state, oauthStateTTL)
+	http.Redirect(w, r, provider.AuthCodeURL(state), http.StatusFound)
+}
+
+// OAuthCallback обменивает код авторизации провайдера на токены сервиса
+func (h *AuthHTTPHandler) OAuthCallback(w http.ResponseWriter, r *http.Request) {
+	provider, ok := h.oauth.Get(chi.URLParam(r, "provider"))
+	if !ok {
+		h.jsonError(w, r, ErrCodeUnknownProvider, http.StatusNotFound)
+		return
+	}
+
+	// state защищает от подстановки чужого кода (login CSRF)
+	cookie, err := r.Cookie(oauthStateCookie)
+	state := r.URL.Query().Get("state")
+	if err != nil || state == "" || subtle.ConstantTimeCompare([]byte(cookie.Value), []byte(state)) != 1 {
+		h.jsonError(w, r, ErrCodeOAuthState, http.StatusBadRequest)
+		return
+	}
+	h.setOAuthState(w, "", -1)
+
+	code := r.URL.Query().Get("code")
+	if code == "" {
+		// Пользователь отказался на странице согласия (параметр error)
+		h.jsonError(w, r, ErrCodeOAuthFailed, http.StatusUnauthorized)
+		return
+	}
+
+	profile, err := provider.Exchange(r.Context(), code)
+	if err != nil {
+		log.Printf("OAuth exchange error (%s): %v", provider.Name(), err)
+		h.jsonError(w, r, ErrCodeOAuthFailed, http.StatusUnauthorized)
+		return
+	}
+
+	h.externalLogin(w, r, provider.Name(), profile)
+}
+
+// externalLogin входит по профилю внешнего провайдера (OAuth или SAML) и выдает токены
+func (h *AuthHTTPHandler) externalLogin(w http.ResponseWriter, r *http.Request, provider string, profile *auth.ExternalProfile) {
+	tokens, err := h.authUC.LoginWithProvider(r.Context(), provider, profile, clientInfo(r))
+	var suspended *entity.AccountSuspendedError
+	switch {
+	case errors.Is(err, entity.ErrEmailNotVerified):
+		h.jsonError(w, r, ErrCodeEmailNotVerified, http.StatusForbidden)
+		return
+	case errors.Is(err, entity.ErrInviteRequired):
+		h.jsonError(w, r, ErrCodeInviteRequired, http.StatusForbidden)
+		return
+	case errors.As(err, &suspended):
+		h.suspendedError(w, r, suspended)
+		return
+	case err != nil:
+		log.Printf("External login error (%s): %v", provider, err)
+		h.jsonError(w, r, ErrCodeInternal, http.StatusInternalServerError)
+		return
+	}
+
+	h.writeTokens(w, r, tokens)
+}
+
+// setOAuthState выставляет (maxAge > 0) или удаляет (maxAge < 0) cookie со state
+func (h *AuthHTTPHandler) setOAuthState(w http.ResponseWriter, state string, maxAge int) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     oauthStateCookie,
+		Value:    state,
+		Path:     "/auth/oauth",
+		MaxAge:   maxAge,
+		HttpOnly: true,
+		Secure:   h.cookies.Secure,
+		SameSite: http.SameSiteLaxMode, // Lax, чтобы cookie пришла при переходе обратно от провайдера
+	})
+}
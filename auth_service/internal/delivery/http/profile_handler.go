@@ -0,0 +1,160 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/kprf42/dolgova/auth_service/internal/entity"
+	"github.com/kprf42/dolgova/auth_service/internal/usecase/auth"
+)
+
+// ProfileResponse профиль текущего пользователя
+type ProfileResponse struct {
+	ID          string `json:"id"`
+	Username    string `json:"username"`
+	Email       string `json:"email"`
+	Role        string `json:"role"`
+	DisplayName string `json:"display_name"`
+	Bio         string `json:"bio"`
+	AvatarURL   string `json:"avatar_url"`
+}
+
+// UpdateProfileRequest изменение профиля; отсутствующие поля не меняются
+type UpdateProfileRequest struct {
+	Username    *string `json:"username"`
+	DisplayName *string `json:"display_name"`
+	Bio         *string `json:"bio"`
+	AvatarURL   *string `json:"avatar_url"`
+}
+
+func newProfileResponse(user *entity.User) ProfileResponse {
+	return ProfileResponse{
+		ID:          user.ID,
+		Username:    user.Username,
+		Email:       user.Email,
+		Role:        user.Role,
+		DisplayName: user.DisplayName,
+		Bio:         user.Bio,
+		AvatarURL:   user.AvatarURL,
+	}
+}
+
+// GetProfile возвращает профиль текущего пользователя
+func (h *AuthHTTPHandler) GetProfile(w http.ResponseWriter, r *http.Request) {
+	userID, _ := r.Context().Value("user_id").(string)
+
+	user, err := h.authUC.GetProfile(r.Context(), userID)
+	if errors.Is(err, entity.ErrUserNotFound) {
+		h.jsonError(w, r, ErrCodeUserNotFound, http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		h.handleAuthError(w, r, err)
+		return
+	}
+
+	h.JsonResponse(w, newProfileResponse(user), http.StatusOK)
+}
+
+// UpdateProfile меняет имя пользователя и поля профиля текущего пользователя
+func (h *AuthHTTPHandler) UpdateProfile(w http.ResponseWriter, r *http.Request) {
+	var req UpdateProfileRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.jsonError(w, r, ErrCodeInvalidRequest, http.StatusBadRequest)
+		return
+	}
+
+	userID, _ := r.Context().Value("user_id").(string)
+	user, err := h.authUC.UpdateProfile(r.Context(), userID, auth.ProfileUpdate{
+		Username:    req.Username,
+		DisplayName: req.DisplayName,
+		Bio:         req.Bio,
+		AvatarURL:   req.AvatarURL,
+	})
+	if errors.Is(err, entity.ErrUserNotFound) {
+		h.jsonError(w, r, ErrCodeUserNotFound, http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		h.handleAuthError(w, r, err)
+		return
+	}
+
+	h.JsonResponse(w, newProfileResponse(user), http.StatusOK)
+}
+
+// MaxAvatarSize максимальный размер загружаемого аватара
+const MaxAvatarSize = 5 << 20
+
+// UploadAvatar принимает изображение аватара (multipart, поле avatar) и возвращает обновленный профиль
+func (h *AuthHTTPHandler) UploadAvatar(w http.ResponseWriter, r *http.Request) {
+	// Запас на заголовки multipart сверх размера файла
+	r.Body = http.MaxBytesReader(w, r.Body, MaxAvatarSize+1<<20)
+	file, header, err := r.FormFile("avatar")
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) || (err == nil && header.Size > MaxAvatarSize) {
+		if file != nil {
+			file.Close()
+		}
+		h.jsonError(w, r, ErrCodeFileTooLarge, http.StatusRequestEntityTooLarge)
+		return
+	}
+	if err != nil {
+		h.jsonError(w, r, ErrCodeInvalidRequest, http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	userID, _ := r.Context().Value("user_id").(string)
+	user, err := h.avatars.Upload(r.Context(), userID, file)
+	if errors.Is(err, entity.ErrUserNotFound) {
+		h.jsonError(w, r, ErrCodeUserNotFound, http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		h.handleAuthError(w, r, err)
+		return
+	}
+
+	h.JsonResponse(w, newProfileResponse(user), http.StatusOK)
+}
+
+// DeleteAccountRequest подтверждение удаления аккаунта
+type DeleteAccountRequest struct {
+	Password string `json:"password"` // Не нужен, если пароль не задан и вход только через внешний аккаунт
+}
+
+// DeleteAccount удаляет аккаунт текущего пользователя и обезличивает его контент на форуме
+func (h *AuthHTTPHandler) DeleteAccount(w http.ResponseWriter, r *http.Request) {
+	var req DeleteAccountRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.jsonError(w, r, ErrCodeInvalidRequest, http.StatusBadRequest)
+			return
+		}
+	}
+
+	userID, _ := r.Context().Value("user_id").(string)
+	err := h.deletion.Delete(r.Context(), userID, req.Password, h.accessToken(r))
+	switch {
+	case errors.Is(err, entity.ErrUserNotFound):
+		h.jsonError(w, r, ErrCodeUserNotFound, http.StatusNotFound)
+		return
+	case errors.Is(err, entity.ErrWrongPassword):
+		h.jsonError(w, r, ErrCodeWrongPassword, http.StatusForbidden)
+		return
+	case errors.Is(err, entity.ErrImpersonationForbidden):
+		h.jsonError(w, r, ErrCodeImpersonation, http.StatusForbidden)
+		return
+	case errors.Is(err, entity.ErrContentAnonymization):
+		h.jsonError(w, r, ErrCodeDeletionFailed, http.StatusBadGateway)
+		return
+	case err != nil:
+		h.handleAuthError(w, r, err)
+		return
+	}
+
+	h.clearAccessCookie(w)
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,103 @@
+package http
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// samlRequestCookie cookie с ID запроса аутентификации; ответ IdP должен ссылаться на него в InResponseTo
+const samlRequestCookie = "saml_request"
+
+// maxSAMLResponseSize ограничение тела POST с ответом IdP
+const maxSAMLResponseSize = 1 << 20
+
+// SAMLMetadata отдает метаданные SP для регистрации в IdP
+func (h *AuthHTTPHandler) SAMLMetadata(w http.ResponseWriter, r *http.Request) {
+	provider, ok := h.saml.Get(chi.URLParam(r, "idp"))
+	if !ok {
+		h.jsonError(w, r, ErrCodeUnknownProvider, http.StatusNotFound)
+		return
+	}
+
+	metadata, err := provider.Metadata()
+	if err != nil {
+		log.Printf("SAML metadata error (%s): %v", provider.Name(), err)
+		h.jsonError(w, r, ErrCodeInternal, http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/samlmetadata+xml")
+	w.Write(metadata)
+}
+
+// SAMLLogin перенаправляет на страницу входа IdP (HTTP-Redirect binding)
+func (h *AuthHTTPHandler) SAMLLogin(w http.ResponseWriter, r *http.Request) {
+	provider, ok := h.saml.Get(chi.URLParam(r, "idp"))
+	if !ok {
+		h.jsonError(w, r, ErrCodeUnknownProvider, http.StatusNotFound)
+		return
+	}
+
+	redirect, requestID, err := provider.AuthnRequest("")
+	if err != nil {
+		log.Printf("SAML authn request error (%s): %v", provider.Name(), err)
+		h.jsonError(w, r, ErrCodeInternal, http.StatusInternalServerError)
+		return
+	}
+
+	h.setSAMLRequest(w, provider.Name(), requestID, oauthStateTTL)
+	http.Redirect(w, r, redirect, http.StatusFound)
+}
+
+// SAMLACS принимает ответ IdP (HTTP-POST binding), проверяет утверждение и выдает токены сервиса
+func (h *AuthHTTPHandler) SAMLACS(w http.ResponseWriter, r *http.Request) {
+	provider, ok := h.saml.Get(chi.URLParam(r, "idp"))
+	if !ok {
+		h.jsonError(w, r, ErrCodeUnknownProvider, http.StatusNotFound)
+		return
+	}
+
+	// Ответ принимается только в браузере, который начал вход: это защищает от подстановки
+	// чужого утверждения (login CSRF) и от повторной отправки перехваченного ответа
+	cookie, err := r.Cookie(samlRequestCookie)
+	if err != nil || cookie.Value == "" {
+		h.jsonError(w, r, ErrCodeOAuthState, http.StatusBadRequest)
+		return
+	}
+	h.setSAMLRequest(w, provider.Name(), "", -1)
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxSAMLResponseSize)
+	if err := r.ParseForm(); err != nil {
+		h.jsonError(w, r, ErrCodeInvalidRequest, http.StatusBadRequest)
+		return
+	}
+
+	profile, err := provider.ParseResponse(r.PostForm.Get("SAMLResponse"), cookie.Value)
+	if err != nil {
+		log.Printf("SAML response error (%s): %v", provider.Name(), err)
+		h.jsonError(w, r, ErrCodeSAMLFailed, http.StatusUnauthorized)
+		return
+	}
+
+	h.externalLogin(w, r, provider.LinkedName(), profile)
+}
+
+// setSAMLRequest выставляет (maxAge > 0) или удаляет (maxAge < 0) cookie с ID запроса.
+// IdP возвращает пользователя POST запросом с другого сайта, поэтому по HTTPS cookie
+// выдается с SameSite=None; без HTTPS браузеры такую cookie не принимают.
+func (h *AuthHTTPHandler) setSAMLRequest(w http.ResponseWriter, idp, requestID string, maxAge int) {
+	sameSite := http.SameSiteDefaultMode
+	if h.cookies.Secure {
+		sameSite = http.SameSiteNoneMode
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     samlRequestCookie,
+		Value:    requestID,
+		Path:     "/auth/saml/" + idp,
+		MaxAge:   maxAge,
+		HttpOnly: true,
+		Secure:   h.cookies.Secure,
+		SameSite: sameSite,
+	})
+}
@@ -0,0 +1,106 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/kprf42/dolgova/auth_service/internal/entity"
+)
+
+// SessionResponse активная сессия пользователя
+type SessionResponse struct {
+	*entity.Session
+	Current bool `json:"current"` // Сессия, которой принадлежит токен запроса
+}
+
+type SessionsResponse struct {
+	Sessions []SessionResponse `json:"sessions"`
+}
+
+type RevokeSessionsResponse struct {
+	Revoked int `json:"revoked"`
+}
+
+// ListSessions возвращает активные сессии текущего пользователя
+func (h *AuthHTTPHandler) ListSessions(w http.ResponseWriter, r *http.Request) {
+	userID, _ := r.Context().Value("user_id").(string)
+	currentID, _ := r.Context().Value("session_id").(string)
+
+	sessions, err := h.authUC.ListSessions(r.Context(), userID)
+	if err != nil {
+		log.Printf("List sessions error: %v", err)
+		h.jsonError(w, r, ErrCodeInternal, http.StatusInternalServerError)
+		return
+	}
+
+	resp := make([]SessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		resp = append(resp, SessionResponse{Session: session, Current: session.ID == currentID})
+	}
+	h.JsonResponse(w, SessionsResponse{Sessions: resp}, http.StatusOK)
+}
+
+// RevokeSession завершает сессию текущего пользователя по id
+func (h *AuthHTTPHandler) RevokeSession(w http.ResponseWriter, r *http.Request) {
+	userID, _ := r.Context().Value("user_id").(string)
+
+	err := h.authUC.RevokeSession(r.Context(), userID, chi.URLParam(r, "id"))
+	switch {
+	case errors.Is(err, entity.ErrSessionNotFound):
+		h.jsonError(w, r, ErrCodeSessionNotFound, http.StatusNotFound)
+		return
+	case err != nil:
+		log.Printf("Revoke session error: %v", err)
+		h.jsonError(w, r, ErrCodeInternal, http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RevokeOtherSessions завершает все сессии текущего пользователя, кроме сессии запроса
+func (h *AuthHTTPHandler) RevokeOtherSessions(w http.ResponseWriter, r *http.Request) {
+	userID, _ := r.Context().Value("user_id").(string)
+	currentID, _ := r.Context().Value("session_id").(string)
+
+	revoked, err := h.authUC.RevokeOtherSessions(r.Context(), userID, currentID)
+	if err != nil {
+		log.Printf("Revoke sessions error: %v", err)
+		h.jsonError(w, r, ErrCodeInternal, http.StatusInternalServerError)
+		return
+	}
+
+	h.JsonResponse(w, RevokeSessionsResponse{Revoked: revoked}, http.StatusOK)
+}
+
+// RevokeByLinkRequest токен из письма о входе с нового устройства
+type RevokeByLinkRequest struct {
+	Token string `json:"token"`
+}
+
+// RevokeSessionByLink завершает сессию по ссылке из письма о входе с нового устройства.
+// Токен ссылки передается в теле POST запроса страницей фронтенда: почтовые сканеры
+// открывают ссылки из писем, и GET запрос завершал бы сессию без ведома пользователя.
+func (h *AuthHTTPHandler) RevokeSessionByLink(w http.ResponseWriter, r *http.Request) {
+	var req RevokeByLinkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Token == "" {
+		h.jsonError(w, r, ErrCodeInvalidRequest, http.StatusBadRequest)
+		return
+	}
+
+	err := h.devices.RevokeByLink(r.Context(), req.Token)
+	switch {
+	case errors.Is(err, entity.ErrInvalidToken):
+		h.jsonError(w, r, ErrCodeInvalidToken, http.StatusBadRequest)
+		return
+	case err != nil:
+		log.Printf("Revoke session by link error: %v", err)
+		h.jsonError(w, r, ErrCodeInternal, http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
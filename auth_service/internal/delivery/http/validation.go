@@ -0,0 +1,62 @@
+package http
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// requests проверяет запросы по тегам validate. В ошибках поля называются по тегу json,
+// чтобы клиент мог подсветить поле формы.
+var requests = newRequestValidator()
+
+func newRequestValidator() *validator.Validate {
+	v := validator.New(validator.WithRequiredStructEnabled())
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+	return v
+}
+
+// FieldError нарушенное правило одного поля запроса
+type FieldError struct {
+	Field string `json:"field"`           // Имя поля в JSON
+	Rule  string `json:"rule"`            // Правило: required, email, max, ...
+	Param string `json:"param,omitempty"` // Параметр правила, например предельная длина
+}
+
+// validateRequest проверяет запрос (указатель на структуру) и при нарушениях отвечает validation_failed со списком полей.
+// Возвращает false, если ответ уже отправлен.
+func (h *AuthHTTPHandler) validateRequest(w http.ResponseWriter, r *http.Request, req any) bool {
+	err := requests.Struct(req)
+	if err == nil {
+		return true
+	}
+
+	var invalid validator.ValidationErrors
+	if !errors.As(err, &invalid) {
+		log.Printf("Validation error: %v", err)
+		h.jsonError(w, r, ErrCodeInternal, http.StatusInternalServerError)
+		return false
+	}
+
+	fields := make([]FieldError, 0, len(invalid))
+	for _, fe := range invalid {
+		param := fe.Param()
+		// Параметр правил вида required_without - имя поля Go; клиенту отдается имя из JSON
+		if f, ok := reflect.TypeOf(req).Elem().FieldByName(param); ok {
+			param, _, _ = strings.Cut(f.Tag.Get("json"), ",")
+		}
+		fields = append(fields, FieldError{Field: fe.Field(), Rule: fe.Tag(), Param: param})
+	}
+	h.errorResponse(w, r, http.StatusBadRequest, ErrCodeValidation, &ErrorDetails{Fields: fields})
+	return false
+}
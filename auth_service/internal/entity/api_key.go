@@ -0,0 +1,50 @@
+package entity
+
+import (
+	"errors"
+	"slices"
+	"time"
+)
+
+// Разрешения ключей внутренних сервисов
+const (
+	ScopeTokensValidate = "tokens:validate" // Проверка и интроспекция токенов пользователей
+	ScopeUsersRead      = "users:read"      // Чтение публичных данных пользователей
+)
+
+// APIKeyScopes все известные разрешения
+var APIKeyScopes = []string{ScopeTokensValidate, ScopeUsersRead}
+
+var (
+	ErrAPIKeyNotFound = errors.New("api key not found")
+	// ErrInvalidAPIKey ключ не существует, отозван, истек или не совпадает секрет
+	ErrInvalidAPIKey = errors.New("invalid api key")
+	// ErrAPIKeyScope у ключа нет нужного разрешения
+	ErrAPIKeyScope = errors.New("api key scope required")
+	// ErrInvalidAPIKeyRequest пустое имя или неизвестное разрешение
+	ErrInvalidAPIKeyRequest = errors.New("invalid api key request")
+)
+
+// APIKey ключ, которым внутренний сервис аутентифицируется вместо JWT пользователя
+type APIKey struct {
+	ID          string     `json:"id"`
+	Name        string     `json:"name"`
+	SecretHash  string     `json:"-"`
+	Scopes      []string   `json:"scopes"`
+	CreatedBy   string     `json:"created_by"`
+	CreatedAt   time.Time  `json:"created_at"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	RevokedAt   *time.Time `json:"revoked_at,omitempty"`
+	LastUsedAt  *time.Time `json:"last_used_at,omitempty"`
+	RotatedFrom string     `json:"rotated_from,omitempty"`
+}
+
+// Active ключ не отозван и не истек на момент now
+func (k *APIKey) Active(now time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || now.Before(*k.ExpiresAt))
+}
+
+// HasScope есть ли у ключа разрешение scope
+func (k *APIKey) HasScope(scope string) bool {
+	return slices.Contains(k.Scopes, scope)
+}
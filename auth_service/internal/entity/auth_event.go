@@ -0,0 +1,29 @@
+package entity
+
+import "time"
+
+// Типы событий аутентификации
+const (
+	AuthEventLoginSuccess   = "login_success"
+	AuthEventLoginFailure   = "login_failure"
+	AuthEventTokenRefresh   = "token_refresh"
+	AuthEventPasswordChange = "password_change" // Зарезервирован: сценария смены пароля пока нет
+)
+
+// Способы входа (AuthEvent.Method); для внешних провайдеров - имя провайдера
+const (
+	AuthMethodPassword  = "password"
+	AuthMethodMagicLink = "magic_link"
+)
+
+// AuthEvent событие аутентификации из истории входов пользователя
+type AuthEvent struct {
+	ID        int64     `json:"id"`
+	UserID    string    `json:"user_id,omitempty"`
+	Type      string    `json:"type"`
+	Method    string    `json:"method,omitempty"`
+	IP        string    `json:"ip,omitempty"`
+	UserAgent string    `json:"user_agent,omitempty"`
+	Details   string    `json:"details,omitempty"` // Причина неудачи и т.п.
+	CreatedAt time.Time `json:"created_at"`
+}
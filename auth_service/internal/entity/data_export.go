@@ -0,0 +1,72 @@
+package entity
+
+import (
+	"errors"
+	"time"
+)
+
+// Состояния выгрузки персональных данных
+const (
+	DataExportPending = "pending"
+	DataExportRunning = "running"
+	DataExportReady   = "ready"
+	DataExportFailed  = "failed"
+)
+
+var (
+	ErrDataExportNotFound = errors.New("data export not found")
+	// ErrDataExportNotReady архив еще собирается, не удался или уже удален
+	ErrDataExportNotReady = errors.New("data export not ready")
+	// ErrDataExportBusy очередь выгрузок переполнена
+	ErrDataExportBusy = errors.New("data export queue is full")
+)
+
+// DataExport выгрузка персональных данных пользователя
+type DataExport struct {
+	ID          string     `json:"id"`
+	UserID      string     `json:"user_id"`
+	Status      string     `json:"status"`
+	Error       string     `json:"error,omitempty"`
+	Size        int64      `json:"size"`
+	CreatedAt   time.Time  `json:"created_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	ExpiresAt   time.Time  `json:"expires_at"` // После этого момента архив удаляется
+}
+
+// Active сообщает, что выгрузка собирается или готова и новую запускать не нужно
+func (e *DataExport) Active(now time.Time) bool {
+	return e.Status != DataExportFailed && now.Before(e.ExpiresAt)
+}
+
+// ForumContent контент пользователя на форуме для выгрузки персональных данных
+type ForumContent struct {
+	Posts        []ForumPost        `json:"posts"`
+	Comments     []ForumComment     `json:"comments"`
+	ChatMessages []ForumChatMessage `json:"chat_messages"`
+}
+
+type ForumPost struct {
+	ID         string    `json:"id"`
+	ForumID    string    `json:"forum_id"`
+	Title      string    `json:"title"`
+	Content    string    `json:"content"`
+	CategoryID string    `json:"category_id,omitempty"`
+	Status     string    `json:"status"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type ForumComment struct {
+	ID        string    `json:"id"`
+	ForumID   string    `json:"forum_id"`
+	PostID    string    `json:"post_id"`
+	Content   string    `json:"content"`
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type ForumChatMessage struct {
+	ID        string    `json:"id"`
+	ForumID   string    `json:"forum_id"`
+	Text      string    `json:"text"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,32 @@
+package entity
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
+
+// KnownDevice устройство, с которого пользователь уже входил
+type KnownDevice struct {
+	UserID      string    `json:"user_id"`
+	Fingerprint string    `json:"fingerprint"`
+	UserAgent   string    `json:"user_agent"`
+	IP          string    `json:"ip"`
+	FirstSeenAt time.Time `json:"first_seen_at"`
+	LastSeenAt  time.Time `json:"last_seen_at"`
+}
+
+// NewDeviceAlert письмо о входе с нового устройства
+type NewDeviceAlert struct {
+	Username   string
+	UserAgent  string
+	IP         string
+	Time       time.Time
+	RevokeLink string // Ссылка, по которой можно завершить сессию, если входил не пользователь
+}
+
+// Fingerprint отпечаток устройства клиента: SHA-256 от User-Agent и IP
+func (c ClientInfo) Fingerprint() string {
+	sum := sha256.Sum256([]byte(c.UserAgent + "\n" + c.IP))
+	return hex.EncodeToString(sum[:])
+}
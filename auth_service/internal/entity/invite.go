@@ -0,0 +1,29 @@
+package entity
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	// ErrInviteRequired регистрация только по приглашениям, а код не указан
+	ErrInviteRequired = errors.New("invite code required")
+	// ErrInvalidInvite код не существует, отозван, истек или исчерпан
+	ErrInvalidInvite = errors.New("invalid invite code")
+	// ErrInvalidInviteRequest некорректное число использований или срок действия
+	ErrInvalidInviteRequest = errors.New("invalid invite request")
+	ErrInviteNotFound       = errors.New("invite not found")
+)
+
+// Invite приглашение для регистрации в режиме только по приглашениям.
+// Код приглашения хранится только в виде хеша и показывается один раз при выпуске.
+type Invite struct {
+	ID        string     `json:"id"`
+	CodeHash  string     `json:"-"`
+	MaxUses   int        `json:"max_uses"`
+	Uses      int        `json:"uses"`
+	CreatedBy string     `json:"created_by"`
+	CreatedAt time.Time  `json:"created_at"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
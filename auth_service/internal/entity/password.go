@@ -0,0 +1,53 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Правила парольной политики (PasswordViolation.Rule)
+const (
+	PasswordRuleMinLength   = "min_length"
+	PasswordRuleMaxLength   = "max_length"
+	PasswordRuleCharClasses = "char_classes"
+	PasswordRuleCommon      = "common"
+)
+
+// PasswordViolation нарушение одного правила парольной политики
+type PasswordViolation struct {
+	Rule  string `json:"rule"`
+	Limit int    `json:"limit,omitempty"` // Порог правила: длина или число классов символов
+}
+
+func (v PasswordViolation) String() string {
+	switch v.Rule {
+	case PasswordRuleMinLength:
+		return fmt.Sprintf("password must be at least %d characters", v.Limit)
+	case PasswordRuleMaxLength:
+		return fmt.Sprintf("password must be at most %d characters", v.Limit)
+	case PasswordRuleCharClasses:
+		return fmt.Sprintf("password must contain at least %d of: lowercase, uppercase, digits, symbols", v.Limit)
+	case PasswordRuleCommon:
+		return "password is too common"
+	default:
+		return v.Rule
+	}
+}
+
+// PasswordPolicyError пароль не соответствует парольной политике
+type PasswordPolicyError struct {
+	Violations []PasswordViolation
+}
+
+func (e *PasswordPolicyError) Error() string {
+	rules := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		rules = append(rules, v.Rule)
+	}
+	return "weak password: " + strings.Join(rules, ", ")
+}
+
+// Is позволяет проверять ошибку через errors.Is(err, ErrWeakPassword)
+func (e *PasswordPolicyError) Is(target error) bool {
+	return target == ErrWeakPassword
+}
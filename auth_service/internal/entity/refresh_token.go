@@ -0,0 +1,13 @@
+package entity
+
+import "time"
+
+// RefreshToken выданный пользователю refresh токен; ID совпадает с RefreshUuid
+type RefreshToken struct {
+	ID        string     `json:"id"`
+	UserID    string     `json:"user_id"`
+	SessionID string     `json:"session_id,omitempty"` // Пусто у токенов, выданных до появления сессий
+	ExpiresAt time.Time  `json:"expires_at"`
+	CreatedAt time.Time  `json:"created_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
@@ -0,0 +1,21 @@
+package entity
+
+import "time"
+
+// ClientInfo сведения о клиенте, с которого выполняется вход
+type ClientInfo struct {
+	IP        string
+	UserAgent string
+}
+
+// Session сессия пользователя на одном устройстве
+type Session struct {
+	ID         string     `json:"id"`
+	UserID     string     `json:"user_id"`
+	UserAgent  string     `json:"user_agent"`
+	IP         string     `json:"ip"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastSeenAt time.Time  `json:"last_seen_at"` // Последний вход или обновление токенов
+	ExpiresAt  time.Time  `json:"expires_at"`   // Срок последнего выданного refresh токена
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
@@ -1,6 +1,9 @@
 package entity
 
-import "errors"
+import (
+	"errors"
+	"time"
+)
 
 type User struct {
 	ID       string
@@ -8,6 +11,37 @@ type User struct {
 	Email    string
 	Password string
 	Role     string
+
+	// Профиль
+	DisplayName string
+	Bio         string
+	AvatarURL   string
+
+	// Статус аккаунта, меняет администратор
+	Status       string
+	StatusUntil  *time.Time // Окончание приостановки; nil - бессрочно
+	StatusReason string
+}
+
+// Статусы аккаунта
+const (
+	UserStatusActive    = "active"
+	UserStatusSuspended = "suspended"
+	UserStatusBanned    = "banned"
+)
+
+// CheckStatus возвращает AccountSuspendedError, если аккаунт приостановлен или заблокирован на момент now.
+// Истекшая приостановка не мешает входу.
+func (u *User) CheckStatus(now time.Time) error {
+	switch u.Status {
+	case UserStatusBanned:
+		return &AccountSuspendedError{Status: u.Status}
+	case UserStatusSuspended:
+		if u.StatusUntil == nil || now.Before(*u.StatusUntil) {
+			return &AccountSuspendedError{Status: u.Status, Until: u.StatusUntil}
+		}
+	}
+	return nil
 }
 
 type TokenDetails struct {
@@ -24,4 +58,63 @@ var (
 	ErrInvalidEmail      = errors.New("invalid email")
 	ErrWeakPassword      = errors.New("weak password")
 	ErrEmptyUsername     = errors.New("empty username")
+	ErrUserNotFound      = errors.New("user not found")
+	ErrNotAdmin          = errors.New("admin role required")
+	ErrInvalidToken      = errors.New("invalid token")
+	ErrEmailNotVerified  = errors.New("email not verified")
+	ErrAccountLocked     = errors.New("account locked")
+	// ErrImpersonationForbidden нельзя имперсонировать администратора или выпускать токен из-под имперсонации
+	ErrImpersonationForbidden = errors.New("impersonation forbidden")
+	// ErrTokenRevoked токен отозван при выходе из аккаунта
+	ErrTokenRevoked = errors.New("token revoked")
+	// ErrSessionNotFound сессии нет среди активных сессий пользователя
+	ErrSessionNotFound = errors.New("session not found")
+	// Ошибки изменения профиля
+	ErrUsernameTaken       = errors.New("username taken")
+	ErrInvalidAvatarURL    = errors.New("invalid avatar url")
+	ErrProfileFieldTooLong = errors.New("profile field too long")
+	// Ошибки загрузки аватара
+	ErrUnsupportedImage = errors.New("unsupported image type")
+	ErrInvalidImage     = errors.New("invalid image")
+	// Ошибки удаления аккаунта
+	ErrWrongPassword        = errors.New("wrong password")
+	ErrContentAnonymization = errors.New("failed to anonymize user content")
+	// Ошибки изменения статуса аккаунта
+	ErrAccountSuspended = errors.New("account suspended")
+	ErrInvalidStatus    = errors.New("invalid account status")
+	ErrOwnStatus        = errors.New("cannot change own account status")
+	// ErrTooManyUsers в одном запросе данных пользователей слишком много ID
+	ErrTooManyUsers = errors.New("too many users requested")
 )
+
+// AccountLockedError вход временно заблокирован после серии неудачных попыток
+type AccountLockedError struct {
+	Until time.Time // Окончание блокировки
+}
+
+func (e *AccountLockedError) Error() string {
+	return "account locked until " + e.Until.UTC().Format(time.RFC3339)
+}
+
+// Is позволяет проверять ошибку через errors.Is(err, ErrAccountLocked)
+func (e *AccountLockedError) Is(target error) bool {
+	return target == ErrAccountLocked
+}
+
+// AccountSuspendedError аккаунт приостановлен или заблокирован администратором
+type AccountSuspendedError struct {
+	Status string     // suspended или banned
+	Until  *time.Time // Окончание приостановки; nil - бессрочно
+}
+
+func (e *AccountSuspendedError) Error() string {
+	if e.Until != nil {
+		return "account " + e.Status + " until " + e.Until.UTC().Format(time.RFC3339)
+	}
+	return "account " + e.Status
+}
+
+// Is позволяет проверять ошибку через errors.Is(err, ErrAccountSuspended)
+func (e *AccountSuspendedError) Is(target error) bool {
+	return target == ErrAccountSuspended
+}
@@ -0,0 +1,139 @@
+// Package forumclient gRPC клиент сервиса форума
+package forumclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/kprf42/dolgova/auth_service/internal/entity"
+	"github.com/kprf42/dolgova/pkg/logger"
+	forumpb "github.com/kprf42/dolgova/proto/forum/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+)
+
+// timeout обезличивание затрагивает весь контент пользователя, поэтому дольше обычного вызова
+const timeout = 30 * time.Second
+
+// Client клиент сервиса форума
+type Client struct {
+	conn *grpc.ClientConn
+	api  forumpb.ForumServiceClient
+	log  *logger.Logger
+}
+
+// New создает клиент. Без opts соединение устанавливается без TLS.
+// Подключение ленивое: форум может быть недоступен в момент запуска.
+func New(addr string, log *logger.Logger, opts ...grpc.DialOption) (*Client, error) {
+	if len(opts) == 0 {
+		opts = []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+	}
+
+	conn, err := grpc.NewClient(addr, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create forum client: %w", err)
+	}
+
+	return &Client{
+		conn: conn,
+		api:  forumpb.NewForumServiceClient(conn),
+		log:  log,
+	}, nil
+}
+
+// Close закрывает соединение
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// AnonymizeUser переназначает контент пользователя на форуме удаленному пользователю.
+// Форум проверяет accessToken: обезличить можно только себя.
+func (c *Client) AnonymizeUser(ctx context.Context, userID, accessToken string) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+accessToken)
+
+	resp, err := c.api.AnonymizeUser(ctx, &forumpb.AnonymizeUserRequest{UserId: userID})
+	if err != nil {
+		return fmt.Errorf("failed to anonymize forum content: %w", err)
+	}
+
+	c.log.Info("Anonymized forum content",
+		logger.String("user_id", userID),
+		logger.Int64("posts", resp.GetPosts()),
+		logger.Int64("comments", resp.GetComments()),
+		logger.Int64("chat_messages", resp.GetChatMessages()))
+	return nil
+}
+
+// exportTimeout выгрузка читает весь контент пользователя потоком
+const exportTimeout = 5 * time.Minute
+
+// ExportUserContent собирает посты, комментарии и сообщения чатов пользователя со всех форумов.
+// Форум проверяет accessToken: выгрузить можно только свой контент.
+func (c *Client) ExportUserContent(ctx context.Context, userID, accessToken string) (*entity.ForumContent, error) {
+	ctx, cancel := context.WithTimeout(ctx, exportTimeout)
+	defer cancel()
+	ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+accessToken)
+
+	stream, err := c.api.ExportUserContent(ctx, &forumpb.ExportUserContentRequest{UserId: userID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to export forum content: %w", err)
+	}
+
+	content := &entity.ForumContent{
+		Posts:        []entity.ForumPost{},
+		Comments:     []entity.ForumComment{},
+		ChatMessages: []entity.ForumChatMessage{},
+	}
+	for {
+		resp, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to export forum content: %w", err)
+		}
+
+		for _, p := range resp.GetPosts() {
+			content.Posts = append(content.Posts, entity.ForumPost{
+				ID:         p.GetId(),
+				ForumID:    p.GetForumId(),
+				Title:      p.GetTitle(),
+				Content:    p.GetContent(),
+				CategoryID: p.GetCategoryId(),
+				Status:     p.GetStatus(),
+				CreatedAt:  p.GetCreatedAt().AsTime(),
+			})
+		}
+		for _, cm := range resp.GetComments() {
+			content.Comments = append(content.Comments, entity.ForumComment{
+				ID:        cm.GetId(),
+				ForumID:   cm.GetForumId(),
+				PostID:    cm.GetPostId(),
+				Content:   cm.GetContent(),
+				Status:    cm.GetStatus(),
+				CreatedAt: cm.GetCreatedAt().AsTime(),
+			})
+		}
+		for _, m := range resp.GetChatMessages() {
+			content.ChatMessages = append(content.ChatMessages, entity.ForumChatMessage{
+				ID:        m.GetId(),
+				ForumID:   m.GetForumId(),
+				Text:      m.GetText(),
+				CreatedAt: m.GetCreatedAt().AsTime(),
+			})
+		}
+	}
+
+	c.log.Info("Exported forum content",
+		logger.String("user_id", userID),
+		logger.Int("posts", len(content.Posts)),
+		logger.Int("comments", len(content.Comments)),
+		logger.Int("chat_messages", len(content.ChatMessages)))
+	return content, nil
+}
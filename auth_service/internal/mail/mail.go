@@ -0,0 +1,56 @@
+// Package mail письма пользователям auth сервиса: шаблоны и отправка через pkg/mailer
+package mail
+
+import (
+	"context"
+	"embed"
+	"io/fs"
+
+	"github.com/kprf42/dolgova/auth_service/internal/entity"
+	"github.com/kprf42/dolgova/pkg/mailer"
+)
+
+//go:embed templates/*.tmpl
+var templatesFS embed.FS
+
+// Mailer собирает письма из шаблонов каталога templates и передает их sender
+type Mailer struct {
+	templates *mailer.Templates
+	sender    mailer.Sender
+}
+
+func New(sender mailer.Sender) (*Mailer, error) {
+	dir, err := fs.Sub(templatesFS, "templates")
+	if err != nil {
+		return nil, err
+	}
+	templates, err := mailer.ParseTemplates(dir)
+	if err != nil {
+		return nil, err
+	}
+	return &Mailer{templates: templates, sender: sender}, nil
+}
+
+// SendLoginLink отправляет ссылку для входа без пароля
+func (m *Mailer) SendLoginLink(ctx context.Context, email, link string) error {
+	return m.send(ctx, "login_link", email, map[string]any{"Link": link})
+}
+
+// SendNewDeviceAlert сообщает о входе в аккаунт с нового устройства
+func (m *Mailer) SendNewDeviceAlert(ctx context.Context, email string, alert *entity.NewDeviceAlert) error {
+	return m.send(ctx, "new_device", email, map[string]any{
+		"Username":   alert.Username,
+		"Time":       alert.Time.UTC().Format("02.01.2006 15:04 UTC"),
+		"IP":         alert.IP,
+		"UserAgent":  alert.UserAgent,
+		"RevokeLink": alert.RevokeLink,
+	})
+}
+
+func (m *Mailer) send(ctx context.Context, name, to string, data any) error {
+	msg, err := m.templates.Render(name, to, data)
+	if err != nil {
+		return err
+	}
+	return m.sender.Send(ctx, msg)
+}
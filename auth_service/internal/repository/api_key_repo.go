@@ -0,0 +1,167 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kprf42/dolgova/auth_service/internal/entity"
+	"github.com/kprf42/dolgova/pkg/logger"
+)
+
+// APIKeyRepository хранит ключи внутренних сервисов
+type APIKeyRepository struct {
+	db  *sql.DB
+	log *logger.Logger
+}
+
+func NewAPIKeyRepository(db *sql.DB, log *logger.Logger) *APIKeyRepository {
+	return &APIKeyRepository{
+		db:  db,
+		log: log,
+	}
+}
+
+const apiKeyColumns = `id, name, secret_hash, scopes, created_by, created_at, expires_at, revoked_at, last_used_at, rotated_from`
+
+func (r *APIKeyRepository) Create(ctx context.Context, key *entity.APIKey) error {
+	r.log.Info("Creating api key",
+		logger.String("key_id", key.ID),
+		logger.String("name", key.Name))
+
+	query := `INSERT INTO api_keys (id, name, secret_hash, scopes, created_by, created_at, expires_at, rotated_from)
+	          VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+	_, err := r.db.ExecContext(ctx, query,
+		key.ID,
+		key.Name,
+		key.SecretHash,
+		strings.Join(key.Scopes, ","),
+		key.CreatedBy,
+		formatUTC(key.CreatedAt),
+		nullableUTC(key.ExpiresAt),
+		key.RotatedFrom,
+	)
+	if err != nil {
+		r.log.Error("Failed to create api key",
+			logger.String("key_id", key.ID),
+			logger.Error(err))
+		return fmt.Errorf("failed to create api key: %w", err)
+	}
+	return nil
+}
+
+// GetByID возвращает ключ или nil, если его нет
+func (r *APIKeyRepository) GetByID(ctx context.Context, id string) (*entity.APIKey, error) {
+	key, err := scanAPIKey(r.db.QueryRowContext(ctx, `SELECT `+apiKeyColumns+` FROM api_keys WHERE id = ?`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		r.log.Error("Failed to get api key",
+			logger.String("key_id", id),
+			logger.Error(err))
+		return nil, fmt.Errorf("failed to get api key: %w", err)
+	}
+	return key, nil
+}
+
+// List возвращает все ключи, новые первыми
+func (r *APIKeyRepository) List(ctx context.Context) ([]*entity.APIKey, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT `+apiKeyColumns+` FROM api_keys ORDER BY created_at DESC`)
+	if err != nil {
+		r.log.Error("Failed to list api keys",
+			logger.Error(err))
+		return nil, fmt.Errorf("failed to list api keys: %w", err)
+	}
+	defer rows.Close()
+
+	keys := []*entity.APIKey{}
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan api key: %w", err)
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+// Revoke отзывает ключ. Возвращает ErrAPIKeyNotFound, если действующего ключа нет.
+func (r *APIKeyRepository) Revoke(ctx context.Context, id string, now time.Time) error {
+	result, err := r.db.ExecContext(ctx, `UPDATE api_keys SET revoked_at = ? WHERE id = ? AND revoked_at IS NULL`,
+		formatUTC(now), id)
+	if err != nil {
+		r.log.Error("Failed to revoke api key",
+			logger.String("key_id", id),
+			logger.Error(err))
+		return fmt.Errorf("failed to revoke api key: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return entity.ErrAPIKeyNotFound
+	}
+	return nil
+}
+
+// Expire сокращает срок действия ключа до expiresAt (при ротации)
+func (r *APIKeyRepository) Expire(ctx context.Context, id string, expiresAt time.Time) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE api_keys SET expires_at = ? WHERE id = ? AND (expires_at IS NULL OR expires_at > ?)`,
+		formatUTC(expiresAt), id, formatUTC(expiresAt))
+	if err != nil {
+		r.log.Error("Failed to expire api key",
+			logger.String("key_id", id),
+			logger.Error(err))
+		return fmt.Errorf("failed to expire api key: %w", err)
+	}
+	return nil
+}
+
+// Touch запоминает время последнего использования ключа
+func (r *APIKeyRepository) Touch(ctx context.Context, id string, now time.Time) error {
+	if _, err := r.db.ExecContext(ctx, `UPDATE api_keys SET last_used_at = ? WHERE id = ?`, formatUTC(now), id); err != nil {
+		r.log.Error("Failed to touch api key",
+			logger.String("key_id", id),
+			logger.Error(err))
+		return fmt.Errorf("failed to touch api key: %w", err)
+	}
+	return nil
+}
+
+func scanAPIKey(row interface{ Scan(...any) error }) (*entity.APIKey, error) {
+	var key entity.APIKey
+	var scopes, createdAt string
+	var expiresAt, revokedAt, lastUsedAt sql.NullString
+	err := row.Scan(&key.ID, &key.Name, &key.SecretHash, &scopes, &key.CreatedBy, &createdAt,
+		&expiresAt, &revokedAt, &lastUsedAt, &key.RotatedFrom)
+	if err != nil {
+		return nil, err
+	}
+
+	key.Scopes = []string{}
+	if scopes != "" {
+		key.Scopes = strings.Split(scopes, ",")
+	}
+	if key.CreatedAt, err = time.Parse(time.RFC3339, createdAt); err != nil {
+		return nil, fmt.Errorf("failed to parse created_at: %w", err)
+	}
+	for _, f := range []struct {
+		src sql.NullString
+		dst **time.Time
+	}{{expiresAt, &key.ExpiresAt}, {revokedAt, &key.RevokedAt}, {lastUsedAt, &key.LastUsedAt}} {
+		if !f.src.Valid {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, f.src.String)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse api key time: %w", err)
+		}
+		*f.dst = &t
+	}
+	return &key, nil
+}
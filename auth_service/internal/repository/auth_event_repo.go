@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/kprf42/dolgova/auth_service/internal/entity"
+	"github.com/kprf42/dolgova/pkg/logger"
+)
+
+// AuthEventRepository история событий аутентификации
+type AuthEventRepository struct {
+	db  *sql.DB
+	log *logger.Logger
+}
+
+func NewAuthEventRepository(db *sql.DB, log *logger.Logger) *AuthEventRepository {
+	return &AuthEventRepository{
+		db:  db,
+		log: log,
+	}
+}
+
+// Create добавляет событие. Время проставляется, если не задано.
+func (r *AuthEventRepository) Create(ctx context.Context, event *entity.AuthEvent) error {
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now()
+	}
+
+	query := `INSERT INTO auth_events (user_id, type, method, ip, user_agent, details, created_at) VALUES (?, ?, ?, ?, ?, ?, ?)`
+	result, err := r.db.ExecContext(ctx, query,
+		event.UserID,
+		event.Type,
+		event.Method,
+		event.IP,
+		event.UserAgent,
+		event.Details,
+		formatUTC(event.CreatedAt),
+	)
+	if err != nil {
+		r.log.Error("Failed to store auth event",
+			logger.String("user_id", event.UserID),
+			logger.String("type", event.Type),
+			logger.Error(err))
+		return fmt.Errorf("failed to store auth event: %w", err)
+	}
+
+	event.ID, _ = result.LastInsertId()
+	return nil
+}
+
+// ListByUser возвращает события пользователя, новые первыми
+func (r *AuthEventRepository) ListByUser(ctx context.Context, userID string, limit, offset int) ([]*entity.AuthEvent, error) {
+	query := `SELECT id, user_id, type, method, ip, user_agent, details, created_at FROM auth_events
+	          WHERE user_id = ?
+	          ORDER BY id DESC LIMIT ? OFFSET ?`
+	rows, err := r.db.QueryContext(ctx, query, userID, limit, offset)
+	if err != nil {
+		r.log.Error("Failed to list auth events",
+			logger.String("user_id", userID),
+			logger.Error(err))
+		return nil, fmt.Errorf("failed to list auth events: %w", err)
+	}
+	defer rows.Close()
+
+	events := []*entity.AuthEvent{}
+	for rows.Next() {
+		var event entity.AuthEvent
+		var createdAt string
+		err := rows.Scan(&event.ID, &event.UserID, &event.Type, &event.Method,
+			&event.IP, &event.UserAgent, &event.Details, &createdAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan auth event: %w", err)
+		}
+		if event.CreatedAt, err = time.Parse(time.RFC3339, createdAt); err != nil {
+			return nil, fmt.Errorf("failed to parse created_at: %w", err)
+		}
+		events = append(events, &event)
+	}
+	return events, rows.Err()
+}
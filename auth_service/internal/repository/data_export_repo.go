@@ -0,0 +1,188 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/kprf42/dolgova/auth_service/internal/entity"
+	"github.com/kprf42/dolgova/pkg/logger"
+)
+
+// DataExportRepository хранит состояние выгрузок персональных данных.
+// Даты хранятся в UTC RFC3339, поэтому сравниваются как строки.
+type DataExportRepository struct {
+	db  *sql.DB
+	log *logger.Logger
+}
+
+func NewDataExportRepository(db *sql.DB, log *logger.Logger) *DataExportRepository {
+	return &DataExportRepository{
+		db:  db,
+		log: log,
+	}
+}
+
+func (r *DataExportRepository) Create(ctx context.Context, export *entity.DataExport) error {
+	r.log.Info("Creating data export",
+		logger.String("export_id", export.ID),
+		logger.String("user_id", export.UserID))
+
+	query := `INSERT INTO data_exports (id, user_id, status, created_at, expires_at) VALUES (?, ?, ?, ?, ?)`
+	_, err := r.db.ExecContext(ctx, query,
+		export.ID,
+		export.UserID,
+		export.Status,
+		formatUTC(export.CreatedAt),
+		formatUTC(export.ExpiresAt),
+	)
+	if err != nil {
+		r.log.Error("Failed to create data export",
+			logger.String("export_id", export.ID),
+			logger.Error(err))
+		return fmt.Errorf("failed to create data export: %w", err)
+	}
+	return nil
+}
+
+// GetByID возвращает выгрузку или nil, если ее нет
+func (r *DataExportRepository) GetByID(ctx context.Context, id string) (*entity.DataExport, error) {
+	query := `SELECT id, user_id, status, error, size, created_at, completed_at, expires_at FROM data_exports WHERE id = ?`
+	export, err := scanDataExport(r.db.QueryRowContext(ctx, query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		r.log.Error("Failed to get data export",
+			logger.String("export_id", id),
+			logger.Error(err))
+		return nil, fmt.Errorf("failed to get data export: %w", err)
+	}
+	return export, nil
+}
+
+// GetLatest возвращает последнюю выгрузку пользователя или nil
+func (r *DataExportRepository) GetLatest(ctx context.Context, userID string) (*entity.DataExport, error) {
+	query := `SELECT id, user_id, status, error, size, created_at, completed_at, expires_at FROM data_exports
+	          WHERE user_id = ? ORDER BY created_at DESC LIMIT 1`
+	export, err := scanDataExport(r.db.QueryRowContext(ctx, query, userID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		r.log.Error("Failed to get latest data export",
+			logger.String("user_id", userID),
+			logger.Error(err))
+		return nil, fmt.Errorf("failed to get data export: %w", err)
+	}
+	return export, nil
+}
+
+// SetRunning отмечает начало сборки архива
+func (r *DataExportRepository) SetRunning(ctx context.Context, id string) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE data_exports SET status = ? WHERE id = ?`, entity.DataExportRunning, id)
+	if err != nil {
+		r.log.Error("Failed to update data export",
+			logger.String("export_id", id),
+			logger.Error(err))
+		return fmt.Errorf("failed to update data export: %w", err)
+	}
+	return nil
+}
+
+// Complete отмечает готовность архива размером size
+func (r *DataExportRepository) Complete(ctx context.Context, id string, size int64, now time.Time) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE data_exports SET status = ?, size = ?, completed_at = ? WHERE id = ?`,
+		entity.DataExportReady, size, formatUTC(now), id)
+	if err != nil {
+		r.log.Error("Failed to complete data export",
+			logger.String("export_id", id),
+			logger.Error(err))
+		return fmt.Errorf("failed to complete data export: %w", err)
+	}
+	return nil
+}
+
+// Fail отмечает неудачную выгрузку с причиной reason
+func (r *DataExportRepository) Fail(ctx context.Context, id, reason string, now time.Time) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE data_exports SET status = ?, error = ?, completed_at = ? WHERE id = ?`,
+		entity.DataExportFailed, reason, formatUTC(now), id)
+	if err != nil {
+		r.log.Error("Failed to mark data export failed",
+			logger.String("export_id", id),
+			logger.Error(err))
+		return fmt.Errorf("failed to update data export: %w", err)
+	}
+	return nil
+}
+
+// FailUnfinished отмечает неудачными выгрузки, прерванные перезапуском сервиса
+func (r *DataExportRepository) FailUnfinished(ctx context.Context, reason string, now time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `UPDATE data_exports SET status = ?, error = ?, completed_at = ? WHERE status IN (?, ?)`,
+		entity.DataExportFailed, reason, formatUTC(now), entity.DataExportPending, entity.DataExportRunning)
+	if err != nil {
+		r.log.Error("Failed to fail unfinished data exports",
+			logger.Error(err))
+		return 0, fmt.Errorf("failed to update data exports: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// ListExpired возвращает ID выгрузок, истекших к моменту now
+func (r *DataExportRepository) ListExpired(ctx context.Context, now time.Time) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT id FROM data_exports WHERE expires_at <= ?`, formatUTC(now))
+	if err != nil {
+		r.log.Error("Failed to list expired data exports",
+			logger.Error(err))
+		return nil, fmt.Errorf("failed to list expired data exports: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan data export: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+func (r *DataExportRepository) Delete(ctx context.Context, id string) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM data_exports WHERE id = ?`, id); err != nil {
+		r.log.Error("Failed to delete data export",
+			logger.String("export_id", id),
+			logger.Error(err))
+		return fmt.Errorf("failed to delete data export: %w", err)
+	}
+	return nil
+}
+
+func scanDataExport(row interface{ Scan(...any) error }) (*entity.DataExport, error) {
+	var export entity.DataExport
+	var createdAt, expiresAt string
+	var completedAt sql.NullString
+	err := row.Scan(&export.ID, &export.UserID, &export.Status, &export.Error, &export.Size,
+		&createdAt, &completedAt, &expiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if export.CreatedAt, err = time.Parse(time.RFC3339, createdAt); err != nil {
+		return nil, fmt.Errorf("failed to parse created_at: %w", err)
+	}
+	if export.ExpiresAt, err = time.Parse(time.RFC3339, expiresAt); err != nil {
+		return nil, fmt.Errorf("failed to parse expires_at: %w", err)
+	}
+	if completedAt.Valid {
+		t, err := time.Parse(time.RFC3339, completedAt.String)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse completed_at: %w", err)
+		}
+		export.CompletedAt = &t
+	}
+	return &export, nil
+}
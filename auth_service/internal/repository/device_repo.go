@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/kprf42/dolgova/auth_service/internal/entity"
+	"github.com/kprf42/dolgova/pkg/logger"
+)
+
+// DeviceRepository хранит устройства, с которых пользователи уже входили
+type DeviceRepository struct {
+	db  *sql.DB
+	log *logger.Logger
+}
+
+func NewDeviceRepository(db *sql.DB, log *logger.Logger) *DeviceRepository {
+	return &DeviceRepository{
+		db:  db,
+		log: log,
+	}
+}
+
+// HasAny сообщает, есть ли у пользователя хотя бы одно известное устройство
+func (r *DeviceRepository) HasAny(ctx context.Context, userID string) (bool, error) {
+	var exists bool
+	err := r.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM known_devices WHERE user_id = ?)`, userID).Scan(&exists)
+	if err != nil {
+		r.log.Error("Failed to check known devices",
+			logger.String("user_id", userID),
+			logger.Error(err))
+		return false, fmt.Errorf("failed to check known devices: %w", err)
+	}
+	return exists, nil
+}
+
+// Remember запоминает устройство или обновляет время последнего входа с него.
+// Возвращает true, если устройство с таким отпечатком у пользователя раньше не встречалось.
+func (r *DeviceRepository) Remember(ctx context.Context, device *entity.KnownDevice) (bool, error) {
+	query := `INSERT INTO known_devices (user_id, fingerprint, user_agent, ip, first_seen_at, last_seen_at)
+	          VALUES (?, ?, ?, ?, ?, ?) ON CONFLICT (user_id, fingerprint) DO NOTHING`
+	result, err := r.db.ExecContext(ctx, query,
+		device.UserID,
+		device.Fingerprint,
+		device.UserAgent,
+		device.IP,
+		formatUTC(device.FirstSeenAt),
+		formatUTC(device.LastSeenAt),
+	)
+	if err != nil {
+		r.log.Error("Failed to remember device",
+			logger.String("user_id", device.UserID),
+			logger.Error(err))
+		return false, fmt.Errorf("failed to remember device: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 1 {
+		return true, nil
+	}
+
+	_, err = r.db.ExecContext(ctx, `UPDATE known_devices SET last_seen_at = ? WHERE user_id = ? AND fingerprint = ?`,
+		formatUTC(device.LastSeenAt), device.UserID, device.Fingerprint)
+	if err != nil {
+		r.log.Error("Failed to touch device",
+			logger.String("user_id", device.UserID),
+			logger.Error(err))
+		return false, fmt.Errorf("failed to touch device: %w", err)
+	}
+	return false, nil
+}
+
+// DeleteByUser удаляет устройства пользователя
+func (r *DeviceRepository) DeleteByUser(ctx context.Context, userID string) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM known_devices WHERE user_id = ?`, userID); err != nil {
+		r.log.Error("Failed to delete known devices",
+			logger.String("user_id", userID),
+			logger.Error(err))
+		return fmt.Errorf("failed to delete known devices: %w", err)
+	}
+	return nil
+}
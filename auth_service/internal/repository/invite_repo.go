@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/kprf42/dolgova/auth_service/internal/entity"
+	"github.com/kprf42/dolgova/pkg/logger"
+)
+
+// InviteRepository хранит приглашения для регистрации.
+// Даты хранятся в UTC RFC3339, поэтому сравниваются как строки.
+type InviteRepository struct {
+	db  *sql.DB
+	log *logger.Logger
+}
+
+func NewInviteRepository(db *sql.DB, log *logger.Logger) *InviteRepository {
+	return &InviteRepository{
+		db:  db,
+		log: log,
+	}
+}
+
+const inviteColumns = `id, code_hash, max_uses, uses, created_by, created_at, expires_at, revoked_at`
+
+func (r *InviteRepository) Create(ctx context.Context, invite *entity.Invite) error {
+	r.log.Info("Creating invite",
+		logger.String("invite_id", invite.ID),
+		logger.Int("max_uses", invite.MaxUses))
+
+	query := `INSERT INTO invites (id, code_hash, max_uses, created_by, created_at, expires_at) VALUES (?, ?, ?, ?, ?, ?)`
+	_, err := r.db.ExecContext(ctx, query,
+		invite.ID,
+		invite.CodeHash,
+		invite.MaxUses,
+		invite.CreatedBy,
+		formatUTC(invite.CreatedAt),
+		nullableUTC(invite.ExpiresAt),
+	)
+	if err != nil {
+		r.log.Error("Failed to create invite",
+			logger.String("invite_id", invite.ID),
+			logger.Error(err))
+		return fmt.Errorf("failed to create invite: %w", err)
+	}
+	return nil
+}
+
+// List возвращает все приглашения, новые первыми
+func (r *InviteRepository) List(ctx context.Context) ([]*entity.Invite, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT `+inviteColumns+` FROM invites ORDER BY created_at DESC`)
+	if err != nil {
+		r.log.Error("Failed to list invites",
+			logger.Error(err))
+		return nil, fmt.Errorf("failed to list invites: %w", err)
+	}
+	defer rows.Close()
+
+	invites := []*entity.Invite{}
+	for rows.Next() {
+		invite, err := scanInvite(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan invite: %w", err)
+		}
+		invites = append(invites, invite)
+	}
+	return invites, rows.Err()
+}
+
+// Consume засчитывает одно использование действующего на момент now приглашения с хешем кода codeHash
+// и возвращает его ID. Проверка и увеличение счетчика выполняются одним запросом, поэтому
+// параллельные регистрации не превысят лимит. Возвращает ErrInvalidInvite, если приглашение не подходит.
+func (r *InviteRepository) Consume(ctx context.Context, codeHash string, now time.Time) (string, error) {
+	query := `UPDATE invites SET uses = uses + 1
+	          WHERE code_hash = ? AND revoked_at IS NULL AND uses < max_uses AND (expires_at IS NULL OR expires_at > ?)
+	          RETURNING id`
+	var id string
+	err := r.db.QueryRowContext(ctx, query, codeHash, formatUTC(now)).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", entity.ErrInvalidInvite
+	}
+	if err != nil {
+		r.log.Error("Failed to consume invite",
+			logger.Error(err))
+		return "", fmt.Errorf("failed to consume invite: %w", err)
+	}
+	return id, nil
+}
+
+// Release возвращает использование приглашения, если регистрация по нему не завершилась
+func (r *InviteRepository) Release(ctx context.Context, id string) error {
+	if _, err := r.db.ExecContext(ctx, `UPDATE invites SET uses = uses - 1 WHERE id = ? AND uses > 0`, id); err != nil {
+		r.log.Error("Failed to release invite",
+			logger.String("invite_id", id),
+			logger.Error(err))
+		return fmt.Errorf("failed to release invite: %w", err)
+	}
+	return nil
+}
+
+// Revoke отзывает приглашение. Возвращает ErrInviteNotFound, если действующего приглашения нет.
+func (r *InviteRepository) Revoke(ctx context.Context, id string, now time.Time) error {
+	result, err := r.db.ExecContext(ctx, `UPDATE invites SET revoked_at = ? WHERE id = ? AND revoked_at IS NULL`,
+		formatUTC(now), id)
+	if err != nil {
+		r.log.Error("Failed to revoke invite",
+			logger.String("invite_id", id),
+			logger.Error(err))
+		return fmt.Errorf("failed to revoke invite: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return entity.ErrInviteNotFound
+	}
+	return nil
+}
+
+func scanInvite(row interface{ Scan(...any) error }) (*entity.Invite, error) {
+	var invite entity.Invite
+	var createdAt string
+	var expiresAt, revokedAt sql.NullString
+	err := row.Scan(&invite.ID, &invite.CodeHash, &invite.MaxUses, &invite.Uses, &invite.CreatedBy, &createdAt,
+		&expiresAt, &revokedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if invite.CreatedAt, err = time.Parse(time.RFC3339, createdAt); err != nil {
+		return nil, fmt.Errorf("failed to parse created_at: %w", err)
+	}
+	for _, f := range []struct {
+		src sql.NullString
+		dst **time.Time
+	}{{expiresAt, &invite.ExpiresAt}, {revokedAt, &invite.RevokedAt}} {
+		if !f.src.Valid {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, f.src.String)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse invite time: %w", err)
+		}
+		*f.dst = &t
+	}
+	return &invite, nil
+}
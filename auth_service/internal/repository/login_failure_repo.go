@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/kprf42/dolgova/pkg/logger"
+)
+
+// Виды счетчиков неудачных входов
+const (
+	LoginFailureUser = "user"
+	LoginFailureIP   = "ip"
+)
+
+// LoginFailureRepository считает подряд идущие неудачные входы и хранит блокировки входа
+type LoginFailureRepository struct {
+	db  *sql.DB
+	log *logger.Logger
+}
+
+func NewLoginFailureRepository(db *sql.DB, log *logger.Logger) *LoginFailureRepository {
+	return &LoginFailureRepository{
+		db:  db,
+		log: log,
+	}
+}
+
+// LockedUntil возвращает время окончания блокировки входа или nil, если на момент now вход не заблокирован
+func (r *LoginFailureRepository) LockedUntil(ctx context.Context, kind, subject string, now time.Time) (*time.Time, error) {
+	var lockedUntil sql.NullString
+	err := r.db.QueryRowContext(ctx, `SELECT locked_until FROM login_failures WHERE kind = ? AND subject = ?`, kind, subject).Scan(&lockedUntil)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && !lockedUntil.Valid) {
+		return nil, nil
+	}
+	if err != nil {
+		r.log.Error("Failed to get login lock",
+			logger.String("kind", kind),
+			logger.String("subject", subject),
+			logger.Error(err))
+		return nil, fmt.Errorf("failed to get login lock: %w", err)
+	}
+
+	until, err := time.Parse(time.RFC3339, lockedUntil.String)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse locked_until: %w", err)
+	}
+	if !until.After(now) {
+		return nil, nil
+	}
+	return &until, nil
+}
+
+// RecordFailure засчитывает неудачный вход. Когда счетчик достигает threshold, вход блокируется
+// на lockout и счетчик сбрасывается; тогда возвращается время окончания блокировки.
+func (r *LoginFailureRepository) RecordFailure(ctx context.Context, kind, subject string, threshold int, lockout time.Duration, now time.Time) (*time.Time, error) {
+	query := `INSERT INTO login_failures (kind, subject, failures, updated_at) VALUES (?, ?, 1, ?)
+	          ON CONFLICT(kind, subject) DO UPDATE SET failures = failures + 1, updated_at = excluded.updated_at
+	          RETURNING failures`
+	var failures int
+	if err := r.db.QueryRowContext(ctx, query, kind, subject, formatUTC(now)).Scan(&failures); err != nil {
+		r.log.Error("Failed to record login failure",
+			logger.String("kind", kind),
+			logger.String("subject", subject),
+			logger.Error(err))
+		return nil, fmt.Errorf("failed to record login failure: %w", err)
+	}
+
+	if failures < threshold {
+		return nil, nil
+	}
+
+	until := now.Add(lockout).UTC().Truncate(time.Second)
+	_, err := r.db.ExecContext(ctx, `UPDATE login_failures SET failures = 0, locked_until = ? WHERE kind = ? AND subject = ?`,
+		formatUTC(until), kind, subject)
+	if err != nil {
+		r.log.Error("Failed to lock login",
+			logger.String("kind", kind),
+			logger.String("subject", subject),
+			logger.Error(err))
+		return nil, fmt.Errorf("failed to lock login: %w", err)
+	}
+
+	r.log.Warn("Login locked after failed attempts",
+		logger.String("kind", kind),
+		logger.String("subject", subject),
+		logger.Int("failures", failures),
+		logger.String("locked_until", formatUTC(until)))
+	return &until, nil
+}
+
+// Reset сбрасывает счетчик после успешного входа
+func (r *LoginFailureRepository) Reset(ctx context.Context, kind, subject string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM login_failures WHERE kind = ? AND subject = ?`, kind, subject)
+	if err != nil {
+		r.log.Error("Failed to reset login failures",
+			logger.String("kind", kind),
+			logger.String("subject", subject),
+			logger.Error(err))
+		return fmt.Errorf("failed to reset login failures: %w", err)
+	}
+	return nil
+}
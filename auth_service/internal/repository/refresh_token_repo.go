@@ -0,0 +1,188 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/kprf42/dolgova/auth_service/internal/entity"
+	"github.com/kprf42/dolgova/pkg/logger"
+)
+
+// RefreshTokenRepository хранит выданные refresh токены.
+// Даты хранятся в UTC RFC3339, поэтому сравниваются как строки.
+type RefreshTokenRepository struct {
+	db  *sql.DB
+	log *logger.Logger
+}
+
+func NewRefreshTokenRepository(db *sql.DB, log *logger.Logger) *RefreshTokenRepository {
+	return &RefreshTokenRepository{
+		db:  db,
+		log: log,
+	}
+}
+
+func (r *RefreshTokenRepository) Create(ctx context.Context, token *entity.RefreshToken) error {
+	r.log.Info("Storing refresh token",
+		logger.String("token_id", token.ID),
+		logger.String("user_id", token.UserID))
+
+	query := `INSERT INTO refresh_tokens (id, user_id, session_id, expires_at, created_at) VALUES (?, ?, ?, ?, ?)`
+	_, err := r.db.ExecContext(ctx, query,
+		token.ID,
+		token.UserID,
+		sql.NullString{String: token.SessionID, Valid: token.SessionID != ""},
+		formatUTC(token.ExpiresAt),
+		formatUTC(token.CreatedAt),
+	)
+	if err != nil {
+		r.log.Error("Failed to store refresh token",
+			logger.String("token_id", token.ID),
+			logger.Error(err))
+		return fmt.Errorf("failed to store refresh token: %w", err)
+	}
+	return nil
+}
+
+// GetByID возвращает refresh токен или nil, если его нет
+func (r *RefreshTokenRepository) GetByID(ctx context.Context, id string) (*entity.RefreshToken, error) {
+	query := `SELECT id, user_id, session_id, expires_at, created_at, revoked_at FROM refresh_tokens WHERE id = ?`
+	token, err := scanRefreshToken(r.db.QueryRowContext(ctx, query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		r.log.Error("Failed to get refresh token",
+			logger.String("token_id", id),
+			logger.Error(err))
+		return nil, fmt.Errorf("failed to get refresh token: %w", err)
+	}
+	return token, nil
+}
+
+// ListActive возвращает неотозванные и не истекшие к моменту now refresh токены пользователя
+func (r *RefreshTokenRepository) ListActive(ctx context.Context, userID string, now time.Time) ([]*entity.RefreshToken, error) {
+	query := `SELECT id, user_id, session_id, expires_at, created_at, revoked_at FROM refresh_tokens
+	          WHERE user_id = ? AND revoked_at IS NULL AND expires_at > ?
+	          ORDER BY created_at DESC`
+	rows, err := r.db.QueryContext(ctx, query, userID, formatUTC(now))
+	if err != nil {
+		r.log.Error("Failed to list refresh tokens",
+			logger.String("user_id", userID),
+			logger.Error(err))
+		return nil, fmt.Errorf("failed to list refresh tokens: %w", err)
+	}
+	defer rows.Close()
+
+	tokens := []*entity.RefreshToken{}
+	for rows.Next() {
+		token, err := scanRefreshToken(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan refresh token: %w", err)
+		}
+		tokens = append(tokens, token)
+	}
+	return tokens, rows.Err()
+}
+
+// Revoke отзывает refresh токен. Возвращает false, если токен уже был отозван или не найден.
+func (r *RefreshTokenRepository) Revoke(ctx context.Context, id string) (bool, error) {
+	r.log.Info("Revoking refresh token",
+		logger.String("token_id", id))
+
+	result, err := r.db.ExecContext(ctx, `UPDATE refresh_tokens SET revoked_at = ? WHERE id = ? AND revoked_at IS NULL`,
+		formatUTC(time.Now()), id)
+	if err != nil {
+		r.log.Error("Failed to revoke refresh token",
+			logger.String("token_id", id),
+			logger.Error(err))
+		return false, fmt.Errorf("failed to revoke refresh token: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return rows > 0, nil
+}
+
+// RevokeAll отзывает все refresh токены пользователя и возвращает их количество
+func (r *RefreshTokenRepository) RevokeAll(ctx context.Context, userID string) (int64, error) {
+	r.log.Info("Revoking all refresh tokens",
+		logger.String("user_id", userID))
+
+	result, err := r.db.ExecContext(ctx, `UPDATE refresh_tokens SET revoked_at = ? WHERE user_id = ? AND revoked_at IS NULL`,
+		formatUTC(time.Now()), userID)
+	if err != nil {
+		r.log.Error("Failed to revoke refresh tokens",
+			logger.String("user_id", userID),
+			logger.Error(err))
+		return 0, fmt.Errorf("failed to revoke refresh tokens: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// RevokeSession отзывает refresh токены сессии sessionID
+func (r *RefreshTokenRepository) RevokeSession(ctx context.Context, sessionID string) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE refresh_tokens SET revoked_at = ? WHERE session_id = ? AND revoked_at IS NULL`,
+		formatUTC(time.Now()), sessionID)
+	if err != nil {
+		r.log.Error("Failed to revoke session refresh tokens",
+			logger.String("session_id", sessionID),
+			logger.Error(err))
+		return fmt.Errorf("failed to revoke session refresh tokens: %w", err)
+	}
+	return nil
+}
+
+// DeleteExpired удаляет истекшие к моменту now refresh токены
+func (r *RefreshTokenRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM refresh_tokens WHERE expires_at <= ?`, formatUTC(now))
+	if err != nil {
+		r.log.Error("Failed to delete expired refresh tokens",
+			logger.Error(err))
+		return 0, fmt.Errorf("failed to delete expired refresh tokens: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+func scanRefreshToken(row interface{ Scan(...any) error }) (*entity.RefreshToken, error) {
+	var token entity.RefreshToken
+	var expiresAt, createdAt string
+	var sessionID, revokedAt sql.NullString
+	if err := row.Scan(&token.ID, &token.UserID, &sessionID, &expiresAt, &createdAt, &revokedAt); err != nil {
+		return nil, err
+	}
+	token.SessionID = sessionID.String
+
+	var err error
+	if token.ExpiresAt, err = time.Parse(time.RFC3339, expiresAt); err != nil {
+		return nil, fmt.Errorf("failed to parse expires_at: %w", err)
+	}
+	if token.CreatedAt, err = time.Parse(time.RFC3339, createdAt); err != nil {
+		return nil, fmt.Errorf("failed to parse created_at: %w", err)
+	}
+	if revokedAt.Valid {
+		t, err := time.Parse(time.RFC3339, revokedAt.String)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse revoked_at: %w", err)
+		}
+		token.RevokedAt = &t
+	}
+	return &token, nil
+}
+
+func formatUTC(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+// nullableUTC formatUTC для необязательного времени; nil сохраняется как NULL
+func nullableUTC(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+	return formatUTC(*t)
+}
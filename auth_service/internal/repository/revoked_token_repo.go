@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/kprf42/dolgova/pkg/logger"
+)
+
+// RevokedTokenRepository черный список токенов, отозванных до истечения срока.
+// Даты хранятся в UTC RFC3339, поэтому сравниваются как строки.
+type RevokedTokenRepository struct {
+	db  *sql.DB
+	log *logger.Logger
+}
+
+func NewRevokedTokenRepository(db *sql.DB, log *logger.Logger) *RevokedTokenRepository {
+	return &RevokedTokenRepository{
+		db:  db,
+		log: log,
+	}
+}
+
+// Revoke добавляет токен tokenID в черный список до момента его истечения expiresAt
+func (r *RevokedTokenRepository) Revoke(ctx context.Context, tokenID, userID string, expiresAt time.Time) error {
+	r.log.Info("Revoking token",
+		logger.String("token_id", tokenID),
+		logger.String("user_id", userID))
+
+	query := `INSERT OR IGNORE INTO revoked_tokens (token_id, user_id, expires_at, revoked_at) VALUES (?, ?, ?, ?)`
+	_, err := r.db.ExecContext(ctx, query,
+		tokenID,
+		userID,
+		formatUTC(expiresAt),
+		formatUTC(time.Now()),
+	)
+	if err != nil {
+		r.log.Error("Failed to revoke token",
+			logger.String("token_id", tokenID),
+			logger.Error(err))
+		return fmt.Errorf("failed to revoke token: %w", err)
+	}
+
+	r.log.Info("Successfully revoked token",
+		logger.String("token_id", tokenID))
+	return nil
+}
+
+// RevokeOnce отзывает токен, как Revoke, и сообщает, был ли он отозван именно этим вызовом.
+// Используется для одноразовых токенов: из параллельных попыток использования пройдет одна.
+func (r *RevokedTokenRepository) RevokeOnce(ctx context.Context, tokenID, userID string, expiresAt time.Time) (bool, error) {
+	query := `INSERT OR IGNORE INTO revoked_tokens (token_id, user_id, expires_at, revoked_at) VALUES (?, ?, ?, ?)`
+	result, err := r.db.ExecContext(ctx, query,
+		tokenID,
+		userID,
+		formatUTC(expiresAt),
+		formatUTC(time.Now()),
+	)
+	if err != nil {
+		r.log.Error("Failed to revoke token",
+			logger.String("token_id", tokenID),
+			logger.Error(err))
+		return false, fmt.Errorf("failed to revoke token: %w", err)
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to revoke token: %w", err)
+	}
+	return n == 1, nil
+}
+
+// IsRevoked сообщает, отозван ли токен tokenID
+func (r *RevokedTokenRepository) IsRevoked(ctx context.Context, tokenID string) (bool, error) {
+	var exists bool
+	err := r.db.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM revoked_tokens WHERE token_id = ?)`, tokenID).Scan(&exists)
+	if err != nil {
+		r.log.Error("Failed to check revoked token",
+			logger.String("token_id", tokenID),
+			logger.Error(err))
+		return false, fmt.Errorf("failed to check revoked token: %w", err)
+	}
+	return exists, nil
+}
+
+// DeleteExpired удаляет записи об истекших к моменту now токенах
+func (r *RevokedTokenRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM revoked_tokens WHERE expires_at <= ?`, formatUTC(now))
+	if err != nil {
+		r.log.Error("Failed to delete expired revoked tokens",
+			logger.Error(err))
+		return 0, fmt.Errorf("failed to delete expired revoked tokens: %w", err)
+	}
+	return result.RowsAffected()
+}
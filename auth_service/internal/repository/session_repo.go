@@ -0,0 +1,171 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/kprf42/dolgova/auth_service/internal/entity"
+	"github.com/kprf42/dolgova/pkg/logger"
+)
+
+// SessionRepository хранит сессии пользователей.
+// Даты хранятся в UTC RFC3339, поэтому сравниваются как строки.
+type SessionRepository struct {
+	db  *sql.DB
+	log *logger.Logger
+}
+
+func NewSessionRepository(db *sql.DB, log *logger.Logger) *SessionRepository {
+	return &SessionRepository{
+		db:  db,
+		log: log,
+	}
+}
+
+func (r *SessionRepository) Create(ctx context.Context, session *entity.Session) error {
+	r.log.Info("Creating session",
+		logger.String("session_id", session.ID),
+		logger.String("user_id", session.UserID))
+
+	query := `INSERT INTO sessions (id, user_id, user_agent, ip, created_at, last_seen_at, expires_at) VALUES (?, ?, ?, ?, ?, ?, ?)`
+	_, err := r.db.ExecContext(ctx, query,
+		session.ID,
+		session.UserID,
+		session.UserAgent,
+		session.IP,
+		formatUTC(session.CreatedAt),
+		formatUTC(session.LastSeenAt),
+		formatUTC(session.ExpiresAt),
+	)
+	if err != nil {
+		r.log.Error("Failed to create session",
+			logger.String("session_id", session.ID),
+			logger.Error(err))
+		return fmt.Errorf("failed to create session: %w", err)
+	}
+	return nil
+}
+
+// GetByID возвращает сессию или nil, если ее нет
+func (r *SessionRepository) GetByID(ctx context.Context, id string) (*entity.Session, error) {
+	query := `SELECT id, user_id, user_agent, ip, created_at, last_seen_at, expires_at, revoked_at FROM sessions WHERE id = ?`
+	session, err := scanSession(r.db.QueryRowContext(ctx, query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		r.log.Error("Failed to get session",
+			logger.String("session_id", id),
+			logger.Error(err))
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+	return session, nil
+}
+
+// ListActive возвращает неотозванные и не истекшие к моменту now сессии пользователя, последние активные первыми
+func (r *SessionRepository) ListActive(ctx context.Context, userID string, now time.Time) ([]*entity.Session, error) {
+	query := `SELECT id, user_id, user_agent, ip, created_at, last_seen_at, expires_at, revoked_at FROM sessions
+	          WHERE user_id = ? AND revoked_at IS NULL AND expires_at > ?
+	          ORDER BY last_seen_at DESC`
+	rows, err := r.db.QueryContext(ctx, query, userID, formatUTC(now))
+	if err != nil {
+		r.log.Error("Failed to list sessions",
+			logger.String("user_id", userID),
+			logger.Error(err))
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+	defer rows.Close()
+
+	sessions := []*entity.Session{}
+	for rows.Next() {
+		session, err := scanSession(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan session: %w", err)
+		}
+		sessions = append(sessions, session)
+	}
+	return sessions, rows.Err()
+}
+
+// Touch отмечает активность сессии: адрес и клиент последнего обновления токенов и новый срок
+func (r *SessionRepository) Touch(ctx context.Context, id string, client entity.ClientInfo, expiresAt, now time.Time) error {
+	query := `UPDATE sessions SET ip = ?, user_agent = ?, last_seen_at = ?, expires_at = ? WHERE id = ?`
+	_, err := r.db.ExecContext(ctx, query,
+		client.IP,
+		client.UserAgent,
+		formatUTC(now),
+		formatUTC(expiresAt),
+		id,
+	)
+	if err != nil {
+		r.log.Error("Failed to touch session",
+			logger.String("session_id", id),
+			logger.Error(err))
+		return fmt.Errorf("failed to touch session: %w", err)
+	}
+	return nil
+}
+
+// Revoke отзывает сессию. Возвращает false, если сессия уже отозвана или не найдена.
+func (r *SessionRepository) Revoke(ctx context.Context, id string) (bool, error) {
+	r.log.Info("Revoking session",
+		logger.String("session_id", id))
+
+	result, err := r.db.ExecContext(ctx, `UPDATE sessions SET revoked_at = ? WHERE id = ? AND revoked_at IS NULL`,
+		formatUTC(time.Now()), id)
+	if err != nil {
+		r.log.Error("Failed to revoke session",
+			logger.String("session_id", id),
+			logger.Error(err))
+		return false, fmt.Errorf("failed to revoke session: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return rows > 0, nil
+}
+
+// DeleteExpired удаляет истекшие к моменту now сессии
+func (r *SessionRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM sessions WHERE expires_at <= ?`, formatUTC(now))
+	if err != nil {
+		r.log.Error("Failed to delete expired sessions",
+			logger.Error(err))
+		return 0, fmt.Errorf("failed to delete expired sessions: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+func scanSession(row interface{ Scan(...any) error }) (*entity.Session, error) {
+	var session entity.Session
+	var createdAt, lastSeenAt, expiresAt string
+	var revokedAt sql.NullString
+	err := row.Scan(&session.ID, &session.UserID, &session.UserAgent, &session.IP,
+		&createdAt, &lastSeenAt, &expiresAt, &revokedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if session.CreatedAt, err = time.Parse(time.RFC3339, createdAt); err != nil {
+		return nil, fmt.Errorf("failed to parse created_at: %w", err)
+	}
+	if session.LastSeenAt, err = time.Parse(time.RFC3339, lastSeenAt); err != nil {
+		return nil, fmt.Errorf("failed to parse last_seen_at: %w", err)
+	}
+	if session.ExpiresAt, err = time.Parse(time.RFC3339, expiresAt); err != nil {
+		return nil, fmt.Errorf("failed to parse expires_at: %w", err)
+	}
+	if revokedAt.Valid {
+		t, err := time.Parse(time.RFC3339, revokedAt.String)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse revoked_at: %w", err)
+		}
+		session.RevokedAt = &t
+	}
+	return &session, nil
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/kprf42/dolgova/auth_service/internal/entity"
+	"github.com/kprf42/dolgova/pkg/logger"
+)
+
+// TokenStore состояние токенов, которое должно быть общим для всех реплик сервиса:
+// выданные refresh токены, отозванные jti и счетчики неудачных входов.
+type TokenStore interface {
+	RefreshTokens() RefreshTokenStore
+	Revoked() RevokedTokenStore
+	LoginFailures() LoginFailureStore
+}
+
+// RefreshTokenStore выданные refresh токены
+type RefreshTokenStore interface {
+	Create(ctx context.Context, token *entity.RefreshToken) error
+	// GetByID возвращает refresh токен или nil, если его нет
+	GetByID(ctx context.Context, id string) (*entity.RefreshToken, error)
+	// ListActive возвращает неотозванные и не истекшие к моменту now токены пользователя, новые первыми
+	ListActive(ctx context.Context, userID string, now time.Time) ([]*entity.RefreshToken, error)
+	// Revoke отзывает токен. Возвращает false, если токен уже был отозван или не найден.
+	Revoke(ctx context.Context, id string) (bool, error)
+	// RevokeAll отзывает все токены пользователя и возвращает их количество
+	RevokeAll(ctx context.Context, userID string) (int64, error)
+	// RevokeSession отзывает токены сессии sessionID
+	RevokeSession(ctx context.Context, sessionID string) error
+}
+
+// RevokedTokenStore черный список jti токенов, отозванных до истечения срока
+type RevokedTokenStore interface {
+	// Revoke добавляет токен в черный список до момента его истечения expiresAt
+	Revoke(ctx context.Context, tokenID, userID string, expiresAt time.Time) error
+	// RevokeOnce отзывает токен и сообщает, был ли он отозван именно этим вызовом
+	RevokeOnce(ctx context.Context, tokenID, userID string, expiresAt time.Time) (bool, error)
+	// IsRevoked сообщает, отозван ли токен tokenID
+	IsRevoked(ctx context.Context, tokenID string) (bool, error)
+}
+
+// LoginFailureStore счетчики неудачных входов и блокировки входа
+type LoginFailureStore interface {
+	// LockedUntil возвращает время окончания блокировки или nil, если на момент now вход не заблокирован
+	LockedUntil(ctx context.Context, kind, subject string, now time.Time) (*time.Time, error)
+	// RecordFailure засчитывает неудачный вход и при достижении threshold блокирует вход на lockout
+	RecordFailure(ctx context.Context, kind, subject string, threshold int, lockout time.Duration, now time.Time) (*time.Time, error)
+	// Reset сбрасывает счетчик после успешного входа
+	Reset(ctx context.Context, kind, subject string) error
+}
+
+// SQLiteTokenStore состояние токенов в БД SQLite сервиса. Подходит только для одной реплики:
+// другие реплики не увидят отзыв токенов и блокировки входа.
+type SQLiteTokenStore struct {
+	refresh  *RefreshTokenRepository
+	revoked  *RevokedTokenRepository
+	failures *LoginFailureRepository
+}
+
+func NewSQLiteTokenStore(db *sql.DB, log *logger.Logger) *SQLiteTokenStore {
+	return &SQLiteTokenStore{
+		refresh:  NewRefreshTokenRepository(db, log),
+		revoked:  NewRevokedTokenRepository(db, log),
+		failures: NewLoginFailureRepository(db, log),
+	}
+}
+
+func (s *SQLiteTokenStore) RefreshTokens() RefreshTokenStore { return s.refresh }
+func (s *SQLiteTokenStore) Revoked() RevokedTokenStore       { return s.revoked }
+func (s *SQLiteTokenStore) LoginFailures() LoginFailureStore { return s.failures }
+
+// DeleteExpired удаляет истекшие к моменту now refresh токены и записи черного списка
+func (s *SQLiteTokenStore) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	refresh, err := s.refresh.DeleteExpired(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+	revoked, err := s.revoked.DeleteExpired(ctx, now)
+	return refresh + revoked, err
+}
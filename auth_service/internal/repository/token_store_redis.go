@@ -0,0 +1,380 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/kprf42/dolgova/auth_service/internal/entity"
+	"github.com/kprf42/dolgova/pkg/logger"
+	"github.com/redis/go-redis/v9"
+)
+
+// failureCounterTTL сколько хранится счетчик неудачных входов без новых попыток.
+// В отличие от SQLite счетчики истекают, чтобы не копить ключи заброшенных адресов.
+const failureCounterTTL = 24 * time.Hour
+
+// revokeRefreshScript помечает существующий refresh токен отозванным; 1, если он не был отозван раньше
+var revokeRefreshScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 0 then
+	return 0
+end
+return redis.call('HSETNX', KEYS[1], 'revoked_at', ARGV[1])
+`)
+
+// RedisTokenStore состояние токенов в Redis, общем для всех реплик сервиса.
+// Записи истекают вместе с токенами, поэтому периодическая очистка не нужна.
+type RedisTokenStore struct {
+	refresh  *redisRefreshTokens
+	revoked  *redisRevokedTokens
+	failures *redisLoginFailures
+}
+
+func NewRedisTokenStore(rdb *redis.Client, log *logger.Logger) *RedisTokenStore {
+	return &RedisTokenStore{
+		refresh:  &redisRefreshTokens{rdb: rdb, log: log},
+		revoked:  &redisRevokedTokens{rdb: rdb, log: log},
+		failures: &redisLoginFailures{rdb: rdb, log: log},
+	}
+}
+
+func (s *RedisTokenStore) RefreshTokens() RefreshTokenStore { return s.refresh }
+func (s *RedisTokenStore) Revoked() RevokedTokenStore       { return s.revoked }
+func (s *RedisTokenStore) LoginFailures() LoginFailureStore { return s.failures }
+
+// redisRefreshTokens хранит токен в хеше auth:refresh_token:{id}, а его ID - в индексах
+// пользователя (sorted set по времени истечения) и сессии (set).
+type redisRefreshTokens struct {
+	rdb *redis.Client
+	log *logger.Logger
+}
+
+func refreshTokenKey(id string) string          { return "auth:refresh_token:" + id }
+func userRefreshTokensKey(userID string) string { return "auth:user_refresh_tokens:" + userID }
+func sessionRefreshTokensKey(sessionID string) string {
+	return "auth:session_refresh_tokens:" + sessionID
+}
+
+func (r *redisRefreshTokens) Create(ctx context.Context, token *entity.RefreshToken) error {
+	r.log.Info("Storing refresh token",
+		logger.String("token_id", token.ID),
+		logger.String("user_id", token.UserID))
+
+	key := refreshTokenKey(token.ID)
+	userKey := userRefreshTokensKey(token.UserID)
+	_, err := r.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, key,
+			"user_id", token.UserID,
+			"session_id", token.SessionID,
+			"expires_at", formatUTC(token.ExpiresAt),
+			"created_at", formatUTC(token.CreatedAt),
+		)
+		pipe.ExpireAt(ctx, key, token.ExpiresAt)
+
+		// Новые токены истекают позже старых, поэтому индекс живет до истечения последнего
+		pipe.ZAdd(ctx, userKey, redis.Z{Score: float64(token.ExpiresAt.Unix()), Member: token.ID})
+		pipe.ZRemRangeByScore(ctx, userKey, "-inf", strconv.FormatInt(token.CreatedAt.Unix(), 10))
+		pipe.ExpireAt(ctx, userKey, token.ExpiresAt)
+
+		if token.SessionID != "" {
+			sessionKey := sessionRefreshTokensKey(token.SessionID)
+			pipe.SAdd(ctx, sessionKey, token.ID)
+			pipe.ExpireAt(ctx, sessionKey, token.ExpiresAt)
+		}
+		return nil
+	})
+	if err != nil {
+		r.log.Error("Failed to store refresh token",
+			logger.String("token_id", token.ID),
+			logger.Error(err))
+		return fmt.Errorf("failed to store refresh token: %w", err)
+	}
+	return nil
+}
+
+func (r *redisRefreshTokens) GetByID(ctx context.Context, id string) (*entity.RefreshToken, error) {
+	fields, err := r.rdb.HGetAll(ctx, refreshTokenKey(id)).Result()
+	if err != nil {
+		r.log.Error("Failed to get refresh token",
+			logger.String("token_id", id),
+			logger.Error(err))
+		return nil, fmt.Errorf("failed to get refresh token: %w", err)
+	}
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	return parseRefreshToken(id, fields)
+}
+
+func (r *redisRefreshTokens) ListActive(ctx context.Context, userID string, now time.Time) ([]*entity.RefreshToken, error) {
+	ids, err := r.rdb.ZRangeByScore(ctx, userRefreshTokensKey(userID), &redis.ZRangeBy{
+		Min: "(" + strconv.FormatInt(now.Unix(), 10),
+		Max: "+inf",
+	}).Result()
+	if err != nil {
+		r.log.Error("Failed to list refresh tokens",
+			logger.String("user_id", userID),
+			logger.Error(err))
+		return nil, fmt.Errorf("failed to list refresh tokens: %w", err)
+	}
+
+	cmds := make([]*redis.MapStringStringCmd, len(ids))
+	_, err = r.rdb.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, id := range ids {
+			cmds[i] = pipe.HGetAll(ctx, refreshTokenKey(id))
+		}
+		return nil
+	})
+	if err != nil {
+		r.log.Error("Failed to list refresh tokens",
+			logger.String("user_id", userID),
+			logger.Error(err))
+		return nil, fmt.Errorf("failed to list refresh tokens: %w", err)
+	}
+
+	tokens := []*entity.RefreshToken{}
+	for i, cmd := range cmds {
+		fields := cmd.Val()
+		if len(fields) == 0 {
+			continue
+		}
+		token, err := parseRefreshToken(ids[i], fields)
+		if err != nil {
+			return nil, err
+		}
+		if token.RevokedAt == nil && token.ExpiresAt.After(now) {
+			tokens = append(tokens, token)
+		}
+	}
+	sort.Slice(tokens, func(i, j int) bool {
+		return tokens[i].CreatedAt.After(tokens[j].CreatedAt)
+	})
+	return tokens, nil
+}
+
+func (r *redisRefreshTokens) Revoke(ctx context.Context, id string) (bool, error) {
+	r.log.Info("Revoking refresh token",
+		logger.String("token_id", id))
+
+	revoked, err := r.revoke(ctx, id)
+	if err != nil {
+		r.log.Error("Failed to revoke refresh token",
+			logger.String("token_id", id),
+			logger.Error(err))
+		return false, fmt.Errorf("failed to revoke refresh token: %w", err)
+	}
+	return revoked, nil
+}
+
+func (r *redisRefreshTokens) RevokeAll(ctx context.Context, userID string) (int64, error) {
+	r.log.Info("Revoking all refresh tokens",
+		logger.String("user_id", userID))
+
+	ids, err := r.rdb.ZRange(ctx, userRefreshTokensKey(userID), 0, -1).Result()
+	if err == nil {
+		var count int64
+		if count, err = r.revokeEach(ctx, ids); err == nil {
+			return count, nil
+		}
+	}
+	r.log.Error("Failed to revoke refresh tokens",
+		logger.String("user_id", userID),
+		logger.Error(err))
+	return 0, fmt.Errorf("failed to revoke refresh tokens: %w", err)
+}
+
+func (r *redisRefreshTokens) RevokeSession(ctx context.Context, sessionID string) error {
+	ids, err := r.rdb.SMembers(ctx, sessionRefreshTokensKey(sessionID)).Result()
+	if err == nil {
+		_, err = r.revokeEach(ctx, ids)
+	}
+	if err != nil {
+		r.log.Error("Failed to revoke session refresh tokens",
+			logger.String("session_id", sessionID),
+			logger.Error(err))
+		return fmt.Errorf("failed to revoke session refresh tokens: %w", err)
+	}
+	return nil
+}
+
+// revokeEach отзывает токены ids и возвращает количество отозванных этим вызовом
+func (r *redisRefreshTokens) revokeEach(ctx context.Context, ids []string) (int64, error) {
+	var count int64
+	for _, id := range ids {
+		revoked, err := r.revoke(ctx, id)
+		if err != nil {
+			return count, err
+		}
+		if revoked {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *redisRefreshTokens) revoke(ctx context.Context, id string) (bool, error) {
+	n, err := revokeRefreshScript.Run(ctx, r.rdb, []string{refreshTokenKey(id)}, formatUTC(time.Now())).Int64()
+	return n == 1, err
+}
+
+func parseRefreshToken(id string, fields map[string]string) (*entity.RefreshToken, error) {
+	token := entity.RefreshToken{
+		ID:        id,
+		UserID:    fields["user_id"],
+		SessionID: fields["session_id"],
+	}
+
+	var err error
+	if token.ExpiresAt, err = time.Parse(time.RFC3339, fields["expires_at"]); err != nil {
+		return nil, fmt.Errorf("failed to parse expires_at: %w", err)
+	}
+	if token.CreatedAt, err = time.Parse(time.RFC3339, fields["created_at"]); err != nil {
+		return nil, fmt.Errorf("failed to parse created_at: %w", err)
+	}
+	if revokedAt, ok := fields["revoked_at"]; ok {
+		t, err := time.Parse(time.RFC3339, revokedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse revoked_at: %w", err)
+		}
+		token.RevokedAt = &t
+	}
+	return &token, nil
+}
+
+// redisRevokedTokens хранит отозванный jti в ключе auth:revoked_token:{jti} до истечения токена
+type redisRevokedTokens struct {
+	rdb *redis.Client
+	log *logger.Logger
+}
+
+func revokedTokenKey(tokenID string) string { return "auth:revoked_token:" + tokenID }
+
+func (r *redisRevokedTokens) Revoke(ctx context.Context, tokenID, userID string, expiresAt time.Time) error {
+	r.log.Info("Revoking token",
+		logger.String("token_id", tokenID),
+		logger.String("user_id", userID))
+
+	if _, err := r.RevokeOnce(ctx, tokenID, userID, expiresAt); err != nil {
+		return err
+	}
+
+	r.log.Info("Successfully revoked token",
+		logger.String("token_id", tokenID))
+	return nil
+}
+
+func (r *redisRevokedTokens) RevokeOnce(ctx context.Context, tokenID, userID string, expiresAt time.Time) (bool, error) {
+	// Истекший токен и так не пройдет проверку; минимальный срок нужен, чтобы SETNX отработал
+	ttl := max(time.Until(expiresAt), time.Second)
+	first, err := r.rdb.SetNX(ctx, revokedTokenKey(tokenID), userID, ttl).Result()
+	if err != nil {
+		r.log.Error("Failed to revoke token",
+			logger.String("token_id", tokenID),
+			logger.Error(err))
+		return false, fmt.Errorf("failed to revoke token: %w", err)
+	}
+	return first, nil
+}
+
+func (r *redisRevokedTokens) IsRevoked(ctx context.Context, tokenID string) (bool, error) {
+	n, err := r.rdb.Exists(ctx, revokedTokenKey(tokenID)).Result()
+	if err != nil {
+		r.log.Error("Failed to check revoked token",
+			logger.String("token_id", tokenID),
+			logger.Error(err))
+		return false, fmt.Errorf("failed to check revoked token: %w", err)
+	}
+	return n > 0, nil
+}
+
+// redisLoginFailures хранит счетчик в auth:login_failures:{kind}:{subject},
+// а время окончания блокировки - в auth:login_lock:{kind}:{subject} со сроком блокировки.
+type redisLoginFailures struct {
+	rdb *redis.Client
+	log *logger.Logger
+}
+
+func loginFailuresKey(kind, subject string) string {
+	return "auth:login_failures:" + kind + ":" + subject
+}
+func loginLockKey(kind, subject string) string { return "auth:login_lock:" + kind + ":" + subject }
+
+func (r *redisLoginFailures) LockedUntil(ctx context.Context, kind, subject string, now time.Time) (*time.Time, error) {
+	lockedUntil, err := r.rdb.Get(ctx, loginLockKey(kind, subject)).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		r.log.Error("Failed to get login lock",
+			logger.String("kind", kind),
+			logger.String("subject", subject),
+			logger.Error(err))
+		return nil, fmt.Errorf("failed to get login lock: %w", err)
+	}
+
+	until, err := time.Parse(time.RFC3339, lockedUntil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse locked_until: %w", err)
+	}
+	if !until.After(now) {
+		return nil, nil
+	}
+	return &until, nil
+}
+
+func (r *redisLoginFailures) RecordFailure(ctx context.Context, kind, subject string, threshold int, lockout time.Duration, now time.Time) (*time.Time, error) {
+	key := loginFailuresKey(kind, subject)
+	var incr *redis.IntCmd
+	_, err := r.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		incr = pipe.Incr(ctx, key)
+		pipe.Expire(ctx, key, failureCounterTTL)
+		return nil
+	})
+	if err != nil {
+		r.log.Error("Failed to record login failure",
+			logger.String("kind", kind),
+			logger.String("subject", subject),
+			logger.Error(err))
+		return nil, fmt.Errorf("failed to record login failure: %w", err)
+	}
+
+	failures := int(incr.Val())
+	if failures < threshold {
+		return nil, nil
+	}
+
+	until := now.Add(lockout).UTC().Truncate(time.Second)
+	_, err = r.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, key)
+		pipe.Set(ctx, loginLockKey(kind, subject), formatUTC(until), lockout)
+		return nil
+	})
+	if err != nil {
+		r.log.Error("Failed to lock login",
+			logger.String("kind", kind),
+			logger.String("subject", subject),
+			logger.Error(err))
+		return nil, fmt.Errorf("failed to lock login: %w", err)
+	}
+
+	r.log.Warn("Login locked after failed attempts",
+		logger.String("kind", kind),
+		logger.String("subject", subject),
+		logger.Int("failures", failures),
+		logger.String("locked_until", formatUTC(until)))
+	return &until, nil
+}
+
+func (r *redisLoginFailures) Reset(ctx context.Context, kind, subject string) error {
+	if err := r.rdb.Del(ctx, loginFailuresKey(kind, subject)).Err(); err != nil {
+		r.log.Error("Failed to reset login failures",
+			logger.String("kind", kind),
+			logger.String("subject", subject),
+			logger.Error(err))
+		return fmt.Errorf("failed to reset login failures: %w", err)
+	}
+	return nil
+}
@@ -6,24 +6,44 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/kprf42/dolgova/auth_service/internal/entity"
 	"github.com/kprf42/dolgova/pkg/logger"
 )
 
-type UserRepository struct {
+// UserRepository хранилище пользователей и привязанных к ним внешних аккаунтов.
+// Методы Get* возвращают nil без ошибки, если пользователя нет.
+type UserRepository interface {
+	CreateUser(ctx context.Context, user *entity.User) error
+	GetUserByEmail(ctx context.Context, email string) (*entity.User, error)
+	GetUserByUsername(ctx context.Context, username string) (*entity.User, error)
+	GetUserByID(ctx context.Context, id string) (*entity.User, error)
+	GetUsersByIDs(ctx context.Context, ids []string) ([]*entity.User, error)
+	GetUserByLinkedAccount(ctx context.Context, provider, externalID string) (*entity.User, error)
+	LinkAccount(ctx context.Context, userID, provider, externalID string) error
+	UpdateUser(ctx context.Context, user *entity.User) error
+	// ReplacePasswordHash заменяет хеш пароля, только если он все еще равен oldHash
+	ReplacePasswordHash(ctx context.Context, userID, oldHash, newHash string) error
+	SetStatus(ctx context.Context, userID, status string, until *time.Time, reason string) error
+	HasLinkedAccounts(ctx context.Context, userID string) (bool, error)
+	DeleteUser(ctx context.Context, userID string) error
+}
+
+// SQLiteUserRepository пользователи в той же БД SQLite, что и остальные данные сервиса
+type SQLiteUserRepository struct {
 	db  *sql.DB
 	log *logger.Logger
 }
 
-func NewUserRepository(db *sql.DB, log *logger.Logger) *UserRepository {
-	return &UserRepository{
+func NewSQLiteUserRepository(db *sql.DB, log *logger.Logger) *SQLiteUserRepository {
+	return &SQLiteUserRepository{
 		db:  db,
 		log: log,
 	}
 }
 
-func (r *UserRepository) CreateUser(ctx context.Context, user *entity.User) error {
+func (r *SQLiteUserRepository) CreateUser(ctx context.Context, user *entity.User) error {
 	r.log.Info("Creating new user",
 		logger.String("user_id", user.ID),
 		logger.String("username", user.Username),
@@ -74,24 +94,31 @@ func (r *UserRepository) CreateUser(ctx context.Context, user *entity.User) erro
 	return nil
 }
 
-func (r *UserRepository) GetUserByEmail(ctx context.Context, email string) (*entity.User, error) {
+func (r *SQLiteUserRepository) GetUserByEmail(ctx context.Context, email string) (*entity.User, error) {
 	r.log.Info("Getting user by email",
 		logger.String("email", email))
 
 	query := `
-		SELECT id, username, email, password, role
+		SELECT id, username, email, password, role, display_name, bio, avatar_url, status, status_until, status_reason
 		FROM users
 		WHERE email = ?
 		LIMIT 1
 	`
 
 	var user entity.User
+	var statusUntil sql.NullString
 	err := r.db.QueryRowContext(ctx, query, email).Scan(
 		&user.ID,
 		&user.Username,
 		&user.Email,
 		&user.Password,
 		&user.Role,
+		&user.DisplayName,
+		&user.Bio,
+		&user.AvatarURL,
+		&user.Status,
+		&statusUntil,
+		&user.StatusReason,
 	)
 
 	if err != nil {
@@ -106,8 +133,396 @@ func (r *UserRepository) GetUserByEmail(ctx context.Context, email string) (*ent
 		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
 
+	if err := parseStatusUntil(&user, statusUntil); err != nil {
+		return nil, err
+	}
+
 	r.log.Info("Successfully got user",
 		logger.String("user_id", user.ID),
 		logger.String("email", email))
 	return &user, nil
 }
+
+// GetUserByUsername возвращает пользователя по точному имени или nil, если его нет
+func (r *SQLiteUserRepository) GetUserByUsername(ctx context.Context, username string) (*entity.User, error) {
+	r.log.Info("Getting user by username",
+		logger.String("username", username))
+
+	query := `
+		SELECT id, username, email, password, role, display_name, bio, avatar_url, status, status_until, status_reason
+		FROM users
+		WHERE username = ?
+		LIMIT 1
+	`
+
+	var user entity.User
+	var statusUntil sql.NullString
+	err := r.db.QueryRowContext(ctx, query, username).Scan(
+		&user.ID,
+		&user.Username,
+		&user.Email,
+		&user.Password,
+		&user.Role,
+		&user.DisplayName,
+		&user.Bio,
+		&user.AvatarURL,
+		&user.Status,
+		&statusUntil,
+		&user.StatusReason,
+	)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			r.log.Warn("User not found",
+				logger.String("username", username))
+			return nil, nil
+		}
+		r.log.Error("Failed to get user",
+			logger.String("username", username),
+			logger.Error(err))
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if err := parseStatusUntil(&user, statusUntil); err != nil {
+		return nil, err
+	}
+
+	r.log.Info("Successfully got user",
+		logger.String("user_id", user.ID),
+		logger.String("username", username))
+	return &user, nil
+}
+
+// GetUserByID возвращает пользователя по ID или nil, если его нет
+func (r *SQLiteUserRepository) GetUserByID(ctx context.Context, id string) (*entity.User, error) {
+	r.log.Info("Getting user by ID",
+		logger.String("user_id", id))
+
+	query := `
+		SELECT id, username, email, password, role, display_name, bio, avatar_url, status, status_until, status_reason
+		FROM users
+		WHERE id = ?
+		LIMIT 1
+	`
+
+	var user entity.User
+	var statusUntil sql.NullString
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&user.ID,
+		&user.Username,
+		&user.Email,
+		&user.Password,
+		&user.Role,
+		&user.DisplayName,
+		&user.Bio,
+		&user.AvatarURL,
+		&user.Status,
+		&statusUntil,
+		&user.StatusReason,
+	)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			r.log.Warn("User not found",
+				logger.String("user_id", id))
+			return nil, nil
+		}
+		r.log.Error("Failed to get user",
+			logger.String("user_id", id),
+			logger.Error(err))
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if err := parseStatusUntil(&user, statusUntil); err != nil {
+		return nil, err
+	}
+
+	r.log.Info("Successfully got user",
+		logger.String("user_id", user.ID))
+	return &user, nil
+}
+
+// GetUsersByIDs возвращает пользователей с указанными ID; отсутствующие ID пропускаются
+func (r *SQLiteUserRepository) GetUsersByIDs(ctx context.Context, ids []string) ([]*entity.User, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(ids)), ", ")
+	query := `
+		SELECT id, username, display_name, avatar_url
+		FROM users
+		WHERE id IN (` + placeholders + `)
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		r.log.Error("Failed to get users",
+			logger.Int("count", len(ids)),
+			logger.Error(err))
+		return nil, fmt.Errorf("failed to get users: %w", err)
+	}
+	defer rows.Close()
+
+	users := make([]*entity.User, 0, len(ids))
+	for rows.Next() {
+		var user entity.User
+		if err := rows.Scan(&user.ID, &user.Username, &user.DisplayName, &user.AvatarURL); err != nil {
+			return nil, fmt.Errorf("failed to scan user: %w", err)
+		}
+		users = append(users, &user)
+	}
+	return users, rows.Err()
+}
+
+// GetUserByLinkedAccount возвращает пользователя, привязанного к внешнему аккаунту, или nil
+func (r *SQLiteUserRepository) GetUserByLinkedAccount(ctx context.Context, provider, externalID string) (*entity.User, error) {
+	r.log.Info("Getting user by linked account",
+		logger.String("provider", provider),
+		logger.String("external_id", externalID))
+
+	query := `
+		SELECT u.id, u.username, u.email, u.password, u.role, u.display_name, u.bio, u.avatar_url, u.status, u.status_until, u.status_reason
+		FROM linked_accounts la
+		JOIN users u ON u.id = la.user_id
+		WHERE la.provider = ? AND la.external_id = ?
+		LIMIT 1
+	`
+
+	var user entity.User
+	var statusUntil sql.NullString
+	err := r.db.QueryRowContext(ctx, query, provider, externalID).Scan(
+		&user.ID,
+		&user.Username,
+		&user.Email,
+		&user.Password,
+		&user.Role,
+		&user.DisplayName,
+		&user.Bio,
+		&user.AvatarURL,
+		&user.Status,
+		&statusUntil,
+		&user.StatusReason,
+	)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		r.log.Error("Failed to get user",
+			logger.String("provider", provider),
+			logger.String("external_id", externalID),
+			logger.Error(err))
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if err := parseStatusUntil(&user, statusUntil); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// LinkAccount привязывает внешний аккаунт к пользователю
+func (r *SQLiteUserRepository) LinkAccount(ctx context.Context, userID, provider, externalID string) error {
+	r.log.Info("Linking external account",
+		logger.String("user_id", userID),
+		logger.String("provider", provider),
+		logger.String("external_id", externalID))
+
+	query := `INSERT INTO linked_accounts (provider, external_id, user_id) VALUES (?, ?, ?)`
+	if _, err := r.db.ExecContext(ctx, query, provider, externalID, userID); err != nil {
+		r.log.Error("Failed to link external account",
+			logger.String("user_id", userID),
+			logger.String("provider", provider),
+			logger.Error(err))
+		return fmt.Errorf("failed to link external account: %w", err)
+	}
+	return nil
+}
+
+// UpdateUser сохраняет имя пользователя и поля профиля.
+// Возвращает ErrUsernameTaken, если имя занято, и ErrUserNotFound, если пользователя нет.
+func (r *SQLiteUserRepository) UpdateUser(ctx context.Context, user *entity.User) error {
+	r.log.Info("Updating user",
+		logger.String("user_id", user.ID),
+		logger.String("username", user.Username))
+
+	query := `
+		UPDATE users
+		SET username = ?, display_name = ?, bio = ?, avatar_url = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ?
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		user.Username,
+		user.DisplayName,
+		user.Bio,
+		user.AvatarURL,
+		user.ID,
+	)
+	if err != nil {
+		if strings.Contains(err.Error(), "UNIQUE constraint failed: users.username") {
+			r.log.Warn("Username already taken",
+				logger.String("username", user.Username))
+			return entity.ErrUsernameTaken
+		}
+		r.log.Error("Failed to update user",
+			logger.String("user_id", user.ID),
+			logger.Error(err))
+		return fmt.Errorf("failed to update user: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return entity.ErrUserNotFound
+	}
+
+	r.log.Info("Successfully updated user",
+		logger.String("user_id", user.ID))
+	return nil
+}
+
+func (r *SQLiteUserRepository) ReplacePasswordHash(ctx context.Context, userID, oldHash, newHash string) error {
+	query := `UPDATE users SET password = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ? AND password = ?`
+	if _, err := r.db.ExecContext(ctx, query, newHash, userID, oldHash); err != nil {
+		r.log.Error("Failed to replace password hash",
+			logger.String("user_id", userID),
+			logger.Error(err))
+		return fmt.Errorf("failed to replace password hash: %w", err)
+	}
+	return nil
+}
+
+// SetStatus меняет статус аккаунта. until - окончание приостановки, nil - бессрочно.
+// Возвращает ErrUserNotFound, если пользователя нет.
+func (r *SQLiteUserRepository) SetStatus(ctx context.Context, userID, status string, until *time.Time, reason string) error {
+	r.log.Info("Setting user status",
+		logger.String("user_id", userID),
+		logger.String("status", status))
+
+	query := `UPDATE users SET status = ?, status_until = ?, status_reason = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?`
+	result, err := r.db.ExecContext(ctx, query, status, nullableUTC(until), reason, userID)
+	if err != nil {
+		r.log.Error("Failed to set user status",
+			logger.String("user_id", userID),
+			logger.Error(err))
+		return fmt.Errorf("failed to set user status: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return entity.ErrUserNotFound
+	}
+	return nil
+}
+
+// parseStatusUntil разбирает окончание приостановки, прочитанное из БД
+func parseStatusUntil(user *entity.User, until sql.NullString) error {
+	if !until.Valid {
+		user.StatusUntil = nil
+		return nil
+	}
+	t, err := time.Parse(time.RFC3339, until.String)
+	if err != nil {
+		return fmt.Errorf("failed to parse status_until: %w", err)
+	}
+	user.StatusUntil = &t
+	return nil
+}
+
+// HasLinkedAccounts сообщает, привязаны ли к пользователю внешние аккаунты
+func (r *SQLiteUserRepository) HasLinkedAccounts(ctx context.Context, userID string) (bool, error) {
+	var exists bool
+	err := r.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM linked_accounts WHERE user_id = ?)`, userID).Scan(&exists)
+	if err != nil {
+		r.log.Error("Failed to check linked accounts",
+			logger.String("user_id", userID),
+			logger.Error(err))
+		return false, fmt.Errorf("failed to check linked accounts: %w", err)
+	}
+	return exists, nil
+}
+
+// DeleteUser удаляет пользователя вместе с его токенами обновления, сессиями, внешними аккаунтами,
+// журналом входов и счетчиком неудачных входов. Отозванные токены остаются до истечения срока,
+// чтобы уже выданные access токены не проходили проверку.
+func (r *SQLiteUserRepository) DeleteUser(ctx context.Context, userID string) error {
+	r.log.Info("Deleting user",
+		logger.String("user_id", userID))
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		r.log.Error("Failed to begin delete user transaction",
+			logger.Error(err))
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM linked_accounts WHERE user_id = ?`, userID); err != nil {
+		r.log.Error("Failed to delete linked accounts",
+			logger.String("user_id", userID),
+			logger.Error(err))
+		return fmt.Errorf("failed to delete linked accounts: %w", err)
+	}
+	if err := deleteUserState(ctx, tx, userID); err != nil {
+		r.log.Error("Failed to delete user data",
+			logger.String("user_id", userID),
+			logger.Error(err))
+		return err
+	}
+
+	result, err := tx.ExecContext(ctx, `DELETE FROM users WHERE id = ?`, userID)
+	if err != nil {
+		r.log.Error("Failed to delete user",
+			logger.String("user_id", userID),
+			logger.Error(err))
+		return fmt.Errorf("failed to delete user: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return entity.ErrUserNotFound
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	r.log.Info("Successfully deleted user",
+		logger.String("user_id", userID))
+	return nil
+}
+
+// execer *sql.DB или *sql.Tx
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// deleteUserState удаляет токены обновления, сессии, журнал входов и счетчик неудачных входов
+// пользователя из БД SQLite сервиса
+func deleteUserState(ctx context.Context, db execer, userID string) error {
+	cleanup := []string{
+		`DELETE FROM refresh_tokens WHERE user_id = ?`,
+		`DELETE FROM sessions WHERE user_id = ?`,
+		`DELETE FROM auth_events WHERE user_id = ?`,
+		`DELETE FROM login_failures WHERE kind = 'user' AND subject = ?`,
+	}
+	for _, query := range cleanup {
+		if _, err := db.ExecContext(ctx, query, userID); err != nil {
+			return fmt.Errorf("failed to delete user data: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,321 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/kprf42/dolgova/auth_service/internal/entity"
+	"github.com/kprf42/dolgova/pkg/logger"
+	"github.com/lib/pq"
+)
+
+// uniqueViolation код ошибки PostgreSQL при нарушении уникальности
+const uniqueViolation = "23505"
+
+// PostgresUserRepository пользователи и внешние аккаунты в PostgreSQL. Переносится только эта часть данных:
+// токены, сессии, API ключи и журналы остаются в БД SQLite сервиса (state).
+type PostgresUserRepository struct {
+	db    *sql.DB
+	state *sql.DB
+	log   *logger.Logger
+}
+
+// NewPostgresUserRepository создает хранилище пользователей в PostgreSQL db.
+// state - БД SQLite сервиса, из которой при удалении пользователя удаляются его токены и сессии.
+func NewPostgresUserRepository(db, state *sql.DB, log *logger.Logger) *PostgresUserRepository {
+	return &PostgresUserRepository{
+		db:    db,
+		state: state,
+		log:   log,
+	}
+}
+
+// userColumns колонки users в порядке scanUser
+const userColumns = `id, username, email, password, role, display_name, bio, avatar_url, status, status_until, status_reason`
+
+func (r *PostgresUserRepository) CreateUser(ctx context.Context, user *entity.User) error {
+	r.log.Info("Creating new user",
+		logger.String("user_id", user.ID),
+		logger.String("username", user.Username),
+		logger.String("email", user.Email),
+		logger.String("role", user.Role))
+
+	query := `
+		INSERT INTO users (id, username, email, password, role)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		user.ID,
+		user.Username,
+		user.Email,
+		user.Password,
+		user.Role,
+	)
+	if err != nil {
+		if isUniqueViolation(err) {
+			r.log.Warn("Email already exists",
+				logger.String("email", user.Email))
+			return fmt.Errorf("email already exists")
+		}
+		r.log.Error("Failed to create user",
+			logger.String("user_id", user.ID),
+			logger.Error(err))
+		return fmt.Errorf("failed to create user: %w", err)
+	}
+
+	r.log.Info("Successfully created user",
+		logger.String("user_id", user.ID))
+	return nil
+}
+
+func (r *PostgresUserRepository) GetUserByEmail(ctx context.Context, email string) (*entity.User, error) {
+	return r.getUser(ctx, `SELECT `+userColumns+` FROM users WHERE email = $1 LIMIT 1`, email)
+}
+
+// GetUserByUsername возвращает пользователя по точному имени или nil, если его нет
+func (r *PostgresUserRepository) GetUserByUsername(ctx context.Context, username string) (*entity.User, error) {
+	return r.getUser(ctx, `SELECT `+userColumns+` FROM users WHERE username = $1 LIMIT 1`, username)
+}
+
+// GetUserByID возвращает пользователя по ID или nil, если его нет
+func (r *PostgresUserRepository) GetUserByID(ctx context.Context, id string) (*entity.User, error) {
+	return r.getUser(ctx, `SELECT `+userColumns+` FROM users WHERE id = $1 LIMIT 1`, id)
+}
+
+// GetUsersByIDs возвращает пользователей с указанными ID; отсутствующие ID пропускаются
+func (r *PostgresUserRepository) GetUsersByIDs(ctx context.Context, ids []string) ([]*entity.User, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	query := `SELECT id, username, display_name, avatar_url FROM users WHERE id = ANY($1)`
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		r.log.Error("Failed to get users",
+			logger.Int("count", len(ids)),
+			logger.Error(err))
+		return nil, fmt.Errorf("failed to get users: %w", err)
+	}
+	defer rows.Close()
+
+	users := make([]*entity.User, 0, len(ids))
+	for rows.Next() {
+		var user entity.User
+		if err := rows.Scan(&user.ID, &user.Username, &user.DisplayName, &user.AvatarURL); err != nil {
+			return nil, fmt.Errorf("failed to scan user: %w", err)
+		}
+		users = append(users, &user)
+	}
+	return users, rows.Err()
+}
+
+// GetUserByLinkedAccount возвращает пользователя, привязанного к внешнему аккаунту, или nil
+func (r *PostgresUserRepository) GetUserByLinkedAccount(ctx context.Context, provider, externalID string) (*entity.User, error) {
+	query := `
+		SELECT u.id, u.username, u.email, u.password, u.role, u.display_name, u.bio, u.avatar_url, u.status, u.status_until, u.status_reason
+		FROM linked_accounts la
+		JOIN users u ON u.id = la.user_id
+		WHERE la.provider = $1 AND la.external_id = $2
+		LIMIT 1
+	`
+	return r.getUser(ctx, query, provider, externalID)
+}
+
+// LinkAccount привязывает внешний аккаунт к пользователю
+func (r *PostgresUserRepository) LinkAccount(ctx context.Context, userID, provider, externalID string) error {
+	r.log.Info("Linking external account",
+		logger.String("user_id", userID),
+		logger.String("provider", provider),
+		logger.String("external_id", externalID))
+
+	query := `INSERT INTO linked_accounts (provider, external_id, user_id) VALUES ($1, $2, $3)`
+	if _, err := r.db.ExecContext(ctx, query, provider, externalID, userID); err != nil {
+		r.log.Error("Failed to link external account",
+			logger.String("user_id", userID),
+			logger.String("provider", provider),
+			logger.Error(err))
+		return fmt.Errorf("failed to link external account: %w", err)
+	}
+	return nil
+}
+
+// UpdateUser сохраняет имя пользователя и поля профиля.
+// Возвращает ErrUsernameTaken, если имя занято, и ErrUserNotFound, если пользователя нет.
+func (r *PostgresUserRepository) UpdateUser(ctx context.Context, user *entity.User) error {
+	r.log.Info("Updating user",
+		logger.String("user_id", user.ID),
+		logger.String("username", user.Username))
+
+	query := `
+		UPDATE users
+		SET username = $1, display_name = $2, bio = $3, avatar_url = $4, updated_at = now()
+		WHERE id = $5
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		user.Username,
+		user.DisplayName,
+		user.Bio,
+		user.AvatarURL,
+		user.ID,
+	)
+	if err != nil {
+		if isUniqueViolation(err) {
+			r.log.Warn("Username already taken",
+				logger.String("username", user.Username))
+			return entity.ErrUsernameTaken
+		}
+		r.log.Error("Failed to update user",
+			logger.String("user_id", user.ID),
+			logger.Error(err))
+		return fmt.Errorf("failed to update user: %w", err)
+	}
+
+	return requireAffected(result)
+}
+
+func (r *PostgresUserRepository) ReplacePasswordHash(ctx context.Context, userID, oldHash, newHash string) error {
+	query := `UPDATE users SET password = $1, updated_at = now() WHERE id = $2 AND password = $3`
+	if _, err := r.db.ExecContext(ctx, query, newHash, userID, oldHash); err != nil {
+		r.log.Error("Failed to replace password hash",
+			logger.String("user_id", userID),
+			logger.Error(err))
+		return fmt.Errorf("failed to replace password hash: %w", err)
+	}
+	return nil
+}
+
+// SetStatus меняет статус аккаунта. until - окончание приостановки, nil - бессрочно.
+// Возвращает ErrUserNotFound, если пользователя нет.
+func (r *PostgresUserRepository) SetStatus(ctx context.Context, userID, status string, until *time.Time, reason string) error {
+	r.log.Info("Setting user status",
+		logger.String("user_id", userID),
+		logger.String("status", status))
+
+	query := `UPDATE users SET status = $1, status_until = $2, status_reason = $3, updated_at = now() WHERE id = $4`
+	result, err := r.db.ExecContext(ctx, query, status, until, reason, userID)
+	if err != nil {
+		r.log.Error("Failed to set user status",
+			logger.String("user_id", userID),
+			logger.Error(err))
+		return fmt.Errorf("failed to set user status: %w", err)
+	}
+
+	return requireAffected(result)
+}
+
+// HasLinkedAccounts сообщает, привязаны ли к пользователю внешние аккаунты
+func (r *PostgresUserRepository) HasLinkedAccounts(ctx context.Context, userID string) (bool, error) {
+	var exists bool
+	err := r.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM linked_accounts WHERE user_id = $1)`, userID).Scan(&exists)
+	if err != nil {
+		r.log.Error("Failed to check linked accounts",
+			logger.String("user_id", userID),
+			logger.Error(err))
+		return false, fmt.Errorf("failed to check linked accounts: %w", err)
+	}
+	return exists, nil
+}
+
+// DeleteUser удаляет пользователя и его внешние аккаунты, затем токены обновления, сессии,
+// журнал входов и счетчик неудачных входов из БД сервиса. Отозванные токены остаются до истечения срока.
+func (r *PostgresUserRepository) DeleteUser(ctx context.Context, userID string) error {
+	r.log.Info("Deleting user",
+		logger.String("user_id", userID))
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		r.log.Error("Failed to begin delete user transaction",
+			logger.Error(err))
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM linked_accounts WHERE user_id = $1`, userID); err != nil {
+		r.log.Error("Failed to delete linked accounts",
+			logger.String("user_id", userID),
+			logger.Error(err))
+		return fmt.Errorf("failed to delete linked accounts: %w", err)
+	}
+
+	result, err := tx.ExecContext(ctx, `DELETE FROM users WHERE id = $1`, userID)
+	if err != nil {
+		r.log.Error("Failed to delete user",
+			logger.String("user_id", userID),
+			logger.Error(err))
+		return fmt.Errorf("failed to delete user: %w", err)
+	}
+	if err := requireAffected(result); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	// Пользователя уже нет, поэтому оставшиеся токены не пройдут проверку даже при сбое очистки
+	if err := deleteUserState(ctx, r.state, userID); err != nil {
+		r.log.Error("Failed to delete user data",
+			logger.String("user_id", userID),
+			logger.Error(err))
+		return err
+	}
+
+	r.log.Info("Successfully deleted user",
+		logger.String("user_id", userID))
+	return nil
+}
+
+// getUser выполняет запрос одного пользователя; nil, если его нет
+func (r *PostgresUserRepository) getUser(ctx context.Context, query string, args ...any) (*entity.User, error) {
+	var user entity.User
+	var statusUntil sql.NullTime
+	err := r.db.QueryRowContext(ctx, query, args...).Scan(
+		&user.ID,
+		&user.Username,
+		&user.Email,
+		&user.Password,
+		&user.Role,
+		&user.DisplayName,
+		&user.Bio,
+		&user.AvatarURL,
+		&user.Status,
+		&statusUntil,
+		&user.StatusReason,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		r.log.Error("Failed to get user",
+			logger.Error(err))
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if statusUntil.Valid {
+		until := statusUntil.Time.UTC()
+		user.StatusUntil = &until
+	}
+	return &user, nil
+}
+
+// requireAffected возвращает ErrUserNotFound, если запрос не изменил ни одной строки
+func requireAffected(result sql.Result) error {
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return entity.ErrUserNotFound
+	}
+	return nil
+}
+
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == uniqueViolation
+}
@@ -0,0 +1,92 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kprf42/dolgova/auth_service/internal/entity"
+	"github.com/kprf42/dolgova/pkg/audit"
+	"github.com/kprf42/dolgova/pkg/logger"
+)
+
+// ContentAnonymizer обезличивает контент пользователя в других сервисах (посты и сообщения форума)
+type ContentAnonymizer interface {
+	AnonymizeUser(ctx context.Context, userID, accessToken string) error
+}
+
+// AccountDeletion удаление аккаунта по запросу пользователя (право на удаление данных)
+type AccountDeletion struct {
+	auth    *AuthUseCase
+	content ContentAnonymizer
+}
+
+func NewAccountDeletion(authUC *AuthUseCase, content ContentAnonymizer) *AccountDeletion {
+	return &AccountDeletion{
+		auth:    authUC,
+		content: content,
+	}
+}
+
+// Delete удаляет аккаунт userID. Пароль подтверждает удаление; его можно не передавать,
+// если пользователь входит через внешний аккаунт и пароля не задавал.
+// Сначала обезличивается контент на форуме с токеном accessToken: если форум недоступен,
+// аккаунт не удаляется, чтобы запрос можно было повторить.
+func (d *AccountDeletion) Delete(ctx context.Context, userID, password, accessToken string) error {
+	// Администратор под имперсонацией не может удалить чужой аккаунт
+	if _, ok := audit.ActingAdminFromContext(ctx); ok {
+		return entity.ErrImpersonationForbidden
+	}
+
+	user, err := d.auth.GetProfile(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if password == "" {
+		linked, err := d.auth.repo.HasLinkedAccounts(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if !linked {
+			return entity.ErrWrongPassword
+		}
+	} else if err := d.auth.hasher.Compare(user.Password, password); err != nil {
+		d.auth.log.Warn("Account deletion with wrong password",
+			logger.String("user_id", userID))
+		return entity.ErrWrongPassword
+	}
+
+	if err := d.content.AnonymizeUser(ctx, userID, accessToken); err != nil {
+		d.auth.log.Error("Failed to anonymize user content",
+			logger.String("user_id", userID),
+			logger.Error(err))
+		return fmt.Errorf("%w: %v", entity.ErrContentAnonymization, err)
+	}
+
+	// Сессии отзываются до удаления, чтобы выданные access токены перестали проходить проверку
+	sessions, err := d.auth.sessions.ListActive(ctx, userID, time.Now())
+	if err != nil {
+		return err
+	}
+	for _, session := range sessions {
+		if err := d.auth.revokeSession(ctx, session); err != nil {
+			return err
+		}
+	}
+
+	if d.auth.devices != nil {
+		if err := d.auth.devices.forget(ctx, userID); err != nil {
+			return err
+		}
+	}
+
+	if err := d.auth.repo.DeleteUser(ctx, userID); err != nil {
+		return err
+	}
+
+	d.auth.log.Info("Deleted user account",
+		logger.String("user_id", userID),
+		logger.Int("sessions", len(sessions)))
+	return nil
+}
@@ -0,0 +1,241 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/kprf42/dolgova/auth_service/internal/entity"
+	"github.com/kprf42/dolgova/auth_service/internal/repository"
+	"github.com/kprf42/dolgova/pkg/audit"
+	"github.com/kprf42/dolgova/pkg/logger"
+)
+
+// apiKeyPrefix начало ключа; по нему ключ легко найти в логах и конфигурации
+const apiKeyPrefix = "dk_"
+
+// apiKeyTouchInterval как часто обновляется время последнего использования ключа
+const apiKeyTouchInterval = time.Minute
+
+// APIKeyRequest параметры нового ключа
+type APIKeyRequest struct {
+	Name   string        // Сервис-владелец ключа
+	Scopes []string      // Разрешения из entity.APIKeyScopes
+	TTL    time.Duration // Срок действия; 0 - бессрочно
+}
+
+// APIKeys ключи внутренних сервисов. Ключ имеет вид dk_<id>_<secret>; в БД хранится
+// только SHA-256 секрета, поэтому ключ показывается один раз при выпуске.
+type APIKeys struct {
+	auth *AuthUseCase
+	repo *repository.APIKeyRepository
+}
+
+func NewAPIKeys(authUC *AuthUseCase, repo *repository.APIKeyRepository) *APIKeys {
+	return &APIKeys{
+		auth: authUC,
+		repo: repo,
+	}
+}
+
+// Issue выпускает ключ от имени администратора adminID. Возвращает ключ и его полное значение.
+func (k *APIKeys) Issue(ctx context.Context, adminID string, req APIKeyRequest) (*entity.APIKey, string, error) {
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" || len(req.Scopes) == 0 || req.TTL < 0 {
+		return nil, "", entity.ErrInvalidAPIKeyRequest
+	}
+	for _, scope := range req.Scopes {
+		if !slices.Contains(entity.APIKeyScopes, scope) {
+			return nil, "", entity.ErrInvalidAPIKeyRequest
+		}
+	}
+
+	admin, err := k.admin(ctx, adminID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	key, raw, err := k.create(ctx, admin.ID, req.Name, req.Scopes, req.TTL, "")
+	if err != nil {
+		return nil, "", err
+	}
+	k.audit(ctx, admin.ID, "api_key.issue", key.ID, key.Name+": "+strings.Join(key.Scopes, ","))
+	return key, raw, nil
+}
+
+// List возвращает все ключи без секретов
+func (k *APIKeys) List(ctx context.Context, adminID string) ([]*entity.APIKey, error) {
+	if _, err := k.admin(ctx, adminID); err != nil {
+		return nil, err
+	}
+	return k.repo.List(ctx)
+}
+
+// Rotate выпускает замену ключа id с теми же именем и разрешениями. Старый ключ
+// продолжает работать grace, чтобы сервис успел переключиться; при grace 0 отзывается сразу.
+func (k *APIKeys) Rotate(ctx context.Context, adminID, id string, grace time.Duration) (*entity.APIKey, string, error) {
+	if grace < 0 {
+		return nil, "", entity.ErrInvalidAPIKeyRequest
+	}
+
+	admin, err := k.admin(ctx, adminID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	now := time.Now()
+	old, err := k.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, "", err
+	}
+	if old == nil || !old.Active(now) {
+		return nil, "", entity.ErrAPIKeyNotFound
+	}
+
+	// Срок замены отсчитывается заново от момента ротации
+	var ttl time.Duration
+	if old.ExpiresAt != nil {
+		ttl = old.ExpiresAt.Sub(old.CreatedAt)
+	}
+	key, raw, err := k.create(ctx, admin.ID, old.Name, old.Scopes, ttl, old.ID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if grace == 0 {
+		err = k.repo.Revoke(ctx, old.ID, now)
+	} else {
+		err = k.repo.Expire(ctx, old.ID, now.Add(grace))
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	k.audit(ctx, admin.ID, "api_key.rotate", old.ID, "replaced by "+key.ID+", grace "+grace.String())
+	return key, raw, nil
+}
+
+// Revoke отзывает ключ id
+func (k *APIKeys) Revoke(ctx context.Context, adminID, id string) error {
+	admin, err := k.admin(ctx, adminID)
+	if err != nil {
+		return err
+	}
+	if err := k.repo.Revoke(ctx, id, time.Now()); err != nil {
+		return err
+	}
+	k.audit(ctx, admin.ID, "api_key.revoke", id, "")
+	return nil
+}
+
+// Authenticate проверяет ключ raw и наличие у него разрешения scope.
+// Возвращает ErrInvalidAPIKey для неизвестного, отозванного или истекшего ключа и ErrAPIKeyScope без разрешения.
+func (k *APIKeys) Authenticate(ctx context.Context, raw, scope string) (*entity.APIKey, error) {
+	id, secret, ok := parseAPIKey(raw)
+	if !ok {
+		return nil, entity.ErrInvalidAPIKey
+	}
+
+	key, err := k.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if key == nil || subtle.ConstantTimeCompare([]byte(hashAPISecret(secret)), []byte(key.SecretHash)) != 1 || !key.Active(now) {
+		k.auth.log.Warn("Invalid api key used",
+			logger.String("key_id", id))
+		return nil, entity.ErrInvalidAPIKey
+	}
+	if !key.HasScope(scope) {
+		k.auth.log.Warn("Api key without required scope",
+			logger.String("key_id", id),
+			logger.String("scope", scope))
+		return nil, entity.ErrAPIKeyScope
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) > apiKeyTouchInterval {
+		k.repo.Touch(ctx, key.ID, now)
+	}
+	return key, nil
+}
+
+// admin проверяет администратора; под имперсонацией ключами управлять нельзя
+func (k *APIKeys) admin(ctx context.Context, adminID string) (*entity.User, error) {
+	if _, ok := audit.ActingAdminFromContext(ctx); ok {
+		return nil, entity.ErrImpersonationForbidden
+	}
+	return k.auth.requireAdmin(ctx, adminID)
+}
+
+func (k *APIKeys) create(ctx context.Context, adminID, name string, scopes []string, ttl time.Duration, rotatedFrom string) (*entity.APIKey, string, error) {
+	id, err := randomHex(8)
+	if err != nil {
+		return nil, "", err
+	}
+	secret, err := randomHex(32)
+	if err != nil {
+		return nil, "", err
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	key := &entity.APIKey{
+		ID:          id,
+		Name:        name,
+		SecretHash:  hashAPISecret(secret),
+		Scopes:      scopes,
+		CreatedBy:   adminID,
+		CreatedAt:   now,
+		RotatedFrom: rotatedFrom,
+	}
+	if ttl > 0 {
+		expiresAt := now.Add(ttl)
+		key.ExpiresAt = &expiresAt
+	}
+	if err := k.repo.Create(ctx, key); err != nil {
+		return nil, "", err
+	}
+	return key, apiKeyPrefix + id + "_" + secret, nil
+}
+
+func (k *APIKeys) audit(ctx context.Context, adminID, action, keyID, details string) {
+	err := k.auth.audit.Write(ctx, &audit.Entry{
+		ActorID:    adminID,
+		Action:     action,
+		TargetType: "api_key",
+		TargetID:   keyID,
+		Details:    details,
+	})
+	if err != nil {
+		k.auth.log.Error("Failed to write audit entry",
+			logger.String("key_id", keyID),
+			logger.Error(err))
+	}
+}
+
+// parseAPIKey разбирает dk_<id>_<secret>
+func parseAPIKey(raw string) (id, secret string, ok bool) {
+	rest, found := strings.CutPrefix(raw, apiKeyPrefix)
+	if !found {
+		return "", "", false
+	}
+	id, secret, found = strings.Cut(rest, "_")
+	return id, secret, found && id != "" && secret != ""
+}
+
+func hashAPISecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
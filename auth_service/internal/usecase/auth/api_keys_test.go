@@ -0,0 +1,159 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kprf42/dolgova/auth_service/internal/entity"
+	"github.com/kprf42/dolgova/auth_service/internal/repository"
+)
+
+func TestAPIKeyAuthenticate(t *testing.T) {
+	ctx := context.Background()
+	uc, db := newTestAuthUseCase(t, LockoutPolicy{})
+	admin := registerTestUser(t, uc, "admin")
+	if _, err := db.Exec(`UPDATE users SET role = 'admin' WHERE id = ?`, admin.ID); err != nil {
+		t.Fatalf("make admin: %v", err)
+	}
+	keys := NewAPIKeys(uc, repository.NewAPIKeyRepository(db, uc.log))
+
+	issue := func(t *testing.T, scopes ...string) (*entity.APIKey, string) {
+		t.Helper()
+		key, raw, err := keys.Issue(ctx, admin.ID, APIKeyRequest{Name: "forum", Scopes: scopes})
+		if err != nil {
+			t.Fatalf("issue key: %v", err)
+		}
+		return key, raw
+	}
+
+	tests := []struct {
+		name    string
+		key     func(t *testing.T) string
+		scope   string
+		wantErr error
+	}{
+		{
+			name: "key with scope",
+			key: func(t *testing.T) string {
+				_, raw := issue(t, entity.ScopeTokensValidate)
+				return raw
+			},
+			scope: entity.ScopeTokensValidate,
+		},
+		{
+			name: "key without scope",
+			key: func(t *testing.T) string {
+				_, raw := issue(t, entity.ScopeTokensValidate)
+				return raw
+			},
+			scope:   entity.ScopeUsersRead,
+			wantErr: entity.ErrAPIKeyScope,
+		},
+		{
+			name: "wrong secret",
+			key: func(t *testing.T) string {
+				key, _ := issue(t, entity.ScopeUsersRead)
+				return apiKeyPrefix + key.ID + "_" + "0000"
+			},
+			scope:   entity.ScopeUsersRead,
+			wantErr: entity.ErrInvalidAPIKey,
+		},
+		{
+			name:    "malformed key",
+			key:     func(t *testing.T) string { return "Bearer something" },
+			scope:   entity.ScopeUsersRead,
+			wantErr: entity.ErrInvalidAPIKey,
+		},
+		{
+			name: "revoked key",
+			key: func(t *testing.T) string {
+				key, raw := issue(t, entity.ScopeUsersRead)
+				if err := keys.Revoke(ctx, admin.ID, key.ID); err != nil {
+					t.Fatalf("revoke: %v", err)
+				}
+				return raw
+			},
+			scope:   entity.ScopeUsersRead,
+			wantErr: entity.ErrInvalidAPIKey,
+		},
+		{
+			name: "rotated key without grace",
+			key: func(t *testing.T) string {
+				key, raw := issue(t, entity.ScopeUsersRead)
+				if _, _, err := keys.Rotate(ctx, admin.ID, key.ID, 0); err != nil {
+					t.Fatalf("rotate: %v", err)
+				}
+				return raw
+			},
+			scope:   entity.ScopeUsersRead,
+			wantErr: entity.ErrInvalidAPIKey,
+		},
+		{
+			name: "rotated key within grace",
+			key: func(t *testing.T) string {
+				key, raw := issue(t, entity.ScopeUsersRead)
+				if _, _, err := keys.Rotate(ctx, admin.ID, key.ID, time.Hour); err != nil {
+					t.Fatalf("rotate: %v", err)
+				}
+				return raw
+			},
+			scope: entity.ScopeUsersRead,
+		},
+		{
+			name: "replacement keeps scopes",
+			key: func(t *testing.T) string {
+				key, _ := issue(t, entity.ScopeUsersRead)
+				_, raw, err := keys.Rotate(ctx, admin.ID, key.ID, 0)
+				if err != nil {
+					t.Fatalf("rotate: %v", err)
+				}
+				return raw
+			},
+			scope: entity.ScopeUsersRead,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := keys.Authenticate(ctx, tt.key(t), tt.scope)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAPIKeyIssue(t *testing.T) {
+	ctx := context.Background()
+	uc, db := newTestAuthUseCase(t, LockoutPolicy{})
+	admin := registerTestUser(t, uc, "admin")
+	user := registerTestUser(t, uc, "alice")
+	if _, err := db.Exec(`UPDATE users SET role = 'admin' WHERE id = ?`, admin.ID); err != nil {
+		t.Fatalf("make admin: %v", err)
+	}
+	keys := NewAPIKeys(uc, repository.NewAPIKeyRepository(db, uc.log))
+
+	tests := []struct {
+		name    string
+		actor   string
+		req     APIKeyRequest
+		wantErr error
+	}{
+		{"valid", admin.ID, APIKeyRequest{Name: "forum", Scopes: entity.APIKeyScopes}, nil},
+		{"unknown scope", admin.ID, APIKeyRequest{Name: "forum", Scopes: []string{"users:write"}}, entity.ErrInvalidAPIKeyRequest},
+		{"no scopes", admin.ID, APIKeyRequest{Name: "forum"}, entity.ErrInvalidAPIKeyRequest},
+		{"empty name", admin.ID, APIKeyRequest{Name: " ", Scopes: entity.APIKeyScopes}, entity.ErrInvalidAPIKeyRequest},
+		{"not admin", user.ID, APIKeyRequest{Name: "forum", Scopes: entity.APIKeyScopes}, entity.ErrNotAdmin},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := keys.Issue(ctx, tt.actor, tt.req)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -8,29 +8,81 @@ import (
 	"strings"
 	"time"
 
+	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
 	"github.com/kprf42/dolgova/auth_service/internal/entity"
 	"github.com/kprf42/dolgova/auth_service/internal/repository"
 	"github.com/kprf42/dolgova/auth_service/internal/usecase/jwt"
+	"github.com/kprf42/dolgova/pkg/audit"
 	"github.com/kprf42/dolgova/pkg/logger"
-	"golang.org/x/crypto/bcrypt"
 )
 
+// ImpersonationTTL время жизни токена имперсонации
+const ImpersonationTTL = 15 * time.Minute
+
+// GuestTokenTTL время жизни гостевого токена
+const GuestTokenTTL = 30 * time.Minute
+
+// LockoutPolicy блокировка входа после серии неудачных попыток
+type LockoutPolicy struct {
+	MaxFailures   int           // Неудачных попыток подряд для пользователя; 0 - блокировка выключена
+	MaxIPFailures int           // Неудачных попыток подряд с одного адреса; 0 - не считать по адресу
+	Duration      time.Duration // Длительность блокировки
+}
+
+// Enabled включена ли блокировка
+func (p LockoutPolicy) Enabled() bool {
+	return p.MaxFailures > 0 && p.Duration > 0
+}
+
 type AuthUseCase struct {
-	repo repository.UserRepository
-	jwt  *jwt.JWTService
-	log  *logger.Logger
+	repo          repository.UserRepository
+	refreshTokens repository.RefreshTokenStore
+	sessions      *repository.SessionRepository
+	events        *repository.AuthEventRepository
+	revoked       repository.RevokedTokenStore
+	failures      repository.LoginFailureStore
+	lockout       LockoutPolicy
+	passwords     *PasswordPolicy
+	hasher        *PasswordHasher
+	invites       *Invites // Не nil - регистрация только по приглашениям
+	devices       *Devices // Не nil - письма о входе с нового устройства
+	audit         *audit.Store
+	jwt           *jwt.JWTService
+	log           *logger.Logger
 }
 
-func NewAuthUseCase(repo repository.UserRepository, jwtSecret string, accessExpiry, refreshExpiry time.Duration, log *logger.Logger) *AuthUseCase {
+func NewAuthUseCase(repo repository.UserRepository, tokens repository.TokenStore, sessions *repository.SessionRepository, events *repository.AuthEventRepository, lockout LockoutPolicy, passwords *PasswordPolicy, auditLog *audit.Store, jwtService *jwt.JWTService, log *logger.Logger) *AuthUseCase {
 	return &AuthUseCase{
-		repo: repo,
-		jwt:  jwt.NewJWTService(jwtSecret, accessExpiry, refreshExpiry),
-		log:  log,
+		repo:          repo,
+		refreshTokens: tokens.RefreshTokens(),
+		sessions:      sessions,
+		events:        events,
+		revoked:       tokens.Revoked(),
+		failures:      tokens.LoginFailures(),
+		lockout:       lockout,
+		passwords:     passwords,
+		hasher:        &PasswordHasher{},
+		audit:         auditLog,
+		jwt:           jwtService,
+		log:           log,
 	}
 }
 
-func (uc *AuthUseCase) Register(ctx context.Context, username, email, password string) (*entity.User, error) {
+// PepperPasswords включает перец: новые хеши паролей считаются текущим перцем hasher,
+// а хеши без перца или со старым перцем пересчитываются при следующем входе
+func (uc *AuthUseCase) PepperPasswords(hasher *PasswordHasher) {
+	uc.hasher = hasher
+}
+
+// RequireInvites включает регистрацию только по приглашениям: Register требует код из invites,
+// а через внешние аккаунты входят только уже зарегистрированные пользователи
+func (uc *AuthUseCase) RequireInvites(invites *Invites) {
+	uc.invites = invites
+}
+
+// Register создает пользователя. inviteCode обязателен только в режиме регистрации по приглашениям.
+func (uc *AuthUseCase) Register(ctx context.Context, username, email, password, inviteCode string) (*entity.User, error) {
 	uc.log.Info("Starting user registration",
 		logger.String("username", username),
 		logger.String("email", email))
@@ -49,9 +101,10 @@ func (uc *AuthUseCase) Register(ctx context.Context, username, email, password s
 		return nil, entity.ErrInvalidEmail
 	}
 
-	if len(password) < 8 {
-		uc.log.Warn("Weak password provided")
-		return nil, entity.ErrWeakPassword
+	if err := uc.passwords.Validate(password); err != nil {
+		uc.log.Warn("Weak password provided",
+			logger.Error(err))
+		return nil, err
 	}
 
 	// Проверка существования пользователя
@@ -69,11 +122,11 @@ func (uc *AuthUseCase) Register(ctx context.Context, username, email, password s
 	}
 
 	// Хеширование пароля
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	hashedPassword, err := uc.hasher.Hash(password)
 	if err != nil {
 		uc.log.Error("Failed to hash password",
 			logger.Error(err))
-		return nil, fmt.Errorf("failed to hash password: %w", err)
+		return nil, err
 	}
 
 	// Создание пользователя
@@ -81,7 +134,7 @@ func (uc *AuthUseCase) Register(ctx context.Context, username, email, password s
 		ID:       uuid.New().String(),
 		Username: username,
 		Email:    email,
-		Password: string(hashedPassword),
+		Password: hashedPassword,
 		Role:     "user",
 	}
 
@@ -91,10 +144,25 @@ func (uc *AuthUseCase) Register(ctx context.Context, username, email, password s
 		logger.String("email", user.Email),
 		logger.String("role", user.Role))
 
+	// Приглашение засчитывается до создания пользователя, чтобы параллельные регистрации
+	// не превысили лимит, и возвращается, если пользователя создать не удалось
+	var inviteID string
+	if uc.invites != nil {
+		if inviteID, err = uc.invites.consume(ctx, inviteCode); err != nil {
+			uc.log.Warn("Registration without valid invite",
+				logger.String("email", email),
+				logger.Error(err))
+			return nil, err
+		}
+	}
+
 	if err := uc.repo.CreateUser(ctx, user); err != nil {
 		uc.log.Error("Failed to create user",
 			logger.String("user_id", user.ID),
 			logger.Error(err))
+		if inviteID != "" {
+			uc.invites.release(ctx, inviteID)
+		}
 		return nil, err
 	}
 
@@ -106,30 +174,325 @@ func (uc *AuthUseCase) Register(ctx context.Context, username, email, password s
 	return user, nil
 }
 
-func (uc *AuthUseCase) Login(ctx context.Context, email, password string) (*entity.TokenDetails, error) {
+// Login проверяет пароль и выдает токены новой сессии клиента client. identifier - email или имя пользователя.
+// Подряд идущие неудачные попытки по пользователю и по адресу клиента считаются отдельно;
+// после порога вход блокируется на время политики lockout.
+func (uc *AuthUseCase) Login(ctx context.Context, identifier, password string, client entity.ClientInfo) (*entity.TokenDetails, error) {
+	identifier = strings.TrimSpace(identifier)
 	uc.log.Info("Attempting user login",
-		logger.String("email", email))
+		logger.String("identifier", identifier))
 
-	user, err := uc.repo.GetUserByEmail(ctx, email)
+	now := time.Now()
+	ip := client.IP
+	if err := uc.checkLock(ctx, repository.LoginFailureIP, ip, now); err != nil {
+		uc.recordEvent(ctx, "", entity.AuthEventLoginFailure, entity.AuthMethodPassword, client, failureIPLocked)
+		return nil, err
+	}
+
+	user, err := uc.findByIdentifier(ctx, identifier)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			uc.log.Warn("User not found during login",
-				logger.String("email", email))
-			return nil, fmt.Errorf("invalid credentials")
-		}
 		uc.log.Error("Failed to get user during login",
-			logger.String("email", email),
+			logger.String("identifier", identifier),
 			logger.Error(err))
 		return nil, err
 	}
+	if user == nil {
+		uc.log.Warn("User not found during login",
+			logger.String("identifier", identifier))
+		uc.recordEvent(ctx, "", entity.AuthEventLoginFailure, entity.AuthMethodPassword, client, failureUnknownUser)
+		return nil, uc.loginFailed(ctx, "", ip, now)
+	}
 
-	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
+	if err := uc.checkLock(ctx, repository.LoginFailureUser, user.ID, now); err != nil {
+		uc.recordEvent(ctx, user.ID, entity.AuthEventLoginFailure, entity.AuthMethodPassword, client, failureLocked)
+		return nil, err
+	}
+
+	if err := uc.hasher.Compare(user.Password, password); err != nil && !isMismatch(err) {
+		uc.log.Error("Failed to check password during login",
+			logger.String("user_id", user.ID),
+			logger.Error(err))
+		return nil, err
+	} else if err != nil {
 		uc.log.Warn("Invalid password during login",
 			logger.String("user_id", user.ID))
-		return nil, fmt.Errorf("invalid credentials")
+		uc.recordEvent(ctx, user.ID, entity.AuthEventLoginFailure, entity.AuthMethodPassword, client, failureInvalidPassword)
+		return nil, uc.loginFailed(ctx, user.ID, ip, now)
+	}
+
+	// Статус проверяется после пароля, чтобы не раскрывать его без знания пароля
+	if err := user.CheckStatus(now); err != nil {
+		uc.log.Warn("Login of suspended user rejected",
+			logger.String("user_id", user.ID),
+			logger.String("status", user.Status))
+		uc.recordEvent(ctx, user.ID, entity.AuthEventLoginFailure, entity.AuthMethodPassword, client, failureSuspended)
+		return nil, err
+	}
+
+	if uc.lockout.Enabled() {
+		if err := uc.failures.Reset(ctx, repository.LoginFailureUser, user.ID); err != nil {
+			return nil, err
+		}
+		if err := uc.failures.Reset(ctx, repository.LoginFailureIP, ip); err != nil {
+			return nil, err
+		}
+	}
+
+	if uc.hasher.NeedsRehash(user.Password) {
+		uc.rehashPassword(ctx, user, password)
+	}
+
+	tokens, err := uc.startSession(ctx, user, client)
+	if err != nil {
+		return nil, err
+	}
+
+	uc.recordEvent(ctx, user.ID, entity.AuthEventLoginSuccess, entity.AuthMethodPassword, client, "")
+	uc.log.Info("Successfully logged in user",
+		logger.String("user_id", user.ID))
+
+	return tokens, nil
+}
+
+// rehashPassword пересчитывает хеш пароля текущим перцем после успешного входа.
+// Ошибка не мешает входу: хеш будет пересчитан при следующем.
+func (uc *AuthUseCase) rehashPassword(ctx context.Context, user *entity.User, password string) {
+	hash, err := uc.hasher.Hash(password)
+	if err == nil {
+		err = uc.repo.ReplacePasswordHash(ctx, user.ID, user.Password, hash)
+	}
+	if err != nil {
+		uc.log.Error("Failed to rehash password",
+			logger.String("user_id", user.ID),
+			logger.Error(err))
+		return
+	}
+	uc.log.Info("Rehashed password with current pepper",
+		logger.String("user_id", user.ID))
+}
+
+// findByIdentifier ищет пользователя по email, а если identifier не похож на email
+// или такого email нет - по имени пользователя. Возвращает nil, если никого не нашлось.
+func (uc *AuthUseCase) findByIdentifier(ctx context.Context, identifier string) (*entity.User, error) {
+	if identifier == "" {
+		return nil, nil
+	}
+
+	if email := strings.ToLower(identifier); isValidEmail(email) {
+		user, err := uc.repo.GetUserByEmail(ctx, email)
+		if err != nil || user != nil {
+			return user, err
+		}
 	}
+	return uc.repo.GetUserByUsername(ctx, identifier)
+}
 
-	tokens, err := uc.jwt.GenerateTokens(user.ID)
+// checkLock возвращает AccountLockedError, если вход для subject заблокирован
+func (uc *AuthUseCase) checkLock(ctx context.Context, kind, subject string, now time.Time) error {
+	if !uc.lockout.Enabled() || subject == "" {
+		return nil
+	}
+	until, err := uc.failures.LockedUntil(ctx, kind, subject, now)
+	if err != nil {
+		return err
+	}
+	if until != nil {
+		uc.log.Warn("Login attempt while locked",
+			logger.String("kind", kind),
+			logger.String("subject", subject))
+		return &entity.AccountLockedError{Until: *until}
+	}
+	return nil
+}
+
+// loginFailed засчитывает неудачный вход пользователю userID (если он найден) и адресу ip.
+// Возвращает AccountLockedError, если эта попытка исчерпала порог, иначе "invalid credentials".
+func (uc *AuthUseCase) loginFailed(ctx context.Context, userID, ip string, now time.Time) error {
+	if !uc.lockout.Enabled() {
+		return fmt.Errorf("invalid credentials")
+	}
+
+	var lockedUntil *time.Time
+	if userID != "" {
+		until, err := uc.failures.RecordFailure(ctx, repository.LoginFailureUser, userID, uc.lockout.MaxFailures, uc.lockout.Duration, now)
+		if err != nil {
+			return err
+		}
+		lockedUntil = until
+	}
+	if ip != "" && uc.lockout.MaxIPFailures > 0 {
+		until, err := uc.failures.RecordFailure(ctx, repository.LoginFailureIP, ip, uc.lockout.MaxIPFailures, uc.lockout.Duration, now)
+		if err != nil {
+			return err
+		}
+		if until != nil && (lockedUntil == nil || until.After(*lockedUntil)) {
+			lockedUntil = until
+		}
+	}
+
+	if lockedUntil != nil {
+		return &entity.AccountLockedError{Until: *lockedUntil}
+	}
+	return fmt.Errorf("invalid credentials")
+}
+
+// LoginWithProvider выдает токены пользователю, привязанному к аккаунту провайдера provider.
+// Аккаунт без привязки связывается с пользователем с тем же подтвержденным email,
+// а если такого нет - создается новый пользователь.
+func (uc *AuthUseCase) LoginWithProvider(ctx context.Context, provider string, profile *ExternalProfile, client entity.ClientInfo) (*entity.TokenDetails, error) {
+	uc.log.Info("Attempting external login",
+		logger.String("provider", provider),
+		logger.String("external_id", profile.ExternalID))
+
+	user, err := uc.repo.GetUserByLinkedAccount(ctx, provider, profile.ExternalID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user == nil {
+		// Без подтвержденного email нельзя ни привязать существующий аккаунт, ни занять адрес новым
+		email := strings.ToLower(strings.TrimSpace(profile.Email))
+		if !profile.EmailVerified || !isValidEmail(email) {
+			uc.log.Warn("External account email is not verified",
+				logger.String("provider", provider),
+				logger.String("external_id", profile.ExternalID))
+			return nil, entity.ErrEmailNotVerified
+		}
+
+		user, err = uc.repo.GetUserByEmail(ctx, email)
+		if err != nil {
+			return nil, err
+		}
+		if user == nil && uc.invites != nil {
+			uc.log.Warn("External account sign up without invite",
+				logger.String("provider", provider),
+				logger.String("email", email))
+			return nil, entity.ErrInviteRequired
+		}
+		if user == nil {
+			user, err = uc.createOAuthUser(ctx, oauthUsername(profile.Name, email), email)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if err := uc.repo.LinkAccount(ctx, user.ID, provider, profile.ExternalID); err != nil {
+			return nil, err
+		}
+	}
+
+	tokens, err := uc.startSession(ctx, user, client)
+	if err != nil {
+		return nil, err
+	}
+
+	uc.recordEvent(ctx, user.ID, entity.AuthEventLoginSuccess, provider, client, "")
+	uc.log.Info("Successfully logged in user with external account",
+		logger.String("user_id", user.ID),
+		logger.String("provider", provider))
+	return tokens, nil
+}
+
+// createOAuthUser создает пользователя без пароля: войти можно только через внешний аккаунт.
+// При занятом имени пользователя к нему добавляется случайный суффикс.
+func (uc *AuthUseCase) createOAuthUser(ctx context.Context, username, email string) (*entity.User, error) {
+	// Случайный пароль, который никто не знает, - password в таблице обязателен
+	hashedPassword, err := uc.hasher.Hash(uuid.New().String())
+	if err != nil {
+		return nil, err
+	}
+
+	for attempt := 0; ; attempt++ {
+		user := &entity.User{
+			ID:       uuid.New().String(),
+			Username: username,
+			Email:    email,
+			Password: string(hashedPassword),
+			Role:     "user",
+		}
+		if attempt > 0 {
+			user.Username = username + "-" + user.ID[:4]
+		}
+
+		err := uc.repo.CreateUser(ctx, user)
+		if err == nil {
+			uc.log.Info("Created user from external account",
+				logger.String("user_id", user.ID),
+				logger.String("username", user.Username))
+			return user, nil
+		}
+		if attempt >= 2 {
+			return nil, err
+		}
+	}
+}
+
+// oauthUsername имя нового пользователя: имя из профиля или часть email до @
+func oauthUsername(name, email string) string {
+	if name = strings.TrimSpace(name); name != "" {
+		return name
+	}
+	return email[:strings.Index(email, "@")]
+}
+
+// Refresh обменивает refresh токен на новую пару токенов; старый refresh токен отзывается.
+// Повторное использование отозванного refresh токена означает его утечку, поэтому
+// в этом случае отзываются все refresh токены пользователя.
+func (uc *AuthUseCase) Refresh(ctx context.Context, refreshToken string, client entity.ClientInfo) (*entity.TokenDetails, error) {
+	claims, err := uc.jwt.ValidateToken(ctx, refreshToken)
+	if err != nil {
+		return nil, entity.ErrInvalidToken
+	}
+
+	stored, err := uc.refreshTokens.GetByID(ctx, claims.ID)
+	if err != nil {
+		return nil, err
+	}
+	if stored == nil || stored.UserID != claims.UserID {
+		// Access токен или refresh токен, выданный до появления хранилища
+		return nil, entity.ErrInvalidToken
+	}
+
+	rotated, err := uc.refreshTokens.Revoke(ctx, stored.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !rotated {
+		uc.log.Warn("Revoked refresh token reused, revoking all user tokens",
+			logger.String("user_id", claims.UserID),
+			logger.String("token_id", claims.ID))
+		if _, err := uc.refreshTokens.RevokeAll(ctx, claims.UserID); err != nil {
+			return nil, err
+		}
+		if _, err := uc.RevokeOtherSessions(ctx, claims.UserID, ""); err != nil {
+			return nil, err
+		}
+		return nil, entity.ErrInvalidToken
+	}
+
+	// Роль в новых токенах берется из БД: она могла измениться после входа
+	user, err := uc.repo.GetUserByID(ctx, claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, entity.ErrInvalidToken
+	}
+
+	tokens, err := uc.continueSession(ctx, user, stored.SessionID, client)
+	if err != nil {
+		return nil, err
+	}
+
+	uc.recordEvent(ctx, user.ID, entity.AuthEventTokenRefresh, "", client, "")
+	uc.log.Info("Successfully refreshed tokens",
+		logger.String("user_id", claims.UserID))
+	return tokens, nil
+}
+
+// issueTokens выпускает пару токенов пользователя в сессии sessionID и сохраняет refresh токен
+func (uc *AuthUseCase) issueTokens(ctx context.Context, user *entity.User, sessionID string) (*entity.TokenDetails, error) {
+	tokens, err := uc.jwt.GenerateTokens(user.ID, user.Role, sessionID)
 	if err != nil {
 		uc.log.Error("Failed to generate tokens",
 			logger.String("user_id", user.ID),
@@ -137,13 +500,149 @@ func (uc *AuthUseCase) Login(ctx context.Context, email, password string) (*enti
 		return nil, fmt.Errorf("failed to generate tokens: %w", err)
 	}
 
-	uc.log.Info("Successfully logged in user",
-		logger.String("user_id", user.ID))
+	err = uc.refreshTokens.Create(ctx, &entity.RefreshToken{
+		ID:        tokens.RefreshUuid,
+		UserID:    user.ID,
+		SessionID: sessionID,
+		ExpiresAt: time.Unix(tokens.RtExpires, 0),
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+// IssueGuestToken выпускает анонимному посетителю гостевой токен: с ним можно читать форум
+// и подключаться к чату без права писать
+func (uc *AuthUseCase) IssueGuestToken(ctx context.Context) (*entity.TokenDetails, error) {
+	tokens, err := uc.jwt.GenerateGuestToken(GuestTokenTTL)
+	if err != nil {
+		uc.log.Error("Failed to generate guest token",
+			logger.Error(err))
+		return nil, fmt.Errorf("failed to generate tokens: %w", err)
+	}
+	return tokens, nil
+}
+
+// Impersonate выпускает администратору adminID короткоживущий токен пользователя targetID.
+// Выдача токена и все действия под ним пишутся в журнал аудита.
+func (uc *AuthUseCase) Impersonate(ctx context.Context, adminID, targetID, reason, ip string) (*entity.TokenDetails, error) {
+	uc.log.Info("Impersonation requested",
+		logger.String("admin_id", adminID),
+		logger.String("target_id", targetID))
+
+	// Токен имперсонации не дает права выпустить следующий
+	if _, ok := audit.ActingAdminFromContext(ctx); ok {
+		return nil, entity.ErrImpersonationForbidden
+	}
+
+	admin, err := uc.repo.GetUserByID(ctx, adminID)
+	if err != nil {
+		return nil, err
+	}
+	if admin == nil || admin.Role != "admin" {
+		uc.log.Warn("Impersonation by non-admin",
+			logger.String("user_id", adminID))
+		return nil, entity.ErrNotAdmin
+	}
+
+	target, err := uc.repo.GetUserByID(ctx, targetID)
+	if err != nil {
+		return nil, err
+	}
+	if target == nil {
+		return nil, entity.ErrUserNotFound
+	}
+	if target.Role == "admin" || target.ID == admin.ID {
+		uc.log.Warn("Impersonation of admin rejected",
+			logger.String("admin_id", adminID),
+			logger.String("target_id", targetID))
+		return nil, entity.ErrImpersonationForbidden
+	}
+
+	tokens, err := uc.jwt.GenerateImpersonationToken(target.ID, target.Role, admin.ID, ImpersonationTTL)
+	if err != nil {
+		uc.log.Error("Failed to generate impersonation token",
+			logger.String("admin_id", adminID),
+			logger.Error(err))
+		return nil, fmt.Errorf("failed to generate tokens: %w", err)
+	}
+
+	// Без записи в журнал токен не выдается
+	err = uc.audit.Write(ctx, &audit.Entry{
+		ActorID:       target.ID,
+		ActingAdminID: admin.ID,
+		Action:        "impersonation.start",
+		TargetType:    "user",
+		TargetID:      target.ID,
+		Details:       reason,
+		IP:            ip,
+	})
+	if err != nil {
+		uc.log.Error("Failed to write audit entry",
+			logger.String("admin_id", adminID),
+			logger.Error(err))
+		return nil, err
+	}
+
+	uc.log.Info("Impersonation token issued",
+		logger.String("admin_id", adminID),
+		logger.String("target_id", targetID))
 
 	return tokens, nil
 }
 
+// Logout отзывает access токен и, если он передан, refresh токен того же пользователя.
+// Отозванные токены не принимаются до истечения их срока.
+func (uc *AuthUseCase) Logout(ctx context.Context, accessToken, refreshToken string) error {
+	claims, err := uc.jwt.ValidateToken(ctx, accessToken)
+	if err != nil {
+		return entity.ErrInvalidToken
+	}
+
+	uc.log.Info("Logging out user",
+		logger.String("user_id", claims.UserID))
+
+	// Refresh токен проверяется до отзыва access, чтобы ошибка в запросе не разлогинила наполовину
+	var refreshClaims *jwt.Claims
+	if refreshToken != "" {
+		refreshClaims, err = uc.jwt.ValidateToken(ctx, refreshToken)
+		switch {
+		case errors.Is(err, entity.ErrTokenRevoked):
+			// Уже отозван предыдущим выходом
+		case err != nil || refreshClaims.UserID != claims.UserID:
+			uc.log.Warn("Invalid refresh token on logout",
+				logger.String("user_id", claims.UserID))
+			return entity.ErrInvalidToken
+		}
+	}
+
+	if err := uc.revoked.Revoke(ctx, claims.ID, claims.UserID, claims.ExpiresAt.Time); err != nil {
+		return err
+	}
+	if refreshClaims != nil {
+		if err := uc.revoked.Revoke(ctx, refreshClaims.ID, refreshClaims.UserID, refreshClaims.ExpiresAt.Time); err != nil {
+			return err
+		}
+		if _, err := uc.refreshTokens.Revoke(ctx, refreshClaims.ID); err != nil {
+			return err
+		}
+	}
+	if claims.SessionID != "" {
+		if err := uc.RevokeSession(ctx, claims.UserID, claims.SessionID); err != nil && !errors.Is(err, entity.ErrSessionNotFound) {
+			return err
+		}
+	}
+
+	uc.log.Info("Successfully logged out user",
+		logger.String("user_id", claims.UserID))
+	return nil
+}
+
+// emails проверяет формат адресов по тем же правилам, что и теги validate:"email" в HTTP запросах
+var emails = validator.New()
+
 func isValidEmail(email string) bool {
-	// Простая проверка на наличие @ и домена
-	return strings.Contains(email, "@") && strings.Contains(email[strings.Index(email, "@"):], ".")
+	return emails.Var(email, "required,email") == nil
 }
@@ -0,0 +1,167 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kprf42/dolgova/auth_service/internal/entity"
+	"github.com/kprf42/dolgova/auth_service/internal/repository"
+	"github.com/kprf42/dolgova/auth_service/internal/usecase/jwt"
+	"github.com/kprf42/dolgova/auth_service/migrations"
+	"github.com/kprf42/dolgova/pkg/audit"
+	"github.com/kprf42/dolgova/pkg/logger"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+const testPassword = "correct-horse-42"
+
+// newTestAuthUseCase создает use case поверх временной SQLite базы с примененными миграциями
+func newTestAuthUseCase(t *testing.T, lockout LockoutPolicy) (*AuthUseCase, *sql.DB) {
+	t.Helper()
+
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "auth.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	migrator, err := migrations.NewMigrator(db)
+	if err != nil {
+		t.Fatalf("create migrator: %v", err)
+	}
+	if err := migrator.Up(); err != nil {
+		t.Fatalf("apply migrations: %v", err)
+	}
+
+	log, err := logger.NewWithConfig(logger.LogConfig{Level: "error", OutputPath: "stdout"})
+	if err != nil {
+		t.Fatalf("create logger: %v", err)
+	}
+
+	passwords, err := NewPasswordPolicy(PasswordPolicyConfig{MinLength: 8, MaxLength: 72})
+	if err != nil {
+		t.Fatalf("create password policy: %v", err)
+	}
+
+	tokens := repository.NewSQLiteTokenStore(db, log)
+	jwtService := jwt.NewJWTService([]jwt.SigningKey{{ID: jwt.DefaultKeyID, Secret: "test-secret"}},
+		time.Hour, 24*time.Hour, tokens.Revoked())
+	uc := NewAuthUseCase(repository.NewSQLiteUserRepository(db, log), tokens,
+		repository.NewSessionRepository(db, log), repository.NewAuthEventRepository(db, log),
+		lockout, passwords, audit.NewStore(db), jwtService, log)
+	return uc, db
+}
+
+// registerTestUser регистрирует пользователя с паролем testPassword
+func registerTestUser(t *testing.T, uc *AuthUseCase, username string) *entity.User {
+	t.Helper()
+	user, err := uc.Register(context.Background(), username, username+"@example.com", testPassword, "")
+	if err != nil {
+		t.Fatalf("register %s: %v", username, err)
+	}
+	return user
+}
+
+func TestLoginLockout(t *testing.T) {
+	const (
+		ok      = "ok"
+		invalid = "invalid"
+		locked  = "locked"
+	)
+	type attempt struct {
+		user, password, ip string
+		want               string
+	}
+	policy := LockoutPolicy{MaxFailures: 3, MaxIPFailures: 4, Duration: time.Hour}
+
+	tests := []struct {
+		name     string
+		policy   LockoutPolicy
+		attempts []attempt
+	}{
+		{
+			name:   "failures below threshold",
+			policy: policy,
+			attempts: []attempt{
+				{"alice", "wrong", "10.0.0.1", invalid},
+				{"alice", "wrong", "10.0.0.1", invalid},
+				{"alice", testPassword, "10.0.0.1", ok},
+			},
+		},
+		{
+			name:   "threshold locks user even with correct password",
+			policy: policy,
+			attempts: []attempt{
+				{"alice", "wrong", "10.0.0.1", invalid},
+				{"alice", "wrong", "10.0.0.2", invalid},
+				{"alice", "wrong", "10.0.0.3", locked},
+				{"alice", testPassword, "10.0.0.4", locked},
+				{"bob", testPassword, "10.0.0.4", ok},
+			},
+		},
+		{
+			name:   "successful login resets counter",
+			policy: policy,
+			attempts: []attempt{
+				{"alice", "wrong", "10.0.0.1", invalid},
+				{"alice", "wrong", "10.0.0.1", invalid},
+				{"alice", testPassword, "10.0.0.1", ok},
+				{"alice", "wrong", "10.0.0.1", invalid},
+				{"alice", "wrong", "10.0.0.1", invalid},
+				{"alice", testPassword, "10.0.0.1", ok},
+			},
+		},
+		{
+			name:   "address is locked across users",
+			policy: policy,
+			attempts: []attempt{
+				{"alice", "wrong", "10.0.0.1", invalid},
+				{"bob", "wrong", "10.0.0.1", invalid},
+				{"nobody", "wrong", "10.0.0.1", invalid},
+				{"ghost", "wrong", "10.0.0.1", locked},
+				{"bob", testPassword, "10.0.0.1", locked},
+				{"bob", testPassword, "10.0.0.2", ok},
+			},
+		},
+		{
+			name:   "disabled policy never locks",
+			policy: LockoutPolicy{},
+			attempts: []attempt{
+				{"alice", "wrong", "10.0.0.1", invalid},
+				{"alice", "wrong", "10.0.0.1", invalid},
+				{"alice", "wrong", "10.0.0.1", invalid},
+				{"alice", "wrong", "10.0.0.1", invalid},
+				{"alice", testPassword, "10.0.0.1", ok},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			uc, _ := newTestAuthUseCase(t, tt.policy)
+			registerTestUser(t, uc, "alice")
+			registerTestUser(t, uc, "bob")
+
+			for i, a := range tt.attempts {
+				_, err := uc.Login(ctx, a.user, a.password, entity.ClientInfo{IP: a.ip})
+
+				var lockErr *entity.AccountLockedError
+				got := invalid
+				switch {
+				case err == nil:
+					got = ok
+				case errors.As(err, &lockErr):
+					got = locked
+				}
+				if got != a.want {
+					t.Fatalf("attempt %d (%s from %s): got %s (%v), want %s", i+1, a.user, a.ip, got, err, a.want)
+				}
+			}
+		})
+	}
+}
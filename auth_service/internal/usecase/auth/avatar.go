@@ -0,0 +1,81 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/kprf42/dolgova/auth_service/internal/entity"
+	"github.com/kprf42/dolgova/pkg/logger"
+	"github.com/kprf42/dolgova/pkg/uploads"
+)
+
+// AvatarSize сторона квадратного аватара в пикселях
+const AvatarSize = 256
+
+// Avatars загрузка аватаров в хранилище файлов (локальный диск или S3)
+type Avatars struct {
+	auth    *AuthUseCase
+	storage uploads.Saver
+}
+
+func NewAvatars(authUC *AuthUseCase, storage uploads.Saver) *Avatars {
+	return &Avatars{
+		auth:    authUC,
+		storage: storage,
+	}
+}
+
+// Upload обрезает изображение до квадрата, уменьшает до AvatarSize, сохраняет
+// и записывает его адрес в профиль. Возвращает обновленного пользователя.
+func (a *Avatars) Upload(ctx context.Context, userID string, r io.Reader) (*entity.User, error) {
+	user, err := a.auth.GetProfile(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	contentType := http.DetectContentType(data)
+	if !uploads.IsImage(contentType) {
+		a.auth.log.Warn("Rejected avatar of unsupported type",
+			logger.String("user_id", userID),
+			logger.String("content_type", contentType))
+		return nil, entity.ErrUnsupportedImage
+	}
+
+	img, format, err := uploads.DecodeImage(bytes.NewReader(data))
+	if err != nil {
+		a.auth.log.Warn("Failed to decode avatar",
+			logger.String("user_id", userID),
+			logger.Error(err))
+		return nil, entity.ErrInvalidImage
+	}
+
+	encoded, ext, err := uploads.Encode(uploads.Resize(uploads.CropSquare(img), AvatarSize, AvatarSize), format)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode avatar: %w", err)
+	}
+	key, err := a.storage.Save(ctx, bytes.NewReader(encoded), ext)
+	if err != nil {
+		a.auth.log.Error("Failed to store avatar",
+			logger.String("user_id", userID),
+			logger.Error(err))
+		return nil, err
+	}
+
+	user.AvatarURL = a.storage.URL(key)
+	if err := a.auth.repo.UpdateUser(ctx, user); err != nil {
+		return nil, err
+	}
+
+	a.auth.log.Info("Uploaded user avatar",
+		logger.String("user_id", userID),
+		logger.String("key", key))
+	return user, nil
+}
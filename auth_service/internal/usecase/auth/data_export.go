@@ -0,0 +1,339 @@
+package auth
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kprf42/dolgova/auth_service/internal/entity"
+	"github.com/kprf42/dolgova/auth_service/internal/repository"
+	"github.com/kprf42/dolgova/pkg/audit"
+	"github.com/kprf42/dolgova/pkg/logger"
+)
+
+// exportQueueSize выгрузок в очереди; при переполнении запрос отклоняется
+const exportQueueSize = 32
+
+// exportActivityPage событий входа за один запрос к базе
+const exportActivityPage = 500
+
+// ContentExporter выгружает контент пользователя из других сервисов (посты и сообщения форума)
+type ContentExporter interface {
+	ExportUserContent(ctx context.Context, userID, accessToken string) (*entity.ForumContent, error)
+}
+
+// DataExportConfig настройки выгрузки персональных данных
+type DataExportConfig struct {
+	Dir string        // Каталог для готовых архивов
+	TTL time.Duration // Сколько архив доступен для скачивания
+}
+
+type exportJob struct {
+	id          string
+	userID      string
+	accessToken string
+}
+
+// DataExports асинхронная выгрузка персональных данных в ZIP архив (право на доступ к данным).
+// Архив собирается фоновым обработчиком; до готовности клиент опрашивает состояние.
+type DataExports struct {
+	auth    *AuthUseCase
+	repo    *repository.DataExportRepository
+	content ContentExporter
+	cfg     DataExportConfig
+
+	jobs chan exportJob
+	quit chan struct{}
+	done chan struct{}
+}
+
+func NewDataExports(authUC *AuthUseCase, repo *repository.DataExportRepository, content ContentExporter, cfg DataExportConfig) (*DataExports, error) {
+	if err := os.MkdirAll(cfg.Dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create export dir: %w", err)
+	}
+	return &DataExports{
+		auth:    authUC,
+		repo:    repo,
+		content: content,
+		cfg:     cfg,
+		jobs:    make(chan exportJob, exportQueueSize),
+		quit:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}, nil
+}
+
+// Request возвращает текущую выгрузку пользователя или ставит в очередь новую.
+// accessToken нужен для запроса к форуму и хранится только в памяти до сборки архива.
+func (d *DataExports) Request(ctx context.Context, userID, accessToken string) (*entity.DataExport, error) {
+	// Администратор под имперсонацией не может выгрузить чужие данные
+	if _, ok := audit.ActingAdminFromContext(ctx); ok {
+		return nil, entity.ErrImpersonationForbidden
+	}
+
+	if _, err := d.auth.GetProfile(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	latest, err := d.repo.GetLatest(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if latest != nil && latest.Active(now) {
+		return latest, nil
+	}
+
+	export := &entity.DataExport{
+		ID:        uuid.New().String(),
+		UserID:    userID,
+		Status:    entity.DataExportPending,
+		CreatedAt: now,
+		ExpiresAt: now.Add(d.cfg.TTL),
+	}
+	if err := d.repo.Create(ctx, export); err != nil {
+		return nil, err
+	}
+
+	select {
+	case d.jobs <- exportJob{id: export.ID, userID: userID, accessToken: accessToken}:
+	default:
+		d.auth.log.Warn("Data export queue is full",
+			logger.String("export_id", export.ID))
+		d.repo.Fail(ctx, export.ID, entity.ErrDataExportBusy.Error(), now)
+		return nil, entity.ErrDataExportBusy
+	}
+
+	d.auth.log.Info("Data export requested",
+		logger.String("export_id", export.ID),
+		logger.String("user_id", userID))
+	return export, nil
+}
+
+// Get возвращает выгрузку userID. Чужие и удаленные выгрузки не находятся.
+func (d *DataExports) Get(ctx context.Context, userID, exportID string) (*entity.DataExport, error) {
+	export, err := d.repo.GetByID(ctx, exportID)
+	if err != nil {
+		return nil, err
+	}
+	if export == nil || export.UserID != userID || !time.Now().Before(export.ExpiresAt) {
+		return nil, entity.ErrDataExportNotFound
+	}
+	return export, nil
+}
+
+// Open открывает готовый архив выгрузки. Файл закрывает вызывающий.
+func (d *DataExports) Open(ctx context.Context, userID, exportID string) (*entity.DataExport, *os.File, error) {
+	export, err := d.Get(ctx, userID, exportID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if export.Status != entity.DataExportReady {
+		return nil, nil, entity.ErrDataExportNotReady
+	}
+
+	f, err := os.Open(d.archivePath(export.ID))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil, entity.ErrDataExportNotReady
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return export, f, nil
+}
+
+// Run собирает архивы из очереди и раз в час удаляет истекшие, пока не вызван Stop.
+// Выгрузки, прерванные прошлым перезапуском, отмечаются неудачными: токена для форума уже нет.
+func (d *DataExports) Run() {
+	defer close(d.done)
+
+	ctx := context.Background()
+	if n, err := d.repo.FailUnfinished(ctx, "interrupted by restart", time.Now()); err == nil && n > 0 {
+		d.auth.log.Warn("Marked interrupted data exports as failed",
+			logger.Int64("count", n))
+	}
+	d.cleanup(ctx)
+
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-d.quit:
+			return
+		case job := <-d.jobs:
+			d.build(job)
+		case <-ticker.C:
+			d.cleanup(ctx)
+		}
+	}
+}
+
+// Stop останавливает обработчик. Текущий архив дописывается, остальные выгрузки
+// будут отмечены неудачными при следующем запуске.
+func (d *DataExports) Stop(ctx context.Context) error {
+	close(d.quit)
+
+	select {
+	case <-d.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (d *DataExports) archivePath(id string) string {
+	return filepath.Join(d.cfg.Dir, id+".zip")
+}
+
+func (d *DataExports) build(job exportJob) {
+	ctx := context.Background()
+	if err := d.repo.SetRunning(ctx, job.id); err != nil {
+		return
+	}
+
+	size, err := d.writeArchive(ctx, job)
+	if err != nil {
+		d.auth.log.Error("Failed to build data export",
+			logger.String("export_id", job.id),
+			logger.String("user_id", job.userID),
+			logger.Error(err))
+		os.Remove(d.archivePath(job.id))
+		d.repo.Fail(ctx, job.id, err.Error(), time.Now())
+		return
+	}
+
+	if err := d.repo.Complete(ctx, job.id, size, time.Now()); err != nil {
+		return
+	}
+	d.auth.log.Info("Data export ready",
+		logger.String("export_id", job.id),
+		logger.Int64("size", size))
+}
+
+// exportAccount данные аккаунта в архиве; хеш пароля не выгружается
+type exportAccount struct {
+	ID          string `json:"id"`
+	Username    string `json:"username"`
+	Email       string `json:"email"`
+	Role        string `json:"role"`
+	DisplayName string `json:"display_name,omitempty"`
+	Bio         string `json:"bio,omitempty"`
+	AvatarURL   string `json:"avatar_url,omitempty"`
+}
+
+// writeArchive собирает данные и пишет архив во временный файл, который
+// переименовывается после успешной записи. Возвращает размер архива.
+func (d *DataExports) writeArchive(ctx context.Context, job exportJob) (int64, error) {
+	user, err := d.auth.GetProfile(ctx, job.userID)
+	if err != nil {
+		return 0, err
+	}
+	sessions, err := d.auth.ListSessions(ctx, job.userID)
+	if err != nil {
+		return 0, err
+	}
+	activity, err := d.allActivity(ctx, job.userID)
+	if err != nil {
+		return 0, err
+	}
+	content, err := d.content.ExportUserContent(ctx, job.userID, job.accessToken)
+	if err != nil {
+		return 0, err
+	}
+
+	files := []struct {
+		name string
+		data any
+	}{
+		{"account.json", exportAccount{
+			ID:          user.ID,
+			Username:    user.Username,
+			Email:       user.Email,
+			Role:        user.Role,
+			DisplayName: user.DisplayName,
+			Bio:         user.Bio,
+			AvatarURL:   user.AvatarURL,
+		}},
+		{"sessions.json", sessions},
+		{"activity.json", activity},
+		{"forum/posts.json", content.Posts},
+		{"forum/comments.json", content.Comments},
+		{"forum/chat_messages.json", content.ChatMessages},
+	}
+
+	tmp, err := os.CreateTemp(d.cfg.Dir, job.id+"-*.tmp")
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	zw := zip.NewWriter(tmp)
+	for _, f := range files {
+		w, err := zw.Create(f.name)
+		if err != nil {
+			return 0, err
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(f.data); err != nil {
+			return 0, fmt.Errorf("failed to write %s: %w", f.name, err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return 0, err
+	}
+
+	info, err := tmp.Stat()
+	if err != nil {
+		return 0, err
+	}
+	if err := tmp.Close(); err != nil {
+		return 0, err
+	}
+	if err := os.Rename(tmp.Name(), d.archivePath(job.id)); err != nil {
+		return 0, err
+	}
+	return info.Size(), nil
+}
+
+func (d *DataExports) allActivity(ctx context.Context, userID string) ([]*entity.AuthEvent, error) {
+	all := []*entity.AuthEvent{}
+	for offset := 0; ; offset += exportActivityPage {
+		page, err := d.auth.ListActivity(ctx, userID, exportActivityPage, offset)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, page...)
+		if len(page) < exportActivityPage {
+			return all, nil
+		}
+	}
+}
+
+// cleanup удаляет истекшие архивы и записи о них
+func (d *DataExports) cleanup(ctx context.Context) {
+	ids, err := d.repo.ListExpired(ctx, time.Now())
+	if err != nil {
+		return
+	}
+	for _, id := range ids {
+		if err := os.Remove(d.archivePath(id)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			d.auth.log.Error("Failed to remove data export archive",
+				logger.String("export_id", id),
+				logger.Error(err))
+			continue
+		}
+		d.repo.Delete(ctx, id)
+	}
+	if len(ids) > 0 {
+		d.auth.log.Info("Removed expired data exports",
+			logger.Int("count", len(ids)))
+	}
+}
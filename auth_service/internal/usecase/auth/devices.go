@@ -0,0 +1,145 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"time"
+
+	"github.com/kprf42/dolgova/auth_service/internal/entity"
+	"github.com/kprf42/dolgova/auth_service/internal/repository"
+	"github.com/kprf42/dolgova/auth_service/internal/usecase/jwt"
+	"github.com/kprf42/dolgova/pkg/logger"
+)
+
+// DeviceAlertSender доставляет пользователю письмо о входе с нового устройства
+type DeviceAlertSender interface {
+	SendNewDeviceAlert(ctx context.Context, email string, alert *entity.NewDeviceAlert) error
+}
+
+// Devices запоминает устройства, с которых входят пользователи, и сообщает письмом
+// о входе с нового устройства. В письме есть ссылка, завершающая эту сессию.
+type Devices struct {
+	auth      *AuthUseCase
+	repo      *repository.DeviceRepository
+	sender    DeviceAlertSender
+	revokeURL string // Страница завершения сессии; токен добавляется параметром token
+}
+
+func NewDevices(authUC *AuthUseCase, repo *repository.DeviceRepository, sender DeviceAlertSender, revokeURL string) *Devices {
+	return &Devices{
+		auth:      authUC,
+		repo:      repo,
+		sender:    sender,
+		revokeURL: revokeURL,
+	}
+}
+
+// NotifyNewDevices включает письма о входе с нового устройства
+func (uc *AuthUseCase) NotifyNewDevices(devices *Devices) {
+	uc.devices = devices
+}
+
+// check запоминает устройство сессии и, если пользователь входит с него впервые, отправляет письмо.
+// Первое устройство запоминается без письма: сравнивать его не с чем. Ошибки только пишутся
+// в лог, чтобы сбой почты не мешал входу.
+func (d *Devices) check(ctx context.Context, user *entity.User, session *entity.Session) {
+	client := entity.ClientInfo{IP: session.IP, UserAgent: session.UserAgent}
+
+	known, err := d.repo.HasAny(ctx, user.ID)
+	if err != nil {
+		return
+	}
+	isNew, err := d.repo.Remember(ctx, &entity.KnownDevice{
+		UserID:      user.ID,
+		Fingerprint: client.Fingerprint(),
+		UserAgent:   client.UserAgent,
+		IP:          client.IP,
+		FirstSeenAt: session.CreatedAt,
+		LastSeenAt:  session.CreatedAt,
+	})
+	if err != nil || !isNew || !known {
+		return
+	}
+
+	d.auth.log.Info("Login from new device",
+		logger.String("user_id", user.ID),
+		logger.String("session_id", session.ID))
+
+	link, err := d.revokeLink(user.ID, session)
+	if err != nil {
+		d.auth.log.Error("Failed to build session revoke link",
+			logger.String("user_id", user.ID),
+			logger.Error(err))
+		return
+	}
+
+	err = d.sender.SendNewDeviceAlert(ctx, user.Email, &entity.NewDeviceAlert{
+		Username:   user.Username,
+		UserAgent:  session.UserAgent,
+		IP:         session.IP,
+		Time:       session.CreatedAt,
+		RevokeLink: link,
+	})
+	if err != nil {
+		d.auth.log.Error("Failed to send new device alert",
+			logger.String("user_id", user.ID),
+			logger.Error(err))
+	}
+}
+
+// revokeLink ссылка, завершающая сессию; действует, пока сессия не истекла
+func (d *Devices) revokeLink(userID string, session *entity.Session) (string, error) {
+	token, err := d.auth.jwt.GenerateSessionPurposeToken(userID, session.ID, jwt.PurposeRevokeSession,
+		time.Until(session.ExpiresAt))
+	if err != nil {
+		return "", err
+	}
+
+	link, err := url.Parse(d.revokeURL)
+	if err != nil {
+		return "", err
+	}
+	query := link.Query()
+	query.Set("token", token)
+	link.RawQuery = query.Encode()
+	return link.String(), nil
+}
+
+// RevokeByLink завершает сессию по токену из письма о входе с нового устройства.
+// Повторный переход по ссылке уже завершенной сессии не считается ошибкой.
+func (d *Devices) RevokeByLink(ctx context.Context, token string) error {
+	claims, err := d.auth.jwt.ValidatePurposeToken(ctx, token, jwt.PurposeRevokeSession)
+	if errors.Is(err, entity.ErrTokenRevoked) {
+		return nil
+	}
+	if err != nil || claims.SessionID == "" {
+		d.auth.log.Warn("Invalid session revoke token",
+			logger.Error(err))
+		return entity.ErrInvalidToken
+	}
+
+	session, err := d.auth.sessions.GetByID(ctx, claims.SessionID)
+	if err != nil {
+		return err
+	}
+	if session == nil || session.UserID != claims.UserID {
+		return entity.ErrInvalidToken
+	}
+	if session.RevokedAt != nil {
+		return nil
+	}
+	if err := d.auth.revokeSession(ctx, session); err != nil {
+		return err
+	}
+
+	d.auth.log.Info("Session revoked from new device alert",
+		logger.String("user_id", session.UserID),
+		logger.String("session_id", session.ID))
+	return nil
+}
+
+// forget удаляет устройства пользователя при удалении аккаунта
+func (d *Devices) forget(ctx context.Context, userID string) error {
+	return d.repo.DeleteByUser(ctx, userID)
+}
@@ -0,0 +1,40 @@
+package auth
+
+import (
+	"context"
+
+	"github.com/kprf42/dolgova/auth_service/internal/entity"
+	"github.com/kprf42/dolgova/pkg/logger"
+)
+
+// Причины неудачного входа (AuthEvent.Details)
+const (
+	failureUnknownUser     = "unknown_user"
+	failureInvalidPassword = "invalid_password"
+	failureLocked          = "locked"
+	failureIPLocked        = "ip_locked"
+	failureSuspended       = "suspended"
+)
+
+// recordEvent пишет событие в историю входов пользователя userID. Ошибка записи не прерывает вход.
+func (uc *AuthUseCase) recordEvent(ctx context.Context, userID, eventType, method string, client entity.ClientInfo, details string) {
+	err := uc.events.Create(ctx, &entity.AuthEvent{
+		UserID:    userID,
+		Type:      eventType,
+		Method:    method,
+		IP:        client.IP,
+		UserAgent: client.UserAgent,
+		Details:   details,
+	})
+	if err != nil {
+		uc.log.Error("Failed to record auth event",
+			logger.String("user_id", userID),
+			logger.String("type", eventType),
+			logger.Error(err))
+	}
+}
+
+// ListActivity возвращает историю входов пользователя, новые события первыми
+func (uc *AuthUseCase) ListActivity(ctx context.Context, userID string, limit, offset int) ([]*entity.AuthEvent, error) {
+	return uc.events.ListByUser(ctx, userID, limit, offset)
+}
@@ -0,0 +1,118 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	githubAuthURL   = "https://github.com/login/oauth/authorize"
+	githubTokenURL  = "https://github.com/login/oauth/access_token"
+	githubUserURL   = "https://api.github.com/user"
+	githubEmailsURL = "https://api.github.com/user/emails"
+)
+
+// GitHubOAuth вход через GitHub
+type GitHubOAuth struct {
+	cfg    OAuthConfig
+	client *http.Client
+}
+
+func NewGitHubOAuth(cfg OAuthConfig) *GitHubOAuth {
+	return &GitHubOAuth{
+		cfg:    cfg,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (g *GitHubOAuth) Name() string {
+	return "github"
+}
+
+func (g *GitHubOAuth) AuthCodeURL(state string) string {
+	params := url.Values{
+		"client_id":    {g.cfg.ClientID},
+		"redirect_uri": {g.cfg.RedirectURL},
+		"scope":        {"read:user user:email"},
+		"state":        {state},
+	}
+	return githubAuthURL + "?" + params.Encode()
+}
+
+// Exchange обменивает код авторизации на access токен GitHub и возвращает профиль.
+// Публичный email в профиле может отсутствовать, поэтому берется основной подтвержденный из /user/emails.
+func (g *GitHubOAuth) Exchange(ctx context.Context, code string) (*ExternalProfile, error) {
+	form := url.Values{
+		"code":          {code},
+		"client_id":     {g.cfg.ClientID},
+		"client_secret": {g.cfg.ClientSecret},
+		"redirect_uri":  {g.cfg.RedirectURL},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, githubTokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	// Ошибки обмена GitHub возвращает с кодом 200 в поле error
+	var token struct {
+		AccessToken string `json:"access_token"`
+		Error       string `json:"error"`
+	}
+	if err := oauthJSON(g.client, req, &token); err != nil {
+		return nil, err
+	}
+	if token.AccessToken == "" {
+		return nil, fmt.Errorf("%w: %s", ErrOAuthExchange, token.Error)
+	}
+
+	var user struct {
+		ID    int64  `json:"id"`
+		Login string `json:"login"`
+		Name  string `json:"name"`
+	}
+	if err := g.get(ctx, githubUserURL, token.AccessToken, &user); err != nil {
+		return nil, err
+	}
+	if user.ID == 0 {
+		return nil, fmt.Errorf("%w: empty user id", ErrOAuthExchange)
+	}
+
+	var emails []struct {
+		Email    string `json:"email"`
+		Primary  bool   `json:"primary"`
+		Verified bool   `json:"verified"`
+	}
+	if err := g.get(ctx, githubEmailsURL, token.AccessToken, &emails); err != nil {
+		return nil, err
+	}
+
+	profile := &ExternalProfile{
+		ExternalID: strconv.FormatInt(user.ID, 10),
+		Name:       user.Login,
+	}
+	for _, e := range emails {
+		if e.Primary {
+			profile.Email = e.Email
+			profile.EmailVerified = e.Verified
+		}
+	}
+	return profile, nil
+}
+
+// get запрос к API GitHub от имени пользователя
+func (g *GitHubOAuth) get(ctx context.Context, endpoint, accessToken string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	return oauthJSON(g.client, req, out)
+}
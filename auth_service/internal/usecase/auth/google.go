@@ -0,0 +1,103 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	googleAuthURL     = "https://accounts.google.com/o/oauth2/v2/auth"
+	googleTokenURL    = "https://oauth2.googleapis.com/token"
+	googleUserInfoURL = "https://openidconnect.googleapis.com/v1/userinfo"
+)
+
+// OAuthConfig параметры OAuth клиента, зарегистрированного у провайдера
+type OAuthConfig struct {
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string // Адрес callback обработчика, зарегистрированный у провайдера
+}
+
+// GoogleOAuth вход через Google
+type GoogleOAuth struct {
+	cfg    OAuthConfig
+	client *http.Client
+}
+
+func NewGoogleOAuth(cfg OAuthConfig) *GoogleOAuth {
+	return &GoogleOAuth{
+		cfg:    cfg,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (g *GoogleOAuth) Name() string {
+	return "google"
+}
+
+func (g *GoogleOAuth) AuthCodeURL(state string) string {
+	params := url.Values{
+		"client_id":     {g.cfg.ClientID},
+		"redirect_uri":  {g.cfg.RedirectURL},
+		"response_type": {"code"},
+		"scope":         {"openid email profile"},
+		"state":         {state},
+	}
+	return googleAuthURL + "?" + params.Encode()
+}
+
+// Exchange обменивает код авторизации на access токен Google и возвращает профиль из userinfo
+func (g *GoogleOAuth) Exchange(ctx context.Context, code string) (*ExternalProfile, error) {
+	form := url.Values{
+		"code":          {code},
+		"client_id":     {g.cfg.ClientID},
+		"client_secret": {g.cfg.ClientSecret},
+		"redirect_uri":  {g.cfg.RedirectURL},
+		"grant_type":    {"authorization_code"},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, googleTokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := oauthJSON(g.client, req, &token); err != nil {
+		return nil, err
+	}
+	if token.AccessToken == "" {
+		return nil, fmt.Errorf("%w: empty access token", ErrOAuthExchange)
+	}
+
+	req, err = http.NewRequestWithContext(ctx, http.MethodGet, googleUserInfoURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token.AccessToken)
+
+	var profile struct {
+		Subject       string `json:"sub"`
+		Email         string `json:"email"`
+		EmailVerified bool   `json:"email_verified"`
+		Name          string `json:"name"`
+	}
+	if err := oauthJSON(g.client, req, &profile); err != nil {
+		return nil, err
+	}
+	if profile.Subject == "" {
+		return nil, fmt.Errorf("%w: empty subject", ErrOAuthExchange)
+	}
+
+	return &ExternalProfile{
+		ExternalID:    profile.Subject,
+		Email:         profile.Email,
+		EmailVerified: profile.EmailVerified,
+		Name:          profile.Name,
+	}, nil
+}
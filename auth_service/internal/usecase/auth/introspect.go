@@ -0,0 +1,63 @@
+package auth
+
+import (
+	"context"
+	"time"
+
+	"github.com/kprf42/dolgova/pkg/logger"
+)
+
+// Типы токенов в ответе интроспекции
+const (
+	TokenTypeAccess  = "access_token"
+	TokenTypeRefresh = "refresh_token"
+)
+
+// TokenIntrospection сведения о действующем токене
+type TokenIntrospection struct {
+	UserID        string
+	Role          string
+	TokenType     string
+	SessionID     string
+	ActingAdminID string
+	Scope         []string // Ограничения гостевого токена
+	ExpiresAt     time.Time
+}
+
+// Introspect проверяет токен так же, как при обычном запросе, и возвращает сведения о нем.
+// Для недействительного, отозванного, использованного refresh токена или токена
+// приостановленного пользователя возвращает nil: причина вызывающему не раскрывается.
+// Как и при обычной проверке, токен, который не удалось проверить, считается недействительным.
+func (uc *AuthUseCase) Introspect(ctx context.Context, token string) (*TokenIntrospection, error) {
+	claims, err := uc.ValidateAccessToken(ctx, token)
+	if err != nil {
+		uc.log.Info("Introspected inactive token",
+			logger.String("reason", err.Error()))
+		return nil, nil
+	}
+
+	info := &TokenIntrospection{
+		UserID:        claims.UserID,
+		Role:          claims.Role,
+		TokenType:     TokenTypeAccess,
+		SessionID:     claims.SessionID,
+		ActingAdminID: claims.ActingAdminID,
+		Scope:         claims.Scope,
+	}
+	if claims.ExpiresAt != nil {
+		info.ExpiresAt = claims.ExpiresAt.Time
+	}
+
+	// Refresh токены хранятся в БД; после обмена они отзываются
+	stored, err := uc.refreshTokens.GetByID(ctx, claims.ID)
+	if err != nil {
+		return nil, err
+	}
+	if stored != nil {
+		if stored.RevokedAt != nil || stored.UserID != claims.UserID {
+			return nil, nil
+		}
+		info.TokenType = TokenTypeRefresh
+	}
+	return info, nil
+}
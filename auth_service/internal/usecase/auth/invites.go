@@ -0,0 +1,139 @@
+package auth
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/kprf42/dolgova/auth_service/internal/entity"
+	"github.com/kprf42/dolgova/auth_service/internal/repository"
+	"github.com/kprf42/dolgova/pkg/audit"
+	"github.com/kprf42/dolgova/pkg/logger"
+)
+
+// maxInviteUses наибольшее число регистраций по одному приглашению
+const maxInviteUses = 1000
+
+// InviteRequest параметры нового приглашения
+type InviteRequest struct {
+	MaxUses int           // Сколько регистраций допускает код; 0 - одна
+	TTL     time.Duration // Срок действия; 0 - бессрочно
+}
+
+// Invites приглашения для регистрации. Код показывается один раз при выпуске,
+// в БД хранится только его SHA-256.
+type Invites struct {
+	auth *AuthUseCase
+	repo *repository.InviteRepository
+}
+
+func NewInvites(authUC *AuthUseCase, repo *repository.InviteRepository) *Invites {
+	return &Invites{
+		auth: authUC,
+		repo: repo,
+	}
+}
+
+// Issue выпускает приглашение от имени администратора adminID. Возвращает приглашение и его код.
+func (i *Invites) Issue(ctx context.Context, adminID string, req InviteRequest) (*entity.Invite, string, error) {
+	if req.MaxUses == 0 {
+		req.MaxUses = 1
+	}
+	if req.MaxUses < 0 || req.MaxUses > maxInviteUses || req.TTL < 0 {
+		return nil, "", entity.ErrInvalidInviteRequest
+	}
+
+	admin, err := i.admin(ctx, adminID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	id, err := randomHex(8)
+	if err != nil {
+		return nil, "", err
+	}
+	code, err := randomHex(12)
+	if err != nil {
+		return nil, "", err
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	invite := &entity.Invite{
+		ID:        id,
+		CodeHash:  hashAPISecret(code),
+		MaxUses:   req.MaxUses,
+		CreatedBy: admin.ID,
+		CreatedAt: now,
+	}
+	if req.TTL > 0 {
+		expiresAt := now.Add(req.TTL)
+		invite.ExpiresAt = &expiresAt
+	}
+	if err := i.repo.Create(ctx, invite); err != nil {
+		return nil, "", err
+	}
+
+	i.audit(ctx, admin.ID, "invite.issue", invite.ID)
+	return invite, code, nil
+}
+
+// List возвращает все приглашения без кодов
+func (i *Invites) List(ctx context.Context, adminID string) ([]*entity.Invite, error) {
+	if _, err := i.admin(ctx, adminID); err != nil {
+		return nil, err
+	}
+	return i.repo.List(ctx)
+}
+
+// Revoke отзывает приглашение id; уже созданные по нему аккаунты не затрагиваются
+func (i *Invites) Revoke(ctx context.Context, adminID, id string) error {
+	admin, err := i.admin(ctx, adminID)
+	if err != nil {
+		return err
+	}
+	if err := i.repo.Revoke(ctx, id, time.Now()); err != nil {
+		return err
+	}
+	i.audit(ctx, admin.ID, "invite.revoke", id)
+	return nil
+}
+
+// consume засчитывает использование приглашения с кодом code и возвращает его ID
+func (i *Invites) consume(ctx context.Context, code string) (string, error) {
+	code = strings.TrimSpace(code)
+	if code == "" {
+		return "", entity.ErrInviteRequired
+	}
+	return i.repo.Consume(ctx, hashAPISecret(code), time.Now())
+}
+
+// release возвращает использование приглашения id после неудачной регистрации
+func (i *Invites) release(ctx context.Context, id string) {
+	if err := i.repo.Release(ctx, id); err != nil {
+		i.auth.log.Error("Failed to release invite",
+			logger.String("invite_id", id),
+			logger.Error(err))
+	}
+}
+
+// admin проверяет администратора; под имперсонацией приглашениями управлять нельзя
+func (i *Invites) admin(ctx context.Context, adminID string) (*entity.User, error) {
+	if _, ok := audit.ActingAdminFromContext(ctx); ok {
+		return nil, entity.ErrImpersonationForbidden
+	}
+	return i.auth.requireAdmin(ctx, adminID)
+}
+
+func (i *Invites) audit(ctx context.Context, adminID, action, inviteID string) {
+	err := i.auth.audit.Write(ctx, &audit.Entry{
+		ActorID:    adminID,
+		Action:     action,
+		TargetType: "invite",
+		TargetID:   inviteID,
+	})
+	if err != nil {
+		i.auth.log.Error("Failed to write audit entry",
+			logger.String("invite_id", inviteID),
+			logger.Error(err))
+	}
+}
@@ -0,0 +1,126 @@
+package auth
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/kprf42/dolgova/auth_service/internal/entity"
+	"github.com/kprf42/dolgova/auth_service/internal/repository"
+	"github.com/kprf42/dolgova/auth_service/internal/usecase/jwt"
+	"github.com/kprf42/dolgova/pkg/logger"
+)
+
+// LinkSender доставляет пользователю ссылку для входа
+type LinkSender interface {
+	SendLoginLink(ctx context.Context, email, link string) error
+}
+
+// MagicLinkConfig параметры входа по ссылке
+type MagicLinkConfig struct {
+	URL string        // Адрес страницы входа; токен добавляется параметром token
+	TTL time.Duration // Время жизни ссылки
+}
+
+// MagicLinks вход без пароля по одноразовой подписанной ссылке из письма
+type MagicLinks struct {
+	auth   *AuthUseCase
+	sender LinkSender
+	cfg    MagicLinkConfig
+}
+
+func NewMagicLinks(authUC *AuthUseCase, sender LinkSender, cfg MagicLinkConfig) *MagicLinks {
+	return &MagicLinks{
+		auth:   authUC,
+		sender: sender,
+		cfg:    cfg,
+	}
+}
+
+// Send отправляет ссылку для входа на email. Для неизвестного адреса ничего не отправляется
+// и ошибка не возвращается, чтобы по ответу нельзя было проверить наличие аккаунта.
+func (m *MagicLinks) Send(ctx context.Context, email string) error {
+	email = strings.ToLower(strings.TrimSpace(email))
+	if !isValidEmail(email) {
+		return entity.ErrInvalidEmail
+	}
+
+	user, err := m.auth.repo.GetUserByEmail(ctx, email)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		m.auth.log.Warn("Magic link requested for unknown email",
+			logger.String("email", email))
+		return nil
+	}
+
+	token, _, err := m.auth.jwt.GeneratePurposeToken(user.ID, jwt.PurposeMagicLink, m.cfg.TTL)
+	if err != nil {
+		return err
+	}
+
+	link, err := url.Parse(m.cfg.URL)
+	if err != nil {
+		return err
+	}
+	query := link.Query()
+	query.Set("token", token)
+	link.RawQuery = query.Encode()
+
+	if err := m.sender.SendLoginLink(ctx, user.Email, link.String()); err != nil {
+		m.auth.log.Error("Failed to send magic link",
+			logger.String("user_id", user.ID),
+			logger.Error(err))
+		return err
+	}
+
+	m.auth.log.Info("Magic link sent",
+		logger.String("user_id", user.ID))
+	return nil
+}
+
+// Exchange обменивает токен из ссылки на пару токенов новой сессии клиента client. Ссылка действует один раз.
+func (m *MagicLinks) Exchange(ctx context.Context, token string, client entity.ClientInfo) (*entity.TokenDetails, error) {
+	claims, err := m.auth.jwt.ValidatePurposeToken(ctx, token, jwt.PurposeMagicLink)
+	if err != nil {
+		m.auth.log.Warn("Invalid magic link token",
+			logger.Error(err))
+		return nil, entity.ErrInvalidToken
+	}
+
+	first, err := m.auth.revoked.RevokeOnce(ctx, claims.ID, claims.UserID, claims.ExpiresAt.Time)
+	if err != nil {
+		return nil, err
+	}
+	if !first {
+		m.auth.log.Warn("Magic link reused",
+			logger.String("user_id", claims.UserID))
+		return nil, entity.ErrInvalidToken
+	}
+
+	user, err := m.auth.repo.GetUserByID(ctx, claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, entity.ErrInvalidToken
+	}
+
+	// Ссылка не обходит блокировку после подбора пароля
+	if err := m.auth.checkLock(ctx, repository.LoginFailureUser, user.ID, time.Now()); err != nil {
+		m.auth.recordEvent(ctx, user.ID, entity.AuthEventLoginFailure, entity.AuthMethodMagicLink, client, failureLocked)
+		return nil, err
+	}
+
+	tokens, err := m.auth.startSession(ctx, user, client)
+	if err != nil {
+		return nil, err
+	}
+
+	m.auth.recordEvent(ctx, user.ID, entity.AuthEventLoginSuccess, entity.AuthMethodMagicLink, client, "")
+	m.auth.log.Info("Successfully logged in user by magic link",
+		logger.String("user_id", user.ID))
+	return tokens, nil
+}
@@ -0,0 +1,139 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/kprf42/dolgova/auth_service/internal/entity"
+)
+
+func TestMagicLinkExchange(t *testing.T) {
+	ctx := context.Background()
+	uc, _ := newTestAuthUseCase(t, LockoutPolicy{MaxFailures: 1, Duration: time.Hour})
+	registerTestUser(t, uc, "alice")
+	registerTestUser(t, uc, "bob")
+
+	sender := &linkRecorder{}
+	links := NewMagicLinks(uc, sender, MagicLinkConfig{URL: "https://forum.example.com/login", TTL: time.Minute})
+
+	// Пользователь bob заблокирован неудачным входом
+	if _, err := uc.Login(ctx, "bob", "wrong password", entity.ClientInfo{}); err == nil {
+		t.Fatal("login with wrong password succeeded")
+	}
+
+	tests := []struct {
+		name    string
+		token   func(t *testing.T) string
+		wantErr error
+	}{
+		{
+			name:  "valid link",
+			token: func(t *testing.T) string { return sender.send(t, links, "alice@example.com") },
+		},
+		{
+			name: "link is used once",
+			token: func(t *testing.T) string {
+				token := sender.send(t, links, "alice@example.com")
+				if _, err := links.Exchange(ctx, token, entity.ClientInfo{}); err != nil {
+					t.Fatalf("first exchange: %v", err)
+				}
+				return token
+			},
+			wantErr: entity.ErrInvalidToken,
+		},
+		{
+			name: "access token is not a link",
+			token: func(t *testing.T) string {
+				tokens, err := uc.Login(ctx, "alice", testPassword, entity.ClientInfo{})
+				if err != nil {
+					t.Fatalf("login: %v", err)
+				}
+				return tokens.AccessToken
+			},
+			wantErr: entity.ErrInvalidToken,
+		},
+		{
+			name:    "forged token",
+			token:   func(t *testing.T) string { return "not-a-jwt" },
+			wantErr: entity.ErrInvalidToken,
+		},
+		{
+			name:    "link does not bypass lockout",
+			token:   func(t *testing.T) string { return sender.send(t, links, "bob@example.com") },
+			wantErr: &entity.AccountLockedError{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens, err := links.Exchange(ctx, tt.token(t), entity.ClientInfo{})
+			switch want := tt.wantErr.(type) {
+			case nil:
+				if err != nil || tokens == nil || tokens.AccessToken == "" {
+					t.Fatalf("exchange: %v", err)
+				}
+			case *entity.AccountLockedError:
+				if !errors.As(err, &want) {
+					t.Fatalf("error %v, want AccountLockedError", err)
+				}
+			default:
+				if !errors.Is(err, want) {
+					t.Fatalf("error %v, want %v", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestMagicLinkTokenIsNotAccessToken(t *testing.T) {
+	ctx := context.Background()
+	uc, _ := newTestAuthUseCase(t, LockoutPolicy{})
+	registerTestUser(t, uc, "alice")
+
+	sender := &linkRecorder{}
+	links := NewMagicLinks(uc, sender, MagicLinkConfig{URL: "https://forum.example.com/login", TTL: time.Minute})
+	token := sender.send(t, links, "alice@example.com")
+
+	if _, err := uc.jwt.ValidateToken(ctx, token); err == nil {
+		t.Fatal("magic link token accepted as access token")
+	}
+}
+
+func TestMagicLinkUnknownEmail(t *testing.T) {
+	uc, _ := newTestAuthUseCase(t, LockoutPolicy{})
+	sender := &linkRecorder{}
+	links := NewMagicLinks(uc, sender, MagicLinkConfig{URL: "https://forum.example.com/login", TTL: time.Minute})
+
+	if err := links.Send(context.Background(), "nobody@example.com"); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if sender.link != "" {
+		t.Fatalf("link sent to unknown email: %s", sender.link)
+	}
+}
+
+// linkRecorder запоминает последнюю отправленную ссылку
+type linkRecorder struct {
+	link string
+}
+
+func (r *linkRecorder) SendLoginLink(ctx context.Context, email, link string) error {
+	r.link = link
+	return nil
+}
+
+// send отправляет ссылку на email и возвращает токен из нее
+func (r *linkRecorder) send(t *testing.T, links *MagicLinks, email string) string {
+	t.Helper()
+	if err := links.Send(context.Background(), email); err != nil {
+		t.Fatalf("send link: %v", err)
+	}
+	u, err := url.Parse(r.link)
+	if err != nil {
+		t.Fatalf("parse link: %v", err)
+	}
+	return u.Query().Get("token")
+}
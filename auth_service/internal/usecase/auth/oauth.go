@@ -0,0 +1,78 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+)
+
+// ErrOAuthExchange провайдер не принял код авторизации или не вернул профиль
+var ErrOAuthExchange = errors.New("oauth code exchange failed")
+
+// ExternalProfile профиль пользователя у внешнего провайдера входа
+type ExternalProfile struct {
+	ExternalID    string // Неизменяемый ID пользователя у провайдера
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+// OAuthProvider внешний провайдер входа по OAuth 2.0 authorization code flow
+type OAuthProvider interface {
+	// Name имя провайдера в маршрутах и в linked_accounts
+	Name() string
+	// AuthCodeURL адрес страницы согласия; state вернется в callback без изменений
+	AuthCodeURL(state string) string
+	// Exchange обменивает код авторизации на профиль пользователя
+	Exchange(ctx context.Context, code string) (*ExternalProfile, error)
+}
+
+// OAuthProviders реестр включенных провайдеров входа
+type OAuthProviders struct {
+	providers map[string]OAuthProvider
+}
+
+func NewOAuthProviders() *OAuthProviders {
+	return &OAuthProviders{providers: make(map[string]OAuthProvider)}
+}
+
+// Register добавляет провайдера; провайдер с тем же именем заменяется
+func (p *OAuthProviders) Register(provider OAuthProvider) {
+	p.providers[provider.Name()] = provider
+}
+
+// Get возвращает провайдера по имени
+func (p *OAuthProviders) Get(name string) (OAuthProvider, bool) {
+	provider, ok := p.providers[name]
+	return provider, ok
+}
+
+// Names имена включенных провайдеров по алфавиту
+func (p *OAuthProviders) Names() []string {
+	names := make([]string, 0, len(p.providers))
+	for name := range p.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// oauthJSON выполняет запрос к провайдеру и разбирает JSON ответ в out
+func oauthJSON(client *http.Client, req *http.Request, out interface{}) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrOAuthExchange, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: %s returned %d", ErrOAuthExchange, req.URL.Host, resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("%w: %v", ErrOAuthExchange, err)
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// pepperPrefix начало хеша, посчитанного с перцем: "$pepper$<id>$<bcrypt>".
+// Хеши без префикса посчитаны от самого пароля, как до появления перца.
+const pepperPrefix = "$pepper$"
+
+// Pepper секрет сервера, который подмешивается к паролю перед bcrypt. В отличие от соли
+// он не хранится в БД, поэтому утечка одной только таблицы users не позволяет перебирать пароли.
+type Pepper struct {
+	ID     string // Сохраняется вместе с хешем, чтобы после ротации проверять старые хеши
+	Secret string
+}
+
+// PasswordHasher хеширует пароли текущим перцем и проверяет хеши, посчитанные любым
+// из известных перцев или без перца. Хеши со старым перцем пересчитываются при входе (NeedsRehash).
+type PasswordHasher struct {
+	peppers map[string][]byte
+	current string // ID перца для новых хешей; пусто - без перца
+}
+
+// NewPasswordHasher создает хешер. current - ID перца для новых хешей (пусто - без перца),
+// peppers - все перцы, которыми могли быть посчитаны сохраненные хеши.
+func NewPasswordHasher(peppers []Pepper, current string) (*PasswordHasher, error) {
+	h := &PasswordHasher{peppers: make(map[string][]byte, len(peppers)), current: current}
+	for _, p := range peppers {
+		if p.ID == "" || strings.Contains(p.ID, "$") {
+			return nil, fmt.Errorf("invalid pepper id %q", p.ID)
+		}
+		if p.Secret == "" {
+			return nil, fmt.Errorf("pepper %q has no secret", p.ID)
+		}
+		h.peppers[p.ID] = []byte(p.Secret)
+	}
+	if _, ok := h.peppers[current]; current != "" && !ok {
+		return nil, fmt.Errorf("current pepper %q is not configured", current)
+	}
+	return h, nil
+}
+
+// Hash хеширует пароль текущим перцем
+func (h *PasswordHasher) Hash(password string) (string, error) {
+	input := []byte(password)
+	if h.current != "" {
+		input = h.pepper(h.current, password)
+	}
+	hash, err := bcrypt.GenerateFromPassword(input, bcrypt.DefaultCost)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash password: %w", err)
+	}
+	if h.current == "" {
+		return string(hash), nil
+	}
+	return pepperPrefix + h.current + "$" + string(hash), nil
+}
+
+// Compare проверяет пароль. Возвращает bcrypt.ErrMismatchedHashAndPassword, если пароль
+// не подходит, и ошибку, если хеш посчитан перцем, которого нет в конфигурации.
+func (h *PasswordHasher) Compare(hash, password string) error {
+	id, bcryptHash := splitPepper(hash)
+	input := []byte(password)
+	if id != "" {
+		if _, ok := h.peppers[id]; !ok {
+			return fmt.Errorf("password hash uses unknown pepper %q", id)
+		}
+		input = h.pepper(id, password)
+	}
+	return bcrypt.CompareHashAndPassword([]byte(bcryptHash), input)
+}
+
+// NeedsRehash сообщает, что хеш посчитан не текущим перцем и его стоит пересчитать после входа
+func (h *PasswordHasher) NeedsRehash(hash string) bool {
+	id, _ := splitPepper(hash)
+	return id != h.current
+}
+
+// pepper HMAC-SHA256 пароля с перцем id. Результат в base64 короче 72 байт,
+// которые учитывает bcrypt, поэтому длинные пароли не обрезаются.
+func (h *PasswordHasher) pepper(id, password string) []byte {
+	mac := hmac.New(sha256.New, h.peppers[id])
+	mac.Write([]byte(password))
+	sum := mac.Sum(nil)
+	out := make([]byte, base64.RawStdEncoding.EncodedLen(len(sum)))
+	base64.RawStdEncoding.Encode(out, sum)
+	return out
+}
+
+// splitPepper разделяет сохраненный хеш на ID перца (пусто - без перца) и хеш bcrypt
+func splitPepper(hash string) (id, bcryptHash string) {
+	rest, ok := strings.CutPrefix(hash, pepperPrefix)
+	if !ok {
+		return "", hash
+	}
+	id, bcryptHash, ok = strings.Cut(rest, "$")
+	if !ok {
+		return "", hash
+	}
+	return id, bcryptHash
+}
+
+// isMismatch пароль не подошел, в отличие от ошибок конфигурации или поврежденного хеша
+func isMismatch(err error) bool {
+	return errors.Is(err, bcrypt.ErrMismatchedHashAndPassword)
+}
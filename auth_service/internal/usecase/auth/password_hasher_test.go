@@ -0,0 +1,154 @@
+package auth
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/kprf42/dolgova/auth_service/internal/entity"
+)
+
+func TestPasswordHasherRotation(t *testing.T) {
+	old := Pepper{ID: "p1", Secret: "old secret"}
+	current := Pepper{ID: "p2", Secret: "new secret"}
+
+	tests := []struct {
+		name        string
+		hashedWith  *PasswordHasher
+		checkedWith *PasswordHasher
+		password    string
+		wantMatch   bool
+		wantErr     bool
+		wantRehash  bool
+	}{
+		{
+			name:        "hash without pepper after pepper is enabled",
+			hashedWith:  mustHasher(t, nil, ""),
+			checkedWith: mustHasher(t, []Pepper{current}, "p2"),
+			password:    testPassword,
+			wantMatch:   true,
+			wantRehash:  true,
+		},
+		{
+			name:        "hash with old pepper after rotation",
+			hashedWith:  mustHasher(t, []Pepper{old}, "p1"),
+			checkedWith: mustHasher(t, []Pepper{old, current}, "p2"),
+			password:    testPassword,
+			wantMatch:   true,
+			wantRehash:  true,
+		},
+		{
+			name:        "hash with current pepper",
+			hashedWith:  mustHasher(t, []Pepper{current}, "p2"),
+			checkedWith: mustHasher(t, []Pepper{old, current}, "p2"),
+			password:    testPassword,
+			wantMatch:   true,
+		},
+		{
+			name:        "wrong password",
+			hashedWith:  mustHasher(t, []Pepper{current}, "p2"),
+			checkedWith: mustHasher(t, []Pepper{current}, "p2"),
+			password:    "wrong password",
+		},
+		{
+			name:        "old pepper removed from config",
+			hashedWith:  mustHasher(t, []Pepper{old}, "p1"),
+			checkedWith: mustHasher(t, []Pepper{current}, "p2"),
+			password:    testPassword,
+			wantErr:     true,
+			wantRehash:  true,
+		},
+		{
+			name:        "same pepper id with another secret",
+			hashedWith:  mustHasher(t, []Pepper{old}, "p1"),
+			checkedWith: mustHasher(t, []Pepper{{ID: "p1", Secret: "leaked db only"}}, "p1"),
+			password:    testPassword,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hash, err := tt.hashedWith.Hash(testPassword)
+			if err != nil {
+				t.Fatalf("hash: %v", err)
+			}
+
+			err = tt.checkedWith.Compare(hash, tt.password)
+			switch {
+			case tt.wantErr:
+				if err == nil || isMismatch(err) {
+					t.Fatalf("compare error %v, want configuration error", err)
+				}
+			case tt.wantMatch:
+				if err != nil {
+					t.Fatalf("compare: %v", err)
+				}
+			default:
+				if !isMismatch(err) {
+					t.Fatalf("compare error %v, want mismatch", err)
+				}
+			}
+
+			if got := tt.checkedWith.NeedsRehash(hash); got != tt.wantRehash {
+				t.Fatalf("needs rehash %v, want %v", got, tt.wantRehash)
+			}
+		})
+	}
+}
+
+func TestNewPasswordHasherValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		peppers []Pepper
+		current string
+	}{
+		{"unknown current pepper", []Pepper{{ID: "p1", Secret: "s"}}, "p2"},
+		{"empty id", []Pepper{{ID: "", Secret: "s"}}, ""},
+		{"id with separator", []Pepper{{ID: "p$1", Secret: "s"}}, "p$1"},
+		{"empty secret", []Pepper{{ID: "p1"}}, "p1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewPasswordHasher(tt.peppers, tt.current); err == nil {
+				t.Fatal("invalid pepper config accepted")
+			}
+		})
+	}
+}
+
+// Вход пересчитывает хеш со старым перцем текущим, и пароль продолжает подходить
+func TestLoginRehashesWithCurrentPepper(t *testing.T) {
+	ctx := context.Background()
+	uc, _ := newTestAuthUseCase(t, LockoutPolicy{})
+	uc.PepperPasswords(mustHasher(t, []Pepper{{ID: "p1", Secret: "old secret"}}, "p1"))
+	user := registerTestUser(t, uc, "alice")
+
+	uc.PepperPasswords(mustHasher(t, []Pepper{{ID: "p1", Secret: "old secret"}, {ID: "p2", Secret: "new secret"}}, "p2"))
+	if _, err := uc.Login(ctx, "alice", testPassword, entity.ClientInfo{}); err != nil {
+		t.Fatalf("login with old pepper: %v", err)
+	}
+
+	stored, err := uc.repo.GetUserByID(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("get user: %v", err)
+	}
+	if !strings.HasPrefix(stored.Password, pepperPrefix+"p2$") {
+		t.Fatalf("hash %q was not rehashed with current pepper", stored.Password)
+	}
+
+	// Старый перец можно убрать из конфигурации
+	uc.PepperPasswords(mustHasher(t, []Pepper{{ID: "p2", Secret: "new secret"}}, "p2"))
+	if _, err := uc.Login(ctx, "alice", testPassword, entity.ClientInfo{}); err != nil {
+		t.Fatalf("login after old pepper removed: %v", err)
+	}
+}
+
+func mustHasher(t *testing.T, peppers []Pepper, current string) *PasswordHasher {
+	t.Helper()
+	hasher, err := NewPasswordHasher(peppers, current)
+	if err != nil {
+		t.Fatalf("create hasher: %v", err)
+	}
+	return hasher
+}
@@ -0,0 +1,117 @@
+package auth
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/kprf42/dolgova/auth_service/internal/entity"
+)
+
+// bcryptMaxBytes bcrypt учитывает только первые 72 байта пароля
+const bcryptMaxBytes = 72
+
+//go:embed common_passwords.txt
+var commonPasswords string
+
+// PasswordPolicyConfig настройки парольной политики
+type PasswordPolicyConfig struct {
+	MinLength  int    // Минимальная длина в символах
+	MaxLength  int    // Максимальная длина в символах; не больше 72 байт в любом случае
+	MinClasses int    // Сколько классов символов (строчные, заглавные, цифры, прочие) должно быть в пароле
+	BanCommon  bool   // Запрещать распространенные пароли из встроенного списка
+	BannedFile string // Файл с дополнительными запрещенными паролями, по одному в строке
+}
+
+// PasswordPolicy проверяет новые пароли на соответствие политике
+type PasswordPolicy struct {
+	cfg    PasswordPolicyConfig
+	banned map[string]struct{} // В нижнем регистре
+}
+
+// NewPasswordPolicy создает политику и загружает списки запрещенных паролей
+func NewPasswordPolicy(cfg PasswordPolicyConfig) (*PasswordPolicy, error) {
+	p := &PasswordPolicy{
+		cfg:    cfg,
+		banned: make(map[string]struct{}),
+	}
+	if cfg.BanCommon {
+		p.addBanned(commonPasswords)
+	}
+	if cfg.BannedFile != "" {
+		data, err := os.ReadFile(cfg.BannedFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read banned passwords: %w", err)
+		}
+		p.addBanned(string(data))
+	}
+	return p, nil
+}
+
+func (p *PasswordPolicy) addBanned(list string) {
+	scanner := bufio.NewScanner(strings.NewReader(list))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		p.banned[strings.ToLower(line)] = struct{}{}
+	}
+}
+
+// Validate возвращает PasswordPolicyError со всеми нарушенными правилами или nil
+func (p *PasswordPolicy) Validate(password string) error {
+	var violations []entity.PasswordViolation
+
+	length := utf8.RuneCountInString(password)
+	if length < p.cfg.MinLength {
+		violations = append(violations, entity.PasswordViolation{Rule: entity.PasswordRuleMinLength, Limit: p.cfg.MinLength})
+	}
+	if (p.cfg.MaxLength > 0 && length > p.cfg.MaxLength) || len(password) > bcryptMaxBytes {
+		limit := p.cfg.MaxLength
+		if limit <= 0 || limit > bcryptMaxBytes {
+			limit = bcryptMaxBytes
+		}
+		violations = append(violations, entity.PasswordViolation{Rule: entity.PasswordRuleMaxLength, Limit: limit})
+	}
+	if p.cfg.MinClasses > 0 && charClasses(password) < p.cfg.MinClasses {
+		violations = append(violations, entity.PasswordViolation{Rule: entity.PasswordRuleCharClasses, Limit: p.cfg.MinClasses})
+	}
+	if _, ok := p.banned[strings.ToLower(password)]; ok {
+		violations = append(violations, entity.PasswordViolation{Rule: entity.PasswordRuleCommon})
+	}
+
+	if len(violations) > 0 {
+		return &entity.PasswordPolicyError{Violations: violations}
+	}
+	return nil
+}
+
+// charClasses число классов символов в пароле: строчные, заглавные, цифры, прочие
+func charClasses(password string) int {
+	var lower, upper, digit, other bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			other = true
+		}
+	}
+
+	n := 0
+	for _, ok := range []bool{lower, upper, digit, other} {
+		if ok {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,120 @@
+package auth
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/kprf42/dolgova/auth_service/internal/entity"
+	"github.com/kprf42/dolgova/pkg/logger"
+)
+
+// Ограничения полей профиля (в символах)
+const (
+	maxUsernameLength    = 32
+	maxDisplayNameLength = 64
+	maxBioLength         = 500
+	maxAvatarURLLength   = 2048
+)
+
+// MaxUsersLookup сколько пользователей можно запросить за один вызов GetUsers
+const MaxUsersLookup = 100
+
+// ProfileUpdate изменяемые поля профиля; nil - поле не меняется
+type ProfileUpdate struct {
+	Username    *string
+	DisplayName *string
+	Bio         *string
+	AvatarURL   *string
+}
+
+// GetProfile возвращает пользователя с полями профиля
+func (uc *AuthUseCase) GetProfile(ctx context.Context, userID string) (*entity.User, error) {
+	user, err := uc.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, entity.ErrUserNotFound
+	}
+	return user, nil
+}
+
+// GetUsers возвращает пользователей по ID для отображения авторов. Повторы и пустые ID
+// отбрасываются, отсутствующие пользователи пропускаются.
+func (uc *AuthUseCase) GetUsers(ctx context.Context, ids []string) ([]*entity.User, error) {
+	unique := make([]string, 0, len(ids))
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		unique = append(unique, id)
+	}
+	if len(unique) > MaxUsersLookup {
+		return nil, entity.ErrTooManyUsers
+	}
+	return uc.repo.GetUsersByIDs(ctx, unique)
+}
+
+// UpdateProfile меняет имя пользователя и поля профиля и возвращает обновленного пользователя
+func (uc *AuthUseCase) UpdateProfile(ctx context.Context, userID string, update ProfileUpdate) (*entity.User, error) {
+	user, err := uc.GetProfile(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if update.Username != nil {
+		username := strings.TrimSpace(*update.Username)
+		if username == "" {
+			return nil, entity.ErrEmptyUsername
+		}
+		if utf8.RuneCountInString(username) > maxUsernameLength {
+			return nil, entity.ErrProfileFieldTooLong
+		}
+		user.Username = username
+	}
+	if update.DisplayName != nil {
+		displayName := strings.TrimSpace(*update.DisplayName)
+		if utf8.RuneCountInString(displayName) > maxDisplayNameLength {
+			return nil, entity.ErrProfileFieldTooLong
+		}
+		user.DisplayName = displayName
+	}
+	if update.Bio != nil {
+		bio := strings.TrimSpace(*update.Bio)
+		if utf8.RuneCountInString(bio) > maxBioLength {
+			return nil, entity.ErrProfileFieldTooLong
+		}
+		user.Bio = bio
+	}
+	if update.AvatarURL != nil {
+		avatarURL := strings.TrimSpace(*update.AvatarURL)
+		if avatarURL != "" && !isValidAvatarURL(avatarURL) {
+			return nil, entity.ErrInvalidAvatarURL
+		}
+		user.AvatarURL = avatarURL
+	}
+
+	if err := uc.repo.UpdateUser(ctx, user); err != nil {
+		return nil, err
+	}
+
+	uc.log.Info("Updated user profile",
+		logger.String("user_id", user.ID))
+	return user, nil
+}
+
+// isValidAvatarURL аватар задается абсолютной http(s) ссылкой
+func isValidAvatarURL(raw string) bool {
+	if len(raw) > maxAvatarURLLength {
+		return false
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return false
+	}
+	return u.Scheme == "https" || u.Scheme == "http"
+}
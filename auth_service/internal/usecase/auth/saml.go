@@ -0,0 +1,294 @@
+package auth
+
+import (
+	"context"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/crewjam/saml"
+)
+
+// SAMLProviderPrefix префикс имени SAML провайдера в linked_accounts: внешние ID разных IdP не пересекаются
+const SAMLProviderPrefix = "saml:"
+
+// ErrSAMLResponse ответ IdP не прошел проверку: подпись, аудитория, сроки или InResponseTo
+var ErrSAMLResponse = errors.New("invalid saml response")
+
+// Атрибуты по умолчанию: имена из eduPerson/LDAP и их OID, как их отправляют распространенные IdP
+var (
+	defaultSAMLEmailAttributes = []string{"email", "mail", "urn:oid:0.9.2342.19200300.100.1.3",
+		"http://schemas.xmlsoap.org/ws/2005/05/identity/claims/emailaddress"}
+	defaultSAMLNameAttributes = []string{"displayName", "urn:oid:2.16.840.1.113730.3.1.241",
+		"http://schemas.xmlsoap.org/ws/2005/05/identity/claims/name"}
+)
+
+// SAMLConfig параметры SP для одного IdP
+type SAMLConfig struct {
+	Name        string // Имя IdP в маршрутах /auth/saml/{name}
+	BaseURL     string // Внешний адрес auth сервиса; из него строятся entityID и адрес ACS
+	Key         *rsa.PrivateKey
+	Certificate *x509.Certificate
+	IDPMetadata *saml.EntityDescriptor
+
+	EmailAttribute string // Атрибут с email; пусто - стандартные имена
+	NameAttribute  string // Атрибут с отображаемым именем; пусто - стандартные имена
+	IDAttribute    string // Атрибут с постоянным ID пользователя; пусто - NameID
+
+	TrustEmail   bool     // Email из утверждения подтвержден в любом домене
+	EmailDomains []string // Домены, email в которых подтвержден
+}
+
+// SAMLProvider SP для одного IdP: метаданные, запрос аутентификации и проверка ответа.
+// Подпись утверждения не делает email подтвержденным: IdP может вписать любой адрес,
+// поэтому email подтвержден только при TrustEmail или в доменах EmailDomains.
+type SAMLProvider struct {
+	name    string
+	sp      *saml.ServiceProvider
+	email   []string
+	names   []string
+	id      string
+	trust   bool
+	domains []string
+}
+
+func NewSAMLProvider(cfg SAMLConfig) (*SAMLProvider, error) {
+	base, err := url.Parse(strings.TrimSuffix(cfg.BaseURL, "/"))
+	if err != nil || !base.IsAbs() {
+		return nil, fmt.Errorf("saml %s: base url %q must be absolute", cfg.Name, cfg.BaseURL)
+	}
+	metadataURL := base.JoinPath("auth", "saml", cfg.Name, "metadata")
+	acsURL := base.JoinPath("auth", "saml", cfg.Name, "acs")
+
+	p := &SAMLProvider{
+		name: cfg.Name,
+		sp: &saml.ServiceProvider{
+			EntityID:    metadataURL.String(),
+			Key:         cfg.Key,
+			Certificate: cfg.Certificate,
+			MetadataURL: *metadataURL,
+			AcsURL:      *acsURL,
+			IDPMetadata: cfg.IDPMetadata,
+			// Ответ принимается только на запрос, отправленный из этого браузера
+			AllowIDPInitiated: false,
+		},
+		email: defaultSAMLEmailAttributes,
+		names: defaultSAMLNameAttributes,
+		id:    cfg.IDAttribute,
+		trust: cfg.TrustEmail,
+	}
+	for _, domain := range cfg.EmailDomains {
+		p.domains = append(p.domains, strings.ToLower(strings.TrimSpace(domain)))
+	}
+	if cfg.EmailAttribute != "" {
+		p.email = []string{cfg.EmailAttribute}
+	}
+	if cfg.NameAttribute != "" {
+		p.names = []string{cfg.NameAttribute}
+	}
+	if p.sp.GetSSOBindingLocation(saml.HTTPRedirectBinding) == "" {
+		return nil, fmt.Errorf("saml %s: idp metadata has no HTTP-Redirect SSO endpoint", cfg.Name)
+	}
+	return p, nil
+}
+
+// Name имя IdP в маршрутах
+func (p *SAMLProvider) Name() string {
+	return p.name
+}
+
+// LinkedName имя провайдера в linked_accounts
+func (p *SAMLProvider) LinkedName() string {
+	return SAMLProviderPrefix + p.name
+}
+
+// Metadata XML метаданные SP для регистрации в IdP
+func (p *SAMLProvider) Metadata() ([]byte, error) {
+	data, err := xml.MarshalIndent(p.sp.Metadata(), "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal saml metadata: %w", err)
+	}
+	return append([]byte(xml.Header), data...), nil
+}
+
+// AuthnRequest создает запрос аутентификации и возвращает адрес IdP для перехода
+// и ID запроса, который должен прийти в InResponseTo ответа
+func (p *SAMLProvider) AuthnRequest(relayState string) (redirect string, requestID string, err error) {
+	req, err := p.sp.MakeAuthenticationRequest(p.sp.GetSSOBindingLocation(saml.HTTPRedirectBinding),
+		saml.HTTPRedirectBinding, saml.HTTPPostBinding)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to make saml authn request: %w", err)
+	}
+	u, err := req.Redirect(relayState, p.sp)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to make saml authn request: %w", err)
+	}
+	return u.String(), req.ID, nil
+}
+
+// ParseResponse проверяет ответ IdP (base64 SAMLResponse из POST формы) на запрос requestID
+// и возвращает профиль пользователя из утверждения
+func (p *SAMLProvider) ParseResponse(samlResponse, requestID string) (*ExternalProfile, error) {
+	raw, err := base64.StdEncoding.DecodeString(samlResponse)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrSAMLResponse, err)
+	}
+
+	assertion, err := p.sp.ParseXMLResponse(raw, []string{requestID})
+	if err != nil {
+		// Error() у ошибки проверки всегда "Authentication failed", причина в PrivateErr
+		var invalid *saml.InvalidResponseError
+		if errors.As(err, &invalid) && invalid.PrivateErr != nil {
+			err = invalid.PrivateErr
+		}
+		return nil, fmt.Errorf("%w: %v", ErrSAMLResponse, err)
+	}
+
+	profile := &ExternalProfile{
+		Email: samlAttribute(assertion, p.email),
+		Name:  samlAttribute(assertion, p.names),
+	}
+	profile.EmailVerified = p.emailVerified(profile.Email)
+	switch {
+	case p.id != "":
+		profile.ExternalID = samlAttribute(assertion, []string{p.id})
+	case assertion.Subject != nil && assertion.Subject.NameID != nil:
+		profile.ExternalID = assertion.Subject.NameID.Value
+	}
+	if profile.ExternalID == "" {
+		return nil, fmt.Errorf("%w: assertion has no user id", ErrSAMLResponse)
+	}
+	return profile, nil
+}
+
+// emailVerified отвечает ли IdP за адрес email
+func (p *SAMLProvider) emailVerified(email string) bool {
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return false
+	}
+	return p.trust || slices.Contains(p.domains, strings.ToLower(email[at+1:]))
+}
+
+// samlAttribute первое значение первого найденного атрибута по имени или FriendlyName
+func samlAttribute(assertion *saml.Assertion, names []string) string {
+	for _, name := range names {
+		for _, statement := range assertion.AttributeStatements {
+			for _, attr := range statement.Attributes {
+				if (attr.Name == name || attr.FriendlyName == name) && len(attr.Values) > 0 {
+					return strings.TrimSpace(attr.Values[0].Value)
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// SAMLProviders реестр настроенных IdP
+type SAMLProviders struct {
+	providers map[string]*SAMLProvider
+}
+
+func NewSAMLProviders() *SAMLProviders {
+	return &SAMLProviders{providers: make(map[string]*SAMLProvider)}
+}
+
+// Register добавляет IdP; IdP с тем же именем заменяется
+func (p *SAMLProviders) Register(provider *SAMLProvider) {
+	p.providers[provider.Name()] = provider
+}
+
+// Get возвращает IdP по имени
+func (p *SAMLProviders) Get(name string) (*SAMLProvider, bool) {
+	provider, ok := p.providers[name]
+	return provider, ok
+}
+
+// Names имена настроенных IdP по алфавиту
+func (p *SAMLProviders) Names() []string {
+	names := make([]string, 0, len(p.providers))
+	for name := range p.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LoadSAMLKeyPair читает сертификат и RSA ключ SP из PEM файлов
+func LoadSAMLKeyPair(certFile, keyFile string) (*rsa.PrivateKey, *x509.Certificate, error) {
+	pair, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load saml key pair: %w", err)
+	}
+	key, ok := pair.PrivateKey.(*rsa.PrivateKey)
+	if !ok {
+		return nil, nil, errors.New("saml key must be an RSA private key")
+	}
+	cert, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse saml certificate: %w", err)
+	}
+	return key, cert, nil
+}
+
+// LoadSAMLMetadata читает метаданные IdP из файла или по адресу (http/https)
+func LoadSAMLMetadata(ctx context.Context, source string) (*saml.EntityDescriptor, error) {
+	var data []byte
+	var err error
+	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+		data, err = fetchSAMLMetadata(ctx, source)
+	} else {
+		data, err = os.ReadFile(source)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load saml metadata: %w", err)
+	}
+
+	// Метаданные бывают как одним EntityDescriptor, так и списком EntitiesDescriptor
+	var entity saml.EntityDescriptor
+	if err := xml.Unmarshal(data, &entity); err == nil && entity.EntityID != "" {
+		return &entity, nil
+	}
+	var entities saml.EntitiesDescriptor
+	if err := xml.Unmarshal(data, &entities); err != nil {
+		return nil, fmt.Errorf("failed to parse saml metadata: %w", err)
+	}
+	for i := range entities.EntityDescriptors {
+		if len(entities.EntityDescriptors[i].IDPSSODescriptors) > 0 {
+			return &entities.EntityDescriptors[i], nil
+		}
+	}
+	return nil, errors.New("saml metadata has no identity provider")
+}
+
+func fetchSAMLMetadata(ctx context.Context, metadataURL string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, metadataURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s returned %d", metadataURL, resp.StatusCode)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+}
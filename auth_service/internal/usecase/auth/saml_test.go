@@ -0,0 +1,205 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"math/big"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/crewjam/saml"
+)
+
+func TestSAMLParseResponse(t *testing.T) {
+	idp := newTestIdP(t)
+
+	tests := []struct {
+		name      string
+		config    SAMLConfig
+		requestID string // ID запроса, на который SP ждет ответ
+		session   saml.Session
+		mutate    func(*saml.Assertion)
+		response  string // готовый SAMLResponse вместо ответа IdP
+
+		wantErr      bool
+		wantID       string
+		wantEmail    string
+		wantVerified bool
+	}{
+		{
+			name:      "email is not verified by default",
+			session:   saml.Session{NameID: "u-1", CustomAttributes: mailAttribute("admin@example.com")},
+			wantID:    "u-1",
+			wantEmail: "admin@example.com",
+		},
+		{
+			name:         "trusted idp verifies any email",
+			config:       SAMLConfig{TrustEmail: true},
+			session:      saml.Session{NameID: "u-1", CustomAttributes: mailAttribute("admin@example.com")},
+			wantID:       "u-1",
+			wantEmail:    "admin@example.com",
+			wantVerified: true,
+		},
+		{
+			name:         "email in allowed domain",
+			config:       SAMLConfig{EmailDomains: []string{"Corp.Example.com"}},
+			session:      saml.Session{NameID: "u-1", CustomAttributes: mailAttribute("jane@corp.example.com")},
+			wantID:       "u-1",
+			wantEmail:    "jane@corp.example.com",
+			wantVerified: true,
+		},
+		{
+			name:      "email outside allowed domains",
+			config:    SAMLConfig{EmailDomains: []string{"corp.example.com"}},
+			session:   saml.Session{NameID: "u-1", CustomAttributes: mailAttribute("admin@example.com")},
+			wantID:    "u-1",
+			wantEmail: "admin@example.com",
+		},
+		{
+			name:    "no email attribute",
+			config:  SAMLConfig{TrustEmail: true},
+			session: saml.Session{NameID: "u-1"},
+			wantID:  "u-1",
+		},
+		{
+			name:      "id from configured attribute",
+			config:    SAMLConfig{IDAttribute: "employeeNumber"},
+			session:   saml.Session{NameID: "transient", CustomAttributes: []saml.Attribute{{Name: "employeeNumber", Values: []saml.AttributeValue{{Value: "42"}}}}},
+			wantID:    "42",
+			wantEmail: "",
+		},
+		{
+			name:      "wrong InResponseTo",
+			requestID: "id-other",
+			session:   saml.Session{NameID: "u-1", CustomAttributes: mailAttribute("admin@example.com")},
+			wantErr:   true,
+		},
+		{
+			name:    "missing NameID",
+			session: saml.Session{NameID: "u-1", CustomAttributes: mailAttribute("admin@example.com")},
+			mutate:  func(a *saml.Assertion) { a.Subject.NameID = nil },
+			wantErr: true,
+		},
+		{
+			name:    "missing configured id attribute",
+			config:  SAMLConfig{IDAttribute: "employeeNumber"},
+			session: saml.Session{NameID: "u-1", CustomAttributes: mailAttribute("admin@example.com")},
+			wantErr: true,
+		},
+		{
+			name:     "response is not base64",
+			response: "%%%",
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := tt.config
+			cfg.Name = "corp"
+			cfg.BaseURL = "https://auth.example.com"
+			cfg.Key, cfg.Certificate = newTestKeyPair(t, "sp")
+			cfg.IDPMetadata = idp.Metadata()
+			provider, err := NewSAMLProvider(cfg)
+			if err != nil {
+				t.Fatalf("new provider: %v", err)
+			}
+
+			response := tt.response
+			if response == "" {
+				response = idpResponse(t, idp, provider, "id-request", tt.session, tt.mutate)
+			}
+			requestID := tt.requestID
+			if requestID == "" {
+				requestID = "id-request"
+			}
+
+			profile, err := provider.ParseResponse(response, requestID)
+			if tt.wantErr {
+				if !errors.Is(err, ErrSAMLResponse) {
+					t.Fatalf("error %v, want ErrSAMLResponse", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parse response: %v", err)
+			}
+			if profile.ExternalID != tt.wantID || profile.Email != tt.wantEmail || profile.EmailVerified != tt.wantVerified {
+				t.Fatalf("profile %+v, want id %q email %q verified %v", profile, tt.wantID, tt.wantEmail, tt.wantVerified)
+			}
+		})
+	}
+}
+
+func mailAttribute(email string) []saml.Attribute {
+	return []saml.Attribute{{Name: "mail", Values: []saml.AttributeValue{{Value: email}}}}
+}
+
+func newTestIdP(t *testing.T) *saml.IdentityProvider {
+	t.Helper()
+	key, cert := newTestKeyPair(t, "idp")
+	metadataURL, _ := url.Parse("https://idp.example.com/metadata")
+	ssoURL, _ := url.Parse("https://idp.example.com/sso")
+	return &saml.IdentityProvider{
+		Key:         key,
+		Certificate: cert,
+		MetadataURL: *metadataURL,
+		SSOURL:      *ssoURL,
+	}
+}
+
+// idpResponse подписанный ответ idp на запрос requestID к provider, как его пришлет браузер
+func idpResponse(t *testing.T, idp *saml.IdentityProvider, provider *SAMLProvider, requestID string, session saml.Session, mutate func(*saml.Assertion)) string {
+	t.Helper()
+	spMetadata := provider.sp.Metadata()
+	descriptor := &spMetadata.SPSSODescriptors[0]
+
+	req := &saml.IdpAuthnRequest{
+		IDP:                     idp,
+		HTTPRequest:             httptest.NewRequest("POST", idp.SSOURL.String(), nil),
+		Request:                 saml.AuthnRequest{ID: requestID, IssueInstant: saml.TimeNow()},
+		ServiceProviderMetadata: spMetadata,
+		SPSSODescriptor:         descriptor,
+		ACSEndpoint:             &descriptor.AssertionConsumerServices[0],
+		Now:                     saml.TimeNow(),
+	}
+	if err := (saml.DefaultAssertionMaker{}).MakeAssertion(req, &session); err != nil {
+		t.Fatalf("make assertion: %v", err)
+	}
+	if mutate != nil {
+		mutate(req.Assertion)
+	}
+	form, err := req.PostBinding()
+	if err != nil {
+		t.Fatalf("make response: %v", err)
+	}
+	return form.SAMLResponse
+}
+
+func newTestKeyPair(t *testing.T, name string) (*rsa.PrivateKey, *x509.Certificate) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("create certificate: %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("parse certificate: %v", err)
+	}
+	return key, cert
+}
@@ -0,0 +1,124 @@
+package auth
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kprf42/dolgova/auth_service/internal/entity"
+	"github.com/kprf42/dolgova/pkg/logger"
+)
+
+// startSession начинает новую сессию клиента client и выдает ее первую пару токенов.
+// Приостановленному аккаунту токены не выдаются при любом способе входа.
+func (uc *AuthUseCase) startSession(ctx context.Context, user *entity.User, client entity.ClientInfo) (*entity.TokenDetails, error) {
+	if err := user.CheckStatus(time.Now()); err != nil {
+		return nil, err
+	}
+
+	sessionID := uuid.New().String()
+	tokens, err := uc.issueTokens(ctx, user, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	session := &entity.Session{
+		ID:         sessionID,
+		UserID:     user.ID,
+		UserAgent:  client.UserAgent,
+		IP:         client.IP,
+		CreatedAt:  now,
+		LastSeenAt: now,
+		ExpiresAt:  time.Unix(tokens.RtExpires, 0),
+	}
+	if err := uc.sessions.Create(ctx, session); err != nil {
+		return nil, err
+	}
+
+	if uc.devices != nil {
+		uc.devices.check(ctx, user, session)
+	}
+	return tokens, nil
+}
+
+// continueSession выдает новую пару токенов в сессии sessionID при обновлении токенов.
+// Токены, выданные до появления сессий, продолжаются в новой сессии.
+func (uc *AuthUseCase) continueSession(ctx context.Context, user *entity.User, sessionID string, client entity.ClientInfo) (*entity.TokenDetails, error) {
+	if sessionID == "" {
+		return uc.startSession(ctx, user, client)
+	}
+	if err := user.CheckStatus(time.Now()); err != nil {
+		return nil, err
+	}
+
+	session, err := uc.sessions.GetByID(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session == nil || session.RevokedAt != nil {
+		return nil, entity.ErrInvalidToken
+	}
+
+	tokens, err := uc.issueTokens(ctx, user, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.sessions.Touch(ctx, sessionID, client, time.Unix(tokens.RtExpires, 0), time.Now()); err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+// ListSessions возвращает активные сессии пользователя
+func (uc *AuthUseCase) ListSessions(ctx context.Context, userID string) ([]*entity.Session, error) {
+	return uc.sessions.ListActive(ctx, userID, time.Now())
+}
+
+// RevokeSession завершает сессию sessionID пользователя userID:
+// ее refresh токены отзываются, а access токены перестают приниматься сразу.
+func (uc *AuthUseCase) RevokeSession(ctx context.Context, userID, sessionID string) error {
+	session, err := uc.sessions.GetByID(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+	if session == nil || session.UserID != userID || session.RevokedAt != nil {
+		return entity.ErrSessionNotFound
+	}
+	return uc.revokeSession(ctx, session)
+}
+
+// RevokeOtherSessions завершает все сессии пользователя, кроме currentSessionID, и возвращает их количество
+func (uc *AuthUseCase) RevokeOtherSessions(ctx context.Context, userID, currentSessionID string) (int, error) {
+	sessions, err := uc.sessions.ListActive(ctx, userID, time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	revoked := 0
+	for _, session := range sessions {
+		if session.ID == currentSessionID {
+			continue
+		}
+		if err := uc.revokeSession(ctx, session); err != nil {
+			return revoked, err
+		}
+		revoked++
+	}
+
+	uc.log.Info("Revoked user sessions",
+		logger.String("user_id", userID),
+		logger.Int("sessions", revoked))
+	return revoked, nil
+}
+
+func (uc *AuthUseCase) revokeSession(ctx context.Context, session *entity.Session) error {
+	if _, err := uc.sessions.Revoke(ctx, session.ID); err != nil {
+		return err
+	}
+	if err := uc.refreshTokens.RevokeSession(ctx, session.ID); err != nil {
+		return err
+	}
+	// Токены сессии не переживут ее последний refresh токен
+	return uc.revoked.Revoke(ctx, session.ID, session.UserID, session.ExpiresAt)
+}
@@ -0,0 +1,140 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kprf42/dolgova/auth_service/internal/entity"
+	"github.com/kprf42/dolgova/auth_service/internal/usecase/jwt"
+	"github.com/kprf42/dolgova/pkg/audit"
+	"github.com/kprf42/dolgova/pkg/logger"
+)
+
+// StatusChange новый статус аккаунта
+type StatusChange struct {
+	Status string
+	Until  *time.Time // Окончание приостановки; nil - бессрочно. Только для suspended
+	Reason string     // Попадает в журнал аудита
+}
+
+// SetUserStatus меняет статус аккаунта targetID от имени администратора adminID.
+// При приостановке и блокировке все сессии пользователя завершаются, поэтому выданные токены
+// перестают проходить проверку сразу, а не по истечении срока.
+func (uc *AuthUseCase) SetUserStatus(ctx context.Context, adminID, targetID string, change StatusChange) (*entity.User, error) {
+	switch change.Status {
+	case entity.UserStatusActive, entity.UserStatusBanned:
+		if change.Until != nil {
+			return nil, entity.ErrInvalidStatus
+		}
+	case entity.UserStatusSuspended:
+		if change.Until != nil && !change.Until.After(time.Now()) {
+			return nil, entity.ErrInvalidStatus
+		}
+	default:
+		return nil, entity.ErrInvalidStatus
+	}
+
+	// Под имперсонацией статусы не меняются
+	if _, ok := audit.ActingAdminFromContext(ctx); ok {
+		return nil, entity.ErrImpersonationForbidden
+	}
+
+	admin, err := uc.requireAdmin(ctx, adminID)
+	if err != nil {
+		return nil, err
+	}
+	if targetID == admin.ID {
+		return nil, entity.ErrOwnStatus
+	}
+
+	target, err := uc.repo.GetUserByID(ctx, targetID)
+	if err != nil {
+		return nil, err
+	}
+	if target == nil {
+		return nil, entity.ErrUserNotFound
+	}
+
+	if err := uc.repo.SetStatus(ctx, target.ID, change.Status, change.Until, change.Reason); err != nil {
+		return nil, err
+	}
+	target.Status = change.Status
+	target.StatusUntil = change.Until
+	target.StatusReason = change.Reason
+
+	if change.Status != entity.UserStatusActive {
+		if _, err := uc.refreshTokens.RevokeAll(ctx, target.ID); err != nil {
+			return nil, err
+		}
+		if _, err := uc.RevokeOtherSessions(ctx, target.ID, ""); err != nil {
+			return nil, err
+		}
+	}
+
+	details := change.Status
+	if change.Until != nil {
+		details += " until " + change.Until.UTC().Format(time.RFC3339)
+	}
+	if change.Reason != "" {
+		details += ": " + change.Reason
+	}
+	err = uc.audit.Write(ctx, &audit.Entry{
+		ActorID:    admin.ID,
+		Action:     "user.status",
+		TargetType: "user",
+		TargetID:   target.ID,
+		Details:    details,
+	})
+	if err != nil {
+		uc.log.Error("Failed to write audit entry",
+			logger.String("admin_id", adminID),
+			logger.Error(err))
+	}
+
+	uc.log.Info("User status changed",
+		logger.String("admin_id", adminID),
+		logger.String("user_id", target.ID),
+		logger.String("status", change.Status))
+	return target, nil
+}
+
+// requireAdmin возвращает пользователя adminID, если по данным БД он администратор, иначе ErrNotAdmin.
+// Роль в токене могла устареть, поэтому административные действия сверяют ее с БД.
+func (uc *AuthUseCase) requireAdmin(ctx context.Context, adminID string) (*entity.User, error) {
+	admin, err := uc.repo.GetUserByID(ctx, adminID)
+	if err != nil {
+		return nil, err
+	}
+	if admin == nil || admin.Role != "admin" {
+		uc.log.Warn("Admin action by non-admin",
+			logger.String("user_id", adminID))
+		return nil, entity.ErrNotAdmin
+	}
+	return admin, nil
+}
+
+// ValidateAccessToken проверяет access токен и статус его владельца.
+// Токены удаленных пользователей недействительны, приостановленных - отклоняются с AccountSuspendedError.
+// Гостевые токены владельца не имеют; их ограничения (Scope) проверяет вызывающий.
+func (uc *AuthUseCase) ValidateAccessToken(ctx context.Context, token string) (*jwt.Claims, error) {
+	claims, err := uc.jwt.ValidateToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if claims.IsGuest() {
+		return claims, nil
+	}
+
+	user, err := uc.repo.GetUserByID(ctx, claims.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check token owner: %w", err)
+	}
+	if user == nil {
+		return nil, entity.ErrInvalidToken
+	}
+	if err := user.CheckStatus(time.Now()); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
@@ -1,6 +1,11 @@
 package jwt
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -8,59 +13,155 @@ import (
 	"github.com/kprf42/dolgova/auth_service/internal/entity"
 )
 
+// RevocationList черный список отозванных токенов по их ID (jti) и отозванных сессий по sid
+type RevocationList interface {
+	IsRevoked(ctx context.Context, tokenID string) (bool, error)
+}
+
+// DefaultKeyID kid ключа из JWT_SECRET; им же проверяются токены без kid, выпущенные до ротации ключей
+const DefaultKeyID = "default"
+
+// ErrUnknownKey токен подписан ключом, которого нет в конфигурации (выведен из ротации)
+var ErrUnknownKey = errors.New("unknown signing key")
+
+// SigningKey ключ подписи токенов
+type SigningKey struct {
+	ID       string    // kid в заголовке токена
+	Secret   string    // Секрет HMAC
+	ActiveAt time.Time // С этого момента ключ подписывает новые токены; нулевое значение - сразу
+}
+
 type JWTService struct {
-	secret        string
+	keys          []SigningKey // По возрастанию ActiveAt
 	accessExpiry  time.Duration
 	refreshExpiry time.Duration
+	revoked       RevocationList
 }
 
-func NewJWTService(secret string, accessExpiry, refreshExpiry time.Duration) *JWTService {
+// NewJWTService создает сервис токенов. Новые токены подписывает последний уже активный ключ,
+// а проверяются они любым ключом из keys: старый ключ остается в конфигурации, пока не истекут
+// подписанные им токены; keys не может быть пустым. revoked может быть nil, тогда отзыв токенов не проверяется.
+func NewJWTService(keys []SigningKey, accessExpiry, refreshExpiry time.Duration, revoked RevocationList) *JWTService {
+	keys = append([]SigningKey(nil), keys...)
+	sort.SliceStable(keys, func(i, j int) bool { return keys[i].ActiveAt.Before(keys[j].ActiveAt) })
+
 	return &JWTService{
-		secret:        secret,
+		keys:          keys,
 		accessExpiry:  accessExpiry,
 		refreshExpiry: refreshExpiry,
+		revoked:       revoked,
 	}
 }
 
+// SigningKeyID kid ключа, которым подписываются токены в момент now
+func (s *JWTService) SigningKeyID(now time.Time) string {
+	return s.signingKey(now).ID
+}
+
+func (s *JWTService) signingKey(now time.Time) SigningKey {
+	key := s.keys[0]
+	for _, k := range s.keys[1:] {
+		if k.ActiveAt.After(now) {
+			break
+		}
+		key = k
+	}
+	return key
+}
+
+// sign подписывает claims текущим ключом и указывает его kid в заголовке
+func (s *JWTService) sign(claims *Claims) (string, error) {
+	key := s.signingKey(time.Now())
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	token.Header["kid"] = key.ID
+	return token.SignedString([]byte(key.Secret))
+}
+
+// verificationKey ключ проверки подписи по kid из заголовка токена
+func (s *JWTService) verificationKey(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		kid = DefaultKeyID
+	}
+	for _, k := range s.keys {
+		if k.ID == kid {
+			return []byte(k.Secret), nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %q", ErrUnknownKey, kid)
+}
+
 type JWTUseCase interface {
-	GenerateTokens(userID string) (*entity.TokenDetails, error)
-	ValidateToken(tokenString string) (*Claims, error)
+	GenerateTokens(userID, role, sessionID string) (*entity.TokenDetails, error)
+	ValidateToken(ctx context.Context, tokenString string) (*Claims, error)
 }
 
 type Claims struct {
-	UserID string `json:"user_id"`
+	UserID        string   `json:"user_id"`
+	Role          string   `json:"role,omitempty"`            // Роль на момент выпуска; пусто у токенов, выпущенных до появления claim
+	SessionID     string   `json:"sid,omitempty"`             // Сессия, в которой выдан токен; пусто у имперсонации и одноразовых токенов
+	ActingAdminID string   `json:"acting_admin_id,omitempty"` // Заполнен у токенов имперсонации
+	Purpose       string   `json:"purpose,omitempty"`         // Назначение одноразового токена; такие токены не принимаются как access
+	Scope         []string `json:"scope,omitempty"`           // Ограничения гостевого токена; пусто - полный доступ владельца
 	jwt.RegisteredClaims
 }
 
-func (s *JWTService) GenerateTokens(userID string) (*entity.TokenDetails, error) {
+// Назначения одноразовых токенов
+const (
+	PurposeMagicLink     = "magic_link"     // Ссылка для входа без пароля
+	PurposeRevokeSession = "revoke_session" // Ссылка из письма о входе с нового устройства, завершает сессию
+)
+
+// Scope гостевых токенов
+const (
+	ScopeReadOnly = "read-only" // Чтение форума; изменяющие запросы отклоняются
+	ScopeChat     = "chat"      // Подключение к чату без права писать
+)
+
+// RoleGuest роль в гостевых токенах; ID гостя начинается с GuestIDPrefix
+const (
+	RoleGuest     = "guest"
+	GuestIDPrefix = "guest-"
+)
+
+// IsGuest сообщает, выпущен ли токен GenerateGuestToken. У гостя нет аккаунта,
+// поэтому его токен не проверяется по БД пользователей.
+func (c *Claims) IsGuest() bool {
+	return c.Role == RoleGuest && strings.HasPrefix(c.UserID, GuestIDPrefix)
+}
+
+// GenerateTokens выпускает пару токенов пользователя userID в сессии sessionID
+func (s *JWTService) GenerateTokens(userID, role, sessionID string) (*entity.TokenDetails, error) {
 	now := time.Now()
 
 	// Access Token
 	accessClaims := &Claims{
-		UserID: userID,
+		UserID:    userID,
+		Role:      role,
+		SessionID: sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(now.Add(s.accessExpiry)),
 			ID:        uuid.New().String(),
 		},
 	}
 
-	accessToken := jwt.NewWithClaims(jwt.SigningMethodHS256, accessClaims)
-	accessTokenString, err := accessToken.SignedString([]byte(s.secret))
+	accessTokenString, err := s.sign(accessClaims)
 	if err != nil {
 		return nil, err
 	}
 
 	// Refresh Token
 	refreshClaims := &Claims{
-		UserID: userID,
+		UserID:    userID,
+		Role:      role,
+		SessionID: sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(now.Add(s.refreshExpiry)),
 			ID:        uuid.New().String(),
 		},
 	}
 
-	refreshToken := jwt.NewWithClaims(jwt.SigningMethodHS256, refreshClaims)
-	refreshTokenString, err := refreshToken.SignedString([]byte(s.secret))
+	refreshTokenString, err := s.sign(refreshClaims)
 	if err != nil {
 		return nil, err
 	}
@@ -75,18 +176,142 @@ func (s *JWTService) GenerateTokens(userID string) (*entity.TokenDetails, error)
 	}, nil
 }
 
-func (s *JWTService) ValidateToken(tokenString string) (*Claims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		return []byte(s.secret), nil
-	})
+// GenerateImpersonationToken выпускает access токен пользователя userID с его ролью role для администратора adminID.
+// Refresh токен не выдается: продлить имперсонацию можно только новым запросом.
+func (s *JWTService) GenerateImpersonationToken(userID, role, adminID string, ttl time.Duration) (*entity.TokenDetails, error) {
+	claims := &Claims{
+		UserID:        userID,
+		Role:          role,
+		ActingAdminID: adminID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
+			ID:        uuid.New().String(),
+		},
+	}
 
+	token, err := s.sign(claims)
 	if err != nil {
 		return nil, err
 	}
 
-	if claims, ok := token.Claims.(*Claims); ok && token.Valid {
-		return claims, nil
+	return &entity.TokenDetails{
+		AccessToken: token,
+		AccessUuid:  claims.ID,
+		AtExpires:   claims.ExpiresAt.Unix(),
+	}, nil
+}
+
+// GenerateGuestToken выпускает анонимному посетителю access токен со scope read-only и chat.
+// Каждый гость получает новый ID; refresh токен не выдается, по истечении ttl нужен новый токен.
+func (s *JWTService) GenerateGuestToken(ttl time.Duration) (*entity.TokenDetails, error) {
+	claims := &Claims{
+		UserID: GuestIDPrefix + uuid.New().String(),
+		Role:   RoleGuest,
+		Scope:  []string{ScopeReadOnly, ScopeChat},
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
+			ID:        uuid.New().String(),
+		},
+	}
+
+	token, err := s.sign(claims)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.TokenDetails{
+		AccessToken: token,
+		AccessUuid:  claims.ID,
+		AtExpires:   claims.ExpiresAt.Unix(),
+	}, nil
+}
+
+// GeneratePurposeToken выпускает одноразовый токен назначения purpose для пользователя userID
+func (s *JWTService) GeneratePurposeToken(userID, purpose string, ttl time.Duration) (string, *Claims, error) {
+	claims := &Claims{
+		UserID:  userID,
+		Purpose: purpose,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
+			ID:        uuid.New().String(),
+		},
+	}
+
+	token, err := s.sign(claims)
+	if err != nil {
+		return "", nil, err
+	}
+	return token, claims, nil
+}
+
+// GenerateSessionPurposeToken выпускает токен назначения purpose, относящийся к сессии sessionID
+// пользователя userID. После отзыва сессии такой токен не проходит проверку.
+func (s *JWTService) GenerateSessionPurposeToken(userID, sessionID, purpose string, ttl time.Duration) (string, error) {
+	claims := &Claims{
+		UserID:    userID,
+		SessionID: sessionID,
+		Purpose:   purpose,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
+			ID:        uuid.New().String(),
+		},
+	}
+	return s.sign(claims)
+}
+
+// ValidateToken проверяет подпись и срок токена, а также что он не отозван
+func (s *JWTService) ValidateToken(ctx context.Context, tokenString string) (*Claims, error) {
+	claims, err := s.parse(ctx, tokenString)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Purpose != "" {
+		return nil, entity.ErrInvalidToken
+	}
+	return claims, nil
+}
+
+// ValidatePurposeToken проверяет токен, выпущенный GeneratePurposeToken с назначением purpose
+func (s *JWTService) ValidatePurposeToken(ctx context.Context, tokenString, purpose string) (*Claims, error) {
+	claims, err := s.parse(ctx, tokenString)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Purpose != purpose {
+		return nil, entity.ErrInvalidToken
+	}
+	return claims, nil
+}
+
+// parse проверяет подпись и срок токена, а также что он не отозван
+func (s *JWTService) parse(ctx context.Context, tokenString string) (*Claims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, s.verificationKey,
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+
+	if err != nil {
+		return nil, err
+	}
+
+	claims, ok := token.Claims.(*Claims)
+	if !ok || !token.Valid {
+		return nil, jwt.ErrSignatureInvalid
+	}
+
+	if s.revoked != nil {
+		// Отзыв сессии вносит в список ее id, что отзывает сразу все токены сессии
+		for _, id := range []string{claims.ID, claims.SessionID} {
+			if id == "" {
+				continue
+			}
+			revoked, err := s.revoked.IsRevoked(ctx, id)
+			if err != nil {
+				return nil, err
+			}
+			if revoked {
+				return nil, entity.ErrTokenRevoked
+			}
+		}
 	}
 
-	return nil, jwt.ErrSignatureInvalid
+	return claims, nil
 }
@@ -1,6 +1,3 @@
--- Удаление таблицы пользователей
-DROP TABLE IF EXISTS users;
-
 -- Триггер для удаления старых сообщений (> 30 дней)
 CREATE TRIGGER clean_old_chat
 AFTER INSERT ON chat_messages
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_audit_log_acting_admin;
+DROP INDEX IF EXISTS idx_audit_log_actor;
+DROP TABLE IF EXISTS audit_log;
@@ -0,0 +1,15 @@
+-- Журнал действий, важных для безопасности (имперсонация и т.п.). Пишут auth сервис и форум.
+CREATE TABLE IF NOT EXISTS audit_log (
+    id              INTEGER PRIMARY KEY AUTOINCREMENT,
+    actor_id        TEXT NOT NULL,
+    acting_admin_id TEXT NOT NULL DEFAULT '', -- Пусто, если действие выполнено самим пользователем
+    action          TEXT NOT NULL,
+    target_type     TEXT NOT NULL DEFAULT '',
+    target_id       TEXT NOT NULL DEFAULT '',
+    details         TEXT NOT NULL DEFAULT '',
+    ip              TEXT NOT NULL DEFAULT '',
+    created_at      TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_actor ON audit_log(actor_id);
+CREATE INDEX IF NOT EXISTS idx_audit_log_acting_admin ON audit_log(acting_admin_id);
@@ -0,0 +1,10 @@
+-- Отозванные до истечения срока токены (logout). Ключ - jti токена (AccessUuid/RefreshUuid).
+-- Строки с истекшим expires_at больше не нужны: такой токен и так не пройдет проверку.
+CREATE TABLE IF NOT EXISTS revoked_tokens (
+    token_id   TEXT PRIMARY KEY,
+    user_id    TEXT NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_revoked_tokens_expires_at ON revoked_tokens(expires_at);
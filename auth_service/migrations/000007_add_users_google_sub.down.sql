@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_users_google_sub;
+ALTER TABLE users DROP COLUMN google_sub;
@@ -0,0 +1,4 @@
+-- Привязка аккаунта к пользователю Google (claim sub). NULL - вход через Google не настроен.
+ALTER TABLE users ADD COLUMN google_sub TEXT;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_google_sub ON users(google_sub) WHERE google_sub IS NOT NULL;
@@ -0,0 +1,10 @@
+ALTER TABLE users ADD COLUMN google_sub TEXT;
+
+UPDATE users SET google_sub = (
+    SELECT external_id FROM linked_accounts la WHERE la.user_id = users.id AND la.provider = 'google'
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_google_sub ON users(google_sub) WHERE google_sub IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_linked_accounts_user;
+DROP TABLE IF EXISTS linked_accounts;
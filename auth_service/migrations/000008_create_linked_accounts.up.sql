@@ -0,0 +1,17 @@
+-- Привязки внешних аккаунтов (Google, GitHub, ...) к пользователям. Заменяет users.google_sub.
+CREATE TABLE IF NOT EXISTS linked_accounts (
+    provider    TEXT NOT NULL,
+    external_id TEXT NOT NULL,
+    user_id     TEXT NOT NULL,
+    created_at  TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (provider, external_id),
+    FOREIGN KEY (user_id) REFERENCES users(id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_linked_accounts_user ON linked_accounts(user_id);
+
+INSERT INTO linked_accounts (provider, external_id, user_id)
+SELECT 'google', google_sub, id FROM users WHERE google_sub IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_users_google_sub;
+ALTER TABLE users DROP COLUMN google_sub;
@@ -0,0 +1,10 @@
+-- Подряд идущие неудачные входы по пользователю (kind = 'user') и по адресу (kind = 'ip').
+-- После порога вход блокируется до locked_until, счетчик начинается заново.
+CREATE TABLE IF NOT EXISTS login_failures (
+    kind         TEXT NOT NULL,
+    subject      TEXT NOT NULL,
+    failures     INTEGER NOT NULL DEFAULT 0,
+    locked_until TIMESTAMP,
+    updated_at   TIMESTAMP NOT NULL,
+    PRIMARY KEY (kind, subject)
+);
@@ -0,0 +1,4 @@
+ALTER TABLE refresh_tokens DROP COLUMN session_id;
+DROP INDEX IF EXISTS idx_sessions_expires_at;
+DROP INDEX IF EXISTS idx_sessions_user;
+DROP TABLE IF EXISTS sessions;
@@ -0,0 +1,18 @@
+-- Сессии (устройства) пользователя. Сессия начинается при входе и продолжается
+-- при обновлении токенов: все refresh токены цепочки ссылаются на нее через session_id.
+CREATE TABLE IF NOT EXISTS sessions (
+    id           TEXT PRIMARY KEY,
+    user_id      TEXT NOT NULL,
+    user_agent   TEXT NOT NULL DEFAULT '',
+    ip           TEXT NOT NULL DEFAULT '',
+    created_at   TIMESTAMP NOT NULL,
+    last_seen_at TIMESTAMP NOT NULL,
+    expires_at   TIMESTAMP NOT NULL,
+    revoked_at   TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_sessions_user ON sessions(user_id);
+CREATE INDEX IF NOT EXISTS idx_sessions_expires_at ON sessions(expires_at);
+
+-- У токенов, выданных до появления сессий, session_id пустой
+ALTER TABLE refresh_tokens ADD COLUMN session_id TEXT;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_auth_events_user;
+DROP TABLE IF EXISTS auth_events;
@@ -0,0 +1,14 @@
+-- Выгрузки персональных данных (GET /auth/me/export). Архив лежит в каталоге выгрузок
+-- до expires_at, затем удаляется вместе с записью.
+CREATE TABLE IF NOT EXISTS data_exports (
+    id           TEXT PRIMARY KEY,
+    user_id      TEXT NOT NULL,
+    status       TEXT NOT NULL,               -- pending, running, ready, failed
+    error        TEXT NOT NULL DEFAULT '',
+    size         INTEGER NOT NULL DEFAULT 0,  -- Размер архива в байтах
+    created_at   TIMESTAMP NOT NULL,
+    completed_at TIMESTAMP,
+    expires_at   TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_data_exports_user ON data_exports(user_id, created_at);
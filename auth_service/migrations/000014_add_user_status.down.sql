@@ -0,0 +1,3 @@
+ALTER TABLE users DROP COLUMN status_reason;
+ALTER TABLE users DROP COLUMN status_until;
+ALTER TABLE users DROP COLUMN status;
@@ -0,0 +1,4 @@
+-- Статус аккаунта: active, suspended (до status_until или бессрочно) или banned
+ALTER TABLE users ADD COLUMN status TEXT NOT NULL DEFAULT 'active';
+ALTER TABLE users ADD COLUMN status_until TIMESTAMP;
+ALTER TABLE users ADD COLUMN status_reason TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,15 @@
+-- Ключи внутренних сервисов. Хранится только SHA-256 секретной части ключа.
+CREATE TABLE IF NOT EXISTS api_keys (
+    id           TEXT PRIMARY KEY,            -- Открытая часть ключа dk_<id>_<secret>
+    name         TEXT NOT NULL,               -- Сервис-владелец ключа, например forum_service
+    secret_hash  TEXT NOT NULL,
+    scopes       TEXT NOT NULL DEFAULT '',    -- Разрешения через запятую
+    created_by   TEXT NOT NULL,
+    created_at   TIMESTAMP NOT NULL,
+    expires_at   TIMESTAMP,                   -- NULL - бессрочно; при ротации старому ключу ставится срок
+    revoked_at   TIMESTAMP,
+    last_used_at TIMESTAMP,
+    rotated_from TEXT NOT NULL DEFAULT ''     -- Ключ, на смену которому выпущен этот
+);
+
+CREATE INDEX IF NOT EXISTS idx_api_keys_name ON api_keys(name);
@@ -0,0 +1,11 @@
+-- Приглашения для регистрации в режиме только по приглашениям. Хранится только SHA-256 кода.
+CREATE TABLE IF NOT EXISTS invites (
+    id         TEXT PRIMARY KEY,
+    code_hash  TEXT NOT NULL UNIQUE,
+    max_uses   INTEGER NOT NULL,             -- Сколько регистраций допускает код
+    uses       INTEGER NOT NULL DEFAULT 0,   -- Сколько уже использовано
+    created_by TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    expires_at TIMESTAMP,                    -- NULL - бессрочно
+    revoked_at TIMESTAMP
+);
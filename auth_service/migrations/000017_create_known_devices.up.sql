@@ -0,0 +1,11 @@
+-- Устройства, с которых пользователь уже входил. Устройство определяется отпечатком
+-- (SHA-256 от User-Agent и IP клиента); вход с нового отпечатка сопровождается письмом.
+CREATE TABLE IF NOT EXISTS known_devices (
+    user_id       TEXT NOT NULL,
+    fingerprint   TEXT NOT NULL,
+    user_agent    TEXT NOT NULL DEFAULT '',
+    ip            TEXT NOT NULL DEFAULT '',
+    first_seen_at TIMESTAMP NOT NULL,
+    last_seen_at  TIMESTAMP NOT NULL,
+    PRIMARY KEY (user_id, fingerprint)
+);
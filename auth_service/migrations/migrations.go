@@ -0,0 +1,135 @@
+// Package migrations содержит SQL-миграции схемы auth сервиса, встроенные в бинарник:
+// полную схему для SQLite и схему хранилища пользователей для PostgreSQL (каталог postgres).
+// В PostgreSQL переносятся только users и linked_accounts, остальные таблицы всегда в SQLite.
+package migrations
+
+import (
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database"
+	"github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/database/sqlite3"
+	"github.com/golang-migrate/migrate/v4/source"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+)
+
+// FS встроенная файловая система с файлами миграций SQLite
+//
+//go:embed *.sql
+var FS embed.FS
+
+// PostgresFS миграции хранилища пользователей в PostgreSQL
+//
+//go:embed postgres/*.sql
+var PostgresFS embed.FS
+
+// Table таблица версий миграций auth сервиса
+const Table = "schema_migrations"
+
+// Status состояние схемы БД
+type Status struct {
+	Version uint `json:"version"` // Текущая примененная версия
+	Latest  uint `json:"latest"`  // Последняя доступная версия
+	Pending int  `json:"pending"` // Количество непримененных миграций
+	Dirty   bool `json:"dirty"`   // Миграция была прервана на середине
+}
+
+// Migrator применяет миграции и сообщает состояние схемы
+type Migrator struct {
+	m        *migrate.Migrate
+	versions []uint
+}
+
+// NewMigrator создает мигратор SQLite поверх открытого соединения с БД
+func NewMigrator(db *sql.DB) (*Migrator, error) {
+	driver, err := sqlite3.WithInstance(db, &sqlite3.Config{MigrationsTable: Table})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create migration driver: %w", err)
+	}
+	return newMigrator(FS, ".", "sqlite3", driver)
+}
+
+// NewPostgresMigrator создает мигратор хранилища пользователей в PostgreSQL
+func NewPostgresMigrator(db *sql.DB) (*Migrator, error) {
+	driver, err := postgres.WithInstance(db, &postgres.Config{MigrationsTable: Table})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create migration driver: %w", err)
+	}
+	return newMigrator(PostgresFS, "postgres", "postgres", driver)
+}
+
+func newMigrator(fsys fs.FS, dir, driverName string, driver database.Driver) (*Migrator, error) {
+	// Источник миграций встроен в бинарник, поэтому не зависит от рабочей директории
+	src, err := iofs.New(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create migration source: %w", err)
+	}
+
+	versions, err := listVersions(src)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list migrations: %w", err)
+	}
+
+	m, err := migrate.NewWithInstance("iofs", src, driverName, driver)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create migration instance: %w", err)
+	}
+
+	return &Migrator{m: m, versions: versions}, nil
+}
+
+// Up применяет все непримененные миграции
+func (m *Migrator) Up() error {
+	if err := m.m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return err
+	}
+	return nil
+}
+
+// Status возвращает текущую версию схемы, количество ожидающих миграций и флаг dirty
+func (m *Migrator) Status() (*Status, error) {
+	version, dirty, err := m.m.Version()
+	if err != nil && !errors.Is(err, migrate.ErrNilVersion) {
+		return nil, err
+	}
+
+	status := &Status{Version: version, Dirty: dirty}
+	for _, v := range m.versions {
+		if v > version {
+			status.Pending++
+		}
+		if v > status.Latest {
+			status.Latest = v
+		}
+	}
+
+	return status, nil
+}
+
+// listVersions возвращает все версии миграций из источника по возрастанию
+func listVersions(src source.Driver) ([]uint, error) {
+	version, err := src.First()
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	versions := []uint{version}
+	for {
+		version, err = src.Next(version)
+		if errors.Is(err, fs.ErrNotExist) {
+			return versions, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, version)
+	}
+}
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS linked_accounts;
+DROP TABLE IF EXISTS users;
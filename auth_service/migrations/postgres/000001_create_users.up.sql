@@ -0,0 +1,27 @@
+-- Пользователи и привязанные внешние аккаунты. Схема соответствует таблицам users и
+-- linked_accounts в SQLite после миграции 000014; остальные данные сервиса остаются в SQLite.
+CREATE TABLE IF NOT EXISTS users (
+    id            TEXT PRIMARY KEY,
+    username      TEXT NOT NULL UNIQUE,
+    email         TEXT NOT NULL UNIQUE,
+    password      TEXT NOT NULL,
+    role          TEXT NOT NULL DEFAULT 'user',
+    display_name  TEXT NOT NULL DEFAULT '',
+    bio           TEXT NOT NULL DEFAULT '',
+    avatar_url    TEXT NOT NULL DEFAULT '',
+    status        TEXT NOT NULL DEFAULT 'active',
+    status_until  TIMESTAMPTZ,
+    status_reason TEXT NOT NULL DEFAULT '',
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at    TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS linked_accounts (
+    provider    TEXT NOT NULL,
+    external_id TEXT NOT NULL,
+    user_id     TEXT NOT NULL REFERENCES users(id),
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (provider, external_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_linked_accounts_user ON linked_accounts(user_id);
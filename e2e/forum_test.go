@@ -0,0 +1,387 @@
+//go:build e2e
+
+package e2e
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+)
+
+type author struct {
+	ID       string `json:"id"`
+	Username string `json:"username"`
+	Karma    int    `json:"karma"`
+}
+
+type post struct {
+	ID       string  `json:"id"`
+	Slug     string  `json:"slug"`
+	Title    string  `json:"title"`
+	AuthorID string  `json:"author_id"`
+	Score    int     `json:"score"`
+	Author   *author `json:"author"`
+}
+
+type comment struct {
+	ID         string  `json:"id"`
+	Content    string  `json:"content"`
+	PostID     string  `json:"post_id"`
+	AuthorID   string  `json:"author_id"`
+	Author     *author `json:"author"`
+	IsAccepted bool    `json:"is_accepted"`
+}
+
+type dataExport struct {
+	ID          string `json:"id"`
+	Status      string `json:"status"`
+	Error       string `json:"error"`
+	DownloadURL string `json:"download_url"`
+}
+
+type chatMessage struct {
+	ID     string `json:"id"`
+	UserID string `json:"user_id"`
+	Text   string `json:"text"`
+}
+
+func TestForumScenario(t *testing.T) {
+	env := Start(t)
+
+	aliceID := env.Register(t, "alice", "alice@example.com", "alice-password")
+	bobID := env.Register(t, "bob", "bob@example.com", "bob-password")
+	alice := env.Login(t, "alice@example.com", "alice-password")
+	bob := env.Login(t, "bob@example.com", "bob-password")
+
+	t.Run("create post", func(t *testing.T) {
+		body := map[string]string{"title": "First post", "content": "Content of the first post", "category_id": "1"}
+		if code := env.Do(t, http.MethodPost, env.ForumURL+"/api/v1/posts", "", body, nil); code != http.StatusUnauthorized {
+			t.Fatalf("create post without token: status %d, want %d", code, http.StatusUnauthorized)
+		}
+
+		var created post
+		if code := env.Do(t, http.MethodPost, env.ForumURL+"/api/v1/posts", alice, body, &created); code != http.StatusOK {
+			t.Fatalf("create post: status %d", code)
+		}
+		if created.AuthorID != aliceID {
+			t.Fatalf("post author %q, want %q", created.AuthorID, aliceID)
+		}
+
+		var got post
+		if code := env.Do(t, http.MethodGet, env.ForumURL+"/api/v1/posts/"+created.ID, "", nil, &got); code != http.StatusOK {
+			t.Fatalf("get post: status %d", code)
+		}
+		if got.Title != "First post" {
+			t.Fatalf("post title %q, want %q", got.Title, "First post")
+		}
+		if got.Author == nil || got.Author.Username != "alice" {
+			t.Fatalf("post author info %+v, want username %q", got.Author, "alice")
+		}
+
+		var bySlug post
+		if code := env.Do(t, http.MethodGet, env.ForumURL+"/api/v1/posts/slug/"+created.Slug, "", nil, &bySlug); code != http.StatusOK {
+			t.Fatalf("get post by slug %q: status %d", created.Slug, code)
+		}
+		if created.Slug != "first-post" || bySlug.ID != created.ID {
+			t.Fatalf("post by slug %q: id %q, want slug %q and id %q", created.Slug, bySlug.ID, "first-post", created.ID)
+		}
+
+		var categories []struct {
+			ID string `json:"id"`
+		}
+		if code := env.Do(t, http.MethodGet, env.ForumURL+"/api/v1/categories", "", nil, &categories); code != http.StatusOK {
+			t.Fatalf("list categories: status %d", code)
+		}
+		if len(categories) == 0 || categories[0].ID != "1" {
+			t.Fatalf("categories %+v, want default category %q first", categories, "1")
+		}
+		unknown := map[string]string{"title": "Lost post", "content": "No such category", "category_id": "404"}
+		if code := env.Do(t, http.MethodPost, env.ForumURL+"/api/v1/posts", alice, unknown, nil); code != http.StatusBadRequest {
+			t.Fatalf("create post in unknown category: status %d, want %d", code, http.StatusBadRequest)
+		}
+
+		t.Run("comment", func(t *testing.T) {
+			url := fmt.Sprintf("%s/api/v1/posts/%s/comments", env.ForumURL, created.ID)
+
+			var c comment
+			if code := env.Do(t, http.MethodPost, url, bob, map[string]string{"content": "Nice post"}, &c); code != http.StatusCreated {
+				t.Fatalf("create comment: status %d", code)
+			}
+			if c.AuthorID != bobID || c.PostID != created.ID {
+				t.Fatalf("comment author %q post %q, want %q %q", c.AuthorID, c.PostID, bobID, created.ID)
+			}
+
+			var list struct {
+				Comments []comment `json:"comments"`
+				Total    int       `json:"total"`
+			}
+			if code := env.Do(t, http.MethodGet, url, "", nil, &list); code != http.StatusOK {
+				t.Fatalf("list comments: status %d", code)
+			}
+			if list.Total != 1 || len(list.Comments) != 1 || list.Comments[0].ID != c.ID {
+				t.Fatalf("comments %+v, want one with id %q", list.Comments, c.ID)
+			}
+			if a := list.Comments[0].Author; a == nil || a.ID != bobID || a.Username != "bob" {
+				t.Fatalf("comment author info %+v, want bob", a)
+			}
+
+			// Принятый ответ отмечает только автор поста
+			accept := fmt.Sprintf("%s/%s/accept", url, c.ID)
+			if code := env.Do(t, http.MethodPost, accept, bob, nil, nil); code != http.StatusForbidden {
+				t.Fatalf("accept answer by comment author: status %d, want %d", code, http.StatusForbidden)
+			}
+			if code := env.Do(t, http.MethodPost, accept, alice, nil, nil); code != http.StatusOK {
+				t.Fatalf("accept answer: status %d", code)
+			}
+			if code := env.Do(t, http.MethodGet, url, "", nil, &list); code != http.StatusOK {
+				t.Fatalf("list comments: status %d", code)
+			}
+			if len(list.Comments) != 1 || !list.Comments[0].IsAccepted {
+				t.Fatalf("comments %+v, want accepted answer %q", list.Comments, c.ID)
+			}
+		})
+
+		t.Run("vote", func(t *testing.T) {
+			url := fmt.Sprintf("%s/api/v1/posts/%s/vote", env.ForumURL, created.ID)
+
+			// Повторный голос не меняет счет, голос против заменяет голос за
+			for _, step := range []struct{ value, score int }{{1, 1}, {1, 1}, {-1, -1}} {
+				var vote struct {
+					Score int `json:"score"`
+				}
+				if code := env.Do(t, http.MethodPost, url, bob, map[string]int{"value": step.value}, &vote); code != http.StatusOK {
+					t.Fatalf("vote %d: status %d", step.value, code)
+				}
+				if vote.Score != step.score {
+					t.Fatalf("vote %d: score %d, want %d", step.value, vote.Score, step.score)
+				}
+			}
+
+			var got post
+			if code := env.Do(t, http.MethodGet, env.ForumURL+"/api/v1/posts/"+created.ID, "", nil, &got); code != http.StatusOK {
+				t.Fatalf("get post: status %d", code)
+			}
+			if got.Score != -1 {
+				t.Fatalf("post score %d, want -1", got.Score)
+			}
+		})
+
+		t.Run("report", func(t *testing.T) {
+			url := fmt.Sprintf("%s/api/v1/posts/%s/report", env.ForumURL, created.ID)
+			report := map[string]string{"reason": "spam"}
+
+			if code := env.Do(t, http.MethodPost, url, bob, report, nil); code != http.StatusCreated {
+				t.Fatalf("report post: status %d", code)
+			}
+			// Повторная жалоба того же пользователя отклоняется
+			if code := env.Do(t, http.MethodPost, url, bob, report, nil); code != http.StatusConflict {
+				t.Fatalf("duplicate report: status %d, want %d", code, http.StatusConflict)
+			}
+		})
+
+		t.Run("author posts", func(t *testing.T) {
+			var list struct {
+				Posts []post `json:"posts"`
+				Total int    `json:"total"`
+			}
+			if code := env.Do(t, http.MethodGet, env.ForumURL+"/api/v1/users/"+aliceID+"/posts", "", nil, &list); code != http.StatusOK {
+				t.Fatalf("list author posts: status %d", code)
+			}
+			if list.Total != 1 || len(list.Posts) != 1 || list.Posts[0].ID != created.ID {
+				t.Fatalf("author posts %+v, want one with id %q", list.Posts, created.ID)
+			}
+		})
+
+		t.Run("user stats", func(t *testing.T) {
+			// Бобу начислена карма за принятый ответ, Алисе снята за голос против ее поста
+			for _, want := range []struct {
+				userID                           string
+				karma, posts, comments, accepted int
+			}{
+				{bobID, 15, 0, 1, 1},
+				{aliceID, -2, 1, 0, 0},
+			} {
+				var stats struct {
+					Karma           int `json:"karma"`
+					Posts           int `json:"posts"`
+					Comments        int `json:"comments"`
+					AcceptedAnswers int `json:"accepted_answers"`
+				}
+				if code := env.Do(t, http.MethodGet, env.ForumURL+"/api/v1/users/"+want.userID+"/stats", "", nil, &stats); code != http.StatusOK {
+					t.Fatalf("user stats: status %d", code)
+				}
+				if stats.Karma != want.karma || stats.Posts != want.posts || stats.Comments != want.comments || stats.AcceptedAnswers != want.accepted {
+					t.Fatalf("stats of %s %+v, want %+v", want.userID, stats, want)
+				}
+			}
+
+			var list struct {
+				Comments []comment `json:"comments"`
+			}
+			url := fmt.Sprintf("%s/api/v1/posts/%s/comments", env.ForumURL, created.ID)
+			if code := env.Do(t, http.MethodGet, url, "", nil, &list); code != http.StatusOK {
+				t.Fatalf("list comments: status %d", code)
+			}
+			if len(list.Comments) != 1 || list.Comments[0].Author == nil || list.Comments[0].Author.Karma != 15 {
+				t.Fatalf("comments %+v, want author with karma 15", list.Comments)
+			}
+		})
+
+		t.Run("trending", func(t *testing.T) {
+			var trending struct {
+				Posts []post `json:"posts"`
+				Hours int    `json:"hours"`
+			}
+			if code := env.Do(t, http.MethodGet, env.ForumURL+"/api/v1/posts/trending", "", nil, &trending); code != http.StatusOK {
+				t.Fatalf("trending posts: status %d", code)
+			}
+			// Пост просмотрен и прокомментирован в предыдущих шагах
+			if len(trending.Posts) == 0 || trending.Posts[0].ID != created.ID || trending.Hours != 24 {
+				t.Fatalf("trending %+v, want post %q first over 24 hours", trending, created.ID)
+			}
+		})
+	})
+
+	t.Run("chat", func(t *testing.T) {
+		aliceConn := env.DialChat(t, alice)
+		bobConn := env.DialChat(t, bob)
+
+		if err := aliceConn.WriteJSON(map[string]string{"text": "hello from alice"}); err != nil {
+			t.Fatalf("send chat message: %v", err)
+		}
+
+		// Новый клиент сначала получает историю, поэтому сообщение ищется среди всех полученных
+		bobConn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		for {
+			var msg chatMessage
+			if err := bobConn.ReadJSON(&msg); err != nil {
+				t.Fatalf("receive chat message: %v", err)
+			}
+			if msg.Text == "hello from alice" {
+				if msg.UserID != aliceID {
+					t.Fatalf("chat message user %q, want %q", msg.UserID, aliceID)
+				}
+				break
+			}
+		}
+
+		var history []chatMessage
+		if code := env.Do(t, http.MethodGet, env.ForumURL+"/api/v1/chat/messages", "", nil, &history); code != http.StatusOK {
+			t.Fatalf("chat history: status %d", code)
+		}
+		if len(history) == 0 || history[0].Text != "hello from alice" {
+			t.Fatalf("chat history %+v, want the sent message first", history)
+		}
+	})
+	t.Run("guest token", func(t *testing.T) {
+		var guest struct {
+			AccessToken string   `json:"access_token"`
+			Scope       []string `json:"scope"`
+		}
+		if code := env.Do(t, http.MethodPost, env.AuthURL+"/auth/guest", "", nil, &guest); code != http.StatusOK {
+			t.Fatalf("guest token: status %d", code)
+		}
+
+		if code := env.Do(t, http.MethodGet, env.ForumURL+"/api/v1/posts", guest.AccessToken, nil, nil); code != http.StatusOK {
+			t.Fatalf("list posts as guest: status %d", code)
+		}
+		body := map[string]string{"title": "Guest post", "content": "Should be rejected", "category_id": "1"}
+		if code := env.Do(t, http.MethodPost, env.ForumURL+"/api/v1/posts", guest.AccessToken, body, nil); code != http.StatusForbidden {
+			t.Fatalf("create post as guest: status %d, want %d", code, http.StatusForbidden)
+		}
+		if code := env.Do(t, http.MethodGet, env.AuthURL+"/auth/me", guest.AccessToken, nil, nil); code != http.StatusForbidden {
+			t.Fatalf("auth profile as guest: status %d, want %d", code, http.StatusForbidden)
+		}
+
+		env.DialChat(t, guest.AccessToken)
+	})
+	t.Run("export data", func(t *testing.T) {
+		var export dataExport
+		code := env.Do(t, http.MethodGet, env.AuthURL+"/auth/me/export", alice, nil, &export)
+		if code != http.StatusAccepted && code != http.StatusOK {
+			t.Fatalf("request export: status %d", code)
+		}
+
+		deadline := time.Now().Add(10 * time.Second)
+		for export.Status != "ready" {
+			if export.Status == "failed" {
+				t.Fatalf("export failed: %s", export.Error)
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("export not ready in time, status %q", export.Status)
+			}
+			time.Sleep(100 * time.Millisecond)
+			if code := env.Do(t, http.MethodGet, env.AuthURL+"/auth/me/export/"+export.ID, alice, nil, &export); code >= 300 {
+				t.Fatalf("export status: status %d", code)
+			}
+		}
+
+		if code := env.Do(t, http.MethodGet, env.AuthURL+export.DownloadURL, bob, nil, nil); code != http.StatusNotFound {
+			t.Fatalf("download other user's export: status %d, want %d", code, http.StatusNotFound)
+		}
+
+		req, _ := http.NewRequest(http.MethodGet, env.AuthURL+export.DownloadURL, nil)
+		req.Header.Set("Authorization", "Bearer "+alice)
+		resp, err := env.http.Do(req)
+		if err != nil {
+			t.Fatalf("download export: %v", err)
+		}
+		data, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil || resp.StatusCode != http.StatusOK {
+			t.Fatalf("download export: status %d, err %v", resp.StatusCode, err)
+		}
+
+		archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			t.Fatalf("open export archive: %v", err)
+		}
+		f, err := archive.Open("forum/posts.json")
+		if err != nil {
+			t.Fatalf("export archive: %v", err)
+		}
+		defer f.Close()
+		var posts []post
+		if err := json.NewDecoder(f).Decode(&posts); err != nil {
+			t.Fatalf("decode exported posts: %v", err)
+		}
+		if len(posts) == 0 || posts[0].Title != "First post" {
+			t.Fatalf("exported posts %+v, want alice's post", posts)
+		}
+	})
+	t.Run("delete account", func(t *testing.T) {
+		body := map[string]string{"title": "Bob's post", "content": "Written before deletion", "category_id": "1"}
+		var created post
+		if code := env.Do(t, http.MethodPost, env.ForumURL+"/api/v1/posts", bob, body, &created); code != http.StatusOK {
+			t.Fatalf("create post: status %d", code)
+		}
+
+		wrong := map[string]string{"password": "not-bob-password"}
+		if code := env.Do(t, http.MethodDelete, env.AuthURL+"/auth/me", bob, wrong, nil); code != http.StatusForbidden {
+			t.Fatalf("delete account with wrong password: status %d, want %d", code, http.StatusForbidden)
+		}
+
+		confirm := map[string]string{"password": "bob-password"}
+		if code := env.Do(t, http.MethodDelete, env.AuthURL+"/auth/me", bob, confirm, nil); code != http.StatusNoContent {
+			t.Fatalf("delete account: status %d", code)
+		}
+
+		var got post
+		if code := env.Do(t, http.MethodGet, env.ForumURL+"/api/v1/posts/"+created.ID, "", nil, &got); code != http.StatusOK {
+			t.Fatalf("get post: status %d", code)
+		}
+		if got.AuthorID == bobID {
+			t.Fatalf("post author %q was not anonymized", got.AuthorID)
+		}
+
+		login := map[string]string{"email": "bob@example.com", "password": "bob-password"}
+		if code := env.Do(t, http.MethodPost, env.AuthURL+"/auth/login", "", login, nil); code != http.StatusUnauthorized {
+			t.Fatalf("login after deletion: status %d, want %d", code, http.StatusUnauthorized)
+		}
+	})
+}
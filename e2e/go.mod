@@ -0,0 +1,5 @@
+module github.com/kprf42/dolgova/e2e
+
+go 1.24.2
+
+require github.com/gorilla/websocket v1.5.3
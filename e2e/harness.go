@@ -0,0 +1,318 @@
+// Package e2e поднимает auth_service и forum_service на временной базе SQLite
+// и дает тестам клиентов их HTTP и WebSocket API.
+//
+// Сервисы собираются из исходников и запускаются отдельными процессами: их пакеты
+// internal недоступны из другого модуля, а main нельзя импортировать.
+// Тесты помечены тегом e2e: go test -tags e2e ./...
+package e2e
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	jwtSecret    = "e2e-secret-key-that-is-long-enough"
+	startTimeout = 60 * time.Second
+	stopTimeout  = 10 * time.Second
+)
+
+// Env запущенные сервисы одного теста
+type Env struct {
+	AuthURL  string // http://127.0.0.1:port auth сервиса
+	ForumURL string // http://127.0.0.1:port форума
+	ForumWS  string // ws://127.0.0.1:port форума
+
+	dir   string
+	http  *http.Client
+	procs []*process
+}
+
+type process struct {
+	name string
+	cmd  *exec.Cmd
+	log  string
+	done chan struct{}
+}
+
+var (
+	buildOnce sync.Once
+	buildDir  string
+	buildErr  error
+)
+
+// Start собирает сервисы (один раз на запуск go test), запускает их на свободных портах
+// с базой во временном каталоге и ждет готовности. Остановка регистрируется в t.Cleanup.
+func Start(t testing.TB) *Env {
+	t.Helper()
+
+	buildOnce.Do(func() { buildDir, buildErr = build() })
+	if buildErr != nil {
+		t.Fatalf("build services: %v", buildErr)
+	}
+
+	env := &Env{
+		dir:  t.TempDir(),
+		http: &http.Client{Timeout: 10 * time.Second},
+	}
+	t.Cleanup(func() { env.stop(t) })
+
+	authPort := freePort(t)
+	forumPort := freePort(t)
+	forumGRPCPort := freePort(t)
+	authGRPCPort := freePort(t)
+	dbPath := filepath.Join(env.dir, "forum.db")
+
+	env.AuthURL = fmt.Sprintf("http://127.0.0.1:%d", authPort)
+	env.ForumURL = fmt.Sprintf("http://127.0.0.1:%d", forumPort)
+	env.ForumWS = fmt.Sprintf("ws://127.0.0.1:%d", forumPort)
+
+	// Auth создает таблицу users, на которую ссылаются миграции форума, поэтому стартует первым
+	env.run(t, "auth", []string{
+		"APP_ENV=development",
+		"DB_PATH=" + dbPath,
+		"SERVER_PORT=" + strconv.Itoa(authPort),
+		"GRPC_PORT=" + strconv.Itoa(authGRPCPort),
+		"JWT_SECRET=" + jwtSecret,
+		"RUNTIME_CONFIG=" + filepath.Join(env.dir, "auth-runtime.json"),
+		"FORUM_GRPC_ADDR=" + fmt.Sprintf("127.0.0.1:%d", forumGRPCPort),
+		"SESSION_REVOKE_URL=http://localhost:3000/sessions/revoke",
+	})
+	env.waitReady(t, env.AuthURL+"/readyz")
+
+	env.run(t, "forum", []string{
+		"APP_ENV=development",
+		"DB_PATH=" + dbPath,
+		"HTTP_PORT=" + strconv.Itoa(forumPort),
+		"GRPC_PORT=" + strconv.Itoa(forumGRPCPort),
+		"AUTH_GRPC_ADDR=" + fmt.Sprintf("127.0.0.1:%d", authGRPCPort),
+		"RUNTIME_CONFIG=" + filepath.Join(env.dir, "forum-runtime.json"),
+		"SEARCH_INDEX_PATH=" + filepath.Join(env.dir, "search.bleve"),
+		"UPLOADS_DIR=" + filepath.Join(env.dir, "uploads"),
+	})
+	env.waitReady(t, env.ForumURL+"/health")
+
+	return env
+}
+
+// build собирает бинарники сервисов во временный каталог
+func build() (string, error) {
+	_, file, _, _ := runtime.Caller(0)
+	root := filepath.Dir(filepath.Dir(file))
+
+	dir, err := os.MkdirTemp("", "dolgova-e2e-")
+	if err != nil {
+		return "", err
+	}
+
+	targets := []struct {
+		name string
+		args []string
+	}{
+		{"auth", []string{"build", "-o", filepath.Join(dir, "auth"), "./cmd"}},
+		{"forum", []string{"build", "-tags", "sqlite_fts5", "-o", filepath.Join(dir, "forum"), "./cmd"}},
+	}
+	for _, target := range targets {
+		cmd := exec.Command("go", target.args...)
+		cmd.Dir = filepath.Join(root, target.name+"_service")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return "", fmt.Errorf("%s: %w\n%s", target.name, err, out)
+		}
+	}
+	return dir, nil
+}
+
+// run запускает сервис name с переменными окружения env, вывод пишется в <name>.log
+func (e *Env) run(t testing.TB, name string, env []string) {
+	t.Helper()
+
+	logPath := filepath.Join(e.dir, name+".log")
+	logFile, err := os.Create(logPath)
+	if err != nil {
+		t.Fatalf("create %s log: %v", name, err)
+	}
+
+	cmd := exec.Command(filepath.Join(buildDir, name))
+	cmd.Dir = e.dir
+	cmd.Env = append(os.Environ(), env...)
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	if err := cmd.Start(); err != nil {
+		logFile.Close()
+		t.Fatalf("start %s: %v", name, err)
+	}
+
+	p := &process{name: name, cmd: cmd, log: logPath, done: make(chan struct{})}
+	go func() {
+		cmd.Wait()
+		logFile.Close()
+		close(p.done)
+	}()
+	e.procs = append(e.procs, p)
+}
+
+// waitReady ждет ответа 200 от url; если процесс завершился раньше, тест падает с его логом
+func (e *Env) waitReady(t testing.TB, url string) {
+	t.Helper()
+
+	p := e.procs[len(e.procs)-1]
+	deadline := time.Now().Add(startTimeout)
+	for time.Now().Before(deadline) {
+		select {
+		case <-p.done:
+			t.Fatalf("%s exited during startup:\n%s", p.name, readLog(p.log))
+		default:
+		}
+
+		resp, err := e.http.Get(url)
+		if err == nil {
+			resp.Body.Close()
+			if resp.StatusCode == http.StatusOK {
+				return
+			}
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	t.Fatalf("%s is not ready after %s:\n%s", p.name, startTimeout, readLog(p.log))
+}
+
+// stop останавливает сервисы в обратном порядке: сначала SIGINT, затем kill по таймауту.
+// Логи упавшего теста выводятся, чтобы не искать их во временном каталоге.
+func (e *Env) stop(t testing.TB) {
+	for i := len(e.procs) - 1; i >= 0; i-- {
+		p := e.procs[i]
+		p.cmd.Process.Signal(os.Interrupt)
+		select {
+		case <-p.done:
+		case <-time.After(stopTimeout):
+			p.cmd.Process.Kill()
+			<-p.done
+		}
+		if t.Failed() {
+			t.Logf("%s log:\n%s", p.name, readLog(p.log))
+		}
+	}
+}
+
+func readLog(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+func freePort(t testing.TB) int {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("find free port: %v", err)
+	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port
+}
+
+// Do выполняет JSON запрос к url с токеном token (если не пустой) и разбирает ответ в out
+// (если не nil). Возвращает код ответа; тело ответа с ошибкой попадает в сообщение теста.
+func (e *Env) Do(t testing.TB, method, url, token string, body, out any) int {
+	t.Helper()
+
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			t.Fatalf("marshal request: %v", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, url, reader)
+	if err != nil {
+		t.Fatalf("new request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := e.http.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, url, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("%s %s: read body: %v", method, url, err)
+	}
+	if out != nil && resp.StatusCode < 300 {
+		if err := json.Unmarshal(data, out); err != nil {
+			t.Fatalf("%s %s: decode %q: %v", method, url, data, err)
+		}
+	}
+	if resp.StatusCode >= 300 {
+		t.Logf("%s %s: %d %s", method, url, resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	return resp.StatusCode
+}
+
+// Register регистрирует пользователя и возвращает его ID
+func (e *Env) Register(t testing.TB, username, email, password string) string {
+	t.Helper()
+
+	var resp struct {
+		UserID string `json:"user_id"`
+	}
+	body := map[string]string{"username": username, "email": email, "password": password}
+	if code := e.Do(t, http.MethodPost, e.AuthURL+"/auth/register", "", body, &resp); code != http.StatusCreated {
+		t.Fatalf("register %s: status %d", email, code)
+	}
+	return resp.UserID
+}
+
+// Login входит пользователем и возвращает access токен
+func (e *Env) Login(t testing.TB, email, password string) string {
+	t.Helper()
+
+	var resp struct {
+		AccessToken string `json:"access_token"`
+	}
+	body := map[string]string{"email": email, "password": password}
+	if code := e.Do(t, http.MethodPost, e.AuthURL+"/auth/login", "", body, &resp); code != http.StatusOK {
+		t.Fatalf("login %s: status %d", email, code)
+	}
+	return resp.AccessToken
+}
+
+// DialChat подключается к чату форума
+func (e *Env) DialChat(t testing.TB, token string) *websocket.Conn {
+	t.Helper()
+
+	header := http.Header{"Authorization": {"Bearer " + token}}
+	conn, resp, err := websocket.DefaultDialer.Dial(e.ForumWS+"/api/v1/chat/ws", header)
+	if err != nil {
+		if resp != nil {
+			err = errors.Join(err, fmt.Errorf("status %d", resp.StatusCode))
+		}
+		t.Fatalf("dial chat: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
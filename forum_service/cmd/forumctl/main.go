@@ -0,0 +1,239 @@
+// forumctl служебные команды для обслуживания БД форума
+//
+//	forumctl export -format json -out backup.json
+//	forumctl export -format csv -out backup/
+//	forumctl import -format json -in backup.json
+//	forumctl import-forum -source discourse -in discourse.json -report mapping.json
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/kprf42/dolgova/forum_service/internal/backup"
+	"github.com/kprf42/dolgova/forum_service/internal/importer"
+	"github.com/kprf42/dolgova/forum_service/internal/repository"
+	"github.com/kprf42/dolgova/forum_service/internal/tenant"
+	"github.com/kprf42/dolgova/forum_service/internal/usecase"
+	"github.com/kprf42/dolgova/pkg/logger"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "export":
+		err = runExport(os.Args[2:])
+	case "import":
+		err = runImport(os.Args[2:])
+	case "import-forum":
+		err = runImportForum(os.Args[2:])
+	default:
+		usage()
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "forumctl %s: %v\n", os.Args[1], err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: forumctl <export|import|import-forum> [flags]")
+}
+
+func runExport(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	dbPath := fs.String("db", defaultDBPath(), "path to SQLite database")
+	format := fs.String("format", "json", "dump format: json or csv")
+	out := fs.String("out", "", "output file (json, default stdout) or directory (csv)")
+	fs.Parse(args)
+
+	db, err := openDB(*dbPath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	dump, err := backup.Export(context.Background(), db)
+	if err != nil {
+		return err
+	}
+
+	switch *format {
+	case "json":
+		var w io.Writer = os.Stdout
+		if *out != "" {
+			file, err := os.Create(*out)
+			if err != nil {
+				return err
+			}
+			defer file.Close()
+			w = file
+		}
+		if err := backup.WriteJSON(w, dump); err != nil {
+			return err
+		}
+	case "csv":
+		if *out == "" {
+			return fmt.Errorf("-out directory is required for csv format")
+		}
+		if err := backup.WriteCSV(*out, dump); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown format %q", *format)
+	}
+
+	for _, table := range backup.Tables {
+		fmt.Fprintf(os.Stderr, "exported %s: %d\n", table.Name, len(dump.Tables[table.Name]))
+	}
+	return nil
+}
+
+func runImport(args []string) error {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	dbPath := fs.String("db", defaultDBPath(), "path to SQLite database (schema must already exist)")
+	format := fs.String("format", "json", "dump format: json or csv")
+	in := fs.String("in", "", "input file (json, default stdin) or directory (csv)")
+	fs.Parse(args)
+
+	var dump *backup.Dump
+	var err error
+	switch *format {
+	case "json":
+		var r io.Reader = os.Stdin
+		if *in != "" {
+			file, err := os.Open(*in)
+			if err != nil {
+				return err
+			}
+			defer file.Close()
+			r = file
+		}
+		dump, err = backup.ReadJSON(r)
+	case "csv":
+		if *in == "" {
+			return fmt.Errorf("-in directory is required for csv format")
+		}
+		dump, err = backup.ReadCSV(*in)
+	default:
+		return fmt.Errorf("unknown format %q", *format)
+	}
+	if err != nil {
+		return err
+	}
+
+	db, err := openDB(*dbPath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	result, err := backup.Import(context.Background(), db, dump)
+	if err != nil {
+		return err
+	}
+
+	for _, table := range backup.Tables {
+		fmt.Fprintf(os.Stderr, "imported %s: %d inserted, %d skipped\n",
+			table.Name, result.Inserted[table.Name], result.Skipped[table.Name])
+	}
+	return nil
+}
+
+func runImportForum(args []string) error {
+	fs := flag.NewFlagSet("import-forum", flag.ExitOnError)
+	dbPath := fs.String("db", defaultDBPath(), "path to SQLite database (schema must already exist)")
+	source := fs.String("source", importer.FormatGeneric, "input format: generic, discourse or phpbb")
+	in := fs.String("in", "", "input JSON file (default stdin)")
+	reportPath := fs.String("report", "", "file to write the ID mapping report to (default stdout)")
+	tenantID := fs.String("tenant", tenant.Default, "forum (tenant) to import into")
+	fs.Parse(args)
+
+	var r io.Reader = os.Stdin
+	if *in != "" {
+		file, err := os.Open(*in)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		r = file
+	}
+
+	src, err := importer.Decode(r, *source)
+	if err != nil {
+		return err
+	}
+
+	log, err := logger.New()
+	if err != nil {
+		return err
+	}
+	defer log.Sync()
+
+	db, err := openDB(*dbPath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	imp := importer.New(
+		usecase.NewUserUseCase(repository.NewUserRepository(db, log), log),
+		usecase.NewPostUseCase(repository.NewPostRepository(db, log), nil, nil, nil, nil, nil, nil, nil, nil, log),
+		usecase.NewCommentUseCase(repository.NewCommentRepository(db, log), repository.NewPostRepository(db, log), nil, nil, nil, nil, nil, nil, log),
+		log,
+	)
+
+	report, err := imp.Import(tenant.WithID(context.Background(), *tenantID), src)
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if *reportPath != "" {
+		file, err := os.Create(*reportPath)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		w = file
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
+
+// defaultDBPath тот же файл, который использует сервис форума
+func defaultDBPath() string {
+	return filepath.Join("..", "auth_service", "auth.db")
+}
+
+func openDB(path string) (*sql.DB, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("database not found: %w", err)
+	}
+
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
@@ -0,0 +1,300 @@
+// loadgen наполняет форум данными через публичный HTTP API и измеряет пропускную
+// способность и задержки, чтобы сравнивать производительность до и после изменений
+//
+//	loadgen -users 20 -posts 200 -comments 1000 -concurrency 16
+//
+// Пользователи создаются с уникальным префиксом, поэтому команду можно запускать повторно.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"text/tabwriter"
+	"time"
+)
+
+type config struct {
+	authURL     string
+	forumURL    string
+	users       int
+	posts       int
+	comments    int
+	concurrency int
+	categories  []string
+}
+
+func main() {
+	var cfg config
+	var categories string
+	flag.StringVar(&cfg.authURL, "auth", "http://localhost:8080", "auth service base URL")
+	flag.StringVar(&cfg.forumURL, "forum", "http://localhost:8081", "forum service base URL")
+	flag.IntVar(&cfg.users, "users", 10, "number of users to register")
+	flag.IntVar(&cfg.posts, "posts", 100, "number of posts to create")
+	flag.IntVar(&cfg.comments, "comments", 500, "number of comments to create")
+	flag.IntVar(&cfg.concurrency, "concurrency", 10, "concurrent requests")
+	flag.StringVar(&categories, "categories", "1,2,3", "comma separated category IDs for posts")
+	timeout := flag.Duration("timeout", 10*time.Second, "HTTP request timeout")
+	flag.Parse()
+
+	cfg.categories = strings.Split(categories, ",")
+	if err := cfg.validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "loadgen: %v\n", err)
+		os.Exit(2)
+	}
+
+	g := &generator{
+		cfg:    cfg,
+		client: &http.Client{Timeout: *timeout},
+		run:    fmt.Sprintf("%x", time.Now().UnixNano()),
+	}
+	if err := g.Run(os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "loadgen: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func (c config) validate() error {
+	var errs []error
+	if c.users < 1 {
+		errs = append(errs, errors.New("-users must be at least 1"))
+	}
+	if c.posts < 0 || c.comments < 0 {
+		errs = append(errs, errors.New("-posts and -comments must not be negative"))
+	}
+	if c.comments > 0 && c.posts == 0 {
+		errs = append(errs, errors.New("-comments requires at least one post"))
+	}
+	if c.concurrency < 1 {
+		errs = append(errs, errors.New("-concurrency must be at least 1"))
+	}
+	return errors.Join(errs...)
+}
+
+// user зарегистрированный пользователь с токеном доступа
+type user struct {
+	id    string
+	token string
+}
+
+type generator struct {
+	cfg    config
+	client *http.Client
+	run    string // Префикс имен пользователей этого запуска
+
+	users []user
+	posts []string
+}
+
+// Run выполняет этапы по очереди и печатает отчет. Этап, на котором не удалось
+// создать ни одной записи, прерывает запуск: следующим этапам нечего использовать.
+func (g *generator) Run(w io.Writer) error {
+	var reports []*report
+
+	g.users = make([]user, g.cfg.users)
+	register := g.phase("register+login", g.cfg.users, func(i int) error {
+		u, err := g.createUser(i)
+		if err == nil {
+			g.users[i] = u
+		}
+		return err
+	})
+	reports = append(reports, register)
+	g.users = slices.DeleteFunc(g.users, func(u user) bool { return u.token == "" })
+	if len(g.users) == 0 {
+		printReport(w, reports)
+		return fmt.Errorf("no users created: %w", register.firstErr)
+	}
+
+	if g.cfg.posts > 0 {
+		g.posts = make([]string, g.cfg.posts)
+		posts := g.phase("create post", g.cfg.posts, func(i int) error {
+			id, err := g.createPost(g.users[i%len(g.users)], i)
+			g.posts[i] = id
+			return err
+		})
+		reports = append(reports, posts)
+		g.posts = slices.DeleteFunc(g.posts, func(id string) bool { return id == "" })
+		if len(g.posts) == 0 {
+			printReport(w, reports)
+			return fmt.Errorf("no posts created: %w", posts.firstErr)
+		}
+	}
+
+	if g.cfg.comments > 0 {
+		reports = append(reports, g.phase("create comment", g.cfg.comments, func(i int) error {
+			return g.createComment(g.users[rand.IntN(len(g.users))], g.posts[rand.IntN(len(g.posts))], i)
+		}))
+	}
+
+	printReport(w, reports)
+	return nil
+}
+
+// phase выполняет op для 0..n-1 в cfg.concurrency потоков и собирает задержки
+func (g *generator) phase(name string, n int, op func(i int) error) *report {
+	r := &report{name: name, latencies: make([]time.Duration, 0, n)}
+	var mu sync.Mutex
+	var next atomic.Int64
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for range min(g.cfg.concurrency, n) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				i := int(next.Add(1)) - 1
+				if i >= n {
+					return
+				}
+				opStart := time.Now()
+				err := op(i)
+				elapsed := time.Since(opStart)
+
+				mu.Lock()
+				if err != nil {
+					r.errors++
+					if r.firstErr == nil {
+						r.firstErr = err
+					}
+				} else {
+					r.latencies = append(r.latencies, elapsed)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	r.duration = time.Since(start)
+	return r
+}
+
+func (g *generator) createUser(i int) (user, error) {
+	username := fmt.Sprintf("loadgen-%s-%d", g.run, i)
+	email := username + "@example.com"
+	password := "loadgen-password"
+
+	var registered struct {
+		UserID string `json:"user_id"`
+	}
+	body := map[string]string{"username": username, "email": email, "password": password}
+	if err := g.do(http.MethodPost, g.cfg.authURL+"/auth/register", "", body, http.StatusCreated, &registered); err != nil {
+		return user{}, fmt.Errorf("register: %w", err)
+	}
+
+	var login struct {
+		AccessToken string `json:"access_token"`
+	}
+	body = map[string]string{"email": email, "password": password}
+	if err := g.do(http.MethodPost, g.cfg.authURL+"/auth/login", "", body, http.StatusOK, &login); err != nil {
+		return user{}, fmt.Errorf("login: %w", err)
+	}
+
+	return user{id: registered.UserID, token: login.AccessToken}, nil
+}
+
+func (g *generator) createPost(u user, i int) (string, error) {
+	var post struct {
+		ID string `json:"id"`
+	}
+	body := map[string]string{
+		"title":       fmt.Sprintf("Load test post %d", i),
+		"content":     fmt.Sprintf("Post %d created by loadgen run %s", i, g.run),
+		"category_id": g.cfg.categories[i%len(g.cfg.categories)],
+	}
+	if err := g.do(http.MethodPost, g.cfg.forumURL+"/api/v1/posts", u.token, body, http.StatusOK, &post); err != nil {
+		return "", fmt.Errorf("create post: %w", err)
+	}
+	return post.ID, nil
+}
+
+func (g *generator) createComment(u user, postID string, i int) error {
+	body := map[string]string{"content": fmt.Sprintf("Load test comment %d", i)}
+	url := fmt.Sprintf("%s/api/v1/posts/%s/comments", g.cfg.forumURL, postID)
+	if err := g.do(http.MethodPost, url, u.token, body, http.StatusCreated, nil); err != nil {
+		return fmt.Errorf("create comment: %w", err)
+	}
+	return nil
+}
+
+// do отправляет JSON запрос и разбирает ответ в out, если код ответа равен want
+func (g *generator) do(method, url, token string, body any, want int, out any) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(method, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != want {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	if out == nil {
+		_, err = io.Copy(io.Discard, resp.Body)
+		return err
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// report результаты одного этапа. Задержки собираются только по успешным операциям.
+type report struct {
+	name      string
+	duration  time.Duration
+	latencies []time.Duration
+	errors    int
+	firstErr  error
+}
+
+func (r *report) percentile(p float64) time.Duration {
+	if len(r.latencies) == 0 {
+		return 0
+	}
+	return r.latencies[int(p*float64(len(r.latencies)-1))]
+}
+
+func printReport(w io.Writer, reports []*report) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "phase\tok\terrors\ttime\tops/s\tp50\tp95\tp99\tmax\t")
+	for _, r := range reports {
+		slices.Sort(r.latencies)
+		ok := len(r.latencies)
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%s\t%.1f\t%s\t%s\t%s\t%s\t\n",
+			r.name, ok, r.errors, r.duration.Round(time.Millisecond),
+			float64(ok)/r.duration.Seconds(),
+			r.percentile(0.50).Round(time.Microsecond),
+			r.percentile(0.95).Round(time.Microsecond),
+			r.percentile(0.99).Round(time.Microsecond),
+			r.percentile(1).Round(time.Microsecond))
+	}
+	tw.Flush()
+
+	for _, r := range reports {
+		if r.firstErr != nil {
+			fmt.Fprintf(w, "%s: first error: %v\n", r.name, r.firstErr)
+		}
+	}
+}
@@ -3,46 +3,53 @@ package main
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"net"
 	"net/http"
+	"net/netip"
+	"net/url"
 	"os"
-	"os/signal"
 	"path/filepath"
-	"strings"
-	"syscall"
+	"strconv"
 	"time"
 
 	"github.com/go-chi/chi/v5"
-	"github.com/golang-jwt/jwt/v5"
-	"github.com/golang-migrate/migrate/v4"
-	"github.com/golang-migrate/migrate/v4/database/sqlite3"
-	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/kprf42/dolgova/forum_service/internal/auth"
+	"github.com/kprf42/dolgova/forum_service/internal/authclient"
+	"github.com/kprf42/dolgova/forum_service/internal/config"
 	grpcdelivery "github.com/kprf42/dolgova/forum_service/internal/delivery/grpcdel"
 	httpdelivery "github.com/kprf42/dolgova/forum_service/internal/delivery/http"
 	"github.com/kprf42/dolgova/forum_service/internal/delivery/http/handlers"
 	"github.com/kprf42/dolgova/forum_service/internal/delivery/websocket"
+	"github.com/kprf42/dolgova/forum_service/internal/events"
 	"github.com/kprf42/dolgova/forum_service/internal/repository"
+	"github.com/kprf42/dolgova/forum_service/internal/search"
+	"github.com/kprf42/dolgova/forum_service/internal/tenant"
 	chat "github.com/kprf42/dolgova/forum_service/internal/usecase"
 	comment "github.com/kprf42/dolgova/forum_service/internal/usecase"
+	moderation "github.com/kprf42/dolgova/forum_service/internal/usecase"
 	post "github.com/kprf42/dolgova/forum_service/internal/usecase"
+	searchuc "github.com/kprf42/dolgova/forum_service/internal/usecase"
+	stats "github.com/kprf42/dolgova/forum_service/internal/usecase"
+	"github.com/kprf42/dolgova/forum_service/migrations"
+	"github.com/kprf42/dolgova/pkg/audit"
+	"github.com/kprf42/dolgova/pkg/ipban"
+	"github.com/kprf42/dolgova/pkg/lifecycle"
 	"github.com/kprf42/dolgova/pkg/logger"
-	"github.com/kprf42/dolgova/proto/forum"
+	"github.com/kprf42/dolgova/pkg/scheduler"
+	"github.com/kprf42/dolgova/pkg/secheaders"
+	"github.com/kprf42/dolgova/pkg/uploads"
+	forum "github.com/kprf42/dolgova/proto/forum/v1"
 	_ "github.com/mattn/go-sqlite3"
+	"github.com/prometheus/client_golang/prometheus"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
 )
 
-// JWTClaims кастомная структура claims с реализацией всех необходимых методов
-type JWTClaims struct {
-	UserID string `json:"user_id"`
-	jwt.RegisteredClaims
-}
-
-// AuthMiddleware структура для middleware аутентификации
-type AuthMiddleware struct {
-	JWTSecret string
-}
-
 func enableCORS(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
@@ -60,69 +67,6 @@ func enableCORS(next http.Handler) http.Handler {
 	})
 }
 
-func (m *AuthMiddleware) JWT(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		fmt.Printf("\n=== JWT Middleware ===\n")
-		fmt.Printf("Request URL: %s\n", r.URL.String())
-		fmt.Printf("Request Method: %s\n", r.Method)
-
-		if r.Method == "OPTIONS" {
-			fmt.Printf("OPTIONS request - skipping auth\n")
-			w.WriteHeader(http.StatusOK)
-			return
-		}
-
-		authHeader := r.Header.Get("Authorization")
-		fmt.Printf("Authorization header: '%s'\n", authHeader)
-
-		if authHeader == "" {
-			fmt.Printf("ERROR: No Authorization header\n")
-			http.Error(w, "Authorization header is required", http.StatusUnauthorized)
-			return
-		}
-
-		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
-		if tokenString == authHeader {
-			fmt.Printf("ERROR: No Bearer prefix in token\n")
-			http.Error(w, "Bearer token required", http.StatusUnauthorized)
-			return
-		}
-		fmt.Printf("Token string after trim: '%s'\n", tokenString)
-
-		token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
-			return []byte(m.JWTSecret), nil
-		})
-
-		if err != nil {
-			fmt.Printf("ERROR: Token parse error: %v\n", err)
-			http.Error(w, "Invalid token: "+err.Error(), http.StatusUnauthorized)
-			return
-		}
-
-		if !token.Valid {
-			fmt.Printf("ERROR: Token is invalid\n")
-			http.Error(w, "Invalid token", http.StatusUnauthorized)
-			return
-		}
-
-		claims, ok := token.Claims.(*JWTClaims)
-		if !ok {
-			fmt.Printf("ERROR: Invalid token claims type\n")
-			http.Error(w, "Invalid token claims", http.StatusUnauthorized)
-			return
-		}
-
-		fmt.Printf("Token claims: %+v\n", claims)
-		fmt.Printf("User ID from token: %s\n", claims.UserID)
-
-		ctx := context.WithValue(r.Context(), "user_id", claims.UserID)
-		fmt.Printf("Added user_id to context: %s\n", claims.UserID)
-		fmt.Printf("=== End JWT Middleware ===\n\n")
-
-		next.ServeHTTP(w, r.WithContext(ctx))
-	})
-}
-
 func main() {
 	// Инициализация логгера
 	log, err := logger.New()
@@ -131,15 +75,39 @@ func main() {
 	}
 	defer log.Sync()
 
-	// Загрузка конфигурации
+	// Загрузка конфигурации. Все ошибки выводятся одним сообщением, чтобы не исправлять их по одной
 	cfg, err := loadConfig()
 	if err != nil {
-		log.Fatal("Failed to load config", logger.Error(err))
+		log.Fatal("Invalid configuration", logger.Error(err))
 	}
 
+	// Настройки, применяемые без перезапуска
+	// Уровень логирования по умолчанию задается LOG_LEVEL, runtime.json может его переопределить
+	runtimeDefaults := config.DefaultRuntime()
+	runtimeDefaults.LogLevel = log.Level()
+	runtimeCfg, err := config.NewRuntimeWatcher(cfg.RuntimeConfigPath, runtimeDefaults, log)
+	if err != nil {
+		log.Fatal("Failed to load runtime config", logger.Error(err))
+	}
+	appliedLevel := ""
+	runtimeCfg.OnChange(func(rt config.Runtime) {
+		// Уровень применяется, только если он изменился в файле: правка других настроек
+		// не должна сбрасывать уровень, выставленный через /api/v1/admin/log-level
+		if rt.LogLevel == appliedLevel {
+			return
+		}
+		appliedLevel = rt.LogLevel
+		if err := log.SetLevel(rt.LogLevel); err != nil {
+			log.Error("Failed to apply log level", logger.String("level", rt.LogLevel), logger.Error(err))
+		}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go runtimeCfg.Watch(ctx, runtimeConfigPollInterval)
+
 	// Подключение к существующей базе данных auth сервиса
-	dbPath := filepath.Join("..", "auth_service", "auth.db")
-	db, err := sql.Open("sqlite3", dbPath)
+	db, err := sql.Open("sqlite3", cfg.DBPath)
 	if err != nil {
 		log.Fatal("Failed to connect to database", logger.Error(err))
 	}
@@ -156,150 +124,573 @@ func main() {
 	}
 
 	// Применение миграций форумного сервиса
-	if err := runForumMigrations(db, log); err != nil {
+	migrator, err := runForumMigrations(db, log)
+	if err != nil {
 		log.Fatal("Failed to apply forum migrations", logger.Error(err))
 	}
 
-	// Инициализация репозиториев
+	// Токены проверяет auth сервис: секрет подписи хранится только в нем
+	authClient, err := authclient.New(authclient.DefaultConfig(cfg.AuthGRPCAddr), log)
+	if err != nil {
+		log.Fatal("Failed to create auth client", logger.Error(err))
+	}
+	defer authClient.Close()
+
+	// Инициализация репозиториев. Запрос к базе ограничен QueryTimeout или дедлайном запроса клиента,
+	// если тот раньше (роутер ограничивает обработку 60 секундами)
+	repository.SetQueryTimeout(cfg.QueryTimeout)
 	postRepo := repository.NewPostRepository(db, log)
 	commentRepo := repository.NewCommentRepository(db, log)
 	chatRepo := repository.NewChatRepository(db, log)
+	statsRepo := repository.NewStatsRepository(db, log)
+	userRepo := repository.NewUserRepository(db, log)
+	moderationRepo := repository.NewModerationRepository(db, log)
+	categoryModeratorRepo := repository.NewCategoryModeratorRepository(db, log)
+	attachmentRepo := repository.NewAttachmentRepository(db, log)
+	profileRepo := repository.NewProfileRepository(db, log)
+	karmaRepo := repository.NewKarmaRepository(db, log)
+	blockRepo := repository.NewBlockRepository(db, log)
+	shareLinkRepo := repository.NewShareLinkRepository(db, log)
+	readMarkRepo := repository.NewReadMarkRepository(db, log)
+	announcementRepo := repository.NewAnnouncementRepository(db, log)
+	categoryRepo := repository.NewCategoryRepository(db, log)
+	reportRepo := repository.NewReportRepository(db, log)
+
+	// Поисковый индекс обновляется по доменным событиям постов и комментариев
+	searchIndex, err := search.Open(cfg.Search, db)
+	if err != nil {
+		// Форум работает и без поиска
+		log.Error("Failed to open search index, search disabled",
+			logger.String("backend", cfg.Search.Backend),
+			logger.Error(err))
+		searchIndex = search.Disabled{}
+	}
+	defer searchIndex.Close()
 
-	// Инициализация use cases
-	postUC := post.NewPostUseCase(postRepo, log)
-	commentUC := comment.NewCommentUseCase(commentRepo, log)
-	chatUC := chat.NewChatUseCase(chatRepo, log)
+	searchUC := searchuc.NewSearchUseCase(searchIndex, log)
+	bus := events.NewBus()
+	bus.Subscribe(searchUC.HandleEvent)
 
-	// Инициализация WebSocket Hub
-	hub := websocket.NewHub(chatUC)
-	go hub.Run()
+	// Загрузки (вложения, аватары) отдаются по /static/uploads или с внешнего адреса
+	uploadsBaseURL := cfg.Uploads.PublicURL
+	if uploadsBaseURL == "" {
+		uploadsBaseURL = uploadsPrefix
+	}
+	uploadStorage, err := uploads.NewLocalStorage(cfg.Uploads.Dir, uploadsBaseURL)
+	if err != nil {
+		log.Fatal("Failed to open uploads storage", logger.Error(err))
+	}
+	uploadsHandler := uploads.Handler(uploadStorage, uploadsPrefix, cfg.Uploads.PublicURL)
+
+	// Инициализация use cases
+	// Вложения: уменьшенные копии изображений строятся в фоне
+	attachmentUC := post.NewAttachmentUseCase(attachmentRepo, uploadStorage, thumbnailWorkers, log)
+
+	// Категории: посты публикуются только в существующие категории
+	categoryUC := post.NewCategoryUseCase(categoryRepo, log)
+
+	// Права модераторов: глобальные по роли или в назначенных категориях
+	moderators := moderation.NewModeratorAccess(userRepo, categoryModeratorRepo, postRepo, log)
+
+	// Премодерация: порог читается из runtime настроек при каждой публикации
+	moderationUC := moderation.NewModerationUseCase(moderationRepo, postRepo, commentRepo, moderators, func() int {
+		return runtimeCfg.Current().PremoderationThreshold
+	}, bus, log)
+
+	// Карма: порог для ссылок читается из runtime настроек
+	karmaUC := post.NewKarmaUseCase(karmaRepo, func() int {
+		return runtimeCfg.Current().LinkKarmaThreshold
+	}, log)
+	// Отметки о прочтении: списки постов для аутентифицированного читателя получают is_unread
+	unreadUC := post.NewUnreadUseCase(readMarkRepo, postRepo, log)
+	postUC := post.NewPostUseCase(postRepo, categoryUC, moderationUC, moderators, attachmentUC, karmaUC, unreadUC, authClient, bus, log)
+	commentUC := comment.NewCommentUseCase(commentRepo, postRepo, moderationUC, moderators, attachmentUC, karmaUC, authClient, bus, log)
+	chatUC := chat.NewChatUseCase(chatRepo, attachmentUC, authClient, log)
+	profileUC := post.NewProfileUseCase(profileRepo, karmaRepo, postRepo, commentRepo, uploadStorage, log)
+	blockUC := post.NewBlockUseCase(blockRepo, userRepo, bus, log)
+	announcementUC := post.NewAnnouncementUseCase(announcementRepo, log)
+	userUC := post.NewUserUseCase(userRepo, log)
+	shareUC := post.NewShareUseCase(shareLinkRepo, postRepo, commentRepo, moderators, log)
+	reportUC := post.NewReportUseCase(reportRepo, postRepo, commentRepo, moderators, log)
+	// Популярные посты: период активности читается из runtime настроек
+	trendingUC := post.NewTrendingUseCase(postUC, func() time.Duration {
+		return time.Duration(runtimeCfg.Current().TrendingWindow)
+	}, log)
+
+	// Инициализация WebSocket Hub: сообщения заблокированных пользователей клиенту не рассылаются
+	hub := websocket.NewHub(chatUC, blockUC)
+	bus.Subscribe(hub.HandleEvent)
+
+	statsUC := stats.NewStatsUseCase(statsRepo, hub, log)
+
+	// Фоновые задачи по расписанию
+	jobs := scheduler.New(prometheus.DefaultRegisterer, log)
+	if err := jobs.Add("chat-cleanup", chatCleanupSchedule, chatCleanupJitter, chatCleanupJob(chatUC, runtimeCfg)); err != nil {
+		log.Fatal("Failed to schedule job", logger.Error(err))
+	}
+	if err := jobs.Add("trending-refresh", trendingRefreshSchedule, trendingRefreshJitter, trendingUC.Refresh); err != nil {
+		log.Fatal("Failed to schedule job", logger.Error(err))
+	}
 
 	// Инициализация обработчиков
 	postHandlers := handlers.NewPostHandlers(postUC)
 	commentHandlers := handlers.NewCommentHandlers(commentUC)
-	chatHandlers := handlers.NewChatHandlers(hub, chatUC)
+	chatHandlers := handlers.NewChatHandlers(hub, chatUC, httpdelivery.WebSocketOrigin(runtimeCfg, cfg.CookieAuth))
+	// Без auth сервиса чтение форума продолжает работать, поэтому его отказ не делает экземпляр недоступным
+	healthHandlers := handlers.NewHealthHandlers(migrator,
+		handlers.HealthCheck{Name: "database", Check: db.PingContext},
+		handlers.HealthCheck{Name: "auth_grpc", Check: authClient.Ping, Optional: true},
+		handlers.HealthCheck{Name: "websocket_hub", Check: hub.Ping},
+	)
+	statsHandlers := handlers.NewStatsHandlers(statsUC)
+	searchHandlers := handlers.NewSearchHandlers(searchUC)
+	moderationHandlers := handlers.NewModerationHandlers(moderationUC)
+	categoryModeratorHandlers := handlers.NewCategoryModeratorHandlers(moderators, categoryUC)
+	attachmentHandlers := handlers.NewAttachmentHandlers(attachmentUC)
+	profileHandlers := handlers.NewProfileHandlers(profileUC)
+	blockHandlers := handlers.NewBlockHandlers(blockUC)
+	shareHandlers := handlers.NewShareHandlers(shareUC, cfg.SiteURL)
+	unreadHandlers := handlers.NewUnreadHandlers(unreadUC, categoryUC)
+	announcementHandlers := handlers.NewAnnouncementHandlers(announcementUC)
+	categoryHandlers := handlers.NewCategoryHandlers(categoryUC)
+	reportHandlers := handlers.NewReportHandlers(reportUC)
+	trendingHandlers := handlers.NewTrendingHandlers(trendingUC)
+
+	// Баны по IP: таблица общая с auth сервисом, поэтому список периодически перечитывается
+	banStore := ipban.NewStore(db)
+	bans := ipban.NewChecker(banStore, log)
+	if err := bans.Refresh(ctx); err != nil {
+		log.Fatal("Failed to load ip bans", logger.Error(err))
+	}
+	go bans.Watch(ctx, ipBanRefreshInterval)
+	ipBanHandlers := handlers.NewIPBanHandlers(banStore, bans)
+
+	// Журнал аудита: таблица общая с auth сервисом, туда же пишутся запросы под имперсонацией
+	auditLog := audit.NewStore(db)
+	auditHandlers := handlers.NewAuditHandlers(auditLog)
+
+	// Сообщества (несколько форумов в одном развертывании)
+	tenants := tenant.NewResolver(runtimeCfg)
 
 	// Создание HTTP роутера
-	router := httpdelivery.NewRouter(postHandlers, commentHandlers, chatHandlers, cfg.JWTSecret)
+	if cfg.MockAuth {
+		log.Warn("MOCK_AUTH enabled: requests with X-Debug-User header are authenticated without a token")
+	}
+	router := httpdelivery.NewRouter(postHandlers, commentHandlers, chatHandlers, healthHandlers, statsHandlers, searchHandlers, ipBanHandlers, moderationHandlers, categoryModeratorHandlers, auditHandlers, attachmentHandlers, profileHandlers, blockHandlers, shareHandlers, unreadHandlers, announcementHandlers, categoryHandlers, reportHandlers, trendingHandlers, uploadsHandler, log.LevelHandler(), userRepo, authClient, cfg.CookieAuth, cfg.MockAuth, runtimeCfg, tenants, cfg.TrustedProxies, bans, auditLog, log)
 
 	// Настройка HTTP сервера
 	httpServer := &http.Server{
 		Addr:         fmt.Sprintf(":%d", cfg.HTTPPort),
-		Handler:      router,
+		Handler:      secheaders.Middleware(securityHeaders(cfg))(router),
 		ReadTimeout:  10 * time.Second,
 		WriteTimeout: 10 * time.Second,
 	}
 
-	// Настройка gRPC сервера
-	grpcServer := grpc.NewServer()
-	forum.RegisterForumServiceServer(grpcServer, grpcdelivery.NewForumServer(postUC, commentUC, chatUC))
+	// Настройка TLS (HTTP/2 включается через ALPN)
+	var grpcOpts []grpc.ServerOption
+	if cfg.TLS.Enabled() {
+		httpTLS, err := cfg.TLS.HTTPConfig()
+		if err != nil {
+			log.Fatal("Failed to configure HTTP TLS", logger.Error(err))
+		}
+		httpServer.TLSConfig = httpTLS
 
-	// Запуск серверов
-	go startHTTPServer(httpServer, cfg.HTTPPort, log)
-	go startGRPCServer(grpcServer, cfg.GRPCPort, log)
+		grpcTLS, err := cfg.TLS.GRPCConfig()
+		if err != nil {
+			log.Fatal("Failed to configure gRPC TLS", logger.Error(err))
+		}
+		grpcOpts = append(grpcOpts, grpc.Creds(credentials.NewTLS(grpcTLS)))
+
+		log.Info("TLS enabled",
+			logger.Bool("grpc_mtls", cfg.TLS.GRPCClientCAFile != ""))
+	}
+
+	// Настройка gRPC сервера
+	// Порядок: логирование и метрики видят итоговый код, в т.ч. после восстановления от паники
+	// и истечения дедлайна
+	grpcOpts = append(grpcOpts, cfg.GRPC.ServerOptions()...)
+	loggingInterceptor := grpcdelivery.NewLoggingInterceptor(log)
+	metricsInterceptor := grpcdelivery.NewMetricsInterceptor(prometheus.DefaultRegisterer)
+	recoveryInterceptor := grpcdelivery.NewRecoveryInterceptor(log)
+	deadlineInterceptor := grpcdelivery.NewDeadlineInterceptor(cfg.GRPC.DefaultDeadline)
+	tenantInterceptor := grpcdelivery.NewTenantInterceptor(tenants)
+	authInterceptor := grpcdelivery.NewAuthInterceptor(authClient, auditLog, log)
+	grpcOpts = append(grpcOpts,
+		grpc.ChainUnaryInterceptor(
+			loggingInterceptor.Unary(),
+			metricsInterceptor.Unary(),
+			recoveryInterceptor.Unary(),
+			deadlineInterceptor.Unary(),
+			tenantInterceptor.Unary(),
+			authInterceptor.Unary(),
+		),
+		grpc.ChainStreamInterceptor(
+			loggingInterceptor.Stream(),
+			metricsInterceptor.Stream(),
+			recoveryInterceptor.Stream(),
+			deadlineInterceptor.Stream(),
+			tenantInterceptor.Stream(),
+			authInterceptor.Stream(),
+		),
+	)
+	grpcServer := grpc.NewServer(grpcOpts...)
+	forum.RegisterForumServiceServer(grpcServer, grpcdelivery.NewForumServer(postUC, commentUC, chatUC, userUC, hub))
+
+	// Стандартный health-check и reflection для grpcurl, балансировщиков и проб Kubernetes
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	healthServer.SetServingStatus(forum.ForumService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(grpcServer, healthServer)
+	reflection.Register(grpcServer)
+
+	// Компоненты останавливаются в обратном порядке регистрации:
+	// health-check -> HTTP -> gRPC -> фоновые задачи -> WebSocket Hub
+	lm := lifecycle.New(shutdownTimeout, log)
+	lm.Add("websocket-hub", func() error {
+		hub.Run()
+		return nil
+	}, hub.Stop)
+	lm.Add("scheduler", func() error {
+		jobs.Run()
+		return nil
+	}, jobs.Stop)
+	lm.Add("thumbnails", func() error {
+		attachmentUC.Run()
+		return nil
+	}, attachmentUC.Stop)
+	lm.Add("grpc-server", func() error {
+		return serveGRPC(grpcServer, cfg.GRPCPort, log)
+	}, func(ctx context.Context) error {
+		return stopGRPC(ctx, grpcServer)
+	})
+	lm.Add("http-server", func() error {
+		return serveHTTP(httpServer, cfg.HTTPPort, log)
+	}, httpServer.Shutdown)
+	lm.Add("grpc-health", nil, func(context.Context) error {
+		// Сообщаем клиентам health-check, что новые запросы принимать не стоит
+		healthServer.Shutdown()
+		return nil
+	})
 
-	// Ожидание сигнала завершения
-	waitForShutdownSignal(httpServer, grpcServer, log)
+	if err := lm.Run(ctx); err != nil {
+		log.Error("Service stopped with errors", logger.Error(err))
+	}
 }
 
+const (
+	runtimeConfigPollInterval = 5 * time.Second
+	ipBanRefreshInterval      = 30 * time.Second
+	chatCleanupSchedule       = "@hourly"
+	chatCleanupJitter         = 5 * time.Minute
+	trendingRefreshSchedule   = "@every 5m"
+	trendingRefreshJitter     = 30 * time.Second
+	shutdownTimeout           = 10 * time.Second
+	uploadsPrefix             = "/static/uploads"
+	thumbnailWorkers          = 2
+)
+
 type Config struct {
-	HTTPPort  int
-	GRPCPort  int
-	JWTSecret string
+	DBPath            string // База auth сервиса, общая для обоих сервисов
+	HTTPPort          int
+	GRPCPort          int
+	AuthGRPCAddr      string
+	RuntimeConfigPath string
+	TLS               config.TLS
+	CookieAuth        bool
+	MockAuth          bool          // Принимать X-Debug-User вместо токена, только в development
+	Env               string        // development или production
+	CSP               string        // Переопределяет Content-Security-Policy окружения, если задан
+	QueryTimeout      time.Duration // Ограничение одного запроса к базе
+	GRPC              config.GRPC
+	Search            search.Config
+	Uploads           uploads.Config
+	SiteURL           string         // Адрес фронтенда для коротких ссылок на посты; пусто - относительные ссылки
+	TrustedProxies    []netip.Prefix // Прокси, которым доверяются X-Forwarded-For и X-Real-IP; пусто - никому
 }
 
 func loadConfig() (*Config, error) {
-	return &Config{
-		HTTPPort:  8081,
-		GRPCPort:  50051,
-		JWTSecret: "your-strong-secret-key",
-	}, nil
-}
+	runtimeConfigPath := os.Getenv("RUNTIME_CONFIG")
+	if runtimeConfigPath == "" {
+		runtimeConfigPath = "runtime.json"
+	}
 
-func runForumMigrations(db *sql.DB, log *logger.Logger) error {
-	log.Info("Applying forum service migrations")
+	searchBackend := os.Getenv("SEARCH_BACKEND")
+	if searchBackend == "" {
+		searchBackend = search.BackendFTS5
+	}
+	searchPath := os.Getenv("SEARCH_INDEX_PATH")
+	if searchPath == "" {
+		searchPath = "search.bleve"
+	}
 
-	// Получаем абсолютный путь к миграциям из auth сервиса
-	migrationsPath := filepath.Join("..", "auth_service", "migrations")
-	absPath, err := filepath.Abs(migrationsPath)
-	if err != nil {
-		return fmt.Errorf("failed to get absolute path: %w", err)
+	uploadsDir := os.Getenv("UPLOADS_DIR")
+	if uploadsDir == "" {
+		uploadsDir = "uploads"
 	}
 
-	// Проверяем существование папки
-	if _, err := os.Stat(absPath); os.IsNotExist(err) {
-		return fmt.Errorf("auth service migrations directory does not exist: %s", absPath)
+	env := os.Getenv("APP_ENV")
+	if env == "" {
+		env = "development"
 	}
 
-	// Инициализируем драйвер SQLite
-	driver, err := sqlite3.WithInstance(db, &sqlite3.Config{})
+	authGRPCAddr := os.Getenv("AUTH_GRPC_ADDR")
+	if authGRPCAddr == "" {
+		authGRPCAddr = "localhost:50052"
+	}
+
+	dbPath := os.Getenv("DB_PATH")
+	if dbPath == "" {
+		dbPath = filepath.Join("..", "auth_service", "auth.db")
+	}
+
+	var errs []error
+	httpPort, grpcPort := 8081, 50051
+	queryTimeout := repository.DefaultQueryTimeout
+	if value := os.Getenv("DB_QUERY_TIMEOUT"); value != "" {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid DB_QUERY_TIMEOUT %q: expected a duration", value))
+		} else {
+			queryTimeout = d
+		}
+	}
+
+	// Порты переопределяются, чтобы запускать несколько экземпляров, например в e2e тестах
+	ports := []struct {
+		env string
+		dst *int
+	}{{"HTTP_PORT", &httpPort}, {"GRPC_PORT", &grpcPort}}
+	for _, p := range ports {
+		if value := os.Getenv(p.env); value != "" {
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("invalid %s %q: expected a port number", p.env, value))
+				continue
+			}
+			*p.dst = n
+		}
+	}
+
+	grpcCfg, err := config.LoadGRPC()
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	trustedProxies, err := ipban.ParsePrefixes(os.Getenv("TRUSTED_PROXIES"))
 	if err != nil {
-		return fmt.Errorf("failed to create migration driver: %w", err)
+		errs = append(errs, fmt.Errorf("invalid TRUSTED_PROXIES: %w", err))
+	}
+
+	cfg := &Config{
+		DBPath:            dbPath,
+		HTTPPort:          httpPort,
+		GRPCPort:          grpcPort,
+		AuthGRPCAddr:      authGRPCAddr,
+		RuntimeConfigPath: runtimeConfigPath,
+		TLS: config.TLS{
+			CertFile:         os.Getenv("TLS_CERT_FILE"),
+			KeyFile:          os.Getenv("TLS_KEY_FILE"),
+			GRPCClientCAFile: os.Getenv("GRPC_CLIENT_CA_FILE"),
+		},
+		CookieAuth:     os.Getenv("COOKIE_AUTH") == "true",
+		MockAuth:       os.Getenv("MOCK_AUTH") == "true",
+		Env:            env,
+		CSP:            os.Getenv("CONTENT_SECURITY_POLICY"),
+		SiteURL:        os.Getenv("SITE_URL"),
+		QueryTimeout:   queryTimeout,
+		GRPC:           grpcCfg,
+		TrustedProxies: trustedProxies,
+		Search: search.Config{
+			Backend: searchBackend,
+			Path:    searchPath,
+		},
+		Uploads: uploads.Config{
+			Dir:       uploadsDir,
+			PublicURL: os.Getenv("UPLOADS_PUBLIC_URL"),
+		},
+	}
+
+	if err := cfg.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// Validate проверяет конфигурацию целиком и возвращает все найденные ошибки сразу
+func (c *Config) Validate() error {
+	var errs []error
+
+	for _, port := range []struct {
+		name  string
+		value int
+	}{{"HTTP port", c.HTTPPort}, {"gRPC port", c.GRPCPort}} {
+		if port.value < 1 || port.value > 65535 {
+			errs = append(errs, fmt.Errorf("%s %d is out of range 1-65535", port.name, port.value))
+		}
+	}
+	if c.HTTPPort == c.GRPCPort {
+		errs = append(errs, fmt.Errorf("HTTP and gRPC servers cannot share port %d", c.HTTPPort))
+	}
+
+	if c.Env != "development" && c.Env != "production" {
+		errs = append(errs, fmt.Errorf("APP_ENV %q: expected development or production", c.Env))
+	}
+	if c.MockAuth && c.Env == "production" {
+		errs = append(errs, errors.New("MOCK_AUTH must not be enabled in production"))
+	}
+
+	if info, err := os.Stat(filepath.Dir(c.DBPath)); err != nil {
+		errs = append(errs, fmt.Errorf("DB_PATH directory: %w", err))
+	} else if !info.IsDir() {
+		errs = append(errs, fmt.Errorf("DB_PATH directory %q is not a directory", filepath.Dir(c.DBPath)))
+	}
+
+	if _, _, err := net.SplitHostPort(c.AuthGRPCAddr); err != nil {
+		errs = append(errs, fmt.Errorf("AUTH_GRPC_ADDR %q: %w", c.AuthGRPCAddr, err))
+	}
+
+	if c.QueryTimeout <= 0 || c.QueryTimeout > time.Minute {
+		errs = append(errs, fmt.Errorf("DB_QUERY_TIMEOUT %s: must be positive and at most 1m", c.QueryTimeout))
+	}
+
+	switch c.Search.Backend {
+	case search.BackendFTS5, search.BackendBleve, search.BackendNone:
+	default:
+		errs = append(errs, fmt.Errorf("SEARCH_BACKEND %q: expected %s, %s or %s",
+			c.Search.Backend, search.BackendFTS5, search.BackendBleve, search.BackendNone))
 	}
 
-	// Формируем URL для миграций
-	migrationsURL := "file://" + filepath.ToSlash(absPath)
+	if c.Uploads.PublicURL != "" {
+		if u, err := url.Parse(c.Uploads.PublicURL); err != nil || u.Scheme == "" || u.Host == "" {
+			errs = append(errs, fmt.Errorf("UPLOADS_PUBLIC_URL %q: expected an absolute URL", c.Uploads.PublicURL))
+		}
+	}
 
-	// Создаем экземпляр мигратора
-	m, err := migrate.NewWithDatabaseInstance(
-		migrationsURL,
-		"sqlite3",
-		driver)
+	if err := c.TLS.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := c.GRPC.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
+
+// securityHeaders заголовки безопасности для окружения с учетом переопределений
+func securityHeaders(cfg *Config) secheaders.Config {
+	headers := secheaders.ForEnv(cfg.Env)
+	if cfg.CSP != "" {
+		headers.ContentSecurityPolicy = cfg.CSP
+	}
+	return headers
+}
+
+// chatCleanupJob удаляет сообщения чата старше срока хранения из runtime настроек
+func chatCleanupJob(chatUC *chat.ChatUseCase, runtimeCfg *config.RuntimeWatcher) scheduler.JobFunc {
+	return func(ctx context.Context) error {
+		retention := time.Duration(runtimeCfg.Current().ChatRetention)
+		if retention <= 0 {
+			return nil
+		}
+		return chatUC.CleanOldMessages(ctx, retention)
+	}
+}
+
+func runForumMigrations(db *sql.DB, log *logger.Logger) (*migrations.Migrator, error) {
+	log.Info("Applying forum service migrations")
+
+	migrator, err := migrations.NewMigrator(db)
 	if err != nil {
-		return fmt.Errorf("failed to create migration instance: %w", err)
+		return nil, err
 	}
 
 	// Применяем миграции
-	if err := m.Up(); err != nil && err != migrate.ErrNoChange {
-		return fmt.Errorf("failed to apply forum migrations: %w", err)
+	if err := migrator.Up(); err != nil {
+		return nil, fmt.Errorf("failed to apply forum migrations: %w", err)
 	}
 
 	log.Info("Forum service migrations applied successfully")
-	return nil
+	return migrator, nil
 }
 
-func startHTTPServer(server *http.Server, port int, log *logger.Logger) {
-	log.Info("Starting HTTP server", logger.Int("port", port))
-	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-		log.Fatal("HTTP server error", logger.Error(err))
+func serveHTTP(server *http.Server, port int, log *logger.Logger) error {
+	log.Info("Starting HTTP server", logger.Int("port", port), logger.Bool("tls", server.TLSConfig != nil))
+
+	var err error
+	if server.TLSConfig != nil {
+		// Сертификаты уже загружены в TLSConfig
+		err = server.ListenAndServeTLS("", "")
+	} else {
+		err = server.ListenAndServe()
+	}
+	if err != nil && err != http.ErrServerClosed {
+		return err
 	}
+	return nil
 }
 
-func startGRPCServer(server *grpc.Server, port int, log *logger.Logger) {
+func serveGRPC(server *grpc.Server, port int, log *logger.Logger) error {
 	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
 	if err != nil {
-		log.Fatal("Failed to listen gRPC", logger.Error(err))
+		return fmt.Errorf("failed to listen gRPC: %w", err)
 	}
 
 	log.Info("Starting gRPC server", logger.Int("port", port))
-	if err := server.Serve(listener); err != nil {
-		log.Fatal("gRPC server error", logger.Error(err))
-	}
+	return server.Serve(listener)
 }
 
-func waitForShutdownSignal(httpServer *http.Server, grpcServer *grpc.Server, log *logger.Logger) {
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
-
-	log.Info("Shutting down servers...")
-
-	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer shutdownCancel()
+// stopGRPC дожидается завершения активных RPC, а по истечении дедлайна обрывает их
+func stopGRPC(ctx context.Context, server *grpc.Server) error {
+	done := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(done)
+	}()
 
-	if err := httpServer.Shutdown(shutdownCtx); err != nil {
-		log.Error("HTTP server shutdown error", logger.Error(err))
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		server.Stop()
+		return ctx.Err()
 	}
-
-	grpcServer.GracefulStop()
-	log.Info("Servers stopped gracefully")
 }
 
 func NewRouter(
 	postHandlers *handlers.PostHandlers,
 	commentHandlers *handlers.CommentHandlers,
 	chatHandlers *handlers.ChatHandlers,
-	jwtSecret string,
+	healthHandlers *handlers.HealthHandlers,
+	statsHandlers *handlers.StatsHandlers,
+	searchHandlers *handlers.SearchHandlers,
+	ipBanHandlers *handlers.IPBanHandlers,
+	moderationHandlers *handlers.ModerationHandlers,
+	categoryModeratorHandlers *handlers.CategoryModeratorHandlers,
+	auditHandlers *handlers.AuditHandlers,
+	attachmentHandlers *handlers.AttachmentHandlers,
+	profileHandlers *handlers.ProfileHandlers,
+	blockHandlers *handlers.BlockHandlers,
+	shareHandlers *handlers.ShareHandlers,
+	unreadHandlers *handlers.UnreadHandlers,
+	announcementHandlers *handlers.AnnouncementHandlers,
+	categoryHandlers *handlers.CategoryHandlers,
+	reportHandlers *handlers.ReportHandlers,
+	trendingHandlers *handlers.TrendingHandlers,
+	uploadsHandler http.Handler,
+	logLevelHandler http.Handler,
+	roles httpdelivery.RoleResolver,
+	tokens auth.TokenValidator,
+	cookieAuth bool,
+	mockAuth bool,
+	runtimeCfg *config.RuntimeWatcher,
+	tenants *tenant.Resolver,
+	trustedProxies []netip.Prefix,
+	bans *ipban.Checker,
+	auditLog *audit.Store,
+	log *logger.Logger,
 ) *chi.Mux {
-	return httpdelivery.NewRouter(postHandlers, commentHandlers, chatHandlers, jwtSecret)
+	return httpdelivery.NewRouter(postHandlers, commentHandlers, chatHandlers, healthHandlers, statsHandlers, searchHandlers, ipBanHandlers, moderationHandlers, categoryModeratorHandlers, auditHandlers, attachmentHandlers, profileHandlers, blockHandlers, shareHandlers, unreadHandlers, announcementHandlers, categoryHandlers, reportHandlers, trendingHandlers, uploadsHandler, logLevelHandler, roles, tokens, cookieAuth, mockAuth, runtimeCfg, tenants, trustedProxies, bans, auditLog, log)
 }
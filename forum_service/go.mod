@@ -5,14 +5,32 @@ go 1.24.2
 require (
 	github.com/go-chi/chi/v5 v5.2.1
 	github.com/google/uuid v1.6.0
+	github.com/kprf42/dolgova/pkg/audit v0.0.0-00010101000000-000000000000
+	github.com/kprf42/dolgova/pkg/csrf v0.0.0-00010101000000-000000000000
+	github.com/kprf42/dolgova/pkg/grpcerr v0.0.0-00010101000000-000000000000
+	github.com/kprf42/dolgova/pkg/i18n v0.0.0-00010101000000-000000000000
+	github.com/kprf42/dolgova/pkg/ipban v0.0.0-00010101000000-000000000000
+	github.com/kprf42/dolgova/pkg/lifecycle v0.0.0-00010101000000-000000000000
 	github.com/kprf42/dolgova/pkg/logger v0.0.0-00010101000000-000000000000
+	github.com/kprf42/dolgova/pkg/runtimecfg v0.0.0-00010101000000-000000000000
+	github.com/kprf42/dolgova/pkg/scheduler v0.0.0-00010101000000-000000000000
+	github.com/kprf42/dolgova/pkg/secheaders v0.0.0-00010101000000-000000000000
+	github.com/kprf42/dolgova/pkg/uploads v0.0.0-00010101000000-000000000000
 	github.com/kprf42/dolgova/proto v0.0.0-00010101000000-000000000000
+	github.com/prometheus/client_golang v1.22.0
 	google.golang.org/grpc v1.72.1
+	google.golang.org/protobuf v1.36.6
 )
 
 require (
+	github.com/beorn7/perks v1.0.1 // indirect
+	github.com/cespare/xxhash/v2 v2.3.0 // indirect
 	github.com/hashicorp/errwrap v1.1.0 // indirect
 	github.com/hashicorp/go-multierror v1.1.1 // indirect
+	github.com/munnerz/goautoneg v0.0.0-20191010083416-a7dc8b61c822 // indirect
+	github.com/prometheus/client_model v0.6.1 // indirect
+	github.com/prometheus/common v0.62.0 // indirect
+	github.com/prometheus/procfs v0.15.1 // indirect
 	go.uber.org/atomic v1.7.0 // indirect
 	go.uber.org/multierr v1.10.0 // indirect
 	go.uber.org/zap v1.27.0 // indirect
@@ -28,9 +46,28 @@ require (
 	golang.org/x/sys v0.33.0 // indirect
 	golang.org/x/text v0.25.0 // indirect
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20250218202821-56aae31c358a // indirect
-	google.golang.org/protobuf v1.36.6 // indirect
 )
 
 replace github.com/kprf42/dolgova/proto => ../proto
 
 replace github.com/kprf42/dolgova/pkg/logger => ../pkg/logger
+
+replace github.com/kprf42/dolgova/pkg/lifecycle => ../pkg/lifecycle
+
+replace github.com/kprf42/dolgova/pkg/csrf => ../pkg/csrf
+
+replace github.com/kprf42/dolgova/pkg/i18n => ../pkg/i18n
+
+replace github.com/kprf42/dolgova/pkg/scheduler => ../pkg/scheduler
+
+replace github.com/kprf42/dolgova/pkg/secheaders => ../pkg/secheaders
+
+replace github.com/kprf42/dolgova/pkg/ipban => ../pkg/ipban
+
+replace github.com/kprf42/dolgova/pkg/audit => ../pkg/audit
+
+replace github.com/kprf42/dolgova/pkg/grpcerr => ../pkg/grpcerr
+
+replace github.com/kprf42/dolgova/pkg/uploads => ../pkg/uploads
+
+replace github.com/kprf42/dolgova/pkg/runtimecfg => ../pkg/runtimecfg
@@ -0,0 +1,100 @@
+// Package auth проверяет токены, выпущенные auth сервисом, и хранит
+// аутентифицированного пользователя и адрес клиента в контексте запроса
+package auth
+
+import (
+	"context"
+	"errors"
+	"slices"
+)
+
+var (
+	// ErrInvalidToken токен отклонен auth сервисом
+	ErrInvalidToken = errors.New("invalid token")
+	// ErrUnavailable токен невозможно проверить: auth сервис недоступен
+	ErrUnavailable = errors.New("auth service unavailable")
+	// ErrSuspended аккаунт владельца токена приостановлен или заблокирован
+	ErrSuspended = errors.New("account suspended")
+)
+
+// Identity владелец проверенного токена
+type Identity struct {
+	UserID        string
+	ActingAdminID string   // Администратор, действующий от имени пользователя; пусто без имперсонации
+	Role          string   // Роль из токена; пусто у токенов, выпущенных без нее
+	Scopes        []string // Ограничения токена (гостевого); пусто - полный доступ владельца
+}
+
+// Scopes ограниченных токенов. Auth сервис выдает их гостям: такой токен разрешает
+// только перечисленное, а изменять данные с ним нельзя.
+const (
+	ScopeReadOnly = "read-only" // Чтение форума
+	ScopeChat     = "chat"      // Подключение к чату без права писать
+)
+
+// TokenValidator проверяет access токен и возвращает его владельца.
+// Секрет подписи известен только auth сервису, поэтому проверка выполняется через него.
+type TokenValidator interface {
+	ValidateToken(ctx context.Context, token string) (Identity, error)
+}
+
+type userIDKey struct{}
+
+// WithUserID возвращает контекст с ID аутентифицированного пользователя
+func WithUserID(ctx context.Context, userID string) context.Context {
+	return context.WithValue(ctx, userIDKey{}, userID)
+}
+
+// UserIDFromContext возвращает ID аутентифицированного пользователя, если он есть
+func UserIDFromContext(ctx context.Context) (string, bool) {
+	userID, ok := ctx.Value(userIDKey{}).(string)
+	return userID, ok && userID != ""
+}
+
+type roleKey struct{}
+
+// WithRole возвращает контекст с ролью пользователя из токена
+func WithRole(ctx context.Context, role string) context.Context {
+	return context.WithValue(ctx, roleKey{}, role)
+}
+
+// RoleFromContext возвращает роль пользователя из токена, если она есть
+func RoleFromContext(ctx context.Context) (string, bool) {
+	role, ok := ctx.Value(roleKey{}).(string)
+	return role, ok && role != ""
+}
+
+type scopesKey struct{}
+
+// WithScopes возвращает контекст с ограничениями токена; пустой список не ограничивает запрос
+func WithScopes(ctx context.Context, scopes []string) context.Context {
+	if len(scopes) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, scopesKey{}, scopes)
+}
+
+// Scoped сообщает, ограничен ли токен запроса списком scopes
+func Scoped(ctx context.Context) bool {
+	scopes, _ := ctx.Value(scopesKey{}).([]string)
+	return len(scopes) > 0
+}
+
+// Allows сообщает, разрешает ли токен запроса scope. Токен без ограничений разрешает все.
+func Allows(ctx context.Context, scope string) bool {
+	scopes, _ := ctx.Value(scopesKey{}).([]string)
+	return len(scopes) == 0 || slices.Contains(scopes, scope)
+}
+
+type clientIPKey struct{}
+
+// WithClientIP возвращает контекст с адресом клиента запроса
+func WithClientIP(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, clientIPKey{}, ip)
+}
+
+// ClientIPFromContext возвращает адрес клиента запроса, если он известен
+func ClientIPFromContext(ctx context.Context) (string, bool) {
+	ip, ok := ctx.Value(clientIPKey{}).(string)
+	return ip, ok && ip != ""
+}
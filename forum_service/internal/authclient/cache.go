@@ -0,0 +1,152 @@
+package authclient
+
+import (
+	"crypto/sha256"
+	"sync"
+	"time"
+
+	"github.com/kprf42/dolgova/forum_service/internal/auth"
+	"github.com/kprf42/dolgova/forum_service/internal/entity"
+)
+
+// tokenCache хранит результаты успешной проверки токенов. Токены хранятся в виде хеша.
+type tokenCache struct {
+	ttl     time.Duration
+	maxSize int
+
+	mu      sync.Mutex
+	entries map[[sha256.Size]byte]cacheEntry
+}
+
+type cacheEntry struct {
+	identity    auth.Identity
+	validatedAt time.Time
+}
+
+func newTokenCache(ttl time.Duration, maxSize int) *tokenCache {
+	return &tokenCache{
+		ttl:     ttl,
+		maxSize: maxSize,
+		entries: make(map[[sha256.Size]byte]cacheEntry),
+	}
+}
+
+// get возвращает пользователя, если токен был успешно проверен не раньше maxAge назад
+func (c *tokenCache) get(token string, maxAge time.Duration) (auth.Identity, bool) {
+	if maxAge <= 0 {
+		return auth.Identity{}, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[sha256.Sum256([]byte(token))]
+	if !ok || time.Since(entry.validatedAt) > maxAge {
+		return auth.Identity{}, false
+	}
+	return entry.identity, true
+}
+
+func (c *tokenCache) put(token string, identity auth.Identity) {
+	if c.ttl <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.entries) >= c.maxSize {
+		c.evictExpired()
+	}
+	// Если место так и не освободилось, начинаем заново: кеш лишь ускоряет проверку
+	if len(c.entries) >= c.maxSize {
+		c.entries = make(map[[sha256.Size]byte]cacheEntry)
+	}
+
+	c.entries[sha256.Sum256([]byte(token))] = cacheEntry{
+		identity:    identity,
+		validatedAt: time.Now(),
+	}
+}
+
+func (c *tokenCache) remove(token string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, sha256.Sum256([]byte(token)))
+}
+
+func (c *tokenCache) evictExpired() {
+	for key, entry := range c.entries {
+		if time.Since(entry.validatedAt) > c.ttl {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// authorCache хранит публичные данные авторов, чтобы не запрашивать их для каждой страницы
+type authorCache struct {
+	ttl     time.Duration
+	maxSize int
+
+	mu      sync.Mutex
+	entries map[string]authorEntry
+}
+
+type authorEntry struct {
+	author    *entity.Author
+	fetchedAt time.Time
+}
+
+func newAuthorCache(ttl time.Duration, maxSize int) *authorCache {
+	return &authorCache{
+		ttl:     ttl,
+		maxSize: maxSize,
+		entries: make(map[string]authorEntry),
+	}
+}
+
+// get возвращает найденных в кеше авторов и ID, которые нужно запросить. Повторы и пустые ID отбрасываются.
+func (c *authorCache) get(ids []string) (map[string]*entity.Author, []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	authors := make(map[string]*entity.Author, len(ids))
+	var missing []string
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		entry, ok := c.entries[id]
+		if ok && time.Since(entry.fetchedAt) <= c.ttl {
+			authors[id] = entry.author
+			continue
+		}
+		missing = append(missing, id)
+	}
+	return authors, missing
+}
+
+func (c *authorCache) put(author *entity.Author) {
+	if c.ttl <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.entries) >= c.maxSize {
+		for id, entry := range c.entries {
+			if time.Since(entry.fetchedAt) > c.ttl {
+				delete(c.entries, id)
+			}
+		}
+	}
+	if len(c.entries) >= c.maxSize {
+		c.entries = make(map[string]authorEntry)
+	}
+
+	c.entries[author.ID] = authorEntry{author: author, fetchedAt: time.Now()}
+}
@@ -0,0 +1,286 @@
+// Package authclient gRPC клиент auth сервиса с таймаутами, повторами с экспоненциальной
+// задержкой, автоматическим выключателем и откатом на ранее полученные результаты
+package authclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/kprf42/dolgova/forum_service/internal/auth"
+	"github.com/kprf42/dolgova/forum_service/internal/entity"
+	"github.com/kprf42/dolgova/pkg/grpcerr"
+	"github.com/kprf42/dolgova/pkg/logger"
+	authpb "github.com/kprf42/dolgova/proto/auth/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	// ErrInvalidToken auth сервис отклонил токен
+	ErrInvalidToken = auth.ErrInvalidToken
+	// ErrUnavailable auth сервис недоступен и сохраненного результата нет
+	ErrUnavailable = auth.ErrUnavailable
+	// ErrSuspended аккаунт владельца токена приостановлен или заблокирован
+	ErrSuspended = auth.ErrSuspended
+)
+
+// reasonAccountSuspended причина отказа auth сервиса для приостановленного аккаунта
+const reasonAccountSuspended = "ACCOUNT_SUSPENDED"
+
+// maxUsersPerRequest ограничение auth сервиса на число ID в одном вызове GetUsers
+const maxUsersPerRequest = 100
+
+// Config параметры клиента
+type Config struct {
+	Addr             string        // Адрес gRPC сервера auth сервиса
+	Timeout          time.Duration // Таймаут одной попытки
+	MaxRetries       int           // Повторы после первой попытки (только для идемпотентных вызовов)
+	BackoffBase      time.Duration // Задержка перед первым повтором, далее удваивается
+	BackoffMax       time.Duration // Максимальная задержка между повторами
+	BreakerThreshold int           // Ошибок подряд до размыкания выключателя
+	BreakerCooldown  time.Duration // Пауза перед пробным вызовом
+	CacheTTL         time.Duration // Сколько доверять успешной проверке токена без обращения к auth
+	FallbackTTL      time.Duration // Сколько использовать последний успешный результат при недоступности auth
+	CacheSize        int           // Максимум токенов в кеше
+	AuthorTTL        time.Duration // Сколько хранить данные авторов (имя, аватар)
+	AuthorCacheSize  int           // Максимум авторов в кеше
+}
+
+// DefaultConfig настройки по умолчанию для адреса addr
+func DefaultConfig(addr string) Config {
+	return Config{
+		Addr:             addr,
+		Timeout:          time.Second,
+		MaxRetries:       2,
+		BackoffBase:      100 * time.Millisecond,
+		BackoffMax:       time.Second,
+		BreakerThreshold: 5,
+		BreakerCooldown:  10 * time.Second,
+		CacheTTL:         30 * time.Second,
+		FallbackTTL:      5 * time.Minute,
+		CacheSize:        10000,
+		AuthorTTL:        time.Minute,
+		AuthorCacheSize:  10000,
+	}
+}
+
+// Client клиент auth сервиса
+type Client struct {
+	cfg     Config
+	conn    *grpc.ClientConn
+	api     authpb.AuthServiceClient
+	breaker *Breaker
+	cache   *tokenCache
+	authors *authorCache
+	log     *logger.Logger
+}
+
+// New создает клиент. Без opts соединение устанавливается без TLS.
+// Подключение ленивое: сервис может быть недоступен в момент запуска.
+func New(cfg Config, log *logger.Logger, opts ...grpc.DialOption) (*Client, error) {
+	if len(opts) == 0 {
+		opts = []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+	}
+
+	conn, err := grpc.NewClient(cfg.Addr, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create auth client: %w", err)
+	}
+
+	c := &Client{
+		cfg:     cfg,
+		conn:    conn,
+		api:     authpb.NewAuthServiceClient(conn),
+		cache:   newTokenCache(max(cfg.CacheTTL, cfg.FallbackTTL), cfg.CacheSize),
+		authors: newAuthorCache(cfg.AuthorTTL, cfg.AuthorCacheSize),
+		log:     log,
+	}
+	c.breaker = NewBreaker(cfg.BreakerThreshold, cfg.BreakerCooldown, func(from, to BreakerState) {
+		log.Warn("Auth service circuit breaker state changed",
+			logger.String("from", from.String()),
+			logger.String("to", to.String()))
+	})
+
+	return c, nil
+}
+
+// Close закрывает соединение
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// ValidateToken проверяет токен в auth сервисе и возвращает его владельца.
+// Успешная проверка кешируется на CacheTTL: отзыв токена вступает в силу не позже этого срока.
+// Если auth сервис недоступен, используется результат недавней успешной проверки того же токена.
+func (c *Client) ValidateToken(ctx context.Context, token string) (auth.Identity, error) {
+	if identity, ok := c.cache.get(token, c.cfg.CacheTTL); ok {
+		return identity, nil
+	}
+
+	var resp *authpb.ValidateTokenResponse
+	err := c.call(ctx, true, func(ctx context.Context) error {
+		var err error
+		resp, err = c.api.ValidateToken(ctx, &authpb.ValidateTokenRequest{Token: token})
+		return err
+	})
+
+	switch {
+	case err == nil && resp.GetValid() && resp.GetUserId() != "":
+		identity := auth.Identity{
+			UserID:        resp.GetUserId(),
+			ActingAdminID: resp.GetActingAdminId(),
+			Role:          resp.GetRole(),
+			Scopes:        resp.GetScopes(),
+		}
+		c.cache.put(token, identity)
+		return identity, nil
+	case grpcerr.Reason(err) == reasonAccountSuspended:
+		c.cache.remove(token)
+		return auth.Identity{}, ErrSuspended
+	case err == nil || isRejection(err):
+		c.cache.remove(token)
+		return auth.Identity{}, ErrInvalidToken
+	}
+
+	if identity, ok := c.cache.get(token, c.cfg.FallbackTTL); ok {
+		c.log.Warn("Auth service unavailable, using cached token validation",
+			logger.Error(err))
+		return identity, nil
+	}
+	return auth.Identity{}, fmt.Errorf("%w: %v", ErrUnavailable, err)
+}
+
+// GetUsers возвращает публичные данные пользователей для отображения авторов.
+// Данные кешируются на AuthorTTL; пользователей, которых нет в auth сервисе, в результате нет.
+func (c *Client) GetUsers(ctx context.Context, ids []string) (map[string]*entity.Author, error) {
+	authors, missing := c.authors.get(ids)
+	for len(missing) > 0 {
+		batch := missing[:min(len(missing), maxUsersPerRequest)]
+		missing = missing[len(batch):]
+
+		var resp *authpb.GetUsersResponse
+		err := c.call(ctx, true, func(ctx context.Context) error {
+			var err error
+			resp, err = c.api.GetUsers(ctx, &authpb.GetUsersRequest{Ids: batch})
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get users: %w", err)
+		}
+
+		for _, user := range resp.GetUsers() {
+			author := &entity.Author{
+				ID:          user.GetId(),
+				Username:    user.GetUsername(),
+				DisplayName: user.GetDisplayName(),
+				AvatarURL:   user.GetAvatarUrl(),
+			}
+			authors[author.ID] = author
+			c.authors.put(author)
+		}
+	}
+	return authors, nil
+}
+
+// Ping проверяет доступность auth сервиса стандартным gRPC health-check в обход выключателя.
+// Сервер без health-check (Unimplemented) считается доступным: он ответил на запрос.
+func (c *Client) Ping(ctx context.Context) error {
+	resp, err := healthpb.NewHealthClient(c.conn).Check(ctx, &healthpb.HealthCheckRequest{})
+	switch {
+	case status.Code(err) == codes.Unimplemented:
+		return nil
+	case err != nil:
+		return err
+	case resp.GetStatus() != healthpb.HealthCheckResponse_SERVING:
+		return fmt.Errorf("auth service is %s", resp.GetStatus())
+	}
+	return nil
+}
+
+// call выполняет вызов с таймаутом на попытку через выключатель.
+// Повторы выполняются только для идемпотентных вызовов и только при временных ошибках.
+func (c *Client) call(ctx context.Context, idempotent bool, fn func(ctx context.Context) error) error {
+	attempts := 1
+	if idempotent {
+		attempts += c.cfg.MaxRetries
+	}
+
+	var err error
+	for attempt := 0; attempt < attempts; attempt++ {
+		if attempt > 0 {
+			if err := sleep(ctx, c.backoff(attempt)); err != nil {
+				return err
+			}
+		}
+
+		if !c.breaker.Allow() {
+			return errors.New("circuit breaker is open")
+		}
+
+		callCtx, cancel := context.WithTimeout(ctx, c.cfg.Timeout)
+		err = fn(callCtx)
+		cancel()
+
+		// Отказ в доступе - корректный ответ сервиса, а не сбой
+		if err == nil || isRejection(err) {
+			c.breaker.Success()
+			return err
+		}
+
+		c.breaker.Failure()
+		if !retryable(err) || ctx.Err() != nil {
+			return err
+		}
+	}
+
+	return err
+}
+
+// backoff экспоненциальная задержка с полным джиттером
+func (c *Client) backoff(attempt int) time.Duration {
+	delay := c.cfg.BackoffBase << (attempt - 1)
+	if delay <= 0 || delay > c.cfg.BackoffMax {
+		delay = c.cfg.BackoffMax
+	}
+	if delay <= 0 {
+		return 0
+	}
+	return rand.N(delay) + 1
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// isRejection ответ сервиса о невалидных данных
+func isRejection(err error) bool {
+	switch status.Code(err) {
+	case codes.Unauthenticated, codes.InvalidArgument, codes.PermissionDenied:
+		return true
+	default:
+		return false
+	}
+}
+
+func retryable(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Aborted:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,186 @@
+// Package backup выгружает и восстанавливает данные общей БД в переносимом формате (JSON или CSV)
+package backup
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// FormatVersion версия формата дампа, увеличивается при несовместимых изменениях:
+// 2 - сообщества (tenant_id), 3 - статус модерации постов и комментариев, 4 - профиль и статус пользователей,
+// 5 - категории
+const FormatVersion = 5
+
+// Table описание выгружаемой таблицы. Колонки перечислены явно,
+// чтобы дамп не зависел от порядка колонок в конкретной СУБД.
+type Table struct {
+	Name    string
+	Columns []string
+	// Defaults значения для колонок, отсутствующих в дампах предыдущих версий
+	Defaults map[string]string
+	// OrderBy порядок строк в дампе, в нем же они восстанавливаются; пусто - по id
+	OrderBy string
+}
+
+// Tables выгружаемые таблицы в порядке, безопасном для восстановления (сначала родительские)
+var Tables = []Table{
+	{Name: "users", Columns: []string{"id", "username", "email", "password", "role", "created_at", "updated_at",
+		"display_name", "bio", "avatar_url", "status", "status_until", "status_reason"}, Defaults: userDefaults},
+	// Родительские категории восстанавливаются раньше вложенных
+	{Name: "categories", Columns: []string{"tenant_id", "id", "name", "description", "position", "parent_id", "depth", "created_at", "updated_at"},
+		OrderBy: "depth, tenant_id, id"},
+	{Name: "posts", Columns: []string{"id", "title", "content", "author_id", "category_id", "is_pinned", "created_at", "tenant_id", "status"}, Defaults: contentDefaults},
+	{Name: "comments", Columns: []string{"id", "content", "post_id", "author_id", "created_at", "tenant_id", "status"}, Defaults: contentDefaults},
+	{Name: "chat_messages", Columns: []string{"id", "user_id", "text", "created_at", "tenant_id"}, Defaults: tenantDefault},
+}
+
+// tenantDefault дампы версии 1 сделаны до появления сообществ
+var tenantDefault = map[string]string{"tenant_id": "default"}
+
+// userDefaults в дампах до версии 4 нет профиля и статуса пользователя
+var userDefaults = map[string]string{"display_name": "", "bio": "", "avatar_url": "", "status": "active", "status_reason": ""}
+
+// contentDefaults в дампах до версии 3 статуса нет, такой контент восстанавливался опубликованным
+var contentDefaults = map[string]string{"tenant_id": "default", "status": "published"}
+
+// Row строка таблицы: имя колонки -> значение (nil для NULL).
+// Значения хранятся строками, чтобы дамп можно было загрузить в другую СУБД.
+type Row map[string]*string
+
+// Dump полный снимок данных
+type Dump struct {
+	Version   int              `json:"version"`
+	CreatedAt time.Time        `json:"created_at"`
+	Tables    map[string][]Row `json:"tables"`
+}
+
+// Export читает все таблицы из БД
+func Export(ctx context.Context, db *sql.DB) (*Dump, error) {
+	dump := &Dump{
+		Version:   FormatVersion,
+		CreatedAt: time.Now().UTC(),
+		Tables:    make(map[string][]Row, len(Tables)),
+	}
+
+	for _, table := range Tables {
+		rows, err := exportTable(ctx, db, table)
+		if err != nil {
+			return nil, fmt.Errorf("failed to export %s: %w", table.Name, err)
+		}
+		dump.Tables[table.Name] = rows
+	}
+
+	return dump, nil
+}
+
+func exportTable(ctx context.Context, db *sql.DB, table Table) ([]Row, error) {
+	orderBy := table.OrderBy
+	if orderBy == "" {
+		orderBy = "id"
+	}
+	query := fmt.Sprintf("SELECT %s FROM %s ORDER BY %s", strings.Join(table.Columns, ", "), table.Name, orderBy)
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []Row{}
+	for rows.Next() {
+		values := make([]sql.NullString, len(table.Columns))
+		dest := make([]interface{}, len(values))
+		for i := range values {
+			dest[i] = &values[i]
+		}
+
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+
+		row := make(Row, len(table.Columns))
+		for i, column := range table.Columns {
+			if values[i].Valid {
+				value := values[i].String
+				row[column] = &value
+			} else {
+				row[column] = nil
+			}
+		}
+		result = append(result, row)
+	}
+
+	return result, rows.Err()
+}
+
+// ImportResult количество восстановленных и пропущенных (уже существующих) строк по таблицам
+type ImportResult struct {
+	Inserted map[string]int
+	Skipped  map[string]int
+}
+
+// Import восстанавливает данные из дампа в одной транзакции.
+// Строки с уже существующим первичным ключом пропускаются, поэтому импорт можно повторять.
+func Import(ctx context.Context, db *sql.DB, dump *Dump) (*ImportResult, error) {
+	if dump.Version < 1 || dump.Version > FormatVersion {
+		return nil, fmt.Errorf("unsupported dump version %d", dump.Version)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	result := &ImportResult{
+		Inserted: make(map[string]int),
+		Skipped:  make(map[string]int),
+	}
+
+	for _, table := range Tables {
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(table.Columns)), ", ")
+		query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s) ON CONFLICT DO NOTHING",
+			table.Name, strings.Join(table.Columns, ", "), placeholders)
+
+		stmt, err := tx.PrepareContext(ctx, query)
+		if err != nil {
+			return nil, fmt.Errorf("failed to prepare insert into %s: %w", table.Name, err)
+		}
+
+		for _, row := range dump.Tables[table.Name] {
+			args := make([]interface{}, len(table.Columns))
+			for i, column := range table.Columns {
+				value, ok := row[column]
+				if !ok {
+					if def, hasDefault := table.Defaults[column]; hasDefault {
+						value = &def
+					}
+				}
+				if value != nil {
+					args[i] = *value
+				}
+			}
+
+			res, err := stmt.ExecContext(ctx, args...)
+			if err != nil {
+				stmt.Close()
+				return nil, fmt.Errorf("failed to insert into %s: %w", table.Name, err)
+			}
+
+			if affected, _ := res.RowsAffected(); affected > 0 {
+				result.Inserted[table.Name]++
+			} else {
+				result.Skipped[table.Name]++
+			}
+		}
+		stmt.Close()
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,172 @@
+package backup
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/kprf42/dolgova/forum_service/migrations"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// usersTable таблица users в том виде, в каком ее создают миграции auth сервиса
+const usersTable = `CREATE TABLE users (
+	id TEXT PRIMARY KEY,
+	username TEXT NOT NULL UNIQUE,
+	email TEXT NOT NULL UNIQUE,
+	password TEXT NOT NULL,
+	role TEXT NOT NULL DEFAULT 'user',
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	display_name TEXT NOT NULL DEFAULT '',
+	bio TEXT NOT NULL DEFAULT '',
+	avatar_url TEXT NOT NULL DEFAULT '',
+	status TEXT NOT NULL DEFAULT 'active',
+	status_until TIMESTAMP,
+	status_reason TEXT NOT NULL DEFAULT ''
+)`
+
+// Восстановление не должно публиковать контент, ожидающий модерации или отклоненный,
+// и снимать баны с пользователей
+func TestExportImportKeepsStatus(t *testing.T) {
+	ctx := context.Background()
+	src := newTestDB(t)
+	mustExec(t, src,
+		`INSERT INTO users (id, username, email, password) VALUES ('u1', 'alice', 'alice@example.com', 'hash')`,
+		`INSERT INTO users (id, username, email, password, display_name, bio, status, status_until, status_reason) VALUES
+		 ('u2', 'bob', 'bob@example.com', 'hash', 'Bob', 'about', 'suspended', '2030-01-01T00:00:00Z', 'spam')`,
+		`INSERT INTO posts (id, title, content, author_id, category_id, created_at, status) VALUES
+		 ('p1', 'Published', 'text', 'u1', '1', '2026-01-01T00:00:00Z', 'published'),
+		 ('p2', 'Pending', 'text', 'u1', '1', '2026-01-01T00:00:00Z', 'pending')`,
+		`INSERT INTO comments (id, content, post_id, author_id, created_at, status) VALUES
+		 ('c1', 'Rejected', 'p1', 'u1', '2026-01-01T00:00:00Z', 'rejected')`)
+
+	dump, err := Export(ctx, src)
+	if err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	dst := newTestDB(t)
+	if _, err := Import(ctx, dst, dump); err != nil {
+		t.Fatalf("import: %v", err)
+	}
+
+	want := []struct{ table, id, status string }{
+		{"posts", "p1", "published"},
+		{"posts", "p2", "pending"},
+		{"comments", "c1", "rejected"},
+		{"users", "u1", "active"},
+		{"users", "u2", "suspended"},
+	}
+	for _, w := range want {
+		if got := queryString(t, dst, "SELECT status FROM "+w.table+" WHERE id = ?", w.id); got != w.status {
+			t.Errorf("%s %s status %q, want %q", w.table, w.id, got, w.status)
+		}
+	}
+
+	got := queryString(t, dst, "SELECT display_name || '|' || bio || '|' || status_until || '|' || status_reason FROM users WHERE id = 'u2'")
+	if want := "Bob|about|2030-01-01T00:00:00Z|spam"; got != want {
+		t.Errorf("user u2 %q, want %q", got, want)
+	}
+}
+
+func TestExportImportCategories(t *testing.T) {
+	ctx := context.Background()
+	src := newTestDB(t)
+	mustExec(t, src,
+		`INSERT INTO categories (tenant_id, id, name, parent_id, depth) VALUES
+		 ('default', 'a-child', 'Child', 'z-root', 1),
+		 ('default', 'z-root', 'Root', NULL, 0)`)
+
+	dump, err := Export(ctx, src)
+	if err != nil {
+		t.Fatalf("export: %v", err)
+	}
+
+	// Родитель выгружается раньше вложенной категории, хотя его id больше
+	var order []string
+	for _, row := range dump.Tables["categories"] {
+		order = append(order, *row["id"])
+	}
+	if root, child := slices.Index(order, "z-root"), slices.Index(order, "a-child"); root < 0 || child < root {
+		t.Fatalf("categories order %v: parent must come before child", order)
+	}
+
+	dst := newTestDB(t)
+	if _, err := Import(ctx, dst, dump); err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	got := queryString(t, dst, "SELECT name || '|' || parent_id || '|' || depth FROM categories WHERE id = 'a-child'")
+	if want := "Child|z-root|1"; got != want {
+		t.Fatalf("category %q, want %q", got, want)
+	}
+}
+
+func TestImportOlderDumpDefaults(t *testing.T) {
+	ctx := context.Background()
+	str := func(s string) *string { return &s }
+
+	dump := &Dump{
+		Version: 2,
+		Tables: map[string][]Row{
+			"users": {{"id": str("u1"), "username": str("alice"), "email": str("alice@example.com"),
+				"password": str("hash"), "role": str("user"), "created_at": nil, "updated_at": nil}},
+			"posts": {{"id": str("p1"), "title": str("Post"), "content": str("text"), "author_id": str("u1"),
+				"category_id": str("1"), "is_pinned": str("0"), "created_at": str("2026-01-01T00:00:00Z"), "tenant_id": str("default")}},
+		},
+	}
+
+	db := newTestDB(t)
+	if _, err := Import(ctx, db, dump); err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if got := queryString(t, db, "SELECT status FROM posts WHERE id = 'p1'"); got != "published" {
+		t.Fatalf("post status %q, want published", got)
+	}
+	if got := queryString(t, db, "SELECT status FROM users WHERE id = 'u1'"); got != "active" {
+		t.Fatalf("user status %q, want active", got)
+	}
+
+	dump.Version = FormatVersion + 1
+	if _, err := Import(ctx, db, dump); err == nil {
+		t.Fatal("dump of newer version accepted")
+	}
+}
+
+func newTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "forum.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	mustExec(t, db, usersTable)
+	migrator, err := migrations.NewMigrator(db)
+	if err != nil {
+		t.Fatalf("create migrator: %v", err)
+	}
+	if err := migrator.Up(); err != nil {
+		t.Fatalf("apply migrations: %v", err)
+	}
+	return db
+}
+
+func mustExec(t *testing.T, db *sql.DB, queries ...string) {
+	t.Helper()
+	for _, query := range queries {
+		if _, err := db.Exec(query); err != nil {
+			t.Fatalf("exec %q: %v", query, err)
+		}
+	}
+}
+
+func queryString(t *testing.T, db *sql.DB, query string, args ...any) string {
+	t.Helper()
+	var value string
+	if err := db.QueryRow(query, args...).Scan(&value); err != nil {
+		t.Fatalf("query %q: %v", query, err)
+	}
+	return value
+}
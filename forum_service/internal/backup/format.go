@@ -0,0 +1,140 @@
+package backup
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// csvNull обозначение NULL в CSV (как в COPY у PostgreSQL)
+const csvNull = `\N`
+
+// WriteJSON записывает дамп одним JSON документом
+func WriteJSON(w io.Writer, dump *Dump) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(dump)
+}
+
+// ReadJSON читает дамп из JSON документа
+func ReadJSON(r io.Reader) (*Dump, error) {
+	var dump Dump
+	if err := json.NewDecoder(r).Decode(&dump); err != nil {
+		return nil, fmt.Errorf("failed to decode dump: %w", err)
+	}
+	return &dump, nil
+}
+
+// WriteCSV записывает каждую таблицу в отдельный файл <dir>/<table>.csv с заголовком
+func WriteCSV(dir string, dump *Dump) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	for _, table := range Tables {
+		if err := writeCSVTable(filepath.Join(dir, table.Name+".csv"), table, dump.Tables[table.Name]); err != nil {
+			return fmt.Errorf("failed to write %s: %w", table.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func writeCSVTable(path string, table Table, rows []Row) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	if err := writer.Write(table.Columns); err != nil {
+		return err
+	}
+
+	record := make([]string, len(table.Columns))
+	for _, row := range rows {
+		for i, column := range table.Columns {
+			if value := row[column]; value != nil {
+				record[i] = *value
+			} else {
+				record[i] = csvNull
+			}
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+// ReadCSV читает дамп из каталога, созданного WriteCSV. Отсутствующие файлы считаются пустыми таблицами.
+func ReadCSV(dir string) (*Dump, error) {
+	dump := &Dump{
+		Version:   FormatVersion,
+		CreatedAt: time.Now().UTC(),
+		Tables:    make(map[string][]Row, len(Tables)),
+	}
+
+	for _, table := range Tables {
+		rows, err := readCSVTable(filepath.Join(dir, table.Name+".csv"))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", table.Name, err)
+		}
+		dump.Tables[table.Name] = rows
+	}
+
+	return dump, nil
+}
+
+func readCSVTable(path string) ([]Row, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []Row
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		row := make(Row, len(header))
+		for i, column := range header {
+			if record[i] == csvNull {
+				row[column] = nil
+				continue
+			}
+			value := record[i]
+			row[column] = &value
+		}
+		rows = append(rows, row)
+	}
+}
@@ -8,10 +8,9 @@ import (
 )
 
 type Config struct {
-	DBPath    string
-	HTTPPort  int
-	GRPCPort  int
-	JWTSecret string
+	DBPath   string
+	HTTPPort int
+	GRPCPort int
 }
 
 func Load() (*Config, error) {
@@ -30,10 +29,9 @@ func Load() (*Config, error) {
 	}
 
 	return &Config{
-		DBPath:    os.Getenv("DB_PATH"),
-		HTTPPort:  httpPort,
-		GRPCPort:  grpcPort,
-		JWTSecret: os.Getenv("JWT_SECRET"),
+		DBPath:   os.Getenv("DB_PATH"),
+		HTTPPort: httpPort,
+		GRPCPort: grpcPort,
 	}, nil
 }
 
@@ -0,0 +1,136 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/keepalive"
+)
+
+// GRPC ограничения gRPC сервера
+type GRPC struct {
+	MaxRecvMsgSize       int           // Максимальный размер входящего сообщения, байты
+	MaxSendMsgSize       int           // Максимальный размер исходящего сообщения, байты
+	MaxConcurrentStreams uint32        // Одновременных вызовов на одно соединение
+	KeepaliveTime        time.Duration // Пинг клиента после простоя соединения
+	KeepaliveTimeout     time.Duration // Ожидание ответа на пинг, после него соединение закрывается
+	KeepaliveMinTime     time.Duration // Минимальный интервал пингов от клиента, чаще - разрыв соединения
+	DefaultDeadline      time.Duration // Дедлайн unary вызова, если клиент не передал свой
+}
+
+// DefaultGRPC значения по умолчанию
+func DefaultGRPC() GRPC {
+	return GRPC{
+		MaxRecvMsgSize:       4 << 20,
+		MaxSendMsgSize:       16 << 20,
+		MaxConcurrentStreams: 100,
+		KeepaliveTime:        2 * time.Minute,
+		KeepaliveTimeout:     20 * time.Second,
+		KeepaliveMinTime:     30 * time.Second,
+		DefaultDeadline:      30 * time.Second,
+	}
+}
+
+// LoadGRPC читает ограничения из переменных окружения GRPC_*, незаданные берутся по умолчанию.
+// Ошибки разбора возвращаются вместе с конфигурацией, чтобы их можно было показать вместе с остальными.
+func LoadGRPC() (GRPC, error) {
+	cfg := DefaultGRPC()
+	var errs []error
+
+	ints := []struct {
+		env string
+		dst *int
+	}{
+		{"GRPC_MAX_RECV_MSG_BYTES", &cfg.MaxRecvMsgSize},
+		{"GRPC_MAX_SEND_MSG_BYTES", &cfg.MaxSendMsgSize},
+	}
+	for _, v := range ints {
+		if value := os.Getenv(v.env); value != "" {
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("invalid %s %q: expected an integer", v.env, value))
+				continue
+			}
+			*v.dst = n
+		}
+	}
+
+	if value := os.Getenv("GRPC_MAX_CONCURRENT_STREAMS"); value != "" {
+		n, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid GRPC_MAX_CONCURRENT_STREAMS %q: expected a positive integer", value))
+		} else {
+			cfg.MaxConcurrentStreams = uint32(n)
+		}
+	}
+
+	durations := []struct {
+		env string
+		dst *time.Duration
+	}{
+		{"GRPC_KEEPALIVE_TIME", &cfg.KeepaliveTime},
+		{"GRPC_KEEPALIVE_TIMEOUT", &cfg.KeepaliveTimeout},
+		{"GRPC_KEEPALIVE_MIN_TIME", &cfg.KeepaliveMinTime},
+		{"GRPC_DEFAULT_DEADLINE", &cfg.DefaultDeadline},
+	}
+	for _, v := range durations {
+		if value := os.Getenv(v.env); value != "" {
+			d, err := time.ParseDuration(value)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("invalid %s %q: expected a duration", v.env, value))
+				continue
+			}
+			*v.dst = d
+		}
+	}
+
+	return cfg, errors.Join(errs...)
+}
+
+// Validate проверяет, что ограничения положительны и согласованы между собой
+func (g GRPC) Validate() error {
+	var errs []error
+	if g.MaxRecvMsgSize <= 0 {
+		errs = append(errs, fmt.Errorf("GRPC_MAX_RECV_MSG_BYTES %d: must be positive", g.MaxRecvMsgSize))
+	}
+	if g.MaxSendMsgSize <= 0 {
+		errs = append(errs, fmt.Errorf("GRPC_MAX_SEND_MSG_BYTES %d: must be positive", g.MaxSendMsgSize))
+	}
+	if g.MaxConcurrentStreams == 0 {
+		errs = append(errs, errors.New("GRPC_MAX_CONCURRENT_STREAMS: must be positive"))
+	}
+	if g.KeepaliveTime <= 0 || g.KeepaliveTimeout <= 0 || g.KeepaliveMinTime <= 0 {
+		errs = append(errs, errors.New("GRPC_KEEPALIVE_TIME, GRPC_KEEPALIVE_TIMEOUT and GRPC_KEEPALIVE_MIN_TIME must be positive"))
+	}
+	// Клиент с тем же интервалом пингов, что и сервер, не должен получать разрыв соединения
+	if g.KeepaliveMinTime > g.KeepaliveTime {
+		errs = append(errs, fmt.Errorf("GRPC_KEEPALIVE_MIN_TIME %s must not exceed GRPC_KEEPALIVE_TIME %s",
+			g.KeepaliveMinTime, g.KeepaliveTime))
+	}
+	if g.DefaultDeadline <= 0 {
+		errs = append(errs, fmt.Errorf("GRPC_DEFAULT_DEADLINE %s: must be positive", g.DefaultDeadline))
+	}
+	return errors.Join(errs...)
+}
+
+// ServerOptions опции gRPC сервера для ограничений. Дедлайн по умолчанию
+// применяется интерсептором, т.к. у сервера нет такой опции.
+func (g GRPC) ServerOptions() []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.MaxRecvMsgSize(g.MaxRecvMsgSize),
+		grpc.MaxSendMsgSize(g.MaxSendMsgSize),
+		grpc.MaxConcurrentStreams(g.MaxConcurrentStreams),
+		grpc.KeepaliveParams(keepalive.ServerParameters{
+			Time:    g.KeepaliveTime,
+			Timeout: g.KeepaliveTimeout,
+		}),
+		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime:             g.KeepaliveMinTime,
+			PermitWithoutStream: true,
+		}),
+	}
+}
@@ -0,0 +1,88 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/kprf42/dolgova/pkg/logger"
+	"github.com/kprf42/dolgova/pkg/runtimecfg"
+)
+
+// Runtime настройки, которые можно менять без перезапуска сервиса
+type Runtime struct {
+	LogLevel      string   `json:"log_level"`      // Уровень логирования
+	CORSOrigins   []string `json:"cors_origins"`   // Разрешенные origin для CORS ("*" - любой, но без cookie)
+	ChatRetention Duration `json:"chat_retention"` // Срок хранения сообщений чата
+	Tenants       []Tenant `json:"tenants"`        // Сообщества помимо сообщества по умолчанию
+
+	// Первые N постов и комментариев нового пользователя попадают на премодерацию (0 - выключено)
+	PremoderationThreshold int `json:"premoderation_threshold"`
+	// Ссылки в постах и комментариях разрешены с этой кармы (0 - выключено)
+	LinkKarmaThreshold int `json:"link_karma_threshold"`
+	// За какой период считается активность в популярных постах (GET /posts/trending)
+	TrendingWindow Duration `json:"trending_window"`
+}
+
+// Tenant сообщество внутри одного развертывания
+type Tenant struct {
+	ID    string   `json:"id"`    // Используется в префиксе пути /t/{id}/ и в metadata x-tenant-id
+	Hosts []string `json:"hosts"` // Хосты, запросы на которые относятся к сообществу
+}
+
+// Duration time.Duration, читаемый из JSON строкой вида "720h"
+type Duration time.Duration
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("duration must be a string: %w", err)
+	}
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// DefaultRuntime настройки по умолчанию, если файл конфигурации отсутствует
+func DefaultRuntime() Runtime {
+	return Runtime{
+		LogLevel:      "info",
+		CORSOrigins:   []string{"http://localhost:3000"},
+		ChatRetention: Duration(30 * 24 * time.Hour),
+
+		TrendingWindow: Duration(24 * time.Hour),
+	}
+}
+
+// AllowsOrigin проверяет, разрешен ли origin для CORS: указан явно или разрешены все ("*")
+func (r *Runtime) AllowsOrigin(origin string) bool {
+	return r.ListsOrigin(origin) || slices.Contains(r.CORSOrigins, "*")
+}
+
+// ListsOrigin проверяет, указан ли origin в списке явно. Запросы с cookie разрешаются
+// только таким origin: "*" открыл бы ответы с данными пользователя любому сайту.
+func (r *Runtime) ListsOrigin(origin string) bool {
+	for _, allowed := range r.CORSOrigins {
+		if allowed != "*" && strings.EqualFold(allowed, origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// RuntimeWatcher следит за файлом runtime настроек и применяет изменения на лету
+type RuntimeWatcher = runtimecfg.Watcher[Runtime]
+
+// NewRuntimeWatcher загружает начальные настройки из файла (если он есть)
+func NewRuntimeWatcher(path string, defaults Runtime, log *logger.Logger) (*RuntimeWatcher, error) {
+	return runtimecfg.New(path, defaults, nil, log)
+}
@@ -0,0 +1,99 @@
+package config
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// TLS параметры сертификатов HTTP и gRPC серверов
+type TLS struct {
+	CertFile         string // Сертификат сервера (PEM)
+	KeyFile          string // Приватный ключ сервера (PEM)
+	GRPCClientCAFile string // CA для проверки клиентских сертификатов gRPC (mTLS), опционально
+}
+
+// Enabled сообщает, настроен ли TLS
+func (t TLS) Enabled() bool {
+	return t.CertFile != "" && t.KeyFile != ""
+}
+
+// Validate проверяет, что сертификат и ключ заданы вместе и файлы существуют
+func (t TLS) Validate() error {
+	var errs []error
+	if (t.CertFile == "") != (t.KeyFile == "") {
+		errs = append(errs, errors.New("TLS_CERT_FILE and TLS_KEY_FILE must be set together"))
+	}
+	if t.GRPCClientCAFile != "" && !t.Enabled() {
+		errs = append(errs, errors.New("GRPC_CLIENT_CA_FILE requires TLS_CERT_FILE and TLS_KEY_FILE"))
+	}
+	files := []struct{ name, path string }{
+		{"TLS_CERT_FILE", t.CertFile},
+		{"TLS_KEY_FILE", t.KeyFile},
+		{"GRPC_CLIENT_CA_FILE", t.GRPCClientCAFile},
+	}
+	for _, f := range files {
+		if f.path == "" {
+			continue
+		}
+		if _, err := os.Stat(f.path); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", f.name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// HTTPConfig возвращает TLS конфигурацию HTTP сервера с поддержкой HTTP/2
+func (t TLS) HTTPConfig() (*tls.Config, error) {
+	cert, err := t.loadCertificate()
+	if err != nil {
+		return nil, err
+	}
+
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+		NextProtos:   []string{"h2", "http/1.1"},
+	}, nil
+}
+
+// GRPCConfig возвращает TLS конфигурацию gRPC сервера.
+// Если задан GRPCClientCAFile, клиенты обязаны предъявить сертификат, подписанный этим CA.
+func (t TLS) GRPCConfig() (*tls.Config, error) {
+	cert, err := t.loadCertificate()
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	if t.GRPCClientCAFile != "" {
+		caPEM, err := os.ReadFile(t.GRPCClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read client CA: %w", err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, errors.New("failed to parse client CA certificates")
+		}
+
+		cfg.ClientCAs = pool
+		cfg.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	return cfg, nil
+}
+
+func (t TLS) loadCertificate() (tls.Certificate, error) {
+	cert, err := tls.LoadX509KeyPair(t.CertFile, t.KeyFile)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("failed to load TLS certificate: %w", err)
+	}
+	return cert, nil
+}
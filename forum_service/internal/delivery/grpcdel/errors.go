@@ -0,0 +1,85 @@
+package grpcdel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	post "github.com/kprf42/dolgova/forum_service/internal/usecase"
+	"github.com/kprf42/dolgova/pkg/grpcerr"
+	"google.golang.org/grpc/codes"
+)
+
+// errorDomain домен ErrorInfo ошибок сервиса форума
+const errorDomain = "forum.dolgova"
+
+// Причины ошибок (ErrorInfo.reason). Клиенты опираются на них, поэтому значения не меняются.
+const (
+	reasonInvalidRequest    = "INVALID_REQUEST" // Подробности в BadRequest.field_violations
+	reasonInvalidPageToken  = "INVALID_PAGE_TOKEN"
+	reasonInvalidUpdateMask = "INVALID_UPDATE_MASK"
+	reasonInvalidAttachment = "INVALID_ATTACHMENT"
+	reasonAuthRequired      = "AUTH_REQUIRED"
+	reasonInvalidToken      = "INVALID_TOKEN"
+	reasonAuthUnavailable   = "AUTH_UNAVAILABLE"
+	reasonAccountSuspended  = "ACCOUNT_SUSPENDED"
+	reasonInsufficientScope = "INSUFFICIENT_SCOPE"
+	reasonUnknownForum      = "UNKNOWN_FORUM"
+	reasonPostNotFound      = "POST_NOT_FOUND"
+	reasonCommentNotFound   = "COMMENT_NOT_FOUND"
+	reasonNotAuthor         = "NOT_AUTHOR"
+	reasonForbidden         = "FORBIDDEN"
+	reasonNotEnoughKarma    = "NOT_ENOUGH_KARMA"
+	reasonPostLocked        = "POST_LOCKED"
+	reasonQueryTimeout      = "QUERY_TIMEOUT"
+	reasonCanceled          = "CANCELED"
+	reasonInternal          = "INTERNAL"
+)
+
+// invalidArgument ошибка проверки запроса с нарушениями полей
+func invalidArgument(message string, violations ...*grpcerr.Violation) error {
+	return grpcerr.InvalidArgument(errorDomain, reasonInvalidRequest, message, violations...)
+}
+
+// invalidField ошибка проверки одного поля запроса
+func invalidField(field, description string) error {
+	return invalidArgument(description, grpcerr.Field(field, description))
+}
+
+// storeError ошибка чтения или записи с кодом code. Если запрос к базе не уложился
+// в отведенное время, возвращается codes.DeadlineExceeded, если клиент отменил вызов - codes.Canceled.
+func storeError(code codes.Code, format string, err error) error {
+	reason := reasonInternal
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		code, reason = codes.DeadlineExceeded, reasonQueryTimeout
+	case errors.Is(err, context.Canceled):
+		code, reason = codes.Canceled, reasonCanceled
+	}
+	return grpcerr.New(code, errorDomain, reason, fmt.Sprintf(format, err))
+}
+
+// notEnoughKarma ошибка ссылок в тексте автора, которому не хватает кармы
+func notEnoughKarma() error {
+	return grpcerr.New(codes.PermissionDenied, errorDomain, reasonNotEnoughKarma, "not enough karma to post links")
+}
+
+// postLocked ошибка комментария к закрытой теме
+func postLocked() error {
+	return grpcerr.New(codes.FailedPrecondition, errorDomain, reasonPostLocked, "post is locked")
+}
+
+// postError переводит ошибки PostUseCase в коды gRPC: отсутствующий пост - NotFound,
+// чужой пост и ссылки без достаточной кармы - PermissionDenied
+func postError(format string, err error) error {
+	if errors.Is(err, post.ErrNotEnoughKarma) {
+		return notEnoughKarma()
+	}
+	switch err.Error() {
+	case "post not found":
+		return grpcerr.New(codes.NotFound, errorDomain, reasonPostNotFound, "post not found")
+	case "unauthorized":
+		return grpcerr.New(codes.PermissionDenied, errorDomain, reasonNotAuthor, "not allowed to modify this post")
+	}
+	return storeError(codes.Internal, format, err)
+}
@@ -0,0 +1,442 @@
+package grpcdel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kprf42/dolgova/forum_service/internal/auth"
+	"github.com/kprf42/dolgova/forum_service/internal/tenant"
+	"github.com/kprf42/dolgova/pkg/audit"
+	"github.com/kprf42/dolgova/pkg/grpcerr"
+	"github.com/kprf42/dolgova/pkg/logger"
+	forum "github.com/kprf42/dolgova/proto/forum/v1"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// protectedMethods методы, которые требуют аутентифицированного пользователя
+var protectedMethods = map[string]bool{
+	forum.ForumService_CreatePost_FullMethodName:        true,
+	forum.ForumService_UpdatePost_FullMethodName:        true,
+	forum.ForumService_DeletePost_FullMethodName:        true,
+	forum.ForumService_CreateComment_FullMethodName:     true,
+	forum.ForumService_UpdateComment_FullMethodName:     true,
+	forum.ForumService_SendChatMessage_FullMethodName:   true,
+	forum.ForumService_AnonymizeUser_FullMethodName:     true,
+	forum.ForumService_ExportUserContent_FullMethodName: true,
+}
+
+// AuthInterceptor проверяет токен из metadata "authorization" через auth сервис и кладет пользователя в контекст.
+// Вызовы под имперсонацией пишутся в журнал аудита.
+type AuthInterceptor struct {
+	tokens auth.TokenValidator
+	audit  *audit.Store
+	log    *logger.Logger
+}
+
+func NewAuthInterceptor(tokens auth.TokenValidator, auditLog *audit.Store, log *logger.Logger) *AuthInterceptor {
+	return &AuthInterceptor{tokens: tokens, audit: auditLog, log: log}
+}
+
+func (i *AuthInterceptor) Unary() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := i.authenticate(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := handler(ctx, req)
+		i.record(ctx, info.FullMethod, err)
+		return resp, err
+	}
+}
+
+func (i *AuthInterceptor) Stream() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := i.authenticate(ss.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+		err = handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+		i.record(ctx, info.FullMethod, err)
+		return err
+	}
+}
+
+// record пишет в журнал аудита вызов, выполненный администратором от имени пользователя
+func (i *AuthInterceptor) record(ctx context.Context, method string, err error) {
+	adminID, ok := audit.ActingAdminFromContext(ctx)
+	if !ok {
+		return
+	}
+	userID, _ := auth.UserIDFromContext(ctx)
+
+	ip, _ := auth.ClientIPFromContext(ctx)
+
+	// Контекст вызова может быть уже отменен, запись в журнал не должна теряться
+	writeErr := i.audit.Write(context.WithoutCancel(ctx), &audit.Entry{
+		ActorID:       userID,
+		ActingAdminID: adminID,
+		Action:        audit.ActionRequest,
+		TargetType:    "grpc",
+		TargetID:      method,
+		Details:       "code=" + status.Code(err).String(),
+		IP:            ip,
+	})
+	if writeErr != nil {
+		i.log.Error("Failed to write audit entry",
+			logger.String("method", method),
+			logger.String("user_id", userID),
+			logger.String("acting_admin_id", adminID),
+			logger.Error(writeErr))
+	}
+}
+
+// authenticate валидирует токен, если он передан. Для защищенных методов токен обязателен,
+// для остальных невалидный токен все равно отклоняется, чтобы ошибка не маскировалась.
+func (i *AuthInterceptor) authenticate(ctx context.Context, method string) (context.Context, error) {
+	ctx = auth.WithClientIP(ctx, peerIP(ctx))
+
+	tokenString := bearerToken(ctx)
+	if tokenString == "" {
+		if protectedMethods[method] {
+			return nil, grpcerr.New(codes.Unauthenticated, errorDomain, reasonAuthRequired, "authorization token is required")
+		}
+		return ctx, nil
+	}
+
+	identity, err := i.tokens.ValidateToken(ctx, tokenString)
+	if errors.Is(err, auth.ErrUnavailable) {
+		return nil, grpcerr.New(codes.Unavailable, errorDomain, reasonAuthUnavailable, "auth service unavailable")
+	}
+	if errors.Is(err, auth.ErrSuspended) {
+		return nil, grpcerr.New(codes.PermissionDenied, errorDomain, reasonAccountSuspended, "account suspended")
+	}
+	if err != nil {
+		return nil, grpcerr.New(codes.Unauthenticated, errorDomain, reasonInvalidToken, "invalid token")
+	}
+
+	// С ограниченным (гостевым) токеном можно только читать, и то лишь при scope read-only
+	if len(identity.Scopes) > 0 && (protectedMethods[method] || !slices.Contains(identity.Scopes, auth.ScopeReadOnly)) {
+		return nil, grpcerr.New(codes.PermissionDenied, errorDomain, reasonInsufficientScope, "token scope does not allow this call")
+	}
+
+	ctx = logger.AddToContext(ctx, logger.String("user_id", identity.UserID))
+	if identity.ActingAdminID != "" {
+		ctx = audit.WithActingAdmin(ctx, identity.ActingAdminID)
+		ctx = logger.AddToContext(ctx, logger.String("acting_admin_id", identity.ActingAdminID))
+	}
+	return auth.WithScopes(auth.WithRole(auth.WithUserID(ctx, identity.UserID), identity.Role), identity.Scopes), nil
+}
+
+// peerIP адрес клиента gRPC соединения без порта
+func peerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	ip := p.Addr.String()
+	if host, _, err := net.SplitHostPort(ip); err == nil {
+		ip = host
+	}
+	return ip
+}
+
+// bearerToken достает токен из metadata "authorization: Bearer <token>"
+func bearerToken(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return ""
+	}
+
+	value := strings.TrimSpace(values[0])
+	if len(value) > len("bearer ") && strings.EqualFold(value[:len("bearer ")], "bearer ") {
+		return strings.TrimSpace(value[len("bearer "):])
+	}
+	return value
+}
+
+const tenantHeader = "x-tenant-id"
+
+// TenantInterceptor определяет сообщество по metadata x-tenant-id или по :authority
+type TenantInterceptor struct {
+	resolver *tenant.Resolver
+}
+
+func NewTenantInterceptor(resolver *tenant.Resolver) *TenantInterceptor {
+	return &TenantInterceptor{resolver: resolver}
+}
+
+func (i *TenantInterceptor) Unary() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := i.resolve(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func (i *TenantInterceptor) Stream() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := i.resolve(ss.Context())
+		if err != nil {
+			return err
+		}
+		return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+func (i *TenantInterceptor) resolve(ctx context.Context) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	tenantID := tenant.Default
+	if values := md.Get(tenantHeader); len(values) > 0 && values[0] != "" {
+		if !i.resolver.Exists(values[0]) {
+			return nil, grpcerr.New(codes.NotFound, errorDomain, reasonUnknownForum, fmt.Sprintf("unknown forum %q", values[0]))
+		}
+		tenantID = values[0]
+	} else if values := md.Get(":authority"); len(values) > 0 {
+		tenantID = i.resolver.ByHost(values[0])
+	}
+
+	ctx = logger.AddToContext(ctx, logger.String("tenant_id", tenantID))
+	return tenant.WithID(ctx, tenantID), nil
+}
+
+// contextStream подменяет контекст потока (пользователь, ID запроса)
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
+
+type requestIDKey struct{}
+
+// RequestIDFromContext возвращает ID запроса, присвоенный LoggingInterceptor
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+// withRequestID берет x-request-id из metadata или генерирует новый и возвращает его клиенту
+func withRequestID(ctx context.Context) (context.Context, string) {
+	var requestID string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(requestIDHeader); len(values) > 0 {
+			requestID = values[0]
+		}
+	}
+	if requestID == "" {
+		requestID = uuid.New().String()
+	}
+
+	grpc.SetHeader(ctx, metadata.Pairs(requestIDHeader, requestID))
+	return context.WithValue(ctx, requestIDKey{}, requestID), requestID
+}
+
+const requestIDHeader = "x-request-id"
+
+// LoggingInterceptor логирует каждый RPC с ID запроса, кодом ответа и длительностью
+type LoggingInterceptor struct {
+	log *logger.Logger
+}
+
+func NewLoggingInterceptor(log *logger.Logger) *LoggingInterceptor {
+	return &LoggingInterceptor{log: log}
+}
+
+func (i *LoggingInterceptor) Unary() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, requestID := withRequestID(ctx)
+		ctx = i.withLogger(ctx, info.FullMethod, requestID)
+		start := time.Now()
+
+		resp, err := handler(ctx, req)
+
+		i.logRPC(info.FullMethod, requestID, start, err)
+		return resp, err
+	}
+}
+
+func (i *LoggingInterceptor) Stream() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, requestID := withRequestID(ss.Context())
+		ctx = i.withLogger(ctx, info.FullMethod, requestID)
+		start := time.Now()
+
+		err := handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+
+		i.logRPC(info.FullMethod, requestID, start, err)
+		return err
+	}
+}
+
+// withLogger сохраняет в контексте логгер вызова: записи use case и репозиториев получают request_id
+func (i *LoggingInterceptor) withLogger(ctx context.Context, method, requestID string) context.Context {
+	return logger.WithContext(ctx, i.log.WithFields(
+		logger.String("request_id", requestID),
+		logger.String("method", method)))
+}
+
+func (i *LoggingInterceptor) logRPC(method, requestID string, start time.Time, err error) {
+	code := status.Code(err)
+	fields := []logger.Field{
+		logger.String("method", method),
+		logger.String("request_id", requestID),
+		logger.String("code", code.String()),
+		logger.Duration("duration_ms", float64(time.Since(start).Microseconds())/1000),
+	}
+
+	switch code {
+	case codes.OK:
+		i.log.Info("gRPC request handled", fields...)
+	case codes.Internal, codes.Unknown, codes.DataLoss, codes.Unavailable:
+		i.log.Error("gRPC request failed", append(fields, logger.Error(err))...)
+	default:
+		i.log.Warn("gRPC request rejected", append(fields, logger.Error(err))...)
+	}
+}
+
+// RecoveryInterceptor превращает панику в обработчике в codes.Internal вместо падения сервера
+type RecoveryInterceptor struct {
+	log *logger.Logger
+}
+
+func NewRecoveryInterceptor(log *logger.Logger) *RecoveryInterceptor {
+	return &RecoveryInterceptor{log: log}
+}
+
+func (i *RecoveryInterceptor) Unary() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = i.recovered(ctx, info.FullMethod, r)
+			}
+		}()
+		return handler(ctx, req)
+	}
+}
+
+func (i *RecoveryInterceptor) Stream() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = i.recovered(ss.Context(), info.FullMethod, r)
+			}
+		}()
+		return handler(srv, ss)
+	}
+}
+
+func (i *RecoveryInterceptor) recovered(ctx context.Context, method string, r interface{}) error {
+	i.log.Error("Panic in gRPC handler",
+		logger.String("method", method),
+		logger.String("request_id", RequestIDFromContext(ctx)),
+		logger.Any("panic", r),
+		logger.Stack("stack"))
+	return grpcerr.New(codes.Internal, errorDomain, reasonInternal, "internal server error")
+}
+
+// DeadlineInterceptor ограничивает unary вызовы без дедлайна клиента значением по умолчанию
+// и возвращает DeadlineExceeded/Canceled, если обработчик завершился из-за отмены контекста.
+// Потоки (StreamPosts) выполняются до дедлайна клиента без ограничения по умолчанию.
+type DeadlineInterceptor struct {
+	defaultTimeout time.Duration
+}
+
+func NewDeadlineInterceptor(defaultTimeout time.Duration) *DeadlineInterceptor {
+	return &DeadlineInterceptor{defaultTimeout: defaultTimeout}
+}
+
+func (i *DeadlineInterceptor) Unary() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if _, ok := ctx.Deadline(); !ok {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, i.defaultTimeout)
+			defer cancel()
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, status.FromContextError(err).Err()
+		}
+
+		resp, err := handler(ctx, req)
+		return resp, contextError(ctx, err)
+	}
+}
+
+func (i *DeadlineInterceptor) Stream() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return contextError(ss.Context(), handler(srv, ss))
+	}
+}
+
+// contextError заменяет ошибку обработчика кодом отмены, если контекст уже завершен
+func contextError(ctx context.Context, err error) error {
+	if err == nil || ctx.Err() == nil {
+		return err
+	}
+	return status.FromContextError(ctx.Err()).Err()
+}
+
+// MetricsInterceptor считает запросы и длительность по каждому методу в Prometheus
+type MetricsInterceptor struct {
+	handled  *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+}
+
+func NewMetricsInterceptor(reg prometheus.Registerer) *MetricsInterceptor {
+	m := &MetricsInterceptor{
+		handled: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "grpc_server_handled_total",
+			Help: "Total number of RPCs completed on the server, by method and status code.",
+		}, []string{"method", "code"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "grpc_server_handling_seconds",
+			Help:    "Histogram of RPC handling latency, by method.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method"}),
+	}
+	reg.MustRegister(m.handled, m.duration)
+	return m
+}
+
+func (i *MetricsInterceptor) Unary() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		i.observe(info.FullMethod, start, err)
+		return resp, err
+	}
+}
+
+func (i *MetricsInterceptor) Stream() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		i.observe(info.FullMethod, start, err)
+		return err
+	}
+}
+
+func (i *MetricsInterceptor) observe(method string, start time.Time, err error) {
+	i.handled.WithLabelValues(method, status.Code(err).String()).Inc()
+	i.duration.WithLabelValues(method).Observe(time.Since(start).Seconds())
+}
@@ -0,0 +1,74 @@
+package grpcdel
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"time"
+
+	"github.com/kprf42/dolgova/forum_service/internal/entity"
+	"github.com/kprf42/dolgova/pkg/grpcerr"
+)
+
+// Размер страницы списков по умолчанию и максимальный (больший page_size уменьшается до максимума)
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// Размер пачки StreamPosts по умолчанию и максимальный
+const (
+	defaultStreamBatch = 100
+	maxStreamBatch     = 500
+)
+
+// pageToken содержимое page_token. Для клиента токен непрозрачен; фильтр запоминается,
+// чтобы токен нельзя было применить к другому списку.
+type pageToken struct {
+	CreatedAt time.Time `json:"t"`
+	ID        string    `json:"i"`
+	Filter    string    `json:"f,omitempty"`
+}
+
+// pageSize проверяет page_size из запроса и подставляет значение по умолчанию
+func pageSize(size int32) (int, error) {
+	switch {
+	case size < 0:
+		return 0, invalidField("page_size", "page_size must not be negative")
+	case size == 0:
+		return defaultPageSize, nil
+	case size > maxPageSize:
+		return maxPageSize, nil
+	}
+	return int(size), nil
+}
+
+// decodePageToken разбирает page_token. Пустой токен означает первую страницу (nil курсор).
+func decodePageToken(token, filter string) (*entity.PageCursor, error) {
+	if token == "" {
+		return nil, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, invalidPageToken("invalid page_token")
+	}
+	var t pageToken
+	if err := json.Unmarshal(raw, &t); err != nil || t.ID == "" {
+		return nil, invalidPageToken("invalid page_token")
+	}
+	if t.Filter != filter {
+		return nil, invalidPageToken("page_token does not match request parameters")
+	}
+
+	return &entity.PageCursor{CreatedAt: t.CreatedAt, ID: t.ID}, nil
+}
+
+// nextPageToken токен страницы, следующей за записью (createdAt, id)
+func nextPageToken(createdAt time.Time, id, filter string) string {
+	raw, _ := json.Marshal(pageToken{CreatedAt: createdAt, ID: id, Filter: filter})
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func invalidPageToken(message string) error {
+	return grpcerr.InvalidArgument(errorDomain, reasonInvalidPageToken, message, grpcerr.Field("page_token", message))
+}
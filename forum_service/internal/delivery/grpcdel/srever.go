@@ -2,33 +2,60 @@ package grpcdel
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"slices"
 	"time"
+	"unicode/utf8"
 
+	"github.com/google/uuid"
+	"github.com/kprf42/dolgova/forum_service/internal/auth"
 	"github.com/kprf42/dolgova/forum_service/internal/entity"
+	"github.com/kprf42/dolgova/forum_service/internal/tenant"
 	chat "github.com/kprf42/dolgova/forum_service/internal/usecase"
 	comment "github.com/kprf42/dolgova/forum_service/internal/usecase"
 	post "github.com/kprf42/dolgova/forum_service/internal/usecase"
-	"github.com/kprf42/dolgova/proto/forum"
+	user "github.com/kprf42/dolgova/forum_service/internal/usecase"
+	"github.com/kprf42/dolgova/pkg/grpcerr"
+	"github.com/kprf42/dolgova/pkg/logger"
+	forum "github.com/kprf42/dolgova/proto/forum/v1"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
+// maxChatMessageLength совпадает с ограничением сообщений WebSocket чата
+const maxChatMessageLength = 1000
+
+// ChatBroadcaster рассылает сохраненное сообщение чата WebSocket клиентам
+type ChatBroadcaster interface {
+	Deliver(ctx context.Context, msg *entity.ChatMessage) error
+}
+
 type ForumServer struct {
 	forum.UnimplementedForumServiceServer
 	postUC    *post.PostUseCase
 	commentUC *comment.CommentUseCase
 	chatUC    *chat.ChatUseCase
+	userUC    *user.UserUseCase
+	chatHub   ChatBroadcaster
 }
 
+// NewForumServer создает gRPC сервер форума. chatHub может быть nil,
+// тогда сообщения из SendChatMessage только сохраняются.
 func NewForumServer(
 	postUC *post.PostUseCase,
 	commentUC *comment.CommentUseCase,
 	chatUC *chat.ChatUseCase,
+	userUC *user.UserUseCase,
+	chatHub ChatBroadcaster,
 ) *ForumServer {
 	return &ForumServer{
 		postUC:    postUC,
 		commentUC: commentUC,
 		chatUC:    chatUC,
+		userUC:    userUC,
+		chatHub:   chatHub,
 	}
 }
 
@@ -39,125 +66,604 @@ func (s *ForumServer) CreatePost(ctx context.Context, req *forum.CreatePostReque
 		CategoryID: req.CategoryId,
 	}
 
-	response, err := s.postUC.Create(ctx, postReq, req.AuthorId)
+	authorID, err := authorFromContext(ctx, req.GetAuthorId())
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to create post: %v", err)
+		return nil, err
+	}
+
+	response, err := s.postUC.Create(ctx, postReq, authorID)
+	if errors.Is(err, post.ErrUnknownCategory) {
+		return nil, invalidField("category_id", "unknown category")
+	}
+	if errors.Is(err, post.ErrNotEnoughKarma) {
+		return nil, notEnoughKarma()
+	}
+	if err != nil {
+		return nil, storeError(codes.Internal, "failed to create post: %v", err)
 	}
 
 	return &forum.PostResponse{
-		Id:         response.ID,
-		Title:      response.Title,
-		Content:    response.Content,
-		AuthorId:   response.AuthorID,
-		CategoryId: response.CategoryID,
-		CreatedAt:  response.CreatedAt.Format(time.RFC3339),
-		IsPinned:   response.IsPinned,
+		Id:               response.ID,
+		Title:            response.Title,
+		Content:          response.Content,
+		AuthorId:         response.AuthorID,
+		AuthorKarma:      int32(response.AuthorKarma),
+		CategoryId:       response.CategoryID,
+		CreatedAt:        timestamppb.New(response.CreatedAt),
+		CreatedAtRfc3339: response.CreatedAt.Format(time.RFC3339),
+		IsPinned:         response.IsPinned,
 	}, nil
 }
 
 func (s *ForumServer) GetPost(ctx context.Context, req *forum.GetPostRequest) (*forum.PostResponse, error) {
 	post, err := s.postUC.GetByID(ctx, req.PostId)
 	if err != nil {
-		return nil, status.Errorf(codes.NotFound, "post not found: %v", err)
+		if err.Error() == "post not found" {
+			return nil, grpcerr.New(codes.NotFound, errorDomain, reasonPostNotFound, "post not found")
+		}
+		return nil, storeError(codes.Internal, "failed to get post: %v", err)
 	}
 
 	return &forum.PostResponse{
-		Id:         post.ID,
-		Title:      post.Title,
-		Content:    post.Content,
-		AuthorId:   post.AuthorID,
-		CategoryId: post.CategoryID,
-		CreatedAt:  post.CreatedAt.Format(time.RFC3339),
-		IsPinned:   post.IsPinned,
+		Id:               post.ID,
+		Title:            post.Title,
+		Content:          post.Content,
+		AuthorId:         post.AuthorID,
+		AuthorKarma:      int32(post.AuthorKarma),
+		CategoryId:       post.CategoryID,
+		CreatedAt:        timestamppb.New(post.CreatedAt),
+		CreatedAtRfc3339: post.CreatedAt.Format(time.RFC3339),
+		IsPinned:         post.IsPinned,
 	}, nil
 }
 
 func (s *ForumServer) GetPosts(ctx context.Context, req *forum.GetPostsRequest) (*forum.GetPostsResponse, error) {
-	posts, total, err := s.postUC.GetAll(ctx, int(req.Limit), int(req.Offset), req.CategoryId)
+	size, err := pageSize(req.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	after, err := decodePageToken(req.PageToken, req.CategoryId)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to get posts: %v", err)
+		return nil, err
+	}
+
+	// Лишняя запись показывает, есть ли следующая страница
+	posts, total, err := s.postUC.GetAll(ctx, size+1, 0, after, entity.PostFilter{CategoryID: req.CategoryId, Chronological: true})
+	if err != nil {
+		return nil, storeError(codes.Internal, "failed to get posts: %v", err)
+	}
+
+	var nextToken string
+	if len(posts) > size {
+		posts = posts[:size]
+		last := posts[size-1]
+		nextToken = nextPageToken(last.CreatedAt, last.ID, req.CategoryId)
 	}
 
 	var responses []*forum.PostResponse
 	for _, post := range posts {
 		responses = append(responses, &forum.PostResponse{
-			Id:         post.ID,
-			Title:      post.Title,
-			Content:    post.Content,
-			AuthorId:   post.AuthorID,
-			CategoryId: post.CategoryID,
-			CreatedAt:  post.CreatedAt.Format(time.RFC3339),
-			IsPinned:   post.IsPinned,
+			Id:               post.ID,
+			Title:            post.Title,
+			Content:          post.Content,
+			AuthorId:         post.AuthorID,
+			AuthorKarma:      int32(post.AuthorKarma),
+			CategoryId:       post.CategoryID,
+			CreatedAt:        timestamppb.New(post.CreatedAt),
+			CreatedAtRfc3339: post.CreatedAt.Format(time.RFC3339),
+			IsPinned:         post.IsPinned,
+			IsUnread:         post.IsUnread != nil && *post.IsUnread,
 		})
 	}
 
 	return &forum.GetPostsResponse{
-		Posts: responses,
-		Total: int32(total),
+		Posts:         responses,
+		Total:         int32(total),
+		NextPageToken: nextToken,
 	}, nil
 }
 
+// StreamPosts выгружает опубликованные посты пачками по keyset-курсору, чтобы
+// интеграции не листали тысячи страниц GetPosts
+func (s *ForumServer) StreamPosts(req *forum.StreamPostsRequest, stream forum.ForumService_StreamPostsServer) error {
+	ctx := stream.Context()
+
+	batchSize := int(req.BatchSize)
+	switch {
+	case batchSize < 0:
+		return invalidField("batch_size", "batch_size must not be negative")
+	case batchSize == 0:
+		batchSize = defaultStreamBatch
+	case batchSize > maxStreamBatch:
+		batchSize = maxStreamBatch
+	}
+
+	var createdAfter time.Time
+	if req.CreatedAfter != nil {
+		if err := req.CreatedAfter.CheckValid(); err != nil {
+			return invalidField("created_after", "invalid timestamp")
+		}
+		createdAfter = req.CreatedAfter.AsTime()
+	}
+
+	// Пустой ID в курсоре отсекает все посты с created_at >= created_before
+	var after *entity.PageCursor
+	if req.CreatedBefore != nil {
+		if err := req.CreatedBefore.CheckValid(); err != nil {
+			return invalidField("created_before", "invalid timestamp")
+		}
+		after = &entity.PageCursor{CreatedAt: req.CreatedBefore.AsTime()}
+	}
+
+	for {
+		// Клиент отключился или истек его дедлайн - дальше не выгружаем
+		if err := ctx.Err(); err != nil {
+			return status.FromContextError(err).Err()
+		}
+
+		posts, _, err := s.postUC.GetAll(ctx, batchSize, 0, after, entity.PostFilter{CategoryID: req.CategoryId, Chronological: true})
+		if err != nil {
+			return storeError(codes.Internal, "failed to get posts: %v", err)
+		}
+
+		batch := make([]*forum.PostResponse, 0, len(posts))
+		done := len(posts) < batchSize
+		for _, post := range posts {
+			// Посты идут от новых к старым, дальше только более ранние
+			if post.CreatedAt.Before(createdAfter) {
+				done = true
+				break
+			}
+			batch = append(batch, &forum.PostResponse{
+				Id:               post.ID,
+				Title:            post.Title,
+				Content:          post.Content,
+				AuthorId:         post.AuthorID,
+				AuthorKarma:      int32(post.AuthorKarma),
+				CategoryId:       post.CategoryID,
+				CreatedAt:        timestamppb.New(post.CreatedAt),
+				CreatedAtRfc3339: post.CreatedAt.Format(time.RFC3339),
+				IsPinned:         post.IsPinned,
+				IsUnread:         post.IsUnread != nil && *post.IsUnread,
+			})
+		}
+
+		if len(batch) > 0 {
+			if err := stream.Send(&forum.StreamPostsResponse{Posts: batch}); err != nil {
+				return err
+			}
+		}
+		if done {
+			return nil
+		}
+
+		last := posts[len(posts)-1]
+		after = &entity.PageCursor{CreatedAt: last.CreatedAt, ID: last.ID}
+	}
+}
+
+// UpdatePost меняет поля поста из update_mask (без маски - заголовок и текст).
+// Редактировать может только автор.
+func (s *ForumServer) UpdatePost(ctx context.Context, req *forum.UpdatePostRequest) (*forum.PostResponse, error) {
+	if _, err := uuid.Parse(req.PostId); err != nil {
+		return nil, invalidField("post_id", "post_id must be a UUID")
+	}
+	mask := req.GetUpdateMask().GetPaths()
+	var violations []*grpcerr.Violation
+	if inMask(mask, "title") && req.Title == "" {
+		violations = append(violations, grpcerr.Field("title", "title is required"))
+	}
+	if inMask(mask, "content") && req.Content == "" {
+		violations = append(violations, grpcerr.Field("content", "content is required"))
+	}
+	if len(violations) > 0 {
+		return nil, invalidArgument("invalid post update", violations...)
+	}
+
+	userID, err := authorFromContext(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := s.postUC.Update(ctx, req.PostId, &entity.PostUpdate{
+		Title:      req.Title,
+		Content:    req.Content,
+		UpdateMask: mask,
+	}, userID)
+	if errors.Is(err, post.ErrInvalidUpdateMask) {
+		return nil, grpcerr.InvalidArgument(errorDomain, reasonInvalidUpdateMask, err.Error(),
+			grpcerr.Field("update_mask.paths", err.Error()))
+	}
+	if err != nil {
+		return nil, postError("failed to update post: %v", err)
+	}
+
+	return &forum.PostResponse{
+		Id:               updated.ID,
+		Title:            updated.Title,
+		Content:          updated.Content,
+		AuthorId:         updated.AuthorID,
+		AuthorKarma:      int32(updated.AuthorKarma),
+		CategoryId:       updated.CategoryID,
+		CreatedAt:        timestamppb.New(updated.CreatedAt),
+		CreatedAtRfc3339: updated.CreatedAt.Format(time.RFC3339),
+		IsPinned:         updated.IsPinned,
+	}, nil
+}
+
+// DeletePost удаляет пост. Удалить может автор или модератор категории поста.
+func (s *ForumServer) DeletePost(ctx context.Context, req *forum.DeletePostRequest) (*forum.DeletePostResponse, error) {
+	if _, err := uuid.Parse(req.PostId); err != nil {
+		return nil, invalidField("post_id", "post_id must be a UUID")
+	}
+
+	userID, err := authorFromContext(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.postUC.Delete(ctx, req.PostId, userID); err != nil {
+		return nil, postError("failed to delete post: %v", err)
+	}
+
+	return &forum.DeletePostResponse{}, nil
+}
+
 func (s *ForumServer) CreateComment(ctx context.Context, req *forum.CreateCommentRequest) (*forum.CommentResponse, error) {
 	commentReq := &entity.CommentRequest{
 		Content: req.Content,
 		PostID:  req.PostId,
 	}
 
-	comment, err := s.commentUC.Create(ctx, commentReq, req.AuthorId)
+	authorID, err := authorFromContext(ctx, req.GetAuthorId())
+	if err != nil {
+		return nil, err
+	}
+
+	comment, err := s.commentUC.Create(ctx, commentReq, authorID)
+	if errors.Is(err, post.ErrNotEnoughKarma) {
+		return nil, notEnoughKarma()
+	}
+	if errors.Is(err, post.ErrPostLocked) {
+		return nil, postLocked()
+	}
+	if err != nil {
+		return nil, storeError(codes.Internal, "failed to create comment: %v", err)
+	}
+
+	return &forum.CommentResponse{
+		Id:               comment.ID,
+		Content:          comment.Content,
+		PostId:           comment.PostID,
+		AuthorId:         comment.AuthorID,
+		AuthorKarma:      int32(comment.AuthorKarma),
+		CreatedAt:        timestamppb.New(comment.CreatedAt),
+		CreatedAtRfc3339: comment.CreatedAt.Format(time.RFC3339),
+	}, nil
+}
+
+// UpdateComment меняет текст комментария. Редактировать может только автор.
+func (s *ForumServer) UpdateComment(ctx context.Context, req *forum.UpdateCommentRequest) (*forum.CommentResponse, error) {
+	if _, err := uuid.Parse(req.CommentId); err != nil {
+		return nil, invalidField("comment_id", "comment_id must be a UUID")
+	}
+	for _, path := range req.GetUpdateMask().GetPaths() {
+		if path != "content" {
+			message := fmt.Sprintf("field %q cannot be updated", path)
+			return nil, grpcerr.InvalidArgument(errorDomain, reasonInvalidUpdateMask, message,
+				grpcerr.Field("update_mask.paths", message))
+		}
+	}
+	if req.Content == "" {
+		return nil, invalidField("content", "content is required")
+	}
+
+	userID, err := authorFromContext(ctx, "")
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to create comment: %v", err)
+		return nil, err
+	}
+
+	comment, err := s.commentUC.Update(ctx, req.CommentId, req.Content, userID)
+	if err != nil {
+		if errors.Is(err, post.ErrNotEnoughKarma) {
+			return nil, notEnoughKarma()
+		}
+		switch err.Error() {
+		case "comment not found":
+			return nil, grpcerr.New(codes.NotFound, errorDomain, reasonCommentNotFound, "comment not found")
+		case "unauthorized":
+			return nil, grpcerr.New(codes.PermissionDenied, errorDomain, reasonNotAuthor, "not allowed to modify this comment")
+		}
+		return nil, storeError(codes.Internal, "failed to update comment: %v", err)
 	}
 
 	return &forum.CommentResponse{
-		Id:        comment.ID,
-		Content:   comment.Content,
-		PostId:    comment.PostID,
-		AuthorId:  comment.AuthorID,
-		CreatedAt: comment.CreatedAt.Format(time.RFC3339),
+		Id:               comment.ID,
+		Content:          comment.Content,
+		PostId:           comment.PostID,
+		AuthorId:         comment.AuthorID,
+		AuthorKarma:      int32(comment.AuthorKarma),
+		CreatedAt:        timestamppb.New(comment.CreatedAt),
+		CreatedAtRfc3339: comment.CreatedAt.Format(time.RFC3339),
 	}, nil
 }
 
 func (s *ForumServer) GetComments(ctx context.Context, req *forum.GetCommentsRequest) (*forum.GetCommentsResponse, error) {
-	comments, total, err := s.commentUC.GetByPostID(ctx, req.PostId, int(req.Limit), int(req.Offset))
+	size, err := pageSize(req.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	after, err := decodePageToken(req.PageToken, req.PostId)
+	if err != nil {
+		return nil, err
+	}
+
+	comments, total, err := s.commentUC.GetByPostID(ctx, req.PostId, size+1, 0, after)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to get comments: %v", err)
+		return nil, storeError(codes.Internal, "failed to get comments: %v", err)
+	}
+
+	var nextToken string
+	if len(comments) > size {
+		comments = comments[:size]
+		last := comments[size-1]
+		nextToken = nextPageToken(last.CreatedAt, last.ID, req.PostId)
 	}
 
 	var responses []*forum.CommentResponse
 	for _, comment := range comments {
 		responses = append(responses, &forum.CommentResponse{
-			Id:        comment.ID,
-			Content:   comment.Content,
-			PostId:    comment.PostID,
-			AuthorId:  comment.AuthorID,
-			CreatedAt: comment.CreatedAt.Format(time.RFC3339),
+			Id:               comment.ID,
+			Content:          comment.Content,
+			PostId:           comment.PostID,
+			AuthorId:         comment.AuthorID,
+			AuthorKarma:      int32(comment.AuthorKarma),
+			CreatedAt:        timestamppb.New(comment.CreatedAt),
+			CreatedAtRfc3339: comment.CreatedAt.Format(time.RFC3339),
 		})
 	}
 
 	return &forum.GetCommentsResponse{
-		Comments: responses,
-		Total:    int32(total),
+		Comments:      responses,
+		Total:         int32(total),
+		NextPageToken: nextToken,
 	}, nil
 }
 
 func (s *ForumServer) GetChatMessages(ctx context.Context, req *forum.GetChatMessagesRequest) (*forum.GetChatMessagesResponse, error) {
-	messages, err := s.chatUC.GetMessages(ctx, int(req.Limit), int(req.Offset))
+	size, err := pageSize(req.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	after, err := decodePageToken(req.PageToken, "")
+	if err != nil {
+		return nil, err
+	}
+
+	messages, err := s.chatUC.GetMessages(ctx, size+1, 0, after)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to get chat messages: %v", err)
+		return nil, storeError(codes.Internal, "failed to get chat messages: %v", err)
+	}
+
+	var nextToken string
+	if len(messages) > size {
+		messages = messages[:size]
+		last := messages[size-1]
+		nextToken = nextPageToken(last.CreatedAt, last.ID, "")
 	}
 
 	var responses []*forum.ChatMessage
 	for _, msg := range messages {
 		responses = append(responses, &forum.ChatMessage{
-			Id:        msg.ID,
-			UserId:    msg.UserID,
-			Text:      msg.Text,
-			CreatedAt: msg.CreatedAt.Format(time.RFC3339),
+			Id:               msg.ID,
+			UserId:           msg.UserID,
+			Text:             msg.Text,
+			CreatedAt:        timestamppb.New(msg.CreatedAt),
+			CreatedAtRfc3339: msg.CreatedAt.Format(time.RFC3339),
 		})
 	}
 
 	return &forum.GetChatMessagesResponse{
-		Messages: responses,
-		Total:    int32(len(responses)),
+		Messages:      responses,
+		Total:         int32(len(responses)),
+		NextPageToken: nextToken,
 	}, nil
 }
+
+// SendChatMessage сохраняет сообщение в чате сообщества и рассылает его WebSocket клиентам
+func (s *ForumServer) SendChatMessage(ctx context.Context, req *forum.SendChatMessageRequest) (*forum.ChatMessage, error) {
+	if len(req.Text) == 0 || utf8.RuneCountInString(req.Text) > maxChatMessageLength {
+		return nil, invalidField("text", fmt.Sprintf("text must be 1 to %d characters", maxChatMessageLength))
+	}
+
+	userID, err := authorFromContext(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	msg := entity.NewChatMessage(&entity.ChatMessageRequest{
+		Text:          req.Text,
+		AttachmentIDs: req.AttachmentIds,
+	}, userID, tenant.FromContext(ctx))
+
+	if err := s.chatUC.SaveMessage(ctx, msg); err != nil {
+		if errors.Is(err, chat.ErrInvalidAttachment) {
+			return nil, grpcerr.InvalidArgument(errorDomain, reasonInvalidAttachment, err.Error(),
+				grpcerr.Field("attachment_ids", err.Error()))
+		}
+		return nil, storeError(codes.Internal, "failed to save chat message: %v", err)
+	}
+
+	// Сообщение уже сохранено: недоступность Hub не должна превращать ответ в ошибку
+	if s.chatHub != nil {
+		if err := s.chatHub.Deliver(ctx, msg); err != nil {
+			logger.FromContext(ctx).Warn("Failed to deliver chat message to websocket clients",
+				logger.String("message_id", msg.ID),
+				logger.Error(err))
+		}
+	}
+
+	return &forum.ChatMessage{
+		Id:               msg.ID,
+		UserId:           msg.UserID,
+		Text:             msg.Text,
+		CreatedAt:        timestamppb.New(msg.CreatedAt),
+		CreatedAtRfc3339: msg.CreatedAt.Format(time.RFC3339),
+	}, nil
+}
+
+// AnonymizeUser переназначает контент пользователя удаленному пользователю и удаляет его данные форума.
+// Вызывается auth сервисом с токеном удаляемого пользователя; администратор может обезличить любого.
+func (s *ForumServer) AnonymizeUser(ctx context.Context, req *forum.AnonymizeUserRequest) (*forum.AnonymizeUserResponse, error) {
+	if req.GetUserId() == "" {
+		return nil, invalidField("user_id", "user_id is required")
+	}
+	if req.GetUserId() == entity.DeletedUserID {
+		return nil, invalidField("user_id", "user is already deleted")
+	}
+
+	if err := selfOrAdmin(ctx, req.GetUserId()); err != nil {
+		return nil, err
+	}
+
+	result, err := s.userUC.Anonymize(ctx, req.GetUserId())
+	if err != nil {
+		return nil, storeError(codes.Internal, "failed to anonymize user: %v", err)
+	}
+
+	return &forum.AnonymizeUserResponse{
+		Posts:        result.Posts,
+		Comments:     result.Comments,
+		ChatMessages: result.ChatMessages,
+	}, nil
+}
+
+// ExportUserContent выгружает посты, комментарии и сообщения чата пользователя пачками:
+// сначала все посты, затем комментарии, затем сообщения
+func (s *ForumServer) ExportUserContent(req *forum.ExportUserContentRequest, stream forum.ForumService_ExportUserContentServer) error {
+	ctx := stream.Context()
+
+	if req.GetUserId() == "" {
+		return invalidField("user_id", "user_id is required")
+	}
+	batchSize := int(req.BatchSize)
+	switch {
+	case batchSize < 0:
+		return invalidField("batch_size", "batch_size must not be negative")
+	case batchSize == 0:
+		batchSize = defaultStreamBatch
+	case batchSize > maxStreamBatch:
+		batchSize = maxStreamBatch
+	}
+	if err := selfOrAdmin(ctx, req.GetUserId()); err != nil {
+		return err
+	}
+
+	for after := ""; ; {
+		posts, err := s.userUC.ExportPosts(ctx, req.GetUserId(), after, batchSize)
+		if err != nil {
+			return storeError(codes.Internal, "failed to export posts: %v", err)
+		}
+		if len(posts) == 0 {
+			break
+		}
+		batch := make([]*forum.ExportedPost, 0, len(posts))
+		for _, post := range posts {
+			batch = append(batch, &forum.ExportedPost{
+				Id:         post.ID,
+				ForumId:    post.TenantID,
+				Title:      post.Title,
+				Content:    post.Content,
+				CategoryId: post.CategoryID,
+				Status:     post.Status,
+				CreatedAt:  timestamppb.New(post.CreatedAt),
+			})
+		}
+		if err := stream.Send(&forum.ExportUserContentResponse{Posts: batch}); err != nil {
+			return err
+		}
+		after = posts[len(posts)-1].ID
+	}
+
+	for after := ""; ; {
+		comments, err := s.userUC.ExportComments(ctx, req.GetUserId(), after, batchSize)
+		if err != nil {
+			return storeError(codes.Internal, "failed to export comments: %v", err)
+		}
+		if len(comments) == 0 {
+			break
+		}
+		batch := make([]*forum.ExportedComment, 0, len(comments))
+		for _, comment := range comments {
+			batch = append(batch, &forum.ExportedComment{
+				Id:        comment.ID,
+				ForumId:   comment.TenantID,
+				PostId:    comment.PostID,
+				Content:   comment.Content,
+				Status:    comment.Status,
+				CreatedAt: timestamppb.New(comment.CreatedAt),
+			})
+		}
+		if err := stream.Send(&forum.ExportUserContentResponse{Comments: batch}); err != nil {
+			return err
+		}
+		after = comments[len(comments)-1].ID
+	}
+
+	for after := ""; ; {
+		messages, err := s.userUC.ExportChatMessages(ctx, req.GetUserId(), after, batchSize)
+		if err != nil {
+			return storeError(codes.Internal, "failed to export chat messages: %v", err)
+		}
+		if len(messages) == 0 {
+			break
+		}
+		batch := make([]*forum.ExportedChatMessage, 0, len(messages))
+		for _, msg := range messages {
+			batch = append(batch, &forum.ExportedChatMessage{
+				Id:        msg.ID,
+				ForumId:   msg.TenantID,
+				Text:      msg.Text,
+				CreatedAt: timestamppb.New(msg.CreatedAt),
+			})
+		}
+		if err := stream.Send(&forum.ExportUserContentResponse{ChatMessages: batch}); err != nil {
+			return err
+		}
+		after = messages[len(messages)-1].ID
+	}
+
+	return nil
+}
+
+// selfOrAdmin разрешает операцию над данными пользователя userID ему самому и администратору
+func selfOrAdmin(ctx context.Context, userID string) error {
+	callerID, ok := auth.UserIDFromContext(ctx)
+	if !ok {
+		return grpcerr.New(codes.Unauthenticated, errorDomain, reasonAuthRequired, "authentication required")
+	}
+	if role, _ := auth.RoleFromContext(ctx); callerID != userID && role != "admin" {
+		return grpcerr.New(codes.PermissionDenied, errorDomain, reasonForbidden, "not allowed to access this user's data")
+	}
+	return nil
+}
+
+// authorFromContext возвращает автора из контекста, который заполнил AuthInterceptor.
+// Автор не может быть задан в запросе: заполненный author_id отклоняется.
+func authorFromContext(ctx context.Context, requestedAuthorID string) (string, error) {
+	if requestedAuthorID != "" {
+		return "", invalidField("author_id", "author_id must not be set, author is taken from the authorization token")
+	}
+
+	userID, ok := auth.UserIDFromContext(ctx)
+	if !ok {
+		return "", grpcerr.New(codes.Unauthenticated, errorDomain, reasonAuthRequired, "authentication required")
+	}
+
+	return userID, nil
+}
+
+// inMask сообщает, меняется ли поле: пустая маска означает все поля
+func inMask(mask []string, field string) bool {
+	return len(mask) == 0 || slices.Contains(mask, field)
+}
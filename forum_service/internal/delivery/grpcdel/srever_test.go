@@ -0,0 +1,132 @@
+package grpcdel
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/kprf42/dolgova/forum_service/internal/entity"
+	"github.com/kprf42/dolgova/forum_service/internal/repository"
+	"github.com/kprf42/dolgova/forum_service/internal/usecase"
+	"github.com/kprf42/dolgova/forum_service/migrations"
+	"github.com/kprf42/dolgova/pkg/logger"
+	forum "github.com/kprf42/dolgova/proto/forum/v1"
+	_ "github.com/mattn/go-sqlite3"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Закрепленный старый пост не должен сбивать обход по курсору
+func TestGetPostsWithPinnedPost(t *testing.T) {
+	ctx := context.Background()
+	server, _ := newTestServer(t)
+
+	var got []string
+	token := ""
+	for page := 0; page < 10; page++ {
+		resp, err := server.GetPosts(ctx, &forum.GetPostsRequest{PageSize: 2, PageToken: token})
+		if err != nil {
+			t.Fatalf("get posts: %v", err)
+		}
+		for _, post := range resp.Posts {
+			got = append(got, post.Id)
+		}
+		if token = resp.NextPageToken; token == "" {
+			break
+		}
+	}
+
+	want := []string{"post-1", "post-2", "post-3", "post-4", "post-5"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("pages %v, want %v", got, want)
+	}
+}
+
+func TestStreamPostsWithPinnedPost(t *testing.T) {
+	server, now := newTestServer(t)
+
+	stream := &postStream{ctx: context.Background()}
+	err := server.StreamPosts(&forum.StreamPostsRequest{
+		CreatedAfter: timestamppb.New(now.Add(-210 * time.Minute)),
+		BatchSize:    2,
+	}, stream)
+	if err != nil {
+		t.Fatalf("stream posts: %v", err)
+	}
+
+	want := []string{"post-1", "post-2", "post-3"}
+	if !slices.Equal(stream.ids, want) {
+		t.Fatalf("streamed %v, want %v", stream.ids, want)
+	}
+}
+
+// newTestServer создает сервер поверх временной БД с постами post-1 (новый) ... post-5 (старый)
+// с интервалом в час; старый post-4 закреплен
+func newTestServer(t *testing.T) (*ForumServer, time.Time) {
+	t.Helper()
+	ctx := context.Background()
+
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "forum.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	migrator, err := migrations.NewMigrator(db)
+	if err != nil {
+		t.Fatalf("create migrator: %v", err)
+	}
+	if err := migrator.Up(); err != nil {
+		t.Fatalf("apply migrations: %v", err)
+	}
+
+	log, err := logger.NewWithConfig(logger.LogConfig{Level: "error", OutputPath: "stdout"})
+	if err != nil {
+		t.Fatalf("create logger: %v", err)
+	}
+
+	posts := repository.NewPostRepository(db, log)
+	now := time.Now().UTC().Truncate(time.Second)
+	for i := 1; i <= 5; i++ {
+		post := &entity.Post{
+			ID:         fmt.Sprintf("post-%d", i),
+			Title:      fmt.Sprintf("Post %d", i),
+			Content:    "content",
+			AuthorID:   "author",
+			CategoryID: "1",
+			Status:     entity.StatusPublished,
+			CreatedAt:  now.Add(-time.Duration(i) * time.Hour),
+		}
+		if err := posts.Create(ctx, post); err != nil {
+			t.Fatalf("create post %s: %v", post.ID, err)
+		}
+	}
+	if err := posts.SetPinned(ctx, "post-4", true); err != nil {
+		t.Fatalf("pin post: %v", err)
+	}
+
+	postUC := usecase.NewPostUseCase(posts, nil, nil, nil, nil, nil, nil, nil, nil, log)
+	return NewForumServer(postUC, nil, nil, nil, nil), now
+}
+
+// postStream собирает ID отправленных постов вместо отправки клиенту
+type postStream struct {
+	grpc.ServerStream
+	ctx context.Context
+	ids []string
+}
+
+func (s *postStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *postStream) Send(resp *forum.StreamPostsResponse) error {
+	for _, post := range resp.Posts {
+		s.ids = append(s.ids, post.Id)
+	}
+	return nil
+}
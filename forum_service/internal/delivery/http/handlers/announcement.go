@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/kprf42/dolgova/forum_service/internal/auth"
+	"github.com/kprf42/dolgova/forum_service/internal/entity"
+	announcementuc "github.com/kprf42/dolgova/forum_service/internal/usecase"
+)
+
+type AnnouncementHandlers struct {
+	uc *announcementuc.AnnouncementUseCase
+}
+
+func NewAnnouncementHandlers(uc *announcementuc.AnnouncementUseCase) *AnnouncementHandlers {
+	return &AnnouncementHandlers{uc: uc}
+}
+
+// Active возвращает объявления, которые сейчас нужно показать посетителю
+func (h *AnnouncementHandlers) Active(w http.ResponseWriter, r *http.Request) {
+	// Владельцы гостевых токенов видят объявления для анонимных посетителей
+	userID, _ := r.Context().Value("user_id").(string)
+	announcements, err := h.uc.Active(r.Context(), userID != "" && !auth.Scoped(r.Context()))
+	if err != nil {
+		WriteInternalError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(announcements)
+}
+
+// ListAnnouncements возвращает все объявления, включая будущие и завершенные
+func (h *AnnouncementHandlers) ListAnnouncements(w http.ResponseWriter, r *http.Request) {
+	announcements, err := h.uc.List(r.Context())
+	if err != nil {
+		WriteInternalError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(announcements)
+}
+
+func (h *AnnouncementHandlers) CreateAnnouncement(w http.ResponseWriter, r *http.Request) {
+	var req entity.AnnouncementRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest)
+		return
+	}
+
+	userID, _ := r.Context().Value("user_id").(string)
+	announcement, err := h.uc.Create(r.Context(), &req, userID)
+	if err != nil {
+		writeAnnouncementError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(announcement)
+}
+
+// UpdateAnnouncement заменяет объявление целиком
+func (h *AnnouncementHandlers) UpdateAnnouncement(w http.ResponseWriter, r *http.Request) {
+	var req entity.AnnouncementRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest)
+		return
+	}
+
+	announcement, err := h.uc.Update(r.Context(), chi.URLParam(r, "announcementId"), &req)
+	if err != nil {
+		writeAnnouncementError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(announcement)
+}
+
+func (h *AnnouncementHandlers) DeleteAnnouncement(w http.ResponseWriter, r *http.Request) {
+	if err := h.uc.Delete(r.Context(), chi.URLParam(r, "announcementId")); err != nil {
+		writeAnnouncementError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeAnnouncementError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, announcementuc.ErrInvalidAnnouncement):
+		WriteError(w, r, http.StatusBadRequest, ErrCodeInvalidAnnouncement)
+	case errors.Is(err, announcementuc.ErrAnnouncementNotFound):
+		WriteError(w, r, http.StatusNotFound, ErrCodeUnknownAnnouncement)
+	default:
+		WriteInternalError(w, r, err)
+	}
+}
@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	attachmentuc "github.com/kprf42/dolgova/forum_service/internal/usecase"
+)
+
+// MaxAttachmentSize максимальный размер загружаемого файла
+const MaxAttachmentSize = 10 << 20
+
+type AttachmentHandlers struct {
+	uc *attachmentuc.AttachmentUseCase
+}
+
+func NewAttachmentHandlers(uc *attachmentuc.AttachmentUseCase) *AttachmentHandlers {
+	return &AttachmentHandlers{uc: uc}
+}
+
+// UploadAttachment принимает файл в поле "file" формы multipart/form-data.
+// Возвращенный id передается в attachment_ids при создании поста, комментария или сообщения чата.
+func (h *AttachmentHandlers) UploadAttachment(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, MaxAttachmentSize+1<<20)
+
+	file, header, err := r.FormFile("file")
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		WriteError(w, r, http.StatusRequestEntityTooLarge, ErrCodeFileTooLarge)
+		return
+	}
+	if err != nil {
+		WriteError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest)
+		return
+	}
+	defer file.Close()
+
+	if header.Size > MaxAttachmentSize {
+		WriteError(w, r, http.StatusRequestEntityTooLarge, ErrCodeFileTooLarge)
+		return
+	}
+
+	attachment, err := h.uc.Upload(r.Context(), moderatorID(r), file)
+	if errors.Is(err, attachmentuc.ErrUnsupportedFile) {
+		WriteError(w, r, http.StatusUnsupportedMediaType, ErrCodeUnsupportedFile)
+		return
+	}
+	if err != nil {
+		WriteInternalError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(attachment)
+}
+
+// invalidAttachment сообщает, что в attachment_ids передано недоступное вложение
+func invalidAttachment(err error) bool {
+	return errors.Is(err, attachmentuc.ErrInvalidAttachment)
+}
@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/kprf42/dolgova/pkg/audit"
+)
+
+type AuditHandlers struct {
+	store *audit.Store
+}
+
+func NewAuditHandlers(store *audit.Store) *AuditHandlers {
+	return &AuditHandlers{store: store}
+}
+
+// ListEntries возвращает журнал аудита: ?actor_id=&acting_admin_id=&action=&limit=&offset=
+func (h *AuditHandlers) ListEntries(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	limit, _ := strconv.Atoi(query.Get("limit"))
+	offset, _ := strconv.Atoi(query.Get("offset"))
+
+	if limit <= 0 || limit > 100 {
+		limit = 50
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	entries, err := h.store.List(r.Context(), audit.Filter{
+		ActorID:       query.Get("actor_id"),
+		ActingAdminID: query.Get("acting_admin_id"),
+		Action:        query.Get("action"),
+		Limit:         limit,
+		Offset:        offset,
+	})
+	if err != nil {
+		WriteInternalError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(entries)
+}
@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	blockuc "github.com/kprf42/dolgova/forum_service/internal/usecase"
+)
+
+type BlockHandlers struct {
+	uc *blockuc.BlockUseCase
+}
+
+func NewBlockHandlers(uc *blockuc.BlockUseCase) *BlockHandlers {
+	return &BlockHandlers{uc: uc}
+}
+
+// ListBlocks возвращает пользователей, заблокированных текущим пользователем
+func (h *BlockHandlers) ListBlocks(w http.ResponseWriter, r *http.Request) {
+	userID, _ := r.Context().Value("user_id").(string)
+	blocks, err := h.uc.List(r.Context(), userID)
+	if err != nil {
+		WriteInternalError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(blocks)
+}
+
+// BlockUser блокирует пользователя: его посты, комментарии и сообщения чата скрываются
+func (h *BlockHandlers) BlockUser(w http.ResponseWriter, r *http.Request) {
+	userID, _ := r.Context().Value("user_id").(string)
+	block, err := h.uc.Block(r.Context(), userID, chi.URLParam(r, "userId"))
+	switch {
+	case errors.Is(err, blockuc.ErrCannotBlockSelf):
+		WriteError(w, r, http.StatusBadRequest, ErrCodeCannotBlockSelf)
+		return
+	case errors.Is(err, blockuc.ErrUserNotFound):
+		WriteError(w, r, http.StatusNotFound, ErrCodeUserNotFound)
+		return
+	case err != nil:
+		WriteInternalError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(block)
+}
+
+// UnblockUser снимает блокировку пользователя
+func (h *BlockHandlers) UnblockUser(w http.ResponseWriter, r *http.Request) {
+	userID, _ := r.Context().Value("user_id").(string)
+	err := h.uc.Unblock(r.Context(), userID, chi.URLParam(r, "userId"))
+	if errors.Is(err, blockuc.ErrBlockNotFound) {
+		WriteError(w, r, http.StatusNotFound, ErrCodeBlockNotFound)
+		return
+	}
+	if err != nil {
+		WriteInternalError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/kprf42/dolgova/forum_service/internal/entity"
+	categoryuc "github.com/kprf42/dolgova/forum_service/internal/usecase"
+)
+
+type CategoryHandlers struct {
+	uc *categoryuc.CategoryUseCase
+}
+
+func NewCategoryHandlers(uc *categoryuc.CategoryUseCase) *CategoryHandlers {
+	return &CategoryHandlers{uc: uc}
+}
+
+// ListCategories возвращает категории сообщества по порядку
+func (h *CategoryHandlers) ListCategories(w http.ResponseWriter, r *http.Request) {
+	categories, err := h.uc.List(r.Context())
+	if err != nil {
+		WriteInternalError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(categories)
+}
+
+// CategoryTree возвращает дерево категорий: корневые категории с вложенными children
+func (h *CategoryHandlers) CategoryTree(w http.ResponseWriter, r *http.Request) {
+	tree, err := h.uc.Tree(r.Context())
+	if err != nil {
+		WriteInternalError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(tree)
+}
+
+func (h *CategoryHandlers) CreateCategory(w http.ResponseWriter, r *http.Request) {
+	var req entity.CategoryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest)
+		return
+	}
+
+	category, err := h.uc.Create(r.Context(), &req)
+	if err != nil {
+		writeCategoryError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(category)
+}
+
+// UpdateCategory заменяет категорию целиком
+func (h *CategoryHandlers) UpdateCategory(w http.ResponseWriter, r *http.Request) {
+	var req entity.CategoryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest)
+		return
+	}
+
+	category, err := h.uc.Update(r.Context(), chi.URLParam(r, "categoryId"), &req)
+	if err != nil {
+		writeCategoryError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(category)
+}
+
+// DeleteCategory удаляет пустую категорию; категорию с постами или подкатегориями удалить нельзя
+func (h *CategoryHandlers) DeleteCategory(w http.ResponseWriter, r *http.Request) {
+	if err := h.uc.Delete(r.Context(), chi.URLParam(r, "categoryId")); err != nil {
+		writeCategoryError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeCategoryError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, categoryuc.ErrInvalidCategory):
+		WriteError(w, r, http.StatusBadRequest, ErrCodeInvalidCategoryData)
+	case errors.Is(err, categoryuc.ErrInvalidCategoryParent):
+		WriteError(w, r, http.StatusBadRequest, ErrCodeInvalidParent)
+	case errors.Is(err, categoryuc.ErrCategoryNotFound):
+		WriteError(w, r, http.StatusNotFound, ErrCodeCategoryNotFound)
+	case errors.Is(err, categoryuc.ErrCategoryNotEmpty):
+		WriteError(w, r, http.StatusConflict, ErrCodeCategoryNotEmpty)
+	default:
+		WriteInternalError(w, r, err)
+	}
+}
+
+// checkCategory проверяет, что категория из запроса существует, и иначе отвечает invalid_category.
+// Возвращает false, если ответ уже отправлен.
+func checkCategory(w http.ResponseWriter, r *http.Request, categories *categoryuc.CategoryUseCase, categoryID string) bool {
+	err := categories.Check(r.Context(), categoryID)
+	if errors.Is(err, categoryuc.ErrUnknownCategory) {
+		WriteError(w, r, http.StatusBadRequest, ErrCodeInvalidCategory)
+		return false
+	}
+	if err != nil {
+		WriteInternalError(w, r, err)
+		return false
+	}
+	return true
+}
@@ -5,26 +5,34 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/kprf42/dolgova/forum_service/internal/auth"
 	"github.com/kprf42/dolgova/forum_service/internal/delivery/websocket"
-	"github.com/kprf42/dolgova/forum_service/internal/entity"
 	chat "github.com/kprf42/dolgova/forum_service/internal/usecase"
 )
 
 type ChatHandlers struct {
-	hub    *websocket.Hub
-	chatUC *chat.ChatUseCase
+	hub         *websocket.Hub
+	chatUC      *chat.ChatUseCase
+	checkOrigin func(r *http.Request) bool
 }
 
-func NewChatHandlers(hub *websocket.Hub, chatUC *chat.ChatUseCase) *ChatHandlers {
+// NewChatHandlers создает обработчики чата. checkOrigin проверяет Origin запроса на подключение к чату.
+func NewChatHandlers(hub *websocket.Hub, chatUC *chat.ChatUseCase, checkOrigin func(r *http.Request) bool) *ChatHandlers {
 	return &ChatHandlers{
-		hub:    hub,
-		chatUC: chatUC,
+		hub:         hub,
+		chatUC:      chatUC,
+		checkOrigin: checkOrigin,
 	}
 }
 
 func (h *ChatHandlers) Connect(w http.ResponseWriter, r *http.Request) {
-	claims := r.Context().Value("claims").(*entity.Claims)
-	websocket.ServeWs(h.hub, w, r, claims.UserID)
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok || userID == "" {
+		WriteError(w, r, http.StatusUnauthorized, ErrCodeUnauthorized)
+		return
+	}
+	// С ограниченным (гостевым) токеном чат можно только читать
+	websocket.ServeWs(h.hub, w, r, h.checkOrigin, userID, auth.Scoped(r.Context()))
 }
 
 func (h *ChatHandlers) GetMessages(w http.ResponseWriter, r *http.Request) {
@@ -38,9 +46,9 @@ func (h *ChatHandlers) GetMessages(w http.ResponseWriter, r *http.Request) {
 		offset = 0
 	}
 
-	messages, err := h.chatUC.GetMessages(r.Context(), limit, offset)
+	messages, err := h.chatUC.GetMessages(r.Context(), limit, offset, nil)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		WriteInternalError(w, r, err)
 		return
 	}
 
@@ -1,8 +1,9 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
-	"fmt"
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -10,6 +11,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/kprf42/dolgova/forum_service/internal/entity"
 	comment "github.com/kprf42/dolgova/forum_service/internal/usecase"
+	"github.com/kprf42/dolgova/pkg/logger"
 )
 
 type CommentHandlers struct {
@@ -21,74 +23,173 @@ func NewCommentHandlers(uc *comment.CommentUseCase) *CommentHandlers {
 }
 
 func (h *CommentHandlers) CreateComment(w http.ResponseWriter, r *http.Request) {
-	fmt.Printf("\n=== CreateComment Handler ===\n")
-	fmt.Printf("Request URL: %s\n", r.URL.String())
+	log := logger.FromContext(r.Context())
 
 	// Получаем postID из URL
 	postID := chi.URLParam(r, "postId")
-	fmt.Printf("Post ID from URL: '%s'\n", postID)
-
-	// Проверяем UUID
 	if _, err := uuid.Parse(postID); err != nil {
-		fmt.Printf("ERROR: Invalid UUID format. Input: '%s', Error: %v\n", postID, err)
-		fmt.Printf("Expected format example: 550e8400-e29b-41d4-a716-446655440000\n")
-		http.Error(w, fmt.Sprintf("invalid post id format: must be a valid UUID"), http.StatusBadRequest)
+		WriteError(w, r, http.StatusBadRequest, ErrCodeInvalidPostID)
 		return
 	}
 
 	// Декодируем тело запроса
 	var req entity.CommentRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		fmt.Printf("ERROR: Failed to decode request body: %v\n", err)
-		http.Error(w, "invalid request body", http.StatusBadRequest)
+		log.Debug("Failed to decode comment request", logger.Error(err))
+		WriteError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest)
 		return
 	}
 	req.PostID = postID
-	fmt.Printf("Request body decoded: %+v\n", req)
 
 	// Получаем user_id из контекста
 	userID, ok := r.Context().Value("user_id").(string)
 	if !ok || userID == "" {
-		fmt.Printf("ERROR: Failed to get user_id from context\n")
-		http.Error(w, "unauthorized: missing user_id", http.StatusUnauthorized)
+		WriteError(w, r, http.StatusUnauthorized, ErrCodeUnauthorized)
 		return
 	}
-	fmt.Printf("User ID from context: %s\n", userID)
 
 	// Создаем комментарий
 	comment, err := h.uc.Create(r.Context(), &req, userID)
 	if err != nil {
-		fmt.Printf("ERROR: Failed to create comment: %v\n", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		log.Debug("Failed to create comment",
+			logger.String("post_id", postID),
+			logger.String("user_id", userID),
+			logger.Error(err))
+		if invalidAttachment(err) {
+			WriteError(w, r, http.StatusBadRequest, ErrCodeInvalidAttachment)
+			return
+		}
+		if notEnoughKarma(err) {
+			WriteError(w, r, http.StatusForbidden, ErrCodeNotEnoughKarma)
+			return
+		}
+		if postLocked(err) {
+			WriteError(w, r, http.StatusConflict, ErrCodePostLocked)
+			return
+		}
+		WriteInternalError(w, r, err)
 		return
 	}
 
-	fmt.Printf("Successfully created comment: %+v\n", comment)
-
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
 	if err := json.NewEncoder(w).Encode(comment); err != nil {
-		fmt.Printf("ERROR: Failed to encode response: %v\n", err)
-		http.Error(w, "error encoding response", http.StatusInternalServerError)
+		log.Error("Failed to encode comment",
+			logger.String("comment_id", comment.ID),
+			logger.Error(err))
+	}
+}
+
+// DeleteComment удаляет комментарий. Удалить может автор или модератор категории поста.
+func (h *CommentHandlers) DeleteComment(w http.ResponseWriter, r *http.Request) {
+	commentID := chi.URLParam(r, "commentId")
+
+	if _, err := uuid.Parse(commentID); err != nil {
+		WriteError(w, r, http.StatusNotFound, ErrCodeCommentNotFound)
+		return
+	}
+
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok || userID == "" {
+		WriteError(w, r, http.StatusUnauthorized, ErrCodeUnauthorized)
+		return
+	}
+
+	if err := h.uc.Delete(r.Context(), commentID, userID); err != nil {
+		status, code := http.StatusInternalServerError, ErrCodeInternal
+		switch err.Error() {
+		case "unauthorized":
+			status, code = http.StatusForbidden, ErrCodeForbidden
+		case "comment not found":
+			status, code = http.StatusNotFound, ErrCodeCommentNotFound
+		}
+		if errors.Is(err, context.DeadlineExceeded) {
+			status, code = http.StatusGatewayTimeout, ErrCodeTimeout
+		}
+		WriteError(w, r, status, code)
 		return
 	}
 
-	fmt.Printf("=== End CreateComment Handler ===\n\n")
+	w.WriteHeader(http.StatusNoContent)
 }
 
-func (h *CommentHandlers) GetComments(w http.ResponseWriter, r *http.Request) {
-	// Добавьте отладочный вывод
-	fmt.Println("\n=== GetComments Handler ===")
-	fmt.Printf("Request URL: %s\n", r.URL.String())
+// VoteComment голосует за комментарий: {"value": 1} - за, -1 - против, 0 - снять голос
+func (h *CommentHandlers) VoteComment(w http.ResponseWriter, r *http.Request) {
+	commentID := chi.URLParam(r, "commentId")
+	if _, err := uuid.Parse(commentID); err != nil {
+		WriteError(w, r, http.StatusNotFound, ErrCodeCommentNotFound)
+		return
+	}
+
+	var req entity.CommentVoteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest)
+		return
+	}
 
+	userID, _ := r.Context().Value("user_id").(string)
+	vote, err := h.uc.Vote(r.Context(), commentID, userID, req.Value)
+	if err != nil {
+		switch {
+		case errors.Is(err, comment.ErrInvalidVote):
+			WriteError(w, r, http.StatusBadRequest, ErrCodeInvalidVote)
+		case errors.Is(err, comment.ErrCommentNotFound):
+			WriteError(w, r, http.StatusNotFound, ErrCodeCommentNotFound)
+		default:
+			WriteInternalError(w, r, err)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(vote)
+}
+
+// AcceptAnswer отмечает комментарий принятым ответом. Отмечать может только автор поста.
+func (h *CommentHandlers) AcceptAnswer(w http.ResponseWriter, r *http.Request) {
+	h.acceptAnswer(w, r, true)
+}
+
+// UnacceptAnswer снимает с комментария отметку принятого ответа
+func (h *CommentHandlers) UnacceptAnswer(w http.ResponseWriter, r *http.Request) {
+	h.acceptAnswer(w, r, false)
+}
+
+func (h *CommentHandlers) acceptAnswer(w http.ResponseWriter, r *http.Request, accepted bool) {
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok || userID == "" {
+		WriteError(w, r, http.StatusUnauthorized, ErrCodeUnauthorized)
+		return
+	}
+
+	postID, commentID := chi.URLParam(r, "postId"), chi.URLParam(r, "commentId")
+	answer, err := h.uc.AcceptAnswer(r.Context(), postID, commentID, userID, accepted)
+	if err != nil {
+		status, code := http.StatusInternalServerError, ErrCodeInternal
+		switch err.Error() {
+		case "unauthorized":
+			status, code = http.StatusForbidden, ErrCodeForbidden
+		case "post not found":
+			status, code = http.StatusNotFound, ErrCodePostNotFound
+		case "comment not found":
+			status, code = http.StatusNotFound, ErrCodeCommentNotFound
+		}
+		if errors.Is(err, context.DeadlineExceeded) {
+			status, code = http.StatusGatewayTimeout, ErrCodeTimeout
+		}
+		WriteError(w, r, status, code)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(answer)
+}
+
+func (h *CommentHandlers) GetComments(w http.ResponseWriter, r *http.Request) {
 	// Получаем postID из URL
 	postID := chi.URLParam(r, "postId")
-	fmt.Printf("Extracted postID: '%s'\n", postID)
-
-	// Проверяем UUID
 	if _, err := uuid.Parse(postID); err != nil {
-		fmt.Printf("Invalid UUID: %v\n", err)
-		http.Error(w, "invalid post id", http.StatusBadRequest)
+		WriteError(w, r, http.StatusBadRequest, ErrCodeInvalidPostID)
 		return
 	}
 
@@ -103,18 +204,16 @@ func (h *CommentHandlers) GetComments(w http.ResponseWriter, r *http.Request) {
 		offset = 0
 	}
 
-	fmt.Printf("Query params: limit=%d, offset=%d\n", limit, offset)
-
 	// Получаем комментарии
-	comments, total, err := h.uc.GetByPostID(r.Context(), postID, limit, offset)
+	comments, total, err := h.uc.GetByPostID(r.Context(), postID, limit, offset, nil)
 	if err != nil {
-		fmt.Printf("Error getting comments: %v\n", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		logger.FromContext(r.Context()).Error("Failed to get comments",
+			logger.String("post_id", postID),
+			logger.Error(err))
+		WriteInternalError(w, r, err)
 		return
 	}
 
-	fmt.Printf("Found %d comments (total: %d)\n", len(comments), total)
-
 	// Формируем ответ
 	response := struct {
 		Comments []*entity.Comment `json:"comments"`
@@ -125,13 +224,7 @@ func (h *CommentHandlers) GetComments(w http.ResponseWriter, r *http.Request) {
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(response); err != nil {
-		fmt.Printf("Error encoding response: %v\n", err)
-		http.Error(w, "error encoding response", http.StatusInternalServerError)
-		return
-	}
-
-	fmt.Println("=== End GetComments Handler ===")
+	json.NewEncoder(w).Encode(response)
 }
 
 // func (h *CommentHandlers) GetComments(w http.ResponseWriter, r *http.Request) {
@@ -168,3 +261,8 @@ func (h *CommentHandlers) GetComments(w http.ResponseWriter, r *http.Request) {
 // 	w.Header().Set("Content-Type", "application/json")
 // 	json.NewEncoder(w).Encode(response)
 // }
+
+// postLocked тема поста закрыта модератором
+func postLocked(err error) bool {
+	return errors.Is(err, comment.ErrPostLocked)
+}
@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/kprf42/dolgova/pkg/i18n"
+)
+
+// Коды ошибок API. Код передается в заголовке X-Error-Code, текст переводится по Accept-Language.
+const (
+	ErrCodeInvalidRequest      = "invalid_request"
+	ErrCodeInvalidCategory     = "invalid_category"
+	ErrCodeUnauthorized        = "unauthorized"
+	ErrCodeForbidden           = "forbidden"
+	ErrCodeTokenRequired       = "token_required"
+	ErrCodeBearerRequired      = "bearer_required"
+	ErrCodeInvalidToken        = "invalid_token"
+	ErrCodeTokenExpired        = "token_expired"
+	ErrCodeAuthUnavailable     = "auth_unavailable"
+	ErrCodeAccountSuspended    = "account_suspended"
+	ErrCodeInsufficientScope   = "insufficient_scope"
+	ErrCodePostIDRequired      = "post_id_required"
+	ErrCodeInvalidPostID       = "invalid_post_id"
+	ErrCodePostNotFound        = "post_not_found"
+	ErrCodeNotAuthor           = "not_author"
+	ErrCodeInvalidDate         = "invalid_date"
+	ErrCodeInvalidDateRange    = "invalid_date_range"
+	ErrCodeUnknownForum        = "unknown_forum"
+	ErrCodeSearchQueryRequired = "search_query_required"
+	ErrCodeSearchUnavailable   = "search_unavailable"
+	ErrCodeIPBanned            = "ip_banned"
+	ErrCodeInvalidIPBan        = "invalid_ip_ban"
+	ErrCodeIPBanNotFound       = "ip_ban_not_found"
+	ErrCodeContentNotFound     = "content_not_found"
+	ErrCodeNotPending          = "not_pending"
+	ErrCodeUserNotFound        = "user_not_found"
+	ErrCodeModeratorNotFound   = "moderator_not_found"
+	ErrCodeCommentNotFound     = "comment_not_found"
+	ErrCodeTimeout             = "timeout"
+	ErrCodeInvalidAttachment   = "invalid_attachment"
+	ErrCodeUnsupportedFile     = "unsupported_file"
+	ErrCodeFileTooLarge        = "file_too_large"
+	ErrCodeInvalidImage        = "invalid_image"
+	ErrCodeNotEnoughKarma      = "not_enough_karma"
+	ErrCodeCannotBlockSelf     = "cannot_block_self"
+	ErrCodeBlockNotFound       = "block_not_found"
+	ErrCodeShareLinkNotFound   = "share_link_not_found"
+	ErrCodeInvalidAnnouncement = "invalid_announcement"
+	ErrCodeUnknownAnnouncement = "announcement_not_found"
+	ErrCodeInvalidCategoryData = "invalid_category_data"
+	ErrCodeInvalidParent       = "invalid_category_parent"
+	ErrCodeCategoryNotFound    = "category_not_found"
+	ErrCodeCategoryNotEmpty    = "category_not_empty"
+	ErrCodeInvalidVote         = "invalid_vote"
+	ErrCodeInvalidSort         = "invalid_sort"
+	ErrCodePostLocked          = "post_locked"
+	ErrCodeInvalidReport       = "invalid_report"
+	ErrCodeAlreadyReported     = "already_reported"
+	ErrCodeInternal            = "internal_error"
+)
+
+// ErrorCodeHeader заголовок с машиночитаемым кодом ошибки
+const ErrorCodeHeader = "X-Error-Code"
+
+var messages = i18n.NewBundle(i18n.EN).
+	Add(i18n.EN, map[string]string{
+		ErrCodeInvalidRequest:      "invalid request body",
+		ErrCodeInvalidCategory:     "invalid category_id: category does not exist",
+		ErrCodeUnauthorized:        "unauthorized",
+		ErrCodeForbidden:           "forbidden",
+		ErrCodeTokenRequired:       "authorization header is required",
+		ErrCodeBearerRequired:      "bearer token required",
+		ErrCodeInvalidToken:        "invalid token",
+		ErrCodeTokenExpired:        "token has expired",
+		ErrCodeAuthUnavailable:     "authentication service is temporarily unavailable",
+		ErrCodeAccountSuspended:    "account is suspended",
+		ErrCodeInsufficientScope:   "guest access is read-only, log in to do this",
+		ErrCodePostIDRequired:      "post id is required",
+		ErrCodeInvalidPostID:       "invalid post id format: must be a valid UUID",
+		ErrCodePostNotFound:        "post not found",
+		ErrCodeNotAuthor:           "only the author can change this post",
+		ErrCodeInvalidDate:         "invalid date, expected YYYY-MM-DD",
+		ErrCodeInvalidDateRange:    "invalid date range",
+		ErrCodeUnknownForum:        "unknown forum",
+		ErrCodeSearchQueryRequired: "search query parameter q is required",
+		ErrCodeSearchUnavailable:   "search is unavailable",
+		ErrCodeIPBanned:            "access from your address is blocked",
+		ErrCodeInvalidIPBan:        "invalid ban: expected IP address or CIDR and a positive duration",
+		ErrCodeIPBanNotFound:       "ip ban not found",
+		ErrCodeContentNotFound:     "post or comment not found",
+		ErrCodeNotPending:          "content is not pending moderation",
+		ErrCodeUserNotFound:        "user not found",
+		ErrCodeModeratorNotFound:   "user is not a moderator of this category",
+		ErrCodeCommentNotFound:     "comment not found",
+		ErrCodeTimeout:             "the request took too long, try again later",
+		ErrCodeInvalidAttachment:   "attachment not found or already used",
+		ErrCodeUnsupportedFile:     "unsupported file type",
+		ErrCodeFileTooLarge:        "file is too large",
+		ErrCodeInvalidImage:        "the file is not a valid image or its dimensions are too large",
+		ErrCodeNotEnoughKarma:      "not enough karma to post links",
+		ErrCodeCannotBlockSelf:     "you cannot block yourself",
+		ErrCodeBlockNotFound:       "user is not blocked",
+		ErrCodeShareLinkNotFound:   "link not found",
+		ErrCodeInvalidAnnouncement: "invalid announcement: title is required, severity must be info, warning or critical, audience all, users or guests, and ends_at must be after starts_at",
+		ErrCodeUnknownAnnouncement: "announcement not found",
+		ErrCodeInvalidCategoryData: "invalid category: name is required and must be at most 100 characters",
+		ErrCodeInvalidParent:       "invalid parent_id: the parent must exist, must not be the category itself or its subcategory, and subcategories can be nested at most 4 levels deep",
+		ErrCodeCategoryNotFound:    "category not found",
+		ErrCodeCategoryNotEmpty:    "category has posts or subcategories and cannot be deleted",
+		ErrCodeInvalidVote:         "invalid vote: value must be 1, -1 or 0",
+		ErrCodeInvalidSort:         "invalid sort: sort must be new, top, hot or score, and window (only for top) must be hour, day, week, month, year or all",
+		ErrCodePostLocked:          "post is locked: new comments are not accepted",
+		ErrCodeInvalidReport:       "invalid report: reason must be spam, abuse, off_topic, illegal or other, and details must be at most 1000 characters",
+		ErrCodeAlreadyReported:     "you have already reported this content",
+		ErrCodeInternal:            "internal server error",
+	}).
+	Add(i18n.RU, map[string]string{
+		ErrCodeInvalidRequest:      "некорректное тело запроса",
+		ErrCodeInvalidCategory:     "некорректная категория: такой категории нет",
+		ErrCodeUnauthorized:        "требуется авторизация",
+		ErrCodeForbidden:           "доступ запрещен",
+		ErrCodeTokenRequired:       "требуется заголовок Authorization",
+		ErrCodeBearerRequired:      "требуется токен Bearer",
+		ErrCodeInvalidToken:        "недействительный токен",
+		ErrCodeTokenExpired:        "срок действия токена истек",
+		ErrCodeAuthUnavailable:     "сервис аутентификации временно недоступен",
+		ErrCodeAccountSuspended:    "аккаунт приостановлен",
+		ErrCodeInsufficientScope:   "гостевой доступ только для чтения, войдите, чтобы продолжить",
+		ErrCodePostIDRequired:      "не указан id поста",
+		ErrCodeInvalidPostID:       "некорректный id поста: ожидается UUID",
+		ErrCodePostNotFound:        "пост не найден",
+		ErrCodeNotAuthor:           "изменять пост может только автор",
+		ErrCodeInvalidDate:         "некорректная дата, ожидается ГГГГ-ММ-ДД",
+		ErrCodeInvalidDateRange:    "некорректный диапазон дат",
+		ErrCodeUnknownForum:        "форум не найден",
+		ErrCodeSearchQueryRequired: "не указан поисковый запрос q",
+		ErrCodeSearchUnavailable:   "поиск недоступен",
+		ErrCodeIPBanned:            "доступ с вашего адреса заблокирован",
+		ErrCodeInvalidIPBan:        "некорректный бан: ожидается IP адрес или подсеть и положительный срок",
+		ErrCodeIPBanNotFound:       "бан не найден",
+		ErrCodeContentNotFound:     "пост или комментарий не найден",
+		ErrCodeNotPending:          "контент не ожидает модерации",
+		ErrCodeUserNotFound:        "пользователь не найден",
+		ErrCodeModeratorNotFound:   "пользователь не модерирует эту категорию",
+		ErrCodeCommentNotFound:     "комментарий не найден",
+		ErrCodeTimeout:             "запрос выполнялся слишком долго, повторите позже",
+		ErrCodeInvalidAttachment:   "вложение не найдено или уже использовано",
+		ErrCodeUnsupportedFile:     "неподдерживаемый тип файла",
+		ErrCodeFileTooLarge:        "файл слишком большой",
+		ErrCodeInvalidImage:        "файл не является изображением или его размеры слишком велики",
+		ErrCodeNotEnoughKarma:      "недостаточно кармы для публикации ссылок",
+		ErrCodeCannotBlockSelf:     "нельзя заблокировать самого себя",
+		ErrCodeBlockNotFound:       "пользователь не заблокирован",
+		ErrCodeShareLinkNotFound:   "ссылка не найдена",
+		ErrCodeInvalidAnnouncement: "некорректное объявление: нужен заголовок, важность info, warning или critical, аудитория all, users или guests, а ends_at позже starts_at",
+		ErrCodeUnknownAnnouncement: "объявление не найдено",
+		ErrCodeInvalidCategoryData: "некорректная категория: нужно название не длиннее 100 символов",
+		ErrCodeInvalidParent:       "некорректный parent_id: родитель должен существовать, не может быть самой категорией или ее подкатегорией, а вложенность подкатегорий не больше 4 уровней",
+		ErrCodeCategoryNotFound:    "категория не найдена",
+		ErrCodeCategoryNotEmpty:    "в категории есть посты или подкатегории, удалить ее нельзя",
+		ErrCodeInvalidVote:         "некорректный голос: value должно быть 1, -1 или 0",
+		ErrCodeInvalidSort:         "некорректная сортировка: sort должен быть new, top, hot или score, а window (только для top) - hour, day, week, month, year или all",
+		ErrCodePostLocked:          "тема закрыта: новые комментарии не принимаются",
+		ErrCodeInvalidReport:       "некорректная жалоба: причина должна быть spam, abuse, off_topic, illegal или other, а пояснение не длиннее 1000 символов",
+		ErrCodeAlreadyReported:     "вы уже пожаловались на эту публикацию",
+		ErrCodeInternal:            "внутренняя ошибка сервера",
+	})
+
+// WriteError отправляет ошибку с кодом и текстом на языке клиента
+func WriteError(w http.ResponseWriter, r *http.Request, status int, code string) {
+	lang := messages.Lang(r)
+	w.Header().Set(ErrorCodeHeader, code)
+	w.Header().Set("Content-Language", lang)
+	http.Error(w, messages.Message(lang, code), status)
+}
+
+// WriteInternalError отправляет 504, если запрос к базе не уложился в отведенное время, иначе 500
+func WriteInternalError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, context.DeadlineExceeded) {
+		WriteError(w, r, http.StatusGatewayTimeout, ErrCodeTimeout)
+		return
+	}
+	WriteError(w, r, http.StatusInternalServerError, ErrCodeInternal)
+}
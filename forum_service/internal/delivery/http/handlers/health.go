@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/kprf42/dolgova/forum_service/migrations"
+)
+
+// healthCheckTimeout ограничивает проверку одной зависимости
+const healthCheckTimeout = 2 * time.Second
+
+// Состояния сервиса и зависимостей
+const (
+	HealthOK       = "ok"
+	HealthDegraded = "degraded"
+	HealthError    = "error"
+)
+
+// HealthCheck проверка одной зависимости сервиса
+type HealthCheck struct {
+	Name  string
+	Check func(ctx context.Context) error
+	// Optional зависимость без которой сервис частично работает: ее отказ дает degraded, а не 503
+	Optional bool
+}
+
+type HealthHandlers struct {
+	migrator *migrations.Migrator
+	checks   []HealthCheck
+}
+
+func NewHealthHandlers(migrator *migrations.Migrator, checks ...HealthCheck) *HealthHandlers {
+	return &HealthHandlers{migrator: migrator, checks: checks}
+}
+
+// DependencyStatus результат проверки зависимости
+type DependencyStatus struct {
+	Status    string `json:"status"`
+	LatencyMs int64  `json:"latency_ms"`
+	Optional  bool   `json:"optional,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+type HealthResponse struct {
+	Status       string                      `json:"status"`
+	Dependencies map[string]DependencyStatus `json:"dependencies,omitempty"`
+	Migrations   *migrations.Status          `json:"migrations,omitempty"`
+	Error        string                      `json:"error,omitempty"`
+}
+
+// Health сообщает состояние сервиса, схемы БД и зависимостей (проверяются параллельно).
+// Незавершенные (dirty) или непримененные миграции и отказ обязательной зависимости
+// возвращают 503, чтобы деплой не пускал трафик на неработающий экземпляр.
+func (h *HealthHandlers) Health(w http.ResponseWriter, r *http.Request) {
+	response := HealthResponse{
+		Status:       HealthOK,
+		Dependencies: h.checkDependencies(r.Context()),
+	}
+	statusCode := http.StatusOK
+
+	for _, dep := range response.Dependencies {
+		if dep.Status == HealthOK {
+			continue
+		}
+		if !dep.Optional {
+			response.Status = HealthError
+			statusCode = http.StatusServiceUnavailable
+		} else if response.Status == HealthOK {
+			response.Status = HealthDegraded
+		}
+	}
+
+	status, err := h.migrator.Status()
+	switch {
+	case err != nil:
+		response.Status = HealthError
+		response.Error = err.Error()
+		statusCode = http.StatusServiceUnavailable
+	case status.Dirty || status.Pending > 0:
+		if response.Status == HealthOK {
+			response.Status = HealthDegraded
+		}
+		response.Migrations = status
+		statusCode = http.StatusServiceUnavailable
+	default:
+		response.Migrations = status
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *HealthHandlers) checkDependencies(ctx context.Context) map[string]DependencyStatus {
+	results := make(map[string]DependencyStatus, len(h.checks))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	for _, check := range h.checks {
+		wg.Add(1)
+		go func(check HealthCheck) {
+			defer wg.Done()
+
+			ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+			defer cancel()
+
+			start := time.Now()
+			err := check.Check(ctx)
+			result := DependencyStatus{
+				Status:    HealthOK,
+				LatencyMs: time.Since(start).Milliseconds(),
+				Optional:  check.Optional,
+			}
+			if err != nil {
+				result.Status = HealthError
+				result.Error = err.Error()
+			}
+
+			mu.Lock()
+			results[check.Name] = result
+			mu.Unlock()
+		}(check)
+	}
+
+	wg.Wait()
+	return results
+}
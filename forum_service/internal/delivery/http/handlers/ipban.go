@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/kprf42/dolgova/pkg/ipban"
+)
+
+type IPBanHandlers struct {
+	store   *ipban.Store
+	checker *ipban.Checker
+}
+
+func NewIPBanHandlers(store *ipban.Store, checker *ipban.Checker) *IPBanHandlers {
+	return &IPBanHandlers{store: store, checker: checker}
+}
+
+// IPBanRequest запрос на бан. Срок задается либо expires_at, либо duration ("72h"); без них бан бессрочный.
+type IPBanRequest struct {
+	Address   string     `json:"address"` // IP адрес или подсеть в нотации CIDR
+	Reason    string     `json:"reason"`
+	ExpiresAt *time.Time `json:"expires_at"`
+	Duration  string     `json:"duration"`
+}
+
+// ListBans возвращает все баны, включая истекшие
+func (h *IPBanHandlers) ListBans(w http.ResponseWriter, r *http.Request) {
+	bans, err := h.store.List(r.Context())
+	if err != nil {
+		WriteInternalError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(bans)
+}
+
+// CreateBan добавляет бан и сразу применяет его
+func (h *IPBanHandlers) CreateBan(w http.ResponseWriter, r *http.Request) {
+	var req IPBanRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest)
+		return
+	}
+
+	if _, err := ipban.ParsePrefix(req.Address); err != nil {
+		WriteError(w, r, http.StatusBadRequest, ErrCodeInvalidIPBan)
+		return
+	}
+
+	expiresAt := req.ExpiresAt
+	if req.Duration != "" {
+		duration, err := time.ParseDuration(req.Duration)
+		if err != nil || duration <= 0 || expiresAt != nil {
+			WriteError(w, r, http.StatusBadRequest, ErrCodeInvalidIPBan)
+			return
+		}
+		t := time.Now().Add(duration)
+		expiresAt = &t
+	}
+
+	userID, _ := r.Context().Value("user_id").(string)
+	ban := &ipban.Ban{
+		CIDR:      req.Address,
+		Reason:    req.Reason,
+		CreatedBy: userID,
+		ExpiresAt: expiresAt,
+	}
+	if err := h.store.Add(r.Context(), ban); err != nil {
+		WriteInternalError(w, r, err)
+		return
+	}
+	h.checker.Refresh(r.Context())
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(ban)
+}
+
+// DeleteBan снимает бан
+func (h *IPBanHandlers) DeleteBan(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "banId"), 10, 64)
+	if err != nil {
+		WriteError(w, r, http.StatusBadRequest, ErrCodeInvalidIPBan)
+		return
+	}
+
+	err = h.store.Delete(r.Context(), id)
+	if errors.Is(err, ipban.ErrNotFound) {
+		WriteError(w, r, http.StatusNotFound, ErrCodeIPBanNotFound)
+		return
+	}
+	if err != nil {
+		WriteInternalError(w, r, err)
+		return
+	}
+	h.checker.Refresh(r.Context())
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,220 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	moderationuc "github.com/kprf42/dolgova/forum_service/internal/usecase"
+)
+
+type ModerationHandlers struct {
+	uc *moderationuc.ModerationUseCase
+}
+
+func NewModerationHandlers(uc *moderationuc.ModerationUseCase) *ModerationHandlers {
+	return &ModerationHandlers{uc: uc}
+}
+
+// Queue возвращает посты и комментарии, ожидающие модерации: ?limit=&offset=
+func (h *ModerationHandlers) Queue(w http.ResponseWriter, r *http.Request) {
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	queue, err := h.uc.Queue(r.Context(), moderatorID(r), limit, offset)
+	if err != nil {
+		writeModerationError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(queue)
+}
+
+// ApprovePost публикует пост из очереди
+func (h *ModerationHandlers) ApprovePost(w http.ResponseWriter, r *http.Request) {
+	post, err := h.uc.ApprovePost(r.Context(), chi.URLParam(r, "postId"), moderatorID(r))
+	if err != nil {
+		writeModerationError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(post)
+}
+
+// RejectPost отклоняет пост из очереди
+func (h *ModerationHandlers) RejectPost(w http.ResponseWriter, r *http.Request) {
+	if err := h.uc.RejectPost(r.Context(), chi.URLParam(r, "postId"), moderatorID(r)); err != nil {
+		writeModerationError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// PinPost закрепляет пост вверху списков
+func (h *ModerationHandlers) PinPost(w http.ResponseWriter, r *http.Request) {
+	h.setPinned(w, r, true)
+}
+
+// UnpinPost открепляет пост
+func (h *ModerationHandlers) UnpinPost(w http.ResponseWriter, r *http.Request) {
+	h.setPinned(w, r, false)
+}
+
+func (h *ModerationHandlers) setPinned(w http.ResponseWriter, r *http.Request, pinned bool) {
+	post, err := h.uc.PinPost(r.Context(), chi.URLParam(r, "postId"), moderatorID(r), pinned)
+	if err != nil {
+		writeModerationError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(post)
+}
+
+// LockPost закрывает тему поста для новых комментариев
+func (h *ModerationHandlers) LockPost(w http.ResponseWriter, r *http.Request) {
+	h.setLocked(w, r, true)
+}
+
+// UnlockPost снова открывает тему для комментариев
+func (h *ModerationHandlers) UnlockPost(w http.ResponseWriter, r *http.Request) {
+	h.setLocked(w, r, false)
+}
+
+func (h *ModerationHandlers) setLocked(w http.ResponseWriter, r *http.Request, locked bool) {
+	post, err := h.uc.LockPost(r.Context(), chi.URLParam(r, "postId"), moderatorID(r), locked)
+	if err != nil {
+		writeModerationError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(post)
+}
+
+// ApproveComment публикует комментарий из очереди
+func (h *ModerationHandlers) ApproveComment(w http.ResponseWriter, r *http.Request) {
+	comment, err := h.uc.ApproveComment(r.Context(), chi.URLParam(r, "commentId"), moderatorID(r))
+	if err != nil {
+		writeModerationError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(comment)
+}
+
+// RejectComment отклоняет комментарий из очереди
+func (h *ModerationHandlers) RejectComment(w http.ResponseWriter, r *http.Request) {
+	if err := h.uc.RejectComment(r.Context(), chi.URLParam(r, "commentId"), moderatorID(r)); err != nil {
+		writeModerationError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// moderatorID пользователь из контекста; права проверяет use case
+func moderatorID(r *http.Request) string {
+	userID, _ := r.Context().Value("user_id").(string)
+	return userID
+}
+
+func writeModerationError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, moderationuc.ErrForbidden):
+		WriteError(w, r, http.StatusForbidden, ErrCodeForbidden)
+	case errors.Is(err, moderationuc.ErrModerationNotFound):
+		WriteError(w, r, http.StatusNotFound, ErrCodeContentNotFound)
+	case errors.Is(err, moderationuc.ErrNotPending):
+		WriteError(w, r, http.StatusConflict, ErrCodeNotPending)
+	default:
+		WriteInternalError(w, r, err)
+	}
+}
+
+type CategoryModeratorHandlers struct {
+	access     *moderationuc.ModeratorAccess
+	categories *moderationuc.CategoryUseCase
+}
+
+func NewCategoryModeratorHandlers(access *moderationuc.ModeratorAccess, categories *moderationuc.CategoryUseCase) *CategoryModeratorHandlers {
+	return &CategoryModeratorHandlers{access: access, categories: categories}
+}
+
+// CategoryModeratorRequest запрос на назначение модератора категории
+type CategoryModeratorRequest struct {
+	UserID string `json:"user_id"`
+}
+
+// ListModerators возвращает модераторов категории
+func (h *CategoryModeratorHandlers) ListModerators(w http.ResponseWriter, r *http.Request) {
+	categoryID := chi.URLParam(r, "categoryId")
+	if !checkCategory(w, r, h.categories, categoryID) {
+		return
+	}
+
+	moderators, err := h.access.List(r.Context(), categoryID)
+	if err != nil {
+		WriteInternalError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(moderators)
+}
+
+// AssignModerator назначает пользователя модератором категории
+func (h *CategoryModeratorHandlers) AssignModerator(w http.ResponseWriter, r *http.Request) {
+	categoryID := chi.URLParam(r, "categoryId")
+	if !checkCategory(w, r, h.categories, categoryID) {
+		return
+	}
+
+	var req CategoryModeratorRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.UserID == "" {
+		WriteError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest)
+		return
+	}
+
+	m, err := h.access.Assign(r.Context(), categoryID, req.UserID, moderatorID(r))
+	if errors.Is(err, moderationuc.ErrUserNotFound) {
+		WriteError(w, r, http.StatusNotFound, ErrCodeUserNotFound)
+		return
+	}
+	if err != nil {
+		WriteInternalError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(m)
+}
+
+// RemoveModerator снимает пользователя с модерации категории
+func (h *CategoryModeratorHandlers) RemoveModerator(w http.ResponseWriter, r *http.Request) {
+	err := h.access.Remove(r.Context(), chi.URLParam(r, "categoryId"), chi.URLParam(r, "userId"))
+	if errors.Is(err, moderationuc.ErrModeratorNotFound) {
+		WriteError(w, r, http.StatusNotFound, ErrCodeModeratorNotFound)
+		return
+	}
+	if err != nil {
+		WriteInternalError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
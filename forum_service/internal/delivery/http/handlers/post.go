@@ -1,17 +1,18 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
-	"fmt"
+	"errors"
 	"net/http"
 	"strconv"
-	"strings"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 	"github.com/kprf42/dolgova/forum_service/internal/entity"
 	post "github.com/kprf42/dolgova/forum_service/internal/usecase"
+	"github.com/kprf42/dolgova/pkg/logger"
 )
 
 // JWTClaims кастомная структура claims с реализацией всех необходимых методов
@@ -29,47 +30,40 @@ func NewPostHandlers(uc *post.PostUseCase) *PostHandlers {
 }
 
 func (h *PostHandlers) CreatePost(w http.ResponseWriter, r *http.Request) {
+	log := logger.FromContext(r.Context())
+
 	var req entity.PostRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		fmt.Printf("Error decoding request: %v\n", err)
-		http.Error(w, "invalid request", http.StatusBadRequest)
+		log.Debug("Failed to decode post request", logger.Error(err))
+		WriteError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest)
 		return
 	}
 
-	fmt.Printf("Received request: %+v\n", req)
-
-	// Проверяем, что category_id является числом от 1 до 3
-	categoryID := req.CategoryID
-	if categoryID != "1" && categoryID != "2" && categoryID != "3" {
-		fmt.Printf("Invalid category_id: %s\n", categoryID)
-		http.Error(w, "invalid category_id: must be 1, 2 or 3", http.StatusBadRequest)
-		return
-	}
-
-	// Получаем claims из контекста
-	claimsValue := r.Context().Value("claims")
-	fmt.Printf("Claims from context: %v (type: %T)\n", claimsValue, claimsValue)
-
-	claims, ok := claimsValue.(map[string]interface{})
-	if !ok {
-		fmt.Printf("Failed to get claims from context\n")
-		http.Error(w, "unauthorized: invalid claims", http.StatusUnauthorized)
-		return
-	}
-
-	userID, ok := claims["user_id"].(string)
+	// Получаем user_id из контекста
+	userID, ok := r.Context().Value("user_id").(string)
 	if !ok || userID == "" {
-		fmt.Printf("Failed to get user_id from claims. ok: %v, userID: %s\n", ok, userID)
-		http.Error(w, "unauthorized: missing user_id", http.StatusUnauthorized)
+		WriteError(w, r, http.StatusUnauthorized, ErrCodeUnauthorized)
 		return
 	}
 
-	fmt.Printf("Creating post for user: %s\n", userID)
-
 	response, err := h.uc.Create(r.Context(), &req, userID)
 	if err != nil {
-		fmt.Printf("Error creating post: %v\n", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		log.Debug("Failed to create post",
+			logger.String("user_id", userID),
+			logger.Error(err))
+		if errors.Is(err, post.ErrUnknownCategory) {
+			WriteError(w, r, http.StatusBadRequest, ErrCodeInvalidCategory)
+			return
+		}
+		if invalidAttachment(err) {
+			WriteError(w, r, http.StatusBadRequest, ErrCodeInvalidAttachment)
+			return
+		}
+		if notEnoughKarma(err) {
+			WriteError(w, r, http.StatusForbidden, ErrCodeNotEnoughKarma)
+			return
+		}
+		WriteInternalError(w, r, err)
 		return
 	}
 
@@ -78,88 +72,72 @@ func (h *PostHandlers) CreatePost(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *PostHandlers) GetPost(w http.ResponseWriter, r *http.Request) {
-	fmt.Printf("\n=== GetPost Handler ===\n")
-
-	// Получаем параметры несколькими способами для отладки
-	rctx := chi.RouteContext(r.Context())
-	var postID string
-
-	if rctx != nil {
-		// Способ 1: Через URLParam
-		postID = chi.URLParam(r, "postId")
-		fmt.Printf("Method 1 - URLParam: '%s'\n", postID)
-
-		// Способ 2: Напрямую из контекста
-		if len(rctx.URLParams.Keys) > 0 && len(rctx.URLParams.Values) > 0 {
-			for i, key := range rctx.URLParams.Keys {
-				fmt.Printf("URL Param %s: %s\n", key, rctx.URLParams.Values[i])
-				if key == "postId" {
-					postID = rctx.URLParams.Values[i]
-				}
-			}
-		}
-
-		// Способ 3: Через pattern matching
-		fmt.Printf("Route Pattern: %s\n", rctx.RoutePattern())
-	} else {
-		fmt.Printf("ERROR: Chi context is nil\n")
-	}
-
-	// Способ 4: Парсим URL напрямую
-	urlPath := r.URL.Path
-	fmt.Printf("URL Path: %s\n", urlPath)
-	pathParts := strings.Split(urlPath, "/")
-	if len(pathParts) > 4 { // /api/v1/posts/{postId}
-		fmt.Printf("PostID from URL split: %s\n", pathParts[4])
-		if postID == "" {
-			postID = pathParts[4]
-		}
-	}
-
-	fmt.Printf("Final PostID: '%s'\n", postID)
+	log := logger.FromContext(r.Context())
 
-	// Проверяем, не пустой ли ID
+	postID := chi.URLParam(r, "postId")
 	if postID == "" {
-		fmt.Printf("ERROR: Post ID is empty\n")
-		http.Error(w, "post id is required", http.StatusBadRequest)
+		WriteError(w, r, http.StatusBadRequest, ErrCodePostIDRequired)
 		return
 	}
-
-	// Пытаемся распарсить UUID
-	parsedUUID, err := uuid.Parse(postID)
-	if err != nil {
-		fmt.Printf("ERROR: Invalid UUID format. Input: '%s', Error: %v\n", postID, err)
-		fmt.Printf("Expected format example: 550e8400-e29b-41d4-a716-446655440000\n")
-		http.Error(w, fmt.Sprintf("invalid post id format: must be a valid UUID (example: 550e8400-e29b-41d4-a716-446655440000)"), http.StatusBadRequest)
+	if _, err := uuid.Parse(postID); err != nil {
+		WriteError(w, r, http.StatusBadRequest, ErrCodeInvalidPostID)
 		return
 	}
 
-	fmt.Printf("Successfully parsed UUID: %s\n", parsedUUID.String())
-
 	post, err := h.uc.GetByID(r.Context(), postID)
 	if err != nil {
-		fmt.Printf("ERROR: Failed to get post from database: %v\n", err)
-		http.Error(w, err.Error(), http.StatusNotFound)
+		log.Debug("Failed to get post",
+			logger.String("post_id", postID),
+			logger.Error(err))
+		if errors.Is(err, context.DeadlineExceeded) {
+			WriteInternalError(w, r, err)
+			return
+		}
+		WriteError(w, r, http.StatusNotFound, ErrCodePostNotFound)
 		return
 	}
 
-	fmt.Printf("Successfully retrieved post from database: %+v\n", post)
-
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(post); err != nil {
-		fmt.Printf("ERROR: Failed to encode response: %v\n", err)
-		http.Error(w, "error encoding response", http.StatusInternalServerError)
+		log.Error("Failed to encode post",
+			logger.String("post_id", postID),
+			logger.Error(err))
+	}
+}
+
+// GetPostBySlug возвращает пост по человекочитаемому адресу /posts/slug/{slug}
+func (h *PostHandlers) GetPostBySlug(w http.ResponseWriter, r *http.Request) {
+	post, err := h.uc.GetBySlug(r.Context(), chi.URLParam(r, "slug"))
+	if errors.Is(err, context.DeadlineExceeded) {
+		WriteInternalError(w, r, err)
+		return
+	}
+	if err != nil {
+		if err.Error() == "post not found" {
+			WriteError(w, r, http.StatusNotFound, ErrCodePostNotFound)
+			return
+		}
+		WriteInternalError(w, r, err)
 		return
 	}
 
-	fmt.Printf("Successfully sent response for post ID: %s\n", post.ID)
-	fmt.Printf("=== End GetPost Handler ===\n\n")
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(post)
 }
 
+// GetPosts возвращает страницу постов: ?limit=&offset=&category_id=&sort=new|top|hot|score&window=;
+// window задает период для sort=top (hour, day, week, month, year, all; по умолчанию day).
+// С include_subcategories=true в список попадают и посты подкатегорий category_id
 func (h *PostHandlers) GetPosts(w http.ResponseWriter, r *http.Request) {
 	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
 	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
-	categoryID := r.URL.Query().Get("category_id")
+	subcategories, _ := strconv.ParseBool(r.URL.Query().Get("include_subcategories"))
+	filter := entity.PostFilter{
+		CategoryID:           r.URL.Query().Get("category_id"),
+		IncludeSubcategories: subcategories,
+		Sort:                 r.URL.Query().Get("sort"),
+		Window:               r.URL.Query().Get("window"),
+	}
 
 	if limit <= 0 {
 		limit = 10
@@ -168,9 +146,13 @@ func (h *PostHandlers) GetPosts(w http.ResponseWriter, r *http.Request) {
 		offset = 0
 	}
 
-	posts, total, err := h.uc.GetAll(r.Context(), limit, offset, categoryID)
+	posts, total, err := h.uc.GetAll(r.Context(), limit, offset, nil, filter)
+	if errors.Is(err, post.ErrInvalidSort) {
+		WriteError(w, r, http.StatusBadRequest, ErrCodeInvalidSort)
+		return
+	}
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		WriteInternalError(w, r, err)
 		return
 	}
 
@@ -186,184 +168,159 @@ func (h *PostHandlers) GetPosts(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(response)
 }
 
-func (h *PostHandlers) UpdatePost(w http.ResponseWriter, r *http.Request) {
-	fmt.Printf("\n=== UpdatePost Handler ===\n")
-
-	// Получаем параметры несколькими способами для отладки
-	rctx := chi.RouteContext(r.Context())
-	var postID string
-
-	if rctx != nil {
-		// Способ 1: Через URLParam
-		postID = chi.URLParam(r, "postId")
-		fmt.Printf("Method 1 - URLParam: '%s'\n", postID)
-
-		// Способ 2: Напрямую из контекста
-		if len(rctx.URLParams.Keys) > 0 && len(rctx.URLParams.Values) > 0 {
-			for i, key := range rctx.URLParams.Keys {
-				fmt.Printf("URL Param %s: %s\n", key, rctx.URLParams.Values[i])
-				if key == "postId" {
-					postID = rctx.URLParams.Values[i]
-				}
-			}
-		}
+// GetUserPosts возвращает страницу опубликованных постов пользователя для профиля: ?limit=&offset=
+func (h *PostHandlers) GetUserPosts(w http.ResponseWriter, r *http.Request) {
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
 
-		// Способ 3: Через pattern matching
-		fmt.Printf("Route Pattern: %s\n", rctx.RoutePattern())
-	} else {
-		fmt.Printf("ERROR: Chi context is nil\n")
+	if limit <= 0 {
+		limit = 10
+	}
+	if offset < 0 {
+		offset = 0
 	}
 
-	// Способ 4: Парсим URL напрямую
-	urlPath := r.URL.Path
-	fmt.Printf("URL Path: %s\n", urlPath)
-	pathParts := strings.Split(urlPath, "/")
-	if len(pathParts) > 4 { // /api/v1/posts/{postId}
-		fmt.Printf("PostID from URL split: %s\n", pathParts[4])
-		if postID == "" {
-			postID = pathParts[4]
-		}
+	posts, total, err := h.uc.GetByAuthor(r.Context(), chi.URLParam(r, "userId"), limit, offset)
+	if err != nil {
+		WriteInternalError(w, r, err)
+		return
+	}
+
+	response := struct {
+		Posts []*entity.PostResponse `json:"posts"`
+		Total int                    `json:"total"`
+	}{
+		Posts: posts,
+		Total: total,
 	}
 
-	fmt.Printf("Final PostID: '%s'\n", postID)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *PostHandlers) UpdatePost(w http.ResponseWriter, r *http.Request) {
+	log := logger.FromContext(r.Context())
 
-	// Проверяем, не пустой ли ID
+	postID := chi.URLParam(r, "postId")
 	if postID == "" {
-		fmt.Printf("ERROR: Post ID is empty\n")
-		http.Error(w, "post id is required", http.StatusBadRequest)
+		WriteError(w, r, http.StatusBadRequest, ErrCodePostIDRequired)
 		return
 	}
-
-	// Пытаемся распарсить UUID
-	_, err := uuid.Parse(postID)
-	if err != nil {
-		fmt.Printf("ERROR: Invalid UUID format. Input: '%s', Error: %v\n", postID, err)
-		fmt.Printf("Expected format example: 550e8400-e29b-41d4-a716-446655440000\n")
-		http.Error(w, fmt.Sprintf("invalid post id format: must be a valid UUID (example: 550e8400-e29b-41d4-a716-446655440000)"), http.StatusBadRequest)
+	if _, err := uuid.Parse(postID); err != nil {
+		WriteError(w, r, http.StatusBadRequest, ErrCodeInvalidPostID)
 		return
 	}
 
 	// Декодируем тело запроса
 	var req entity.PostUpdate
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		fmt.Printf("ERROR: Failed to decode request body: %v\n", err)
-		http.Error(w, "invalid request body", http.StatusBadRequest)
+		log.Debug("Failed to decode post update", logger.Error(err))
+		WriteError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest)
 		return
 	}
-	fmt.Printf("Request body decoded: %+v\n", req)
 
 	// Получаем user_id из контекста
 	userID, ok := r.Context().Value("user_id").(string)
 	if !ok || userID == "" {
-		fmt.Printf("ERROR: Failed to get user_id from context\n")
-		http.Error(w, "unauthorized: missing user_id", http.StatusUnauthorized)
+		WriteError(w, r, http.StatusUnauthorized, ErrCodeUnauthorized)
 		return
 	}
-	fmt.Printf("User ID from context: %s\n", userID)
 
 	// Обновляем пост
 	response, err := h.uc.Update(r.Context(), postID, &req, userID)
 	if err != nil {
-		status := http.StatusInternalServerError
+		status, code := http.StatusInternalServerError, ErrCodeInternal
 		if err.Error() == "unauthorized" {
-			status = http.StatusUnauthorized
+			status, code = http.StatusUnauthorized, ErrCodeNotAuthor
+		}
+		if notEnoughKarma(err) {
+			status, code = http.StatusForbidden, ErrCodeNotEnoughKarma
+		}
+		if errors.Is(err, context.DeadlineExceeded) {
+			status, code = http.StatusGatewayTimeout, ErrCodeTimeout
 		}
-		fmt.Printf("ERROR: Failed to update post: %v\n", err)
-		http.Error(w, err.Error(), status)
+		log.Debug("Failed to update post",
+			logger.String("post_id", postID),
+			logger.String("user_id", userID),
+			logger.Error(err))
+		WriteError(w, r, status, code)
 		return
 	}
 
-	fmt.Printf("Successfully updated post: %+v\n", response)
-
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(response); err != nil {
-		fmt.Printf("ERROR: Failed to encode response: %v\n", err)
-		http.Error(w, "error encoding response", http.StatusInternalServerError)
-		return
+		log.Error("Failed to encode post",
+			logger.String("post_id", postID),
+			logger.Error(err))
 	}
-
-	fmt.Printf("=== End UpdatePost Handler ===\n\n")
 }
 
 func (h *PostHandlers) DeletePost(w http.ResponseWriter, r *http.Request) {
-	fmt.Printf("\n=== DeletePost Handler ===\n")
-
-	// Получаем параметры несколькими способами для отладки
-	rctx := chi.RouteContext(r.Context())
-	var postID string
-
-	if rctx != nil {
-		// Способ 1: Через URLParam
-		postID = chi.URLParam(r, "postId")
-		fmt.Printf("Method 1 - URLParam: '%s'\n", postID)
-
-		// Способ 2: Напрямую из контекста
-		if len(rctx.URLParams.Keys) > 0 && len(rctx.URLParams.Values) > 0 {
-			for i, key := range rctx.URLParams.Keys {
-				fmt.Printf("URL Param %s: %s\n", key, rctx.URLParams.Values[i])
-				if key == "postId" {
-					postID = rctx.URLParams.Values[i]
-				}
-			}
-		}
-
-		// Способ 3: Через pattern matching
-		fmt.Printf("Route Pattern: %s\n", rctx.RoutePattern())
-	} else {
-		fmt.Printf("ERROR: Chi context is nil\n")
-	}
-
-	// Способ 4: Парсим URL напрямую
-	urlPath := r.URL.Path
-	fmt.Printf("URL Path: %s\n", urlPath)
-	pathParts := strings.Split(urlPath, "/")
-	if len(pathParts) > 4 { // /api/v1/posts/{postId}
-		fmt.Printf("PostID from URL split: %s\n", pathParts[4])
-		if postID == "" {
-			postID = pathParts[4]
-		}
-	}
-
-	fmt.Printf("Final PostID: '%s'\n", postID)
-
-	// Проверяем, не пустой ли ID
+	postID := chi.URLParam(r, "postId")
 	if postID == "" {
-		fmt.Printf("ERROR: Post ID is empty\n")
-		http.Error(w, "post id is required", http.StatusBadRequest)
+		WriteError(w, r, http.StatusBadRequest, ErrCodePostIDRequired)
 		return
 	}
-
-	// Пытаемся распарсить UUID
-	_, err := uuid.Parse(postID)
-	if err != nil {
-		fmt.Printf("ERROR: Invalid UUID format. Input: '%s', Error: %v\n", postID, err)
-		fmt.Printf("Expected format example: 550e8400-e29b-41d4-a716-446655440000\n")
-		http.Error(w, fmt.Sprintf("invalid post id format: must be a valid UUID (example: 550e8400-e29b-41d4-a716-446655440000)"), http.StatusBadRequest)
+	if _, err := uuid.Parse(postID); err != nil {
+		WriteError(w, r, http.StatusBadRequest, ErrCodeInvalidPostID)
 		return
 	}
 
 	// Получаем user_id из контекста
 	userID, ok := r.Context().Value("user_id").(string)
 	if !ok || userID == "" {
-		fmt.Printf("ERROR: Failed to get user_id from context\n")
-		http.Error(w, "unauthorized: missing user_id", http.StatusUnauthorized)
+		WriteError(w, r, http.StatusUnauthorized, ErrCodeUnauthorized)
 		return
 	}
-	fmt.Printf("User ID from context: %s\n", userID)
 
 	// Удаляем пост
 	if err := h.uc.Delete(r.Context(), postID, userID); err != nil {
-		status := http.StatusInternalServerError
+		status, code := http.StatusInternalServerError, ErrCodeInternal
 		if err.Error() == "unauthorized" {
-			status = http.StatusUnauthorized
+			status, code = http.StatusUnauthorized, ErrCodeNotAuthor
+		}
+		if errors.Is(err, context.DeadlineExceeded) {
+			status, code = http.StatusGatewayTimeout, ErrCodeTimeout
 		}
-		fmt.Printf("ERROR: Failed to delete post: %v\n", err)
-		http.Error(w, err.Error(), status)
+		logger.FromContext(r.Context()).Debug("Failed to delete post",
+			logger.String("post_id", postID),
+			logger.String("user_id", userID),
+			logger.Error(err))
+		WriteError(w, r, status, code)
 		return
 	}
 
-	fmt.Printf("Successfully deleted post\n")
-	fmt.Printf("=== End DeletePost Handler ===\n\n")
-
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// VotePost голосует за пост: {"value": 1} - за, -1 - против, 0 - снять голос
+func (h *PostHandlers) VotePost(w http.ResponseWriter, r *http.Request) {
+	postID := chi.URLParam(r, "postId")
+	if _, err := uuid.Parse(postID); err != nil {
+		WriteError(w, r, http.StatusBadRequest, ErrCodeInvalidPostID)
+		return
+	}
+
+	var req entity.PostVoteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest)
+		return
+	}
+
+	userID, _ := r.Context().Value("user_id").(string)
+	vote, err := h.uc.Vote(r.Context(), postID, userID, req.Value)
+	if err != nil {
+		switch {
+		case errors.Is(err, post.ErrInvalidVote):
+			WriteError(w, r, http.StatusBadRequest, ErrCodeInvalidVote)
+		case err.Error() == "post not found":
+			WriteError(w, r, http.StatusNotFound, ErrCodePostNotFound)
+		default:
+			WriteInternalError(w, r, err)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(vote)
+}
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	profileuc "github.com/kprf42/dolgova/forum_service/internal/usecase"
+	"github.com/kprf42/dolgova/pkg/logger"
+	"github.com/kprf42/dolgova/pkg/uploads"
+)
+
+// MaxAvatarSize максимальный размер файла аватара
+const MaxAvatarSize = 5 << 20
+
+type ProfileHandlers struct {
+	uc *profileuc.ProfileUseCase
+}
+
+func NewProfileHandlers(uc *profileuc.ProfileUseCase) *ProfileHandlers {
+	return &ProfileHandlers{uc: uc}
+}
+
+func (h *ProfileHandlers) GetProfile(w http.ResponseWriter, r *http.Request) {
+	userID := chi.URLParam(r, "userId")
+
+	profile, err := h.uc.GetProfile(r.Context(), userID)
+	if err != nil {
+		WriteInternalError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(profile)
+}
+
+// GetStats возвращает карму пользователя с разбивкой по источникам и число его публикаций
+func (h *ProfileHandlers) GetStats(w http.ResponseWriter, r *http.Request) {
+	stats, err := h.uc.GetStats(r.Context(), chi.URLParam(r, "userId"))
+	if err != nil {
+		WriteInternalError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(stats)
+}
+
+// UploadAvatar принимает изображение в поле "avatar" формы multipart/form-data
+func (h *ProfileHandlers) UploadAvatar(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok || userID == "" {
+		WriteError(w, r, http.StatusUnauthorized, ErrCodeUnauthorized)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, MaxAvatarSize+1<<20)
+	file, header, err := r.FormFile("avatar")
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) || (err == nil && header.Size > MaxAvatarSize) {
+		if file != nil {
+			file.Close()
+		}
+		WriteError(w, r, http.StatusRequestEntityTooLarge, ErrCodeFileTooLarge)
+		return
+	}
+	if err != nil {
+		logger.FromContext(r.Context()).Debug("Failed to read avatar", logger.Error(err))
+		WriteError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest)
+		return
+	}
+	defer file.Close()
+
+	profile, err := h.uc.UploadAvatar(r.Context(), userID, file)
+	switch {
+	case errors.Is(err, profileuc.ErrUnsupportedFile):
+		WriteError(w, r, http.StatusUnsupportedMediaType, ErrCodeUnsupportedFile)
+		return
+	case errors.Is(err, profileuc.ErrInvalidImage), errors.Is(err, uploads.ErrImageTooLarge):
+		WriteError(w, r, http.StatusBadRequest, ErrCodeInvalidImage)
+		return
+	case err != nil:
+		WriteInternalError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(profile)
+}
+
+// notEnoughKarma сообщает, что автору не хватает кармы для ссылок в тексте
+func notEnoughKarma(err error) bool {
+	return errors.Is(err, profileuc.ErrNotEnoughKarma)
+}
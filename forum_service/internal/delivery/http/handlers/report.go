@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/kprf42/dolgova/forum_service/internal/entity"
+	reportuc "github.com/kprf42/dolgova/forum_service/internal/usecase"
+)
+
+type ReportHandlers struct {
+	uc *reportuc.ReportUseCase
+}
+
+func NewReportHandlers(uc *reportuc.ReportUseCase) *ReportHandlers {
+	return &ReportHandlers{uc: uc}
+}
+
+// ReportPost принимает жалобу на пост: {"reason": "spam", "details": "..."}
+func (h *ReportHandlers) ReportPost(w http.ResponseWriter, r *http.Request) {
+	var req entity.ReportRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest)
+		return
+	}
+
+	report, err := h.uc.ReportPost(r.Context(), chi.URLParam(r, "postId"), moderatorID(r), &req)
+	writeReport(w, r, report, err)
+}
+
+// ReportComment принимает жалобу на комментарий
+func (h *ReportHandlers) ReportComment(w http.ResponseWriter, r *http.Request) {
+	var req entity.ReportRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest)
+		return
+	}
+
+	report, err := h.uc.ReportComment(r.Context(), chi.URLParam(r, "commentId"), moderatorID(r), &req)
+	writeReport(w, r, report, err)
+}
+
+// ListReports возвращает жалобы в категориях модератора: ?limit=&offset=
+func (h *ReportHandlers) ListReports(w http.ResponseWriter, r *http.Request) {
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	reports, err := h.uc.List(r.Context(), moderatorID(r), limit, offset)
+	if err != nil {
+		writeModerationError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(reports)
+}
+
+func writeReport(w http.ResponseWriter, r *http.Request, report *entity.Report, err error) {
+	switch {
+	case errors.Is(err, reportuc.ErrInvalidReport):
+		WriteError(w, r, http.StatusBadRequest, ErrCodeInvalidReport)
+	case errors.Is(err, reportuc.ErrAlreadyReported):
+		WriteError(w, r, http.StatusConflict, ErrCodeAlreadyReported)
+	case err != nil:
+		writeModerationError(w, r, err)
+	default:
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(report)
+	}
+}
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/kprf42/dolgova/forum_service/internal/search"
+	searchuc "github.com/kprf42/dolgova/forum_service/internal/usecase"
+)
+
+type SearchHandlers struct {
+	uc *searchuc.SearchUseCase
+}
+
+func NewSearchHandlers(uc *searchuc.SearchUseCase) *SearchHandlers {
+	return &SearchHandlers{uc: uc}
+}
+
+// Search ищет по постам и комментариям: ?q=текст&limit=&offset=
+func (h *SearchHandlers) Search(w http.ResponseWriter, r *http.Request) {
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+
+	if limit <= 0 {
+		limit = 10
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	result, err := h.uc.Search(r.Context(), r.URL.Query().Get("q"), limit, offset)
+	if errors.Is(err, searchuc.ErrEmptySearchQuery) {
+		WriteError(w, r, http.StatusBadRequest, ErrCodeSearchQueryRequired)
+		return
+	}
+	if errors.Is(err, search.ErrDisabled) {
+		WriteError(w, r, http.StatusServiceUnavailable, ErrCodeSearchUnavailable)
+		return
+	}
+	if err != nil {
+		WriteInternalError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
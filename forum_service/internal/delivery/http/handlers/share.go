@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	shareuc "github.com/kprf42/dolgova/forum_service/internal/usecase"
+)
+
+type ShareHandlers struct {
+	uc      *shareuc.ShareUseCase
+	siteURL string
+}
+
+// NewShareHandlers создает обработчики коротких ссылок. siteURL адрес фронтенда, на страницу
+// поста которого ведет короткая ссылка; пустой адрес дает относительную ссылку /posts/{id}.
+func NewShareHandlers(uc *shareuc.ShareUseCase, siteURL string) *ShareHandlers {
+	return &ShareHandlers{uc: uc, siteURL: strings.TrimSuffix(siteURL, "/")}
+}
+
+// SharePost возвращает короткую ссылку текущего пользователя на пост
+func (h *ShareHandlers) SharePost(w http.ResponseWriter, r *http.Request) {
+	postID := chi.URLParam(r, "postId")
+	if _, err := uuid.Parse(postID); err != nil {
+		WriteError(w, r, http.StatusBadRequest, ErrCodeInvalidPostID)
+		return
+	}
+
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok || userID == "" {
+		WriteError(w, r, http.StatusUnauthorized, ErrCodeUnauthorized)
+		return
+	}
+
+	link, err := h.uc.Share(r.Context(), postID, userID)
+	if err != nil {
+		if err.Error() == "post not found" {
+			WriteError(w, r, http.StatusNotFound, ErrCodePostNotFound)
+			return
+		}
+		WriteInternalError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(link)
+}
+
+// PostStats возвращает статистику поста с переходами по коротким ссылкам
+func (h *ShareHandlers) PostStats(w http.ResponseWriter, r *http.Request) {
+	postID := chi.URLParam(r, "postId")
+	if _, err := uuid.Parse(postID); err != nil {
+		WriteError(w, r, http.StatusBadRequest, ErrCodeInvalidPostID)
+		return
+	}
+
+	userID, _ := r.Context().Value("user_id").(string)
+	stats, err := h.uc.PostStats(r.Context(), postID, userID)
+	if err != nil {
+		switch err.Error() {
+		case "post not found":
+			WriteError(w, r, http.StatusNotFound, ErrCodePostNotFound)
+		case "unauthorized":
+			WriteError(w, r, http.StatusForbidden, ErrCodeForbidden)
+		default:
+			WriteInternalError(w, r, err)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(stats)
+}
+
+// OpenLink засчитывает переход по короткой ссылке и перенаправляет на страницу поста
+func (h *ShareHandlers) OpenLink(w http.ResponseWriter, r *http.Request) {
+	postID, err := h.uc.Open(r.Context(), chi.URLParam(r, "code"))
+	if errors.Is(err, shareuc.ErrShareLinkNotFound) {
+		WriteError(w, r, http.StatusNotFound, ErrCodeShareLinkNotFound)
+		return
+	}
+	if err != nil {
+		WriteInternalError(w, r, err)
+		return
+	}
+
+	http.Redirect(w, r, h.siteURL+"/posts/"+postID, http.StatusFound)
+}
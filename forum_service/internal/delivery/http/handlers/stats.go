@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	stats "github.com/kprf42/dolgova/forum_service/internal/usecase"
+)
+
+type StatsHandlers struct {
+	statsUC *stats.StatsUseCase
+}
+
+func NewStatsHandlers(statsUC *stats.StatsUseCase) *StatsHandlers {
+	return &StatsHandlers{statsUC: statsUC}
+}
+
+// GetStats отдает статистику для админ-панели.
+// Параметры from и to (YYYY-MM-DD) необязательны, по умолчанию последние 30 дней.
+func (h *StatsHandlers) GetStats(w http.ResponseWriter, r *http.Request) {
+	from, err := parseDate(r.URL.Query().Get("from"))
+	if err != nil {
+		WriteError(w, r, http.StatusBadRequest, ErrCodeInvalidDate)
+		return
+	}
+	to, err := parseDate(r.URL.Query().Get("to"))
+	if err != nil {
+		WriteError(w, r, http.StatusBadRequest, ErrCodeInvalidDate)
+		return
+	}
+
+	response, err := h.statsUC.GetStats(r.Context(), from, to)
+	if errors.Is(err, stats.ErrInvalidStatsRange) {
+		WriteError(w, r, http.StatusBadRequest, ErrCodeInvalidDateRange)
+		return
+	}
+	if err != nil {
+		WriteInternalError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func parseDate(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse("2006-01-02", value)
+}
@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	trendinguc "github.com/kprf42/dolgova/forum_service/internal/usecase"
+)
+
+type TrendingHandlers struct {
+	uc *trendinguc.TrendingUseCase
+}
+
+func NewTrendingHandlers(uc *trendinguc.TrendingUseCase) *TrendingHandlers {
+	return &TrendingHandlers{uc: uc}
+}
+
+// GetTrending возвращает популярные посты за последние часы: ?limit= (не больше TrendingLimit)
+func (h *TrendingHandlers) GetTrending(w http.ResponseWriter, r *http.Request) {
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	if limit <= 0 || limit > trendinguc.TrendingLimit {
+		limit = trendinguc.TrendingLimit
+	}
+
+	trending, err := h.uc.Get(r.Context())
+	if err != nil {
+		WriteInternalError(w, r, err)
+		return
+	}
+
+	// Кешированный список общий для запросов, поэтому обрезается в копии
+	response := *trending
+	response.Posts = trending.Posts[:min(limit, len(trending.Posts))]
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
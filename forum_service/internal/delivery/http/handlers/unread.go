@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	unreaduc "github.com/kprf42/dolgova/forum_service/internal/usecase"
+)
+
+type UnreadHandlers struct {
+	uc         *unreaduc.UnreadUseCase
+	categories *unreaduc.CategoryUseCase
+}
+
+func NewUnreadHandlers(uc *unreaduc.UnreadUseCase, categories *unreaduc.CategoryUseCase) *UnreadHandlers {
+	return &UnreadHandlers{uc: uc, categories: categories}
+}
+
+// GetUnread возвращает темы с новыми комментариями после последнего визита: ?limit=&offset=
+func (h *UnreadHandlers) GetUnread(w http.ResponseWriter, r *http.Request) {
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	userID, _ := r.Context().Value("user_id").(string)
+	threads, err := h.uc.Threads(r.Context(), userID, limit, offset)
+	if err != nil {
+		WriteInternalError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(threads)
+}
+
+// MarkPostRead отмечает пост прочитанным
+func (h *UnreadHandlers) MarkPostRead(w http.ResponseWriter, r *http.Request) {
+	postID := chi.URLParam(r, "postId")
+	if _, err := uuid.Parse(postID); err != nil {
+		WriteError(w, r, http.StatusBadRequest, ErrCodeInvalidPostID)
+		return
+	}
+
+	userID, _ := r.Context().Value("user_id").(string)
+	if err := h.uc.MarkPostRead(r.Context(), userID, postID); err != nil {
+		if err.Error() == "post not found" {
+			WriteError(w, r, http.StatusNotFound, ErrCodePostNotFound)
+			return
+		}
+		if errors.Is(err, context.DeadlineExceeded) {
+			WriteError(w, r, http.StatusGatewayTimeout, ErrCodeTimeout)
+			return
+		}
+		WriteInternalError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// MarkCategoryRead отмечает прочитанными все посты категории
+func (h *UnreadHandlers) MarkCategoryRead(w http.ResponseWriter, r *http.Request) {
+	categoryID := chi.URLParam(r, "categoryId")
+	if !checkCategory(w, r, h.categories, categoryID) {
+		return
+	}
+
+	userID, _ := r.Context().Value("user_id").(string)
+	if err := h.uc.MarkCategoryRead(r.Context(), userID, categoryID); err != nil {
+		WriteInternalError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -2,155 +2,335 @@ package http
 
 import (
 	"context"
-	"fmt"
+	"errors"
+	"net"
 	"net/http"
+	"net/netip"
+	"net/url"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
-	"github.com/golang-jwt/jwt/v5"
+	"github.com/kprf42/dolgova/forum_service/internal/auth"
+	"github.com/kprf42/dolgova/forum_service/internal/config"
 	"github.com/kprf42/dolgova/forum_service/internal/delivery/http/handlers"
+	"github.com/kprf42/dolgova/forum_service/internal/tenant"
+	"github.com/kprf42/dolgova/pkg/audit"
+	"github.com/kprf42/dolgova/pkg/csrf"
+	"github.com/kprf42/dolgova/pkg/ipban"
+	"github.com/kprf42/dolgova/pkg/logger"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
-// JWTClaims кастомная структура claims с реализацией всех необходимых методов
-type JWTClaims struct {
-	UserID string `json:"user_id"`
-	jwt.RegisteredClaims
-}
+// AccessTokenCookie cookie с access токеном, которую выдает auth сервис в режиме cookie-аутентификации
+const AccessTokenCookie = "access_token"
+
+// DebugUserHeader заголовок с ID пользователя в режиме MOCK_AUTH. WebSocket из браузера
+// не передает заголовки, поэтому ID можно указать и параметром запроса debug_user.
+const DebugUserHeader = "X-Debug-User"
 
+// AuthMiddleware проверяет access токен через auth сервис
 type AuthMiddleware struct {
-	JWTSecret string
+	Tokens     auth.TokenValidator
+	CookieAuth bool         // Принимать токен из cookie, если нет заголовка Authorization
+	DebugUsers bool         // Принимать X-Debug-User без токена и auth сервиса (MOCK_AUTH, не для production)
+	Audit      *audit.Store // Журнал запросов, выполненных под имперсонацией
 }
 
 func (m *AuthMiddleware) JWT(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		fmt.Printf("\n=== JWT Middleware ===\n")
-		fmt.Printf("Request URL: %s\n", r.URL.String())
-		fmt.Printf("Request Method: %s\n", r.Method)
-		fmt.Printf("JWT Secret: %s\n", m.JWTSecret)
-
 		if r.Method == "OPTIONS" {
-			fmt.Printf("OPTIONS request - skipping auth\n")
 			w.WriteHeader(http.StatusOK)
 			return
 		}
 
+		// URL в журнал не пишется: в нем может быть debug_user
+		log := logger.FromContext(r.Context())
+
+		if m.DebugUsers {
+			if userID := debugUser(r); userID != "" {
+				ctx := context.WithValue(r.Context(), "user_id", userID)
+				ctx = auth.WithUserID(ctx, userID)
+				ctx = logger.AddToContext(ctx, logger.String("user_id", userID), logger.Bool("debug_user", true))
+				next.ServeHTTP(w, r.WithContext(ctx))
+				return
+			}
+		}
+
 		authHeader := r.Header.Get("Authorization")
-		fmt.Printf("Authorization header: '%s'\n", authHeader)
+
+		if authHeader == "" && m.CookieAuth {
+			if cookie, err := r.Cookie(AccessTokenCookie); err == nil {
+				authHeader = "Bearer " + cookie.Value
+			}
+		}
 
 		if authHeader == "" {
-			fmt.Printf("ERROR: No Authorization header\n")
-			http.Error(w, "Authorization header is required", http.StatusUnauthorized)
+			log.Debug("Missing authorization token")
+			handlers.WriteError(w, r, http.StatusUnauthorized, handlers.ErrCodeTokenRequired)
 			return
 		}
 
 		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
 		if tokenString == authHeader {
-			fmt.Printf("ERROR: No Bearer prefix in token\n")
-			http.Error(w, "Bearer token required", http.StatusUnauthorized)
+			log.Debug("Authorization header without Bearer prefix")
+			handlers.WriteError(w, r, http.StatusUnauthorized, handlers.ErrCodeBearerRequired)
 			return
 		}
-		fmt.Printf("Token string after trim: '%s'\n", tokenString)
 
-		parts := strings.Split(tokenString, ".")
-		if len(parts) != 3 {
-			fmt.Printf("ERROR: Invalid token format - expected 3 parts, got %d\n", len(parts))
-			http.Error(w, "Invalid token format", http.StatusUnauthorized)
+		identity, err := m.Tokens.ValidateToken(r.Context(), tokenString)
+		if errors.Is(err, auth.ErrUnavailable) {
+			log.Warn("Token validation unavailable", logger.Error(err))
+			handlers.WriteError(w, r, http.StatusServiceUnavailable, handlers.ErrCodeAuthUnavailable)
+			return
+		}
+		if errors.Is(err, auth.ErrSuspended) {
+			log.Debug("Account suspended")
+			handlers.WriteError(w, r, http.StatusForbidden, handlers.ErrCodeAccountSuspended)
 			return
 		}
-
-		token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				fmt.Printf("ERROR: Unexpected signing method: %v\n", token.Header["alg"])
-				return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-			}
-			return []byte(m.JWTSecret), nil
-		})
-
 		if err != nil {
-			fmt.Printf("ERROR: Token parse error: %v\n", err)
-			http.Error(w, "Invalid token: "+err.Error(), http.StatusUnauthorized)
+			log.Debug("Token validation failed", logger.Error(err))
+			handlers.WriteError(w, r, http.StatusUnauthorized, handlers.ErrCodeInvalidToken)
 			return
 		}
 
-		if !token.Valid {
-			fmt.Printf("ERROR: Token is invalid\n")
-			http.Error(w, "Invalid token", http.StatusUnauthorized)
+		if !scopeAllows(identity.Scopes, r.Method) {
+			log.Debug("Token scopes do not allow method",
+				logger.Strings("scopes", identity.Scopes),
+				logger.String("method", r.Method))
+			handlers.WriteError(w, r, http.StatusForbidden, handlers.ErrCodeInsufficientScope)
 			return
 		}
 
-		claims, ok := token.Claims.(*JWTClaims)
-		if !ok {
-			fmt.Printf("ERROR: Invalid token claims type\n")
-			http.Error(w, "Invalid token claims", http.StatusUnauthorized)
+		userID := identity.UserID
+		ctx := context.WithValue(r.Context(), "user_id", userID)
+		ctx = auth.WithUserID(ctx, userID)
+		ctx = auth.WithRole(ctx, identity.Role)
+		ctx = auth.WithScopes(ctx, identity.Scopes)
+		ctx = logger.AddToContext(ctx, logger.String("user_id", userID))
+
+		// Запросы под имперсонацией помечаются и пишутся в журнал аудита
+		if identity.ActingAdminID != "" {
+			ctx = audit.WithActingAdmin(ctx, identity.ActingAdminID)
+			ctx = logger.AddToContext(ctx, logger.String("acting_admin_id", identity.ActingAdminID))
+			audit.Request(m.Audit, userID, identity.ActingAdminID, next, func(err error) {
+				logger.FromContext(ctx).Error("Failed to write audit entry", logger.Error(err))
+			}).ServeHTTP(w, r.WithContext(ctx))
 			return
 		}
 
-		if claims.ExpiresAt != nil {
-			if claims.ExpiresAt.Before(time.Now()) {
-				fmt.Printf("ERROR: Token has expired\n")
-				http.Error(w, "Token has expired", http.StatusUnauthorized)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// Optional аутентифицирует запрос, если в нем есть токен, и пропускает анонимные запросы.
+// Нужен публичным спискам, чтобы скрыть контент пользователей, заблокированных читателем.
+// Невалидный токен отклоняется так же, как в JWT и в gRPC.
+func (m *AuthMiddleware) Optional(next http.Handler) http.Handler {
+	jwt := m.JWT(next)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "" || (m.DebugUsers && debugUser(r) != "") {
+			jwt.ServeHTTP(w, r)
+			return
+		}
+		if m.CookieAuth {
+			if _, err := r.Cookie(AccessTokenCookie); err == nil {
+				jwt.ServeHTTP(w, r)
 				return
 			}
 		}
+		next.ServeHTTP(w, r)
+	})
+}
 
-		fmt.Printf("Token claims: %+v\n", claims)
-		fmt.Printf("User ID from token: %s\n", claims.UserID)
+// scopeAllows проверяет ограничения токена: с ограниченным (гостевым) токеном можно только
+// читать, и то лишь при scope read-only. Остальные scopes проверяет RequireScope на маршрутах.
+func scopeAllows(scopes []string, method string) bool {
+	if len(scopes) == 0 {
+		return true
+	}
+	return (method == http.MethodGet || method == http.MethodHead) && slices.Contains(scopes, auth.ScopeReadOnly)
+}
 
-		ctx := context.WithValue(r.Context(), "user_id", claims.UserID)
-		fmt.Printf("Added user_id to context: %s\n", claims.UserID)
-		fmt.Printf("=== End JWT Middleware ===\n\n")
+// RequireScope пропускает токены без ограничений и ограниченные токены со scope.
+// Должен стоять после JWT middleware.
+func RequireScope(scope string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !auth.Allows(r.Context(), scope) {
+				handlers.WriteError(w, r, http.StatusForbidden, handlers.ErrCodeInsufficientScope)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
 
-		next.ServeHTTP(w, r.WithContext(ctx))
+func debugUser(r *http.Request) string {
+	if userID := r.Header.Get(DebugUserHeader); userID != "" {
+		return userID
+	}
+	return r.URL.Query().Get("debug_user")
+}
+
+// RoleResolver возвращает роль пользователя по его ID
+type RoleResolver interface {
+	GetRole(ctx context.Context, userID string) (string, error)
+}
+
+// RequireRole пропускает только пользователей с одной из указанных ролей.
+// Должен стоять после JWT middleware, т.к. берет user_id и роль из контекста.
+// Роль берется из токена; resolver нужен для токенов без роли и пользователей MOCK_AUTH.
+func RequireRole(resolver RoleResolver, roles ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			userID, ok := r.Context().Value("user_id").(string)
+			if !ok || userID == "" {
+				handlers.WriteError(w, r, http.StatusUnauthorized, handlers.ErrCodeUnauthorized)
+				return
+			}
+
+			role, ok := auth.RoleFromContext(r.Context())
+			if !ok {
+				var err error
+				role, err = resolver.GetRole(r.Context(), userID)
+				if errors.Is(err, context.DeadlineExceeded) {
+					handlers.WriteInternalError(w, r, err)
+					return
+				}
+				if err != nil {
+					handlers.WriteError(w, r, http.StatusForbidden, handlers.ErrCodeForbidden)
+					return
+				}
+			}
+
+			for _, allowed := range roles {
+				if role == allowed {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+
+			handlers.WriteError(w, r, http.StatusForbidden, handlers.ErrCodeForbidden)
+		})
+	}
+}
+
+// RequestLogger сохраняет в контексте логгер с request_id и сообществом запроса.
+// Use case и репозитории получают его через log.ForContext(ctx); user_id добавляет AuthMiddleware.
+func RequestLogger(log *logger.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := logger.WithContext(r.Context(), log.WithFields(
+				logger.String("request_id", middleware.GetReqID(r.Context())),
+				logger.String("tenant_id", tenant.FromContext(r.Context()))))
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// ClientIP кладет адрес клиента в контекст запроса. Должен стоять после ipban.RealIP,
+// иначе за прокси это будет адрес прокси.
+func ClientIP(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ip := r.RemoteAddr
+		if host, _, err := net.SplitHostPort(ip); err == nil {
+			ip = host
+		}
+		next.ServeHTTP(w, r.WithContext(auth.WithClientIP(r.Context(), ip)))
 	})
 }
 
+// routePattern шаблон маршрута chi ("/api/v1/posts/{postId}"): по нему группируются записи access-лога
+func routePattern(r *http.Request) string {
+	if rctx := chi.RouteContext(r.Context()); rctx != nil {
+		return rctx.RoutePattern()
+	}
+	return ""
+}
+
 func NewRouter(
 	postHandlers *handlers.PostHandlers,
 	commentHandlers *handlers.CommentHandlers,
 	chatHandlers *handlers.ChatHandlers,
-	jwtSecret string,
+	healthHandlers *handlers.HealthHandlers,
+	statsHandlers *handlers.StatsHandlers,
+	searchHandlers *handlers.SearchHandlers,
+	ipBanHandlers *handlers.IPBanHandlers,
+	moderationHandlers *handlers.ModerationHandlers,
+	categoryModeratorHandlers *handlers.CategoryModeratorHandlers,
+	auditHandlers *handlers.AuditHandlers,
+	attachmentHandlers *handlers.AttachmentHandlers,
+	profileHandlers *handlers.ProfileHandlers,
+	blockHandlers *handlers.BlockHandlers,
+	shareHandlers *handlers.ShareHandlers,
+	unreadHandlers *handlers.UnreadHandlers,
+	announcementHandlers *handlers.AnnouncementHandlers,
+	categoryHandlers *handlers.CategoryHandlers,
+	reportHandlers *handlers.ReportHandlers,
+	trendingHandlers *handlers.TrendingHandlers,
+	uploadsHandler http.Handler,
+	logLevelHandler http.Handler,
+	roles RoleResolver,
+	tokens auth.TokenValidator,
+	cookieAuth bool,
+	debugUsers bool,
+	runtime *config.RuntimeWatcher,
+	tenants *tenant.Resolver,
+	trustedProxies []netip.Prefix,
+	bans *ipban.Checker,
+	auditLog *audit.Store,
+	log *logger.Logger,
 ) *chi.Mux {
 	r := chi.NewRouter()
 
+	// Сообщество определяется до маршрутизации, т.к. middleware срезает префикс /t/{tenant}
+	r.Use(ResolveTenant(tenants))
+
 	// Basic middleware
 	r.Use(middleware.RequestID)
-	r.Use(middleware.RealIP)
-	r.Use(middleware.Logger)
+	r.Use(ipban.RealIP(trustedProxies))
+	r.Use(ClientIP)
+	r.Use(RequestLogger(log))
+	r.Use(ipban.Middleware(bans, func(w http.ResponseWriter, r *http.Request) {
+		handlers.WriteError(w, r, http.StatusForbidden, handlers.ErrCodeIPBanned)
+	}))
+	r.Use(logger.AccessLog(log, routePattern))
 	r.Use(middleware.Recoverer)
 	r.Use(middleware.Timeout(60 * time.Second))
-	r.Use(enableCORS)
-
-	// Debug middleware
-	r.Use(func(next http.Handler) http.Handler {
-		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			fmt.Printf("\n=== URL Parameters Debug ===\n")
-			fmt.Printf("Full URL: %s\n", r.URL.String())
-			fmt.Printf("Path: %s\n", r.URL.Path)
-
-			rctx := chi.RouteContext(r.Context())
-			if rctx != nil {
-				fmt.Printf("Chi Route Pattern: %s\n", rctx.RoutePattern())
-				fmt.Printf("Chi URL Params: %+v\n", rctx.URLParams)
-			} else {
-				fmt.Printf("Chi context is nil\n")
-			}
-			fmt.Printf("=== End URL Parameters Debug ===\n\n")
+	r.Use(enableCORS(runtime))
 
-			next.ServeHTTP(w, r)
-		})
-	})
+	// Загруженные файлы (вложения, аватары)
+	r.Handle("/static/uploads/*", uploadsHandler)
 
-	authMiddleware := &AuthMiddleware{JWTSecret: jwtSecret}
+	authMiddleware := &AuthMiddleware{Tokens: tokens, CookieAuth: cookieAuth, DebugUsers: debugUsers, Audit: auditLog}
 
 	r.Route("/api/v1", func(r chi.Router) {
-		// Public routes
+		// В режиме cookie-аутентификации изменяющие запросы должны содержать CSRF токен
+		if cookieAuth {
+			r.Use(csrf.Protect(AccessTokenCookie))
+		}
+
+		// Public routes: токен необязателен, но с ним скрывается контент заблокированных пользователей
 		r.Group(func(r chi.Router) {
+			r.Use(authMiddleware.Optional)
+
 			r.Get("/posts", postHandlers.GetPosts)
 			r.Get("/posts/{postId}", postHandlers.GetPost)
+			r.Get("/posts/slug/{slug}", postHandlers.GetPostBySlug)
+			r.Get("/posts/trending", trendingHandlers.GetTrending)
 			r.Get("/posts/{postId}/comments", commentHandlers.GetComments)
 			r.Get("/chat/messages", chatHandlers.GetMessages)
+			r.Get("/search", searchHandlers.Search)
+			r.Get("/users/{userId}/profile", profileHandlers.GetProfile)
+			r.Get("/users/{userId}/posts", postHandlers.GetUserPosts)
+			r.Get("/users/{userId}/stats", profileHandlers.GetStats)
+			r.Get("/announcements/active", announcementHandlers.Active)
+			r.Get("/categories", categoryHandlers.ListCategories)
+			r.Get("/categories/tree", categoryHandlers.CategoryTree)
 		})
 
 		// Authenticated routes
@@ -161,35 +341,127 @@ func NewRouter(
 			r.Put("/posts/{postId}", postHandlers.UpdatePost)
 			r.Delete("/posts/{postId}", postHandlers.DeletePost)
 			r.Post("/posts/{postId}/comments", commentHandlers.CreateComment)
-			r.Get("/chat/ws", chatHandlers.Connect)
+			r.Post("/posts/{postId}/share", shareHandlers.SharePost)
+			r.Post("/posts/{postId}/vote", postHandlers.VotePost)
+			r.Post("/posts/{postId}/report", reportHandlers.ReportPost)
+			r.Post("/comments/{commentId}/report", reportHandlers.ReportComment)
+			r.Post("/comments/{commentId}/vote", commentHandlers.VoteComment)
+			r.Get("/posts/{postId}/stats", shareHandlers.PostStats)
+			r.Post("/posts/{postId}/read", unreadHandlers.MarkPostRead)
+			r.Post("/categories/{categoryId}/read", unreadHandlers.MarkCategoryRead)
+			r.Get("/users/me/unread", unreadHandlers.GetUnread)
+			r.Delete("/posts/{postId}/comments/{commentId}", commentHandlers.DeleteComment)
+			r.Post("/posts/{postId}/comments/{commentId}/accept", commentHandlers.AcceptAnswer)
+			r.Delete("/posts/{postId}/comments/{commentId}/accept", commentHandlers.UnacceptAnswer)
+			r.Post("/attachments", attachmentHandlers.UploadAttachment)
+			r.Post("/users/me/avatar", profileHandlers.UploadAvatar)
+			r.Get("/users/me/blocks", blockHandlers.ListBlocks)
+			r.Post("/users/{userId}/block", blockHandlers.BlockUser)
+			r.Delete("/users/{userId}/block", blockHandlers.UnblockUser)
+			r.With(RequireScope(auth.ScopeChat)).Get("/chat/ws", chatHandlers.Connect)
+		})
+
+		// Admin routes
+		r.Group(func(r chi.Router) {
+			r.Use(authMiddleware.JWT)
+			r.Use(RequireRole(roles, "admin"))
+
+			r.Get("/admin/stats", statsHandlers.GetStats)
+			r.Get("/admin/ip-bans", ipBanHandlers.ListBans)
+			r.Post("/admin/ip-bans", ipBanHandlers.CreateBan)
+			r.Delete("/admin/ip-bans/{banId}", ipBanHandlers.DeleteBan)
+			r.Post("/admin/categories", categoryHandlers.CreateCategory)
+			r.Put("/admin/categories/{categoryId}", categoryHandlers.UpdateCategory)
+			r.Delete("/admin/categories/{categoryId}", categoryHandlers.DeleteCategory)
+			r.Get("/admin/categories/{categoryId}/moderators", categoryModeratorHandlers.ListModerators)
+			r.Post("/admin/categories/{categoryId}/moderators", categoryModeratorHandlers.AssignModerator)
+			r.Delete("/admin/categories/{categoryId}/moderators/{userId}", categoryModeratorHandlers.RemoveModerator)
+			r.Get("/admin/audit-log", auditHandlers.ListEntries)
+			r.Get("/admin/announcements", announcementHandlers.ListAnnouncements)
+			r.Post("/admin/announcements", announcementHandlers.CreateAnnouncement)
+			r.Put("/admin/announcements/{announcementId}", announcementHandlers.UpdateAnnouncement)
+			r.Delete("/admin/announcements/{announcementId}", announcementHandlers.DeleteAnnouncement)
+			r.Handle("/admin/log-level", logLevelHandler)
+		})
+
+		// Moderation routes: права (глобальные или по категориям) проверяет use case
+		r.Group(func(r chi.Router) {
+			r.Use(authMiddleware.JWT)
+
+			r.Get("/moderation/queue", moderationHandlers.Queue)
+			r.Get("/moderation/reports", reportHandlers.ListReports)
+			r.Post("/moderation/posts/{postId}/approve", moderationHandlers.ApprovePost)
+			r.Post("/moderation/posts/{postId}/reject", moderationHandlers.RejectPost)
+			r.Post("/moderation/comments/{commentId}/approve", moderationHandlers.ApproveComment)
+			r.Post("/moderation/comments/{commentId}/reject", moderationHandlers.RejectComment)
+			r.Post("/posts/{postId}/pin", moderationHandlers.PinPost)
+			r.Post("/posts/{postId}/unpin", moderationHandlers.UnpinPost)
+			r.Post("/posts/{postId}/lock", moderationHandlers.LockPost)
+			r.Post("/posts/{postId}/unlock", moderationHandlers.UnlockPost)
 		})
 	})
 
+	// Короткие ссылки на посты
+	r.Get("/s/{code}", shareHandlers.OpenLink)
+
 	// Health check endpoint
-	r.Get("/health", func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte("OK"))
-	})
+	r.Get("/health", healthHandlers.Health)
+
+	// Метрики Prometheus
+	r.Handle("/metrics", promhttp.Handler())
 
 	return r
 }
 
-func enableCORS(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Устанавливаем базовые CORS заголовки
-		w.Header().Set("Access-Control-Allow-Origin", "http://localhost:3000")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Authorization, Content-Type, Accept")
-		w.Header().Set("Access-Control-Allow-Credentials", "true")
-		w.Header().Set("Access-Control-Max-Age", "3600")
-		w.Header().Set("Access-Control-Expose-Headers", "Authorization")
-
-		// Обработка preflight запросов
-		if r.Method == "OPTIONS" {
-			w.WriteHeader(http.StatusOK)
-			return
+// WebSocketOrigin проверяет Origin запроса на подключение к чату. Запросы не из браузера (без Origin)
+// и со страниц того же хоста разрешены. Остальные origin проверяются по runtime настройкам, а при
+// cookie-аутентификации должны быть указаны явно: cookie браузер отправит с любого сайта.
+func WebSocketOrigin(runtime *config.RuntimeWatcher, cookieAuth bool) func(r *http.Request) bool {
+	return func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			return true
 		}
+		if u, err := url.Parse(origin); err == nil && strings.EqualFold(u.Host, r.Host) {
+			return true
+		}
+		settings := runtime.Current()
+		if cookieAuth {
+			return settings.ListsOrigin(origin)
+		}
+		return settings.AllowsOrigin(origin)
+	}
+}
 
-		next.ServeHTTP(w, r)
-	})
+// enableCORS разрешает origin из текущих runtime настроек, поэтому список можно менять без перезапуска
+func enableCORS(runtime *config.RuntimeWatcher) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Явно указанному origin разрешаются запросы с cookie, остальным при "*" - только без них
+			origin := r.Header.Get("Origin")
+			if origin != "" {
+				w.Header().Add("Vary", "Origin")
+				settings := runtime.Current()
+				switch {
+				case settings.ListsOrigin(origin):
+					w.Header().Set("Access-Control-Allow-Origin", origin)
+					w.Header().Set("Access-Control-Allow-Credentials", "true")
+				case settings.AllowsOrigin(origin):
+					w.Header().Set("Access-Control-Allow-Origin", "*")
+				}
+			}
+			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
+			w.Header().Set("Access-Control-Allow-Headers", "Authorization, Content-Type, Accept, X-CSRF-Token")
+			w.Header().Set("Access-Control-Max-Age", "3600")
+			w.Header().Set("Access-Control-Expose-Headers", "Authorization, X-Error-Code")
+
+			// Обработка preflight запросов
+			if r.Method == "OPTIONS" {
+				w.WriteHeader(http.StatusOK)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
 }
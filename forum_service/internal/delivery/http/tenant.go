@@ -0,0 +1,37 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/kprf42/dolgova/forum_service/internal/delivery/http/handlers"
+	"github.com/kprf42/dolgova/forum_service/internal/tenant"
+)
+
+// tenantPathPrefix префикс пути для выбора сообщества: /t/{tenant}/api/v1/...
+const tenantPathPrefix = "/t/"
+
+// ResolveTenant определяет сообщество по префиксу пути или хосту и кладет его в контекст.
+// Префикс срезается до маршрутизации, поэтому маршруты одинаковы для всех сообществ.
+func ResolveTenant(resolver *tenant.Resolver) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			tenantID := resolver.ByHost(r.Host)
+
+			if strings.HasPrefix(r.URL.Path, tenantPathPrefix) {
+				rest := strings.TrimPrefix(r.URL.Path, tenantPathPrefix)
+				id, path, _ := strings.Cut(rest, "/")
+				if !resolver.Exists(id) {
+					handlers.WriteError(w, r, http.StatusNotFound, handlers.ErrCodeUnknownForum)
+					return
+				}
+
+				tenantID = id
+				r.URL.Path = "/" + path
+				r.URL.RawPath = ""
+			}
+
+			next.ServeHTTP(w, r.WithContext(tenant.WithID(r.Context(), tenantID)))
+		})
+	}
+}
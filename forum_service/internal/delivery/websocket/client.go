@@ -8,6 +8,7 @@ import (
 
 	"github.com/gorilla/websocket"
 	"github.com/kprf42/dolgova/forum_service/internal/entity"
+	"github.com/kprf42/dolgova/forum_service/internal/tenant"
 )
 
 const (
@@ -17,25 +18,29 @@ const (
 	maxMessageSize = 512
 )
 
+// upgrader общие параметры подключения; CheckOrigin задается в ServeWs
 var upgrader = websocket.Upgrader{
-	ReadBufferSize:  1024,
-	WriteBufferSize: 1024,
-	CheckOrigin: func(r *http.Request) bool {
-		return true // Разрешаем все origin в development
-	},
+	ReadBufferSize:    1024,
+	WriteBufferSize:   1024,
 	EnableCompression: true,
 }
 
 type Client struct {
-	hub    *Hub
-	conn   *websocket.Conn
-	send   chan *entity.ChatMessage
-	userID string
+	hub      *Hub
+	conn     *websocket.Conn
+	send     chan *entity.ChatMessage
+	userID   string
+	tenantID string
+	blocked  map[string]bool // Заблокированные пользователи; обновляется только в Hub.Run
+	readOnly bool            // Клиент только получает сообщения; отправленные им отбрасываются
 }
 
 func (c *Client) readPump() {
 	defer func() {
-		c.hub.unregister <- c
+		select {
+		case c.hub.unregister <- c:
+		case <-c.hub.done:
+		}
 		c.conn.Close()
 	}()
 
@@ -56,8 +61,16 @@ func (c *Client) readPump() {
 			break
 		}
 
-		msg := entity.NewChatMessage(&msgReq, c.userID)
-		c.hub.broadcast <- msg
+		if c.readOnly {
+			continue
+		}
+
+		msg := entity.NewChatMessage(&msgReq, c.userID, c.tenantID)
+		select {
+		case c.hub.broadcast <- msg:
+		case <-c.hub.done:
+			return
+		}
 	}
 }
 
@@ -95,12 +108,10 @@ func (c *Client) writePump() {
 	}
 }
 
-func ServeWs(hub *Hub, w http.ResponseWriter, r *http.Request, userID string) {
-	// Устанавливаем CORS заголовки
-	w.Header().Set("Access-Control-Allow-Origin", "http://localhost:3000")
-	w.Header().Set("Access-Control-Allow-Credentials", "true")
-	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Requested-With")
-
+// ServeWs подключает пользователя userID к чату; readOnly клиент только получает сообщения.
+// checkOrigin решает, можно ли подключиться со страницы из заголовка Origin: CORS к WebSocket
+// не применяется, поэтому без проверки чужой сайт подключился бы с cookie пользователя.
+func ServeWs(hub *Hub, w http.ResponseWriter, r *http.Request, checkOrigin func(r *http.Request) bool, userID string, readOnly bool) {
 	// Проверяем метод запроса
 	if r.Method != http.MethodGet {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
@@ -113,9 +124,11 @@ func ServeWs(hub *Hub, w http.ResponseWriter, r *http.Request, userID string) {
 		return
 	}
 
-	// Добавляем отладочное логирование
-	log.Printf("Attempting WebSocket upgrade. Headers: %+v", r.Header)
+	// Заголовки не логируем: в них токен (Authorization) и cookie
+	log.Printf("Attempting WebSocket upgrade from %s", r.RemoteAddr)
 
+	upgrader := upgrader
+	upgrader.CheckOrigin = checkOrigin
 	conn, err := upgrader.Upgrade(w, r, nil)
 	if err != nil {
 		log.Printf("WebSocket upgrade error: %v", err)
@@ -125,12 +138,19 @@ func ServeWs(hub *Hub, w http.ResponseWriter, r *http.Request, userID string) {
 	log.Printf("WebSocket connection established for user: %s", userID)
 
 	client := &Client{
-		hub:    hub,
-		conn:   conn,
-		send:   make(chan *entity.ChatMessage, 256),
-		userID: userID,
+		hub:      hub,
+		conn:     conn,
+		send:     make(chan *entity.ChatMessage, 256),
+		userID:   userID,
+		tenantID: tenant.FromContext(r.Context()),
+		readOnly: readOnly,
+	}
+	select {
+	case client.hub.register <- client:
+	case <-client.hub.done:
+		conn.Close()
+		return
 	}
-	client.hub.register <- client
 
 	go client.writePump()
 	go client.readPump()
@@ -2,42 +2,150 @@ package websocket
 
 import (
 	"context"
+	"errors"
 	"log"
+	"sync/atomic"
 
+	"github.com/kprf42/dolgova/forum_service/internal/auth"
 	"github.com/kprf42/dolgova/forum_service/internal/entity"
+	"github.com/kprf42/dolgova/forum_service/internal/events"
+	"github.com/kprf42/dolgova/forum_service/internal/tenant"
 )
 
+// ErrHubStopped цикл Run не запущен или уже завершился
+var ErrHubStopped = errors.New("websocket hub is stopped")
+
 type Hub struct {
 	clients    map[*Client]bool
 	broadcast  chan *entity.ChatMessage
+	deliver    chan *entity.ChatMessage // Уже сохраненные сообщения из других источников (gRPC)
 	register   chan *Client
 	unregister chan *Client
+	ping       chan chan struct{}
+	blocks     chan string // Пользователи, у которых изменился список блокировок
 	chatUC     ChatUseCase
+	blockList  BlockList
+	quit       chan struct{}
+	done       chan struct{}
+
+	// connections дублирует len(clients) для чтения вне горутины Run
+	connections atomic.Int64
 }
 
 type ChatUseCase interface {
 	SaveMessage(ctx context.Context, msg *entity.ChatMessage) error
-	GetMessages(ctx context.Context, limit, offset int) ([]*entity.ChatMessage, error)
+	GetMessages(ctx context.Context, limit, offset int, after *entity.PageCursor) ([]*entity.ChatMessage, error)
+}
+
+// BlockList возвращает пользователей, заблокированных userID: их сообщения клиенту не отправляются
+type BlockList interface {
+	BlockedIDs(ctx context.Context, userID string) ([]string, error)
 }
 
-func NewHub(chatUC ChatUseCase) *Hub {
+// NewHub создает хаб чата. blockList может быть nil: тогда сообщения не фильтруются.
+func NewHub(chatUC ChatUseCase, blockList BlockList) *Hub {
 	return &Hub{
 		broadcast:  make(chan *entity.ChatMessage),
+		deliver:    make(chan *entity.ChatMessage),
 		register:   make(chan *Client),
 		unregister: make(chan *Client),
+		ping:       make(chan chan struct{}),
+		blocks:     make(chan string),
 		clients:    make(map[*Client]bool),
 		chatUC:     chatUC,
+		blockList:  blockList,
+		quit:       make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+}
+
+// Stop останавливает цикл Run и закрывает соединения всех клиентов
+func (h *Hub) Stop(ctx context.Context) error {
+	close(h.quit)
+
+	select {
+	case <-h.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Ping проверяет, что цикл Run работает и не завис на обработке сообщения
+func (h *Hub) Ping(ctx context.Context) error {
+	reply := make(chan struct{})
+	select {
+	case h.ping <- reply:
+	case <-h.done:
+		return ErrHubStopped
+	case <-ctx.Done():
+		return ctx.Err()
 	}
+
+	select {
+	case <-reply:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Deliver рассылает клиентам сообщества сообщение, уже сохраненное через ChatUseCase
+// (например, отправленное по gRPC). В отличие от сообщений клиентов, повторно не сохраняется.
+func (h *Hub) Deliver(ctx context.Context, msg *entity.ChatMessage) error {
+	select {
+	case h.deliver <- msg:
+		return nil
+	case <-h.done:
+		return ErrHubStopped
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// HandleEvent обновляет списки блокировок подключенных клиентов пользователя,
+// изменившего блокировки. Подписывается на шину событий.
+func (h *Hub) HandleEvent(ctx context.Context, event events.Event) {
+	if event.Type != events.UserBlocked && event.Type != events.UserUnblocked {
+		return
+	}
+	select {
+	case h.blocks <- event.ID:
+	case <-h.done:
+	case <-ctx.Done():
+	}
+}
+
+// ClientCount возвращает количество активных WebSocket соединений
+func (h *Hub) ClientCount() int {
+	return int(h.connections.Load())
 }
 
 func (h *Hub) Run() {
+	defer close(h.done)
+
 	for {
 		select {
+		case <-h.quit:
+			// Закрытие send завершает writePump, который отправит клиенту close frame
+			for client := range h.clients {
+				delete(h.clients, client)
+				close(client.send)
+			}
+			h.connections.Store(0)
+			return
+
+		case reply := <-h.ping:
+			close(reply)
+
 		case client := <-h.register:
 			h.clients[client] = true
+			h.connections.Store(int64(len(h.clients)))
+			client.blocked = h.blockedBy(client.userID)
 
-			// Отправляем историю сообщений новому клиенту
-			messages, err := h.chatUC.GetMessages(context.Background(), 100, 0)
+			// Отправляем историю сообщений комнаты новому клиенту без сообщений заблокированных им пользователей
+			ctx := auth.WithUserID(tenant.WithID(context.Background(), client.tenantID), client.userID)
+			messages, err := h.chatUC.GetMessages(ctx, 100, 0, nil)
 			if err == nil {
 				for _, msg := range messages {
 					client.send <- msg
@@ -48,24 +156,62 @@ func (h *Hub) Run() {
 			if _, ok := h.clients[client]; ok {
 				delete(h.clients, client)
 				close(client.send)
+				h.connections.Store(int64(len(h.clients)))
 			}
 
 		case message := <-h.broadcast:
 			// Сохраняем сообщение в БД
-			if err := h.chatUC.SaveMessage(context.Background(), message); err != nil {
+			if err := h.chatUC.SaveMessage(tenant.WithID(context.Background(), message.TenantID), message); err != nil {
 				log.Printf("Error saving message: %v", err)
 				continue
 			}
 
-			// Рассылаем сообщение всем клиентам
+			h.fanOut(message)
+
+		case message := <-h.deliver:
+			h.fanOut(message)
+
+		case userID := <-h.blocks:
+			blocked := h.blockedBy(userID)
 			for client := range h.clients {
-				select {
-				case client.send <- message:
-				default:
-					close(client.send)
-					delete(h.clients, client)
+				if client.userID == userID {
+					client.blocked = blocked
 				}
 			}
 		}
 	}
 }
+
+// blockedBy загружает пользователей, заблокированных userID. При ошибке фильтр не применяется:
+// чат продолжает работать, а список обновится при следующем подключении.
+func (h *Hub) blockedBy(userID string) map[string]bool {
+	if h.blockList == nil {
+		return nil
+	}
+	ids, err := h.blockList.BlockedIDs(context.Background(), userID)
+	if err != nil {
+		log.Printf("Error loading blocked users of %s: %v", userID, err)
+		return nil
+	}
+	blocked := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		blocked[id] = true
+	}
+	return blocked
+}
+
+// fanOut рассылает сообщение клиентам того же сообщества, кроме заблокировавших автора
+func (h *Hub) fanOut(message *entity.ChatMessage) {
+	for client := range h.clients {
+		if client.tenantID != message.TenantID || client.blocked[message.UserID] {
+			continue
+		}
+		select {
+		case client.send <- message:
+		default:
+			close(client.send)
+			delete(h.clients, client)
+		}
+	}
+	h.connections.Store(int64(len(h.clients)))
+}
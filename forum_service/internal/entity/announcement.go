@@ -0,0 +1,41 @@
+package entity
+
+import "time"
+
+// Важность объявления
+const (
+	SeverityInfo     = "info"
+	SeverityWarning  = "warning"
+	SeverityCritical = "critical"
+)
+
+// Аудитория объявления
+const (
+	AudienceAll    = "all"    // Все посетители
+	AudienceUsers  = "users"  // Только вошедшие пользователи
+	AudienceGuests = "guests" // Только анонимные посетители
+)
+
+// Announcement объявление-баннер, видимое с StartsAt до EndsAt
+type Announcement struct {
+	ID        string     `json:"id"`
+	Title     string     `json:"title"`
+	Body      string     `json:"body"`
+	Severity  string     `json:"severity"`
+	Audience  string     `json:"audience"`
+	StartsAt  time.Time  `json:"starts_at"`
+	EndsAt    *time.Time `json:"ends_at,omitempty"`
+	CreatedBy string     `json:"created_by"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// AnnouncementRequest запрос на создание или замену объявления. Без starts_at объявление начинается сразу.
+type AnnouncementRequest struct {
+	Title    string     `json:"title"`
+	Body     string     `json:"body"`
+	Severity string     `json:"severity"`
+	Audience string     `json:"audience"`
+	StartsAt *time.Time `json:"starts_at"`
+	EndsAt   *time.Time `json:"ends_at"`
+}
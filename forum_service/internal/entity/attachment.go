@@ -0,0 +1,38 @@
+package entity
+
+import "time"
+
+// К чему привязано вложение
+const (
+	AttachmentPost    = "post"
+	AttachmentComment = "comment"
+	AttachmentChat    = "chat_message"
+)
+
+// Состояния генерации уменьшенных копий
+const (
+	ThumbnailNone    = "none"    // Не изображение, копии не нужны
+	ThumbnailPending = "pending" // Ждет обработки
+	ThumbnailReady   = "ready"
+	ThumbnailFailed  = "failed"
+)
+
+// MaxAttachments максимум вложений у одного поста, комментария или сообщения
+const MaxAttachments = 10
+
+// Attachment загруженный файл
+type Attachment struct {
+	ID          string            `json:"id"`
+	OwnerID     string            `json:"owner_id"`
+	URL         string            `json:"url"`
+	ContentType string            `json:"content_type"`
+	Size        int64             `json:"size"`
+	Variants    map[string]string `json:"variants,omitempty"` // Уменьшенные копии изображения: имя -> URL
+	CreatedAt   time.Time         `json:"created_at"`
+
+	StorageKey     string `json:"-"`
+	TargetType     string `json:"-"`
+	TargetID       string `json:"-"`
+	ThumbnailState string `json:"-"`
+	TenantID       string `json:"-"`
+}
@@ -0,0 +1,10 @@
+package entity
+
+import "time"
+
+// Block блокировка пользователя: BlockerID не видит контент BlockedID
+type Block struct {
+	BlockerID string    `json:"blocker_id"`
+	BlockedID string    `json:"blocked_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
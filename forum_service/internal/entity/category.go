@@ -0,0 +1,30 @@
+package entity
+
+import "time"
+
+// Category категория форума; посты публикуются только в существующие категории.
+// Категории образуют дерево: подфорум ссылается на родителя через ParentID.
+type Category struct {
+	ID          string    `json:"id"`
+	ParentID    string    `json:"parent_id,omitempty"` // Пусто у корневых категорий
+	Depth       int       `json:"depth"`               // Уровень вложенности, 0 у корневых категорий
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Position    int       `json:"position"` // Порядок среди категорий с тем же родителем
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// CategoryNode категория с подкатегориями в ответе дерева категорий
+type CategoryNode struct {
+	*Category
+	Children []*CategoryNode `json:"children"`
+}
+
+// CategoryRequest запрос на создание или замену категории. Без parent_id категория корневая.
+type CategoryRequest struct {
+	ParentID    string `json:"parent_id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Position    int    `json:"position"`
+}
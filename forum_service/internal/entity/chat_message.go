@@ -11,17 +11,26 @@ type ChatMessage struct {
 	UserID    string    `json:"user_id" db:"user_id" validate:"required,uuid4"`
 	Text      string    `json:"text" db:"text" validate:"required,min=1,max=1000"`
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	TenantID  string    `json:"-" db:"tenant_id"` // Комната чата = сообщество
+
+	AttachmentIDs []string      `json:"-" db:"-"` // Вложения из запроса, привязываются при сохранении
+	Attachments   []*Attachment `json:"attachments,omitempty" db:"-"`
+	Author        *Author       `json:"author,omitempty" db:"-"` // Нет, если auth сервис недоступен или автор удален
 }
 
 type ChatMessageRequest struct {
-	Text string `json:"text" validate:"required,min=1,max=1000"`
+	Text          string   `json:"text" validate:"required,min=1,max=1000"`
+	AttachmentIDs []string `json:"attachment_ids,omitempty"`
 }
 
-func NewChatMessage(req *ChatMessageRequest, userID string) *ChatMessage {
+func NewChatMessage(req *ChatMessageRequest, userID, tenantID string) *ChatMessage {
 	return &ChatMessage{
 		ID:        uuid.New().String(),
 		UserID:    userID,
 		Text:      req.Text,
 		CreatedAt: time.Now().UTC(),
+		TenantID:  tenantID,
+
+		AttachmentIDs: req.AttachmentIDs,
 	}
 }
@@ -11,12 +11,33 @@ type Comment struct {
 	Content   string    `json:"content" validate:"required,min=3,max=500"`
 	PostID    string    `json:"post_id" validate:"required,uuid4"`
 	AuthorID  string    `json:"author_id"`
+	Score     int       `json:"score"` // Сумма голосов: +1 за каждый голос за, -1 против
+	Status    string    `json:"status"`
 	CreatedAt time.Time `json:"created_at"`
+
+	Author      *Author       `json:"author,omitempty"` // Нет, если auth сервис недоступен или автор удален
+	AuthorKarma int           `json:"author_karma"`
+	Attachments []*Attachment `json:"attachments,omitempty"`
+	IsAccepted  bool          `json:"is_accepted,omitempty"` // Автор поста отметил комментарий принятым ответом
 }
 
 type CommentRequest struct {
 	Content string `json:"content" validate:"required,min=3,max=500"`
 	PostID  string `json:"post_id" validate:"required,uuid4"`
+	// AttachmentIDs загруженные автором вложения (POST /attachments)
+	AttachmentIDs []string `json:"attachment_ids,omitempty"`
+}
+
+// CommentVoteRequest голос за комментарий: 1 - за, -1 - против, 0 - снять голос
+type CommentVoteRequest struct {
+	Value int `json:"value"`
+}
+
+// CommentVote итог голосования за комментарий после голоса пользователя
+type CommentVote struct {
+	CommentID string `json:"comment_id"`
+	Score     int    `json:"score"`
+	Vote      int    `json:"vote"` // Текущий голос пользователя
 }
 
 type CommentResponse struct {
@@ -33,6 +54,7 @@ func NewComment(req *CommentRequest, authorID string) *Comment {
 		Content:   req.Content,
 		PostID:    req.PostID,
 		AuthorID:  authorID,
+		Status:    StatusPublished,
 		CreatedAt: time.Now().UTC(),
 	}
 }
@@ -0,0 +1,8 @@
+package entity
+
+// Источники кармы
+const (
+	KarmaPostVote       = "post_vote"       // Голос за пост автора
+	KarmaCommentVote    = "comment_vote"    // Голос за комментарий автора
+	KarmaAcceptedAnswer = "accepted_answer" // Комментарий автора отмечен принятым ответом
+)
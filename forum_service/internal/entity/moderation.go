@@ -0,0 +1,24 @@
+package entity
+
+import "time"
+
+// Статусы модерации постов и комментариев
+const (
+	StatusPublished = "published" // Виден всем
+	StatusPending   = "pending"   // Ждет одобрения модератора
+	StatusRejected  = "rejected"  // Отклонен модератором
+)
+
+// ModerationQueue контент, ожидающий модерации
+type ModerationQueue struct {
+	Posts    []*Post    `json:"posts"`
+	Comments []*Comment `json:"comments"`
+}
+
+// CategoryModerator назначение пользователя модератором категории
+type CategoryModerator struct {
+	CategoryID string    `json:"category_id"`
+	UserID     string    `json:"user_id"`
+	CreatedBy  string    `json:"created_by"`
+	CreatedAt  time.Time `json:"created_at"`
+}
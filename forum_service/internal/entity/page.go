@@ -0,0 +1,10 @@
+package entity
+
+import "time"
+
+// PageCursor позиция в списке, отсортированном по (created_at, id) от новых к старым.
+// Следующая страница начинается с записей строго после курсора.
+type PageCursor struct {
+	CreatedAt time.Time `json:"created_at"`
+	ID        string    `json:"id"`
+}
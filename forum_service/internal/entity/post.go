@@ -4,11 +4,16 @@ import "time"
 
 type Post struct {
 	ID         string    `json:"id"`
+	Slug       string    `json:"slug"` // Адрес поста из заголовка: GET /posts/slug/{slug}
 	Title      string    `json:"title"`
 	Content    string    `json:"content"`
 	AuthorID   string    `json:"author_id"`
 	CategoryID string    `json:"category_id"`
 	IsPinned   bool      `json:"is_pinned"`
+	IsLocked   bool      `json:"is_locked"`                     // Тема закрыта модератором, новые комментарии не принимаются
+	Score      int       `json:"score"`                         // Сумма голосов: +1 за каждый голос за, -1 против
+	AnswerID   string    `json:"accepted_comment_id,omitempty"` // Комментарий, отмеченный автором принятым ответом
+	Status     string    `json:"status"`
 	CreatedAt  time.Time `json:"created_at"`
 }
 
@@ -16,21 +21,84 @@ type PostRequest struct {
 	Title      string `json:"title" validate:"required,min=3,max=100"`
 	Content    string `json:"content" validate:"required,min=10"`
 	CategoryID string `json:"category_id" validate:"required"`
+	// AttachmentIDs загруженные автором вложения (POST /attachments)
+	AttachmentIDs []string `json:"attachment_ids,omitempty"`
 }
 
 type PostUpdate struct {
 	Title   string `json:"title" validate:"required,min=3,max=100"`
 	Content string `json:"content" validate:"required,min=10"`
+	// UpdateMask поля, которые нужно изменить (title, content). Пустая маска - все поля.
+	UpdateMask []string `json:"-"`
 }
 
 type PostResponse struct {
 	ID         string    `json:"id"`
+	Slug       string    `json:"slug"`
 	Title      string    `json:"title"`
 	Content    string    `json:"content"`
 	AuthorID   string    `json:"author_id"`
 	CategoryID string    `json:"category_id"`
 	IsPinned   bool      `json:"is_pinned"`
+	IsLocked   bool      `json:"is_locked"`
+	Score      int       `json:"score"`
+	AnswerID   string    `json:"accepted_comment_id,omitempty"`
+	Status     string    `json:"status"`
 	CreatedAt  time.Time `json:"created_at"`
+
+	Author      *Author       `json:"author,omitempty"` // Нет, если auth сервис недоступен или автор удален
+	AuthorKarma int           `json:"author_karma"`
+	IsUnread    *bool         `json:"is_unread,omitempty"` // Только в списках для аутентифицированного читателя
+	Attachments []*Attachment `json:"attachments,omitempty"`
+}
+
+// Порядок списка постов
+const (
+	PostSortNew   = "new"   // От новых к старым
+	PostSortTop   = "top"   // По сумме голосов за окно PostFilter.Window, при равенстве от новых к старым
+	PostSortHot   = "hot"   // По сумме голосов с поправкой на возраст поста
+	PostSortScore = "score" // По сумме голосов за все время, то же что top с окном all
+)
+
+// Окно сортировки top
+const (
+	PostWindowHour  = "hour"
+	PostWindowDay   = "day"
+	PostWindowWeek  = "week"
+	PostWindowMonth = "month"
+	PostWindowYear  = "year"
+	PostWindowAll   = "all"
+)
+
+// PostFilter условия выборки списка постов
+type PostFilter struct {
+	CategoryID           string    // Пусто - все категории
+	IncludeSubcategories bool      // Вместе с постами всех подкатегорий CategoryID
+	Sort                 string    // PostSortNew (по умолчанию), PostSortTop, PostSortHot или PostSortScore
+	Window               string    // Окно для PostSortTop, по умолчанию PostWindowDay
+	Since                time.Time // Только посты, созданные не раньше; нулевое значение - без ограничения
+	// Chronological строго по дате, без закрепленных постов наверху: для обхода по PageCursor,
+	// где закрепленный пост в середине страницы сбивал бы курсор
+	Chronological bool
+}
+
+// PostVoteRequest голос за пост: 1 - за, -1 - против, 0 - снять голос
+type PostVoteRequest struct {
+	Value int `json:"value"`
+}
+
+// PostVote итог голосования после голоса пользователя
+type PostVote struct {
+	PostID string `json:"post_id"`
+	Score  int    `json:"score"`
+	Vote   int    `json:"vote"` // Текущий голос пользователя
+}
+
+// TrendingPosts популярные посты по активности за последние Hours часов
+type TrendingPosts struct {
+	Posts     []*PostResponse `json:"posts"`
+	Hours     int             `json:"hours"`
+	UpdatedAt time.Time       `json:"updated_at"` // Когда список был пересчитан
 }
 
 type PostErrorResponse struct {
@@ -0,0 +1,22 @@
+package entity
+
+import "time"
+
+// Profile профиль пользователя на форуме
+type Profile struct {
+	UserID    string            `json:"user_id"`
+	Karma     int               `json:"karma"`
+	AvatarURL string            `json:"avatar_url,omitempty"`
+	Avatars   map[string]string `json:"avatars,omitempty"` // Аватар в стандартных размерах: сторона в пикселях -> URL
+	UpdatedAt time.Time         `json:"updated_at"`
+}
+
+// UserStats активность и карма пользователя на форуме
+type UserStats struct {
+	UserID          string         `json:"user_id"`
+	Karma           int            `json:"karma"`
+	KarmaBySource   map[string]int `json:"karma_by_source"` // Источник начисления (KarmaPostVote, ...) -> очки
+	Posts           int            `json:"posts"`
+	Comments        int            `json:"comments"`
+	AcceptedAnswers int            `json:"accepted_answers"`
+}
@@ -0,0 +1,13 @@
+package entity
+
+import "time"
+
+// UnreadThread пост, в котором после последнего визита пользователя появились новые комментарии
+type UnreadThread struct {
+	PostID         string    `json:"post_id"`
+	Title          string    `json:"title"`
+	CategoryID     string    `json:"category_id"`
+	NewComments    int       `json:"new_comments"`
+	LastSeenAt     time.Time `json:"last_seen_at"`
+	LastActivityAt time.Time `json:"last_activity_at"`
+}
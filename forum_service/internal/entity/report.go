@@ -0,0 +1,35 @@
+package entity
+
+import "time"
+
+// Что обжалуется
+const (
+	ReportTargetPost    = "post"
+	ReportTargetComment = "comment"
+)
+
+// Причина жалобы
+const (
+	ReportReasonSpam     = "spam"
+	ReportReasonAbuse    = "abuse"     // Оскорбления, травля
+	ReportReasonOffTopic = "off_topic" // Не по теме категории
+	ReportReasonIllegal  = "illegal"   // Нарушает закон
+	ReportReasonOther    = "other"     // Подробности в details
+)
+
+// Report жалоба пользователя на пост или комментарий
+type Report struct {
+	ID         string    `json:"id"`
+	TargetType string    `json:"target_type"`
+	TargetID   string    `json:"target_id"`
+	CategoryID string    `json:"category_id"`
+	ReporterID string    `json:"reporter_id"`
+	Reason     string    `json:"reason"`
+	Details    string    `json:"details,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type ReportRequest struct {
+	Reason  string `json:"reason"`
+	Details string `json:"details"`
+}
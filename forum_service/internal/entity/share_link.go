@@ -0,0 +1,21 @@
+package entity
+
+import "time"
+
+// ShareLink короткая ссылка на пост
+type ShareLink struct {
+	Code      string    `json:"code"`
+	URL       string    `json:"url"` // Путь короткой ссылки: /s/{code}
+	PostID    string    `json:"post_id"`
+	CreatedBy string    `json:"created_by"`
+	Clicks    int       `json:"clicks"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// PostStats статистика поста для автора и модераторов
+type PostStats struct {
+	PostID      string       `json:"post_id"`
+	Comments    int          `json:"comments"`
+	ShareClicks int          `json:"share_clicks"` // Переходы по всем коротким ссылкам
+	ShareLinks  []*ShareLink `json:"share_links"`
+}
@@ -0,0 +1,26 @@
+package entity
+
+import "time"
+
+type StatsTotals struct {
+	Users             int `json:"users"`
+	Posts             int `json:"posts"`
+	Comments          int `json:"comments"`
+	ChatMessages      int `json:"chat_messages"`
+	ActiveConnections int `json:"active_connections"`
+}
+
+type DailyStats struct {
+	Date         string `json:"date"` // YYYY-MM-DD (UTC)
+	Users        int    `json:"users"`
+	Posts        int    `json:"posts"`
+	Comments     int    `json:"comments"`
+	ChatMessages int    `json:"chat_messages"`
+}
+
+type StatsResponse struct {
+	From   time.Time     `json:"from"`
+	To     time.Time     `json:"to"`
+	Totals StatsTotals   `json:"totals"`
+	Daily  []*DailyStats `json:"daily"`
+}
@@ -0,0 +1,45 @@
+package entity
+
+import "time"
+
+// User пользователь auth сервиса (таблица users в общей БД)
+type User struct {
+	ID        string    `json:"id"`
+	Username  string    `json:"username"`
+	Email     string    `json:"email"`
+	Password  string    `json:"-"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Author публичные данные автора поста, комментария или сообщения чата из auth сервиса
+type Author struct {
+	ID          string `json:"id"`
+	Username    string `json:"username"`
+	DisplayName string `json:"display_name,omitempty"`
+	AvatarURL   string `json:"avatar_url,omitempty"`
+	Karma       int    `json:"karma,omitempty"` // Заполняется в постах и комментариях
+}
+
+// DeletedUserID автор постов, комментариев и сообщений чата удаленных пользователей
+const DeletedUserID = "deleted"
+
+// AnonymizeResult сколько записей удаленного пользователя переназначено DeletedUserID
+type AnonymizeResult struct {
+	Posts        int64 `json:"posts"`
+	Comments     int64 `json:"comments"`
+	ChatMessages int64 `json:"chat_messages"`
+}
+
+// ExportedPost пост в выгрузке данных пользователя. Выгрузка идет по всем сообществам,
+// поэтому запись помечена сообществом.
+type ExportedPost struct {
+	Post
+	TenantID string `json:"forum_id"`
+}
+
+// ExportedComment комментарий в выгрузке данных пользователя
+type ExportedComment struct {
+	Comment
+	TenantID string `json:"forum_id"`
+}
@@ -0,0 +1,70 @@
+// Package events содержит шину доменных событий форума
+package events
+
+import (
+	"context"
+	"sync"
+
+	"github.com/kprf42/dolgova/forum_service/internal/entity"
+)
+
+// Type тип доменного события
+type Type string
+
+const (
+	PostCreated    Type = "post.created"
+	PostUpdated    Type = "post.updated"
+	PostDeleted    Type = "post.deleted"
+	CommentCreated Type = "comment.created"
+	CommentUpdated Type = "comment.updated"
+	CommentDeleted Type = "comment.deleted"
+	AnswerAccepted Type = "answer.accepted" // Уведомление автору комментария: Comment принят ответом на Post
+	UserBlocked    Type = "user.blocked"
+	UserUnblocked  Type = "user.unblocked"
+)
+
+// Event доменное событие. Для удалений заполнен только ID,
+// для блокировок ID - пользователь, изменивший список блокировок.
+type Event struct {
+	Type     Type
+	TenantID string
+	ID       string
+	Post     *entity.Post
+	Comment  *entity.Comment
+}
+
+// Handler обработчик событий
+type Handler func(ctx context.Context, event Event)
+
+// Bus синхронная шина событий внутри процесса
+type Bus struct {
+	mu       sync.RWMutex
+	handlers []Handler
+}
+
+func NewBus() *Bus {
+	return &Bus{}
+}
+
+// Subscribe регистрирует обработчик всех событий
+func (b *Bus) Subscribe(handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers = append(b.handlers, handler)
+}
+
+// Publish передает событие всем подписчикам. Ошибки обработчики обрабатывают сами.
+// Допускает nil шину, чтобы use cases работали и без подписчиков.
+func (b *Bus) Publish(ctx context.Context, event Event) {
+	if b == nil {
+		return
+	}
+
+	b.mu.RLock()
+	handlers := append([]Handler{}, b.handlers...)
+	b.mu.RUnlock()
+
+	for _, handler := range handlers {
+		handler(ctx, event)
+	}
+}
@@ -0,0 +1,202 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// Поддерживаемые форматы входных данных
+const (
+	FormatGeneric   = "generic"
+	FormatDiscourse = "discourse"
+	FormatPhpBB     = "phpbb"
+)
+
+// Decode читает данные в указанном формате и приводит их к общей схеме
+func Decode(r io.Reader, format string) (*Source, error) {
+	switch format {
+	case FormatGeneric:
+		var src Source
+		if err := json.NewDecoder(r).Decode(&src); err != nil {
+			return nil, fmt.Errorf("failed to decode generic data: %w", err)
+		}
+		return &src, nil
+	case FormatDiscourse:
+		var data discourseData
+		if err := json.NewDecoder(r).Decode(&data); err != nil {
+			return nil, fmt.Errorf("failed to decode discourse data: %w", err)
+		}
+		return data.toSource(), nil
+	case FormatPhpBB:
+		var data phpbbData
+		if err := json.NewDecoder(r).Decode(&data); err != nil {
+			return nil, fmt.Errorf("failed to decode phpbb data: %w", err)
+		}
+		return data.toSource(), nil
+	default:
+		return nil, fmt.Errorf("unknown import format %q", format)
+	}
+}
+
+// discourseData выгрузка Discourse: темы и сообщения раздельно,
+// первое сообщение темы (post_number = 1) становится текстом поста
+type discourseData struct {
+	Users []struct {
+		ID        int64     `json:"id"`
+		Username  string    `json:"username"`
+		Email     string    `json:"email"`
+		CreatedAt time.Time `json:"created_at"`
+	} `json:"users"`
+	Categories []struct {
+		ID   int64  `json:"id"`
+		Name string `json:"name"`
+	} `json:"categories"`
+	Topics []struct {
+		ID         int64     `json:"id"`
+		Title      string    `json:"title"`
+		CategoryID int64     `json:"category_id"`
+		UserID     int64     `json:"user_id"`
+		CreatedAt  time.Time `json:"created_at"`
+	} `json:"topics"`
+	Posts []struct {
+		ID         int64     `json:"id"`
+		TopicID    int64     `json:"topic_id"`
+		UserID     int64     `json:"user_id"`
+		PostNumber int       `json:"post_number"`
+		Raw        string    `json:"raw"`
+		CreatedAt  time.Time `json:"created_at"`
+	} `json:"posts"`
+}
+
+func (d *discourseData) toSource() *Source {
+	src := &Source{}
+	for _, u := range d.Users {
+		src.Users = append(src.Users, User{ID: itoa(u.ID), Username: u.Username, Email: u.Email, CreatedAt: u.CreatedAt})
+	}
+	for _, c := range d.Categories {
+		src.Categories = append(src.Categories, Category{ID: itoa(c.ID), Name: c.Name})
+	}
+
+	bodies := make(map[int64]string)
+	for _, p := range d.Posts {
+		if p.PostNumber == 1 {
+			bodies[p.TopicID] = p.Raw
+		}
+	}
+
+	for _, t := range d.Topics {
+		post := Post{
+			ID:        itoa(t.ID),
+			Title:     t.Title,
+			Content:   bodies[t.ID],
+			AuthorID:  itoa(t.UserID),
+			CreatedAt: t.CreatedAt,
+		}
+		if t.CategoryID != 0 {
+			post.CategoryID = itoa(t.CategoryID)
+		}
+		src.Posts = append(src.Posts, post)
+	}
+
+	for _, p := range d.Posts {
+		if p.PostNumber == 1 {
+			continue
+		}
+		src.Comments = append(src.Comments, Comment{
+			ID:        itoa(p.ID),
+			PostID:    itoa(p.TopicID),
+			AuthorID:  itoa(p.UserID),
+			Content:   p.Raw,
+			CreatedAt: p.CreatedAt,
+		})
+	}
+
+	return src
+}
+
+// phpbbData выгрузка таблиц phpBB (phpbb_users, phpbb_forums, phpbb_topics, phpbb_posts) в JSON.
+// Разделы (forums) становятся категориями, первое сообщение темы - текстом поста.
+type phpbbData struct {
+	Users []struct {
+		UserID      int64  `json:"user_id"`
+		Username    string `json:"username"`
+		UserEmail   string `json:"user_email"`
+		UserRegdate int64  `json:"user_regdate"`
+	} `json:"users"`
+	Forums []struct {
+		ForumID   int64  `json:"forum_id"`
+		ForumName string `json:"forum_name"`
+	} `json:"forums"`
+	Topics []struct {
+		TopicID          int64  `json:"topic_id"`
+		ForumID          int64  `json:"forum_id"`
+		TopicTitle       string `json:"topic_title"`
+		TopicPoster      int64  `json:"topic_poster"`
+		TopicFirstPostID int64  `json:"topic_first_post_id"`
+		TopicTime        int64  `json:"topic_time"`
+	} `json:"topics"`
+	Posts []struct {
+		PostID   int64  `json:"post_id"`
+		TopicID  int64  `json:"topic_id"`
+		PosterID int64  `json:"poster_id"`
+		PostText string `json:"post_text"`
+		PostTime int64  `json:"post_time"`
+	} `json:"posts"`
+}
+
+func (d *phpbbData) toSource() *Source {
+	src := &Source{}
+	for _, u := range d.Users {
+		src.Users = append(src.Users, User{ID: itoa(u.UserID), Username: u.Username, Email: u.UserEmail, CreatedAt: unixTime(u.UserRegdate)})
+	}
+	for _, f := range d.Forums {
+		src.Categories = append(src.Categories, Category{ID: itoa(f.ForumID), Name: f.ForumName})
+	}
+
+	texts := make(map[int64]string, len(d.Posts))
+	for _, p := range d.Posts {
+		texts[p.PostID] = p.PostText
+	}
+
+	firstPosts := make(map[int64]bool, len(d.Topics))
+	for _, t := range d.Topics {
+		firstPosts[t.TopicFirstPostID] = true
+		src.Posts = append(src.Posts, Post{
+			ID:         itoa(t.TopicID),
+			Title:      t.TopicTitle,
+			Content:    texts[t.TopicFirstPostID],
+			AuthorID:   itoa(t.TopicPoster),
+			CategoryID: itoa(t.ForumID),
+			CreatedAt:  unixTime(t.TopicTime),
+		})
+	}
+
+	for _, p := range d.Posts {
+		if firstPosts[p.PostID] {
+			continue
+		}
+		src.Comments = append(src.Comments, Comment{
+			ID:        itoa(p.PostID),
+			PostID:    itoa(p.TopicID),
+			AuthorID:  itoa(p.PosterID),
+			Content:   p.PostText,
+			CreatedAt: unixTime(p.PostTime),
+		})
+	}
+
+	return src
+}
+
+func itoa(id int64) string {
+	return strconv.FormatInt(id, 10)
+}
+
+func unixTime(sec int64) time.Time {
+	if sec == 0 {
+		return time.Time{}
+	}
+	return time.Unix(sec, 0).UTC()
+}
@@ -0,0 +1,190 @@
+// Package importer переносит данные с других форумов через слой use case
+package importer
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kprf42/dolgova/forum_service/internal/entity"
+	"github.com/kprf42/dolgova/forum_service/internal/usecase"
+	"github.com/kprf42/dolgova/pkg/logger"
+)
+
+// Source данные в общей (generic) схеме импорта. ID внешние, в БД получают новые UUID.
+type Source struct {
+	Users      []User     `json:"users"`
+	Categories []Category `json:"categories"`
+	Posts      []Post     `json:"posts"`
+	Comments   []Comment  `json:"comments"`
+}
+
+type User struct {
+	ID        string    `json:"id"`
+	Username  string    `json:"username"`
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type Category struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type Post struct {
+	ID         string    `json:"id"`
+	Title      string    `json:"title"`
+	Content    string    `json:"content"`
+	AuthorID   string    `json:"author_id"`
+	CategoryID string    `json:"category_id"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type Comment struct {
+	ID        string    `json:"id"`
+	PostID    string    `json:"post_id"`
+	AuthorID  string    `json:"author_id"`
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Report соответствие внешних ID новым и список пропущенных записей
+type Report struct {
+	Users      map[string]string `json:"users"`
+	Categories map[string]string `json:"categories"`
+	Posts      map[string]string `json:"posts"`
+	Comments   map[string]string `json:"comments"`
+	// ExistingUsers внешние ID пользователей, сопоставленных с уже существующими по email
+	ExistingUsers []string  `json:"existing_users,omitempty"`
+	Skipped       []Skipped `json:"skipped,omitempty"`
+}
+
+type Skipped struct {
+	Kind   string `json:"kind"`
+	ID     string `json:"id"`
+	Reason string `json:"reason"`
+}
+
+type Importer struct {
+	users    *usecase.UserUseCase
+	posts    *usecase.PostUseCase
+	comments *usecase.CommentUseCase
+	log      *logger.Logger
+}
+
+func New(users *usecase.UserUseCase, posts *usecase.PostUseCase, comments *usecase.CommentUseCase, log *logger.Logger) *Importer {
+	return &Importer{
+		users:    users,
+		posts:    posts,
+		comments: comments,
+		log:      log,
+	}
+}
+
+// Import создает пользователей, посты и комментарии. Ошибка отдельной записи
+// не прерывает импорт: запись попадает в Skipped, а зависящие от нее записи пропускаются.
+func (i *Importer) Import(ctx context.Context, src *Source) (*Report, error) {
+	report := &Report{
+		Users:      make(map[string]string),
+		Categories: make(map[string]string),
+		Posts:      make(map[string]string),
+		Comments:   make(map[string]string),
+	}
+
+	for _, u := range src.Users {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+
+		user, created, err := i.users.Import(ctx, u.Username, u.Email, u.CreatedAt)
+		if err != nil {
+			report.skip("user", u.ID, err.Error())
+			continue
+		}
+		report.Users[u.ID] = user.ID
+		if !created {
+			report.ExistingUsers = append(report.ExistingUsers, u.ID)
+		}
+	}
+
+	// Отдельной таблицы категорий пока нет: категория существует только как category_id поста,
+	// поэтому здесь лишь выдаются новые ID
+	for _, c := range src.Categories {
+		report.Categories[c.ID] = uuid.New().String()
+	}
+
+	for _, p := range src.Posts {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+
+		authorID, ok := report.Users[p.AuthorID]
+		if !ok {
+			report.skip("post", p.ID, fmt.Sprintf("unknown author %q", p.AuthorID))
+			continue
+		}
+
+		categoryID := ""
+		if p.CategoryID != "" {
+			if categoryID, ok = report.Categories[p.CategoryID]; !ok {
+				report.skip("post", p.ID, fmt.Sprintf("unknown category %q", p.CategoryID))
+				continue
+			}
+		}
+
+		post, err := i.posts.Import(ctx, &entity.Post{
+			Title:      p.Title,
+			Content:    p.Content,
+			AuthorID:   authorID,
+			CategoryID: categoryID,
+			CreatedAt:  p.CreatedAt,
+		})
+		if err != nil {
+			report.skip("post", p.ID, err.Error())
+			continue
+		}
+		report.Posts[p.ID] = post.ID
+	}
+
+	for _, c := range src.Comments {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+
+		postID, ok := report.Posts[c.PostID]
+		if !ok {
+			report.skip("comment", c.ID, fmt.Sprintf("unknown post %q", c.PostID))
+			continue
+		}
+		authorID, ok := report.Users[c.AuthorID]
+		if !ok {
+			report.skip("comment", c.ID, fmt.Sprintf("unknown author %q", c.AuthorID))
+			continue
+		}
+
+		comment, err := i.comments.Import(ctx, &entity.Comment{
+			Content:   c.Content,
+			PostID:    postID,
+			AuthorID:  authorID,
+			CreatedAt: c.CreatedAt,
+		})
+		if err != nil {
+			report.skip("comment", c.ID, err.Error())
+			continue
+		}
+		report.Comments[c.ID] = comment.ID
+	}
+
+	i.log.Info("Import finished",
+		logger.Int("users", len(report.Users)),
+		logger.Int("posts", len(report.Posts)),
+		logger.Int("comments", len(report.Comments)),
+		logger.Int("skipped", len(report.Skipped)))
+
+	return report, nil
+}
+
+func (r *Report) skip(kind, id, reason string) {
+	r.Skipped = append(r.Skipped, Skipped{Kind: kind, ID: id, Reason: reason})
+}
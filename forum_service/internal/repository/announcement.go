@@ -0,0 +1,239 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/kprf42/dolgova/forum_service/internal/entity"
+	"github.com/kprf42/dolgova/forum_service/internal/tenant"
+	"github.com/kprf42/dolgova/pkg/logger"
+)
+
+// ErrAnnouncementNotFound объявление не найдено
+var ErrAnnouncementNotFound = errors.New("announcement not found")
+
+// AnnouncementRepository хранит объявления администрации. Даты хранятся в UTC RFC3339,
+// поэтому активные объявления выбираются сравнением строк.
+type AnnouncementRepository struct {
+	db  *sql.DB
+	log *logger.Logger
+}
+
+func NewAnnouncementRepository(db *sql.DB, log *logger.Logger) *AnnouncementRepository {
+	return &AnnouncementRepository{
+		db:  db,
+		log: log,
+	}
+}
+
+const announcementColumns = `id, title, body, severity, audience, starts_at, ends_at, created_by, created_at, updated_at`
+
+func (r *AnnouncementRepository) Create(ctx context.Context, a *entity.Announcement) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	r.log.ForContext(ctx).Info("Creating announcement",
+		logger.String("announcement_id", a.ID),
+		logger.String("severity", a.Severity),
+		logger.String("audience", a.Audience))
+
+	query := `INSERT INTO announcements (` + announcementColumns + `, tenant_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	_, err := r.db.ExecContext(ctx, query,
+		a.ID,
+		a.Title,
+		a.Body,
+		a.Severity,
+		a.Audience,
+		formatUTC(a.StartsAt),
+		formatNullableUTC(a.EndsAt),
+		a.CreatedBy,
+		formatUTC(a.CreatedAt),
+		formatUTC(a.UpdatedAt),
+		tenant.FromContext(ctx),
+	)
+	if err != nil {
+		r.log.ForContext(ctx).Error("Failed to create announcement",
+			logger.String("announcement_id", a.ID),
+			logger.Error(err))
+		return err
+	}
+
+	r.log.ForContext(ctx).Info("Successfully created announcement",
+		logger.String("announcement_id", a.ID))
+	return nil
+}
+
+// Update заменяет содержимое и сроки объявления
+func (r *AnnouncementRepository) Update(ctx context.Context, a *entity.Announcement) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	r.log.ForContext(ctx).Info("Updating announcement",
+		logger.String("announcement_id", a.ID))
+
+	query := `UPDATE announcements SET title = ?, body = ?, severity = ?, audience = ?, starts_at = ?, ends_at = ?, updated_at = ?
+	          WHERE tenant_id = ? AND id = ?`
+	result, err := r.db.ExecContext(ctx, query,
+		a.Title,
+		a.Body,
+		a.Severity,
+		a.Audience,
+		formatUTC(a.StartsAt),
+		formatNullableUTC(a.EndsAt),
+		formatUTC(a.UpdatedAt),
+		tenant.FromContext(ctx),
+		a.ID,
+	)
+	if err != nil {
+		r.log.ForContext(ctx).Error("Failed to update announcement",
+			logger.String("announcement_id", a.ID),
+			logger.Error(err))
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		r.log.ForContext(ctx).Warn("Announcement not found",
+			logger.String("announcement_id", a.ID))
+		return ErrAnnouncementNotFound
+	}
+
+	r.log.ForContext(ctx).Info("Successfully updated announcement",
+		logger.String("announcement_id", a.ID))
+	return nil
+}
+
+func (r *AnnouncementRepository) Delete(ctx context.Context, id string) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	r.log.ForContext(ctx).Info("Deleting announcement",
+		logger.String("announcement_id", id))
+
+	result, err := r.db.ExecContext(ctx, `DELETE FROM announcements WHERE tenant_id = ? AND id = ?`, tenant.FromContext(ctx), id)
+	if err != nil {
+		r.log.ForContext(ctx).Error("Failed to delete announcement",
+			logger.String("announcement_id", id),
+			logger.Error(err))
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		r.log.ForContext(ctx).Warn("Announcement not found",
+			logger.String("announcement_id", id))
+		return ErrAnnouncementNotFound
+	}
+
+	r.log.ForContext(ctx).Info("Successfully deleted announcement",
+		logger.String("announcement_id", id))
+	return nil
+}
+
+func (r *AnnouncementRepository) GetByID(ctx context.Context, id string) (*entity.Announcement, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	query := `SELECT ` + announcementColumns + ` FROM announcements WHERE tenant_id = ? AND id = ?`
+	a, err := scanAnnouncement(r.db.QueryRowContext(ctx, query, tenant.FromContext(ctx), id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrAnnouncementNotFound
+	}
+	if err != nil {
+		r.log.ForContext(ctx).Error("Failed to get announcement",
+			logger.String("announcement_id", id),
+			logger.Error(err))
+		return nil, err
+	}
+	return a, nil
+}
+
+// List возвращает все объявления сообщества, включая будущие и завершенные
+func (r *AnnouncementRepository) List(ctx context.Context) ([]*entity.Announcement, error) {
+	query := `SELECT ` + announcementColumns + ` FROM announcements WHERE tenant_id = ? ORDER BY starts_at DESC`
+	return r.query(ctx, query, tenant.FromContext(ctx))
+}
+
+// Active возвращает объявления, действующие в момент now, для аудиторий audiences
+func (r *AnnouncementRepository) Active(ctx context.Context, now time.Time, audiences []string) ([]*entity.Announcement, error) {
+	filter, filterArgs := inFilter("audience", audiences)
+	query := `SELECT ` + announcementColumns + ` FROM announcements
+	          WHERE tenant_id = ? AND starts_at <= ? AND (ends_at IS NULL OR ends_at > ?)` + filter + `
+	          ORDER BY CASE severity WHEN 'critical' THEN 0 WHEN 'warning' THEN 1 ELSE 2 END, starts_at DESC`
+
+	args := []interface{}{tenant.FromContext(ctx), formatUTC(now), formatUTC(now)}
+	return r.query(ctx, query, append(args, filterArgs...)...)
+}
+
+func (r *AnnouncementRepository) query(ctx context.Context, query string, args ...interface{}) ([]*entity.Announcement, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		r.log.ForContext(ctx).Error("Failed to get announcements",
+			logger.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	announcements := []*entity.Announcement{}
+	for rows.Next() {
+		a, err := scanAnnouncement(rows)
+		if err != nil {
+			r.log.ForContext(ctx).Error("Failed to scan announcement row",
+				logger.Error(err))
+			return nil, err
+		}
+		announcements = append(announcements, a)
+	}
+	return announcements, rows.Err()
+}
+
+func scanAnnouncement(row interface{ Scan(...any) error }) (*entity.Announcement, error) {
+	var a entity.Announcement
+	var startsAt, createdAt, updatedAt string
+	var endsAt sql.NullString
+	if err := row.Scan(&a.ID, &a.Title, &a.Body, &a.Severity, &a.Audience, &startsAt, &endsAt, &a.CreatedBy, &createdAt, &updatedAt); err != nil {
+		return nil, err
+	}
+
+	var err error
+	if a.StartsAt, err = time.Parse(time.RFC3339, startsAt); err != nil {
+		return nil, fmt.Errorf("failed to parse starts_at: %w", err)
+	}
+	if endsAt.Valid {
+		t, err := time.Parse(time.RFC3339, endsAt.String)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse ends_at: %w", err)
+		}
+		a.EndsAt = &t
+	}
+	if a.CreatedAt, err = time.Parse(time.RFC3339, createdAt); err != nil {
+		return nil, fmt.Errorf("failed to parse created_at: %w", err)
+	}
+	if a.UpdatedAt, err = time.Parse(time.RFC3339, updatedAt); err != nil {
+		return nil, fmt.Errorf("failed to parse updated_at: %w", err)
+	}
+	return &a, nil
+}
+
+func formatUTC(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+func formatNullableUTC(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return formatUTC(*t)
+}
@@ -0,0 +1,249 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/kprf42/dolgova/forum_service/internal/entity"
+	"github.com/kprf42/dolgova/forum_service/internal/tenant"
+	"github.com/kprf42/dolgova/pkg/logger"
+)
+
+// ErrAttachmentNotFound вложение не найдено
+var ErrAttachmentNotFound = errors.New("attachment not found")
+
+// AttachmentRepository хранит вложения постов, комментариев и сообщений чата
+type AttachmentRepository struct {
+	db  *sql.DB
+	log *logger.Logger
+}
+
+func NewAttachmentRepository(db *sql.DB, log *logger.Logger) *AttachmentRepository {
+	return &AttachmentRepository{
+		db:  db,
+		log: log,
+	}
+}
+
+const attachmentColumns = `id, tenant_id, owner_id, storage_key, url, content_type, size,
+	target_type, target_id, variants, thumbnail_state, created_at`
+
+// Create сохраняет загруженное, еще не привязанное вложение
+func (r *AttachmentRepository) Create(ctx context.Context, a *entity.Attachment) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	r.log.ForContext(ctx).Info("Creating attachment",
+		logger.String("attachment_id", a.ID),
+		logger.String("owner_id", a.OwnerID),
+		logger.String("content_type", a.ContentType))
+
+	variants, err := json.Marshal(a.Variants)
+	if err != nil || a.Variants == nil {
+		variants = []byte("{}")
+	}
+
+	query := `INSERT INTO attachments (id, tenant_id, owner_id, storage_key, url, content_type, size, variants, thumbnail_state, created_at)
+	          VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	_, err = r.db.ExecContext(ctx, query,
+		a.ID,
+		tenant.FromContext(ctx),
+		a.OwnerID,
+		a.StorageKey,
+		a.URL,
+		a.ContentType,
+		a.Size,
+		string(variants),
+		a.ThumbnailState,
+		a.CreatedAt.Format(time.RFC3339),
+	)
+	if err != nil {
+		r.log.ForContext(ctx).Error("Failed to create attachment",
+			logger.String("attachment_id", a.ID),
+			logger.Error(err))
+		return err
+	}
+
+	r.log.ForContext(ctx).Info("Successfully created attachment",
+		logger.String("attachment_id", a.ID))
+	return nil
+}
+
+// GetByID возвращает вложение текущего сообщества
+func (r *AttachmentRepository) GetByID(ctx context.Context, id string) (*entity.Attachment, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	query := `SELECT ` + attachmentColumns + ` FROM attachments WHERE id = ? AND tenant_id = ?`
+	a, err := scanAttachment(r.db.QueryRowContext(ctx, query, id, tenant.FromContext(ctx)))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrAttachmentNotFound
+	}
+	if err != nil {
+		r.log.ForContext(ctx).Error("Failed to get attachment",
+			logger.String("attachment_id", id),
+			logger.Error(err))
+		return nil, err
+	}
+	return a, nil
+}
+
+// CountUnattached считает вложения из ids, загруженные ownerID и еще ни к чему не привязанные
+func (r *AttachmentRepository) CountUnattached(ctx context.Context, ownerID string, ids []string) (int, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	filter, args := inFilter("id", ids)
+	query := `SELECT COUNT(*) FROM attachments WHERE tenant_id = ? AND owner_id = ? AND target_type = ''` + filter
+
+	var count int
+	args = append([]interface{}{tenant.FromContext(ctx), ownerID}, args...)
+	if err := r.db.QueryRowContext(ctx, query, args...).Scan(&count); err != nil {
+		r.log.ForContext(ctx).Error("Failed to count unattached attachments",
+			logger.String("owner_id", ownerID),
+			logger.Error(err))
+		return 0, err
+	}
+	return count, nil
+}
+
+// Attach привязывает непривязанные вложения ownerID к посту, комментарию или сообщению
+func (r *AttachmentRepository) Attach(ctx context.Context, ownerID, targetType, targetID string, ids []string) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	r.log.ForContext(ctx).Info("Attaching files",
+		logger.String("target_type", targetType),
+		logger.String("target_id", targetID),
+		logger.Int("count", len(ids)))
+
+	filter, args := inFilter("id", ids)
+	query := `UPDATE attachments SET target_type = ?, target_id = ?
+	          WHERE tenant_id = ? AND owner_id = ? AND target_type = ''` + filter
+
+	args = append([]interface{}{targetType, targetID, tenant.FromContext(ctx), ownerID}, args...)
+	if _, err := r.db.ExecContext(ctx, query, args...); err != nil {
+		r.log.ForContext(ctx).Error("Failed to attach files",
+			logger.String("target_type", targetType),
+			logger.String("target_id", targetID),
+			logger.Error(err))
+		return err
+	}
+
+	r.log.ForContext(ctx).Info("Successfully attached files",
+		logger.String("target_type", targetType),
+		logger.String("target_id", targetID))
+	return nil
+}
+
+// ForTargets возвращает вложения нескольких постов (комментариев, сообщений) в порядке загрузки
+func (r *AttachmentRepository) ForTargets(ctx context.Context, targetType string, targetIDs []string) ([]*entity.Attachment, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	filter, args := inFilter("target_id", targetIDs)
+	query := `SELECT ` + attachmentColumns + ` FROM attachments
+	          WHERE tenant_id = ? AND target_type = ?` + filter + ` ORDER BY created_at, id`
+
+	args = append([]interface{}{tenant.FromContext(ctx), targetType}, args...)
+	return r.query(ctx, query, args...)
+}
+
+// SetVariants сохраняет адреса уменьшенных копий и состояние их генерации
+func (r *AttachmentRepository) SetVariants(ctx context.Context, id string, variants map[string]string, state string) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	data, err := json.Marshal(variants)
+	if err != nil || variants == nil {
+		data = []byte("{}")
+	}
+
+	query := `UPDATE attachments SET variants = ?, thumbnail_state = ? WHERE id = ? AND tenant_id = ?`
+	if _, err := r.db.ExecContext(ctx, query, string(data), state, id, tenant.FromContext(ctx)); err != nil {
+		r.log.ForContext(ctx).Error("Failed to save attachment variants",
+			logger.String("attachment_id", id),
+			logger.Error(err))
+		return err
+	}
+
+	r.log.ForContext(ctx).Info("Successfully saved attachment variants",
+		logger.String("attachment_id", id),
+		logger.String("state", state))
+	return nil
+}
+
+// PendingThumbnails возвращает вложения всех сообществ, для которых копии еще не сгенерированы
+func (r *AttachmentRepository) PendingThumbnails(ctx context.Context) ([]*entity.Attachment, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	query := `SELECT ` + attachmentColumns + ` FROM attachments WHERE thumbnail_state = ? ORDER BY created_at`
+	return r.query(ctx, query, entity.ThumbnailPending)
+}
+
+func (r *AttachmentRepository) query(ctx context.Context, query string, args ...interface{}) ([]*entity.Attachment, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		r.log.ForContext(ctx).Error("Failed to get attachments",
+			logger.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var attachments []*entity.Attachment
+	for rows.Next() {
+		a, err := scanAttachment(rows)
+		if err != nil {
+			r.log.ForContext(ctx).Error("Failed to scan attachment row",
+				logger.Error(err))
+			return nil, err
+		}
+		attachments = append(attachments, a)
+	}
+	return attachments, rows.Err()
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanAttachment(row rowScanner) (*entity.Attachment, error) {
+	var a entity.Attachment
+	var variants, createdAt string
+
+	err := row.Scan(
+		&a.ID,
+		&a.TenantID,
+		&a.OwnerID,
+		&a.StorageKey,
+		&a.URL,
+		&a.ContentType,
+		&a.Size,
+		&a.TargetType,
+		&a.TargetID,
+		&variants,
+		&a.ThumbnailState,
+		&createdAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal([]byte(variants), &a.Variants); err != nil {
+		return nil, fmt.Errorf("failed to parse variants: %w", err)
+	}
+	if len(a.Variants) == 0 {
+		a.Variants = nil
+	}
+
+	a.CreatedAt, err = time.Parse(time.RFC3339, createdAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse created_at: %w", err)
+	}
+	return &a, nil
+}
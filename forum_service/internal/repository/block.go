@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/kprf42/dolgova/forum_service/internal/auth"
+	"github.com/kprf42/dolgova/forum_service/internal/entity"
+	"github.com/kprf42/dolgova/pkg/logger"
+)
+
+// ErrBlockNotFound пользователь не заблокирован
+var ErrBlockNotFound = errors.New("block not found")
+
+// BlockRepository хранит блокировки пользователей
+type BlockRepository struct {
+	db  *sql.DB
+	log *logger.Logger
+}
+
+func NewBlockRepository(db *sql.DB, log *logger.Logger) *BlockRepository {
+	return &BlockRepository{
+		db:  db,
+		log: log,
+	}
+}
+
+// Block блокирует пользователя. Повторная блокировка не считается ошибкой.
+func (r *BlockRepository) Block(ctx context.Context, b *entity.Block) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	r.log.ForContext(ctx).Info("Blocking user",
+		logger.String("blocker_id", b.BlockerID),
+		logger.String("blocked_id", b.BlockedID))
+
+	if b.CreatedAt.IsZero() {
+		b.CreatedAt = time.Now().UTC()
+	}
+
+	query := `INSERT OR IGNORE INTO user_blocks (blocker_id, blocked_id, created_at) VALUES (?, ?, ?)`
+	_, err := r.db.ExecContext(ctx, query, b.BlockerID, b.BlockedID, b.CreatedAt.Format(time.RFC3339))
+	if err != nil {
+		r.log.ForContext(ctx).Error("Failed to block user",
+			logger.String("blocker_id", b.BlockerID),
+			logger.String("blocked_id", b.BlockedID),
+			logger.Error(err))
+		return err
+	}
+
+	r.log.ForContext(ctx).Info("Successfully blocked user",
+		logger.String("blocker_id", b.BlockerID),
+		logger.String("blocked_id", b.BlockedID))
+	return nil
+}
+
+// Unblock снимает блокировку
+func (r *BlockRepository) Unblock(ctx context.Context, blockerID, blockedID string) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	r.log.ForContext(ctx).Info("Unblocking user",
+		logger.String("blocker_id", blockerID),
+		logger.String("blocked_id", blockedID))
+
+	query := `DELETE FROM user_blocks WHERE blocker_id = ? AND blocked_id = ?`
+	result, err := r.db.ExecContext(ctx, query, blockerID, blockedID)
+	if err != nil {
+		r.log.ForContext(ctx).Error("Failed to unblock user",
+			logger.String("blocker_id", blockerID),
+			logger.String("blocked_id", blockedID),
+			logger.Error(err))
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		r.log.ForContext(ctx).Warn("Block not found",
+			logger.String("blocker_id", blockerID),
+			logger.String("blocked_id", blockedID))
+		return ErrBlockNotFound
+	}
+
+	r.log.ForContext(ctx).Info("Successfully unblocked user",
+		logger.String("blocker_id", blockerID),
+		logger.String("blocked_id", blockedID))
+	return nil
+}
+
+// List возвращает блокировки пользователя, новые первыми
+func (r *BlockRepository) List(ctx context.Context, blockerID string) ([]*entity.Block, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	r.log.ForContext(ctx).Info("Getting user blocks",
+		logger.String("blocker_id", blockerID))
+
+	query := `SELECT blocker_id, blocked_id, created_at FROM user_blocks
+	          WHERE blocker_id = ? ORDER BY created_at DESC`
+
+	rows, err := r.db.QueryContext(ctx, query, blockerID)
+	if err != nil {
+		r.log.ForContext(ctx).Error("Failed to get user blocks",
+			logger.String("blocker_id", blockerID),
+			logger.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	blocks := []*entity.Block{}
+	for rows.Next() {
+		var b entity.Block
+		var createdAt string
+
+		if err := rows.Scan(&b.BlockerID, &b.BlockedID, &createdAt); err != nil {
+			r.log.ForContext(ctx).Error("Failed to scan user block row",
+				logger.Error(err))
+			return nil, err
+		}
+
+		b.CreatedAt, err = time.Parse(time.RFC3339, createdAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse created_at: %w", err)
+		}
+
+		blocks = append(blocks, &b)
+	}
+
+	return blocks, rows.Err()
+}
+
+// BlockedIDs возвращает ID пользователей, заблокированных blockerID
+func (r *BlockRepository) BlockedIDs(ctx context.Context, blockerID string) ([]string, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx, `SELECT blocked_id FROM user_blocks WHERE blocker_id = ?`, blockerID)
+	if err != nil {
+		r.log.ForContext(ctx).Error("Failed to get blocked users",
+			logger.String("blocker_id", blockerID),
+			logger.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+// blockedFilter исключает из выборки контент пользователей, заблокированных
+// аутентифицированным пользователем из контекста. Анонимные запросы не фильтруются.
+func blockedFilter(ctx context.Context, column string) (string, []interface{}) {
+	viewerID, ok := auth.UserIDFromContext(ctx)
+	if !ok {
+		return "", nil
+	}
+	return " AND " + column + " NOT IN (SELECT blocked_id FROM user_blocks WHERE blocker_id = ?)", []interface{}{viewerID}
+}
@@ -0,0 +1,298 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/kprf42/dolgova/forum_service/internal/entity"
+	"github.com/kprf42/dolgova/forum_service/internal/tenant"
+	"github.com/kprf42/dolgova/pkg/logger"
+)
+
+var (
+	// ErrCategoryNotFound категория не найдена
+	ErrCategoryNotFound = errors.New("category not found")
+	// ErrCategoryNotEmpty в категории есть посты или подкатегории, удалить ее нельзя
+	ErrCategoryNotEmpty = errors.New("category has posts or subcategories")
+)
+
+// CategoryRepository хранит категории форума. Дерево подфорумов хранится списком смежности
+// (parent_id); поддеревья выбираются рекурсивными запросами.
+type CategoryRepository struct {
+	db  *sql.DB
+	log *logger.Logger
+}
+
+func NewCategoryRepository(db *sql.DB, log *logger.Logger) *CategoryRepository {
+	return &CategoryRepository{
+		db:  db,
+		log: log,
+	}
+}
+
+const categoryColumns = `id, parent_id, depth, name, description, position, created_at, updated_at`
+
+func (r *CategoryRepository) Create(ctx context.Context, c *entity.Category) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	r.log.ForContext(ctx).Info("Creating category",
+		logger.String("category_id", c.ID),
+		logger.String("name", c.Name))
+
+	query := `INSERT INTO categories (` + categoryColumns + `, tenant_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	_, err := r.db.ExecContext(ctx, query,
+		c.ID,
+		nullableString(c.ParentID),
+		c.Depth,
+		c.Name,
+		c.Description,
+		c.Position,
+		formatUTC(c.CreatedAt),
+		formatUTC(c.UpdatedAt),
+		tenant.FromContext(ctx),
+	)
+	if err != nil {
+		r.log.ForContext(ctx).Error("Failed to create category",
+			logger.String("category_id", c.ID),
+			logger.Error(err))
+		return err
+	}
+
+	r.log.ForContext(ctx).Info("Successfully created category",
+		logger.String("category_id", c.ID))
+	return nil
+}
+
+// Update заменяет категорию. При переносе в другого родителя глубина подкатегорий пересчитывается.
+func (r *CategoryRepository) Update(ctx context.Context, c *entity.Category) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	r.log.ForContext(ctx).Info("Updating category",
+		logger.String("category_id", c.ID),
+		logger.String("parent_id", c.ParentID))
+
+	tenantID := tenant.FromContext(ctx)
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `UPDATE categories SET parent_id = ?, depth = ?, name = ?, description = ?, position = ?, updated_at = ?
+	          WHERE tenant_id = ? AND id = ?`
+	result, err := tx.ExecContext(ctx, query,
+		nullableString(c.ParentID),
+		c.Depth,
+		c.Name,
+		c.Description,
+		c.Position,
+		formatUTC(c.UpdatedAt),
+		tenantID,
+		c.ID,
+	)
+	if err != nil {
+		r.log.ForContext(ctx).Error("Failed to update category",
+			logger.String("category_id", c.ID),
+			logger.Error(err))
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		r.log.ForContext(ctx).Warn("Category not found",
+			logger.String("category_id", c.ID))
+		return ErrCategoryNotFound
+	}
+
+	depths := `WITH RECURSIVE subtree(id, depth) AS (
+	               SELECT id, depth FROM categories WHERE tenant_id = ? AND id = ?
+	               UNION ALL
+	               SELECT c.id, s.depth + 1 FROM categories c JOIN subtree s ON c.parent_id = s.id WHERE c.tenant_id = ?
+	           )
+	           UPDATE categories SET depth = (SELECT depth FROM subtree WHERE subtree.id = categories.id)
+	           WHERE tenant_id = ? AND id IN (SELECT id FROM subtree)`
+	if _, err := tx.ExecContext(ctx, depths, tenantID, c.ID, tenantID, tenantID); err != nil {
+		return fmt.Errorf("failed to update subcategory depth: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	r.log.ForContext(ctx).Info("Successfully updated category",
+		logger.String("category_id", c.ID))
+	return nil
+}
+
+// Delete удаляет пустую категорию вместе с ее модераторами и отметками о прочтении.
+// Если в категории есть посты или подкатегории, возвращает ErrCategoryNotEmpty.
+func (r *CategoryRepository) Delete(ctx context.Context, id string) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	r.log.ForContext(ctx).Info("Deleting category",
+		logger.String("category_id", id))
+
+	tenantID := tenant.FromContext(ctx)
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var notEmpty bool
+	err = tx.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM posts WHERE tenant_id = ? AND category_id = ?)
+	                                   OR EXISTS (SELECT 1 FROM categories WHERE tenant_id = ? AND parent_id = ?)`,
+		tenantID, id, tenantID, id).Scan(&notEmpty)
+	if err != nil {
+		return fmt.Errorf("failed to check category contents: %w", err)
+	}
+	if notEmpty {
+		r.log.ForContext(ctx).Warn("Category is not empty",
+			logger.String("category_id", id))
+		return ErrCategoryNotEmpty
+	}
+
+	result, err := tx.ExecContext(ctx, `DELETE FROM categories WHERE tenant_id = ? AND id = ?`, tenantID, id)
+	if err != nil {
+		r.log.ForContext(ctx).Error("Failed to delete category",
+			logger.String("category_id", id),
+			logger.Error(err))
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		r.log.ForContext(ctx).Warn("Category not found",
+			logger.String("category_id", id))
+		return ErrCategoryNotFound
+	}
+
+	for _, query := range []string{
+		`DELETE FROM category_moderators WHERE tenant_id = ? AND category_id = ?`,
+		`DELETE FROM category_reads WHERE tenant_id = ? AND category_id = ?`,
+	} {
+		if _, err := tx.ExecContext(ctx, query, tenantID, id); err != nil {
+			return fmt.Errorf("failed to delete category references: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	r.log.ForContext(ctx).Info("Successfully deleted category",
+		logger.String("category_id", id))
+	return nil
+}
+
+func (r *CategoryRepository) GetByID(ctx context.Context, id string) (*entity.Category, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	query := `SELECT ` + categoryColumns + ` FROM categories WHERE tenant_id = ? AND id = ?`
+	c, err := scanCategory(r.db.QueryRowContext(ctx, query, tenant.FromContext(ctx), id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrCategoryNotFound
+	}
+	if err != nil {
+		r.log.ForContext(ctx).Error("Failed to get category",
+			logger.String("category_id", id),
+			logger.Error(err))
+		return nil, err
+	}
+	return c, nil
+}
+
+// Exists сообщает, есть ли категория в текущем сообществе
+func (r *CategoryRepository) Exists(ctx context.Context, id string) (bool, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	var exists bool
+	err := r.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM categories WHERE tenant_id = ? AND id = ?)`,
+		tenant.FromContext(ctx), id).Scan(&exists)
+	if err != nil {
+		r.log.ForContext(ctx).Error("Failed to check category",
+			logger.String("category_id", id),
+			logger.Error(err))
+		return false, err
+	}
+	return exists, nil
+}
+
+// List возвращает категории сообщества по уровню вложенности, затем по позиции и названию
+func (r *CategoryRepository) List(ctx context.Context) ([]*entity.Category, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	query := `SELECT ` + categoryColumns + ` FROM categories WHERE tenant_id = ? ORDER BY depth, position, name`
+	rows, err := r.db.QueryContext(ctx, query, tenant.FromContext(ctx))
+	if err != nil {
+		r.log.ForContext(ctx).Error("Failed to get categories",
+			logger.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	categories := []*entity.Category{}
+	for rows.Next() {
+		c, err := scanCategory(rows)
+		if err != nil {
+			r.log.ForContext(ctx).Error("Failed to scan category row",
+				logger.Error(err))
+			return nil, err
+		}
+		categories = append(categories, c)
+	}
+	return categories, rows.Err()
+}
+
+func scanCategory(row interface{ Scan(...any) error }) (*entity.Category, error) {
+	var c entity.Category
+	var parentID sql.NullString
+	var createdAt, updatedAt string
+	if err := row.Scan(&c.ID, &parentID, &c.Depth, &c.Name, &c.Description, &c.Position, &createdAt, &updatedAt); err != nil {
+		return nil, err
+	}
+	c.ParentID = parentID.String
+
+	var err error
+	if c.CreatedAt, err = time.Parse(time.RFC3339, createdAt); err != nil {
+		return nil, fmt.Errorf("failed to parse created_at: %w", err)
+	}
+	if c.UpdatedAt, err = time.Parse(time.RFC3339, updatedAt); err != nil {
+		return nil, fmt.Errorf("failed to parse updated_at: %w", err)
+	}
+	return &c, nil
+}
+
+// subcategoriesFilter условие column IN (категория categoryID и все ее подкатегории) с аргументами
+func subcategoriesFilter(ctx context.Context, column, categoryID string) (string, []interface{}) {
+	tenantID := tenant.FromContext(ctx)
+	filter := ` AND ` + column + ` IN (
+	    WITH RECURSIVE subtree(id) AS (
+	        SELECT ?
+	        UNION
+	        SELECT c.id FROM categories c JOIN subtree s ON c.parent_id = s.id WHERE c.tenant_id = ?
+	    )
+	    SELECT id FROM subtree)`
+	return filter, []interface{}{categoryID, tenantID}
+}
+
+func nullableString(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
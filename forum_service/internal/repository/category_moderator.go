@@ -0,0 +1,191 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/kprf42/dolgova/forum_service/internal/entity"
+	"github.com/kprf42/dolgova/forum_service/internal/tenant"
+	"github.com/kprf42/dolgova/pkg/logger"
+)
+
+// ErrModeratorNotFound назначение модератора категории не найдено
+var ErrModeratorNotFound = errors.New("category moderator not found")
+
+// CategoryModeratorRepository хранит назначения модераторов по категориям
+type CategoryModeratorRepository struct {
+	db  *sql.DB
+	log *logger.Logger
+}
+
+func NewCategoryModeratorRepository(db *sql.DB, log *logger.Logger) *CategoryModeratorRepository {
+	return &CategoryModeratorRepository{
+		db:  db,
+		log: log,
+	}
+}
+
+// Assign назначает пользователя модератором категории. Повторное назначение не считается ошибкой.
+func (r *CategoryModeratorRepository) Assign(ctx context.Context, m *entity.CategoryModerator) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	r.log.ForContext(ctx).Info("Assigning category moderator",
+		logger.String("category_id", m.CategoryID),
+		logger.String("user_id", m.UserID))
+
+	if m.CreatedAt.IsZero() {
+		m.CreatedAt = time.Now().UTC()
+	}
+
+	query := `INSERT OR IGNORE INTO category_moderators (tenant_id, category_id, user_id, created_by, created_at)
+	          VALUES (?, ?, ?, ?, ?)`
+	_, err := r.db.ExecContext(ctx, query,
+		tenant.FromContext(ctx),
+		m.CategoryID,
+		m.UserID,
+		m.CreatedBy,
+		m.CreatedAt.Format(time.RFC3339),
+	)
+	if err != nil {
+		r.log.ForContext(ctx).Error("Failed to assign category moderator",
+			logger.String("category_id", m.CategoryID),
+			logger.String("user_id", m.UserID),
+			logger.Error(err))
+		return err
+	}
+
+	r.log.ForContext(ctx).Info("Successfully assigned category moderator",
+		logger.String("category_id", m.CategoryID),
+		logger.String("user_id", m.UserID))
+	return nil
+}
+
+// Remove снимает пользователя с модерации категории
+func (r *CategoryModeratorRepository) Remove(ctx context.Context, categoryID, userID string) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	r.log.ForContext(ctx).Info("Removing category moderator",
+		logger.String("category_id", categoryID),
+		logger.String("user_id", userID))
+
+	query := `DELETE FROM category_moderators WHERE tenant_id = ? AND category_id = ? AND user_id = ?`
+	result, err := r.db.ExecContext(ctx, query, tenant.FromContext(ctx), categoryID, userID)
+	if err != nil {
+		r.log.ForContext(ctx).Error("Failed to remove category moderator",
+			logger.String("category_id", categoryID),
+			logger.String("user_id", userID),
+			logger.Error(err))
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		r.log.ForContext(ctx).Warn("Category moderator not found",
+			logger.String("category_id", categoryID),
+			logger.String("user_id", userID))
+		return ErrModeratorNotFound
+	}
+
+	r.log.ForContext(ctx).Info("Successfully removed category moderator",
+		logger.String("category_id", categoryID),
+		logger.String("user_id", userID))
+	return nil
+}
+
+// ListByCategory возвращает модераторов категории
+func (r *CategoryModeratorRepository) ListByCategory(ctx context.Context, categoryID string) ([]*entity.CategoryModerator, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	r.log.ForContext(ctx).Info("Getting category moderators",
+		logger.String("category_id", categoryID))
+
+	query := `SELECT category_id, user_id, created_by, created_at
+	          FROM category_moderators WHERE tenant_id = ? AND category_id = ? ORDER BY created_at`
+
+	rows, err := r.db.QueryContext(ctx, query, tenant.FromContext(ctx), categoryID)
+	if err != nil {
+		r.log.ForContext(ctx).Error("Failed to get category moderators",
+			logger.String("category_id", categoryID),
+			logger.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	moderators := []*entity.CategoryModerator{}
+	for rows.Next() {
+		var m entity.CategoryModerator
+		var createdAt string
+
+		if err := rows.Scan(&m.CategoryID, &m.UserID, &m.CreatedBy, &createdAt); err != nil {
+			r.log.ForContext(ctx).Error("Failed to scan category moderator row",
+				logger.Error(err))
+			return nil, err
+		}
+
+		m.CreatedAt, err = time.Parse(time.RFC3339, createdAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse created_at: %w", err)
+		}
+
+		moderators = append(moderators, &m)
+	}
+
+	return moderators, rows.Err()
+}
+
+// CategoriesOf возвращает категории, которые модерирует пользователь
+func (r *CategoryModeratorRepository) CategoriesOf(ctx context.Context, userID string) ([]string, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	query := `SELECT category_id FROM category_moderators WHERE tenant_id = ? AND user_id = ?`
+
+	rows, err := r.db.QueryContext(ctx, query, tenant.FromContext(ctx), userID)
+	if err != nil {
+		r.log.ForContext(ctx).Error("Failed to get moderated categories",
+			logger.String("user_id", userID),
+			logger.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var categories []string
+	for rows.Next() {
+		var categoryID string
+		if err := rows.Scan(&categoryID); err != nil {
+			return nil, err
+		}
+		categories = append(categories, categoryID)
+	}
+
+	return categories, rows.Err()
+}
+
+// IsModerator проверяет, назначен ли пользователь модератором категории
+func (r *CategoryModeratorRepository) IsModerator(ctx context.Context, userID, categoryID string) (bool, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	query := `SELECT EXISTS(SELECT 1 FROM category_moderators WHERE tenant_id = ? AND category_id = ? AND user_id = ?)`
+
+	var exists bool
+	err := r.db.QueryRowContext(ctx, query, tenant.FromContext(ctx), categoryID, userID).Scan(&exists)
+	if err != nil {
+		r.log.ForContext(ctx).Error("Failed to check category moderator",
+			logger.String("category_id", categoryID),
+			logger.String("user_id", userID),
+			logger.Error(err))
+		return false, err
+	}
+
+	return exists, nil
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/kprf42/dolgova/forum_service/internal/entity"
+	"github.com/kprf42/dolgova/forum_service/internal/tenant"
 	"github.com/kprf42/dolgova/pkg/logger"
 	_ "github.com/mattn/go-sqlite3"
 )
@@ -24,14 +25,17 @@ func NewChatRepository(db *sql.DB, log *logger.Logger) *ChatRepository {
 }
 
 func (r *ChatRepository) SaveMessage(ctx context.Context, msg *entity.ChatMessage) error {
-	r.log.Info("Saving chat message",
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	r.log.ForContext(ctx).Info("Saving chat message",
 		logger.String("message_id", msg.ID),
 		logger.String("user_id", msg.UserID))
 
-	query := `INSERT INTO chat_messages (id, user_id, text, created_at) VALUES (?, ?, ?, ?)`
-	result, err := r.db.ExecContext(ctx, query, msg.ID, msg.UserID, msg.Text, msg.CreatedAt.Format(time.RFC3339))
+	query := `INSERT INTO chat_messages (id, user_id, text, created_at, tenant_id) VALUES (?, ?, ?, ?, ?)`
+	result, err := r.db.ExecContext(ctx, query, msg.ID, msg.UserID, msg.Text, msg.CreatedAt.Format(time.RFC3339), tenant.FromContext(ctx))
 	if err != nil {
-		r.log.Error("Failed to save chat message",
+		r.log.ForContext(ctx).Error("Failed to save chat message",
 			logger.String("message_id", msg.ID),
 			logger.Error(err))
 		return err
@@ -39,34 +43,51 @@ func (r *ChatRepository) SaveMessage(ctx context.Context, msg *entity.ChatMessag
 
 	rows, err := result.RowsAffected()
 	if err != nil {
-		r.log.Error("Failed to get rows affected",
+		r.log.ForContext(ctx).Error("Failed to get rows affected",
 			logger.String("message_id", msg.ID),
 			logger.Error(err))
 		return err
 	}
 
 	if rows == 0 {
-		r.log.Error("No rows affected when saving chat message",
+		r.log.ForContext(ctx).Error("No rows affected when saving chat message",
 			logger.String("message_id", msg.ID))
 		return fmt.Errorf("no rows affected when saving chat message")
 	}
 
-	r.log.Info("Successfully saved chat message",
+	r.log.ForContext(ctx).Info("Successfully saved chat message",
 		logger.String("message_id", msg.ID))
 	return nil
 }
 
-func (r *ChatRepository) GetMessages(ctx context.Context, limit, offset int) ([]*entity.ChatMessage, error) {
-	r.log.Info("Getting chat messages",
+// GetMessages возвращает сообщения чата от новых к старым. Если задан after,
+// выборка начинается после курсора (keyset), иначе используется offset.
+func (r *ChatRepository) GetMessages(ctx context.Context, limit, offset int, after *entity.PageCursor) ([]*entity.ChatMessage, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	r.log.ForContext(ctx).Info("Getting chat messages",
 		logger.Int("limit", limit),
 		logger.Int("offset", offset))
 
 	query := `SELECT id, user_id, text, created_at FROM chat_messages 
-	          ORDER BY created_at DESC LIMIT ? OFFSET ?`
+	          WHERE tenant_id = ?`
+	args := []interface{}{tenant.FromContext(ctx)}
+
+	blocked, blockedArgs := blockedFilter(ctx, "user_id")
+	query += blocked
+	args = append(args, blockedArgs...)
+	if after != nil {
+		createdAt := after.CreatedAt.Format(time.RFC3339)
+		query += ` AND (created_at < ? OR (created_at = ? AND id < ?))`
+		args = append(args, createdAt, createdAt, after.ID)
+	}
+	query += ` ORDER BY created_at DESC, id DESC LIMIT ? OFFSET ?`
+	args = append(args, limit, offset)
 
-	rows, err := r.db.QueryContext(ctx, query, limit, offset)
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
-		r.log.Error("Failed to get chat messages",
+		r.log.ForContext(ctx).Error("Failed to get chat messages",
 			logger.Int("limit", limit),
 			logger.Int("offset", offset),
 			logger.Error(err))
@@ -85,14 +106,14 @@ func (r *ChatRepository) GetMessages(ctx context.Context, limit, offset int) ([]
 			&msg.Text,
 			&createdAt,
 		); err != nil {
-			r.log.Error("Failed to scan chat message row",
+			r.log.ForContext(ctx).Error("Failed to scan chat message row",
 				logger.Error(err))
 			return nil, err
 		}
 
 		msg.CreatedAt, err = time.Parse(time.RFC3339, createdAt)
 		if err != nil {
-			r.log.Error("Failed to parse created_at",
+			r.log.ForContext(ctx).Error("Failed to parse created_at",
 				logger.String("created_at", createdAt),
 				logger.Error(err))
 			return nil, err
@@ -101,20 +122,24 @@ func (r *ChatRepository) GetMessages(ctx context.Context, limit, offset int) ([]
 		messages = append(messages, &msg)
 	}
 
-	r.log.Info("Successfully got chat messages",
+	r.log.ForContext(ctx).Info("Successfully got chat messages",
 		logger.Int("count", len(messages)))
 	return messages, nil
 }
 
+// CleanOldMessages удаляет старые сообщения во всех сообществах
 func (r *ChatRepository) CleanOldMessages(ctx context.Context, olderThan time.Duration) error {
-	r.log.Info("Cleaning old chat messages",
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	r.log.ForContext(ctx).Info("Cleaning old chat messages",
 		logger.Float64("older_than_seconds", olderThan.Seconds()))
 
 	result, err := r.db.ExecContext(ctx,
 		`DELETE FROM chat_messages WHERE created_at < datetime('now', ?)`,
 		fmt.Sprintf("-%d seconds", int(olderThan.Seconds())))
 	if err != nil {
-		r.log.Error("Failed to clean old chat messages",
+		r.log.ForContext(ctx).Error("Failed to clean old chat messages",
 			logger.Float64("older_than_seconds", olderThan.Seconds()),
 			logger.Error(err))
 		return err
@@ -122,12 +147,12 @@ func (r *ChatRepository) CleanOldMessages(ctx context.Context, olderThan time.Du
 
 	rows, err := result.RowsAffected()
 	if err != nil {
-		r.log.Error("Failed to get rows affected",
+		r.log.ForContext(ctx).Error("Failed to get rows affected",
 			logger.Error(err))
 		return err
 	}
 
-	r.log.Info("Successfully cleaned old chat messages",
+	r.log.ForContext(ctx).Info("Successfully cleaned old chat messages",
 		logger.Int64("deleted_count", rows))
 	return nil
 }
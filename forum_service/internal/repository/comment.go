@@ -8,9 +8,13 @@ import (
 	"time"
 
 	"github.com/kprf42/dolgova/forum_service/internal/entity"
+	"github.com/kprf42/dolgova/forum_service/internal/tenant"
 	"github.com/kprf42/dolgova/pkg/logger"
 )
 
+// ErrCommentNotFound комментарий не найден
+var ErrCommentNotFound = errors.New("comment not found")
+
 type CommentRepository struct {
 	db  *sql.DB
 	log *logger.Logger
@@ -24,22 +28,32 @@ func NewCommentRepository(db *sql.DB, log *logger.Logger) *CommentRepository {
 }
 
 func (r *CommentRepository) Create(ctx context.Context, comment *entity.Comment) error {
-	r.log.Info("Creating new comment",
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	r.log.ForContext(ctx).Info("Creating new comment",
 		logger.String("comment_id", comment.ID),
 		logger.String("post_id", comment.PostID),
 		logger.String("author_id", comment.AuthorID))
 
-	query := `INSERT INTO comments (id, content, post_id, author_id, created_at) 
-	          VALUES (?, ?, ?, ?, ?)`
+	status := comment.Status
+	if status == "" {
+		status = entity.StatusPublished
+	}
+
+	query := `INSERT INTO comments (id, content, post_id, author_id, created_at, tenant_id, status) 
+	          VALUES (?, ?, ?, ?, ?, ?, ?)`
 	result, err := r.db.ExecContext(ctx, query,
 		comment.ID,
 		comment.Content,
 		comment.PostID,
 		comment.AuthorID,
 		comment.CreatedAt.Format(time.RFC3339),
+		tenant.FromContext(ctx),
+		status,
 	)
 	if err != nil {
-		r.log.Error("Failed to create comment",
+		r.log.ForContext(ctx).Error("Failed to create comment",
 			logger.String("comment_id", comment.ID),
 			logger.Error(err))
 		return err
@@ -47,48 +61,70 @@ func (r *CommentRepository) Create(ctx context.Context, comment *entity.Comment)
 
 	rows, err := result.RowsAffected()
 	if err != nil {
-		r.log.Error("Failed to get rows affected",
+		r.log.ForContext(ctx).Error("Failed to get rows affected",
 			logger.String("comment_id", comment.ID),
 			logger.Error(err))
 		return err
 	}
 
 	if rows == 0 {
-		r.log.Error("No rows affected when creating comment",
+		r.log.ForContext(ctx).Error("No rows affected when creating comment",
 			logger.String("comment_id", comment.ID))
 		return fmt.Errorf("no rows affected when creating comment")
 	}
 
-	r.log.Info("Successfully created comment",
+	r.log.ForContext(ctx).Info("Successfully created comment",
 		logger.String("comment_id", comment.ID))
 	return nil
 }
 
+// PostLocked сообщает, закрыта ли тема поста postID для новых комментариев
+func (r *CommentRepository) PostLocked(ctx context.Context, postID string) (bool, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	var locked bool
+	err := r.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM posts WHERE id = ? AND tenant_id = ? AND is_locked)`,
+		postID, tenant.FromContext(ctx)).Scan(&locked)
+	if err != nil {
+		r.log.ForContext(ctx).Error("Failed to check post lock",
+			logger.String("post_id", postID),
+			logger.Error(err))
+		return false, err
+	}
+	return locked, nil
+}
+
 func (r *CommentRepository) GetByID(ctx context.Context, id string) (*entity.Comment, error) {
-	r.log.Info("Getting comment by ID",
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	r.log.ForContext(ctx).Info("Getting comment by ID",
 		logger.String("comment_id", id))
 
-	query := `SELECT id, content, post_id, author_id, created_at 
-	          FROM comments WHERE id = ?`
+	query := `SELECT id, content, post_id, author_id, score, created_at, status 
+	          FROM comments WHERE id = ? AND tenant_id = ?`
 
 	var comment entity.Comment
 	var createdAt string
 
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
+	err := r.db.QueryRowContext(ctx, query, id, tenant.FromContext(ctx)).Scan(
 		&comment.ID,
 		&comment.Content,
 		&comment.PostID,
 		&comment.AuthorID,
+		&comment.Score,
 		&createdAt,
+		&comment.Status,
 	)
 
 	if errors.Is(err, sql.ErrNoRows) {
-		r.log.Warn("Comment not found",
+		r.log.ForContext(ctx).Warn("Comment not found",
 			logger.String("comment_id", id))
-		return nil, fmt.Errorf("comment not found")
+		return nil, ErrCommentNotFound
 	}
 	if err != nil {
-		r.log.Error("Failed to get comment",
+		r.log.ForContext(ctx).Error("Failed to get comment",
 			logger.String("comment_id", id),
 			logger.Error(err))
 		return nil, err
@@ -96,31 +132,51 @@ func (r *CommentRepository) GetByID(ctx context.Context, id string) (*entity.Com
 
 	comment.CreatedAt, err = time.Parse(time.RFC3339, createdAt)
 	if err != nil {
-		r.log.Error("Failed to parse created_at",
+		r.log.ForContext(ctx).Error("Failed to parse created_at",
 			logger.String("comment_id", id),
 			logger.String("created_at", createdAt),
 			logger.Error(err))
 		return nil, fmt.Errorf("failed to parse created_at: %w", err)
 	}
 
-	r.log.Info("Successfully got comment",
+	r.log.ForContext(ctx).Info("Successfully got comment",
 		logger.String("comment_id", id))
 	return &comment, nil
 }
 
-func (r *CommentRepository) GetByPostID(ctx context.Context, postID string, limit, offset int) ([]*entity.Comment, error) {
-	r.log.Info("Getting comments by post ID",
+// GetByPostID возвращает опубликованные комментарии поста от новых к старым, принятый ответ первым.
+// Если задан after, выборка начинается после курсора (keyset), иначе используется offset.
+func (r *CommentRepository) GetByPostID(ctx context.Context, postID string, limit, offset int, after *entity.PageCursor) ([]*entity.Comment, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	r.log.ForContext(ctx).Info("Getting comments by post ID",
 		logger.String("post_id", postID),
 		logger.Int("limit", limit),
 		logger.Int("offset", offset))
 
-	query := `SELECT id, content, post_id, author_id, created_at 
-	          FROM comments WHERE post_id = ? 
-	          ORDER BY created_at DESC LIMIT ? OFFSET ?`
+	query := `SELECT id, content, post_id, author_id, score, created_at,
+	                 id = (SELECT accepted_comment_id FROM posts WHERE id = comments.post_id AND tenant_id = comments.tenant_id) AS accepted
+	          FROM comments WHERE post_id = ? AND tenant_id = ? AND status = 'published'`
+	args := []interface{}{postID, tenant.FromContext(ctx)}
+
+	blocked, blockedArgs := blockedFilter(ctx, "author_id")
+	query += blocked
+	args = append(args, blockedArgs...)
+	// Принятый ответ закреплен наверху первой страницы, следующие страницы по курсору его не повторяют
+	order := ` ORDER BY accepted DESC, created_at DESC, id DESC`
+	if after != nil {
+		createdAt := after.CreatedAt.Format(time.RFC3339)
+		query += ` AND NOT accepted AND (created_at < ? OR (created_at = ? AND id < ?))`
+		args = append(args, createdAt, createdAt, after.ID)
+		order = ` ORDER BY created_at DESC, id DESC`
+	}
+	query += order + ` LIMIT ? OFFSET ?`
+	args = append(args, limit, offset)
 
-	rows, err := r.db.QueryContext(ctx, query, postID, limit, offset)
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
-		r.log.Error("Failed to get comments",
+		r.log.ForContext(ctx).Error("Failed to get comments",
 			logger.String("post_id", postID),
 			logger.Error(err))
 		return nil, err
@@ -137,38 +193,44 @@ func (r *CommentRepository) GetByPostID(ctx context.Context, postID string, limi
 			&comment.Content,
 			&comment.PostID,
 			&comment.AuthorID,
+			&comment.Score,
 			&createdAt,
+			&comment.IsAccepted,
 		); err != nil {
-			r.log.Error("Failed to scan comment row",
+			r.log.ForContext(ctx).Error("Failed to scan comment row",
 				logger.Error(err))
 			return nil, err
 		}
 
 		comment.CreatedAt, err = time.Parse(time.RFC3339, createdAt)
 		if err != nil {
-			r.log.Error("Failed to parse created_at",
+			r.log.ForContext(ctx).Error("Failed to parse created_at",
 				logger.String("created_at", createdAt),
 				logger.Error(err))
 			return nil, fmt.Errorf("failed to parse created_at: %w", err)
 		}
+		comment.Status = entity.StatusPublished
 
 		comments = append(comments, &comment)
 	}
 
-	r.log.Info("Successfully got comments",
+	r.log.ForContext(ctx).Info("Successfully got comments",
 		logger.String("post_id", postID),
 		logger.Int("count", len(comments)))
 	return comments, nil
 }
 
 func (r *CommentRepository) Update(ctx context.Context, id string, content string) error {
-	r.log.Info("Updating comment",
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	r.log.ForContext(ctx).Info("Updating comment",
 		logger.String("comment_id", id))
 
-	query := `UPDATE comments SET content = ? WHERE id = ?`
-	result, err := r.db.ExecContext(ctx, query, content, id)
+	query := `UPDATE comments SET content = ? WHERE id = ? AND tenant_id = ?`
+	result, err := r.db.ExecContext(ctx, query, content, id, tenant.FromContext(ctx))
 	if err != nil {
-		r.log.Error("Failed to update comment",
+		r.log.ForContext(ctx).Error("Failed to update comment",
 			logger.String("comment_id", id),
 			logger.Error(err))
 		return err
@@ -176,17 +238,17 @@ func (r *CommentRepository) Update(ctx context.Context, id string, content strin
 
 	rows, err := result.RowsAffected()
 	if err != nil {
-		r.log.Error("Failed to get rows affected",
+		r.log.ForContext(ctx).Error("Failed to get rows affected",
 			logger.String("comment_id", id),
 			logger.Error(err))
 		return err
 	}
 
 	if rows == 0 {
-		r.log.Warn("No rows affected when updating comment",
+		r.log.ForContext(ctx).Warn("No rows affected when updating comment",
 			logger.String("comment_id", id))
 	} else {
-		r.log.Info("Successfully updated comment",
+		r.log.ForContext(ctx).Info("Successfully updated comment",
 			logger.String("comment_id", id))
 	}
 
@@ -194,13 +256,16 @@ func (r *CommentRepository) Update(ctx context.Context, id string, content strin
 }
 
 func (r *CommentRepository) Delete(ctx context.Context, id string) error {
-	r.log.Info("Deleting comment",
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	r.log.ForContext(ctx).Info("Deleting comment",
 		logger.String("comment_id", id))
 
-	query := `DELETE FROM comments WHERE id = ?`
-	result, err := r.db.ExecContext(ctx, query, id)
+	query := `DELETE FROM comments WHERE id = ? AND tenant_id = ?`
+	result, err := r.db.ExecContext(ctx, query, id, tenant.FromContext(ctx))
 	if err != nil {
-		r.log.Error("Failed to delete comment",
+		r.log.ForContext(ctx).Error("Failed to delete comment",
 			logger.String("comment_id", id),
 			logger.Error(err))
 		return err
@@ -208,17 +273,17 @@ func (r *CommentRepository) Delete(ctx context.Context, id string) error {
 
 	rows, err := result.RowsAffected()
 	if err != nil {
-		r.log.Error("Failed to get rows affected",
+		r.log.ForContext(ctx).Error("Failed to get rows affected",
 			logger.String("comment_id", id),
 			logger.Error(err))
 		return err
 	}
 
 	if rows == 0 {
-		r.log.Warn("No rows affected when deleting comment",
+		r.log.ForContext(ctx).Warn("No rows affected when deleting comment",
 			logger.String("comment_id", id))
 	} else {
-		r.log.Info("Successfully deleted comment",
+		r.log.ForContext(ctx).Info("Successfully deleted comment",
 			logger.String("comment_id", id))
 	}
 
@@ -226,21 +291,127 @@ func (r *CommentRepository) Delete(ctx context.Context, id string) error {
 }
 
 func (r *CommentRepository) CountByPostID(ctx context.Context, postID string) (int, error) {
-	r.log.Info("Counting comments by post ID",
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	r.log.ForContext(ctx).Info("Counting comments by post ID",
 		logger.String("post_id", postID))
 
-	query := `SELECT COUNT(*) FROM comments WHERE post_id = ?`
+	query := `SELECT COUNT(*) FROM comments WHERE post_id = ? AND tenant_id = ? AND status = 'published'`
+	args := []interface{}{postID, tenant.FromContext(ctx)}
+	blocked, blockedArgs := blockedFilter(ctx, "author_id")
+	query += blocked
+	args = append(args, blockedArgs...)
+
 	var count int
-	err := r.db.QueryRowContext(ctx, query, postID).Scan(&count)
+	err := r.db.QueryRowContext(ctx, query, args...).Scan(&count)
 	if err != nil {
-		r.log.Error("Failed to count comments",
+		r.log.ForContext(ctx).Error("Failed to count comments",
 			logger.String("post_id", postID),
 			logger.Error(err))
 		return 0, err
 	}
 
-	r.log.Info("Successfully counted comments",
+	r.log.ForContext(ctx).Info("Successfully counted comments",
 		logger.String("post_id", postID),
 		logger.Int("count", count))
 	return count, nil
 }
+
+// CountByAuthor возвращает число опубликованных комментариев автора и сколько из них
+// отмечены принятым ответом
+func (r *CommentRepository) CountByAuthor(ctx context.Context, authorID string) (comments, accepted int, err error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	query := `SELECT COUNT(*),
+	                 COALESCE(SUM(CASE WHEN EXISTS (SELECT 1 FROM posts p
+	                     WHERE p.tenant_id = c.tenant_id AND p.id = c.post_id AND p.accepted_comment_id = c.id) THEN 1 ELSE 0 END), 0)
+	          FROM comments c WHERE c.tenant_id = ? AND c.author_id = ? AND c.status = 'published'`
+	err = r.db.QueryRowContext(ctx, query, tenant.FromContext(ctx), authorID).Scan(&comments, &accepted)
+	if err != nil {
+		r.log.ForContext(ctx).Error("Failed to count comments by author",
+			logger.String("author_id", authorID),
+			logger.Error(err))
+		return 0, 0, err
+	}
+	return comments, accepted, nil
+}
+
+// Vote сохраняет голос userID за комментарий так же, как PostRepository.Vote. Возвращает новый счет.
+func (r *CommentRepository) Vote(ctx context.Context, commentID, userID string, value int) (int, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	r.log.ForContext(ctx).Info("Voting for comment",
+		logger.String("comment_id", commentID),
+		logger.String("user_id", userID),
+		logger.Int("value", value))
+
+	tenantID := tenant.FromContext(ctx)
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		r.log.ForContext(ctx).Error("Failed to begin vote transaction",
+			logger.Error(err))
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var old int
+	err = tx.QueryRowContext(ctx,
+		`SELECT value FROM comment_votes WHERE tenant_id = ? AND comment_id = ? AND user_id = ?`,
+		tenantID, commentID, userID,
+	).Scan(&old)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		r.log.ForContext(ctx).Error("Failed to get comment vote",
+			logger.String("comment_id", commentID),
+			logger.Error(err))
+		return 0, err
+	}
+
+	if old != value {
+		now := time.Now().UTC().Format(time.RFC3339)
+		if value == 0 {
+			_, err = tx.ExecContext(ctx,
+				`DELETE FROM comment_votes WHERE tenant_id = ? AND comment_id = ? AND user_id = ?`,
+				tenantID, commentID, userID)
+		} else {
+			_, err = tx.ExecContext(ctx,
+				`INSERT INTO comment_votes (tenant_id, comment_id, user_id, value, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?)
+				 ON CONFLICT(tenant_id, comment_id, user_id) DO UPDATE SET value = excluded.value, updated_at = excluded.updated_at`,
+				tenantID, commentID, userID, value, now, now)
+		}
+		if err != nil {
+			r.log.ForContext(ctx).Error("Failed to save comment vote",
+				logger.String("comment_id", commentID),
+				logger.Error(err))
+			return 0, err
+		}
+
+		_, err = tx.ExecContext(ctx, `UPDATE comments SET score = score + ? WHERE tenant_id = ? AND id = ?`,
+			value-old, tenantID, commentID)
+		if err != nil {
+			r.log.ForContext(ctx).Error("Failed to update comment score",
+				logger.String("comment_id", commentID),
+				logger.Error(err))
+			return 0, err
+		}
+	}
+
+	var score int
+	err = tx.QueryRowContext(ctx, `SELECT score FROM comments WHERE tenant_id = ? AND id = ?`, tenantID, commentID).Scan(&score)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, ErrCommentNotFound
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		r.log.ForContext(ctx).Error("Failed to commit vote transaction",
+			logger.String("comment_id", commentID),
+			logger.Error(err))
+		return 0, err
+	}
+	return score, nil
+}
@@ -0,0 +1,176 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/kprf42/dolgova/forum_service/internal/tenant"
+	"github.com/kprf42/dolgova/pkg/logger"
+)
+
+// KarmaRepository хранит начисления кармы и итоговую карму пользователей. Карма считается
+// в каждом сообществе отдельно.
+type KarmaRepository struct {
+	db  *sql.DB
+	log *logger.Logger
+}
+
+func NewKarmaRepository(db *sql.DB, log *logger.Logger) *KarmaRepository {
+	return &KarmaRepository{
+		db:  db,
+		log: log,
+	}
+}
+
+// Set заменяет начисление userID за (source, sourceID) на points и корректирует итог
+// на разницу со старым значением. points = 0 удаляет начисление.
+func (r *KarmaRepository) Set(ctx context.Context, userID, source, sourceID string, points int) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	r.log.ForContext(ctx).Info("Setting karma points",
+		logger.String("user_id", userID),
+		logger.String("source", source),
+		logger.String("source_id", sourceID),
+		logger.Int("points", points))
+
+	tenantID := tenant.FromContext(ctx)
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		r.log.ForContext(ctx).Error("Failed to begin karma transaction",
+			logger.Error(err))
+		return err
+	}
+	defer tx.Rollback()
+
+	var old int
+	err = tx.QueryRowContext(ctx,
+		`SELECT points FROM karma_events WHERE tenant_id = ? AND source = ? AND source_id = ? AND user_id = ?`,
+		tenantID, source, sourceID, userID,
+	).Scan(&old)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		r.log.ForContext(ctx).Error("Failed to get karma event",
+			logger.String("user_id", userID),
+			logger.Error(err))
+		return err
+	}
+	if old == points {
+		return nil
+	}
+
+	if points == 0 {
+		_, err = tx.ExecContext(ctx,
+			`DELETE FROM karma_events WHERE tenant_id = ? AND source = ? AND source_id = ? AND user_id = ?`,
+			tenantID, source, sourceID, userID)
+	} else {
+		_, err = tx.ExecContext(ctx,
+			`INSERT INTO karma_events (tenant_id, user_id, source, source_id, points) VALUES (?, ?, ?, ?, ?)
+			 ON CONFLICT(tenant_id, source, source_id, user_id) DO UPDATE SET points = excluded.points`,
+			tenantID, userID, source, sourceID, points)
+	}
+	if err != nil {
+		r.log.ForContext(ctx).Error("Failed to save karma event",
+			logger.String("user_id", userID),
+			logger.Error(err))
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO user_karma (tenant_id, user_id, karma) VALUES (?, ?, ?)
+		 ON CONFLICT(tenant_id, user_id) DO UPDATE SET karma = karma + excluded.karma`,
+		tenantID, userID, points-old)
+	if err != nil {
+		r.log.ForContext(ctx).Error("Failed to update user karma",
+			logger.String("user_id", userID),
+			logger.Error(err))
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		r.log.ForContext(ctx).Error("Failed to commit karma transaction",
+			logger.String("user_id", userID),
+			logger.Error(err))
+		return err
+	}
+
+	r.log.ForContext(ctx).Info("Successfully set karma points",
+		logger.String("user_id", userID),
+		logger.Int("delta", points-old))
+	return nil
+}
+
+// Get возвращает карму пользователя в сообществе; без начислений карма равна 0
+func (r *KarmaRepository) Get(ctx context.Context, userID string) (int, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	var karma int
+	err := r.db.QueryRowContext(ctx, `SELECT karma FROM user_karma WHERE tenant_id = ? AND user_id = ?`,
+		tenant.FromContext(ctx), userID).Scan(&karma)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		r.log.ForContext(ctx).Error("Failed to get karma",
+			logger.String("user_id", userID),
+			logger.Error(err))
+		return 0, err
+	}
+	return karma, nil
+}
+
+// ForUsers возвращает карму пользователей userIDs; пользователей без кармы в результате нет
+func (r *KarmaRepository) ForUsers(ctx context.Context, userIDs []string) (map[string]int, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	filter, args := inFilter("user_id", userIDs)
+	rows, err := r.db.QueryContext(ctx, `SELECT user_id, karma FROM user_karma WHERE tenant_id = ?`+filter,
+		append([]interface{}{tenant.FromContext(ctx)}, args...)...)
+	if err != nil {
+		r.log.ForContext(ctx).Error("Failed to get users karma",
+			logger.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	karma := make(map[string]int, len(userIDs))
+	for rows.Next() {
+		var userID string
+		var value int
+		if err := rows.Scan(&userID, &value); err != nil {
+			return nil, err
+		}
+		karma[userID] = value
+	}
+	return karma, rows.Err()
+}
+
+// BySource возвращает карму пользователя по источникам начислений; источников без начислений в результате нет
+func (r *KarmaRepository) BySource(ctx context.Context, userID string) (map[string]int, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT source, SUM(points) FROM karma_events WHERE tenant_id = ? AND user_id = ? GROUP BY source`,
+		tenant.FromContext(ctx), userID)
+	if err != nil {
+		r.log.ForContext(ctx).Error("Failed to get karma by source",
+			logger.String("user_id", userID),
+			logger.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	karma := make(map[string]int)
+	for rows.Next() {
+		var source string
+		var points int
+		if err := rows.Scan(&source, &points); err != nil {
+			return nil, err
+		}
+		karma[source] = points
+	}
+	return karma, rows.Err()
+}
@@ -0,0 +1,241 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kprf42/dolgova/forum_service/internal/entity"
+	"github.com/kprf42/dolgova/forum_service/internal/tenant"
+	"github.com/kprf42/dolgova/pkg/logger"
+)
+
+// ModerationRepository работает со статусами модерации постов и комментариев
+type ModerationRepository struct {
+	db  *sql.DB
+	log *logger.Logger
+}
+
+func NewModerationRepository(db *sql.DB, log *logger.Logger) *ModerationRepository {
+	return &ModerationRepository{
+		db:  db,
+		log: log,
+	}
+}
+
+// CountPublished возвращает число опубликованных постов и комментариев автора в текущем сообществе
+func (r *ModerationRepository) CountPublished(ctx context.Context, authorID string) (int, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	r.log.ForContext(ctx).Info("Counting published content",
+		logger.String("author_id", authorID))
+
+	query := `SELECT
+	            (SELECT COUNT(*) FROM posts WHERE author_id = ? AND tenant_id = ? AND status = 'published') +
+	            (SELECT COUNT(*) FROM comments WHERE author_id = ? AND tenant_id = ? AND status = 'published')`
+
+	tenantID := tenant.FromContext(ctx)
+	var count int
+	err := r.db.QueryRowContext(ctx, query, authorID, tenantID, authorID, tenantID).Scan(&count)
+	if err != nil {
+		r.log.ForContext(ctx).Error("Failed to count published content",
+			logger.String("author_id", authorID),
+			logger.Error(err))
+		return 0, err
+	}
+
+	r.log.ForContext(ctx).Info("Successfully counted published content",
+		logger.String("author_id", authorID),
+		logger.Int("count", count))
+	return count, nil
+}
+
+// PendingPosts возвращает посты, ожидающие модерации, от старых к новым.
+// categories ограничивает выборку категориями; nil - все категории.
+func (r *ModerationRepository) PendingPosts(ctx context.Context, categories []string, limit, offset int) ([]*entity.Post, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	r.log.ForContext(ctx).Info("Getting pending posts",
+		logger.Int("limit", limit),
+		logger.Int("offset", offset))
+
+	filter, args := inFilter("category_id", categories)
+	query := `SELECT id, title, content, author_id, category_id, is_pinned, created_at
+	          FROM posts WHERE tenant_id = ? AND status = 'pending'` + filter + ` ORDER BY created_at ASC LIMIT ? OFFSET ?`
+
+	args = append([]interface{}{tenant.FromContext(ctx)}, args...)
+	rows, err := r.db.QueryContext(ctx, query, append(args, limit, offset)...)
+	if err != nil {
+		r.log.ForContext(ctx).Error("Failed to get pending posts",
+			logger.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var posts []*entity.Post
+	for rows.Next() {
+		var post entity.Post
+		var createdAt string
+
+		if err := rows.Scan(
+			&post.ID,
+			&post.Title,
+			&post.Content,
+			&post.AuthorID,
+			&post.CategoryID,
+			&post.IsPinned,
+			&createdAt,
+		); err != nil {
+			r.log.ForContext(ctx).Error("Failed to scan post row",
+				logger.Error(err))
+			return nil, err
+		}
+
+		post.CreatedAt, err = time.Parse(time.RFC3339, createdAt)
+		if err != nil {
+			r.log.ForContext(ctx).Error("Failed to parse created_at",
+				logger.String("created_at", createdAt),
+				logger.Error(err))
+			return nil, fmt.Errorf("failed to parse created_at: %w", err)
+		}
+		post.Status = entity.StatusPending
+
+		posts = append(posts, &post)
+	}
+
+	r.log.ForContext(ctx).Info("Successfully got pending posts",
+		logger.Int("count", len(posts)))
+	return posts, nil
+}
+
+// PendingComments возвращает комментарии, ожидающие модерации, от старых к новым.
+// Категория комментария - категория его поста.
+func (r *ModerationRepository) PendingComments(ctx context.Context, categories []string, limit, offset int) ([]*entity.Comment, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	r.log.ForContext(ctx).Info("Getting pending comments",
+		logger.Int("limit", limit),
+		logger.Int("offset", offset))
+
+	filter, args := inFilter("p.category_id", categories)
+	query := `SELECT c.id, c.content, c.post_id, c.author_id, c.created_at
+	          FROM comments c JOIN posts p ON p.id = c.post_id
+	          WHERE c.tenant_id = ? AND c.status = 'pending'` + filter + ` ORDER BY c.created_at ASC LIMIT ? OFFSET ?`
+
+	args = append([]interface{}{tenant.FromContext(ctx)}, args...)
+	rows, err := r.db.QueryContext(ctx, query, append(args, limit, offset)...)
+	if err != nil {
+		r.log.ForContext(ctx).Error("Failed to get pending comments",
+			logger.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var comments []*entity.Comment
+	for rows.Next() {
+		var comment entity.Comment
+		var createdAt string
+
+		if err := rows.Scan(
+			&comment.ID,
+			&comment.Content,
+			&comment.PostID,
+			&comment.AuthorID,
+			&createdAt,
+		); err != nil {
+			r.log.ForContext(ctx).Error("Failed to scan comment row",
+				logger.Error(err))
+			return nil, err
+		}
+
+		comment.CreatedAt, err = time.Parse(time.RFC3339, createdAt)
+		if err != nil {
+			r.log.ForContext(ctx).Error("Failed to parse created_at",
+				logger.String("created_at", createdAt),
+				logger.Error(err))
+			return nil, fmt.Errorf("failed to parse created_at: %w", err)
+		}
+		comment.Status = entity.StatusPending
+
+		comments = append(comments, &comment)
+	}
+
+	r.log.ForContext(ctx).Info("Successfully got pending comments",
+		logger.Int("count", len(comments)))
+	return comments, nil
+}
+
+// SetPostStatus переводит пост из статуса from в статус to.
+// Возвращает false, если пост не найден или уже не в статусе from.
+func (r *ModerationRepository) SetPostStatus(ctx context.Context, id, from, to string) (bool, error) {
+	return r.setStatus(ctx, "posts", id, from, to)
+}
+
+// SetCommentStatus переводит комментарий из статуса from в статус to
+func (r *ModerationRepository) SetCommentStatus(ctx context.Context, id, from, to string) (bool, error) {
+	return r.setStatus(ctx, "comments", id, from, to)
+}
+
+func (r *ModerationRepository) setStatus(ctx context.Context, table, id, from, to string) (bool, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	r.log.ForContext(ctx).Info("Changing moderation status",
+		logger.String("table", table),
+		logger.String("id", id),
+		logger.String("from", from),
+		logger.String("to", to))
+
+	query := fmt.Sprintf(`UPDATE %s SET status = ? WHERE id = ? AND tenant_id = ? AND status = ?`, table)
+	result, err := r.db.ExecContext(ctx, query, to, id, tenant.FromContext(ctx), from)
+	if err != nil {
+		r.log.ForContext(ctx).Error("Failed to change moderation status",
+			logger.String("table", table),
+			logger.String("id", id),
+			logger.Error(err))
+		return false, err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		r.log.ForContext(ctx).Error("Failed to get rows affected",
+			logger.String("id", id),
+			logger.Error(err))
+		return false, err
+	}
+
+	if rows == 0 {
+		r.log.ForContext(ctx).Warn("No rows affected when changing moderation status",
+			logger.String("table", table),
+			logger.String("id", id))
+		return false, nil
+	}
+
+	r.log.ForContext(ctx).Info("Successfully changed moderation status",
+		logger.String("table", table),
+		logger.String("id", id))
+	return true, nil
+}
+
+// inFilter строит условие "AND column IN (...)"; для nil условие не добавляется,
+// для пустого списка условие всегда ложно
+func inFilter(column string, values []string) (string, []interface{}) {
+	if values == nil {
+		return "", nil
+	}
+	if len(values) == 0 {
+		return " AND 0", nil
+	}
+
+	args := make([]interface{}, len(values))
+	for i, value := range values {
+		args[i] = value
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(values)), ", ")
+	return " AND " + column + " IN (" + placeholders + ")", args
+}
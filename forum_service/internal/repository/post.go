@@ -5,13 +5,18 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/kprf42/dolgova/forum_service/internal/entity"
+	"github.com/kprf42/dolgova/forum_service/internal/tenant"
 	"github.com/kprf42/dolgova/pkg/logger"
 	_ "github.com/mattn/go-sqlite3"
 )
 
+// ErrSlugTaken slug уже занят другим постом сообщества
+var ErrSlugTaken = errors.New("post slug is taken")
+
 type PostRepository struct {
 	db  *sql.DB
 	log *logger.Logger
@@ -25,26 +30,40 @@ func NewPostRepository(db *sql.DB, log *logger.Logger) *PostRepository {
 }
 
 func (r *PostRepository) Create(ctx context.Context, post *entity.Post) error {
-	r.log.Info("Creating new post",
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	r.log.ForContext(ctx).Info("Creating new post",
 		logger.String("post_id", post.ID),
 		logger.String("title", post.Title),
 		logger.String("author_id", post.AuthorID),
 		logger.String("category_id", post.CategoryID))
 
-	query := `INSERT INTO posts (id, title, content, author_id, category_id, is_pinned, created_at) 
-	          VALUES (?, ?, ?, ?, ?, ?, ?)`
+	status := post.Status
+	if status == "" {
+		status = entity.StatusPublished
+	}
+
+	query := `INSERT INTO posts (id, slug, title, content, author_id, category_id, is_pinned, created_at, tenant_id, status) 
+	          VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 
 	result, err := r.db.ExecContext(ctx, query,
 		post.ID,
+		post.Slug,
 		post.Title,
 		post.Content,
 		post.AuthorID,
 		post.CategoryID,
 		post.IsPinned,
 		post.CreatedAt.Format(time.RFC3339),
+		tenant.FromContext(ctx),
+		status,
 	)
 	if err != nil {
-		r.log.Error("Failed to create post",
+		if isSlugViolation(err) {
+			return ErrSlugTaken
+		}
+		r.log.ForContext(ctx).Error("Failed to create post",
 			logger.String("post_id", post.ID),
 			logger.Error(err))
 		return fmt.Errorf("failed to create post: %w", err)
@@ -52,50 +71,58 @@ func (r *PostRepository) Create(ctx context.Context, post *entity.Post) error {
 
 	rows, err := result.RowsAffected()
 	if err != nil {
-		r.log.Error("Failed to get rows affected",
+		r.log.ForContext(ctx).Error("Failed to get rows affected",
 			logger.String("post_id", post.ID),
 			logger.Error(err))
 		return fmt.Errorf("failed to get rows affected: %w", err)
 	}
 
 	if rows == 0 {
-		r.log.Error("No rows affected when creating post",
+		r.log.ForContext(ctx).Error("No rows affected when creating post",
 			logger.String("post_id", post.ID))
 		return fmt.Errorf("no rows affected when creating post")
 	}
 
-	r.log.Info("Successfully created post",
+	r.log.ForContext(ctx).Info("Successfully created post",
 		logger.String("post_id", post.ID))
 	return nil
 }
 
 func (r *PostRepository) GetByID(ctx context.Context, id string) (*entity.Post, error) {
-	r.log.Info("Getting post by ID",
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	r.log.ForContext(ctx).Info("Getting post by ID",
 		logger.String("post_id", id))
 
-	query := `SELECT id, title, content, author_id, category_id, is_pinned, created_at 
-	          FROM posts WHERE id = ?`
+	query := `SELECT id, slug, title, content, author_id, category_id, is_pinned, is_locked, score, accepted_comment_id, created_at, status 
+	          FROM posts WHERE id = ? AND tenant_id = ?`
 
 	var post entity.Post
 	var createdAt string
 
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
+	err := r.db.QueryRowContext(ctx, query, id, tenant.FromContext(ctx)).Scan(
 		&post.ID,
+		&post.Slug,
 		&post.Title,
 		&post.Content,
 		&post.AuthorID,
 		&post.CategoryID,
 		&post.IsPinned,
+		&post.IsLocked,
+		&post.Score,
+		&post.AnswerID,
 		&createdAt,
+		&post.Status,
 	)
 
 	if errors.Is(err, sql.ErrNoRows) {
-		r.log.Warn("Post not found",
+		r.log.ForContext(ctx).Warn("Post not found",
 			logger.String("post_id", id))
 		return nil, fmt.Errorf("post not found")
 	}
 	if err != nil {
-		r.log.Error("Failed to get post",
+		r.log.ForContext(ctx).Error("Failed to get post",
 			logger.String("post_id", id),
 			logger.Error(err))
 		return nil, err
@@ -103,43 +130,102 @@ func (r *PostRepository) GetByID(ctx context.Context, id string) (*entity.Post,
 
 	post.CreatedAt, err = time.Parse(time.RFC3339, createdAt)
 	if err != nil {
-		r.log.Error("Failed to parse created_at",
+		r.log.ForContext(ctx).Error("Failed to parse created_at",
 			logger.String("post_id", id),
 			logger.String("created_at", createdAt),
 			logger.Error(err))
 		return nil, fmt.Errorf("failed to parse created_at: %w", err)
 	}
 
-	r.log.Info("Successfully got post",
+	r.log.ForContext(ctx).Info("Successfully got post",
 		logger.String("post_id", id))
 	return &post, nil
 }
 
-func (r *PostRepository) GetAll(ctx context.Context, limit, offset int, categoryID string) ([]*entity.Post, error) {
-	r.log.Info("Getting all posts",
-		logger.Int("limit", limit),
-		logger.Int("offset", offset),
-		logger.String("category_id", categoryID))
+// isSlugViolation нарушение уникальности slug в сообществе. Остальные нарушения
+// уникальности (например, повтор ID) не должны выглядеть как занятый slug.
+func isSlugViolation(err error) bool {
+	if !isUniqueViolation(err) {
+		return false
+	}
+	msg := err.Error()
+	return strings.Contains(msg, "idx_posts_tenant_slug") || strings.Contains(msg, "posts.tenant_id, posts.slug")
+}
 
-	var query string
-	var args []interface{}
+// IDBySlug возвращает ID поста по slug
+func (r *PostRepository) IDBySlug(ctx context.Context, slug string) (string, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 
-	if categoryID != "" {
-		query = `SELECT id, title, content, author_id, category_id, is_pinned, created_at 
-		         FROM posts WHERE category_id = ? ORDER BY created_at DESC LIMIT ? OFFSET ?`
-		args = []interface{}{categoryID, limit, offset}
-	} else {
-		query = `SELECT id, title, content, author_id, category_id, is_pinned, created_at 
-		         FROM posts ORDER BY created_at DESC LIMIT ? OFFSET ?`
-		args = []interface{}{limit, offset}
+	var id string
+	err := r.db.QueryRowContext(ctx, `SELECT id FROM posts WHERE tenant_id = ? AND slug = ? AND slug <> ''`,
+		tenant.FromContext(ctx), slug).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", fmt.Errorf("post not found")
+	}
+	if err != nil {
+		r.log.ForContext(ctx).Error("Failed to get post by slug",
+			logger.String("slug", slug),
+			logger.Error(err))
+		return "", err
+	}
+	return id, nil
+}
+
+// hotRank ранг поста для сортировки hot: (score + 1) / (age + 2)^2, где age - возраст поста в часах.
+// Формула Hacker News со степенью 2 вместо 1.8: в SQLite без math-функций нет pow.
+// Новый пост без голосов поднимается над старыми, но через сутки его обходят посты с десятком голосов.
+const hotRank = `((score + 1) / (((julianday('now') - julianday(created_at)) * 24 + 2) * ((julianday('now') - julianday(created_at)) * 24 + 2)))`
+
+// GetAll возвращает опубликованные посты: закрепленные первыми, затем в порядке filter.Sort (по умолчанию от новых к старым).
+// Если задан after, выборка начинается после курсора (keyset, только для порядка по дате), иначе используется offset.
+func (r *PostRepository) GetAll(ctx context.Context, limit, offset int, after *entity.PageCursor, filter entity.PostFilter) ([]*entity.Post, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	r.log.ForContext(ctx).Info("Getting all posts",
+		logger.Int("limit", limit),
+		logger.Int("offset", offset),
+		logger.String("category_id", filter.CategoryID),
+		logger.Bool("include_subcategories", filter.IncludeSubcategories))
+
+	query := `SELECT id, slug, title, content, author_id, category_id, is_pinned, is_locked, score, accepted_comment_id, created_at 
+	          FROM posts WHERE tenant_id = ? AND status = 'published'`
+	args := []interface{}{tenant.FromContext(ctx)}
+
+	conditions, conditionArgs := postListFilter(ctx, filter)
+	query += conditions
+	args = append(args, conditionArgs...)
+	blocked, blockedArgs := blockedFilter(ctx, "author_id")
+	query += blocked
+	args = append(args, blockedArgs...)
+	order := `created_at DESC, id DESC`
+	switch filter.Sort {
+	case entity.PostSortTop, entity.PostSortScore:
+		order = `score DESC, ` + order
+	case entity.PostSortHot:
+		order = hotRank + ` DESC, ` + order
 	}
+	keyset := after != nil && (filter.Sort == "" || filter.Sort == entity.PostSortNew)
+	if keyset {
+		createdAt := after.CreatedAt.Format(time.RFC3339)
+		query += ` AND (created_at < ? OR (created_at = ? AND id < ?))`
+		args = append(args, createdAt, createdAt, after.ID)
+	}
+	// Закрепленные посты идут первыми. При обходе по курсору порядок только по дате на всех
+	// страницах, включая первую, иначе курсор (created_at, id) не определял бы позицию в списке.
+	if !keyset && !filter.Chronological {
+		order = `is_pinned DESC, ` + order
+	}
+	query += ` ORDER BY ` + order + ` LIMIT ? OFFSET ?`
+	args = append(args, limit, offset)
 
 	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
-		r.log.Error("Failed to get posts",
+		r.log.ForContext(ctx).Error("Failed to get posts",
 			logger.Int("limit", limit),
 			logger.Int("offset", offset),
-			logger.String("category_id", categoryID),
+			logger.String("category_id", filter.CategoryID),
 			logger.Error(err))
 		return nil, err
 	}
@@ -152,42 +238,50 @@ func (r *PostRepository) GetAll(ctx context.Context, limit, offset int, category
 
 		if err := rows.Scan(
 			&post.ID,
+			&post.Slug,
 			&post.Title,
 			&post.Content,
 			&post.AuthorID,
 			&post.CategoryID,
 			&post.IsPinned,
+			&post.IsLocked,
+			&post.Score,
+			&post.AnswerID,
 			&createdAt,
 		); err != nil {
-			r.log.Error("Failed to scan post row",
+			r.log.ForContext(ctx).Error("Failed to scan post row",
 				logger.Error(err))
 			return nil, err
 		}
 
 		post.CreatedAt, err = time.Parse(time.RFC3339, createdAt)
 		if err != nil {
-			r.log.Error("Failed to parse created_at",
+			r.log.ForContext(ctx).Error("Failed to parse created_at",
 				logger.String("created_at", createdAt),
 				logger.Error(err))
 			return nil, fmt.Errorf("failed to parse created_at: %w", err)
 		}
+		post.Status = entity.StatusPublished
 
 		posts = append(posts, &post)
 	}
 
-	r.log.Info("Successfully got posts",
+	r.log.ForContext(ctx).Info("Successfully got posts",
 		logger.Int("count", len(posts)))
 	return posts, nil
 }
 
 func (r *PostRepository) Update(ctx context.Context, id string, post *entity.PostUpdate) error {
-	r.log.Info("Updating post",
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	r.log.ForContext(ctx).Info("Updating post",
 		logger.String("post_id", id))
 
-	query := `UPDATE posts SET title = ?, content = ? WHERE id = ?`
-	result, err := r.db.ExecContext(ctx, query, post.Title, post.Content, id)
+	query := `UPDATE posts SET title = ?, content = ? WHERE id = ? AND tenant_id = ?`
+	result, err := r.db.ExecContext(ctx, query, post.Title, post.Content, id, tenant.FromContext(ctx))
 	if err != nil {
-		r.log.Error("Failed to update post",
+		r.log.ForContext(ctx).Error("Failed to update post",
 			logger.String("post_id", id),
 			logger.Error(err))
 		return err
@@ -195,17 +289,17 @@ func (r *PostRepository) Update(ctx context.Context, id string, post *entity.Pos
 
 	rows, err := result.RowsAffected()
 	if err != nil {
-		r.log.Error("Failed to get rows affected",
+		r.log.ForContext(ctx).Error("Failed to get rows affected",
 			logger.String("post_id", id),
 			logger.Error(err))
 		return err
 	}
 
 	if rows == 0 {
-		r.log.Warn("No rows affected when updating post",
+		r.log.ForContext(ctx).Warn("No rows affected when updating post",
 			logger.String("post_id", id))
 	} else {
-		r.log.Info("Successfully updated post",
+		r.log.ForContext(ctx).Info("Successfully updated post",
 			logger.String("post_id", id))
 	}
 
@@ -213,13 +307,16 @@ func (r *PostRepository) Update(ctx context.Context, id string, post *entity.Pos
 }
 
 func (r *PostRepository) Delete(ctx context.Context, id string) error {
-	r.log.Info("Deleting post",
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	r.log.ForContext(ctx).Info("Deleting post",
 		logger.String("post_id", id))
 
-	query := `DELETE FROM posts WHERE id = ?`
-	result, err := r.db.ExecContext(ctx, query, id)
+	query := `DELETE FROM posts WHERE id = ? AND tenant_id = ?`
+	result, err := r.db.ExecContext(ctx, query, id, tenant.FromContext(ctx))
 	if err != nil {
-		r.log.Error("Failed to delete post",
+		r.log.ForContext(ctx).Error("Failed to delete post",
 			logger.String("post_id", id),
 			logger.Error(err))
 		return err
@@ -227,48 +324,306 @@ func (r *PostRepository) Delete(ctx context.Context, id string) error {
 
 	rows, err := result.RowsAffected()
 	if err != nil {
-		r.log.Error("Failed to get rows affected",
+		r.log.ForContext(ctx).Error("Failed to get rows affected",
 			logger.String("post_id", id),
 			logger.Error(err))
 		return err
 	}
 
 	if rows == 0 {
-		r.log.Warn("No rows affected when deleting post",
+		r.log.ForContext(ctx).Warn("No rows affected when deleting post",
 			logger.String("post_id", id))
 	} else {
-		r.log.Info("Successfully deleted post",
+		r.log.ForContext(ctx).Info("Successfully deleted post",
 			logger.String("post_id", id))
 	}
 
 	return nil
 }
 
-func (r *PostRepository) Count(ctx context.Context, categoryID string) (int, error) {
-	r.log.Info("Counting posts",
-		logger.String("category_id", categoryID))
+func (r *PostRepository) Count(ctx context.Context, filter entity.PostFilter) (int, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 
-	var query string
-	var args []interface{}
+	r.log.ForContext(ctx).Info("Counting posts",
+		logger.String("category_id", filter.CategoryID))
 
-	if categoryID != "" {
-		query = `SELECT COUNT(*) FROM posts WHERE category_id = ?`
-		args = []interface{}{categoryID}
-	} else {
-		query = `SELECT COUNT(*) FROM posts`
-	}
+	query := `SELECT COUNT(*) FROM posts WHERE tenant_id = ? AND status = 'published'`
+	args := []interface{}{tenant.FromContext(ctx)}
+
+	conditions, conditionArgs := postListFilter(ctx, filter)
+	query += conditions
+	args = append(args, conditionArgs...)
+	blocked, blockedArgs := blockedFilter(ctx, "author_id")
+	query += blocked
+	args = append(args, blockedArgs...)
 
 	var count int
 	err := r.db.QueryRowContext(ctx, query, args...).Scan(&count)
 	if err != nil {
-		r.log.Error("Failed to count posts",
-			logger.String("category_id", categoryID),
+		r.log.ForContext(ctx).Error("Failed to count posts",
+			logger.String("category_id", filter.CategoryID),
 			logger.Error(err))
 		return 0, err
 	}
 
-	r.log.Info("Successfully counted posts",
+	r.log.ForContext(ctx).Info("Successfully counted posts",
 		logger.Int("count", count),
-		logger.String("category_id", categoryID))
+		logger.String("category_id", filter.CategoryID))
 	return count, nil
 }
+
+// GetByAuthor возвращает опубликованные посты автора от новых к старым, без учета закрепления
+func (r *PostRepository) GetByAuthor(ctx context.Context, authorID string, limit, offset int) ([]*entity.Post, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	r.log.ForContext(ctx).Info("Getting posts by author",
+		logger.String("author_id", authorID),
+		logger.Int("limit", limit),
+		logger.Int("offset", offset))
+
+	query := `SELECT id, slug, title, content, author_id, category_id, is_pinned, is_locked, score, accepted_comment_id, created_at 
+	          FROM posts WHERE tenant_id = ? AND author_id = ? AND status = 'published'
+	          ORDER BY created_at DESC, id DESC LIMIT ? OFFSET ?`
+	rows, err := r.db.QueryContext(ctx, query, tenant.FromContext(ctx), authorID, limit, offset)
+	if err != nil {
+		r.log.ForContext(ctx).Error("Failed to get posts by author",
+			logger.String("author_id", authorID),
+			logger.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var posts []*entity.Post
+	for rows.Next() {
+		var post entity.Post
+		var createdAt string
+
+		if err := rows.Scan(
+			&post.ID,
+			&post.Slug,
+			&post.Title,
+			&post.Content,
+			&post.AuthorID,
+			&post.CategoryID,
+			&post.IsPinned,
+			&post.IsLocked,
+			&post.Score,
+			&post.AnswerID,
+			&createdAt,
+		); err != nil {
+			r.log.ForContext(ctx).Error("Failed to scan post row",
+				logger.Error(err))
+			return nil, err
+		}
+
+		post.CreatedAt, err = time.Parse(time.RFC3339, createdAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse created_at: %w", err)
+		}
+		post.Status = entity.StatusPublished
+
+		posts = append(posts, &post)
+	}
+	return posts, rows.Err()
+}
+
+// CountByAuthor возвращает число опубликованных постов автора
+func (r *PostRepository) CountByAuthor(ctx context.Context, authorID string) (int, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	var count int
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM posts WHERE tenant_id = ? AND author_id = ? AND status = 'published'`,
+		tenant.FromContext(ctx), authorID).Scan(&count)
+	if err != nil {
+		r.log.ForContext(ctx).Error("Failed to count posts by author",
+			logger.String("author_id", authorID),
+			logger.Error(err))
+		return 0, err
+	}
+	return count, nil
+}
+
+// postListFilter условия списка постов по фильтру: категория и период
+func postListFilter(ctx context.Context, filter entity.PostFilter) (string, []interface{}) {
+	var query string
+	var args []interface{}
+	switch {
+	case filter.CategoryID == "":
+	case filter.IncludeSubcategories:
+		query, args = subcategoriesFilter(ctx, "category_id", filter.CategoryID)
+	default:
+		query, args = ` AND category_id = ?`, []interface{}{filter.CategoryID}
+	}
+	// created_at хранится со смещением сервера, поэтому сравнение по julianday, а не строкой
+	if !filter.Since.IsZero() {
+		query += ` AND julianday(created_at) >= julianday(?)`
+		args = append(args, formatUTC(filter.Since))
+	}
+	return query, args
+}
+
+// SetPinned закрепляет или открепляет пост
+func (r *PostRepository) SetPinned(ctx context.Context, id string, pinned bool) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	r.log.ForContext(ctx).Info("Setting post pinned",
+		logger.String("post_id", id),
+		logger.Bool("pinned", pinned))
+
+	result, err := r.db.ExecContext(ctx, `UPDATE posts SET is_pinned = ? WHERE id = ? AND tenant_id = ?`,
+		pinned, id, tenant.FromContext(ctx))
+	if err != nil {
+		r.log.ForContext(ctx).Error("Failed to set post pinned",
+			logger.String("post_id", id),
+			logger.Error(err))
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return fmt.Errorf("post not found")
+	}
+	return nil
+}
+
+// SetLocked закрывает тему поста для новых комментариев или открывает ее
+func (r *PostRepository) SetLocked(ctx context.Context, id string, locked bool) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	r.log.ForContext(ctx).Info("Setting post locked",
+		logger.String("post_id", id),
+		logger.Bool("locked", locked))
+
+	result, err := r.db.ExecContext(ctx, `UPDATE posts SET is_locked = ? WHERE id = ? AND tenant_id = ?`,
+		locked, id, tenant.FromContext(ctx))
+	if err != nil {
+		r.log.ForContext(ctx).Error("Failed to set post locked",
+			logger.String("post_id", id),
+			logger.Error(err))
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return fmt.Errorf("post not found")
+	}
+	return nil
+}
+
+// SetAcceptedAnswer отмечает комментарий commentID принятым ответом на пост; пустой commentID снимает отметку
+func (r *PostRepository) SetAcceptedAnswer(ctx context.Context, postID, commentID string) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	r.log.ForContext(ctx).Info("Setting accepted answer",
+		logger.String("post_id", postID),
+		logger.String("comment_id", commentID))
+
+	result, err := r.db.ExecContext(ctx, `UPDATE posts SET accepted_comment_id = ? WHERE id = ? AND tenant_id = ?`,
+		commentID, postID, tenant.FromContext(ctx))
+	if err != nil {
+		r.log.ForContext(ctx).Error("Failed to set accepted answer",
+			logger.String("post_id", postID),
+			logger.Error(err))
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return fmt.Errorf("post not found")
+	}
+	return nil
+}
+
+// Vote сохраняет голос userID за пост: value 1 или -1, 0 удаляет голос. Повторный такой же голос
+// ничего не меняет, другой заменяет прежний; счет поста меняется на разницу. Возвращает новый счет.
+func (r *PostRepository) Vote(ctx context.Context, postID, userID string, value int) (int, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	r.log.ForContext(ctx).Info("Voting for post",
+		logger.String("post_id", postID),
+		logger.String("user_id", userID),
+		logger.Int("value", value))
+
+	tenantID := tenant.FromContext(ctx)
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		r.log.ForContext(ctx).Error("Failed to begin vote transaction",
+			logger.Error(err))
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var old int
+	err = tx.QueryRowContext(ctx,
+		`SELECT value FROM post_votes WHERE tenant_id = ? AND post_id = ? AND user_id = ?`,
+		tenantID, postID, userID,
+	).Scan(&old)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		r.log.ForContext(ctx).Error("Failed to get post vote",
+			logger.String("post_id", postID),
+			logger.Error(err))
+		return 0, err
+	}
+
+	if old != value {
+		now := time.Now().UTC().Format(time.RFC3339)
+		if value == 0 {
+			_, err = tx.ExecContext(ctx,
+				`DELETE FROM post_votes WHERE tenant_id = ? AND post_id = ? AND user_id = ?`,
+				tenantID, postID, userID)
+		} else {
+			_, err = tx.ExecContext(ctx,
+				`INSERT INTO post_votes (tenant_id, post_id, user_id, value, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?)
+				 ON CONFLICT(tenant_id, post_id, user_id) DO UPDATE SET value = excluded.value, updated_at = excluded.updated_at`,
+				tenantID, postID, userID, value, now, now)
+		}
+		if err != nil {
+			r.log.ForContext(ctx).Error("Failed to save post vote",
+				logger.String("post_id", postID),
+				logger.Error(err))
+			return 0, err
+		}
+
+		_, err = tx.ExecContext(ctx, `UPDATE posts SET score = score + ? WHERE tenant_id = ? AND id = ?`,
+			value-old, tenantID, postID)
+		if err != nil {
+			r.log.ForContext(ctx).Error("Failed to update post score",
+				logger.String("post_id", postID),
+				logger.Error(err))
+			return 0, err
+		}
+	}
+
+	var score int
+	err = tx.QueryRowContext(ctx, `SELECT score FROM posts WHERE tenant_id = ? AND id = ?`, tenantID, postID).Scan(&score)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, fmt.Errorf("post not found")
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		r.log.ForContext(ctx).Error("Failed to commit vote transaction",
+			logger.String("post_id", postID),
+			logger.Error(err))
+		return 0, err
+	}
+	return score, nil
+}
@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kprf42/dolgova/forum_service/internal/entity"
+	"github.com/kprf42/dolgova/forum_service/internal/tenant"
+	"github.com/kprf42/dolgova/pkg/logger"
+)
+
+// engagement активность поста с момента since: просмотр - 1 очко, комментарий - 4,
+// голос за - 2 (против - минус 2). Голос учитывается по времени последнего изменения.
+// Комментарии и голоса хранят время с локальным смещением, поэтому сравниваются через julianday.
+const engagement = `COALESCE((SELECT SUM(views) FROM post_views v
+	                          WHERE v.tenant_id = p.tenant_id AND v.post_id = p.id AND v.hour >= ?), 0)
+	+ 4 * (SELECT COUNT(*) FROM comments c
+	       WHERE c.tenant_id = p.tenant_id AND c.post_id = p.id AND c.status = 'published'
+	         AND julianday(c.created_at) >= julianday(?))
+	+ 2 * COALESCE((SELECT SUM(value) FROM post_votes pv
+	                WHERE pv.tenant_id = p.tenant_id AND pv.post_id = p.id
+	                  AND julianday(pv.updated_at) >= julianday(?)), 0)`
+
+// AddView увеличивает счетчик просмотров поста за час, в который попадает at
+func (r *PostRepository) AddView(ctx context.Context, postID string, at time.Time) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO post_views (tenant_id, post_id, hour, views) VALUES (?, ?, ?, 1)
+		 ON CONFLICT(tenant_id, post_id, hour) DO UPDATE SET views = views + 1`,
+		tenant.FromContext(ctx), postID, formatUTC(at.Truncate(time.Hour)))
+	if err != nil {
+		r.log.ForContext(ctx).Error("Failed to add post view",
+			logger.String("post_id", postID),
+			logger.Error(err))
+		return err
+	}
+	return nil
+}
+
+// DeleteViewsBefore удаляет счетчики просмотров за часы до before во всех сообществах
+func (r *PostRepository) DeleteViewsBefore(ctx context.Context, before time.Time) (int64, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx, `DELETE FROM post_views WHERE hour < ?`, formatUTC(before))
+	if err != nil {
+		r.log.ForContext(ctx).Error("Failed to delete old post views",
+			logger.Error(err))
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// Trending возвращает до limit опубликованных постов с наибольшей активностью с момента since.
+// Посты без активности в выборку не попадают.
+func (r *PostRepository) Trending(ctx context.Context, since time.Time, limit int) ([]*entity.Post, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	r.log.ForContext(ctx).Info("Getting trending posts",
+		logger.String("since", formatUTC(since)),
+		logger.Int("limit", limit))
+
+	query := `SELECT id, slug, title, content, author_id, category_id, is_pinned, is_locked, score, accepted_comment_id, created_at
+	          FROM (SELECT p.*, ` + engagement + ` AS engagement
+	                FROM posts p WHERE p.tenant_id = ? AND p.status = 'published')
+	          WHERE engagement > 0
+	          ORDER BY engagement DESC, created_at DESC, id DESC LIMIT ?`
+	from := formatUTC(since)
+	rows, err := r.db.QueryContext(ctx, query, from, from, from, tenant.FromContext(ctx), limit)
+	if err != nil {
+		r.log.ForContext(ctx).Error("Failed to get trending posts",
+			logger.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var posts []*entity.Post
+	for rows.Next() {
+		var post entity.Post
+		var createdAt string
+
+		if err := rows.Scan(
+			&post.ID,
+			&post.Slug,
+			&post.Title,
+			&post.Content,
+			&post.AuthorID,
+			&post.CategoryID,
+			&post.IsPinned,
+			&post.IsLocked,
+			&post.Score,
+			&post.AnswerID,
+			&createdAt,
+		); err != nil {
+			r.log.ForContext(ctx).Error("Failed to scan post row",
+				logger.Error(err))
+			return nil, err
+		}
+
+		post.CreatedAt, err = time.Parse(time.RFC3339, createdAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse created_at: %w", err)
+		}
+		post.Status = entity.StatusPublished
+
+		posts = append(posts, &post)
+	}
+	return posts, rows.Err()
+}
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/kprf42/dolgova/forum_service/internal/entity"
+	"github.com/kprf42/dolgova/pkg/logger"
+)
+
+// ProfileRepository хранит профили пользователей форума
+type ProfileRepository struct {
+	db  *sql.DB
+	log *logger.Logger
+}
+
+func NewProfileRepository(db *sql.DB, log *logger.Logger) *ProfileRepository {
+	return &ProfileRepository{
+		db:  db,
+		log: log,
+	}
+}
+
+// Get возвращает профиль пользователя. Если профиль еще не заполнялся, возвращается пустой профиль.
+func (r *ProfileRepository) Get(ctx context.Context, userID string) (*entity.Profile, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	profile := &entity.Profile{UserID: userID}
+	var avatars, updatedAt string
+	err := r.db.QueryRowContext(ctx,
+		`SELECT avatar_url, avatars, updated_at FROM user_profiles WHERE user_id = ?`, userID,
+	).Scan(&profile.AvatarURL, &avatars, &updatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return profile, nil
+	}
+	if err != nil {
+		r.log.ForContext(ctx).Error("Failed to get profile",
+			logger.String("user_id", userID),
+			logger.Error(err))
+		return nil, err
+	}
+
+	if err := json.Unmarshal([]byte(avatars), &profile.Avatars); err != nil {
+		return nil, fmt.Errorf("failed to parse avatars: %w", err)
+	}
+	if len(profile.Avatars) == 0 {
+		profile.Avatars = nil
+	}
+	profile.UpdatedAt, err = time.Parse(time.RFC3339, updatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse updated_at: %w", err)
+	}
+	return profile, nil
+}
+
+// SetAvatar сохраняет адреса аватара, создавая профиль при необходимости
+func (r *ProfileRepository) SetAvatar(ctx context.Context, profile *entity.Profile) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	r.log.ForContext(ctx).Info("Updating avatar",
+		logger.String("user_id", profile.UserID))
+
+	avatars, err := json.Marshal(profile.Avatars)
+	if err != nil || profile.Avatars == nil {
+		avatars = []byte("{}")
+	}
+
+	query := `INSERT INTO user_profiles (user_id, avatar_url, avatars, updated_at) VALUES (?, ?, ?, ?)
+	          ON CONFLICT(user_id) DO UPDATE SET avatar_url = excluded.avatar_url,
+	          avatars = excluded.avatars, updated_at = excluded.updated_at`
+	_, err = r.db.ExecContext(ctx, query,
+		profile.UserID,
+		profile.AvatarURL,
+		string(avatars),
+		profile.UpdatedAt.Format(time.RFC3339),
+	)
+	if err != nil {
+		r.log.ForContext(ctx).Error("Failed to update avatar",
+			logger.String("user_id", profile.UserID),
+			logger.Error(err))
+		return err
+	}
+
+	r.log.ForContext(ctx).Info("Successfully updated avatar",
+		logger.String("user_id", profile.UserID))
+	return nil
+}
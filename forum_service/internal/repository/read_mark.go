@@ -0,0 +1,181 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/kprf42/dolgova/forum_service/internal/entity"
+	"github.com/kprf42/dolgova/forum_service/internal/tenant"
+	"github.com/kprf42/dolgova/pkg/logger"
+)
+
+// ReadMarkRepository хранит отметки о прочтении постов и категорий.
+// Даты сравниваются через julianday, т.к. created_at постов и комментариев записаны с разными смещениями.
+type ReadMarkRepository struct {
+	db  *sql.DB
+	log *logger.Logger
+}
+
+func NewReadMarkRepository(db *sql.DB, log *logger.Logger) *ReadMarkRepository {
+	return &ReadMarkRepository{
+		db:  db,
+		log: log,
+	}
+}
+
+// MarkPost отмечает пост прочитанным пользователем на момент at
+func (r *ReadMarkRepository) MarkPost(ctx context.Context, userID, postID string, at time.Time) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	r.log.ForContext(ctx).Info("Marking post as read",
+		logger.String("user_id", userID),
+		logger.String("post_id", postID))
+
+	query := `INSERT INTO post_reads (user_id, tenant_id, post_id, last_seen_at) VALUES (?, ?, ?, ?)
+	          ON CONFLICT(user_id, tenant_id, post_id) DO UPDATE SET last_seen_at = excluded.last_seen_at`
+	_, err := r.db.ExecContext(ctx, query, userID, tenant.FromContext(ctx), postID, at.UTC().Format(time.RFC3339))
+	if err != nil {
+		r.log.ForContext(ctx).Error("Failed to mark post as read",
+			logger.String("user_id", userID),
+			logger.String("post_id", postID),
+			logger.Error(err))
+		return err
+	}
+	return nil
+}
+
+// MarkCategory отмечает прочитанными все посты категории на момент at
+func (r *ReadMarkRepository) MarkCategory(ctx context.Context, userID, categoryID string, at time.Time) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	r.log.ForContext(ctx).Info("Marking category as read",
+		logger.String("user_id", userID),
+		logger.String("category_id", categoryID))
+
+	query := `INSERT INTO category_reads (user_id, tenant_id, category_id, last_seen_at) VALUES (?, ?, ?, ?)
+	          ON CONFLICT(user_id, tenant_id, category_id) DO UPDATE SET last_seen_at = excluded.last_seen_at`
+	_, err := r.db.ExecContext(ctx, query, userID, tenant.FromContext(ctx), categoryID, at.UTC().Format(time.RFC3339))
+	if err != nil {
+		r.log.ForContext(ctx).Error("Failed to mark category as read",
+			logger.String("user_id", userID),
+			logger.String("category_id", categoryID),
+			logger.Error(err))
+		return err
+	}
+	return nil
+}
+
+// Unread возвращает посты из postIDs, непрочитанные пользователем: пост или комментарий другого
+// пользователя появился после отметки поста и отметки его категории
+func (r *ReadMarkRepository) Unread(ctx context.Context, userID string, postIDs []string) (map[string]bool, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	filter, filterArgs := inFilter("p.id", postIDs)
+	blocked, blockedArgs := blockedFilter(ctx, "c.author_id")
+
+	query := `SELECT p.id FROM posts p
+	          LEFT JOIN post_reads pr ON pr.user_id = ? AND pr.tenant_id = p.tenant_id AND pr.post_id = p.id
+	          LEFT JOIN category_reads cr ON cr.user_id = ? AND cr.tenant_id = p.tenant_id AND cr.category_id = p.category_id
+	          WHERE p.tenant_id = ?` + filter + `
+	          AND MAX(
+	              CASE WHEN p.author_id = ? THEN 0 ELSE julianday(p.created_at) END,
+	              COALESCE((SELECT MAX(julianday(c.created_at)) FROM comments c
+	                        WHERE c.tenant_id = p.tenant_id AND c.post_id = p.id AND c.status = 'published'
+	                        AND c.author_id != ?` + blocked + `), 0)
+	          ) > MAX(COALESCE(julianday(pr.last_seen_at), 0), COALESCE(julianday(cr.last_seen_at), 0))`
+
+	args := []interface{}{userID, userID, tenant.FromContext(ctx)}
+	args = append(args, filterArgs...)
+	args = append(args, userID, userID)
+	args = append(args, blockedArgs...)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		r.log.ForContext(ctx).Error("Failed to get unread posts",
+			logger.String("user_id", userID),
+			logger.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	unread := make(map[string]bool, len(postIDs))
+	for rows.Next() {
+		var postID string
+		if err := rows.Scan(&postID); err != nil {
+			return nil, err
+		}
+		unread[postID] = true
+	}
+	return unread, rows.Err()
+}
+
+// Threads возвращает посты, которые пользователь уже открывал и в которых после этого
+// появились комментарии других пользователей, начиная с самых свежих
+func (r *ReadMarkRepository) Threads(ctx context.Context, userID string, limit, offset int) ([]*entity.UnreadThread, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	r.log.ForContext(ctx).Info("Getting unread threads",
+		logger.String("user_id", userID),
+		logger.Int("limit", limit),
+		logger.Int("offset", offset))
+
+	blocked, blockedArgs := blockedFilter(ctx, "c.author_id")
+
+	query := `WITH seen AS (
+	              SELECT p.id, p.title, p.category_id, p.tenant_id,
+	                     MAX(pr.last_seen_at, COALESCE(cr.last_seen_at, pr.last_seen_at)) AS last_seen_at
+	              FROM post_reads pr
+	              JOIN posts p ON p.tenant_id = pr.tenant_id AND p.id = pr.post_id AND p.status = 'published'
+	              LEFT JOIN category_reads cr ON cr.user_id = pr.user_id AND cr.tenant_id = pr.tenant_id AND cr.category_id = p.category_id
+	              WHERE pr.user_id = ? AND pr.tenant_id = ?
+	          )
+	          SELECT s.id, s.title, s.category_id, s.last_seen_at, COUNT(c.id),
+	                 strftime('%Y-%m-%dT%H:%M:%SZ', MAX(julianday(c.created_at)))
+	          FROM seen s
+	          JOIN comments c ON c.tenant_id = s.tenant_id AND c.post_id = s.id AND c.status = 'published'
+	              AND c.author_id != ? AND julianday(c.created_at) > julianday(s.last_seen_at)` + blocked + `
+	          GROUP BY s.id
+	          ORDER BY MAX(julianday(c.created_at)) DESC, s.id
+	          LIMIT ? OFFSET ?`
+
+	args := []interface{}{userID, tenant.FromContext(ctx), userID}
+	args = append(args, blockedArgs...)
+	args = append(args, limit, offset)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		r.log.ForContext(ctx).Error("Failed to get unread threads",
+			logger.String("user_id", userID),
+			logger.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	threads := []*entity.UnreadThread{}
+	for rows.Next() {
+		var t entity.UnreadThread
+		var lastSeenAt, lastActivityAt string
+		if err := rows.Scan(&t.PostID, &t.Title, &t.CategoryID, &lastSeenAt, &t.NewComments, &lastActivityAt); err != nil {
+			r.log.ForContext(ctx).Error("Failed to scan unread thread row",
+				logger.Error(err))
+			return nil, err
+		}
+
+		if t.LastSeenAt, err = time.Parse(time.RFC3339, lastSeenAt); err != nil {
+			return nil, fmt.Errorf("failed to parse last_seen_at: %w", err)
+		}
+		if t.LastActivityAt, err = time.Parse(time.RFC3339, lastActivityAt); err != nil {
+			return nil, fmt.Errorf("failed to parse last activity: %w", err)
+		}
+
+		threads = append(threads, &t)
+	}
+
+	return threads, rows.Err()
+}
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/kprf42/dolgova/forum_service/internal/entity"
+	"github.com/kprf42/dolgova/forum_service/internal/tenant"
+	"github.com/kprf42/dolgova/pkg/logger"
+)
+
+// ErrAlreadyReported пользователь уже пожаловался на эту публикацию
+var ErrAlreadyReported = errors.New("already reported")
+
+// ReportRepository хранит жалобы на посты и комментарии
+type ReportRepository struct {
+	db  *sql.DB
+	log *logger.Logger
+}
+
+func NewReportRepository(db *sql.DB, log *logger.Logger) *ReportRepository {
+	return &ReportRepository{
+		db:  db,
+		log: log,
+	}
+}
+
+// Create сохраняет жалобу. Повторная жалоба пользователя на ту же публикацию не сохраняется
+// и возвращает ErrAlreadyReported.
+func (r *ReportRepository) Create(ctx context.Context, report *entity.Report) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	r.log.ForContext(ctx).Info("Creating report",
+		logger.String("report_id", report.ID),
+		logger.String("target_type", report.TargetType),
+		logger.String("target_id", report.TargetID),
+		logger.String("reporter_id", report.ReporterID))
+
+	query := `INSERT INTO reports (id, target_type, target_id, category_id, reporter_id, reason, details, created_at, tenant_id)
+	          VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	          ON CONFLICT (tenant_id, target_type, target_id, reporter_id) DO NOTHING`
+	result, err := r.db.ExecContext(ctx, query,
+		report.ID,
+		report.TargetType,
+		report.TargetID,
+		report.CategoryID,
+		report.ReporterID,
+		report.Reason,
+		report.Details,
+		formatUTC(report.CreatedAt),
+		tenant.FromContext(ctx),
+	)
+	if err != nil {
+		r.log.ForContext(ctx).Error("Failed to create report",
+			logger.String("report_id", report.ID),
+			logger.Error(err))
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		r.log.ForContext(ctx).Warn("Duplicate report",
+			logger.String("target_id", report.TargetID),
+			logger.String("reporter_id", report.ReporterID))
+		return ErrAlreadyReported
+	}
+	return nil
+}
+
+// List возвращает жалобы от новых к старым. categories ограничивает выборку категориями; nil - все категории.
+func (r *ReportRepository) List(ctx context.Context, categories []string, limit, offset int) ([]*entity.Report, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	filter, args := inFilter("category_id", categories)
+	query := `SELECT id, target_type, target_id, category_id, reporter_id, reason, details, created_at
+	          FROM reports WHERE tenant_id = ?` + filter + ` ORDER BY created_at DESC, id DESC LIMIT ? OFFSET ?`
+
+	args = append([]interface{}{tenant.FromContext(ctx)}, args...)
+	rows, err := r.db.QueryContext(ctx, query, append(args, limit, offset)...)
+	if err != nil {
+		r.log.ForContext(ctx).Error("Failed to get reports",
+			logger.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	reports := []*entity.Report{}
+	for rows.Next() {
+		var report entity.Report
+		var createdAt string
+		if err := rows.Scan(
+			&report.ID,
+			&report.TargetType,
+			&report.TargetID,
+			&report.CategoryID,
+			&report.ReporterID,
+			&report.Reason,
+			&report.Details,
+			&createdAt,
+		); err != nil {
+			r.log.ForContext(ctx).Error("Failed to scan report row",
+				logger.Error(err))
+			return nil, err
+		}
+
+		if report.CreatedAt, err = time.Parse(time.RFC3339, createdAt); err != nil {
+			return nil, fmt.Errorf("failed to parse created_at: %w", err)
+		}
+		reports = append(reports, &report)
+	}
+	return reports, rows.Err()
+}
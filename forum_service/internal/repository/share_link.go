@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/kprf42/dolgova/forum_service/internal/entity"
+	"github.com/kprf42/dolgova/forum_service/internal/tenant"
+	"github.com/kprf42/dolgova/pkg/logger"
+	"github.com/mattn/go-sqlite3"
+)
+
+var (
+	// ErrShareLinkNotFound короткая ссылка не найдена
+	ErrShareLinkNotFound = errors.New("share link not found")
+	// ErrShareCodeTaken код короткой ссылки уже занят другой ссылкой
+	ErrShareCodeTaken = errors.New("share code is taken")
+)
+
+// ShareLinkRepository хранит короткие ссылки на посты и счетчики переходов
+type ShareLinkRepository struct {
+	db  *sql.DB
+	log *logger.Logger
+}
+
+func NewShareLinkRepository(db *sql.DB, log *logger.Logger) *ShareLinkRepository {
+	return &ShareLinkRepository{
+		db:  db,
+		log: log,
+	}
+}
+
+// GetOrCreate возвращает ссылку пользователя на пост, создавая ее с кодом link.Code, если ее нет.
+// Если код занят ссылкой на другой пост, возвращает ErrShareCodeTaken.
+func (r *ShareLinkRepository) GetOrCreate(ctx context.Context, link *entity.ShareLink) (*entity.ShareLink, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	r.log.ForContext(ctx).Info("Creating share link",
+		logger.String("post_id", link.PostID),
+		logger.String("created_by", link.CreatedBy))
+
+	if link.CreatedAt.IsZero() {
+		link.CreatedAt = time.Now().UTC()
+	}
+
+	query := `INSERT INTO share_links (code, tenant_id, post_id, created_by, created_at) VALUES (?, ?, ?, ?, ?)
+	          ON CONFLICT(tenant_id, post_id, created_by) DO NOTHING`
+	_, err := r.db.ExecContext(ctx, query,
+		link.Code,
+		tenant.FromContext(ctx),
+		link.PostID,
+		link.CreatedBy,
+		link.CreatedAt.Format(time.RFC3339),
+	)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrShareCodeTaken
+		}
+		r.log.ForContext(ctx).Error("Failed to create share link",
+			logger.String("post_id", link.PostID),
+			logger.Error(err))
+		return nil, err
+	}
+
+	query = `SELECT code, post_id, created_by, clicks, created_at FROM share_links
+	         WHERE tenant_id = ? AND post_id = ? AND created_by = ?`
+	row := r.db.QueryRowContext(ctx, query, tenant.FromContext(ctx), link.PostID, link.CreatedBy)
+	saved, err := scanShareLink(row)
+	if err != nil {
+		r.log.ForContext(ctx).Error("Failed to get share link",
+			logger.String("post_id", link.PostID),
+			logger.Error(err))
+		return nil, err
+	}
+
+	r.log.ForContext(ctx).Info("Successfully created share link",
+		logger.String("post_id", saved.PostID),
+		logger.String("code", saved.Code))
+	return saved, nil
+}
+
+// Click увеличивает счетчик переходов по ссылке и возвращает ее
+func (r *ShareLinkRepository) Click(ctx context.Context, code string) (*entity.ShareLink, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	query := `UPDATE share_links SET clicks = clicks + 1 WHERE tenant_id = ? AND code = ?
+	          RETURNING code, post_id, created_by, clicks, created_at`
+	link, err := scanShareLink(r.db.QueryRowContext(ctx, query, tenant.FromContext(ctx), code))
+	if errors.Is(err, sql.ErrNoRows) {
+		r.log.ForContext(ctx).Warn("Share link not found",
+			logger.String("code", code))
+		return nil, ErrShareLinkNotFound
+	}
+	if err != nil {
+		r.log.ForContext(ctx).Error("Failed to count share link click",
+			logger.String("code", code),
+			logger.Error(err))
+		return nil, err
+	}
+
+	return link, nil
+}
+
+// ListByPost возвращает ссылки на пост, самые популярные первыми
+func (r *ShareLinkRepository) ListByPost(ctx context.Context, postID string) ([]*entity.ShareLink, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	r.log.ForContext(ctx).Info("Getting share links",
+		logger.String("post_id", postID))
+
+	query := `SELECT code, post_id, created_by, clicks, created_at FROM share_links
+	          WHERE tenant_id = ? AND post_id = ? ORDER BY clicks DESC, created_at`
+	rows, err := r.db.QueryContext(ctx, query, tenant.FromContext(ctx), postID)
+	if err != nil {
+		r.log.ForContext(ctx).Error("Failed to get share links",
+			logger.String("post_id", postID),
+			logger.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	links := []*entity.ShareLink{}
+	for rows.Next() {
+		link, err := scanShareLink(rows)
+		if err != nil {
+			r.log.ForContext(ctx).Error("Failed to scan share link row",
+				logger.Error(err))
+			return nil, err
+		}
+		links = append(links, link)
+	}
+
+	return links, rows.Err()
+}
+
+func scanShareLink(row interface{ Scan(...any) error }) (*entity.ShareLink, error) {
+	var link entity.ShareLink
+	var createdAt string
+	if err := row.Scan(&link.Code, &link.PostID, &link.CreatedBy, &link.Clicks, &createdAt); err != nil {
+		return nil, err
+	}
+
+	var err error
+	link.CreatedAt, err = time.Parse(time.RFC3339, createdAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse created_at: %w", err)
+	}
+	return &link, nil
+}
+
+// isUniqueViolation ошибка нарушения уникальности (в т.ч. первичного ключа)
+func isUniqueViolation(err error) bool {
+	var sqliteErr sqlite3.Error
+	return errors.As(err, &sqliteErr) &&
+		(sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique || sqliteErr.ExtendedCode == sqlite3.ErrConstraintPrimaryKey)
+}
@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/kprf42/dolgova/forum_service/internal/entity"
+	"github.com/kprf42/dolgova/pkg/logger"
+)
+
+// statsTables таблицы, по которым считается статистика (users принадлежит auth сервису, но лежит в общей БД)
+var statsTables = []string{"users", "posts", "comments", "chat_messages"}
+
+type StatsRepository struct {
+	db  *sql.DB
+	log *logger.Logger
+}
+
+func NewStatsRepository(db *sql.DB, log *logger.Logger) *StatsRepository {
+	return &StatsRepository{
+		db:  db,
+		log: log,
+	}
+}
+
+func (r *StatsRepository) Totals(ctx context.Context) (*entity.StatsTotals, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	r.log.ForContext(ctx).Info("Counting stats totals")
+
+	counts := make(map[string]int, len(statsTables))
+	for _, table := range statsTables {
+		var count int
+		if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM `+table).Scan(&count); err != nil {
+			r.log.ForContext(ctx).Error("Failed to count rows",
+				logger.String("table", table),
+				logger.Error(err))
+			return nil, fmt.Errorf("failed to count %s: %w", table, err)
+		}
+		counts[table] = count
+	}
+
+	return &entity.StatsTotals{
+		Users:        counts["users"],
+		Posts:        counts["posts"],
+		Comments:     counts["comments"],
+		ChatMessages: counts["chat_messages"],
+	}, nil
+}
+
+// DailyCounts возвращает количество созданных записей по дням (YYYY-MM-DD) в диапазоне дат включительно
+func (r *StatsRepository) DailyCounts(ctx context.Context, from, to string) (map[string]*entity.DailyStats, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	r.log.ForContext(ctx).Info("Getting daily stats",
+		logger.String("from", from),
+		logger.String("to", to))
+
+	days := make(map[string]*entity.DailyStats)
+	for _, table := range statsTables {
+		// date() приводит и RFC3339 с часовым поясом, и CURRENT_TIMESTAMP к дате в UTC
+		query := `SELECT date(created_at) AS day, COUNT(*) FROM ` + table + `
+		          WHERE date(created_at) BETWEEN ? AND ?
+		          GROUP BY day`
+
+		rows, err := r.db.QueryContext(ctx, query, from, to)
+		if err != nil {
+			r.log.ForContext(ctx).Error("Failed to get daily stats",
+				logger.String("table", table),
+				logger.Error(err))
+			return nil, fmt.Errorf("failed to get daily %s: %w", table, err)
+		}
+
+		for rows.Next() {
+			var day sql.NullString
+			var count int
+			if err := rows.Scan(&day, &count); err != nil {
+				rows.Close()
+				r.log.ForContext(ctx).Error("Failed to scan daily stats row",
+					logger.String("table", table),
+					logger.Error(err))
+				return nil, err
+			}
+			if !day.Valid {
+				continue
+			}
+
+			stats, ok := days[day.String]
+			if !ok {
+				stats = &entity.DailyStats{Date: day.String}
+				days[day.String] = stats
+			}
+
+			switch table {
+			case "users":
+				stats.Users = count
+			case "posts":
+				stats.Posts = count
+			case "comments":
+				stats.Comments = count
+			case "chat_messages":
+				stats.ChatMessages = count
+			}
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	r.log.ForContext(ctx).Info("Successfully got daily stats",
+		logger.Int("days", len(days)))
+	return days, nil
+}
@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+// DefaultQueryTimeout ограничение одного запроса к базе по умолчанию
+const DefaultQueryTimeout = 5 * time.Second
+
+var queryTimeout = DefaultQueryTimeout
+
+// SetQueryTimeout задает ограничение одного запроса к базе; вызывается при запуске до обработки запросов
+func SetQueryTimeout(d time.Duration) {
+	if d > 0 {
+		queryTimeout = d
+	}
+}
+
+// withQueryTimeout ограничивает запрос к базе. Если у запроса клиента дедлайн раньше,
+// действует он: по истечении запрос к базе прерывается с context.DeadlineExceeded.
+func withQueryTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, queryTimeout)
+}
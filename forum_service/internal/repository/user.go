@@ -0,0 +1,267 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/kprf42/dolgova/forum_service/internal/entity"
+	"github.com/kprf42/dolgova/pkg/logger"
+)
+
+// UserRepository работает с пользователями auth сервиса в общей БД
+type UserRepository struct {
+	db  *sql.DB
+	log *logger.Logger
+}
+
+func NewUserRepository(db *sql.DB, log *logger.Logger) *UserRepository {
+	return &UserRepository{
+		db:  db,
+		log: log,
+	}
+}
+
+func (r *UserRepository) GetRole(ctx context.Context, userID string) (string, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	var role string
+	err := r.db.QueryRowContext(ctx, `SELECT role FROM users WHERE id = ?`, userID).Scan(&role)
+	if errors.Is(err, sql.ErrNoRows) {
+		r.log.ForContext(ctx).Warn("User not found",
+			logger.String("user_id", userID))
+		return "", fmt.Errorf("user not found")
+	}
+	if err != nil {
+		r.log.ForContext(ctx).Error("Failed to get user role",
+			logger.String("user_id", userID),
+			logger.Error(err))
+		return "", err
+	}
+
+	return role, nil
+}
+
+func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*entity.User, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	user := &entity.User{}
+	err := r.db.QueryRowContext(ctx,
+		`SELECT id, username, email, role FROM users WHERE email = ?`, email,
+	).Scan(&user.ID, &user.Username, &user.Email, &user.Role)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		r.log.ForContext(ctx).Error("Failed to get user by email",
+			logger.String("email", email),
+			logger.Error(err))
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (r *UserRepository) Create(ctx context.Context, user *entity.User) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	r.log.ForContext(ctx).Info("Creating user",
+		logger.String("user_id", user.ID),
+		logger.String("username", user.Username))
+
+	query := `INSERT INTO users (id, username, email, password, role, created_at, updated_at)
+	          VALUES (?, ?, ?, ?, ?, ?, ?)`
+	createdAt := user.CreatedAt.UTC().Format(time.DateTime)
+	if _, err := r.db.ExecContext(ctx, query,
+		user.ID,
+		user.Username,
+		user.Email,
+		user.Password,
+		user.Role,
+		createdAt,
+		createdAt,
+	); err != nil {
+		r.log.ForContext(ctx).Error("Failed to create user",
+			logger.String("user_id", user.ID),
+			logger.Error(err))
+		return fmt.Errorf("failed to create user: %w", err)
+	}
+
+	r.log.ForContext(ctx).Info("Successfully created user",
+		logger.String("user_id", user.ID))
+	return nil
+}
+
+// Anonymize переназначает посты, комментарии, сообщения чата, вложения и объявления пользователя
+// DeletedUserID и удаляет его профиль, карму, отметки о прочтении, блокировки, короткие ссылки
+// и назначения модератором. Все изменения выполняются в одной транзакции.
+func (r *UserRepository) Anonymize(ctx context.Context, userID string) (*entity.AnonymizeResult, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	r.log.ForContext(ctx).Info("Anonymizing user",
+		logger.String("user_id", userID))
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		r.log.ForContext(ctx).Error("Failed to begin anonymize transaction",
+			logger.Error(err))
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var result entity.AnonymizeResult
+	reassign := []struct {
+		query string
+		count *int64
+	}{
+		{`UPDATE posts SET author_id = ? WHERE author_id = ?`, &result.Posts},
+		{`UPDATE comments SET author_id = ? WHERE author_id = ?`, &result.Comments},
+		{`UPDATE chat_messages SET user_id = ? WHERE user_id = ?`, &result.ChatMessages},
+		{`UPDATE attachments SET owner_id = ? WHERE owner_id = ?`, nil},
+		{`UPDATE announcements SET created_by = ? WHERE created_by = ?`, nil},
+	}
+	for _, step := range reassign {
+		res, err := tx.ExecContext(ctx, step.query, entity.DeletedUserID, userID)
+		if err != nil {
+			r.log.ForContext(ctx).Error("Failed to reassign user content",
+				logger.String("user_id", userID),
+				logger.Error(err))
+			return nil, fmt.Errorf("failed to reassign user content: %w", err)
+		}
+		if step.count != nil {
+			if *step.count, err = res.RowsAffected(); err != nil {
+				return nil, fmt.Errorf("failed to get rows affected: %w", err)
+			}
+		}
+	}
+
+	cleanup := []string{
+		`DELETE FROM user_profiles WHERE user_id = ?`,
+		`DELETE FROM karma_events WHERE user_id = ?`,
+		`DELETE FROM user_karma WHERE user_id = ?`,
+		`DELETE FROM post_reads WHERE user_id = ?`,
+		`DELETE FROM category_reads WHERE user_id = ?`,
+		`DELETE FROM user_blocks WHERE blocker_id = ?1 OR blocked_id = ?1`,
+		`DELETE FROM share_links WHERE created_by = ?`,
+		`DELETE FROM category_moderators WHERE user_id = ?`,
+	}
+	for _, query := range cleanup {
+		if _, err := tx.ExecContext(ctx, query, userID); err != nil {
+			r.log.ForContext(ctx).Error("Failed to delete user data",
+				logger.String("user_id", userID),
+				logger.Error(err))
+			return nil, fmt.Errorf("failed to delete user data: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		r.log.ForContext(ctx).Error("Failed to commit anonymize transaction",
+			logger.String("user_id", userID),
+			logger.Error(err))
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ExportPosts возвращает до limit постов пользователя во всех сообществах и с любым статусом
+// в порядке ID, начиная после afterID (пустой - с начала)
+func (r *UserRepository) ExportPosts(ctx context.Context, userID, afterID string, limit int) ([]*entity.ExportedPost, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT id, tenant_id, title, content, COALESCE(category_id, ''), status, created_at FROM posts
+		 WHERE author_id = ? AND id > ? ORDER BY id LIMIT ?`,
+		userID, afterID, limit)
+	if err != nil {
+		r.log.ForContext(ctx).Error("Failed to export posts",
+			logger.String("user_id", userID),
+			logger.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var posts []*entity.ExportedPost
+	for rows.Next() {
+		post := entity.ExportedPost{Post: entity.Post{AuthorID: userID}}
+		var createdAt string
+		if err := rows.Scan(&post.ID, &post.TenantID, &post.Title, &post.Content, &post.CategoryID, &post.Status, &createdAt); err != nil {
+			return nil, fmt.Errorf("failed to scan post: %w", err)
+		}
+		if post.CreatedAt, err = time.Parse(time.RFC3339, createdAt); err != nil {
+			return nil, fmt.Errorf("failed to parse created_at: %w", err)
+		}
+		posts = append(posts, &post)
+	}
+	return posts, rows.Err()
+}
+
+// ExportComments возвращает до limit комментариев пользователя после afterID, как ExportPosts
+func (r *UserRepository) ExportComments(ctx context.Context, userID, afterID string, limit int) ([]*entity.ExportedComment, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT id, tenant_id, post_id, content, status, created_at FROM comments
+		 WHERE author_id = ? AND id > ? ORDER BY id LIMIT ?`,
+		userID, afterID, limit)
+	if err != nil {
+		r.log.ForContext(ctx).Error("Failed to export comments",
+			logger.String("user_id", userID),
+			logger.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var comments []*entity.ExportedComment
+	for rows.Next() {
+		comment := entity.ExportedComment{Comment: entity.Comment{AuthorID: userID}}
+		var createdAt string
+		if err := rows.Scan(&comment.ID, &comment.TenantID, &comment.PostID, &comment.Content, &comment.Status, &createdAt); err != nil {
+			return nil, fmt.Errorf("failed to scan comment: %w", err)
+		}
+		if comment.CreatedAt, err = time.Parse(time.RFC3339, createdAt); err != nil {
+			return nil, fmt.Errorf("failed to parse created_at: %w", err)
+		}
+		comments = append(comments, &comment)
+	}
+	return comments, rows.Err()
+}
+
+// ExportChatMessages возвращает до limit сообщений чата пользователя после afterID, как ExportPosts
+func (r *UserRepository) ExportChatMessages(ctx context.Context, userID, afterID string, limit int) ([]*entity.ChatMessage, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT id, tenant_id, text, created_at FROM chat_messages
+		 WHERE user_id = ? AND id > ? ORDER BY id LIMIT ?`,
+		userID, afterID, limit)
+	if err != nil {
+		r.log.ForContext(ctx).Error("Failed to export chat messages",
+			logger.String("user_id", userID),
+			logger.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var messages []*entity.ChatMessage
+	for rows.Next() {
+		msg := entity.ChatMessage{UserID: userID}
+		var createdAt string
+		if err := rows.Scan(&msg.ID, &msg.TenantID, &msg.Text, &createdAt); err != nil {
+			return nil, fmt.Errorf("failed to scan chat message: %w", err)
+		}
+		if msg.CreatedAt, err = time.Parse(time.RFC3339, createdAt); err != nil {
+			return nil, fmt.Errorf("failed to parse created_at: %w", err)
+		}
+		messages = append(messages, &msg)
+	}
+	return messages, rows.Err()
+}
@@ -0,0 +1,160 @@
+//go:build bleve
+
+package search
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/blevesearch/bleve/v2"
+	"github.com/blevesearch/bleve/v2/analysis/analyzer/keyword"
+	"github.com/blevesearch/bleve/v2/mapping"
+	"github.com/kprf42/dolgova/forum_service/internal/entity"
+)
+
+// bleveFuzziness допустимое число опечаток в слове запроса
+const bleveFuzziness = 1
+
+// BleveIndex индекс Bleve в отдельном каталоге: ранжирование BM25 и нечеткий поиск
+type BleveIndex struct {
+	index bleve.Index
+}
+
+type bleveDocument struct {
+	Type     string `json:"type"`
+	PostID   string `json:"post_id"`
+	TenantID string `json:"tenant_id"`
+	Title    string `json:"title"`
+	Content  string `json:"content"`
+}
+
+// NewBleveIndex открывает индекс по пути или создает новый
+func NewBleveIndex(path string) (SearchIndex, error) {
+	if path == "" {
+		return nil, errors.New("bleve index path is required")
+	}
+
+	index, err := bleve.Open(path)
+	if errors.Is(err, bleve.ErrorIndexPathDoesNotExist) {
+		index, err = bleve.New(path, bleveMapping())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open bleve index: %w", err)
+	}
+
+	return &BleveIndex{index: index}, nil
+}
+
+func bleveMapping() *mapping.IndexMappingImpl {
+	// Служебные поля индексируются целиком, без разбиения на слова
+	exact := bleve.NewTextFieldMapping()
+	exact.Analyzer = keyword.Name
+
+	doc := bleve.NewDocumentMapping()
+	doc.AddFieldMappingsAt("type", exact)
+	doc.AddFieldMappingsAt("post_id", exact)
+	doc.AddFieldMappingsAt("tenant_id", exact)
+	doc.AddFieldMappingsAt("title", bleve.NewTextFieldMapping())
+	doc.AddFieldMappingsAt("content", bleve.NewTextFieldMapping())
+
+	m := bleve.NewIndexMapping()
+	m.DefaultMapping = doc
+	return m
+}
+
+func docID(docType, id string) string {
+	return docType + ":" + id
+}
+
+func (i *BleveIndex) IndexPost(ctx context.Context, tenantID string, post *entity.Post) error {
+	return i.index.Index(docID(DocPost, post.ID), bleveDocument{
+		Type:     DocPost,
+		PostID:   post.ID,
+		TenantID: tenantID,
+		Title:    post.Title,
+		Content:  post.Content,
+	})
+}
+
+func (i *BleveIndex) IndexComment(ctx context.Context, tenantID string, comment *entity.Comment) error {
+	return i.index.Index(docID(DocComment, comment.ID), bleveDocument{
+		Type:     DocComment,
+		PostID:   comment.PostID,
+		TenantID: tenantID,
+		Content:  comment.Content,
+	})
+}
+
+func (i *BleveIndex) Remove(ctx context.Context, docType, id string) error {
+	if docType != DocPost {
+		return i.index.Delete(docID(docType, id))
+	}
+
+	// Вместе с постом удаляем его комментарии
+	byPost := bleve.NewTermQuery(id)
+	byPost.SetField("post_id")
+	req := bleve.NewSearchRequestOptions(byPost, int(^uint(0)>>1), 0, false)
+	res, err := i.index.SearchInContext(ctx, req)
+	if err != nil {
+		return fmt.Errorf("failed to find post documents: %w", err)
+	}
+
+	batch := i.index.NewBatch()
+	for _, hit := range res.Hits {
+		batch.Delete(hit.ID)
+	}
+	return i.index.Batch(batch)
+}
+
+func (i *BleveIndex) Query(ctx context.Context, q Query) (*Result, error) {
+	title := bleve.NewMatchQuery(q.Text)
+	title.SetField("title")
+	title.SetFuzziness(bleveFuzziness)
+	// Совпадение в заголовке важнее совпадения в тексте
+	title.SetBoost(2)
+
+	content := bleve.NewMatchQuery(q.Text)
+	content.SetField("content")
+	content.SetFuzziness(bleveFuzziness)
+
+	tenantQuery := bleve.NewTermQuery(q.TenantID)
+	tenantQuery.SetField("tenant_id")
+
+	text := bleve.NewDisjunctionQuery(title, content)
+	req := bleve.NewSearchRequestOptions(bleve.NewConjunctionQuery(text, tenantQuery), q.Limit, q.Offset, false)
+	req.Fields = []string{"type", "post_id", "title"}
+	req.Highlight = bleve.NewHighlightWithStyle("html")
+	req.Highlight.AddField("content")
+
+	res, err := i.index.SearchInContext(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search: %w", err)
+	}
+
+	result := &Result{Hits: []*Hit{}, Total: int(res.Total)}
+	for _, h := range res.Hits {
+		hit := &Hit{
+			Type:   fieldString(h.Fields, "type"),
+			PostID: fieldString(h.Fields, "post_id"),
+			Title:  fieldString(h.Fields, "title"),
+			Score:  h.Score,
+		}
+		hit.ID = h.ID[len(hit.Type)+1:]
+		if fragments := h.Fragments["content"]; len(fragments) > 0 {
+			hit.Snippet = fragments[0]
+		}
+		result.Hits = append(result.Hits, hit)
+	}
+
+	return result, nil
+}
+
+func (i *BleveIndex) Close() error {
+	return i.index.Close()
+}
+
+func fieldString(fields map[string]interface{}, name string) string {
+	value, _ := fields[name].(string)
+	return value
+}
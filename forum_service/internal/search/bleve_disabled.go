@@ -0,0 +1,10 @@
+//go:build !bleve
+
+package search
+
+import "errors"
+
+// NewBleveIndex без тега сборки bleve недоступен, чтобы не тянуть зависимость по умолчанию
+func NewBleveIndex(path string) (SearchIndex, error) {
+	return nil, errors.New("bleve search backend is not compiled in (build with -tags bleve)")
+}
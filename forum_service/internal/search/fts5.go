@@ -0,0 +1,144 @@
+package search
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/kprf42/dolgova/forum_service/internal/entity"
+)
+
+// FTS5Index индекс на виртуальной таблице SQLite FTS5.
+// Драйвер go-sqlite3 должен быть собран с тегом sqlite_fts5.
+type FTS5Index struct {
+	db *sql.DB
+}
+
+// NewFTS5Index создает таблицу индекса и при первом запуске заполняет ее существующими данными
+func NewFTS5Index(db *sql.DB) (*FTS5Index, error) {
+	var exists int
+	err := db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'search_index'`).Scan(&exists)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check search index: %w", err)
+	}
+	if exists > 0 {
+		return &FTS5Index{db: db}, nil
+	}
+
+	// Таблица не в миграциях: без FTS5 в сборке миграции форума должны применяться
+	_, err = db.Exec(`CREATE VIRTUAL TABLE search_index USING fts5(
+		doc_type UNINDEXED,
+		doc_id UNINDEXED,
+		post_id UNINDEXED,
+		tenant_id UNINDEXED,
+		title,
+		content,
+		tokenize = 'unicode61'
+	)`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create fts5 search index (build with -tags sqlite_fts5): %w", err)
+	}
+
+	_, err = db.Exec(`INSERT INTO search_index (doc_type, doc_id, post_id, tenant_id, title, content)
+		SELECT 'post', id, id, tenant_id, title, content FROM posts WHERE status = 'published'
+		UNION ALL
+		SELECT 'comment', id, post_id, tenant_id, '', content FROM comments WHERE status = 'published'`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fill search index: %w", err)
+	}
+
+	return &FTS5Index{db: db}, nil
+}
+
+func (i *FTS5Index) IndexPost(ctx context.Context, tenantID string, post *entity.Post) error {
+	return i.upsert(ctx, DocPost, post.ID, post.ID, tenantID, post.Title, post.Content)
+}
+
+func (i *FTS5Index) IndexComment(ctx context.Context, tenantID string, comment *entity.Comment) error {
+	return i.upsert(ctx, DocComment, comment.ID, comment.PostID, tenantID, "", comment.Content)
+}
+
+func (i *FTS5Index) upsert(ctx context.Context, docType, id, postID, tenantID, title, content string) error {
+	tx, err := i.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// FTS5 не поддерживает ON CONFLICT, поэтому заменяем документ вручную
+	if _, err := tx.ExecContext(ctx, `DELETE FROM search_index WHERE doc_type = ? AND doc_id = ?`, docType, id); err != nil {
+		return fmt.Errorf("failed to remove old document: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `INSERT INTO search_index (doc_type, doc_id, post_id, tenant_id, title, content)
+		VALUES (?, ?, ?, ?, ?, ?)`, docType, id, postID, tenantID, title, content); err != nil {
+		return fmt.Errorf("failed to index document: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+func (i *FTS5Index) Remove(ctx context.Context, docType, id string) error {
+	query := `DELETE FROM search_index WHERE doc_type = ? AND doc_id = ?`
+	args := []interface{}{docType, id}
+	if docType == DocPost {
+		query = `DELETE FROM search_index WHERE post_id = ?`
+		args = []interface{}{id}
+	}
+
+	if _, err := i.db.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("failed to remove document: %w", err)
+	}
+	return nil
+}
+
+func (i *FTS5Index) Query(ctx context.Context, q Query) (*Result, error) {
+	match := ftsQuery(q.Text)
+
+	var total int
+	err := i.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM search_index WHERE search_index MATCH ? AND tenant_id = ?`,
+		match, q.TenantID).Scan(&total)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count search results: %w", err)
+	}
+
+	rows, err := i.db.QueryContext(ctx, `SELECT doc_type, doc_id, post_id, title,
+			snippet(search_index, 5, '<b>', '</b>', '...', 16), bm25(search_index)
+		FROM search_index
+		WHERE search_index MATCH ? AND tenant_id = ?
+		ORDER BY bm25(search_index)
+		LIMIT ? OFFSET ?`, match, q.TenantID, q.Limit, q.Offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search: %w", err)
+	}
+	defer rows.Close()
+
+	result := &Result{Hits: []*Hit{}, Total: total}
+	for rows.Next() {
+		var hit Hit
+		var rank float64
+		if err := rows.Scan(&hit.Type, &hit.ID, &hit.PostID, &hit.Title, &hit.Snippet, &rank); err != nil {
+			return nil, fmt.Errorf("failed to scan search result: %w", err)
+		}
+		// bm25 отрицателен: чем меньше, тем релевантнее
+		hit.Score = -rank
+		result.Hits = append(result.Hits, &hit)
+	}
+
+	return result, rows.Err()
+}
+
+func (i *FTS5Index) Close() error {
+	// Соединение с БД принадлежит вызывающему коду
+	return nil
+}
+
+// ftsQuery превращает пользовательский текст в запрос FTS5: каждое слово ищется
+// как префикс, спецсимволы синтаксиса FTS5 экранируются кавычками
+func ftsQuery(text string) string {
+	words := strings.Fields(text)
+	for n, word := range words {
+		words[n] = `"` + strings.ReplaceAll(word, `"`, `""`) + `"*`
+	}
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,91 @@
+// Package search содержит полнотекстовый поиск по постам и комментариям.
+// Бэкенд выбирается конфигурацией: FTS5 в общей SQLite БД или отдельный индекс Bleve.
+package search
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/kprf42/dolgova/forum_service/internal/entity"
+)
+
+// Бэкенды поиска
+const (
+	BackendFTS5  = "fts5"
+	BackendBleve = "bleve"
+	BackendNone  = "none"
+)
+
+// Типы документов в индексе
+const (
+	DocPost    = "post"
+	DocComment = "comment"
+)
+
+// ErrDisabled поиск выключен конфигурацией или бэкенд недоступен
+var ErrDisabled = errors.New("search is disabled")
+
+// Query параметры поискового запроса
+type Query struct {
+	Text     string
+	TenantID string
+	Limit    int
+	Offset   int
+}
+
+// Hit найденный документ
+type Hit struct {
+	Type    string  `json:"type"`
+	ID      string  `json:"id"`
+	PostID  string  `json:"post_id"`
+	Title   string  `json:"title,omitempty"`
+	Snippet string  `json:"snippet"`
+	Score   float64 `json:"score"`
+}
+
+// Result страница результатов поиска
+type Result struct {
+	Hits  []*Hit `json:"hits"`
+	Total int    `json:"total"`
+}
+
+// SearchIndex поисковый индекс. Документы хранятся отдельно для каждого сообщества.
+type SearchIndex interface {
+	IndexPost(ctx context.Context, tenantID string, post *entity.Post) error
+	IndexComment(ctx context.Context, tenantID string, comment *entity.Comment) error
+	// Remove удаляет документ; для поста удаляются и его комментарии
+	Remove(ctx context.Context, docType, id string) error
+	Query(ctx context.Context, q Query) (*Result, error)
+	Close() error
+}
+
+// Config настройки поиска
+type Config struct {
+	Backend string // fts5, bleve или none
+	Path    string // Каталог индекса Bleve
+}
+
+// Open создает индекс выбранного бэкенда
+func Open(cfg Config, db *sql.DB) (SearchIndex, error) {
+	switch cfg.Backend {
+	case BackendFTS5, "":
+		return NewFTS5Index(db)
+	case BackendBleve:
+		return NewBleveIndex(cfg.Path)
+	case BackendNone:
+		return Disabled{}, nil
+	default:
+		return nil, fmt.Errorf("unknown search backend %q", cfg.Backend)
+	}
+}
+
+// Disabled индекс-заглушка: изменения игнорируются, запросы возвращают ErrDisabled
+type Disabled struct{}
+
+func (Disabled) IndexPost(context.Context, string, *entity.Post) error       { return nil }
+func (Disabled) IndexComment(context.Context, string, *entity.Comment) error { return nil }
+func (Disabled) Remove(context.Context, string, string) error                { return nil }
+func (Disabled) Query(context.Context, Query) (*Result, error)               { return nil, ErrDisabled }
+func (Disabled) Close() error                                                { return nil }
@@ -0,0 +1,71 @@
+// Package tenant определяет сообщество (форум), к которому относится запрос.
+// Одно развертывание обслуживает несколько изолированных сообществ;
+// сообщество выбирается по имени хоста или по префиксу пути /t/{tenant}/.
+package tenant
+
+import (
+	"context"
+	"net"
+	"strings"
+
+	"github.com/kprf42/dolgova/forum_service/internal/config"
+)
+
+// Default сообщество, к которому относятся запросы без явного выбора и старые данные
+const Default = "default"
+
+type ctxKey struct{}
+
+// WithID возвращает контекст с ID сообщества
+func WithID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, id)
+}
+
+// FromContext возвращает ID сообщества из контекста или Default
+func FromContext(ctx context.Context) string {
+	if id, ok := ctx.Value(ctxKey{}).(string); ok && id != "" {
+		return id
+	}
+	return Default
+}
+
+// Resolver сопоставляет хосты и ID сообществ по runtime настройкам
+type Resolver struct {
+	runtime *config.RuntimeWatcher
+}
+
+func NewResolver(runtime *config.RuntimeWatcher) *Resolver {
+	return &Resolver{runtime: runtime}
+}
+
+// ByHost возвращает сообщество, к которому привязан хост, или Default
+func (r *Resolver) ByHost(host string) string {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+
+	settings := r.runtime.Current()
+	for _, t := range settings.Tenants {
+		for _, h := range t.Hosts {
+			if strings.EqualFold(h, host) {
+				return t.ID
+			}
+		}
+	}
+	return Default
+}
+
+// Exists сообщает, настроено ли сообщество с таким ID
+func (r *Resolver) Exists(id string) bool {
+	if id == Default {
+		return true
+	}
+
+	settings := r.runtime.Current()
+	for _, t := range settings.Tenants {
+		if t.ID == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,132 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kprf42/dolgova/forum_service/internal/entity"
+	"github.com/kprf42/dolgova/forum_service/internal/repository"
+	"github.com/kprf42/dolgova/pkg/logger"
+)
+
+var (
+	ErrInvalidAnnouncement = errors.New("invalid announcement")
+
+	ErrAnnouncementNotFound = repository.ErrAnnouncementNotFound
+)
+
+// AnnouncementUseCase объявления администрации для баннеров на сайте
+type AnnouncementUseCase struct {
+	repo *repository.AnnouncementRepository
+	log  *logger.Logger
+}
+
+func NewAnnouncementUseCase(repo *repository.AnnouncementRepository, log *logger.Logger) *AnnouncementUseCase {
+	return &AnnouncementUseCase{
+		repo: repo,
+		log:  log,
+	}
+}
+
+func (uc *AnnouncementUseCase) Create(ctx context.Context, req *entity.AnnouncementRequest, adminID string) (*entity.Announcement, error) {
+	now := time.Now().UTC().Truncate(time.Second)
+	a := &entity.Announcement{
+		ID:        uuid.New().String(),
+		CreatedBy: adminID,
+		CreatedAt: now,
+	}
+	if err := applyAnnouncement(a, req, now); err != nil {
+		return nil, err
+	}
+
+	if err := uc.repo.Create(ctx, a); err != nil {
+		return nil, err
+	}
+
+	uc.log.ForContext(ctx).Info("Announcement created",
+		logger.String("announcement_id", a.ID),
+		logger.String("created_by", adminID))
+	return a, nil
+}
+
+// Update заменяет объявление целиком
+func (uc *AnnouncementUseCase) Update(ctx context.Context, id string, req *entity.AnnouncementRequest) (*entity.Announcement, error) {
+	a, err := uc.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyAnnouncement(a, req, time.Now().UTC().Truncate(time.Second)); err != nil {
+		return nil, err
+	}
+
+	if err := uc.repo.Update(ctx, a); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+func (uc *AnnouncementUseCase) Delete(ctx context.Context, id string) error {
+	return uc.repo.Delete(ctx, id)
+}
+
+// List возвращает все объявления для администраторов
+func (uc *AnnouncementUseCase) List(ctx context.Context) ([]*entity.Announcement, error) {
+	return uc.repo.List(ctx)
+}
+
+// Active возвращает действующие объявления для посетителя: authenticated - вошел ли он
+func (uc *AnnouncementUseCase) Active(ctx context.Context, authenticated bool) ([]*entity.Announcement, error) {
+	audiences := []string{entity.AudienceAll, entity.AudienceGuests}
+	if authenticated {
+		audiences = []string{entity.AudienceAll, entity.AudienceUsers}
+	}
+	return uc.repo.Active(ctx, time.Now(), audiences)
+}
+
+// applyAnnouncement проверяет запрос и переносит его поля в объявление
+func applyAnnouncement(a *entity.Announcement, req *entity.AnnouncementRequest, now time.Time) error {
+	title := strings.TrimSpace(req.Title)
+	if title == "" {
+		return ErrInvalidAnnouncement
+	}
+
+	severity := req.Severity
+	if severity == "" {
+		severity = entity.SeverityInfo
+	}
+	if severity != entity.SeverityInfo && severity != entity.SeverityWarning && severity != entity.SeverityCritical {
+		return ErrInvalidAnnouncement
+	}
+
+	audience := req.Audience
+	if audience == "" {
+		audience = entity.AudienceAll
+	}
+	if audience != entity.AudienceAll && audience != entity.AudienceUsers && audience != entity.AudienceGuests {
+		return ErrInvalidAnnouncement
+	}
+
+	startsAt := now
+	if req.StartsAt != nil {
+		startsAt = *req.StartsAt
+	}
+	if req.EndsAt != nil && !req.EndsAt.After(startsAt) {
+		return ErrInvalidAnnouncement
+	}
+
+	a.Title = title
+	a.Body = req.Body
+	a.Severity = severity
+	a.Audience = audience
+	a.StartsAt = startsAt.UTC().Truncate(time.Second)
+	a.EndsAt = nil
+	if req.EndsAt != nil {
+		endsAt := req.EndsAt.UTC().Truncate(time.Second)
+		a.EndsAt = &endsAt
+	}
+	a.UpdatedAt = now
+	return nil
+}
@@ -0,0 +1,321 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kprf42/dolgova/forum_service/internal/entity"
+	"github.com/kprf42/dolgova/forum_service/internal/repository"
+	"github.com/kprf42/dolgova/forum_service/internal/tenant"
+	"github.com/kprf42/dolgova/pkg/logger"
+	"github.com/kprf42/dolgova/pkg/uploads"
+)
+
+var (
+	// ErrInvalidAttachment вложение не найдено, загружено другим пользователем или уже привязано
+	ErrInvalidAttachment = errors.New("invalid attachment")
+	// ErrUnsupportedFile тип файла не разрешен для загрузки
+	ErrUnsupportedFile = errors.New("unsupported file type")
+)
+
+// attachmentTypes разрешенные типы файлов (определяются по содержимому) и их расширения
+var attachmentTypes = map[string]string{
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+	"image/gif":       ".gif",
+	"image/webp":      ".webp",
+	"application/pdf": ".pdf",
+}
+
+// ThumbnailSize уменьшенная копия изображения: вписывается в MaxWidth x MaxHeight
+type ThumbnailSize struct {
+	Name      string
+	MaxWidth  int
+	MaxHeight int
+}
+
+// ThumbnailSizes копии, которые строятся для каждого загруженного изображения
+var ThumbnailSizes = []ThumbnailSize{
+	{Name: "thumb", MaxWidth: 320, MaxHeight: 320},
+	{Name: "web", MaxWidth: 1280, MaxHeight: 1280},
+}
+
+// thumbnailQueueSize очередь на генерацию копий. Если она заполнена, вложение остается
+// в состоянии pending и обрабатывается при следующем запуске.
+const thumbnailQueueSize = 256
+
+// Attachments вложения постов, комментариев и сообщений чата
+type Attachments interface {
+	// Check проверяет, что ownerID может привязать вложения ids
+	Check(ctx context.Context, ownerID string, ids []string) error
+	Attach(ctx context.Context, ownerID, targetType, targetID string, ids []string) ([]*entity.Attachment, error)
+	// ForTargets возвращает вложения, сгруппированные по ID поста (комментария, сообщения)
+	ForTargets(ctx context.Context, targetType string, targetIDs []string) (map[string][]*entity.Attachment, error)
+}
+
+type AttachmentUseCase struct {
+	repo    *repository.AttachmentRepository
+	storage uploads.Storage
+	workers int
+	jobs    chan *entity.Attachment
+	quit    chan struct{}
+	done    chan struct{}
+	log     *logger.Logger
+}
+
+// NewAttachmentUseCase создает use case вложений. Уменьшенные копии изображений
+// строятся в фоне workers обработчиками после вызова Run.
+func NewAttachmentUseCase(repo *repository.AttachmentRepository, storage uploads.Storage, workers int, log *logger.Logger) *AttachmentUseCase {
+	return &AttachmentUseCase{
+		repo:    repo,
+		storage: storage,
+		workers: max(1, workers),
+		jobs:    make(chan *entity.Attachment, thumbnailQueueSize),
+		quit:    make(chan struct{}),
+		done:    make(chan struct{}),
+		log:     log,
+	}
+}
+
+// Upload сохраняет файл и создает непривязанное вложение. Тип файла определяется по содержимому.
+func (uc *AttachmentUseCase) Upload(ctx context.Context, ownerID string, r io.Reader) (*entity.Attachment, error) {
+	head := make([]byte, 512)
+	n, err := io.ReadFull(r, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		if errors.Is(err, io.EOF) {
+			return nil, ErrUnsupportedFile
+		}
+		return nil, err
+	}
+	head = head[:n]
+
+	contentType := http.DetectContentType(head)
+	ext, ok := attachmentTypes[contentType]
+	if !ok {
+		uc.log.ForContext(ctx).Warn("Rejected upload of unsupported type",
+			logger.String("owner_id", ownerID),
+			logger.String("content_type", contentType))
+		return nil, ErrUnsupportedFile
+	}
+
+	counter := &countingReader{r: io.MultiReader(bytes.NewReader(head), r)}
+	key, err := uc.storage.Save(ctx, counter, ext)
+	if err != nil {
+		uc.log.ForContext(ctx).Error("Failed to store upload",
+			logger.String("owner_id", ownerID),
+			logger.Error(err))
+		return nil, err
+	}
+
+	a := &entity.Attachment{
+		ID:             uuid.New().String(),
+		OwnerID:        ownerID,
+		URL:            uc.storage.URL(key),
+		ContentType:    contentType,
+		Size:           counter.n,
+		StorageKey:     key,
+		ThumbnailState: entity.ThumbnailNone,
+		TenantID:       tenant.FromContext(ctx),
+		CreatedAt:      time.Now().UTC(),
+	}
+	if uploads.IsImage(contentType) {
+		a.ThumbnailState = entity.ThumbnailPending
+	}
+
+	if err := uc.repo.Create(ctx, a); err != nil {
+		return nil, err
+	}
+
+	if a.ThumbnailState == entity.ThumbnailPending {
+		uc.enqueue(a)
+	}
+	return a, nil
+}
+
+func (uc *AttachmentUseCase) Check(ctx context.Context, ownerID string, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	ids = unique(ids)
+	if len(ids) > entity.MaxAttachments {
+		return ErrInvalidAttachment
+	}
+
+	count, err := uc.repo.CountUnattached(ctx, ownerID, ids)
+	if err != nil {
+		return err
+	}
+	if count != len(ids) {
+		uc.log.ForContext(ctx).Warn("Attempt to attach unavailable files",
+			logger.String("owner_id", ownerID),
+			logger.Int("requested", len(ids)),
+			logger.Int("available", count))
+		return ErrInvalidAttachment
+	}
+	return nil
+}
+
+func (uc *AttachmentUseCase) Attach(ctx context.Context, ownerID, targetType, targetID string, ids []string) ([]*entity.Attachment, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	if err := uc.repo.Attach(ctx, ownerID, targetType, targetID, unique(ids)); err != nil {
+		return nil, err
+	}
+	return uc.repo.ForTargets(ctx, targetType, []string{targetID})
+}
+
+func (uc *AttachmentUseCase) ForTargets(ctx context.Context, targetType string, targetIDs []string) (map[string][]*entity.Attachment, error) {
+	result := make(map[string][]*entity.Attachment)
+	if len(targetIDs) == 0 {
+		return result, nil
+	}
+
+	attachments, err := uc.repo.ForTargets(ctx, targetType, targetIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range attachments {
+		result[a.TargetID] = append(result[a.TargetID], a)
+	}
+	return result, nil
+}
+
+// Run запускает обработчики уменьшенных копий и ставит в очередь изображения,
+// не обработанные до перезапуска. Блокируется до Stop.
+func (uc *AttachmentUseCase) Run() {
+	defer close(uc.done)
+
+	var wg sync.WaitGroup
+	for i := 0; i < uc.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-uc.quit:
+					return
+				case a := <-uc.jobs:
+					uc.processThumbnails(a)
+				}
+			}
+		}()
+	}
+
+	pending, err := uc.repo.PendingThumbnails(context.Background())
+	if err != nil {
+		uc.log.Error("Failed to load pending thumbnails", logger.Error(err))
+	}
+	for _, a := range pending {
+		uc.enqueue(a)
+	}
+
+	wg.Wait()
+}
+
+// Stop останавливает обработчики. Текущие копии дописываются, остальные вложения
+// остаются в состоянии pending до следующего запуска.
+func (uc *AttachmentUseCase) Stop(ctx context.Context) error {
+	close(uc.quit)
+
+	select {
+	case <-uc.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (uc *AttachmentUseCase) enqueue(a *entity.Attachment) {
+	select {
+	case uc.jobs <- a:
+	default:
+		uc.log.Warn("Thumbnail queue is full, attachment left pending",
+			logger.String("attachment_id", a.ID))
+	}
+}
+
+// processThumbnails строит уменьшенные копии изображения и сохраняет их адреса
+func (uc *AttachmentUseCase) processThumbnails(a *entity.Attachment) {
+	ctx := tenant.WithID(context.Background(), a.TenantID)
+
+	variants, err := uc.buildThumbnails(ctx, a)
+	if err != nil {
+		uc.log.ForContext(ctx).Error("Failed to build thumbnails",
+			logger.String("attachment_id", a.ID),
+			logger.Error(err))
+		uc.repo.SetVariants(ctx, a.ID, nil, entity.ThumbnailFailed)
+		return
+	}
+
+	if err := uc.repo.SetVariants(ctx, a.ID, variants, entity.ThumbnailReady); err != nil {
+		return
+	}
+	uc.log.ForContext(ctx).Info("Thumbnails built",
+		logger.String("attachment_id", a.ID),
+		logger.Int("variants", len(variants)))
+}
+
+func (uc *AttachmentUseCase) buildThumbnails(ctx context.Context, a *entity.Attachment) (map[string]string, error) {
+	f, _, err := uc.storage.Open(a.StorageKey)
+	if err != nil {
+		return nil, err
+	}
+	img, format, err := uploads.DecodeImage(f)
+	f.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	bounds := img.Bounds()
+	variants := make(map[string]string, len(ThumbnailSizes))
+	for _, size := range ThumbnailSizes {
+		w, h := uploads.Fit(bounds.Dx(), bounds.Dy(), size.MaxWidth, size.MaxHeight)
+		if w == bounds.Dx() && h == bounds.Dy() {
+			// Оригинал уже меньше варианта
+			variants[size.Name] = a.URL
+			continue
+		}
+
+		data, ext, err := uploads.Encode(uploads.Resize(img, w, h), format)
+		if err != nil {
+			return nil, err
+		}
+		key, err := uc.storage.Save(ctx, bytes.NewReader(data), ext)
+		if err != nil {
+			return nil, err
+		}
+		variants[size.Name] = uc.storage.URL(key)
+	}
+	return variants, nil
+}
+
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+func unique(ids []string) []string {
+	seen := make(map[string]bool, len(ids))
+	result := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			result = append(result, id)
+		}
+	}
+	return result
+}
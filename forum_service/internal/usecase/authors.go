@@ -0,0 +1,50 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/kprf42/dolgova/forum_service/internal/entity"
+	"github.com/kprf42/dolgova/pkg/logger"
+)
+
+// Authors публичные данные авторов (имя, аватар) из auth сервиса
+type Authors interface {
+	// GetUsers возвращает авторов по ID; неизвестных пользователей в результате нет
+	GetUsers(ctx context.Context, ids []string) (map[string]*entity.Author, error)
+}
+
+// lookupAuthors возвращает авторов userIDs. Данные авторов лишь дополняют ответ, поэтому
+// при недоступности auth сервиса ошибка только логируется, а результат пустой.
+func lookupAuthors(ctx context.Context, authors Authors, log *logger.Logger, userIDs []string) map[string]*entity.Author {
+	if authors == nil || len(userIDs) == 0 {
+		return nil
+	}
+
+	ids := make([]string, 0, len(userIDs))
+	for _, id := range userIDs {
+		if id != entity.DeletedUserID {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	result, err := authors.GetUsers(ctx, ids)
+	if err != nil {
+		log.ForContext(ctx).Warn("Failed to get authors, responding without author info",
+			logger.Error(err))
+		return nil
+	}
+	return result
+}
+
+// withKarma возвращает копию автора с кармой: авторы из auth клиента общие для всех запросов
+func withKarma(author *entity.Author, karma int) *entity.Author {
+	if author == nil {
+		return nil
+	}
+	copied := *author
+	copied.Karma = karma
+	return &copied
+}
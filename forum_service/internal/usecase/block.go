@@ -0,0 +1,82 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"github.com/kprf42/dolgova/forum_service/internal/entity"
+	"github.com/kprf42/dolgova/forum_service/internal/events"
+	"github.com/kprf42/dolgova/forum_service/internal/repository"
+	"github.com/kprf42/dolgova/forum_service/internal/tenant"
+	"github.com/kprf42/dolgova/pkg/logger"
+)
+
+var (
+	ErrCannotBlockSelf = errors.New("cannot block yourself")
+
+	ErrBlockNotFound = repository.ErrBlockNotFound
+)
+
+// BlockUseCase блокировки пользователей. Контент заблокированных скрывают репозитории
+// по пользователю из контекста, чат - Hub; изменения публикуются в шину событий.
+type BlockUseCase struct {
+	repo   *repository.BlockRepository
+	roles  RoleResolver
+	events *events.Bus
+	log    *logger.Logger
+}
+
+func NewBlockUseCase(repo *repository.BlockRepository, roles RoleResolver, bus *events.Bus, log *logger.Logger) *BlockUseCase {
+	return &BlockUseCase{
+		repo:   repo,
+		roles:  roles,
+		events: bus,
+		log:    log,
+	}
+}
+
+// Block блокирует пользователя blockedID для blockerID
+func (uc *BlockUseCase) Block(ctx context.Context, blockerID, blockedID string) (*entity.Block, error) {
+	if blockerID == blockedID {
+		return nil, ErrCannotBlockSelf
+	}
+	if _, err := uc.roles.GetRole(ctx, blockedID); err != nil {
+		return nil, notFound(err, ErrUserNotFound)
+	}
+
+	b := &entity.Block{BlockerID: blockerID, BlockedID: blockedID}
+	if err := uc.repo.Block(ctx, b); err != nil {
+		return nil, err
+	}
+
+	uc.publish(ctx, events.UserBlocked, blockerID)
+	return b, nil
+}
+
+// Unblock снимает блокировку
+func (uc *BlockUseCase) Unblock(ctx context.Context, blockerID, blockedID string) error {
+	if err := uc.repo.Unblock(ctx, blockerID, blockedID); err != nil {
+		return err
+	}
+
+	uc.publish(ctx, events.UserUnblocked, blockerID)
+	return nil
+}
+
+// List возвращает пользователей, заблокированных blockerID
+func (uc *BlockUseCase) List(ctx context.Context, blockerID string) ([]*entity.Block, error) {
+	return uc.repo.List(ctx, blockerID)
+}
+
+// BlockedIDs возвращает ID пользователей, заблокированных blockerID
+func (uc *BlockUseCase) BlockedIDs(ctx context.Context, blockerID string) ([]string, error) {
+	return uc.repo.BlockedIDs(ctx, blockerID)
+}
+
+func (uc *BlockUseCase) publish(ctx context.Context, eventType events.Type, blockerID string) {
+	uc.events.Publish(ctx, events.Event{
+		Type:     eventType,
+		TenantID: tenant.FromContext(ctx),
+		ID:       blockerID,
+	})
+}
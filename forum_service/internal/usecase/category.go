@@ -0,0 +1,208 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kprf42/dolgova/forum_service/internal/entity"
+	"github.com/kprf42/dolgova/forum_service/internal/repository"
+	"github.com/kprf42/dolgova/pkg/logger"
+)
+
+const (
+	// maxCategoryName предельная длина названия категории в символах
+	maxCategoryName = 100
+	// maxCategoryDepth предельный уровень вложенности подфорумов (0 у корневых категорий)
+	maxCategoryDepth = 4
+)
+
+var (
+	ErrInvalidCategory = errors.New("invalid category")
+	// ErrInvalidCategoryParent родитель не найден, вложенность слишком глубокая или образуется цикл
+	ErrInvalidCategoryParent = errors.New("invalid category parent")
+	// ErrUnknownCategory пост или действие ссылается на несуществующую категорию
+	ErrUnknownCategory = errors.New("unknown category")
+
+	ErrCategoryNotFound = repository.ErrCategoryNotFound
+	ErrCategoryNotEmpty = repository.ErrCategoryNotEmpty
+)
+
+// Categories проверяет категории. Используется use case'ом постов, чтобы не публиковать
+// посты в несуществующие категории.
+type Categories interface {
+	// Check возвращает ErrUnknownCategory, если категории нет в текущем сообществе
+	Check(ctx context.Context, categoryID string) error
+}
+
+// CategoryUseCase категории форума: список и дерево для всех, изменение для администраторов
+type CategoryUseCase struct {
+	repo *repository.CategoryRepository
+	log  *logger.Logger
+}
+
+func NewCategoryUseCase(repo *repository.CategoryRepository, log *logger.Logger) *CategoryUseCase {
+	return &CategoryUseCase{
+		repo: repo,
+		log:  log,
+	}
+}
+
+func (uc *CategoryUseCase) Create(ctx context.Context, req *entity.CategoryRequest) (*entity.Category, error) {
+	now := time.Now().UTC().Truncate(time.Second)
+	c := &entity.Category{
+		ID:        uuid.New().String(),
+		CreatedAt: now,
+	}
+	if err := applyCategory(c, req, now); err != nil {
+		return nil, err
+	}
+	if c.ParentID != "" {
+		parent, err := uc.repo.GetByID(ctx, c.ParentID)
+		if errors.Is(err, ErrCategoryNotFound) {
+			return nil, ErrInvalidCategoryParent
+		}
+		if err != nil {
+			return nil, err
+		}
+		if parent.Depth >= maxCategoryDepth {
+			return nil, ErrInvalidCategoryParent
+		}
+		c.Depth = parent.Depth + 1
+	}
+
+	if err := uc.repo.Create(ctx, c); err != nil {
+		return nil, err
+	}
+
+	uc.log.ForContext(ctx).Info("Category created",
+		logger.String("category_id", c.ID))
+	return c, nil
+}
+
+// Update заменяет категорию целиком; смена parent_id переносит категорию вместе с подкатегориями
+func (uc *CategoryUseCase) Update(ctx context.Context, id string, req *entity.CategoryRequest) (*entity.Category, error) {
+	c, err := uc.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	parentID := c.ParentID
+	if err := applyCategory(c, req, time.Now().UTC().Truncate(time.Second)); err != nil {
+		return nil, err
+	}
+	if c.ParentID != parentID {
+		if err := uc.move(ctx, c); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := uc.repo.Update(ctx, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Delete удаляет категорию, если в ней нет постов и подкатегорий
+func (uc *CategoryUseCase) Delete(ctx context.Context, id string) error {
+	return uc.repo.Delete(ctx, id)
+}
+
+func (uc *CategoryUseCase) List(ctx context.Context) ([]*entity.Category, error) {
+	return uc.repo.List(ctx)
+}
+
+// Tree возвращает корневые категории с вложенными подкатегориями
+func (uc *CategoryUseCase) Tree(ctx context.Context) ([]*entity.CategoryNode, error) {
+	categories, err := uc.repo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Список упорядочен по глубине, поэтому родитель попадает в nodes раньше своих подкатегорий
+	nodes := make(map[string]*entity.CategoryNode, len(categories))
+	roots := []*entity.CategoryNode{}
+	for _, c := range categories {
+		node := &entity.CategoryNode{Category: c, Children: []*entity.CategoryNode{}}
+		nodes[c.ID] = node
+		if parent, ok := nodes[c.ParentID]; ok {
+			parent.Children = append(parent.Children, node)
+		} else {
+			roots = append(roots, node)
+		}
+	}
+	return roots, nil
+}
+
+// move проверяет перенос категории c к новому родителю c.ParentID и вычисляет ее глубину.
+// Родитель не может быть самой категорией или ее подкатегорией, а поддерево после переноса
+// должно уложиться в maxCategoryDepth.
+func (uc *CategoryUseCase) move(ctx context.Context, c *entity.Category) error {
+	categories, err := uc.repo.List(ctx)
+	if err != nil {
+		return err
+	}
+	byID := make(map[string]*entity.Category, len(categories))
+	children := make(map[string][]string)
+	for _, category := range categories {
+		byID[category.ID] = category
+		children[category.ParentID] = append(children[category.ParentID], category.ID)
+	}
+
+	c.Depth = 0
+	if c.ParentID != "" {
+		parent, ok := byID[c.ParentID]
+		if !ok {
+			return ErrInvalidCategoryParent
+		}
+		for p := parent; p != nil; p = byID[p.ParentID] {
+			if p.ID == c.ID {
+				return ErrInvalidCategoryParent
+			}
+		}
+		c.Depth = parent.Depth + 1
+	}
+
+	var height func(id string) int
+	height = func(id string) int {
+		h := 0
+		for _, child := range children[id] {
+			h = max(h, height(child)+1)
+		}
+		return h
+	}
+	if c.Depth+height(c.ID) > maxCategoryDepth {
+		return ErrInvalidCategoryParent
+	}
+	return nil
+}
+
+func (uc *CategoryUseCase) Check(ctx context.Context, categoryID string) error {
+	if categoryID == "" {
+		return ErrUnknownCategory
+	}
+	exists, err := uc.repo.Exists(ctx, categoryID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrUnknownCategory
+	}
+	return nil
+}
+
+// applyCategory проверяет запрос и переносит его поля в категорию
+func applyCategory(c *entity.Category, req *entity.CategoryRequest, now time.Time) error {
+	name := strings.TrimSpace(req.Name)
+	if name == "" || len([]rune(name)) > maxCategoryName {
+		return ErrInvalidCategory
+	}
+
+	c.ParentID = strings.TrimSpace(req.ParentID)
+	c.Name = name
+	c.Description = strings.TrimSpace(req.Description)
+	c.Position = req.Position
+	c.UpdatedAt = now
+	return nil
+}
@@ -10,64 +10,119 @@ import (
 )
 
 type ChatUseCase struct {
-	repo *repository.ChatRepository
-	log  *logger.Logger
+	repo        *repository.ChatRepository
+	attachments Attachments
+	authors     Authors
+	log         *logger.Logger
 }
 
-func NewChatUseCase(repo *repository.ChatRepository, log *logger.Logger) *ChatUseCase {
+// NewChatUseCase создает use case чата; attachments может быть nil, тогда вложения не поддерживаются;
+// authors может быть nil, тогда данные авторов сообщений не заполняются
+func NewChatUseCase(repo *repository.ChatRepository, attachments Attachments, authors Authors, log *logger.Logger) *ChatUseCase {
 	return &ChatUseCase{
-		repo: repo,
-		log:  log,
+		repo:        repo,
+		attachments: attachments,
+		authors:     authors,
+		log:         log,
 	}
 }
 
 func (uc *ChatUseCase) SaveMessage(ctx context.Context, msg *entity.ChatMessage) error {
-	uc.log.Info("Saving chat message",
+	uc.log.ForContext(ctx).Info("Saving chat message",
 		logger.String("message_id", msg.ID),
 		logger.String("user_id", msg.UserID))
 
+	if uc.attachments != nil {
+		if err := uc.attachments.Check(ctx, msg.UserID, msg.AttachmentIDs); err != nil {
+			return err
+		}
+	}
+
 	if err := uc.repo.SaveMessage(ctx, msg); err != nil {
-		uc.log.Error("Failed to save chat message",
+		uc.log.ForContext(ctx).Error("Failed to save chat message",
 			logger.String("message_id", msg.ID),
 			logger.Error(err))
 		return err
 	}
 
-	uc.log.Info("Successfully saved chat message",
+	if uc.attachments != nil {
+		attachments, err := uc.attachments.Attach(ctx, msg.UserID, entity.AttachmentChat, msg.ID, msg.AttachmentIDs)
+		if err != nil {
+			uc.log.ForContext(ctx).Error("Failed to attach files to chat message",
+				logger.String("message_id", msg.ID),
+				logger.Error(err))
+			return err
+		}
+		msg.Attachments = attachments
+	}
+	uc.loadAuthors(ctx, msg)
+
+	uc.log.ForContext(ctx).Info("Successfully saved chat message",
 		logger.String("message_id", msg.ID))
 
 	return nil
 }
 
-func (uc *ChatUseCase) GetMessages(ctx context.Context, limit, offset int) ([]*entity.ChatMessage, error) {
-	uc.log.Info("Getting chat messages",
+// GetMessages возвращает страницу сообщений: по offset или, если задан after, после курсора
+func (uc *ChatUseCase) GetMessages(ctx context.Context, limit, offset int, after *entity.PageCursor) ([]*entity.ChatMessage, error) {
+	uc.log.ForContext(ctx).Info("Getting chat messages",
 		logger.Int("limit", limit),
 		logger.Int("offset", offset))
 
-	messages, err := uc.repo.GetMessages(ctx, limit, offset)
+	messages, err := uc.repo.GetMessages(ctx, limit, offset, after)
 	if err != nil {
-		uc.log.Error("Failed to get chat messages",
+		uc.log.ForContext(ctx).Error("Failed to get chat messages",
 			logger.Error(err))
 		return nil, err
 	}
 
-	uc.log.Info("Successfully got chat messages",
+	if uc.attachments != nil && len(messages) > 0 {
+		ids := make([]string, len(messages))
+		for i, msg := range messages {
+			ids[i] = msg.ID
+		}
+		attachments, err := uc.attachments.ForTargets(ctx, entity.AttachmentChat, ids)
+		if err != nil {
+			uc.log.ForContext(ctx).Error("Failed to get chat attachments",
+				logger.Error(err))
+			return nil, err
+		}
+		for _, msg := range messages {
+			msg.Attachments = attachments[msg.ID]
+		}
+	}
+	uc.loadAuthors(ctx, messages...)
+
+	uc.log.ForContext(ctx).Info("Successfully got chat messages",
 		logger.Int("count", len(messages)))
 
 	return messages, nil
 }
 
 func (uc *ChatUseCase) CleanOldMessages(ctx context.Context, olderThan time.Duration) error {
-	uc.log.Info("Cleaning old chat messages",
+	uc.log.ForContext(ctx).Info("Cleaning old chat messages",
 		logger.Float64("older_than_seconds", olderThan.Seconds()))
 
 	if err := uc.repo.CleanOldMessages(ctx, olderThan); err != nil {
-		uc.log.Error("Failed to clean old chat messages",
+		uc.log.ForContext(ctx).Error("Failed to clean old chat messages",
 			logger.Float64("older_than_seconds", olderThan.Seconds()),
 			logger.Error(err))
 		return err
 	}
 
-	uc.log.Info("Successfully cleaned old chat messages")
+	uc.log.ForContext(ctx).Info("Successfully cleaned old chat messages")
 	return nil
 }
+
+// loadAuthors заполняет данные авторов сообщений одним запросом к auth сервису
+func (uc *ChatUseCase) loadAuthors(ctx context.Context, messages ...*entity.ChatMessage) {
+	ids := make([]string, len(messages))
+	for i, msg := range messages {
+		ids[i] = msg.UserID
+	}
+
+	authors := lookupAuthors(ctx, uc.authors, uc.log, ids)
+	for _, msg := range messages {
+		msg.Author = authors[msg.UserID]
+	}
+}
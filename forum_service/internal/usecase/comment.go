@@ -3,75 +3,214 @@ package usecase
 import (
 	"context"
 	"errors"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/kprf42/dolgova/forum_service/internal/entity"
+	"github.com/kprf42/dolgova/forum_service/internal/events"
 	"github.com/kprf42/dolgova/forum_service/internal/repository"
+	"github.com/kprf42/dolgova/forum_service/internal/tenant"
 	"github.com/kprf42/dolgova/pkg/logger"
 )
 
+// ErrPostLocked тема поста закрыта модератором, новые комментарии не принимаются
+var ErrPostLocked = errors.New("post is locked")
+
+// ErrCommentNotFound комментарий не найден или не опубликован
+var ErrCommentNotFound = repository.ErrCommentNotFound
+
 type CommentUseCase struct {
-	repo *repository.CommentRepository
-	log  *logger.Logger
+	repo        *repository.CommentRepository
+	posts       *repository.PostRepository
+	policy      StatusPolicy
+	moderators  Moderators
+	attachments Attachments
+	karma       Karma
+	authors     Authors
+	events      *events.Bus
+	log         *logger.Logger
 }
 
-func NewCommentUseCase(repo *repository.CommentRepository, log *logger.Logger) *CommentUseCase {
+// NewCommentUseCase создает use case комментариев. bus может быть nil, тогда события не публикуются.
+// policy может быть nil, тогда комментарии публикуются сразу; moderators может быть nil,
+// тогда удалять комментарий может только автор; attachments может быть nil, тогда вложения не поддерживаются;
+// karma может быть nil, тогда ссылки не ограничиваются, а карма авторов не заполняется;
+// authors может быть nil, тогда данные авторов не заполняются.
+func NewCommentUseCase(repo *repository.CommentRepository, posts *repository.PostRepository, policy StatusPolicy, moderators Moderators, attachments Attachments, karma Karma, authors Authors, bus *events.Bus, log *logger.Logger) *CommentUseCase {
 	return &CommentUseCase{
-		repo: repo,
-		log:  log,
+		repo:        repo,
+		posts:       posts,
+		policy:      policy,
+		moderators:  moderators,
+		attachments: attachments,
+		karma:       karma,
+		authors:     authors,
+		events:      bus,
+		log:         log,
 	}
 }
 
 func (uc *CommentUseCase) Create(ctx context.Context, req *entity.CommentRequest, authorID string) (*entity.Comment, error) {
-	uc.log.Info("Creating new comment",
+	uc.log.ForContext(ctx).Info("Creating new comment",
 		logger.String("post_id", req.PostID),
 		logger.String("author_id", authorID))
 
+	if err := uc.checkLocked(ctx, req.PostID, authorID); err != nil {
+		return nil, err
+	}
+
 	comment := entity.NewComment(req, authorID)
+	if uc.policy != nil {
+		status, err := uc.policy.InitialStatus(ctx, authorID)
+		if err != nil {
+			uc.log.ForContext(ctx).Error("Failed to resolve comment status",
+				logger.String("author_id", authorID),
+				logger.Error(err))
+			return nil, err
+		}
+		comment.Status = status
+	}
 
-	uc.log.Debug("Generated comment details",
+	if uc.attachments != nil {
+		if err := uc.attachments.Check(ctx, authorID, req.AttachmentIDs); err != nil {
+			return nil, err
+		}
+	}
+
+	if uc.karma != nil {
+		if err := uc.karma.CheckLinks(ctx, authorID, req.Content); err != nil {
+			return nil, err
+		}
+	}
+
+	uc.log.ForContext(ctx).Debug("Generated comment details",
 		logger.String("comment_id", comment.ID),
 		logger.String("post_id", comment.PostID))
 
 	if err := uc.repo.Create(ctx, comment); err != nil {
-		uc.log.Error("Failed to create comment",
+		uc.log.ForContext(ctx).Error("Failed to create comment",
 			logger.String("comment_id", comment.ID),
 			logger.Error(err))
 		return nil, err
 	}
 
-	uc.log.Info("Successfully created comment",
-		logger.String("comment_id", comment.ID))
+	uc.log.ForContext(ctx).Info("Successfully created comment",
+		logger.String("comment_id", comment.ID),
+		logger.String("status", comment.Status))
+
+	if uc.attachments != nil {
+		attachments, err := uc.attachments.Attach(ctx, authorID, entity.AttachmentComment, comment.ID, req.AttachmentIDs)
+		if err != nil {
+			uc.log.ForContext(ctx).Error("Failed to attach files to comment",
+				logger.String("comment_id", comment.ID),
+				logger.Error(err))
+			return nil, err
+		}
+		comment.Attachments = attachments
+	}
+
+	// Комментарий на премодерации становится видимым только после одобрения
+	if comment.Status == entity.StatusPublished {
+		uc.publish(ctx, events.CommentCreated, comment.ID, comment)
+	}
+
+	return comment, nil
+}
+
+// checkLocked возвращает ErrPostLocked, если тема поста закрыта. Модераторы поста
+// могут комментировать и в закрытой теме.
+func (uc *CommentUseCase) checkLocked(ctx context.Context, postID, authorID string) error {
+	locked, err := uc.repo.PostLocked(ctx, postID)
+	if err != nil || !locked {
+		return err
+	}
+	if uc.moderators != nil {
+		allowed, err := uc.moderators.CanModeratePost(ctx, authorID, postID)
+		if err != nil {
+			return err
+		}
+		if allowed {
+			return nil
+		}
+	}
+
+	uc.log.ForContext(ctx).Warn("Comment to locked post rejected",
+		logger.String("post_id", postID),
+		logger.String("author_id", authorID))
+	return ErrPostLocked
+}
+
+// Import сохраняет комментарий из внешнего источника с исходной датой создания
+func (uc *CommentUseCase) Import(ctx context.Context, comment *entity.Comment) (*entity.Comment, error) {
+	if comment.Content == "" || comment.PostID == "" {
+		return nil, errors.New("content and post_id are required")
+	}
+
+	comment.ID = uuid.New().String()
+	if comment.CreatedAt.IsZero() {
+		comment.CreatedAt = time.Now()
+	}
+	if comment.Status == "" {
+		comment.Status = entity.StatusPublished
+	}
+
+	if err := uc.repo.Create(ctx, comment); err != nil {
+		uc.log.ForContext(ctx).Error("Failed to import comment",
+			logger.String("comment_id", comment.ID),
+			logger.Error(err))
+		return nil, err
+	}
+
+	if comment.Status == entity.StatusPublished {
+		uc.publish(ctx, events.CommentCreated, comment.ID, comment)
+	}
 
 	return comment, nil
 }
 
 func (uc *CommentUseCase) GetByID(ctx context.Context, id string) (*entity.Comment, error) {
-	uc.log.Info("Getting comment by ID",
+	uc.log.ForContext(ctx).Info("Getting comment by ID",
 		logger.String("comment_id", id))
 
 	comment, err := uc.repo.GetByID(ctx, id)
 	if err != nil {
-		uc.log.Error("Failed to get comment",
+		uc.log.ForContext(ctx).Error("Failed to get comment",
 			logger.String("comment_id", id),
 			logger.Error(err))
 		return nil, err
 	}
 
-	uc.log.Info("Successfully got comment",
+	if comment.Status != entity.StatusPublished {
+		uc.log.ForContext(ctx).Warn("Comment is not published",
+			logger.String("comment_id", id),
+			logger.String("status", comment.Status))
+		return nil, ErrCommentNotFound
+	}
+
+	if err := uc.loadAttachments(ctx, comment); err != nil {
+		return nil, err
+	}
+	if err := uc.loadKarma(ctx, comment); err != nil {
+		return nil, err
+	}
+	uc.loadAuthors(ctx, comment)
+
+	uc.log.ForContext(ctx).Info("Successfully got comment",
 		logger.String("comment_id", id))
 
 	return comment, nil
 }
 
-func (uc *CommentUseCase) GetByPostID(ctx context.Context, postID string, limit, offset int) ([]*entity.Comment, int, error) {
-	uc.log.Info("Getting comments by post ID",
+// GetByPostID возвращает страницу комментариев поста: по offset или, если задан after, после курсора
+func (uc *CommentUseCase) GetByPostID(ctx context.Context, postID string, limit, offset int, after *entity.PageCursor) ([]*entity.Comment, int, error) {
+	uc.log.ForContext(ctx).Info("Getting comments by post ID",
 		logger.String("post_id", postID),
 		logger.Int("limit", limit),
 		logger.Int("offset", offset))
 
-	comments, err := uc.repo.GetByPostID(ctx, postID, limit, offset)
+	comments, err := uc.repo.GetByPostID(ctx, postID, limit, offset, after)
 	if err != nil {
-		uc.log.Error("Failed to get comments",
+		uc.log.ForContext(ctx).Error("Failed to get comments",
 			logger.String("post_id", postID),
 			logger.Error(err))
 		return nil, 0, err
@@ -79,13 +218,21 @@ func (uc *CommentUseCase) GetByPostID(ctx context.Context, postID string, limit,
 
 	total, err := uc.repo.CountByPostID(ctx, postID)
 	if err != nil {
-		uc.log.Error("Failed to count comments",
+		uc.log.ForContext(ctx).Error("Failed to count comments",
 			logger.String("post_id", postID),
 			logger.Error(err))
 		return nil, 0, err
 	}
 
-	uc.log.Info("Successfully got comments",
+	if err := uc.loadAttachments(ctx, comments...); err != nil {
+		return nil, 0, err
+	}
+	if err := uc.loadKarma(ctx, comments...); err != nil {
+		return nil, 0, err
+	}
+	uc.loadAuthors(ctx, comments...)
+
+	uc.log.ForContext(ctx).Info("Successfully got comments",
 		logger.String("post_id", postID),
 		logger.Int("count", len(comments)),
 		logger.Int("total", total))
@@ -94,28 +241,34 @@ func (uc *CommentUseCase) GetByPostID(ctx context.Context, postID string, limit,
 }
 
 func (uc *CommentUseCase) Update(ctx context.Context, id string, content string, authorID string) (*entity.Comment, error) {
-	uc.log.Info("Updating comment",
+	uc.log.ForContext(ctx).Info("Updating comment",
 		logger.String("comment_id", id),
 		logger.String("author_id", authorID))
 
 	comment, err := uc.repo.GetByID(ctx, id)
 	if err != nil {
-		uc.log.Error("Failed to get comment for update",
+		uc.log.ForContext(ctx).Error("Failed to get comment for update",
 			logger.String("comment_id", id),
 			logger.Error(err))
 		return nil, err
 	}
 
 	if comment.AuthorID != authorID {
-		uc.log.Warn("Unauthorized comment update attempt",
+		uc.log.ForContext(ctx).Warn("Unauthorized comment update attempt",
 			logger.String("comment_id", id),
 			logger.String("author_id", authorID),
 			logger.String("comment_author_id", comment.AuthorID))
 		return nil, errors.New("unauthorized")
 	}
 
+	if uc.karma != nil {
+		if err := uc.karma.CheckLinks(ctx, authorID, content); err != nil {
+			return nil, err
+		}
+	}
+
 	if err := uc.repo.Update(ctx, id, content); err != nil {
-		uc.log.Error("Failed to update comment",
+		uc.log.ForContext(ctx).Error("Failed to update comment",
 			logger.String("comment_id", id),
 			logger.Error(err))
 		return nil, err
@@ -123,33 +276,35 @@ func (uc *CommentUseCase) Update(ctx context.Context, id string, content string,
 
 	updatedComment, err := uc.repo.GetByID(ctx, id)
 	if err != nil {
-		uc.log.Error("Failed to get updated comment",
+		uc.log.ForContext(ctx).Error("Failed to get updated comment",
 			logger.String("comment_id", id),
 			logger.Error(err))
 		return nil, err
 	}
 
-	uc.log.Info("Successfully updated comment",
+	uc.log.ForContext(ctx).Info("Successfully updated comment",
 		logger.String("comment_id", id))
 
+	uc.publish(ctx, events.CommentUpdated, id, updatedComment)
+
 	return updatedComment, nil
 }
 
 func (uc *CommentUseCase) Delete(ctx context.Context, id string, authorID string) error {
-	uc.log.Info("Deleting comment",
+	uc.log.ForContext(ctx).Info("Deleting comment",
 		logger.String("comment_id", id),
 		logger.String("author_id", authorID))
 
 	comment, err := uc.repo.GetByID(ctx, id)
 	if err != nil {
-		uc.log.Error("Failed to get comment for deletion",
+		uc.log.ForContext(ctx).Error("Failed to get comment for deletion",
 			logger.String("comment_id", id),
 			logger.Error(err))
 		return err
 	}
 
-	if comment.AuthorID != authorID {
-		uc.log.Warn("Unauthorized comment deletion attempt",
+	if comment.AuthorID != authorID && !uc.canModerate(ctx, authorID, comment.PostID) {
+		uc.log.ForContext(ctx).Warn("Unauthorized comment deletion attempt",
 			logger.String("comment_id", id),
 			logger.String("author_id", authorID),
 			logger.String("comment_author_id", comment.AuthorID))
@@ -157,14 +312,257 @@ func (uc *CommentUseCase) Delete(ctx context.Context, id string, authorID string
 	}
 
 	if err := uc.repo.Delete(ctx, id); err != nil {
-		uc.log.Error("Failed to delete comment",
+		uc.log.ForContext(ctx).Error("Failed to delete comment",
 			logger.String("comment_id", id),
 			logger.Error(err))
 		return err
 	}
 
-	uc.log.Info("Successfully deleted comment",
+	uc.log.ForContext(ctx).Info("Successfully deleted comment",
 		logger.String("comment_id", id))
 
+	if err := uc.clearAnswer(ctx, comment); err != nil {
+		return err
+	}
+
+	uc.publish(ctx, events.CommentDeleted, id, nil)
+
 	return nil
 }
+
+// Vote сохраняет голос userID за опубликованный комментарий (1 - за, -1 - против, 0 - снять голос)
+// и начисляет карму автору. Повторный такой же голос ничего не меняет.
+func (uc *CommentUseCase) Vote(ctx context.Context, commentID, userID string, value int) (*entity.CommentVote, error) {
+	if value < -1 || value > 1 {
+		return nil, ErrInvalidVote
+	}
+
+	comment, err := uc.repo.GetByID(ctx, commentID)
+	if err != nil {
+		return nil, err
+	}
+	if comment.Status != entity.StatusPublished {
+		return nil, ErrCommentNotFound
+	}
+
+	score, err := uc.repo.Vote(ctx, commentID, userID, value)
+	if err != nil {
+		uc.log.ForContext(ctx).Error("Failed to vote for comment",
+			logger.String("comment_id", commentID),
+			logger.String("user_id", userID),
+			logger.Error(err))
+		return nil, err
+	}
+
+	// Начисление по голосу идемпотентно, поэтому при ошибке повторный голос его восстановит
+	if uc.karma != nil {
+		if err := uc.karma.CommentVoted(ctx, comment.AuthorID, commentID, userID, value); err != nil {
+			uc.log.ForContext(ctx).Error("Failed to update author karma",
+				logger.String("comment_id", commentID),
+				logger.String("author_id", comment.AuthorID),
+				logger.Error(err))
+			return nil, err
+		}
+	}
+
+	return &entity.CommentVote{CommentID: commentID, Score: score, Vote: value}, nil
+}
+
+// AcceptAnswer отмечает комментарий принятым ответом на пост (accepted = false снимает отметку).
+// Отмечать может только автор поста, у поста не больше одного принятого ответа: новая отметка
+// заменяет прежнюю. Автор комментария получает карму и уведомление через событие AnswerAccepted.
+func (uc *CommentUseCase) AcceptAnswer(ctx context.Context, postID, commentID, userID string, accepted bool) (*entity.Comment, error) {
+	uc.log.ForContext(ctx).Info("Accepting answer",
+		logger.String("post_id", postID),
+		logger.String("comment_id", commentID),
+		logger.String("user_id", userID),
+		logger.Bool("accepted", accepted))
+
+	post, err := uc.posts.GetByID(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+	if post.Status != entity.StatusPublished {
+		return nil, errors.New("post not found")
+	}
+	if post.AuthorID != userID {
+		uc.log.ForContext(ctx).Warn("Unauthorized answer accept attempt",
+			logger.String("post_id", postID),
+			logger.String("user_id", userID),
+			logger.String("post_author_id", post.AuthorID))
+		return nil, errors.New("unauthorized")
+	}
+
+	comment, err := uc.repo.GetByID(ctx, commentID)
+	if err != nil {
+		return nil, err
+	}
+	if comment.PostID != postID || comment.Status != entity.StatusPublished {
+		return nil, ErrCommentNotFound
+	}
+
+	// Снятие отметки с другого комментария ничего не меняет
+	if !accepted && post.AnswerID != commentID {
+		return comment, nil
+	}
+
+	answerID := ""
+	if accepted {
+		answerID = commentID
+	}
+	if err := uc.posts.SetAcceptedAnswer(ctx, postID, answerID); err != nil {
+		uc.log.ForContext(ctx).Error("Failed to set accepted answer",
+			logger.String("post_id", postID),
+			logger.Error(err))
+		return nil, err
+	}
+
+	if post.AnswerID != "" && post.AnswerID != answerID {
+		previous, err := uc.repo.GetByID(ctx, post.AnswerID)
+		if err != nil {
+			return nil, err
+		}
+		if err := uc.answerKarma(ctx, post, previous, false); err != nil {
+			return nil, err
+		}
+	}
+	if accepted {
+		if err := uc.answerKarma(ctx, post, comment, true); err != nil {
+			return nil, err
+		}
+	}
+	comment.IsAccepted = accepted
+
+	uc.log.ForContext(ctx).Info("Successfully accepted answer",
+		logger.String("post_id", postID),
+		logger.String("comment_id", answerID))
+
+	if accepted && post.AnswerID != commentID {
+		uc.events.Publish(ctx, events.Event{
+			Type:     events.AnswerAccepted,
+			TenantID: tenant.FromContext(ctx),
+			ID:       commentID,
+			Post:     post,
+			Comment:  comment,
+		})
+	}
+
+	return comment, nil
+}
+
+// answerKarma начисляет или снимает карму автору принятого ответа. За ответ на собственный пост
+// карма не начисляется.
+func (uc *CommentUseCase) answerKarma(ctx context.Context, post *entity.Post, comment *entity.Comment, accepted bool) error {
+	if uc.karma == nil || comment.AuthorID == post.AuthorID {
+		return nil
+	}
+
+	if err := uc.karma.AnswerAccepted(ctx, comment.AuthorID, comment.ID, accepted); err != nil {
+		uc.log.ForContext(ctx).Error("Failed to update answer author karma",
+			logger.String("comment_id", comment.ID),
+			logger.String("author_id", comment.AuthorID),
+			logger.Error(err))
+		return err
+	}
+	return nil
+}
+
+// clearAnswer снимает с поста отметку принятого ответа, если удален именно он
+func (uc *CommentUseCase) clearAnswer(ctx context.Context, comment *entity.Comment) error {
+	post, err := uc.posts.GetByID(ctx, comment.PostID)
+	if err != nil || post.AnswerID != comment.ID {
+		return nil
+	}
+
+	if err := uc.posts.SetAcceptedAnswer(ctx, post.ID, ""); err != nil {
+		uc.log.ForContext(ctx).Error("Failed to clear accepted answer",
+			logger.String("post_id", post.ID),
+			logger.Error(err))
+		return err
+	}
+	return uc.answerKarma(ctx, post, comment, false)
+}
+
+// canModerate проверяет, модерирует ли пользователь категорию поста
+func (uc *CommentUseCase) canModerate(ctx context.Context, userID, postID string) bool {
+	if uc.moderators == nil {
+		return false
+	}
+
+	allowed, err := uc.moderators.CanModeratePost(ctx, userID, postID)
+	if err != nil {
+		uc.log.ForContext(ctx).Error("Failed to check moderator rights",
+			logger.String("user_id", userID),
+			logger.Error(err))
+		return false
+	}
+	return allowed
+}
+
+func (uc *CommentUseCase) publish(ctx context.Context, eventType events.Type, id string, comment *entity.Comment) {
+	uc.events.Publish(ctx, events.Event{
+		Type:     eventType,
+		TenantID: tenant.FromContext(ctx),
+		ID:       id,
+		Comment:  comment,
+	})
+}
+
+// loadAttachments заполняет вложения комментариев одним запросом
+func (uc *CommentUseCase) loadAttachments(ctx context.Context, comments ...*entity.Comment) error {
+	if uc.attachments == nil || len(comments) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(comments))
+	for i, comment := range comments {
+		ids[i] = comment.ID
+	}
+
+	attachments, err := uc.attachments.ForTargets(ctx, entity.AttachmentComment, ids)
+	if err != nil {
+		uc.log.ForContext(ctx).Error("Failed to get comment attachments",
+			logger.Error(err))
+		return err
+	}
+	for _, comment := range comments {
+		comment.Attachments = attachments[comment.ID]
+	}
+	return nil
+}
+
+// loadKarma заполняет карму авторов комментариев одним запросом
+func (uc *CommentUseCase) loadKarma(ctx context.Context, comments ...*entity.Comment) error {
+	if uc.karma == nil || len(comments) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(comments))
+	for i, comment := range comments {
+		ids[i] = comment.AuthorID
+	}
+
+	karma, err := uc.karma.ForUsers(ctx, ids)
+	if err != nil {
+		uc.log.ForContext(ctx).Error("Failed to get authors karma",
+			logger.Error(err))
+		return err
+	}
+	for _, comment := range comments {
+		comment.AuthorKarma = karma[comment.AuthorID]
+	}
+	return nil
+}
+
+// loadAuthors заполняет данные авторов комментариев одним запросом к auth сервису, добавляя уже загруженную карму
+func (uc *CommentUseCase) loadAuthors(ctx context.Context, comments ...*entity.Comment) {
+	ids := make([]string, len(comments))
+	for i, comment := range comments {
+		ids[i] = comment.AuthorID
+	}
+
+	authors := lookupAuthors(ctx, uc.authors, uc.log, ids)
+	for _, comment := range comments {
+		comment.Author = withKarma(authors[comment.AuthorID], comment.AuthorKarma)
+	}
+}
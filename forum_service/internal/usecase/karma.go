@@ -0,0 +1,133 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"regexp"
+
+	"github.com/kprf42/dolgova/forum_service/internal/entity"
+	"github.com/kprf42/dolgova/forum_service/internal/repository"
+	"github.com/kprf42/dolgova/pkg/logger"
+)
+
+// ErrNotEnoughKarma у автора недостаточно кармы для действия
+var ErrNotEnoughKarma = errors.New("not enough karma")
+
+// Очки кармы, которые получает автор
+const (
+	KarmaPostUpvote      = 5
+	KarmaPostDownvote    = -2
+	KarmaCommentUpvote   = 2
+	KarmaCommentDownvote = -1
+	KarmaAcceptedAnswer  = 15
+)
+
+// linkPattern ссылки в тексте поста или комментария
+var linkPattern = regexp.MustCompile(`(?i)\bhttps?://|\bwww\.`)
+
+// Karma карма авторов для постов и комментариев
+type Karma interface {
+	// CheckLinks возвращает ErrNotEnoughKarma, если в texts есть ссылки, а карма userID ниже порога
+	CheckLinks(ctx context.Context, userID string, texts ...string) error
+	// ForUsers возвращает карму пользователей по ID
+	ForUsers(ctx context.Context, userIDs []string) (map[string]int, error)
+	// PostVoted начисляет автору поста карму за голос voterID
+	PostVoted(ctx context.Context, authorID, postID, voterID string, value int) error
+	// CommentVoted начисляет автору комментария карму за голос voterID
+	CommentVoted(ctx context.Context, authorID, commentID, voterID string, value int) error
+	// AnswerAccepted начисляет автору комментария карму за принятый ответ или снимает ее
+	AnswerAccepted(ctx context.Context, authorID, commentID string, accepted bool) error
+}
+
+type KarmaUseCase struct {
+	repo          *repository.KarmaRepository
+	linkThreshold func() int
+	log           *logger.Logger
+}
+
+// NewKarmaUseCase создает use case кармы. linkThreshold возвращает текущую карму,
+// с которой разрешены ссылки; 0 снимает ограничение.
+func NewKarmaUseCase(repo *repository.KarmaRepository, linkThreshold func() int, log *logger.Logger) *KarmaUseCase {
+	return &KarmaUseCase{
+		repo:          repo,
+		linkThreshold: linkThreshold,
+		log:           log,
+	}
+}
+
+// PostVoted начисляет автору поста карму за голос voterID: value > 0 - за, value < 0 - против,
+// 0 - голос снят. Повторный вызов для того же голоса заменяет прежнее начисление.
+// За голос за собственный пост карма не начисляется.
+func (uc *KarmaUseCase) PostVoted(ctx context.Context, authorID, postID, voterID string, value int) error {
+	return uc.voted(ctx, entity.KarmaPostVote, authorID, postID, voterID, value, KarmaPostUpvote, KarmaPostDownvote)
+}
+
+// CommentVoted как PostVoted, но для голоса за комментарий
+func (uc *KarmaUseCase) CommentVoted(ctx context.Context, authorID, commentID, voterID string, value int) error {
+	return uc.voted(ctx, entity.KarmaCommentVote, authorID, commentID, voterID, value, KarmaCommentUpvote, KarmaCommentDownvote)
+}
+
+// AnswerAccepted начисляет автору комментария карму за принятый ответ или снимает ее
+func (uc *KarmaUseCase) AnswerAccepted(ctx context.Context, authorID, commentID string, accepted bool) error {
+	points := 0
+	if accepted {
+		points = KarmaAcceptedAnswer
+	}
+	return uc.repo.Set(ctx, authorID, entity.KarmaAcceptedAnswer, commentID, points)
+}
+
+func (uc *KarmaUseCase) voted(ctx context.Context, source, authorID, targetID, voterID string, value, up, down int) error {
+	if authorID == voterID {
+		return nil
+	}
+
+	points := 0
+	switch {
+	case value > 0:
+		points = up
+	case value < 0:
+		points = down
+	}
+	// Один голос пользователя за цель - одно начисление
+	return uc.repo.Set(ctx, authorID, source, targetID+":"+voterID, points)
+}
+
+// Get возвращает карму пользователя
+func (uc *KarmaUseCase) Get(ctx context.Context, userID string) (int, error) {
+	return uc.repo.Get(ctx, userID)
+}
+
+func (uc *KarmaUseCase) ForUsers(ctx context.Context, userIDs []string) (map[string]int, error) {
+	return uc.repo.ForUsers(ctx, userIDs)
+}
+
+func (uc *KarmaUseCase) CheckLinks(ctx context.Context, userID string, texts ...string) error {
+	threshold := uc.linkThreshold()
+	if threshold <= 0 {
+		return nil
+	}
+
+	hasLinks := false
+	for _, text := range texts {
+		if linkPattern.MatchString(text) {
+			hasLinks = true
+			break
+		}
+	}
+	if !hasLinks {
+		return nil
+	}
+
+	karma, err := uc.repo.Get(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if karma < threshold {
+		uc.log.ForContext(ctx).Warn("Rejected links from user with low karma",
+			logger.String("user_id", userID),
+			logger.Int("karma", karma),
+			logger.Int("threshold", threshold))
+		return ErrNotEnoughKarma
+	}
+	return nil
+}
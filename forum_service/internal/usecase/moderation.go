@@ -0,0 +1,277 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"github.com/kprf42/dolgova/forum_service/internal/entity"
+	"github.com/kprf42/dolgova/forum_service/internal/events"
+	"github.com/kprf42/dolgova/forum_service/internal/repository"
+	"github.com/kprf42/dolgova/forum_service/internal/tenant"
+	"github.com/kprf42/dolgova/pkg/logger"
+)
+
+var (
+	ErrModerationNotFound = errors.New("content not found")
+	ErrNotPending         = errors.New("content is not pending moderation")
+)
+
+// StatusPolicy определяет статус, с которым сохраняется новый контент автора
+type StatusPolicy interface {
+	InitialStatus(ctx context.Context, authorID string) (string, error)
+}
+
+type ModerationUseCase struct {
+	repo      *repository.ModerationRepository
+	posts     *repository.PostRepository
+	comments  *repository.CommentRepository
+	access    *ModeratorAccess
+	threshold func() int
+	events    *events.Bus
+	log       *logger.Logger
+}
+
+// NewModerationUseCase создает use case премодерации. threshold возвращает текущее
+// число публикаций, которые должны пройти модерацию; читается при каждом вызове,
+// чтобы порог можно было менять без перезапуска.
+func NewModerationUseCase(
+	repo *repository.ModerationRepository,
+	posts *repository.PostRepository,
+	comments *repository.CommentRepository,
+	access *ModeratorAccess,
+	threshold func() int,
+	bus *events.Bus,
+	log *logger.Logger,
+) *ModerationUseCase {
+	return &ModerationUseCase{
+		repo:      repo,
+		posts:     posts,
+		comments:  comments,
+		access:    access,
+		threshold: threshold,
+		events:    bus,
+		log:       log,
+	}
+}
+
+// InitialStatus возвращает pending, пока у автора меньше threshold одобренных публикаций
+func (uc *ModerationUseCase) InitialStatus(ctx context.Context, authorID string) (string, error) {
+	threshold := uc.threshold()
+	if threshold <= 0 {
+		return entity.StatusPublished, nil
+	}
+
+	published, err := uc.repo.CountPublished(ctx, authorID)
+	if err != nil {
+		return "", err
+	}
+
+	if published < threshold {
+		uc.log.ForContext(ctx).Info("Content sent to premoderation",
+			logger.String("author_id", authorID),
+			logger.Int("published", published),
+			logger.Int("threshold", threshold))
+		return entity.StatusPending, nil
+	}
+	return entity.StatusPublished, nil
+}
+
+// Queue возвращает посты и комментарии, ожидающие модерации, в категориях модератора
+func (uc *ModerationUseCase) Queue(ctx context.Context, moderatorID string, limit, offset int) (*entity.ModerationQueue, error) {
+	categories, err := uc.access.Scope(ctx, moderatorID)
+	if err != nil {
+		return nil, err
+	}
+
+	posts, err := uc.repo.PendingPosts(ctx, categories, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	comments, err := uc.repo.PendingComments(ctx, categories, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	if posts == nil {
+		posts = []*entity.Post{}
+	}
+	if comments == nil {
+		comments = []*entity.Comment{}
+	}
+
+	return &entity.ModerationQueue{Posts: posts, Comments: comments}, nil
+}
+
+// ApprovePost публикует пост. Для остальных подписчиков это выглядит как создание поста.
+func (uc *ModerationUseCase) ApprovePost(ctx context.Context, id, moderatorID string) (*entity.Post, error) {
+	if err := uc.setPostStatus(ctx, id, moderatorID, entity.StatusPublished); err != nil {
+		return nil, err
+	}
+
+	post, err := uc.posts.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	uc.events.Publish(ctx, events.Event{
+		Type:     events.PostCreated,
+		TenantID: tenant.FromContext(ctx),
+		ID:       post.ID,
+		Post:     post,
+	})
+	return post, nil
+}
+
+// RejectPost отклоняет пост, он остается в базе, но не показывается
+func (uc *ModerationUseCase) RejectPost(ctx context.Context, id, moderatorID string) error {
+	return uc.setPostStatus(ctx, id, moderatorID, entity.StatusRejected)
+}
+
+// ApproveComment публикует комментарий
+func (uc *ModerationUseCase) ApproveComment(ctx context.Context, id, moderatorID string) (*entity.Comment, error) {
+	if err := uc.setCommentStatus(ctx, id, moderatorID, entity.StatusPublished); err != nil {
+		return nil, err
+	}
+
+	comment, err := uc.comments.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	uc.events.Publish(ctx, events.Event{
+		Type:     events.CommentCreated,
+		TenantID: tenant.FromContext(ctx),
+		ID:       comment.ID,
+		Comment:  comment,
+	})
+	return comment, nil
+}
+
+// RejectComment отклоняет комментарий
+func (uc *ModerationUseCase) RejectComment(ctx context.Context, id, moderatorID string) error {
+	return uc.setCommentStatus(ctx, id, moderatorID, entity.StatusRejected)
+}
+
+// PinPost закрепляет пост вверху списков (pinned) или открепляет его
+func (uc *ModerationUseCase) PinPost(ctx context.Context, id, moderatorID string, pinned bool) (*entity.Post, error) {
+	post, err := uc.posts.GetByID(ctx, id)
+	if err != nil || post.Status != entity.StatusPublished {
+		return nil, notFound(err, ErrModerationNotFound)
+	}
+	if err := uc.authorize(ctx, moderatorID, post.CategoryID); err != nil {
+		return nil, err
+	}
+
+	if post.IsPinned != pinned {
+		if err := uc.posts.SetPinned(ctx, id, pinned); err != nil {
+			return nil, err
+		}
+		post.IsPinned = pinned
+	}
+
+	uc.log.ForContext(ctx).Info("Post pin changed",
+		logger.String("post_id", id),
+		logger.String("moderator_id", moderatorID),
+		logger.Bool("pinned", pinned))
+	return post, nil
+}
+
+// LockPost закрывает тему поста для новых комментариев (locked) или открывает ее
+func (uc *ModerationUseCase) LockPost(ctx context.Context, id, moderatorID string, locked bool) (*entity.Post, error) {
+	post, err := uc.posts.GetByID(ctx, id)
+	if err != nil || post.Status != entity.StatusPublished {
+		return nil, notFound(err, ErrModerationNotFound)
+	}
+	if err := uc.authorize(ctx, moderatorID, post.CategoryID); err != nil {
+		return nil, err
+	}
+
+	if post.IsLocked != locked {
+		if err := uc.posts.SetLocked(ctx, id, locked); err != nil {
+			return nil, err
+		}
+		post.IsLocked = locked
+	}
+
+	uc.log.ForContext(ctx).Info("Post lock changed",
+		logger.String("post_id", id),
+		logger.String("moderator_id", moderatorID),
+		logger.Bool("locked", locked))
+	return post, nil
+}
+
+func (uc *ModerationUseCase) setPostStatus(ctx context.Context, id, moderatorID, status string) error {
+	post, err := uc.posts.GetByID(ctx, id)
+	if err != nil {
+		return notFound(err, ErrModerationNotFound)
+	}
+	if err := uc.authorize(ctx, moderatorID, post.CategoryID); err != nil {
+		return err
+	}
+
+	changed, err := uc.repo.SetPostStatus(ctx, id, entity.StatusPending, status)
+	if err != nil {
+		return err
+	}
+	if !changed {
+		return ErrNotPending
+	}
+
+	uc.log.ForContext(ctx).Info("Post moderated",
+		logger.String("post_id", id),
+		logger.String("moderator_id", moderatorID),
+		logger.String("status", status))
+	return nil
+}
+
+func (uc *ModerationUseCase) setCommentStatus(ctx context.Context, id, moderatorID, status string) error {
+	comment, err := uc.comments.GetByID(ctx, id)
+	if err != nil {
+		return notFound(err, ErrModerationNotFound)
+	}
+	post, err := uc.posts.GetByID(ctx, comment.PostID)
+	if err != nil {
+		return notFound(err, ErrModerationNotFound)
+	}
+	if err := uc.authorize(ctx, moderatorID, post.CategoryID); err != nil {
+		return err
+	}
+
+	changed, err := uc.repo.SetCommentStatus(ctx, id, entity.StatusPending, status)
+	if err != nil {
+		return err
+	}
+	if !changed {
+		return ErrNotPending
+	}
+
+	uc.log.ForContext(ctx).Info("Comment moderated",
+		logger.String("comment_id", id),
+		logger.String("moderator_id", moderatorID),
+		logger.String("status", status))
+	return nil
+}
+
+// notFound заменяет ошибку чтения на target, кроме истечения времени запроса:
+// оно не означает, что записи нет, и должно дойти до обработчика как есть
+func notFound(err, target error) error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+	return target
+}
+
+func (uc *ModerationUseCase) authorize(ctx context.Context, moderatorID, categoryID string) error {
+	allowed, err := uc.access.CanModerate(ctx, moderatorID, categoryID)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		uc.log.ForContext(ctx).Warn("Moderation outside assigned categories",
+			logger.String("moderator_id", moderatorID),
+			logger.String("category_id", categoryID))
+		return ErrForbidden
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"github.com/kprf42/dolgova/forum_service/internal/entity"
+	"github.com/kprf42/dolgova/forum_service/internal/repository"
+	"github.com/kprf42/dolgova/pkg/logger"
+)
+
+var (
+	ErrForbidden    = errors.New("forbidden") // У пользователя нет прав модератора для этого контента
+	ErrUserNotFound = errors.New("user not found")
+
+	ErrModeratorNotFound = repository.ErrModeratorNotFound
+)
+
+// RoleResolver возвращает глобальную роль пользователя
+type RoleResolver interface {
+	GetRole(ctx context.Context, userID string) (string, error)
+}
+
+// Moderators проверяет права модератора. Используется use case'ами постов и комментариев,
+// чтобы разрешить модератору действия над чужим контентом.
+type Moderators interface {
+	CanModerate(ctx context.Context, userID, categoryID string) (bool, error)
+	CanModeratePost(ctx context.Context, userID, postID string) (bool, error)
+}
+
+// ModeratorAccess права модератора: администраторы и пользователи с ролью moderator
+// модерируют весь форум, остальные - только назначенные им категории.
+type ModeratorAccess struct {
+	roles RoleResolver
+	repo  *repository.CategoryModeratorRepository
+	posts *repository.PostRepository
+	log   *logger.Logger
+}
+
+func NewModeratorAccess(roles RoleResolver, repo *repository.CategoryModeratorRepository, posts *repository.PostRepository, log *logger.Logger) *ModeratorAccess {
+	return &ModeratorAccess{
+		roles: roles,
+		repo:  repo,
+		posts: posts,
+		log:   log,
+	}
+}
+
+// CanModerate проверяет, может ли пользователь модерировать категорию
+func (a *ModeratorAccess) CanModerate(ctx context.Context, userID, categoryID string) (bool, error) {
+	global, err := a.isGlobal(ctx, userID)
+	if err != nil || global {
+		return global, err
+	}
+	return a.repo.IsModerator(ctx, userID, categoryID)
+}
+
+// CanModeratePost проверяет права на категорию поста
+func (a *ModeratorAccess) CanModeratePost(ctx context.Context, userID, postID string) (bool, error) {
+	post, err := a.posts.GetByID(ctx, postID)
+	if err != nil {
+		return false, err
+	}
+	return a.CanModerate(ctx, userID, post.CategoryID)
+}
+
+// Scope возвращает категории, доступные модератору; nil - весь форум.
+// Пользователь без прав получает ErrForbidden.
+func (a *ModeratorAccess) Scope(ctx context.Context, userID string) ([]string, error) {
+	global, err := a.isGlobal(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if global {
+		return nil, nil
+	}
+
+	categories, err := a.repo.CategoriesOf(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(categories) == 0 {
+		return nil, ErrForbidden
+	}
+	return categories, nil
+}
+
+// Assign назначает пользователя модератором категории
+func (a *ModeratorAccess) Assign(ctx context.Context, categoryID, userID, assignedBy string) (*entity.CategoryModerator, error) {
+	if _, err := a.roles.GetRole(ctx, userID); err != nil {
+		return nil, notFound(err, ErrUserNotFound)
+	}
+
+	m := &entity.CategoryModerator{
+		CategoryID: categoryID,
+		UserID:     userID,
+		CreatedBy:  assignedBy,
+	}
+	if err := a.repo.Assign(ctx, m); err != nil {
+		return nil, err
+	}
+
+	a.log.Info("Category moderator assigned",
+		logger.String("category_id", categoryID),
+		logger.String("user_id", userID),
+		logger.String("assigned_by", assignedBy))
+	return m, nil
+}
+
+// Remove снимает пользователя с модерации категории
+func (a *ModeratorAccess) Remove(ctx context.Context, categoryID, userID string) error {
+	return a.repo.Remove(ctx, categoryID, userID)
+}
+
+// List возвращает модераторов категории
+func (a *ModeratorAccess) List(ctx context.Context, categoryID string) ([]*entity.CategoryModerator, error) {
+	return a.repo.ListByCategory(ctx, categoryID)
+}
+
+func (a *ModeratorAccess) isGlobal(ctx context.Context, userID string) (bool, error) {
+	role, err := a.roles.GetRole(ctx, userID)
+	if errors.Is(err, context.DeadlineExceeded) {
+		return false, err
+	}
+	if err != nil {
+		// Неизвестный пользователь не может быть модератором
+		return false, nil
+	}
+	return role == "admin" || role == "moderator", nil
+}
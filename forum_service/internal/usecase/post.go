@@ -3,28 +3,77 @@ package usecase
 import (
 	"context"
 	"errors"
+	"fmt"
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/kprf42/dolgova/forum_service/internal/auth"
 	"github.com/kprf42/dolgova/forum_service/internal/entity"
+	"github.com/kprf42/dolgova/forum_service/internal/events"
 	"github.com/kprf42/dolgova/forum_service/internal/repository"
+	"github.com/kprf42/dolgova/forum_service/internal/tenant"
 	"github.com/kprf42/dolgova/pkg/logger"
 )
 
+var (
+	// ErrInvalidUpdateMask в маске обновления есть поле, которое нельзя менять
+	ErrInvalidUpdateMask = errors.New("invalid update mask")
+	// ErrInvalidVote голос должен быть 1, -1 или 0
+	ErrInvalidVote = errors.New("invalid vote")
+	// ErrInvalidSort неизвестный порядок списка постов или окно сортировки top
+	ErrInvalidSort = errors.New("invalid sort")
+)
+
+// postWindows окна сортировки top; 0 - за все время
+var postWindows = map[string]time.Duration{
+	entity.PostWindowHour:  time.Hour,
+	entity.PostWindowDay:   24 * time.Hour,
+	entity.PostWindowWeek:  7 * 24 * time.Hour,
+	entity.PostWindowMonth: 30 * 24 * time.Hour,
+	entity.PostWindowYear:  365 * 24 * time.Hour,
+	entity.PostWindowAll:   0,
+}
+
 type PostUseCase struct {
-	postRepo *repository.PostRepository
-	log      *logger.Logger
+	postRepo    *repository.PostRepository
+	categories  Categories
+	policy      StatusPolicy
+	moderators  Moderators
+	attachments Attachments
+	karma       Karma
+	reads       ReadMarks
+	authors     Authors
+	events      *events.Bus
+	views       *viewDebouncer
+	log         *logger.Logger
 }
 
-func NewPostUseCase(postRepo *repository.PostRepository, log *logger.Logger) *PostUseCase {
+// NewPostUseCase создает use case постов. bus может быть nil, тогда события не публикуются.
+// categories может быть nil, тогда категория поста не проверяется (импорт);
+// policy может быть nil, тогда посты публикуются сразу; moderators может быть nil,
+// тогда удалять пост может только автор; attachments может быть nil, тогда вложения не поддерживаются;
+// karma может быть nil, тогда ссылки не ограничиваются, а карма авторов не заполняется;
+// reads может быть nil, тогда признак непрочитанного поста не заполняется;
+// authors может быть nil, тогда данные авторов не заполняются.
+func NewPostUseCase(postRepo *repository.PostRepository, categories Categories, policy StatusPolicy, moderators Moderators, attachments Attachments, karma Karma, reads ReadMarks, authors Authors, bus *events.Bus, log *logger.Logger) *PostUseCase {
 	return &PostUseCase{
-		postRepo: postRepo,
-		log:      log,
+		postRepo:    postRepo,
+		categories:  categories,
+		policy:      policy,
+		moderators:  moderators,
+		attachments: attachments,
+		karma:       karma,
+		reads:       reads,
+		authors:     authors,
+		events:      bus,
+		views:       newViewDebouncer(viewWindow),
+		log:         log,
 	}
 }
 
 func (uc *PostUseCase) Create(ctx context.Context, req *entity.PostRequest, authorID string) (*entity.PostResponse, error) {
-	uc.log.Info("Creating new post",
+	uc.log.ForContext(ctx).Info("Creating new post",
 		logger.String("title", req.Title),
 		logger.String("author_id", authorID),
 		logger.String("category_id", req.CategoryID))
@@ -36,76 +85,212 @@ func (uc *PostUseCase) Create(ctx context.Context, req *entity.PostRequest, auth
 		AuthorID:   authorID,
 		CategoryID: req.CategoryID,
 		IsPinned:   false,
+		Status:     entity.StatusPublished,
 		CreatedAt:  time.Now(),
 	}
 
-	uc.log.Debug("Generated post details",
+	if uc.categories != nil {
+		if err := uc.categories.Check(ctx, req.CategoryID); err != nil {
+			return nil, err
+		}
+	}
+
+	if uc.policy != nil {
+		status, err := uc.policy.InitialStatus(ctx, authorID)
+		if err != nil {
+			uc.log.ForContext(ctx).Error("Failed to resolve post status",
+				logger.String("author_id", authorID),
+				logger.Error(err))
+			return nil, err
+		}
+		post.Status = status
+	}
+
+	if uc.attachments != nil {
+		if err := uc.attachments.Check(ctx, authorID, req.AttachmentIDs); err != nil {
+			return nil, err
+		}
+	}
+
+	if uc.karma != nil {
+		if err := uc.karma.CheckLinks(ctx, authorID, req.Title, req.Content); err != nil {
+			return nil, err
+		}
+	}
+
+	uc.log.ForContext(ctx).Debug("Generated post details",
 		logger.String("post_id", post.ID),
 		logger.String("title", post.Title))
 
-	if err := uc.postRepo.Create(ctx, post); err != nil {
-		uc.log.Error("Failed to create post",
+	if err := uc.createWithSlug(ctx, post); err != nil {
+		uc.log.ForContext(ctx).Error("Failed to create post",
+			logger.String("post_id", post.ID),
+			logger.Error(err))
+		return nil, err
+	}
+
+	uc.log.ForContext(ctx).Info("Successfully created post",
+		logger.String("post_id", post.ID),
+		logger.String("status", post.Status))
+
+	var attachments []*entity.Attachment
+	if uc.attachments != nil {
+		var err error
+		attachments, err = uc.attachments.Attach(ctx, authorID, entity.AttachmentPost, post.ID, req.AttachmentIDs)
+		if err != nil {
+			uc.log.ForContext(ctx).Error("Failed to attach files to post",
+				logger.String("post_id", post.ID),
+				logger.Error(err))
+			return nil, err
+		}
+	}
+
+	// Пост на премодерации становится видимым только после одобрения
+	if post.Status == entity.StatusPublished {
+		uc.publish(ctx, events.PostCreated, post.ID, post)
+	}
+
+	return &entity.PostResponse{
+		ID:          post.ID,
+		Slug:        post.Slug,
+		Title:       post.Title,
+		Content:     post.Content,
+		AuthorID:    post.AuthorID,
+		CategoryID:  post.CategoryID,
+		IsPinned:    post.IsPinned,
+		IsLocked:    post.IsLocked,
+		Score:       post.Score,
+		Status:      post.Status,
+		CreatedAt:   post.CreatedAt,
+		AnswerID:    post.AnswerID,
+		Attachments: attachments,
+	}, nil
+}
+
+// Import сохраняет пост из внешнего источника с исходной датой создания
+func (uc *PostUseCase) Import(ctx context.Context, post *entity.Post) (*entity.PostResponse, error) {
+	if post.Title == "" || post.Content == "" {
+		return nil, errors.New("title and content are required")
+	}
+
+	post.ID = uuid.New().String()
+	if post.CreatedAt.IsZero() {
+		post.CreatedAt = time.Now()
+	}
+	if post.Status == "" {
+		post.Status = entity.StatusPublished
+	}
+
+	if err := uc.createWithSlug(ctx, post); err != nil {
+		uc.log.ForContext(ctx).Error("Failed to import post",
 			logger.String("post_id", post.ID),
 			logger.Error(err))
 		return nil, err
 	}
 
-	uc.log.Info("Successfully created post",
-		logger.String("post_id", post.ID))
+	if post.Status == entity.StatusPublished {
+		uc.publish(ctx, events.PostCreated, post.ID, post)
+	}
 
 	return &entity.PostResponse{
 		ID:         post.ID,
+		Slug:       post.Slug,
 		Title:      post.Title,
 		Content:    post.Content,
 		AuthorID:   post.AuthorID,
 		CategoryID: post.CategoryID,
 		IsPinned:   post.IsPinned,
+		IsLocked:   post.IsLocked,
+		Score:      post.Score,
+		Status:     post.Status,
 		CreatedAt:  post.CreatedAt,
+		AnswerID:   post.AnswerID,
 	}, nil
 }
 
 func (uc *PostUseCase) GetByID(ctx context.Context, id string) (*entity.PostResponse, error) {
-	uc.log.Info("Getting post by ID",
+	uc.log.ForContext(ctx).Info("Getting post by ID",
 		logger.String("post_id", id))
 
 	post, err := uc.postRepo.GetByID(ctx, id)
 	if err != nil {
-		uc.log.Error("Failed to get post",
+		uc.log.ForContext(ctx).Error("Failed to get post",
 			logger.String("post_id", id),
 			logger.Error(err))
 		return nil, err
 	}
 
-	uc.log.Info("Successfully got post",
+	if post.Status != entity.StatusPublished {
+		uc.log.ForContext(ctx).Warn("Post is not published",
+			logger.String("post_id", id),
+			logger.String("status", post.Status))
+		return nil, errors.New("post not found")
+	}
+
+	// Просмотр учитывается в популярных постах; без счетчика пост все равно отдается
+	if err := uc.countView(ctx, id, post.AuthorID); err != nil {
+		uc.log.ForContext(ctx).Warn("Failed to count post view",
+			logger.String("post_id", id),
+			logger.Error(err))
+	}
+
+	uc.log.ForContext(ctx).Info("Successfully got post",
 		logger.String("post_id", id))
 
-	return &entity.PostResponse{
+	response := &entity.PostResponse{
 		ID:         post.ID,
+		Slug:       post.Slug,
 		Title:      post.Title,
 		Content:    post.Content,
 		AuthorID:   post.AuthorID,
 		CategoryID: post.CategoryID,
 		IsPinned:   post.IsPinned,
+		IsLocked:   post.IsLocked,
+		Score:      post.Score,
+		Status:     post.Status,
 		CreatedAt:  post.CreatedAt,
-	}, nil
+		AnswerID:   post.AnswerID,
+	}
+	if err := uc.loadAttachments(ctx, response); err != nil {
+		return nil, err
+	}
+	if err := uc.loadKarma(ctx, response); err != nil {
+		return nil, err
+	}
+	uc.loadAuthors(ctx, response)
+	return response, nil
 }
 
-func (uc *PostUseCase) GetAll(ctx context.Context, limit, offset int, categoryID string) ([]*entity.PostResponse, int, error) {
-	uc.log.Info("Getting all posts",
+// GetBySlug возвращает опубликованный пост по slug
+func (uc *PostUseCase) GetBySlug(ctx context.Context, slug string) (*entity.PostResponse, error) {
+	id, err := uc.postRepo.IDBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	return uc.GetByID(ctx, id)
+}
+
+// GetAll возвращает страницу постов: по offset или, если задан after, после курсора
+func (uc *PostUseCase) GetAll(ctx context.Context, limit, offset int, after *entity.PageCursor, filter entity.PostFilter) ([]*entity.PostResponse, int, error) {
+	uc.log.ForContext(ctx).Info("Getting all posts",
 		logger.Int("limit", limit),
 		logger.Int("offset", offset),
-		logger.String("category_id", categoryID))
+		logger.String("category_id", filter.CategoryID))
+
+	if err := applyPostSort(&filter, time.Now()); err != nil {
+		return nil, 0, err
+	}
 
-	posts, err := uc.postRepo.GetAll(ctx, limit, offset, categoryID)
+	posts, err := uc.postRepo.GetAll(ctx, limit, offset, after, filter)
 	if err != nil {
-		uc.log.Error("Failed to get posts",
+		uc.log.ForContext(ctx).Error("Failed to get posts",
 			logger.Error(err))
 		return nil, 0, err
 	}
 
-	total, err := uc.postRepo.Count(ctx, categoryID)
+	total, err := uc.postRepo.Count(ctx, filter)
 	if err != nil {
-		uc.log.Error("Failed to count posts",
+		uc.log.ForContext(ctx).Error("Failed to count posts",
 			logger.Error(err))
 		return nil, 0, err
 	}
@@ -114,45 +299,133 @@ func (uc *PostUseCase) GetAll(ctx context.Context, limit, offset int, categoryID
 	for _, post := range posts {
 		responses = append(responses, &entity.PostResponse{
 			ID:         post.ID,
+			Slug:       post.Slug,
 			Title:      post.Title,
 			Content:    post.Content,
 			AuthorID:   post.AuthorID,
 			CategoryID: post.CategoryID,
 			IsPinned:   post.IsPinned,
+			IsLocked:   post.IsLocked,
+			Score:      post.Score,
+			Status:     post.Status,
 			CreatedAt:  post.CreatedAt,
+			AnswerID:   post.AnswerID,
 		})
 	}
 
-	uc.log.Info("Successfully got posts",
+	if err := uc.loadAttachments(ctx, responses...); err != nil {
+		return nil, 0, err
+	}
+	if err := uc.loadKarma(ctx, responses...); err != nil {
+		return nil, 0, err
+	}
+	if err := uc.loadUnread(ctx, responses...); err != nil {
+		return nil, 0, err
+	}
+	uc.loadAuthors(ctx, responses...)
+
+	uc.log.ForContext(ctx).Info("Successfully got posts",
 		logger.Int("count", len(responses)),
 		logger.Int("total", total))
 
 	return responses, total, nil
 }
 
+// GetByAuthor возвращает страницу опубликованных постов автора от новых к старым для его профиля
+func (uc *PostUseCase) GetByAuthor(ctx context.Context, authorID string, limit, offset int) ([]*entity.PostResponse, int, error) {
+	posts, err := uc.postRepo.GetByAuthor(ctx, authorID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := uc.postRepo.CountByAuthor(ctx, authorID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	responses := make([]*entity.PostResponse, 0, len(posts))
+	for _, post := range posts {
+		responses = append(responses, &entity.PostResponse{
+			ID:         post.ID,
+			Slug:       post.Slug,
+			Title:      post.Title,
+			Content:    post.Content,
+			AuthorID:   post.AuthorID,
+			CategoryID: post.CategoryID,
+			IsPinned:   post.IsPinned,
+			IsLocked:   post.IsLocked,
+			Score:      post.Score,
+			Status:     post.Status,
+			CreatedAt:  post.CreatedAt,
+			AnswerID:   post.AnswerID,
+		})
+	}
+
+	if err := uc.loadAttachments(ctx, responses...); err != nil {
+		return nil, 0, err
+	}
+	if err := uc.loadKarma(ctx, responses...); err != nil {
+		return nil, 0, err
+	}
+	if err := uc.loadUnread(ctx, responses...); err != nil {
+		return nil, 0, err
+	}
+	uc.loadAuthors(ctx, responses...)
+
+	return responses, total, nil
+}
+
 func (uc *PostUseCase) Update(ctx context.Context, id string, req *entity.PostUpdate, authorID string) (*entity.PostResponse, error) {
-	uc.log.Info("Updating post",
+	uc.log.ForContext(ctx).Info("Updating post",
 		logger.String("post_id", id),
-		logger.String("author_id", authorID))
+		logger.String("author_id", authorID),
+		logger.Strings("update_mask", req.UpdateMask))
+
+	if err := validateUpdateMask(req.UpdateMask, "title", "content"); err != nil {
+		uc.log.ForContext(ctx).Warn("Invalid post update mask",
+			logger.String("post_id", id),
+			logger.Strings("update_mask", req.UpdateMask))
+		return nil, err
+	}
 
 	post, err := uc.postRepo.GetByID(ctx, id)
 	if err != nil {
-		uc.log.Error("Failed to get post for update",
+		uc.log.ForContext(ctx).Error("Failed to get post for update",
 			logger.String("post_id", id),
 			logger.Error(err))
 		return nil, err
 	}
 
 	if post.AuthorID != authorID {
-		uc.log.Warn("Unauthorized post update attempt",
+		uc.log.ForContext(ctx).Warn("Unauthorized post update attempt",
 			logger.String("post_id", id),
 			logger.String("author_id", authorID),
 			logger.String("post_author_id", post.AuthorID))
 		return nil, errors.New("unauthorized")
 	}
 
-	if err := uc.postRepo.Update(ctx, id, req); err != nil {
-		uc.log.Error("Failed to update post",
+	// Поля вне маски сохраняют текущие значения
+	update := *req
+	if len(req.UpdateMask) > 0 {
+		update.Title, update.Content = post.Title, post.Content
+		for _, path := range req.UpdateMask {
+			switch path {
+			case "title":
+				update.Title = req.Title
+			case "content":
+				update.Content = req.Content
+			}
+		}
+	}
+
+	if uc.karma != nil {
+		if err := uc.karma.CheckLinks(ctx, authorID, update.Title, update.Content); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := uc.postRepo.Update(ctx, id, &update); err != nil {
+		uc.log.ForContext(ctx).Error("Failed to update post",
 			logger.String("post_id", id),
 			logger.Error(err))
 		return nil, err
@@ -160,41 +433,48 @@ func (uc *PostUseCase) Update(ctx context.Context, id string, req *entity.PostUp
 
 	updatedPost, err := uc.postRepo.GetByID(ctx, id)
 	if err != nil {
-		uc.log.Error("Failed to get updated post",
+		uc.log.ForContext(ctx).Error("Failed to get updated post",
 			logger.String("post_id", id),
 			logger.Error(err))
 		return nil, err
 	}
 
-	uc.log.Info("Successfully updated post",
+	uc.log.ForContext(ctx).Info("Successfully updated post",
 		logger.String("post_id", id))
 
+	uc.publish(ctx, events.PostUpdated, id, updatedPost)
+
 	return &entity.PostResponse{
 		ID:         updatedPost.ID,
+		Slug:       updatedPost.Slug,
 		Title:      updatedPost.Title,
 		Content:    updatedPost.Content,
 		AuthorID:   updatedPost.AuthorID,
 		CategoryID: updatedPost.CategoryID,
 		IsPinned:   updatedPost.IsPinned,
+		IsLocked:   updatedPost.IsLocked,
+		Score:      updatedPost.Score,
+		Status:     updatedPost.Status,
 		CreatedAt:  updatedPost.CreatedAt,
+		AnswerID:   updatedPost.AnswerID,
 	}, nil
 }
 
 func (uc *PostUseCase) Delete(ctx context.Context, id string, authorID string) error {
-	uc.log.Info("Deleting post",
+	uc.log.ForContext(ctx).Info("Deleting post",
 		logger.String("post_id", id),
 		logger.String("author_id", authorID))
 
 	post, err := uc.postRepo.GetByID(ctx, id)
 	if err != nil {
-		uc.log.Error("Failed to get post for deletion",
+		uc.log.ForContext(ctx).Error("Failed to get post for deletion",
 			logger.String("post_id", id),
 			logger.Error(err))
 		return err
 	}
 
-	if post.AuthorID != authorID {
-		uc.log.Warn("Unauthorized post deletion attempt",
+	if post.AuthorID != authorID && !uc.canModerate(ctx, authorID, post.CategoryID) {
+		uc.log.ForContext(ctx).Warn("Unauthorized post deletion attempt",
 			logger.String("post_id", id),
 			logger.String("author_id", authorID),
 			logger.String("post_author_id", post.AuthorID))
@@ -202,14 +482,205 @@ func (uc *PostUseCase) Delete(ctx context.Context, id string, authorID string) e
 	}
 
 	if err := uc.postRepo.Delete(ctx, id); err != nil {
-		uc.log.Error("Failed to delete post",
+		uc.log.ForContext(ctx).Error("Failed to delete post",
 			logger.String("post_id", id),
 			logger.Error(err))
 		return err
 	}
 
-	uc.log.Info("Successfully deleted post",
+	uc.log.ForContext(ctx).Info("Successfully deleted post",
 		logger.String("post_id", id))
 
+	uc.publish(ctx, events.PostDeleted, id, nil)
+
+	return nil
+}
+
+// Vote сохраняет голос userID за опубликованный пост (1 - за, -1 - против, 0 - снять голос)
+// и начисляет карму автору. Повторный такой же голос ничего не меняет.
+func (uc *PostUseCase) Vote(ctx context.Context, postID, userID string, value int) (*entity.PostVote, error) {
+	if value < -1 || value > 1 {
+		return nil, ErrInvalidVote
+	}
+
+	post, err := uc.postRepo.GetByID(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+	if post.Status != entity.StatusPublished {
+		return nil, errors.New("post not found")
+	}
+
+	score, err := uc.postRepo.Vote(ctx, postID, userID, value)
+	if err != nil {
+		uc.log.ForContext(ctx).Error("Failed to vote for post",
+			logger.String("post_id", postID),
+			logger.String("user_id", userID),
+			logger.Error(err))
+		return nil, err
+	}
+
+	// Начисление по голосу идемпотентно, поэтому при ошибке повторный голос его восстановит
+	if uc.karma != nil {
+		if err := uc.karma.PostVoted(ctx, post.AuthorID, postID, userID, value); err != nil {
+			uc.log.ForContext(ctx).Error("Failed to update author karma",
+				logger.String("post_id", postID),
+				logger.String("author_id", post.AuthorID),
+				logger.Error(err))
+			return nil, err
+		}
+	}
+
+	return &entity.PostVote{PostID: postID, Score: score, Vote: value}, nil
+}
+
+// validateUpdateMask проверяет, что маска содержит только разрешенные поля
+func validateUpdateMask(mask []string, allowed ...string) error {
+	for _, path := range mask {
+		if !slices.Contains(allowed, path) {
+			return fmt.Errorf("%w: field %q cannot be updated", ErrInvalidUpdateMask, path)
+		}
+	}
+	return nil
+}
+
+// canModerate проверяет, может ли пользователь как модератор менять чужие посты в категории
+func (uc *PostUseCase) canModerate(ctx context.Context, userID, categoryID string) bool {
+	if uc.moderators == nil {
+		return false
+	}
+
+	allowed, err := uc.moderators.CanModerate(ctx, userID, categoryID)
+	if err != nil {
+		uc.log.ForContext(ctx).Error("Failed to check moderator rights",
+			logger.String("user_id", userID),
+			logger.Error(err))
+		return false
+	}
+	return allowed
+}
+
+// applyPostSort проверяет порядок и окно фильтра и переводит окно top в filter.Since
+// относительно now. Сортировка score - top за все время.
+func applyPostSort(filter *entity.PostFilter, now time.Time) error {
+	switch filter.Sort {
+	case "", entity.PostSortNew, entity.PostSortHot:
+		if filter.Window != "" {
+			return ErrInvalidSort
+		}
+		return nil
+	case entity.PostSortScore:
+		if filter.Window != "" && filter.Window != entity.PostWindowAll {
+			return ErrInvalidSort
+		}
+		filter.Sort, filter.Window = entity.PostSortTop, entity.PostWindowAll
+		return nil
+	case entity.PostSortTop:
+		if filter.Window == "" {
+			filter.Window = entity.PostWindowDay
+		}
+		window, ok := postWindows[filter.Window]
+		if !ok {
+			return ErrInvalidSort
+		}
+		if window > 0 {
+			filter.Since = now.Add(-window)
+		}
+		return nil
+	default:
+		return ErrInvalidSort
+	}
+}
+
+func (uc *PostUseCase) publish(ctx context.Context, eventType events.Type, id string, post *entity.Post) {
+	uc.events.Publish(ctx, events.Event{
+		Type:     eventType,
+		TenantID: tenant.FromContext(ctx),
+		ID:       id,
+		Post:     post,
+	})
+}
+
+// loadAttachments заполняет вложения постов одним запросом
+func (uc *PostUseCase) loadAttachments(ctx context.Context, posts ...*entity.PostResponse) error {
+	if uc.attachments == nil || len(posts) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(posts))
+	for i, post := range posts {
+		ids[i] = post.ID
+	}
+
+	attachments, err := uc.attachments.ForTargets(ctx, entity.AttachmentPost, ids)
+	if err != nil {
+		uc.log.ForContext(ctx).Error("Failed to get post attachments",
+			logger.Error(err))
+		return err
+	}
+	for _, post := range posts {
+		post.Attachments = attachments[post.ID]
+	}
+	return nil
+}
+
+// loadKarma заполняет карму авторов постов одним запросом
+func (uc *PostUseCase) loadKarma(ctx context.Context, posts ...*entity.PostResponse) error {
+	if uc.karma == nil || len(posts) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(posts))
+	for i, post := range posts {
+		ids[i] = post.AuthorID
+	}
+
+	karma, err := uc.karma.ForUsers(ctx, ids)
+	if err != nil {
+		uc.log.ForContext(ctx).Error("Failed to get authors karma",
+			logger.Error(err))
+		return err
+	}
+	for _, post := range posts {
+		post.AuthorKarma = karma[post.AuthorID]
+	}
+	return nil
+}
+
+// loadAuthors заполняет данные авторов постов одним запросом к auth сервису, добавляя уже загруженную карму
+func (uc *PostUseCase) loadAuthors(ctx context.Context, posts ...*entity.PostResponse) {
+	ids := make([]string, len(posts))
+	for i, post := range posts {
+		ids[i] = post.AuthorID
+	}
+
+	authors := lookupAuthors(ctx, uc.authors, uc.log, ids)
+	for _, post := range posts {
+		post.Author = withKarma(authors[post.AuthorID], post.AuthorKarma)
+	}
+}
+
+// loadUnread отмечает посты с новой активностью для аутентифицированного читателя
+func (uc *PostUseCase) loadUnread(ctx context.Context, posts ...*entity.PostResponse) error {
+	userID, ok := auth.UserIDFromContext(ctx)
+	if uc.reads == nil || !ok || len(posts) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(posts))
+	for i, post := range posts {
+		ids[i] = post.ID
+	}
+
+	unread, err := uc.reads.Unread(ctx, userID, ids)
+	if err != nil {
+		uc.log.ForContext(ctx).Error("Failed to get unread posts",
+			logger.Error(err))
+		return err
+	}
+	for _, post := range posts {
+		isUnread := unread[post.ID]
+		post.IsUnread = &isUnread
+	}
 	return nil
 }
@@ -0,0 +1,259 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/kprf42/dolgova/forum_service/internal/auth"
+	"github.com/kprf42/dolgova/forum_service/internal/entity"
+	"github.com/kprf42/dolgova/forum_service/internal/repository"
+	"github.com/kprf42/dolgova/forum_service/migrations"
+	"github.com/kprf42/dolgova/pkg/logger"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestApplyPostSort(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		filter entity.PostFilter
+		want   entity.PostFilter
+		err    error
+	}{
+		{name: "default", filter: entity.PostFilter{}, want: entity.PostFilter{}},
+		{name: "new", filter: entity.PostFilter{Sort: "new"}, want: entity.PostFilter{Sort: "new"}},
+		{name: "hot", filter: entity.PostFilter{Sort: "hot"}, want: entity.PostFilter{Sort: "hot"}},
+		{
+			name:   "top defaults to day",
+			filter: entity.PostFilter{Sort: "top"},
+			want:   entity.PostFilter{Sort: "top", Window: "day", Since: now.Add(-24 * time.Hour)},
+		},
+		{
+			name:   "top week",
+			filter: entity.PostFilter{Sort: "top", Window: "week"},
+			want:   entity.PostFilter{Sort: "top", Window: "week", Since: now.Add(-7 * 24 * time.Hour)},
+		},
+		{
+			name:   "top all time",
+			filter: entity.PostFilter{Sort: "top", Window: "all"},
+			want:   entity.PostFilter{Sort: "top", Window: "all"},
+		},
+		{
+			name:   "score is top all time",
+			filter: entity.PostFilter{Sort: "score"},
+			want:   entity.PostFilter{Sort: "top", Window: "all"},
+		},
+		{name: "unknown sort", filter: entity.PostFilter{Sort: "best"}, err: ErrInvalidSort},
+		{name: "unknown window", filter: entity.PostFilter{Sort: "top", Window: "decade"}, err: ErrInvalidSort},
+		{name: "window without top", filter: entity.PostFilter{Sort: "hot", Window: "day"}, err: ErrInvalidSort},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := tt.filter
+			err := applyPostSort(&filter, now)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("error %v, want %v", err, tt.err)
+			}
+			if tt.err == nil && filter != tt.want {
+				t.Fatalf("filter %+v, want %+v", filter, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetAllRanking(t *testing.T) {
+	ctx := context.Background()
+	uc, db := newTestPostUseCase(t)
+
+	// Посты с возрастом в часах и суммой голосов. Время создания со смещением, как на сервере
+	// не в UTC: окно top должно сравнивать моменты времени, а не строки.
+	zone := time.FixedZone("UTC-8", -8*60*60)
+	posts := []struct {
+		id    string
+		age   int
+		score int
+	}{
+		{"old-popular", 10 * 24, 40},
+		{"day-old", 20, 10},
+		{"fresh", 1, 0},
+		{"rising", 3, 5},
+		{"downvoted", 2, -3},
+	}
+	for _, p := range posts {
+		post := &entity.Post{
+			ID:         p.id,
+			Title:      p.id,
+			Content:    "content of " + p.id,
+			AuthorID:   "author",
+			CategoryID: "1",
+			Status:     entity.StatusPublished,
+			CreatedAt:  time.Now().In(zone).Add(-time.Duration(p.age) * time.Hour),
+		}
+		if err := uc.postRepo.Create(ctx, post); err != nil {
+			t.Fatalf("create post %s: %v", p.id, err)
+		}
+		if _, err := db.Exec(`UPDATE posts SET score = ? WHERE id = ?`, p.score, p.id); err != nil {
+			t.Fatalf("set score of %s: %v", p.id, err)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		filter entity.PostFilter
+		want   []string
+	}{
+		{
+			name:   "new",
+			filter: entity.PostFilter{Sort: "new"},
+			want:   []string{"fresh", "downvoted", "rising", "day-old", "old-popular"},
+		},
+		{
+			name:   "top of the day",
+			filter: entity.PostFilter{Sort: "top"},
+			want:   []string{"day-old", "rising", "fresh", "downvoted"},
+		},
+		{
+			name:   "top of all time",
+			filter: entity.PostFilter{Sort: "top", Window: "all"},
+			want:   []string{"old-popular", "day-old", "rising", "fresh", "downvoted"},
+		},
+		{
+			// Свежий пост без голосов выше старых популярных, отрицательный счет опускает пост вниз
+			name:   "hot",
+			filter: entity.PostFilter{Sort: "hot"},
+			want:   []string{"rising", "fresh", "day-old", "old-popular", "downvoted"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, total, err := uc.GetAll(ctx, 10, 0, nil, tt.filter)
+			if err != nil {
+				t.Fatalf("get posts: %v", err)
+			}
+			ids := make([]string, len(got))
+			for i, post := range got {
+				ids[i] = post.ID
+			}
+			if !slices.Equal(ids, tt.want) {
+				t.Fatalf("order %v, want %v", ids, tt.want)
+			}
+			if total != len(tt.want) {
+				t.Fatalf("total %d, want %d", total, len(tt.want))
+			}
+		})
+	}
+}
+
+func TestGetByIDCountsViews(t *testing.T) {
+	ctx := context.Background()
+	uc, db := newTestPostUseCase(t)
+
+	post := &entity.Post{
+		ID:         "post",
+		Title:      "post",
+		Content:    "content",
+		AuthorID:   "author",
+		CategoryID: "1",
+		Status:     entity.StatusPublished,
+		CreatedAt:  time.Now(),
+	}
+	if err := uc.postRepo.Create(ctx, post); err != nil {
+		t.Fatalf("create post: %v", err)
+	}
+
+	user := func(id string) context.Context { return auth.WithUserID(ctx, id) }
+	guest := func(ip string) context.Context { return auth.WithClientIP(ctx, ip) }
+	reads := []struct {
+		name string
+		ctx  context.Context
+		want int // просмотров после чтения
+	}{
+		{"first read", user("alice"), 1},
+		{"reload by same user", user("alice"), 1},
+		{"another user", user("bob"), 2},
+		{"author", user("author"), 2},
+		{"guest", guest("10.0.0.1"), 3},
+		{"reload by same guest", guest("10.0.0.1"), 3},
+		{"user from guest address", auth.WithClientIP(user("carol"), "10.0.0.1"), 4},
+	}
+	for _, r := range reads {
+		if _, err := uc.GetByID(r.ctx, post.ID); err != nil {
+			t.Fatalf("%s: get post: %v", r.name, err)
+		}
+		var views int
+		if err := db.QueryRow(`SELECT COALESCE(SUM(views), 0) FROM post_views WHERE post_id = ?`, post.ID).Scan(&views); err != nil {
+			t.Fatalf("%s: count views: %v", r.name, err)
+		}
+		if views != r.want {
+			t.Fatalf("%s: %d views, want %d", r.name, views, r.want)
+		}
+	}
+
+	// После окна повторный просмотр снова учитывается
+	uc.views.window = 0
+	if _, err := uc.GetByID(user("alice"), post.ID); err != nil {
+		t.Fatalf("get post: %v", err)
+	}
+	var views int
+	if err := db.QueryRow(`SELECT SUM(views) FROM post_views WHERE post_id = ?`, post.ID).Scan(&views); err != nil {
+		t.Fatalf("count views: %v", err)
+	}
+	if views != 5 {
+		t.Fatalf("%d views after window, want 5", views)
+	}
+}
+
+func TestCreatePostUniqueViolations(t *testing.T) {
+	ctx := context.Background()
+	uc, _ := newTestPostUseCase(t)
+
+	newPost := func(id, slug string) *entity.Post {
+		return &entity.Post{ID: id, Slug: slug, Title: id, Content: "content", AuthorID: "author",
+			CategoryID: "1", Status: entity.StatusPublished, CreatedAt: time.Now()}
+	}
+	if err := uc.postRepo.Create(ctx, newPost("p1", "hello")); err != nil {
+		t.Fatalf("create post: %v", err)
+	}
+
+	if err := uc.postRepo.Create(ctx, newPost("p2", "hello")); !errors.Is(err, repository.ErrSlugTaken) {
+		t.Fatalf("duplicate slug: error %v, want ErrSlugTaken", err)
+	}
+	// Повтор ID - не занятый slug: повторная попытка с другим slug не поможет
+	err := uc.postRepo.Create(ctx, newPost("p1", "other"))
+	if err == nil || errors.Is(err, repository.ErrSlugTaken) {
+		t.Fatalf("duplicate id: error %v, want unique violation other than ErrSlugTaken", err)
+	}
+}
+
+// newTestPostUseCase создает use case постов поверх временной БД со всеми миграциями
+func newTestPostUseCase(t *testing.T) (*PostUseCase, *sql.DB) {
+	t.Helper()
+
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "forum.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	migrator, err := migrations.NewMigrator(db)
+	if err != nil {
+		t.Fatalf("create migrator: %v", err)
+	}
+	if err := migrator.Up(); err != nil {
+		t.Fatalf("apply migrations: %v", err)
+	}
+
+	log, err := logger.NewWithConfig(logger.LogConfig{Level: "error", OutputPath: "stdout"})
+	if err != nil {
+		t.Fatalf("create logger: %v", err)
+	}
+	return NewPostUseCase(repository.NewPostRepository(db, log), nil, nil, nil, nil, nil, nil, nil, nil, log), db
+}
@@ -0,0 +1,82 @@
+package usecase
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/kprf42/dolgova/forum_service/internal/auth"
+	"github.com/kprf42/dolgova/forum_service/internal/tenant"
+)
+
+const (
+	// viewWindow повторные просмотры поста одним читателем в течение этого времени не учитываются
+	viewWindow = 30 * time.Minute
+	// maxRecentViews сколько недавних просмотров хранится; при переполнении устаревшие удаляются
+	maxRecentViews = 100_000
+)
+
+// viewDebouncer помнит недавние просмотры, чтобы обновление страницы не накручивало популярность поста
+type viewDebouncer struct {
+	window time.Duration
+
+	mu   sync.Mutex
+	seen map[string]time.Time // сообщество, пост и читатель -> время учтенного просмотра
+}
+
+func newViewDebouncer(window time.Duration) *viewDebouncer {
+	return &viewDebouncer{window: window, seen: make(map[string]time.Time)}
+}
+
+// first сообщает, нужно ли учесть просмотр поста читателем viewer в момент now, и запоминает его.
+// Читатель без ID и адреса не различается, его просмотры учитываются всегда.
+func (d *viewDebouncer) first(ctx context.Context, postID, viewer string, now time.Time) bool {
+	if viewer == "" {
+		return true
+	}
+	key := tenant.FromContext(ctx) + "/" + postID + "/" + viewer
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if at, ok := d.seen[key]; ok && now.Sub(at) < d.window {
+		return false
+	}
+	if len(d.seen) >= maxRecentViews {
+		for k, at := range d.seen {
+			if now.Sub(at) >= d.window {
+				delete(d.seen, k)
+			}
+		}
+		// Все просмотры свежие: лучше изредка учесть повтор, чем расти без ограничений
+		if len(d.seen) >= maxRecentViews {
+			clear(d.seen)
+		}
+	}
+	d.seen[key] = now
+	return true
+}
+
+// viewerKey читатель запроса: пользователь, а без аутентификации - адрес клиента
+func viewerKey(ctx context.Context) string {
+	if userID, ok := auth.UserIDFromContext(ctx); ok {
+		return "user:" + userID
+	}
+	if ip, ok := auth.ClientIPFromContext(ctx); ok {
+		return "ip:" + ip
+	}
+	return ""
+}
+
+// countView учитывает просмотр поста в популярных: не чаще раза в viewWindow от читателя
+// и без просмотров автором собственного поста
+func (uc *PostUseCase) countView(ctx context.Context, postID, authorID string) error {
+	if userID, ok := auth.UserIDFromContext(ctx); ok && userID == authorID {
+		return nil
+	}
+	now := time.Now()
+	if !uc.views.first(ctx, postID, viewerKey(ctx), now) {
+		return nil
+	}
+	return uc.postRepo.AddView(ctx, postID, now)
+}
@@ -0,0 +1,131 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/kprf42/dolgova/forum_service/internal/entity"
+	"github.com/kprf42/dolgova/forum_service/internal/repository"
+	"github.com/kprf42/dolgova/pkg/logger"
+	"github.com/kprf42/dolgova/pkg/uploads"
+)
+
+// ErrInvalidImage файл не удалось прочитать как изображение
+var ErrInvalidImage = errors.New("invalid image")
+
+// AvatarSizes стороны квадратного аватара в пикселях. Основной адрес профиля - первый размер.
+var AvatarSizes = []int{256, 128, 64}
+
+type ProfileUseCase struct {
+	repo     *repository.ProfileRepository
+	karma    *repository.KarmaRepository
+	posts    *repository.PostRepository
+	comments *repository.CommentRepository
+	storage  uploads.Storage
+	log      *logger.Logger
+}
+
+func NewProfileUseCase(repo *repository.ProfileRepository, karma *repository.KarmaRepository, posts *repository.PostRepository, comments *repository.CommentRepository, storage uploads.Storage, log *logger.Logger) *ProfileUseCase {
+	return &ProfileUseCase{
+		repo:     repo,
+		karma:    karma,
+		posts:    posts,
+		comments: comments,
+		storage:  storage,
+		log:      log,
+	}
+}
+
+func (uc *ProfileUseCase) GetProfile(ctx context.Context, userID string) (*entity.Profile, error) {
+	profile, err := uc.repo.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	profile.Karma, err = uc.karma.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return profile, nil
+}
+
+// GetStats возвращает карму пользователя с разбивкой по источникам и число его публикаций
+func (uc *ProfileUseCase) GetStats(ctx context.Context, userID string) (*entity.UserStats, error) {
+	stats := &entity.UserStats{UserID: userID}
+
+	var err error
+	if stats.KarmaBySource, err = uc.karma.BySource(ctx, userID); err != nil {
+		return nil, err
+	}
+	if stats.Karma, err = uc.karma.Get(ctx, userID); err != nil {
+		return nil, err
+	}
+	if stats.Posts, err = uc.posts.CountByAuthor(ctx, userID); err != nil {
+		return nil, err
+	}
+	if stats.Comments, stats.AcceptedAnswers, err = uc.comments.CountByAuthor(ctx, userID); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// UploadAvatar обрезает изображение до квадрата, сохраняет его в размерах AvatarSizes
+// и обновляет аватар в профиле. Старые файлы не удаляются: на них могут ссылаться кеши.
+func (uc *ProfileUseCase) UploadAvatar(ctx context.Context, userID string, r io.Reader) (*entity.Profile, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	contentType := http.DetectContentType(data)
+	if !uploads.IsImage(contentType) {
+		uc.log.ForContext(ctx).Warn("Rejected avatar of unsupported type",
+			logger.String("user_id", userID),
+			logger.String("content_type", contentType))
+		return nil, ErrUnsupportedFile
+	}
+
+	img, format, err := uploads.DecodeImage(bytes.NewReader(data))
+	if errors.Is(err, uploads.ErrImageTooLarge) {
+		return nil, err
+	}
+	if err != nil {
+		uc.log.ForContext(ctx).Warn("Failed to decode avatar",
+			logger.String("user_id", userID),
+			logger.Error(err))
+		return nil, ErrInvalidImage
+	}
+
+	square := uploads.CropSquare(img)
+	profile := &entity.Profile{
+		UserID:    userID,
+		Avatars:   make(map[string]string, len(AvatarSizes)),
+		UpdatedAt: time.Now().UTC(),
+	}
+	for _, size := range AvatarSizes {
+		encoded, ext, err := uploads.Encode(uploads.Resize(square, size, size), format)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode avatar: %w", err)
+		}
+		key, err := uc.storage.Save(ctx, bytes.NewReader(encoded), ext)
+		if err != nil {
+			uc.log.ForContext(ctx).Error("Failed to store avatar",
+				logger.String("user_id", userID),
+				logger.Error(err))
+			return nil, err
+		}
+		profile.Avatars[strconv.Itoa(size)] = uc.storage.URL(key)
+	}
+	profile.AvatarURL = profile.Avatars[strconv.Itoa(AvatarSizes[0])]
+
+	if err := uc.repo.SetAvatar(ctx, profile); err != nil {
+		return nil, err
+	}
+	return profile, nil
+}
@@ -0,0 +1,97 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"testing"
+	"time"
+
+	"github.com/kprf42/dolgova/forum_service/internal/entity"
+	"github.com/kprf42/dolgova/forum_service/internal/repository"
+	"github.com/kprf42/dolgova/forum_service/internal/tenant"
+)
+
+// Карма, как и посты с комментариями, считается в каждом сообществе отдельно
+func TestGetStatsByTenant(t *testing.T) {
+	posts, db := newTestPostUseCase(t)
+	log := posts.log
+	karmaRepo := repository.NewKarmaRepository(db, log)
+	commentRepo := repository.NewCommentRepository(db, log)
+	comments := NewCommentUseCase(commentRepo, posts.postRepo, nil, nil, nil,
+		NewKarmaUseCase(karmaRepo, func() int { return 0 }, log), nil, nil, log)
+	profiles := NewProfileUseCase(repository.NewProfileRepository(db, log), karmaRepo, posts.postRepo, commentRepo, nil, log)
+
+	alpha := tenant.WithID(context.Background(), "alpha")
+	beta := tenant.WithID(context.Background(), "beta")
+
+	// В alpha у автора пост и принятый ответ с голосом за него, в beta - один комментарий
+	mustCreatePost(t, posts, alpha, "alpha-post", "owner")
+	mustCreateComment(t, commentRepo, alpha, "alpha-answer", "alpha-post", "author")
+	mustCreatePost(t, posts, beta, "beta-post", "owner")
+	mustCreateComment(t, commentRepo, beta, "beta-comment", "beta-post", "author")
+
+	if _, err := comments.Vote(alpha, "alpha-answer", "voter", 1); err != nil {
+		t.Fatalf("vote: %v", err)
+	}
+	if _, err := comments.AcceptAnswer(alpha, "alpha-post", "alpha-answer", "owner", true); err != nil {
+		t.Fatalf("accept answer: %v", err)
+	}
+	// Комментарий другого сообщества не найден
+	if _, err := comments.Vote(beta, "alpha-answer", "voter", 1); !errors.Is(err, ErrCommentNotFound) {
+		t.Fatalf("vote in other tenant: error %v, want ErrCommentNotFound", err)
+	}
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want entity.UserStats
+	}{
+		{
+			name: "alpha",
+			ctx:  alpha,
+			want: entity.UserStats{
+				Karma:           KarmaCommentUpvote + KarmaAcceptedAnswer,
+				KarmaBySource:   map[string]int{entity.KarmaCommentVote: KarmaCommentUpvote, entity.KarmaAcceptedAnswer: KarmaAcceptedAnswer},
+				Comments:        1,
+				AcceptedAnswers: 1,
+			},
+		},
+		{
+			name: "beta",
+			ctx:  beta,
+			want: entity.UserStats{KarmaBySource: map[string]int{}, Comments: 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stats, err := profiles.GetStats(tt.ctx, "author")
+			if err != nil {
+				t.Fatalf("get stats: %v", err)
+			}
+			got := *stats
+			if got.Karma != tt.want.Karma || !maps.Equal(got.KarmaBySource, tt.want.KarmaBySource) ||
+				got.Posts != tt.want.Posts || got.Comments != tt.want.Comments || got.AcceptedAnswers != tt.want.AcceptedAnswers {
+				t.Fatalf("stats %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func mustCreatePost(t *testing.T, uc *PostUseCase, ctx context.Context, id, authorID string) {
+	t.Helper()
+	post := &entity.Post{ID: id, Title: id, Content: "content", AuthorID: authorID, CategoryID: "1",
+		Status: entity.StatusPublished, CreatedAt: time.Now()}
+	if err := uc.postRepo.Create(ctx, post); err != nil {
+		t.Fatalf("create post %s: %v", id, err)
+	}
+}
+
+func mustCreateComment(t *testing.T, repo *repository.CommentRepository, ctx context.Context, id, postID, authorID string) {
+	t.Helper()
+	comment := &entity.Comment{ID: id, Content: "content", PostID: postID, AuthorID: authorID,
+		Status: entity.StatusPublished, CreatedAt: time.Now()}
+	if err := repo.Create(ctx, comment); err != nil {
+		t.Fatalf("create comment %s: %v", id, err)
+	}
+}
@@ -0,0 +1,109 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kprf42/dolgova/forum_service/internal/entity"
+	"github.com/kprf42/dolgova/forum_service/internal/repository"
+	"github.com/kprf42/dolgova/pkg/logger"
+)
+
+// maxReportDetails предельная длина пояснения к жалобе в символах
+const maxReportDetails = 1000
+
+var (
+	// ErrInvalidReport неизвестная причина или слишком длинное пояснение
+	ErrInvalidReport = errors.New("invalid report")
+
+	ErrAlreadyReported = repository.ErrAlreadyReported
+)
+
+var reportReasons = map[string]bool{
+	entity.ReportReasonSpam:     true,
+	entity.ReportReasonAbuse:    true,
+	entity.ReportReasonOffTopic: true,
+	entity.ReportReasonIllegal:  true,
+	entity.ReportReasonOther:    true,
+}
+
+// ReportUseCase жалобы на посты и комментарии: отправляют пользователи, читают модераторы
+type ReportUseCase struct {
+	repo     *repository.ReportRepository
+	posts    *repository.PostRepository
+	comments *repository.CommentRepository
+	access   *ModeratorAccess
+	log      *logger.Logger
+}
+
+func NewReportUseCase(repo *repository.ReportRepository, posts *repository.PostRepository, comments *repository.CommentRepository, access *ModeratorAccess, log *logger.Logger) *ReportUseCase {
+	return &ReportUseCase{
+		repo:     repo,
+		posts:    posts,
+		comments: comments,
+		access:   access,
+		log:      log,
+	}
+}
+
+// ReportPost сохраняет жалобу reporterID на опубликованный пост
+func (uc *ReportUseCase) ReportPost(ctx context.Context, postID, reporterID string, req *entity.ReportRequest) (*entity.Report, error) {
+	post, err := uc.posts.GetByID(ctx, postID)
+	if err != nil || post.Status != entity.StatusPublished {
+		return nil, notFound(err, ErrModerationNotFound)
+	}
+	return uc.create(ctx, entity.ReportTargetPost, postID, post.CategoryID, reporterID, req)
+}
+
+// ReportComment сохраняет жалобу reporterID на опубликованный комментарий
+func (uc *ReportUseCase) ReportComment(ctx context.Context, commentID, reporterID string, req *entity.ReportRequest) (*entity.Report, error) {
+	comment, err := uc.comments.GetByID(ctx, commentID)
+	if err != nil || comment.Status != entity.StatusPublished {
+		return nil, notFound(err, ErrModerationNotFound)
+	}
+	post, err := uc.posts.GetByID(ctx, comment.PostID)
+	if err != nil {
+		return nil, notFound(err, ErrModerationNotFound)
+	}
+	return uc.create(ctx, entity.ReportTargetComment, commentID, post.CategoryID, reporterID, req)
+}
+
+// List возвращает жалобы в категориях модератора, от новых к старым
+func (uc *ReportUseCase) List(ctx context.Context, moderatorID string, limit, offset int) ([]*entity.Report, error) {
+	categories, err := uc.access.Scope(ctx, moderatorID)
+	if err != nil {
+		return nil, err
+	}
+	return uc.repo.List(ctx, categories, limit, offset)
+}
+
+func (uc *ReportUseCase) create(ctx context.Context, targetType, targetID, categoryID, reporterID string, req *entity.ReportRequest) (*entity.Report, error) {
+	details := strings.TrimSpace(req.Details)
+	if !reportReasons[req.Reason] || len([]rune(details)) > maxReportDetails {
+		return nil, ErrInvalidReport
+	}
+
+	report := &entity.Report{
+		ID:         uuid.New().String(),
+		TargetType: targetType,
+		TargetID:   targetID,
+		CategoryID: categoryID,
+		ReporterID: reporterID,
+		Reason:     req.Reason,
+		Details:    details,
+		CreatedAt:  time.Now().UTC().Truncate(time.Second),
+	}
+	if err := uc.repo.Create(ctx, report); err != nil {
+		return nil, err
+	}
+
+	uc.log.ForContext(ctx).Info("Content reported",
+		logger.String("report_id", report.ID),
+		logger.String("target_type", targetType),
+		logger.String("target_id", targetID),
+		logger.String("reason", report.Reason))
+	return report, nil
+}
@@ -0,0 +1,92 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/kprf42/dolgova/forum_service/internal/entity"
+	"github.com/kprf42/dolgova/forum_service/internal/events"
+	"github.com/kprf42/dolgova/forum_service/internal/search"
+	"github.com/kprf42/dolgova/forum_service/internal/tenant"
+	"github.com/kprf42/dolgova/pkg/logger"
+)
+
+const maxSearchLimit = 100
+
+// ErrEmptySearchQuery пустой поисковый запрос
+var ErrEmptySearchQuery = errors.New("search query is empty")
+
+type SearchUseCase struct {
+	index search.SearchIndex
+	log   *logger.Logger
+}
+
+func NewSearchUseCase(index search.SearchIndex, log *logger.Logger) *SearchUseCase {
+	return &SearchUseCase{
+		index: index,
+		log:   log,
+	}
+}
+
+// Search ищет посты и комментарии текущего сообщества
+func (uc *SearchUseCase) Search(ctx context.Context, text string, limit, offset int) (*search.Result, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, ErrEmptySearchQuery
+	}
+	if limit > maxSearchLimit {
+		limit = maxSearchLimit
+	}
+
+	result, err := uc.index.Query(ctx, search.Query{
+		Text:     text,
+		TenantID: tenant.FromContext(ctx),
+		Limit:    limit,
+		Offset:   offset,
+	})
+	if err != nil {
+		if !errors.Is(err, search.ErrDisabled) {
+			uc.log.ForContext(ctx).Error("Search failed",
+				logger.String("query", text),
+				logger.Error(err))
+		}
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// HandleEvent обновляет индекс по доменным событиям постов и комментариев.
+// Ошибка индексации не отменяет изменение, поэтому только логируется.
+func (uc *SearchUseCase) HandleEvent(ctx context.Context, event events.Event) {
+	var err error
+	switch event.Type {
+	case events.PostCreated, events.PostUpdated:
+		// Правка поста на премодерации не должна попасть в поиск
+		if event.Post.Status != "" && event.Post.Status != entity.StatusPublished {
+			err = uc.index.Remove(ctx, search.DocPost, event.ID)
+			break
+		}
+		err = uc.index.IndexPost(ctx, event.TenantID, event.Post)
+	case events.PostDeleted:
+		err = uc.index.Remove(ctx, search.DocPost, event.ID)
+	case events.CommentCreated, events.CommentUpdated:
+		if event.Comment.Status != "" && event.Comment.Status != entity.StatusPublished {
+			err = uc.index.Remove(ctx, search.DocComment, event.ID)
+			break
+		}
+		err = uc.index.IndexComment(ctx, event.TenantID, event.Comment)
+	case events.CommentDeleted:
+		err = uc.index.Remove(ctx, search.DocComment, event.ID)
+	default:
+		return
+	}
+
+	if err != nil {
+		uc.log.ForContext(ctx).Error("Failed to update search index",
+			logger.String("event", string(event.Type)),
+			logger.String("id", event.ID),
+			logger.Error(err))
+	}
+}
@@ -0,0 +1,141 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+
+	"github.com/kprf42/dolgova/forum_service/internal/auth"
+	"github.com/kprf42/dolgova/forum_service/internal/entity"
+	"github.com/kprf42/dolgova/forum_service/internal/repository"
+	"github.com/kprf42/dolgova/pkg/logger"
+)
+
+// ShareLinkPrefix путь, по которому открываются короткие ссылки
+const ShareLinkPrefix = "/s/"
+
+const (
+	shareCodeLength   = 8
+	shareCodeAttempts = 5
+	shareCodeAlphabet = "abcdefghijkmnpqrstuvwxyzABCDEFGHJKLMNPQRSTUVWXYZ23456789" // Без похожих символов (0/O, 1/l/I)
+)
+
+var ErrShareLinkNotFound = repository.ErrShareLinkNotFound
+
+// ShareUseCase короткие ссылки на посты и статистика переходов по ним
+type ShareUseCase struct {
+	repo       *repository.ShareLinkRepository
+	posts      *repository.PostRepository
+	comments   *repository.CommentRepository
+	moderators Moderators
+	log        *logger.Logger
+}
+
+// NewShareUseCase создает use case коротких ссылок. moderators может быть nil,
+// тогда статистику поста видит только автор.
+func NewShareUseCase(repo *repository.ShareLinkRepository, posts *repository.PostRepository, comments *repository.CommentRepository, moderators Moderators, log *logger.Logger) *ShareUseCase {
+	return &ShareUseCase{
+		repo:       repo,
+		posts:      posts,
+		comments:   comments,
+		moderators: moderators,
+		log:        log,
+	}
+}
+
+// Share возвращает короткую ссылку пользователя на опубликованный пост.
+// Повторный вызов возвращает ту же ссылку, чтобы переходы не делились между кодами.
+func (uc *ShareUseCase) Share(ctx context.Context, postID, userID string) (*entity.ShareLink, error) {
+	post, err := uc.posts.GetByID(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+	if post.Status != entity.StatusPublished {
+		return nil, errors.New("post not found")
+	}
+
+	for attempt := 0; ; attempt++ {
+		link, err := uc.repo.GetOrCreate(ctx, &entity.ShareLink{
+			Code:      newShareCode(),
+			PostID:    postID,
+			CreatedBy: userID,
+		})
+		if errors.Is(err, repository.ErrShareCodeTaken) && attempt < shareCodeAttempts-1 {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		link.URL = ShareLinkPrefix + link.Code
+		return link, nil
+	}
+}
+
+// Open засчитывает переход по короткой ссылке и возвращает ID поста
+func (uc *ShareUseCase) Open(ctx context.Context, code string) (string, error) {
+	link, err := uc.repo.Click(ctx, code)
+	if err != nil {
+		return "", err
+	}
+
+	uc.log.ForContext(ctx).Info("Share link opened",
+		logger.String("code", code),
+		logger.String("post_id", link.PostID),
+		logger.Int("clicks", link.Clicks))
+	return link.PostID, nil
+}
+
+// PostStats возвращает статистику поста: комментарии и переходы по коротким ссылкам.
+// Доступна автору поста и модераторам его категории.
+func (uc *ShareUseCase) PostStats(ctx context.Context, postID, userID string) (*entity.PostStats, error) {
+	post, err := uc.posts.GetByID(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+	if post.AuthorID != userID {
+		allowed := false
+		if uc.moderators != nil {
+			allowed, err = uc.moderators.CanModerate(ctx, userID, post.CategoryID)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if !allowed {
+			uc.log.ForContext(ctx).Warn("Unauthorized post stats access",
+				logger.String("post_id", postID),
+				logger.String("user_id", userID))
+			return nil, errors.New("unauthorized")
+		}
+	}
+
+	// Статистика считает все комментарии, включая авторов, заблокированных читателем
+	comments, err := uc.comments.CountByPostID(auth.WithUserID(ctx, ""), postID)
+	if err != nil {
+		return nil, err
+	}
+	links, err := uc.repo.ListByPost(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &entity.PostStats{
+		PostID:     postID,
+		Comments:   comments,
+		ShareLinks: links,
+	}
+	for _, link := range links {
+		link.URL = ShareLinkPrefix + link.Code
+		stats.ShareClicks += link.Clicks
+	}
+	return stats, nil
+}
+
+// newShareCode случайный код короткой ссылки
+func newShareCode() string {
+	b := make([]byte, shareCodeLength)
+	rand.Read(b)
+	for i := range b {
+		b[i] = shareCodeAlphabet[int(b[i])%len(shareCodeAlphabet)]
+	}
+	return string(b)
+}
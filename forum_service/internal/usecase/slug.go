@@ -0,0 +1,89 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"strings"
+	"unicode"
+
+	"github.com/kprf42/dolgova/forum_service/internal/entity"
+	"github.com/kprf42/dolgova/forum_service/internal/repository"
+)
+
+const (
+	// maxSlugLength предельная длина slug без суффикса, делающего его уникальным
+	maxSlugLength   = 80
+	slugAttempts    = 5
+	slugSuffixLen   = 6
+	slugAlphabet    = "abcdefghijkmnpqrstuvwxyz23456789"
+	defaultPostSlug = "post" // Для заголовков без букв и цифр
+)
+
+// slugTranslit транслитерация кириллицы для slug
+var slugTranslit = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "e", 'ж': "zh",
+	'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o",
+	'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts",
+	'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu",
+	'я': "ya",
+}
+
+// createWithSlug сохраняет пост со slug из заголовка. Если slug занят другим постом
+// сообщества, к нему добавляется случайный суффикс.
+func (uc *PostUseCase) createWithSlug(ctx context.Context, post *entity.Post) error {
+	base := slugify(post.Title)
+	for attempt := 0; ; attempt++ {
+		post.Slug = base
+		if attempt > 0 {
+			post.Slug = base + "-" + newSlugSuffix()
+		}
+		err := uc.postRepo.Create(ctx, post)
+		if errors.Is(err, repository.ErrSlugTaken) && attempt < slugAttempts-1 {
+			continue
+		}
+		return err
+	}
+}
+
+// slugify переводит заголовок в slug: латиница в нижнем регистре, цифры и дефисы между словами
+func slugify(title string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(title) {
+		var part string
+		switch {
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			part = string(r)
+		case slugTranslit[r] != "":
+			part = slugTranslit[r]
+		case r == 'ъ' || r == 'ь':
+			continue
+		default:
+			dash = b.Len() > 0
+			continue
+		}
+		if b.Len()+len(part)+1 > maxSlugLength {
+			break
+		}
+		if dash {
+			b.WriteByte('-')
+			dash = false
+		}
+		b.WriteString(part)
+	}
+	if b.Len() == 0 {
+		return defaultPostSlug
+	}
+	return b.String()
+}
+
+// newSlugSuffix случайный суффикс для занятого slug
+func newSlugSuffix() string {
+	b := make([]byte, slugSuffixLen)
+	rand.Read(b)
+	for i := range b {
+		b[i] = slugAlphabet[int(b[i])%len(slugAlphabet)]
+	}
+	return string(b)
+}
@@ -0,0 +1,95 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/kprf42/dolgova/forum_service/internal/entity"
+	"github.com/kprf42/dolgova/forum_service/internal/repository"
+	"github.com/kprf42/dolgova/pkg/logger"
+)
+
+const (
+	statsDateLayout    = "2006-01-02"
+	defaultStatsPeriod = 30 * 24 * time.Hour
+	maxStatsPeriodDays = 366
+)
+
+var ErrInvalidStatsRange = errors.New("invalid date range")
+
+// ConnectionCounter источник количества активных WebSocket соединений
+type ConnectionCounter interface {
+	ClientCount() int
+}
+
+type StatsUseCase struct {
+	repo        *repository.StatsRepository
+	connections ConnectionCounter
+	log         *logger.Logger
+}
+
+func NewStatsUseCase(repo *repository.StatsRepository, connections ConnectionCounter, log *logger.Logger) *StatsUseCase {
+	return &StatsUseCase{
+		repo:        repo,
+		connections: connections,
+		log:         log,
+	}
+}
+
+// GetStats возвращает общие счетчики и разбивку по дням за период [from, to].
+// Нулевые from/to означают последние 30 дней.
+func (uc *StatsUseCase) GetStats(ctx context.Context, from, to time.Time) (*entity.StatsResponse, error) {
+	if to.IsZero() {
+		to = time.Now().UTC()
+	}
+	if from.IsZero() {
+		from = to.Add(-defaultStatsPeriod)
+	}
+	from = truncateDay(from)
+	to = truncateDay(to)
+
+	if from.After(to) || to.Sub(from) > maxStatsPeriodDays*24*time.Hour {
+		return nil, ErrInvalidStatsRange
+	}
+
+	uc.log.ForContext(ctx).Info("Getting stats",
+		logger.String("from", from.Format(statsDateLayout)),
+		logger.String("to", to.Format(statsDateLayout)))
+
+	totals, err := uc.repo.Totals(ctx)
+	if err != nil {
+		uc.log.ForContext(ctx).Error("Failed to get stats totals", logger.Error(err))
+		return nil, err
+	}
+	totals.ActiveConnections = uc.connections.ClientCount()
+
+	counts, err := uc.repo.DailyCounts(ctx, from.Format(statsDateLayout), to.Format(statsDateLayout))
+	if err != nil {
+		uc.log.ForContext(ctx).Error("Failed to get daily stats", logger.Error(err))
+		return nil, err
+	}
+
+	// Дни без активности тоже попадают в ряд, чтобы график был непрерывным
+	var daily []*entity.DailyStats
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		date := day.Format(statsDateLayout)
+		if stats, ok := counts[date]; ok {
+			daily = append(daily, stats)
+		} else {
+			daily = append(daily, &entity.DailyStats{Date: date})
+		}
+	}
+
+	return &entity.StatsResponse{
+		From:   from,
+		To:     to,
+		Totals: *totals,
+		Daily:  daily,
+	}, nil
+}
+
+func truncateDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,138 @@
+package usecase
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/kprf42/dolgova/forum_service/internal/entity"
+	"github.com/kprf42/dolgova/forum_service/internal/tenant"
+	"github.com/kprf42/dolgova/pkg/logger"
+)
+
+const (
+	// TrendingLimit сколько популярных постов хранится в кеше
+	TrendingLimit = 20
+	// defaultTrendingWindow период активности, если в настройках он не задан
+	defaultTrendingWindow = 24 * time.Hour
+	// minViewRetention сколько хранятся счетчики просмотров при коротком периоде активности
+	minViewRetention = 7 * 24 * time.Hour
+)
+
+// TrendingUseCase популярные посты для главной страницы. Список общий для всех читателей
+// сообщества, поэтому хранится в кеше и пересчитывается по расписанию (Refresh), а не на запрос.
+// Скрытие заблокированных читателем авторов к нему не применяется.
+type TrendingUseCase struct {
+	posts  *PostUseCase
+	window func() time.Duration
+	log    *logger.Logger
+
+	mu    sync.RWMutex
+	cache map[string]*entity.TrendingPosts // ID сообщества -> список
+}
+
+// NewTrendingUseCase создает use case популярных постов. window возвращает текущий период,
+// за который считается активность.
+func NewTrendingUseCase(posts *PostUseCase, window func() time.Duration, log *logger.Logger) *TrendingUseCase {
+	return &TrendingUseCase{
+		posts:  posts,
+		window: window,
+		log:    log,
+		cache:  make(map[string]*entity.TrendingPosts),
+	}
+}
+
+// Get возвращает популярные посты сообщества из кеша. Первый запрос сообщества считает
+// список сразу, дальше его обновляет Refresh.
+func (uc *TrendingUseCase) Get(ctx context.Context) (*entity.TrendingPosts, error) {
+	tenantID := tenant.FromContext(ctx)
+
+	uc.mu.RLock()
+	trending, ok := uc.cache[tenantID]
+	uc.mu.RUnlock()
+	if ok {
+		return trending, nil
+	}
+	return uc.refresh(ctx)
+}
+
+// Refresh пересчитывает популярные посты всех сообществ, которые уже запрашивались,
+// и удаляет счетчики просмотров, вышедшие за период активности
+func (uc *TrendingUseCase) Refresh(ctx context.Context) error {
+	uc.mu.RLock()
+	tenants := make([]string, 0, len(uc.cache))
+	for tenantID := range uc.cache {
+		tenants = append(tenants, tenantID)
+	}
+	uc.mu.RUnlock()
+
+	for _, tenantID := range tenants {
+		if _, err := uc.refresh(tenant.WithID(ctx, tenantID)); err != nil {
+			return err
+		}
+	}
+
+	retention := max(uc.currentWindow(), minViewRetention)
+	deleted, err := uc.posts.postRepo.DeleteViewsBefore(ctx, time.Now().Add(-retention))
+	if err != nil {
+		return err
+	}
+	uc.log.Info("Trending posts refreshed",
+		logger.Int("tenants", len(tenants)),
+		logger.Int64("deleted_view_counters", deleted))
+	return nil
+}
+
+func (uc *TrendingUseCase) refresh(ctx context.Context) (*entity.TrendingPosts, error) {
+	window := uc.currentWindow()
+	now := time.Now().UTC()
+
+	posts, err := uc.posts.postRepo.Trending(ctx, now.Add(-window), TrendingLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*entity.PostResponse, 0, len(posts))
+	for _, post := range posts {
+		responses = append(responses, &entity.PostResponse{
+			ID:         post.ID,
+			Slug:       post.Slug,
+			Title:      post.Title,
+			Content:    post.Content,
+			AuthorID:   post.AuthorID,
+			CategoryID: post.CategoryID,
+			IsPinned:   post.IsPinned,
+			IsLocked:   post.IsLocked,
+			Score:      post.Score,
+			Status:     post.Status,
+			CreatedAt:  post.CreatedAt,
+			AnswerID:   post.AnswerID,
+		})
+	}
+
+	if err := uc.posts.loadAttachments(ctx, responses...); err != nil {
+		return nil, err
+	}
+	if err := uc.posts.loadKarma(ctx, responses...); err != nil {
+		return nil, err
+	}
+	uc.posts.loadAuthors(ctx, responses...)
+
+	trending := &entity.TrendingPosts{
+		Posts:     responses,
+		Hours:     int(window / time.Hour),
+		UpdatedAt: now.Truncate(time.Second),
+	}
+
+	uc.mu.Lock()
+	uc.cache[tenant.FromContext(ctx)] = trending
+	uc.mu.Unlock()
+	return trending, nil
+}
+
+func (uc *TrendingUseCase) currentWindow() time.Duration {
+	if window := uc.window(); window > 0 {
+		return window
+	}
+	return defaultTrendingWindow
+}
@@ -0,0 +1,58 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/kprf42/dolgova/forum_service/internal/entity"
+	"github.com/kprf42/dolgova/forum_service/internal/repository"
+	"github.com/kprf42/dolgova/pkg/logger"
+)
+
+// ReadMarks отметки о прочтении для списков постов
+type ReadMarks interface {
+	// Unread возвращает посты из postIDs с новой активностью после визита userID
+	Unread(ctx context.Context, userID string, postIDs []string) (map[string]bool, error)
+}
+
+// UnreadUseCase отслеживает, какие посты пользователь видел, и находит темы с новыми комментариями
+type UnreadUseCase struct {
+	repo  *repository.ReadMarkRepository
+	posts *repository.PostRepository
+	log   *logger.Logger
+}
+
+func NewUnreadUseCase(repo *repository.ReadMarkRepository, posts *repository.PostRepository, log *logger.Logger) *UnreadUseCase {
+	return &UnreadUseCase{
+		repo:  repo,
+		posts: posts,
+		log:   log,
+	}
+}
+
+// MarkPostRead отмечает опубликованный пост прочитанным
+func (uc *UnreadUseCase) MarkPostRead(ctx context.Context, userID, postID string) error {
+	post, err := uc.posts.GetByID(ctx, postID)
+	if err != nil {
+		return err
+	}
+	if post.Status != entity.StatusPublished {
+		return errors.New("post not found")
+	}
+	return uc.repo.MarkPost(ctx, userID, postID, time.Now())
+}
+
+// MarkCategoryRead отмечает прочитанными все текущие посты категории
+func (uc *UnreadUseCase) MarkCategoryRead(ctx context.Context, userID, categoryID string) error {
+	return uc.repo.MarkCategory(ctx, userID, categoryID, time.Now())
+}
+
+// Threads возвращает открытые пользователем темы с новыми комментариями
+func (uc *UnreadUseCase) Threads(ctx context.Context, userID string, limit, offset int) ([]*entity.UnreadThread, error) {
+	return uc.repo.Threads(ctx, userID, limit, offset)
+}
+
+func (uc *UnreadUseCase) Unread(ctx context.Context, userID string, postIDs []string) (map[string]bool, error) {
+	return uc.repo.Unread(ctx, userID, postIDs)
+}
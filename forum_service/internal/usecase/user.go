@@ -0,0 +1,100 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kprf42/dolgova/forum_service/internal/entity"
+	"github.com/kprf42/dolgova/forum_service/internal/repository"
+	"github.com/kprf42/dolgova/pkg/logger"
+)
+
+// disabledPassword не является bcrypt хешем, поэтому вход по паролю невозможен,
+// пока пользователь не задаст пароль заново
+const disabledPassword = "!"
+
+type UserUseCase struct {
+	repo *repository.UserRepository
+	log  *logger.Logger
+}
+
+func NewUserUseCase(repo *repository.UserRepository, log *logger.Logger) *UserUseCase {
+	return &UserUseCase{
+		repo: repo,
+		log:  log,
+	}
+}
+
+// Import создает пользователя без пароля или возвращает существующего с тем же email
+func (uc *UserUseCase) Import(ctx context.Context, username, email string, createdAt time.Time) (user *entity.User, created bool, err error) {
+	email = strings.ToLower(strings.TrimSpace(email))
+	username = strings.TrimSpace(username)
+	if username == "" || email == "" {
+		return nil, false, errors.New("username and email are required")
+	}
+
+	existing, err := uc.repo.GetByEmail(ctx, email)
+	if err != nil {
+		return nil, false, err
+	}
+	if existing != nil {
+		uc.log.ForContext(ctx).Info("User already exists, reusing",
+			logger.String("user_id", existing.ID))
+		return existing, false, nil
+	}
+
+	if createdAt.IsZero() {
+		createdAt = time.Now()
+	}
+
+	user = &entity.User{
+		ID:        uuid.New().String(),
+		Username:  username,
+		Email:     email,
+		Password:  disabledPassword,
+		Role:      "user",
+		CreatedAt: createdAt,
+	}
+	if err := uc.repo.Create(ctx, user); err != nil {
+		return nil, false, err
+	}
+
+	return user, true, nil
+}
+
+// Anonymize обезличивает данные пользователя на форуме при удалении аккаунта
+func (uc *UserUseCase) Anonymize(ctx context.Context, userID string) (*entity.AnonymizeResult, error) {
+	if userID == "" || userID == entity.DeletedUserID {
+		return nil, errors.New("invalid user id")
+	}
+
+	result, err := uc.repo.Anonymize(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	uc.log.ForContext(ctx).Info("Anonymized user",
+		logger.String("user_id", userID),
+		logger.Int64("posts", result.Posts),
+		logger.Int64("comments", result.Comments),
+		logger.Int64("chat_messages", result.ChatMessages))
+	return result, nil
+}
+
+// ExportPosts пачка постов пользователя для выгрузки персональных данных
+func (uc *UserUseCase) ExportPosts(ctx context.Context, userID, afterID string, limit int) ([]*entity.ExportedPost, error) {
+	return uc.repo.ExportPosts(ctx, userID, afterID, limit)
+}
+
+// ExportComments пачка комментариев пользователя для выгрузки персональных данных
+func (uc *UserUseCase) ExportComments(ctx context.Context, userID, afterID string, limit int) ([]*entity.ExportedComment, error) {
+	return uc.repo.ExportComments(ctx, userID, afterID, limit)
+}
+
+// ExportChatMessages пачка сообщений чата пользователя для выгрузки персональных данных
+func (uc *UserUseCase) ExportChatMessages(ctx context.Context, userID, afterID string, limit int) ([]*entity.ChatMessage, error) {
+	return uc.repo.ExportChatMessages(ctx, userID, afterID, limit)
+}
@@ -0,0 +1,28 @@
+-- Посты (author_id ссылается на пользователя auth сервиса)
+CREATE TABLE IF NOT EXISTS posts (
+    id          TEXT PRIMARY KEY,
+    title       TEXT NOT NULL,
+    content     TEXT NOT NULL,
+    author_id   TEXT NOT NULL,
+    category_id TEXT,
+    is_pinned   INTEGER DEFAULT 0, -- 0 = false, 1 = true
+    created_at  TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Комментарии
+CREATE TABLE IF NOT EXISTS comments (
+    id         TEXT PRIMARY KEY,
+    content    TEXT NOT NULL,
+    post_id    TEXT NOT NULL,
+    author_id  TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (post_id) REFERENCES posts(id)
+);
+
+-- Сообщения чата
+CREATE TABLE IF NOT EXISTS chat_messages (
+    id         TEXT PRIMARY KEY,
+    user_id    TEXT NOT NULL,
+    text       TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_chat_created;
+DROP INDEX IF EXISTS idx_chat_user;
+DROP INDEX IF EXISTS idx_comments_author;
+DROP INDEX IF EXISTS idx_comments_post;
+DROP INDEX IF EXISTS idx_posts_category;
+DROP INDEX IF EXISTS idx_posts_author;
@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_chat_tenant_created;
+DROP INDEX IF EXISTS idx_comments_tenant_post;
+DROP INDEX IF EXISTS idx_posts_tenant_category;
+DROP INDEX IF EXISTS idx_posts_tenant_created;
+
+ALTER TABLE chat_messages DROP COLUMN tenant_id;
+ALTER TABLE comments DROP COLUMN tenant_id;
+ALTER TABLE posts DROP COLUMN tenant_id;
@@ -0,0 +1,10 @@
+-- Несколько сообществ в одном развертывании: все данные форума привязаны к tenant_id.
+-- Существующие записи попадают в сообщество по умолчанию.
+ALTER TABLE posts ADD COLUMN tenant_id TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE comments ADD COLUMN tenant_id TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE chat_messages ADD COLUMN tenant_id TEXT NOT NULL DEFAULT 'default';
+
+CREATE INDEX IF NOT EXISTS idx_posts_tenant_created ON posts(tenant_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_posts_tenant_category ON posts(tenant_id, category_id);
+CREATE INDEX IF NOT EXISTS idx_comments_tenant_post ON comments(tenant_id, post_id);
+CREATE INDEX IF NOT EXISTS idx_chat_tenant_created ON chat_messages(tenant_id, created_at);
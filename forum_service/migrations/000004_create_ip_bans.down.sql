@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_ip_bans_expires;
+DROP TABLE IF EXISTS ip_bans;
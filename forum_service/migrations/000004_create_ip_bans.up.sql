@@ -0,0 +1,11 @@
+-- Баны по IP адресам и подсетям. Таблица общая: ее проверяют и форум, и auth сервис.
+CREATE TABLE IF NOT EXISTS ip_bans (
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    cidr       TEXT NOT NULL,
+    reason     TEXT NOT NULL DEFAULT '',
+    created_by TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP -- NULL = бессрочно
+);
+
+CREATE INDEX IF NOT EXISTS idx_ip_bans_expires ON ip_bans(expires_at);
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_comments_author_status;
+DROP INDEX IF EXISTS idx_posts_author_status;
+DROP INDEX IF EXISTS idx_comments_tenant_status;
+DROP INDEX IF EXISTS idx_posts_tenant_status;
+
+ALTER TABLE comments DROP COLUMN status;
+ALTER TABLE posts DROP COLUMN status;
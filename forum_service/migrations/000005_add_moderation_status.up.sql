@@ -0,0 +1,9 @@
+-- Премодерация: контент новых пользователей ждет одобрения модератора.
+-- published - виден всем, pending - в очереди модерации, rejected - отклонен.
+ALTER TABLE posts ADD COLUMN status TEXT NOT NULL DEFAULT 'published';
+ALTER TABLE comments ADD COLUMN status TEXT NOT NULL DEFAULT 'published';
+
+CREATE INDEX IF NOT EXISTS idx_posts_tenant_status ON posts(tenant_id, status);
+CREATE INDEX IF NOT EXISTS idx_comments_tenant_status ON comments(tenant_id, status);
+CREATE INDEX IF NOT EXISTS idx_posts_author_status ON posts(author_id, status);
+CREATE INDEX IF NOT EXISTS idx_comments_author_status ON comments(author_id, status);
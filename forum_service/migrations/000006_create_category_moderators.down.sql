@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_category_moderators_user;
+DROP TABLE IF EXISTS category_moderators;
@@ -0,0 +1,12 @@
+-- Модераторы отдельных категорий: права модератора действуют только внутри назначенных категорий.
+-- Глобальные модераторы и администраторы определяются ролью в users.
+CREATE TABLE IF NOT EXISTS category_moderators (
+    tenant_id   TEXT NOT NULL DEFAULT 'default',
+    category_id TEXT NOT NULL,
+    user_id     TEXT NOT NULL,
+    created_by  TEXT NOT NULL DEFAULT '',
+    created_at  TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (tenant_id, category_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_category_moderators_user ON category_moderators(tenant_id, user_id);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_attachments_thumbnail_state;
+DROP INDEX IF EXISTS idx_attachments_owner;
+DROP INDEX IF EXISTS idx_attachments_target;
+DROP TABLE IF EXISTS attachments;
@@ -0,0 +1,22 @@
+-- Вложения: файл хранится в хранилище загрузок, здесь - его адрес и привязка к посту,
+-- комментарию или сообщению чата. Пока target_type пуст, вложение загружено, но не привязано.
+CREATE TABLE IF NOT EXISTS attachments (
+    id              TEXT PRIMARY KEY,
+    tenant_id       TEXT NOT NULL DEFAULT 'default',
+    owner_id        TEXT NOT NULL,
+    storage_key     TEXT NOT NULL,
+    url             TEXT NOT NULL,
+    content_type    TEXT NOT NULL,
+    size            INTEGER NOT NULL,
+    target_type     TEXT NOT NULL DEFAULT '',
+    target_id       TEXT NOT NULL DEFAULT '',
+    -- Уменьшенные копии изображений: JSON объект "имя варианта" -> URL
+    variants        TEXT NOT NULL DEFAULT '{}',
+    -- Состояние генерации вариантов: none (не изображение), pending, ready, failed
+    thumbnail_state TEXT NOT NULL DEFAULT 'none',
+    created_at      TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_attachments_target ON attachments(tenant_id, target_type, target_id);
+CREATE INDEX IF NOT EXISTS idx_attachments_owner ON attachments(tenant_id, owner_id);
+CREATE INDEX IF NOT EXISTS idx_attachments_thumbnail_state ON attachments(thumbnail_state);
@@ -0,0 +1,8 @@
+-- Профили пользователей форума. Учетные записи хранит auth сервис (таблица users),
+-- здесь только данные, которые показывает форум.
+CREATE TABLE IF NOT EXISTS user_profiles (
+    user_id     TEXT PRIMARY KEY,
+    avatar_url  TEXT NOT NULL DEFAULT '',
+    avatars     TEXT NOT NULL DEFAULT '{}',
+    updated_at  TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
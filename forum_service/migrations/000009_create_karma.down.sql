@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS user_karma;
+DROP INDEX IF EXISTS idx_karma_events_user;
+DROP TABLE IF EXISTS karma_events;
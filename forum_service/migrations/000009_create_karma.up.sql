@@ -0,0 +1,18 @@
+-- Карма пользователей. Каждое начисление хранится отдельно, чтобы смена голоса
+-- или снятие отметки ответа заменяли прежнее начисление, а не добавляли новое.
+CREATE TABLE IF NOT EXISTS karma_events (
+    user_id     TEXT NOT NULL,  -- Получатель кармы
+    source      TEXT NOT NULL,  -- post_vote, comment_vote, accepted_answer
+    source_id   TEXT NOT NULL,  -- Голос или принятый ответ, за который начислено
+    points      INTEGER NOT NULL,
+    created_at  TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (source, source_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_karma_events_user ON karma_events(user_id);
+
+-- Итоговая карма, чтобы не суммировать журнал при каждом показе автора
+CREATE TABLE IF NOT EXISTS user_karma (
+    user_id  TEXT PRIMARY KEY,
+    karma    INTEGER NOT NULL DEFAULT 0
+);
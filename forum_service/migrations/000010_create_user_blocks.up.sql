@@ -0,0 +1,10 @@
+-- Блокировки пользователей: blocker_id не видит постов, комментариев и сообщений чата blocked_id.
+-- Блокировка глобальная и действует во всех сообществах.
+CREATE TABLE IF NOT EXISTS user_blocks (
+    blocker_id  TEXT NOT NULL,  -- Кто заблокировал
+    blocked_id  TEXT NOT NULL,  -- Кого заблокировали
+    created_at  TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (blocker_id, blocked_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_blocks_blocked ON user_blocks(blocked_id);
@@ -0,0 +1,13 @@
+-- Короткие ссылки на посты (/s/{code}). У пользователя одна ссылка на пост,
+-- поэтому переходы считаются по тому, кто поделился.
+CREATE TABLE IF NOT EXISTS share_links (
+    code        TEXT PRIMARY KEY,
+    tenant_id   TEXT NOT NULL DEFAULT 'default',
+    post_id     TEXT NOT NULL,
+    created_by  TEXT NOT NULL,
+    clicks      INTEGER NOT NULL DEFAULT 0,
+    created_at  TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (tenant_id, post_id, created_by)
+);
+
+CREATE INDEX IF NOT EXISTS idx_share_links_post ON share_links(tenant_id, post_id);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS category_reads;
+DROP TABLE IF EXISTS post_reads;
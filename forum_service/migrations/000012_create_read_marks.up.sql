@@ -0,0 +1,17 @@
+-- Отметки о прочтении: когда пользователь последний раз видел пост или всю категорию.
+-- Пост непрочитан, если после обеих отметок в нем появилась чужая активность.
+CREATE TABLE IF NOT EXISTS post_reads (
+    user_id       TEXT NOT NULL,
+    tenant_id     TEXT NOT NULL DEFAULT 'default',
+    post_id       TEXT NOT NULL,
+    last_seen_at  TIMESTAMP NOT NULL,
+    PRIMARY KEY (user_id, tenant_id, post_id)
+);
+
+CREATE TABLE IF NOT EXISTS category_reads (
+    user_id       TEXT NOT NULL,
+    tenant_id     TEXT NOT NULL DEFAULT 'default',
+    category_id   TEXT NOT NULL,
+    last_seen_at  TIMESTAMP NOT NULL,
+    PRIMARY KEY (user_id, tenant_id, category_id)
+);
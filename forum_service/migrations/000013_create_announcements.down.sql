@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_announcements_active;
+DROP TABLE IF EXISTS announcements;
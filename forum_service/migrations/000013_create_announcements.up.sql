@@ -0,0 +1,16 @@
+-- Объявления администрации, которые фронтенд показывает баннером
+CREATE TABLE IF NOT EXISTS announcements (
+    id          TEXT PRIMARY KEY,
+    tenant_id   TEXT NOT NULL DEFAULT 'default',
+    title       TEXT NOT NULL,
+    body        TEXT NOT NULL DEFAULT '',
+    severity    TEXT NOT NULL DEFAULT 'info',  -- info, warning, critical
+    audience    TEXT NOT NULL DEFAULT 'all',   -- all, users, guests
+    starts_at   TIMESTAMP NOT NULL,            -- UTC RFC3339, сравнивается как строка
+    ends_at     TIMESTAMP,                     -- NULL - без срока
+    created_by  TEXT NOT NULL,
+    created_at  TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at  TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_announcements_active ON announcements(tenant_id, starts_at, ends_at);
@@ -0,0 +1,23 @@
+-- Категории форума. Раньше допустимые категории были зашиты в код ("1", "2", "3"),
+-- поэтому они создаются для сообщества по умолчанию вместе с категориями уже существующих постов.
+CREATE TABLE IF NOT EXISTS categories (
+    tenant_id   TEXT NOT NULL DEFAULT 'default',
+    id          TEXT NOT NULL,
+    name        TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    position    INTEGER NOT NULL DEFAULT 0, -- Порядок в списке категорий
+    created_at  TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at  TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (tenant_id, id)
+);
+
+INSERT OR IGNORE INTO categories (tenant_id, id, name, position, created_at, updated_at) VALUES
+    ('default', '1', 'Category 1', 1, strftime('%Y-%m-%dT%H:%M:%SZ', 'now'), strftime('%Y-%m-%dT%H:%M:%SZ', 'now')),
+    ('default', '2', 'Category 2', 2, strftime('%Y-%m-%dT%H:%M:%SZ', 'now'), strftime('%Y-%m-%dT%H:%M:%SZ', 'now')),
+    ('default', '3', 'Category 3', 3, strftime('%Y-%m-%dT%H:%M:%SZ', 'now'), strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+
+INSERT OR IGNORE INTO categories (tenant_id, id, name, position, created_at, updated_at)
+SELECT DISTINCT tenant_id, category_id, 'Category ' || category_id, 0,
+       strftime('%Y-%m-%dT%H:%M:%SZ', 'now'), strftime('%Y-%m-%dT%H:%M:%SZ', 'now')
+FROM posts
+WHERE category_id IS NOT NULL AND category_id <> '';
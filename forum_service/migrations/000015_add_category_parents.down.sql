@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_categories_parent;
+
+ALTER TABLE categories DROP COLUMN depth;
+ALTER TABLE categories DROP COLUMN parent_id;
@@ -0,0 +1,6 @@
+-- Подфорумы: категория может быть вложена в другую категорию того же сообщества.
+-- depth - уровень вложенности (0 у корневых категорий), хранится, чтобы ограничивать глубину без обхода дерева.
+ALTER TABLE categories ADD COLUMN parent_id TEXT; -- NULL - корневая категория
+ALTER TABLE categories ADD COLUMN depth INTEGER NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_categories_parent ON categories(tenant_id, parent_id);
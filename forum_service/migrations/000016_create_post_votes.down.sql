@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_posts_tenant_score;
+
+ALTER TABLE posts DROP COLUMN score;
+
+DROP TABLE IF EXISTS post_votes;
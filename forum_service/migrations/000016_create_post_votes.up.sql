@@ -0,0 +1,15 @@
+-- Голоса за посты: один голос пользователя за пост, повторный голос заменяет прежний.
+-- Итоговый счет хранится в posts.score, чтобы сортировать списки без агрегации голосов.
+CREATE TABLE IF NOT EXISTS post_votes (
+    tenant_id   TEXT NOT NULL DEFAULT 'default',
+    post_id     TEXT NOT NULL,
+    user_id     TEXT NOT NULL,
+    value       INTEGER NOT NULL, -- 1 - за, -1 - против
+    created_at  TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at  TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (tenant_id, post_id, user_id)
+);
+
+ALTER TABLE posts ADD COLUMN score INTEGER NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_posts_tenant_score ON posts(tenant_id, score);
@@ -0,0 +1,2 @@
+-- Закрытая модератором тема: новые комментарии к посту не принимаются
+ALTER TABLE posts ADD COLUMN is_locked INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_reports_tenant_created;
+
+DROP TABLE IF EXISTS reports;
@@ -0,0 +1,18 @@
+-- Жалобы пользователей на посты и комментарии. Пользователь жалуется на одну публикацию один раз.
+-- category_id - категория поста (для комментария - категория его поста), по ней жалобы
+-- показываются модераторам категорий.
+CREATE TABLE IF NOT EXISTS reports (
+    tenant_id   TEXT NOT NULL DEFAULT 'default',
+    id          TEXT NOT NULL,
+    target_type TEXT NOT NULL, -- post или comment
+    target_id   TEXT NOT NULL,
+    category_id TEXT NOT NULL,
+    reporter_id TEXT NOT NULL,
+    reason      TEXT NOT NULL,
+    details     TEXT NOT NULL DEFAULT '',
+    created_at  TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (tenant_id, id),
+    UNIQUE (tenant_id, target_type, target_id, reporter_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_reports_tenant_created ON reports(tenant_id, created_at);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_posts_tenant_slug;
+
+ALTER TABLE posts DROP COLUMN slug;
@@ -0,0 +1,7 @@
+-- Человекочитаемый адрес поста, уникальный в сообществе. Существующие посты получают slug
+-- по своему ID; пустой slug (посты из дампов) уникальностью не ограничен.
+ALTER TABLE posts ADD COLUMN slug TEXT NOT NULL DEFAULT '';
+
+UPDATE posts SET slug = id;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_posts_tenant_slug ON posts(tenant_id, slug) WHERE slug <> '';
@@ -0,0 +1,2 @@
+-- Принятый автором поста ответ (режим вопросов и ответов); пусто - ответ не выбран
+ALTER TABLE posts ADD COLUMN accepted_comment_id TEXT NOT NULL DEFAULT '';
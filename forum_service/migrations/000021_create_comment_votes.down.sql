@@ -0,0 +1,3 @@
+ALTER TABLE comments DROP COLUMN score;
+
+DROP TABLE IF EXISTS comment_votes;
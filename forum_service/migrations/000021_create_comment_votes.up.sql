@@ -0,0 +1,13 @@
+-- Голоса за комментарии: как post_votes, итоговый счет хранится в comments.score.
+-- Голоса начисляют карму автору комментария (karma_events.source = comment_vote).
+CREATE TABLE IF NOT EXISTS comment_votes (
+    tenant_id   TEXT NOT NULL DEFAULT 'default',
+    comment_id  TEXT NOT NULL,
+    user_id     TEXT NOT NULL,
+    value       INTEGER NOT NULL, -- 1 - за, -1 - против
+    created_at  TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at  TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (tenant_id, comment_id, user_id)
+);
+
+ALTER TABLE comments ADD COLUMN score INTEGER NOT NULL DEFAULT 0;
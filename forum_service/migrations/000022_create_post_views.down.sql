@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_post_views_hour;
+
+DROP TABLE IF EXISTS post_views;
@@ -0,0 +1,11 @@
+-- Просмотры постов по часам для популярных постов. Счетчик за час вместо строки на просмотр,
+-- чтобы таблица росла по числу активных постов, а не по трафику.
+CREATE TABLE IF NOT EXISTS post_views (
+    tenant_id  TEXT NOT NULL DEFAULT 'default',
+    post_id    TEXT NOT NULL,
+    hour       TIMESTAMP NOT NULL, -- Начало часа в UTC
+    views      INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (tenant_id, post_id, hour)
+);
+
+CREATE INDEX IF NOT EXISTS idx_post_views_hour ON post_views(hour);
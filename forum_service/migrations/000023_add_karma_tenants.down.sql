@@ -0,0 +1,28 @@
+-- Начисления всех сообществ снова складываются в общую карму
+CREATE TABLE karma_events_old (
+    user_id     TEXT NOT NULL,
+    source      TEXT NOT NULL,
+    source_id   TEXT NOT NULL,
+    points      INTEGER NOT NULL,
+    created_at  TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (source, source_id, user_id)
+);
+
+INSERT OR IGNORE INTO karma_events_old (user_id, source, source_id, points, created_at)
+SELECT user_id, source, source_id, points, created_at FROM karma_events;
+
+DROP INDEX IF EXISTS idx_karma_events_tenant_user;
+DROP TABLE karma_events;
+ALTER TABLE karma_events_old RENAME TO karma_events;
+CREATE INDEX IF NOT EXISTS idx_karma_events_user ON karma_events(user_id);
+
+CREATE TABLE user_karma_old (
+    user_id  TEXT PRIMARY KEY,
+    karma    INTEGER NOT NULL DEFAULT 0
+);
+
+INSERT INTO user_karma_old (user_id, karma)
+SELECT user_id, SUM(points) FROM karma_events GROUP BY user_id;
+
+DROP TABLE user_karma;
+ALTER TABLE user_karma_old RENAME TO user_karma;
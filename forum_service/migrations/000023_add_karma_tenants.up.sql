@@ -0,0 +1,43 @@
+-- Карма считается в каждом сообществе отдельно, как посты и комментарии, за которые она начислена.
+-- SQLite не меняет первичный ключ существующей таблицы, поэтому таблицы пересоздаются.
+CREATE TABLE karma_events_new (
+    tenant_id   TEXT NOT NULL DEFAULT 'default',
+    user_id     TEXT NOT NULL,
+    source      TEXT NOT NULL,
+    source_id   TEXT NOT NULL,
+    points      INTEGER NOT NULL,
+    created_at  TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (tenant_id, source, source_id, user_id)
+);
+
+-- Сообщество начисления берется из поста или комментария источника (source_id голоса - "цель:голосующий")
+INSERT INTO karma_events_new (tenant_id, user_id, source, source_id, points, created_at)
+SELECT COALESCE(
+           CASE k.source
+               WHEN 'post_vote' THEN (SELECT p.tenant_id FROM posts p
+                                      WHERE p.id = substr(k.source_id, 1, instr(k.source_id, ':') - 1))
+               WHEN 'comment_vote' THEN (SELECT c.tenant_id FROM comments c
+                                         WHERE c.id = substr(k.source_id, 1, instr(k.source_id, ':') - 1))
+               ELSE (SELECT c.tenant_id FROM comments c WHERE c.id = k.source_id)
+           END, 'default'),
+       k.user_id, k.source, k.source_id, k.points, k.created_at
+FROM karma_events k;
+
+DROP INDEX IF EXISTS idx_karma_events_user;
+DROP TABLE karma_events;
+ALTER TABLE karma_events_new RENAME TO karma_events;
+CREATE INDEX IF NOT EXISTS idx_karma_events_tenant_user ON karma_events(tenant_id, user_id);
+
+CREATE TABLE user_karma_new (
+    tenant_id  TEXT NOT NULL DEFAULT 'default',
+    user_id    TEXT NOT NULL,
+    karma      INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (tenant_id, user_id)
+);
+
+-- Итог пересчитывается из журнала, т.к. прежний итог общий для всех сообществ
+INSERT INTO user_karma_new (tenant_id, user_id, karma)
+SELECT tenant_id, user_id, SUM(points) FROM karma_events GROUP BY tenant_id, user_id;
+
+DROP TABLE user_karma;
+ALTER TABLE user_karma_new RENAME TO user_karma;
@@ -0,0 +1,112 @@
+// Package audit журнал действий, важных для безопасности. Таблица audit_log общая
+// для auth сервиса и форума, создается миграциями auth сервиса.
+package audit
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Entry запись журнала
+type Entry struct {
+	ID            int64     `json:"id"`
+	ActorID       string    `json:"actor_id"`                  // Пользователь, от имени которого выполнено действие
+	ActingAdminID string    `json:"acting_admin_id,omitempty"` // Администратор, если действие выполнено под имперсонацией
+	Action        string    `json:"action"`
+	TargetType    string    `json:"target_type,omitempty"`
+	TargetID      string    `json:"target_id,omitempty"`
+	Details       string    `json:"details,omitempty"`
+	IP            string    `json:"ip,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// Filter условия выборки записей; пустые поля не ограничивают выборку
+type Filter struct {
+	ActorID       string
+	ActingAdminID string
+	Action        string
+	Limit         int
+	Offset        int
+}
+
+// Store хранит записи в таблице audit_log
+type Store struct {
+	db *sql.DB
+}
+
+func NewStore(db *sql.DB) *Store {
+	return &Store{db: db}
+}
+
+// Write добавляет запись. Время проставляется, если не задано.
+func (s *Store) Write(ctx context.Context, e *Entry) error {
+	if e.CreatedAt.IsZero() {
+		e.CreatedAt = time.Now().UTC()
+	}
+
+	result, err := s.db.ExecContext(ctx,
+		`INSERT INTO audit_log (actor_id, acting_admin_id, action, target_type, target_id, details, ip, created_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		e.ActorID, e.ActingAdminID, e.Action, e.TargetType, e.TargetID, e.Details, e.IP,
+		e.CreatedAt.Format(time.RFC3339))
+	if err != nil {
+		return fmt.Errorf("failed to write audit entry: %w", err)
+	}
+
+	e.ID, _ = result.LastInsertId()
+	return nil
+}
+
+// List возвращает записи, новые первыми
+func (s *Store) List(ctx context.Context, f Filter) ([]*Entry, error) {
+	var conds []string
+	var args []interface{}
+	if f.ActorID != "" {
+		conds = append(conds, "actor_id = ?")
+		args = append(args, f.ActorID)
+	}
+	if f.ActingAdminID != "" {
+		conds = append(conds, "acting_admin_id = ?")
+		args = append(args, f.ActingAdminID)
+	}
+	if f.Action != "" {
+		conds = append(conds, "action = ?")
+		args = append(args, f.Action)
+	}
+
+	query := `SELECT id, actor_id, acting_admin_id, action, target_type, target_id, details, ip, created_at FROM audit_log`
+	if len(conds) > 0 {
+		query += " WHERE " + strings.Join(conds, " AND ")
+	}
+	query += " ORDER BY id DESC LIMIT ? OFFSET ?"
+
+	limit := f.Limit
+	if limit <= 0 {
+		limit = 50
+	}
+	args = append(args, limit, f.Offset)
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit entries: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []*Entry{}
+	for rows.Next() {
+		var e Entry
+		var createdAt string
+		if err := rows.Scan(&e.ID, &e.ActorID, &e.ActingAdminID, &e.Action, &e.TargetType, &e.TargetID, &e.Details, &e.IP, &createdAt); err != nil {
+			return nil, fmt.Errorf("failed to scan audit entry: %w", err)
+		}
+		if e.CreatedAt, err = time.Parse(time.RFC3339, createdAt); err != nil {
+			return nil, fmt.Errorf("failed to parse created_at: %w", err)
+		}
+		entries = append(entries, &e)
+	}
+
+	return entries, rows.Err()
+}
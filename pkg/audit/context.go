@@ -0,0 +1,16 @@
+package audit
+
+import "context"
+
+type actingAdminKey struct{}
+
+// WithActingAdmin помечает контекст запроса, выполняемого администратором под имперсонацией
+func WithActingAdmin(ctx context.Context, adminID string) context.Context {
+	return context.WithValue(ctx, actingAdminKey{}, adminID)
+}
+
+// ActingAdminFromContext возвращает ID администратора, если запрос выполняется под имперсонацией
+func ActingAdminFromContext(ctx context.Context) (string, bool) {
+	adminID, ok := ctx.Value(actingAdminKey{}).(string)
+	return adminID, ok && adminID != ""
+}
@@ -0,0 +1,65 @@
+package audit
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+)
+
+// ActionRequest действие "запрос к API", которым журналируются запросы под имперсонацией
+const ActionRequest = "impersonation.request"
+
+// Request оборачивает обработчик запроса, выполняемого adminID от имени actorID:
+// после ответа в журнал пишется метод, путь и код ответа. Ошибка записи передается в onError
+// и не влияет на ответ.
+func Request(store *Store, actorID, adminID string, next http.Handler, onError func(error)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+
+		ip, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			ip = r.RemoteAddr
+		}
+
+		err = store.Write(r.Context(), &Entry{
+			ActorID:       actorID,
+			ActingAdminID: adminID,
+			Action:        ActionRequest,
+			TargetType:    "http",
+			TargetID:      r.Method + " " + r.URL.Path,
+			Details:       fmt.Sprintf("status=%d", rec.status),
+			IP:            ip,
+		})
+		if err != nil && onError != nil {
+			onError(err)
+		}
+	})
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Unwrap нужен http.ResponseController (websocket, flush)
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// Hijack нужен websocket соединениям
+func (r *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := r.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("response writer does not support hijacking")
+	}
+	r.status = http.StatusSwitchingProtocols
+	return h.Hijack()
+}
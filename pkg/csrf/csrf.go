@@ -0,0 +1,112 @@
+// Package csrf реализует защиту от CSRF по схеме double-submit cookie
+// для сервисов, принимающих аутентификацию через cookie.
+//
+// При входе сервис выдает токен в cookie, доступной JavaScript (Issue).
+// Клиент повторяет его в заголовке X-CSRF-Token для всех изменяющих запросов,
+// а Protect сверяет заголовок с cookie. Сторонний сайт cookie прочитать не может.
+package csrf
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"net/http"
+	"time"
+)
+
+const (
+	CookieName = "csrf_token"
+	HeaderName = "X-CSRF-Token"
+
+	tokenSize = 32
+)
+
+// NewToken генерирует случайный токен
+func NewToken() (string, error) {
+	b := make([]byte, tokenSize)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// Issue создает новый токен и устанавливает его в cookie. Cookie не HttpOnly,
+// чтобы клиент мог прочитать токен и отправить его в заголовке.
+func Issue(w http.ResponseWriter, secure bool, ttl time.Duration) (string, error) {
+	token, err := NewToken()
+	if err != nil {
+		return "", err
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     CookieName,
+		Value:    token,
+		Path:     "/",
+		MaxAge:   int(ttl.Seconds()),
+		Secure:   secure,
+		SameSite: http.SameSiteStrictMode,
+	})
+
+	return token, nil
+}
+
+// Clear удаляет cookie с токеном
+func Clear(w http.ResponseWriter, secure bool) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     CookieName,
+		Value:    "",
+		Path:     "/",
+		MaxAge:   -1,
+		Secure:   secure,
+		SameSite: http.SameSiteStrictMode,
+	})
+}
+
+// Protect проверяет CSRF токен у изменяющих запросов, аутентифицированных cookie authCookie.
+// Запросы с заголовком Authorization пропускаются: браузер не добавляет его сам,
+// поэтому подделать такой запрос со стороннего сайта нельзя.
+func Protect(authCookie string) func(http.Handler) http.Handler {
+	return ProtectWith(authCookie, nil)
+}
+
+// ProtectWith как Protect, но ответ на отклоненный запрос формирует deny; nil - 403 с текстом
+func ProtectWith(authCookie string, deny http.HandlerFunc) func(http.Handler) http.Handler {
+	if deny == nil {
+		deny = func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "CSRF token missing or invalid", http.StatusForbidden)
+		}
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if isSafeMethod(r.Method) || r.Header.Get("Authorization") != "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if _, err := r.Cookie(authCookie); err != nil {
+				// Без cookie аутентификации подделывать нечего
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			cookie, err := r.Cookie(CookieName)
+			header := r.Header.Get(HeaderName)
+			if err != nil || cookie.Value == "" || header == "" ||
+				subtle.ConstantTimeCompare([]byte(cookie.Value), []byte(header)) != 1 {
+				deny(w, r)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func isSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,120 @@
+package csrf
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestProtect(t *testing.T) {
+	const authCookie = "access_token"
+
+	handler := Protect(authCookie)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		name    string
+		method  string
+		cookies map[string]string
+		headers map[string]string
+		want    int
+	}{
+		{
+			name:    "safe method without token",
+			method:  http.MethodGet,
+			cookies: map[string]string{authCookie: "jwt"},
+			want:    http.StatusOK,
+		},
+		{
+			name:    "matching token",
+			method:  http.MethodPost,
+			cookies: map[string]string{authCookie: "jwt", CookieName: "token"},
+			headers: map[string]string{HeaderName: "token"},
+			want:    http.StatusOK,
+		},
+		{
+			name:    "missing header",
+			method:  http.MethodPost,
+			cookies: map[string]string{authCookie: "jwt", CookieName: "token"},
+			want:    http.StatusForbidden,
+		},
+		{
+			name:    "missing csrf cookie",
+			method:  http.MethodDelete,
+			cookies: map[string]string{authCookie: "jwt"},
+			headers: map[string]string{HeaderName: "token"},
+			want:    http.StatusForbidden,
+		},
+		{
+			name:    "token mismatch",
+			method:  http.MethodPut,
+			cookies: map[string]string{authCookie: "jwt", CookieName: "token"},
+			headers: map[string]string{HeaderName: "other"},
+			want:    http.StatusForbidden,
+		},
+		{
+			name:    "empty token in cookie and header",
+			method:  http.MethodPost,
+			cookies: map[string]string{authCookie: "jwt", CookieName: ""},
+			headers: map[string]string{HeaderName: ""},
+			want:    http.StatusForbidden,
+		},
+		{
+			name:    "bearer token instead of cookie",
+			method:  http.MethodPost,
+			cookies: map[string]string{authCookie: "jwt"},
+			headers: map[string]string{"Authorization": "Bearer jwt"},
+			want:    http.StatusOK,
+		},
+		{
+			name:   "no auth cookie",
+			method: http.MethodPost,
+			want:   http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/", nil)
+			for name, value := range tt.cookies {
+				req.AddCookie(&http.Cookie{Name: name, Value: value})
+			}
+			for key, value := range tt.headers {
+				req.Header.Set(key, value)
+			}
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Fatalf("status %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestIssue(t *testing.T) {
+	rec := httptest.NewRecorder()
+	token, err := Issue(rec, true, time.Hour)
+	if err != nil {
+		t.Fatalf("issue: %v", err)
+	}
+
+	cookies := rec.Result().Cookies()
+	if len(cookies) != 1 {
+		t.Fatalf("got %d cookies, want 1", len(cookies))
+	}
+	cookie := cookies[0]
+	if cookie.Name != CookieName || cookie.Value != token || cookie.HttpOnly || !cookie.Secure || cookie.SameSite != http.SameSiteStrictMode {
+		t.Fatalf("unexpected cookie %+v", cookie)
+	}
+
+	other, err := NewToken()
+	if err != nil {
+		t.Fatalf("new token: %v", err)
+	}
+	if other == token {
+		t.Fatal("tokens are not random")
+	}
+}
@@ -0,0 +1,74 @@
+// Package grpcerr собирает ошибки gRPC с деталями google.rpc.Status: причиной ErrorInfo
+// и нарушениями полей BadRequest, чтобы клиенты обрабатывали их без разбора текста.
+package grpcerr
+
+import (
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+)
+
+// Violation нарушение проверки одного поля запроса
+type Violation = errdetails.BadRequest_FieldViolation
+
+// Field описывает нарушение: field - имя поля как в proto (post_id, update_mask.paths)
+func Field(field, description string) *Violation {
+	return &Violation{Field: field, Description: description}
+}
+
+// New ошибка с кодом code и ErrorInfo{Reason: reason, Domain: domain}.
+// reason - постоянный код в UPPER_SNAKE_CASE, на который опираются клиенты.
+func New(code codes.Code, domain, reason, message string) error {
+	return withDetails(status.New(code, message), &errdetails.ErrorInfo{
+		Reason: reason,
+		Domain: domain,
+	})
+}
+
+// InvalidArgument ошибка INVALID_ARGUMENT с ErrorInfo и списком нарушений полей
+func InvalidArgument(domain, reason, message string, violations ...*Violation) error {
+	details := []protoadapt.MessageV1{&errdetails.ErrorInfo{Reason: reason, Domain: domain}}
+	if len(violations) > 0 {
+		details = append(details, &errdetails.BadRequest{FieldViolations: violations})
+	}
+	return withDetails(status.New(codes.InvalidArgument, message), details...)
+}
+
+// Reason возвращает причину из ErrorInfo ошибки gRPC или пустую строку
+func Reason(err error) string {
+	st, ok := status.FromError(err)
+	if !ok {
+		return ""
+	}
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok {
+			return info.Reason
+		}
+	}
+	return ""
+}
+
+// Violations возвращает нарушения полей из BadRequest ошибки gRPC
+func Violations(err error) []*Violation {
+	st, ok := status.FromError(err)
+	if !ok {
+		return nil
+	}
+	for _, detail := range st.Details() {
+		if br, ok := detail.(*errdetails.BadRequest); ok {
+			return br.FieldViolations
+		}
+	}
+	return nil
+}
+
+// withDetails добавляет детали к статусу. Детали - известные сообщения errdetails,
+// поэтому ошибка сериализации не ожидается; при ней возвращается статус без деталей.
+func withDetails(st *status.Status, details ...protoadapt.MessageV1) error {
+	detailed, err := st.WithDetails(details...)
+	if err != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
@@ -0,0 +1,3 @@
+module github.com/kprf42/dolgova/pkg/i18n
+
+go 1.24.2
@@ -0,0 +1,115 @@
+// Package i18n переводит сообщения об ошибках API по кодам
+// с выбором языка по заголовку Accept-Language.
+package i18n
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Поддерживаемые языки
+const (
+	EN = "en"
+	RU = "ru"
+)
+
+// Bundle каталог сообщений: код -> язык -> текст
+type Bundle struct {
+	fallback string
+	messages map[string]map[string]string
+}
+
+// NewBundle создает каталог; fallback используется, если клиент не указал поддерживаемый язык
+func NewBundle(fallback string) *Bundle {
+	return &Bundle{
+		fallback: fallback,
+		messages: make(map[string]map[string]string),
+	}
+}
+
+// Add добавляет переводы на язык lang
+func (b *Bundle) Add(lang string, messages map[string]string) *Bundle {
+	for code, text := range messages {
+		if b.messages[code] == nil {
+			b.messages[code] = make(map[string]string)
+		}
+		b.messages[code][lang] = text
+	}
+	return b
+}
+
+// Message возвращает текст для кода на языке lang, затем на языке по умолчанию, иначе сам код
+func (b *Bundle) Message(lang, code string) string {
+	translations := b.messages[code]
+	if text, ok := translations[lang]; ok {
+		return text
+	}
+	if text, ok := translations[b.fallback]; ok {
+		return text
+	}
+	return code
+}
+
+// Lang выбирает язык ответа по заголовку Accept-Language запроса
+func (b *Bundle) Lang(r *http.Request) string {
+	return b.Negotiate(r.Header.Get("Accept-Language"))
+}
+
+// Negotiate выбирает поддерживаемый язык с наибольшим q из Accept-Language
+// (например "ru-RU,ru;q=0.9,en;q=0.8")
+func (b *Bundle) Negotiate(acceptLanguage string) string {
+	type candidate struct {
+		lang string
+		q    float64
+	}
+
+	var candidates []candidate
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if tag == "" {
+			continue
+		}
+
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q <= 0 {
+			continue
+		}
+
+		// Региональный вариант сводим к базовому языку: ru-RU -> ru
+		base, _, _ := strings.Cut(strings.ToLower(tag), "-")
+		candidates = append(candidates, candidate{lang: base, q: q})
+	}
+
+	// Стабильная сортировка сохраняет порядок клиента при равных q
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].q > candidates[j].q
+	})
+
+	for _, c := range candidates {
+		if b.supports(c.lang) {
+			return c.lang
+		}
+	}
+	return b.fallback
+}
+
+func (b *Bundle) supports(lang string) bool {
+	if lang == b.fallback {
+		return true
+	}
+	for _, translations := range b.messages {
+		if _, ok := translations[lang]; ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,128 @@
+// Package ipban блокирует запросы с забаненных адресов и подсетей.
+// Баны хранятся в таблице ip_bans общей БД и кешируются в памяти каждого сервиса;
+// кеш перечитывается периодически и сразу после изменений через Refresh.
+package ipban
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/netip"
+	"sync"
+	"time"
+
+	"github.com/kprf42/dolgova/pkg/logger"
+)
+
+type entry struct {
+	prefix    netip.Prefix
+	expiresAt *time.Time
+}
+
+// Checker проверяет адреса по закешированному списку банов
+type Checker struct {
+	store *Store
+	log   *logger.Logger
+
+	mu      sync.RWMutex
+	entries []entry
+}
+
+func NewChecker(store *Store, log *logger.Logger) *Checker {
+	return &Checker{
+		store: store,
+		log:   log,
+	}
+}
+
+// Refresh перечитывает действующие баны из БД
+func (c *Checker) Refresh(ctx context.Context) error {
+	bans, err := c.store.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	entries := make([]entry, 0, len(bans))
+	for _, ban := range bans {
+		if !ban.Active(now) {
+			continue
+		}
+		prefix, err := ParsePrefix(ban.CIDR)
+		if err != nil {
+			c.log.Warn("Skipping invalid ip ban",
+				logger.Int64("ban_id", ban.ID),
+				logger.String("cidr", ban.CIDR))
+			continue
+		}
+		entries = append(entries, entry{prefix: prefix, expiresAt: ban.ExpiresAt})
+	}
+
+	c.mu.Lock()
+	c.entries = entries
+	c.mu.Unlock()
+	return nil
+}
+
+// Watch периодически перечитывает баны, чтобы подхватить изменения из другого сервиса
+func (c *Checker) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := c.Refresh(ctx); err != nil {
+				// Остаемся со старым списком до следующей попытки
+				c.log.Error("Failed to refresh ip bans", logger.Error(err))
+			}
+		}
+	}
+}
+
+// Banned сообщает, заблокирован ли адрес. Истекшие баны не учитываются.
+func (c *Checker) Banned(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	now := time.Now()
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for _, e := range c.entries {
+		if e.prefix.Contains(addr) && (e.expiresAt == nil || now.Before(*e.expiresAt)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Middleware отклоняет запросы с забаненных адресов. Адрес берется из r.RemoteAddr,
+// поэтому middleware должен стоять после RealIP этого пакета. deny формирует ответ; nil - 403 с текстом.
+func Middleware(checker *Checker, deny http.HandlerFunc) func(http.Handler) http.Handler {
+	if deny == nil {
+		deny = func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "access denied", http.StatusForbidden)
+		}
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if addr, ok := remoteAddr(r); ok && checker.Banned(addr) {
+				deny(w, r)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func remoteAddr(r *http.Request) (netip.Addr, bool) {
+	host := r.RemoteAddr
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	addr, err := netip.ParseAddr(host)
+	return addr, err == nil
+}
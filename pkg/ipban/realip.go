@@ -0,0 +1,74 @@
+package ipban
+
+import (
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// ParsePrefixes разбирает список адресов и подсетей через запятую, например TRUSTED_PROXIES
+func ParsePrefixes(value string) ([]netip.Prefix, error) {
+	var prefixes []netip.Prefix
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		prefix, err := ParsePrefix(item)
+		if err != nil {
+			return nil, err
+		}
+		prefixes = append(prefixes, prefix)
+	}
+	return prefixes, nil
+}
+
+// RealIP заменяет r.RemoteAddr адресом клиента из X-Forwarded-For или X-Real-IP, но только если
+// запрос пришел от доверенного прокси из trusted. Иначе заголовки игнорируются: их мог прислать
+// сам клиент, чтобы обойти бан или блокировку входа по адресу. В X-Forwarded-For клиентом
+// считается самый правый адрес вне trusted - все, что левее, прокси лишь передали дальше.
+// Без trusted адрес не подменяется никогда.
+func RealIP(trusted []netip.Prefix) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if peer, ok := remoteAddr(r); ok && contains(trusted, peer) {
+				if client, ok := forwardedClient(r, trusted); ok {
+					r.RemoteAddr = client.String()
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// forwardedClient адрес клиента по заголовкам запроса от доверенного прокси
+func forwardedClient(r *http.Request, trusted []netip.Prefix) (netip.Addr, bool) {
+	if header := r.Header.Values("X-Forwarded-For"); len(header) > 0 {
+		hops := strings.Split(strings.Join(header, ","), ",")
+		for i := len(hops) - 1; i >= 0; i-- {
+			addr, err := netip.ParseAddr(strings.TrimSpace(hops[i]))
+			if err != nil {
+				return netip.Addr{}, false
+			}
+			if !contains(trusted, addr) || i == 0 {
+				return addr.Unmap(), true
+			}
+		}
+	}
+
+	addr, err := netip.ParseAddr(strings.TrimSpace(r.Header.Get("X-Real-IP")))
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	return addr.Unmap(), true
+}
+
+func contains(prefixes []netip.Prefix, addr netip.Addr) bool {
+	addr = addr.Unmap()
+	for _, prefix := range prefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,100 @@
+package ipban
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+)
+
+// Поддельные заголовки прокси не должны снимать бан с клиента
+func TestBanWithSpoofedHeader(t *testing.T) {
+	checker := &Checker{entries: []entry{{prefix: netip.MustParsePrefix("203.0.113.0/24")}}}
+	trusted := []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}
+
+	handler := RealIP(trusted)(Middleware(checker, nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})))
+
+	tests := []struct {
+		name    string
+		remote  string
+		headers map[string]string
+		want    int
+	}{
+		{
+			name:    "banned client with spoofed X-Forwarded-For",
+			remote:  "203.0.113.7:5000",
+			headers: map[string]string{"X-Forwarded-For": "198.51.100.1"},
+			want:    http.StatusForbidden,
+		},
+		{
+			name:    "banned client with spoofed X-Real-IP",
+			remote:  "203.0.113.7:5000",
+			headers: map[string]string{"X-Real-IP": "198.51.100.1"},
+			want:    http.StatusForbidden,
+		},
+		{
+			name:    "banned client with spoofed True-Client-IP",
+			remote:  "203.0.113.7:5000",
+			headers: map[string]string{"True-Client-IP": "198.51.100.1"},
+			want:    http.StatusForbidden,
+		},
+		{
+			name:    "banned client behind trusted proxy",
+			remote:  "10.0.0.2:5000",
+			headers: map[string]string{"X-Forwarded-For": "203.0.113.7"},
+			want:    http.StatusForbidden,
+		},
+		{
+			name:    "banned client prepends spoofed hop behind trusted proxy",
+			remote:  "10.0.0.2:5000",
+			headers: map[string]string{"X-Forwarded-For": "198.51.100.1, 203.0.113.7, 10.0.0.3"},
+			want:    http.StatusForbidden,
+		},
+		{
+			name:    "spoofing a banned address does not ban the client",
+			remote:  "198.51.100.1:5000",
+			headers: map[string]string{"X-Forwarded-For": "203.0.113.7"},
+			want:    http.StatusOK,
+		},
+		{
+			name:    "allowed client behind trusted proxy",
+			remote:  "10.0.0.2:5000",
+			headers: map[string]string{"X-Real-IP": "198.51.100.1"},
+			want:    http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remote
+			for key, value := range tt.headers {
+				req.Header.Set(key, value)
+			}
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Fatalf("status %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestRealIPWithoutTrustedProxies(t *testing.T) {
+	var got string
+	handler := RealIP(nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.RemoteAddr
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "198.51.100.1:5000"
+	req.Header.Set("X-Forwarded-For", "203.0.113.7")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	if got != "198.51.100.1:5000" {
+		t.Fatalf("remote addr %q, want original", got)
+	}
+}
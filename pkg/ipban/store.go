@@ -0,0 +1,130 @@
+package ipban
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/netip"
+	"time"
+)
+
+// ErrNotFound бан не найден
+var ErrNotFound = errors.New("ip ban not found")
+
+// Ban блокировка адреса или подсети
+type Ban struct {
+	ID        int64      `json:"id"`
+	CIDR      string     `json:"cidr"` // Подсеть в каноническом виде, одиночный адрес - /32 или /128
+	Reason    string     `json:"reason"`
+	CreatedBy string     `json:"created_by"`
+	CreatedAt time.Time  `json:"created_at"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"` // nil - бессрочно
+}
+
+// Active сообщает, действует ли бан в момент now
+func (b *Ban) Active(now time.Time) bool {
+	return b.ExpiresAt == nil || now.Before(*b.ExpiresAt)
+}
+
+// ParsePrefix разбирает адрес или подсеть (1.2.3.4, 10.0.0.0/8, 2001:db8::/32)
+func ParsePrefix(value string) (netip.Prefix, error) {
+	if prefix, err := netip.ParsePrefix(value); err == nil {
+		return prefix.Masked(), nil
+	}
+
+	addr, err := netip.ParseAddr(value)
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("invalid address or CIDR %q", value)
+	}
+	addr = addr.Unmap()
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
+
+// Store хранит баны в таблице ip_bans
+type Store struct {
+	db *sql.DB
+}
+
+func NewStore(db *sql.DB) *Store {
+	return &Store{db: db}
+}
+
+// List возвращает все баны, включая истекшие, новые первыми
+func (s *Store) List(ctx context.Context) ([]*Ban, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT id, cidr, reason, created_by, created_at, expires_at FROM ip_bans ORDER BY id DESC`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ip bans: %w", err)
+	}
+	defer rows.Close()
+
+	bans := []*Ban{}
+	for rows.Next() {
+		var ban Ban
+		var createdAt string
+		var expiresAt sql.NullString
+		if err := rows.Scan(&ban.ID, &ban.CIDR, &ban.Reason, &ban.CreatedBy, &createdAt, &expiresAt); err != nil {
+			return nil, fmt.Errorf("failed to scan ip ban: %w", err)
+		}
+
+		if ban.CreatedAt, err = time.Parse(time.RFC3339, createdAt); err != nil {
+			return nil, fmt.Errorf("failed to parse created_at: %w", err)
+		}
+		if expiresAt.Valid {
+			t, err := time.Parse(time.RFC3339, expiresAt.String)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse expires_at: %w", err)
+			}
+			ban.ExpiresAt = &t
+		}
+
+		bans = append(bans, &ban)
+	}
+
+	return bans, rows.Err()
+}
+
+// Add сохраняет бан и заполняет его ID. CIDR приводится к каноническому виду.
+func (s *Store) Add(ctx context.Context, ban *Ban) error {
+	prefix, err := ParsePrefix(ban.CIDR)
+	if err != nil {
+		return err
+	}
+	ban.CIDR = prefix.String()
+	if ban.CreatedAt.IsZero() {
+		ban.CreatedAt = time.Now().UTC()
+	}
+
+	var expiresAt interface{}
+	if ban.ExpiresAt != nil {
+		expiresAt = ban.ExpiresAt.UTC().Format(time.RFC3339)
+	}
+
+	result, err := s.db.ExecContext(ctx,
+		`INSERT INTO ip_bans (cidr, reason, created_by, created_at, expires_at) VALUES (?, ?, ?, ?, ?)`,
+		ban.CIDR, ban.Reason, ban.CreatedBy, ban.CreatedAt.Format(time.RFC3339), expiresAt)
+	if err != nil {
+		return fmt.Errorf("failed to add ip ban: %w", err)
+	}
+
+	ban.ID, err = result.LastInsertId()
+	return err
+}
+
+// Delete снимает бан
+func (s *Store) Delete(ctx context.Context, id int64) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM ip_bans WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete ip ban: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+// Package lifecycle запускает долгоживущие компоненты сервиса и
+// останавливает их в заданном порядке с общим дедлайном
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/kprf42/dolgova/pkg/logger"
+)
+
+// StartFunc блокирующий запуск компонента. Возвращает nil после штатной остановки.
+type StartFunc func() error
+
+// StopFunc останавливает компонент, не дольше дедлайна контекста
+type StopFunc func(ctx context.Context) error
+
+type component struct {
+	name  string
+	start StartFunc
+	stop  StopFunc
+}
+
+// Manager управляет запуском и остановкой компонентов
+type Manager struct {
+	components []component
+	timeout    time.Duration
+	log        *logger.Logger
+}
+
+// New создает менеджер с общим таймаутом на остановку всех компонентов
+func New(timeout time.Duration, log *logger.Logger) *Manager {
+	return &Manager{
+		timeout: timeout,
+		log:     log,
+	}
+}
+
+// Add регистрирует компонент. Компоненты останавливаются в обратном порядке регистрации:
+// то, что зарегистрировано последним (обычно серверы), перестает принимать запросы первым.
+// start может быть nil, если компонент уже запущен; stop может быть nil, если останавливать нечего.
+func (m *Manager) Add(name string, start StartFunc, stop StopFunc) {
+	m.components = append(m.components, component{
+		name:  name,
+		start: start,
+		stop:  stop,
+	})
+}
+
+// Run запускает компоненты и ждет SIGINT/SIGTERM, отмены ctx или ошибки любого компонента,
+// после чего останавливает все компоненты
+func (m *Manager) Run(ctx context.Context) error {
+	ctx, stop := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	errCh := make(chan error, len(m.components))
+	for _, c := range m.components {
+		if c.start == nil {
+			continue
+		}
+
+		m.log.Info("Starting component", logger.String("component", c.name))
+		go func(c component) {
+			if err := c.start(); err != nil {
+				errCh <- fmt.Errorf("%s: %w", c.name, err)
+			}
+		}(c)
+	}
+
+	var runErr error
+	select {
+	case <-ctx.Done():
+		m.log.Info("Shutdown signal received")
+	case runErr = <-errCh:
+		m.log.Error("Component failed, shutting down", logger.Error(runErr))
+	}
+
+	return errors.Join(runErr, m.Shutdown())
+}
+
+// Shutdown останавливает компоненты по очереди в пределах общего таймаута
+func (m *Manager) Shutdown() error {
+	ctx, cancel := context.WithTimeout(context.Background(), m.timeout)
+	defer cancel()
+
+	var errs []error
+	for i := len(m.components) - 1; i >= 0; i-- {
+		c := m.components[i]
+		if c.stop == nil {
+			continue
+		}
+
+		m.log.Info("Stopping component", logger.String("component", c.name))
+		if err := c.stop(ctx); err != nil {
+			m.log.Error("Failed to stop component",
+				logger.String("component", c.name),
+				logger.Error(err))
+			errs = append(errs, fmt.Errorf("%s: %w", c.name, err))
+		}
+	}
+
+	if len(errs) == 0 {
+		m.log.Info("All components stopped gracefully")
+	}
+
+	return errors.Join(errs...)
+}
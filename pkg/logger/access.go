@@ -0,0 +1,123 @@
+package logger
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// accessFields поля записи access-лога, которые обработчики добавляют по ходу запроса
+// (например, user_id после аутентификации) через AddToContext
+type accessFields struct {
+	mu     sync.Mutex
+	fields []Field
+}
+
+type accessKey struct{}
+
+func (a *accessFields) add(fields ...Field) {
+	a.mu.Lock()
+	a.fields = append(a.fields, fields...)
+	a.mu.Unlock()
+}
+
+// AccessLog middleware пишет по одной структурированной записи на запрос: метод, шаблон маршрута,
+// статус, размер ответа и время обработки. Поля логгера из контекста (request_id) и добавленные
+// позже через AddToContext (user_id) попадают в запись. route возвращает шаблон маршрута
+// после обработки запроса (например, из chi); если он пуст, пишется только путь.
+func AccessLog(log *Logger, route func(r *http.Request) string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			annotations := &accessFields{}
+			ctx := context.WithValue(r.Context(), accessKey{}, annotations)
+			r = r.WithContext(ctx)
+
+			ww := &statusWriter{ResponseWriter: w}
+			next.ServeHTTP(ww, r)
+
+			status := ww.status
+			if status == 0 {
+				status = http.StatusOK
+			}
+
+			fields := []Field{
+				String("http_method", r.Method),
+				String("path", r.URL.Path),
+				Int("status", status),
+				Int64("bytes", ww.bytes),
+				Float64("latency_ms", float64(time.Since(start).Microseconds())/1000),
+				String("remote_ip", remoteIP(r)),
+			}
+			if route != nil {
+				if pattern := route(r); pattern != "" {
+					fields = append(fields, String("route", pattern))
+				}
+			}
+			annotations.mu.Lock()
+			fields = append(fields, annotations.fields...)
+			annotations.mu.Unlock()
+
+			l := log.ForContext(ctx)
+			if status >= http.StatusInternalServerError {
+				l.Error("HTTP request", fields...)
+				return
+			}
+			l.Info("HTTP request", fields...)
+		})
+	}
+}
+
+func remoteIP(r *http.Request) string {
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
+
+// statusWriter запоминает статус и размер ответа. Hijack и Flush нужны WebSocket и потоковым ответам.
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+	bytes  int64
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusWriter) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(p)
+	w.bytes += int64(n)
+	return n, err
+}
+
+func (w *statusWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (w *statusWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("response writer does not support hijacking")
+	}
+	// После перехвата соединения (WebSocket) статус 101 отправляет сам обработчик
+	w.status = http.StatusSwitchingProtocols
+	return h.Hijack()
+}
+
+func (w *statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,50 @@
+package logger
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+type contextKey struct{}
+
+// WithContext сохраняет логгер (обычно уже дополненный request_id, user_id) в контексте
+func WithContext(ctx context.Context, l *Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, l)
+}
+
+// FromContext возвращает логгер из контекста или логгер, ничего не пишущий, если его там нет
+func FromContext(ctx context.Context) *Logger {
+	if l, ok := ctx.Value(contextKey{}).(*Logger); ok {
+		return l
+	}
+	return nop
+}
+
+// ForContext возвращает логгер запроса из контекста, а если его нет - сам l.
+// Позволяет репозиториям и use case писать с полями запроса, не меняя сигнатуры:
+//
+//	r.log.ForContext(ctx).Info("Creating post")
+func (l *Logger) ForContext(ctx context.Context) *Logger {
+	if ctxLogger, ok := ctx.Value(contextKey{}).(*Logger); ok {
+		return ctxLogger
+	}
+	return l
+}
+
+// AddToContext дополняет логгер контекста полями и сохраняет результат в новом контексте.
+// Поля также попадают в запись AccessLog этого запроса.
+// Если логгера в контексте нет, контекст возвращается без изменений.
+func AddToContext(ctx context.Context, fields ...Field) context.Context {
+	if annotations, ok := ctx.Value(accessKey{}).(*accessFields); ok {
+		annotations.add(fields...)
+	}
+
+	l, ok := ctx.Value(contextKey{}).(*Logger)
+	if !ok {
+		return ctx
+	}
+	return WithContext(ctx, l.WithFields(fields...))
+}
+
+var nop = &Logger{Logger: zap.NewNop(), level: zap.NewAtomicLevel()}
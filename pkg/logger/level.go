@@ -0,0 +1,106 @@
+package logger
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// levelRequest тело запроса на смену уровня. Если задан Duration, по его истечении
+// восстанавливается уровень, действовавший до запроса.
+type levelRequest struct {
+	Level    string `json:"level"`
+	Duration string `json:"duration,omitempty"`
+}
+
+type levelResponse struct {
+	Level     string     `json:"level"`
+	RevertsAt *time.Time `json:"reverts_at,omitempty"`
+	Error     string     `json:"error,omitempty"`
+}
+
+// LevelHandler HTTP обработчик для просмотра (GET) и смены (PUT) уровня логирования на лету:
+//
+//	PUT {"level": "debug", "duration": "15m"}
+//
+// Обработчик не проверяет права: его нужно подключать за аутентификацией администратора.
+func (l *Logger) LevelHandler() http.Handler {
+	var (
+		mu        sync.Mutex
+		timer     *time.Timer
+		revertsAt *time.Time
+	)
+
+	writeJSON := func(w http.ResponseWriter, status int, resp levelResponse) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(resp)
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch r.Method {
+		case http.MethodGet:
+			writeJSON(w, http.StatusOK, levelResponse{Level: l.Level(), RevertsAt: revertsAt})
+
+		case http.MethodPut:
+			var req levelRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				writeJSON(w, http.StatusBadRequest, levelResponse{Level: l.Level(), Error: "invalid request body"})
+				return
+			}
+
+			var duration time.Duration
+			if req.Duration != "" {
+				d, err := time.ParseDuration(req.Duration)
+				if err != nil || d <= 0 {
+					writeJSON(w, http.StatusBadRequest, levelResponse{Level: l.Level(), Error: "invalid duration"})
+					return
+				}
+				duration = d
+			}
+
+			previous := l.Level()
+			if err := l.SetLevel(req.Level); err != nil {
+				writeJSON(w, http.StatusBadRequest, levelResponse{Level: previous, Error: err.Error()})
+				return
+			}
+
+			// Новая смена уровня отменяет запланированный возврат предыдущей
+			if timer != nil {
+				timer.Stop()
+				timer, revertsAt = nil, nil
+			}
+			if duration > 0 {
+				at := time.Now().Add(duration).UTC()
+				revertsAt = &at
+				var t *time.Timer
+				t = time.AfterFunc(duration, func() {
+					mu.Lock()
+					defer mu.Unlock()
+					// Таймер мог сработать одновременно с новой сменой уровня
+					if timer != t {
+						return
+					}
+					timer, revertsAt = nil, nil
+					l.SetLevel(previous)
+					l.Warn("Log level reverted", String("level", previous))
+				})
+				timer = t
+			}
+
+			l.Warn("Log level changed",
+				String("from", previous),
+				String("to", l.Level()),
+				String("duration", req.Duration))
+			writeJSON(w, http.StatusOK, levelResponse{Level: l.Level(), RevertsAt: revertsAt})
+
+		default:
+			w.Header().Set("Allow", "GET, PUT")
+			writeJSON(w, http.StatusMethodNotAllowed, levelResponse{Level: l.Level(), Error: "method not allowed"})
+		}
+	})
+}
@@ -3,6 +3,8 @@ package logger
 import (
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
@@ -11,24 +13,134 @@ import (
 // Logger - обертка вокруг zap.Logger
 type Logger struct {
 	*zap.Logger
+	level zap.AtomicLevel
 }
 
+// Форматы вывода
+const (
+	FormatJSON    = "json"    // Для сборщиков логов (production)
+	FormatConsole = "console" // Для чтения человеком (development)
+)
+
+// Имена полей записи. Одинаковы в обоих форматах и во всех сервисах,
+// чтобы запросы в системе сбора логов не зависели от источника.
+const (
+	TimeKey       = "ts"
+	LevelKey      = "level"
+	LoggerKey     = "logger"
+	CallerKey     = "caller"
+	MessageKey    = "msg"
+	StacktraceKey = "stacktrace"
+)
+
 // LogConfig конфигурация для логгера
 type LogConfig struct {
 	Level      string // debug, info, warn, error, fatal
 	OutputPath string // путь к файлу или "stdout" для вывода в консоль
-	Format     string // json или console
+	Format     string // json или console (по умолчанию)
+	// File дополнительная запись в файл с ротацией (nil - не писать)
+	File *FileConfig
+	// ErrorReporting отправка записей Error и Fatal в Sentry (nil - не отправлять)
+	ErrorReporting *ErrorReportingConfig
+	// RedactKeys дополнительные имена полей, значения которых скрываются (к DefaultRedactKeys)
+	RedactKeys []string
 }
 
-// New создает новый экземпляр логгера с конфигурацией по умолчанию
+// New создает новый экземпляр логгера с конфигурацией по умолчанию.
+// Уровень берется из переменной окружения LOG_LEVEL (по умолчанию info), формат - из LOG_FORMAT
+// (по умолчанию json при APP_ENV=production, иначе console). Если задан LOG_FILE, лог
+// дополнительно пишется в файл с ротацией (LOG_FILE_MAX_SIZE_MB, LOG_FILE_MAX_AGE_DAYS,
+// LOG_FILE_MAX_BACKUPS, LOG_FILE_COMPRESS). LOG_REDACT_KEYS - дополнительные имена скрываемых
+// полей через запятую. Если задан SENTRY_DSN, записи Error и Fatal отправляются в Sentry
+// (SENTRY_ENVIRONMENT, SENTRY_RELEASE).
 func New() (*Logger, error) {
+	level := os.Getenv("LOG_LEVEL")
+	if level == "" {
+		level = "info"
+	}
+
+	format := os.Getenv("LOG_FORMAT")
+	if format == "" {
+		format = FormatConsole
+		if os.Getenv("APP_ENV") == "production" {
+			format = FormatJSON
+		}
+	}
+
+	file, err := fileConfigFromEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	var redactKeys []string
+	for _, key := range strings.Split(os.Getenv("LOG_REDACT_KEYS"), ",") {
+		if key = strings.TrimSpace(key); key != "" {
+			redactKeys = append(redactKeys, key)
+		}
+	}
+
 	return NewWithConfig(LogConfig{
-		Level:      "info",
+		Level:      level,
 		OutputPath: "stdout",
-		Format:     "console",
+		Format:     format,
+		File:       file,
+		RedactKeys: redactKeys,
+
+		ErrorReporting: errorReportingFromEnv(),
 	})
 }
 
+// errorReportingFromEnv читает настройки Sentry; nil, если SENTRY_DSN не задан
+func errorReportingFromEnv() *ErrorReportingConfig {
+	dsn := os.Getenv("SENTRY_DSN")
+	if dsn == "" {
+		return nil
+	}
+
+	environment := os.Getenv("SENTRY_ENVIRONMENT")
+	if environment == "" {
+		environment = os.Getenv("APP_ENV")
+	}
+	return &ErrorReportingConfig{
+		DSN:         dsn,
+		Environment: environment,
+		Release:     os.Getenv("SENTRY_RELEASE"),
+	}
+}
+
+// fileConfigFromEnv читает настройки файла лога; nil, если LOG_FILE не задан
+func fileConfigFromEnv() (*FileConfig, error) {
+	path := os.Getenv("LOG_FILE")
+	if path == "" {
+		return nil, nil
+	}
+
+	cfg := &FileConfig{
+		Path:     path,
+		Compress: os.Getenv("LOG_FILE_COMPRESS") == "true",
+	}
+	limits := []struct {
+		env   string
+		value *int
+	}{
+		{"LOG_FILE_MAX_SIZE_MB", &cfg.MaxSizeMB},
+		{"LOG_FILE_MAX_AGE_DAYS", &cfg.MaxAgeDays},
+		{"LOG_FILE_MAX_BACKUPS", &cfg.MaxBackups},
+	}
+	for _, limit := range limits {
+		value := os.Getenv(limit.env)
+		if value == "" {
+			continue
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid %s %q: expected a non-negative number", limit.env, value)
+		}
+		*limit.value = n
+	}
+	return cfg, nil
+}
+
 // NewWithConfig создает новый экземпляр логгера с заданной конфигурацией
 func NewWithConfig(config LogConfig) (*Logger, error) {
 	// Настройка уровня логирования
@@ -46,37 +158,89 @@ func NewWithConfig(config LogConfig) (*Logger, error) {
 		outputPaths = []string{config.OutputPath}
 	}
 
-	// Настройка энкодера
+	// Настройка энкодера: в JSON уровень пишется строчными буквами, как ожидают сборщики логов
+	encodeLevel := zapcore.CapitalLevelEncoder
+	switch config.Format {
+	case FormatJSON:
+		encodeLevel = zapcore.LowercaseLevelEncoder
+	case FormatConsole:
+	case "":
+		config.Format = FormatConsole
+	default:
+		return nil, fmt.Errorf("invalid log format %q: expected %s or %s", config.Format, FormatJSON, FormatConsole)
+	}
+
 	encoderConfig := zapcore.EncoderConfig{
-		TimeKey:        "ts",
-		LevelKey:       "level",
-		NameKey:        "logger",
-		CallerKey:      "caller",
+		TimeKey:        TimeKey,
+		LevelKey:       LevelKey,
+		NameKey:        LoggerKey,
+		CallerKey:      CallerKey,
 		FunctionKey:    zapcore.OmitKey,
-		MessageKey:     "msg",
-		StacktraceKey:  "stacktrace",
+		MessageKey:     MessageKey,
+		StacktraceKey:  StacktraceKey,
 		LineEnding:     zapcore.DefaultLineEnding,
-		EncodeLevel:    zapcore.CapitalLevelEncoder,
+		EncodeLevel:    encodeLevel,
 		EncodeTime:     zapcore.ISO8601TimeEncoder,
 		EncodeDuration: zapcore.StringDurationEncoder,
 		EncodeCaller:   zapcore.ShortCallerEncoder,
 	}
 
-	zapConfig := zap.Config{
-		Level:            level,
-		Development:      false,
-		Encoding:         config.Format,
-		EncoderConfig:    encoderConfig,
-		OutputPaths:      outputPaths,
-		ErrorOutputPaths: []string{"stderr"},
+	var encoder zapcore.Encoder
+	if config.Format == FormatJSON {
+		encoder = zapcore.NewJSONEncoder(encoderConfig)
+	} else {
+		encoder = zapcore.NewConsoleEncoder(encoderConfig)
 	}
 
-	zapLogger, err := zapConfig.Build(zap.AddCallerSkip(1))
+	output, _, err := zap.Open(outputPaths...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open log output: %w", err)
+	}
+	// Файл с ротацией пишется в дополнение к основному выводу
+	if config.File != nil {
+		file, err := newRotatingFile(*config.File)
+		if err != nil {
+			return nil, err
+		}
+		output = zapcore.NewMultiWriteSyncer(output, file)
+	}
+	errorOutput, _, err := zap.Open("stderr")
 	if err != nil {
 		return nil, err
 	}
 
-	return &Logger{zapLogger}, nil
+	// Значения чувствительных полей скрываются для любого вывода и всех производных логгеров
+	redactKeys := append(append([]string{}, DefaultRedactKeys...), config.RedactKeys...)
+	// Оборачивается каждый вывод отдельно: redactCore пишет во вложенное ядро без проверки уровня
+	core := newRedactCore(zapcore.NewCore(encoder, output, level), redactKeys)
+	if config.ErrorReporting != nil {
+		reporter, err := newSentryCore(*config.ErrorReporting)
+		if err != nil {
+			return nil, err
+		}
+		core = zapcore.NewTee(core, newRedactCore(reporter, redactKeys))
+	}
+	zapLogger := zap.New(core,
+		zap.ErrorOutput(errorOutput),
+		zap.AddCaller(),
+		zap.AddCallerSkip(1),
+		zap.AddStacktrace(zapcore.ErrorLevel),
+	)
+
+	return &Logger{Logger: zapLogger, level: level}, nil
+}
+
+// SetLevel меняет уровень логирования во время работы (для всех производных логгеров)
+func (l *Logger) SetLevel(level string) error {
+	if err := l.level.UnmarshalText([]byte(level)); err != nil {
+		return fmt.Errorf("invalid log level: %v", err)
+	}
+	return nil
+}
+
+// Level возвращает текущий уровень логирования
+func (l *Logger) Level() string {
+	return l.level.String()
 }
 
 // Debug логирует сообщение с уровнем Debug
@@ -114,14 +278,21 @@ func (l *Logger) Fatalf(format string, err error) {
 
 // WithFields создает новый логгер с дополнительными полями
 func (l *Logger) WithFields(fields ...zap.Field) *Logger {
-	return &Logger{l.Logger.With(fields...)}
+	return &Logger{Logger: l.Logger.With(fields...), level: l.level}
 }
 
+// Field поле структурированного лога
+type Field = zap.Field
+
 // Вспомогательные функции для создания полей
 func String(key, val string) zap.Field {
 	return zap.String(key, val)
 }
 
+func Strings(key string, val []string) zap.Field {
+	return zap.Strings(key, val)
+}
+
 func Int(key string, val int) zap.Field {
 	return zap.Int(key, val)
 }
@@ -149,3 +320,8 @@ func Any(key string, val interface{}) zap.Field {
 func Duration(key string, val float64) zap.Field {
 	return zap.Float64(key, val)
 }
+
+// Stack добавляет стек вызовов в точке логирования
+func Stack(key string) zap.Field {
+	return zap.Stack(key)
+}
@@ -0,0 +1,153 @@
+package logger
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"reflect"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Redacted значение, которое пишется в лог вместо чувствительных данных
+const Redacted = "[REDACTED]"
+
+// DefaultRedactKeys подстроки имен полей, значения которых никогда не попадают в лог.
+// Сравнение без учета регистра, "-" и "_", поэтому "api_key", "API-Key" и "apiKey" совпадают.
+var DefaultRedactKeys = []string{
+	"password",
+	"passwd",
+	"secret",
+	"token",
+	"authorization",
+	"cookie",
+	"apikey",
+	"privatekey",
+}
+
+// Secret поле с чувствительным значением под любым именем. Вместо значения пишется
+// короткий отпечаток SHA-256: по нему можно сопоставить записи с одним и тем же токеном,
+// но нельзя восстановить сам токен.
+//
+//	log.Info("Token refreshed", logger.Secret("refresh", refreshToken))
+func Secret(key, value string) Field {
+	if value == "" {
+		return zap.String(key, "")
+	}
+	sum := sha256.Sum256([]byte(value))
+	return zap.String(key, secretPrefix+hex.EncodeToString(sum[:secretFingerprintBytes]))
+}
+
+const (
+	secretPrefix           = Redacted + " sha256:"
+	secretFingerprintBytes = 4
+)
+
+func isSecretFingerprint(s string) bool {
+	return len(s) == len(secretPrefix)+2*secretFingerprintBytes && strings.HasPrefix(s, secretPrefix)
+}
+
+// redactCore заменяет значения полей с чувствительными именами до записи в лог
+type redactCore struct {
+	zapcore.Core
+	keys []string
+}
+
+func newRedactCore(core zapcore.Core, keys []string) zapcore.Core {
+	normalized := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if key = normalizeKey(key); key != "" {
+			normalized = append(normalized, key)
+		}
+	}
+	return &redactCore{Core: core, keys: normalized}
+}
+
+func (c *redactCore) With(fields []zapcore.Field) zapcore.Core {
+	return &redactCore{Core: c.Core.With(c.redact(fields)), keys: c.keys}
+}
+
+func (c *redactCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+func (c *redactCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	return c.Core.Write(entry, c.redact(fields))
+}
+
+func (c *redactCore) redact(fields []zapcore.Field) []zapcore.Field {
+	var result []zapcore.Field
+	for i, field := range fields {
+		replacement, ok := c.redactField(field)
+		if !ok {
+			continue
+		}
+		// Копируем срез только при первой замене, чтобы не менять поля вызывающего
+		if result == nil {
+			result = make([]zapcore.Field, len(fields))
+			copy(result, fields)
+		}
+		result[i] = replacement
+	}
+	if result == nil {
+		return fields
+	}
+	return result
+}
+
+// redactField возвращает замену поля, если в нем есть чувствительные данные:
+// само поле с чувствительным именем или словарь (например, http.Header) с такими ключами
+func (c *redactCore) redactField(field zapcore.Field) (zapcore.Field, bool) {
+	if c.sensitive(field.Key) {
+		// Значение уже скрыто через Secret: сохраняем отпечаток
+		if field.Type == zapcore.StringType && isSecretFingerprint(field.String) {
+			return field, false
+		}
+		return zapcore.Field{Key: field.Key, Type: zapcore.StringType, String: Redacted}, true
+	}
+
+	if field.Type != zapcore.ReflectType || field.Interface == nil {
+		return field, false
+	}
+	value := reflect.ValueOf(field.Interface)
+	if value.Kind() != reflect.Map || value.Type().Key().Kind() != reflect.String {
+		return field, false
+	}
+
+	masked := make(map[string]interface{}, value.Len())
+	found := false
+	iter := value.MapRange()
+	for iter.Next() {
+		key := iter.Key().String()
+		if c.sensitive(key) {
+			masked[key] = Redacted
+			found = true
+			continue
+		}
+		masked[key] = iter.Value().Interface()
+	}
+	if !found {
+		return field, false
+	}
+	return zap.Any(field.Key, masked), true
+}
+
+func (c *redactCore) sensitive(key string) bool {
+	key = normalizeKey(key)
+	for _, k := range c.keys {
+		if strings.Contains(key, k) {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizeKey(key string) string {
+	key = strings.ToLower(key)
+	key = strings.ReplaceAll(key, "_", "")
+	return strings.ReplaceAll(key, "-", "")
+}
@@ -0,0 +1,219 @@
+package logger
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// backupTimeFormat время ротации в имени архива: app-2006-01-02T15-04-05.000.log
+const backupTimeFormat = "2006-01-02T15-04-05.000"
+
+// FileConfig запись лога в файл с ротацией по размеру и удалением старых архивов
+// (совместима по именам архивов с lumberjack)
+type FileConfig struct {
+	Path       string // Файл лога; архивы создаются рядом с ним
+	MaxSizeMB  int    // Размер, после которого файл архивируется (по умолчанию 100)
+	MaxAgeDays int    // Архивы старше удаляются (0 - без ограничения)
+	MaxBackups int    // Сколько архивов хранить (0 - без ограничения)
+	Compress   bool   // Сжимать архивы gzip
+}
+
+// rotatingFile io.Writer, который архивирует файл при превышении размера
+type rotatingFile struct {
+	cfg     FileConfig
+	maxSize int64
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+
+	// cleanup выполняется в фоне по одному запросу за раз
+	cleanup chan struct{}
+}
+
+func newRotatingFile(cfg FileConfig) (*rotatingFile, error) {
+	if cfg.Path == "" {
+		return nil, errors.New("log file path is required")
+	}
+	if cfg.MaxSizeMB <= 0 {
+		cfg.MaxSizeMB = 100
+	}
+	if err := os.MkdirAll(filepath.Dir(cfg.Path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create log directory: %w", err)
+	}
+
+	f := &rotatingFile{
+		cfg:     cfg,
+		maxSize: int64(cfg.MaxSizeMB) << 20,
+		cleanup: make(chan struct{}, 1),
+	}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+
+	go f.cleanupLoop()
+	f.requestCleanup()
+	return f, nil
+}
+
+func (f *rotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.size+int64(len(p)) > f.maxSize && f.size > 0 {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+func (f *rotatingFile) Sync() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.file.Sync()
+}
+
+func (f *rotatingFile) open() error {
+	file, err := os.OpenFile(f.cfg.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to stat log file: %w", err)
+	}
+
+	f.file = file
+	f.size = info.Size()
+	return nil
+}
+
+// rotate переименовывает текущий файл в архив и открывает новый
+func (f *rotatingFile) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return fmt.Errorf("failed to close log file: %w", err)
+	}
+
+	prefix, ext := f.backupNameParts()
+	backup := prefix + time.Now().UTC().Format(backupTimeFormat) + ext
+	if err := os.Rename(f.cfg.Path, backup); err != nil {
+		// Продолжаем писать в тот же файл, чтобы не терять записи
+		if openErr := f.open(); openErr != nil {
+			return openErr
+		}
+		return fmt.Errorf("failed to rotate log file: %w", err)
+	}
+
+	if err := f.open(); err != nil {
+		return err
+	}
+	f.requestCleanup()
+	return nil
+}
+
+func (f *rotatingFile) requestCleanup() {
+	select {
+	case f.cleanup <- struct{}{}:
+	default:
+	}
+}
+
+func (f *rotatingFile) cleanupLoop() {
+	for range f.cleanup {
+		// Ошибки очистки не должны мешать записи лога, а писать их некуда, кроме stderr
+		if err := f.cleanupBackups(); err != nil {
+			fmt.Fprintf(os.Stderr, "log rotation cleanup failed: %v\n", err)
+		}
+	}
+}
+
+// cleanupBackups сжимает новые архивы и удаляет лишние и устаревшие
+func (f *rotatingFile) cleanupBackups() error {
+	prefix, ext := f.backupNameParts()
+	matches, err := filepath.Glob(prefix + "*")
+	if err != nil {
+		return err
+	}
+
+	type backup struct {
+		path string
+		time time.Time
+	}
+	var backups []backup
+	for _, path := range matches {
+		stamp := strings.TrimPrefix(path, prefix)
+		stamp = strings.TrimSuffix(strings.TrimSuffix(stamp, ".gz"), ext)
+		t, err := time.Parse(backupTimeFormat, stamp)
+		if err != nil {
+			continue
+		}
+		backups = append(backups, backup{path: path, time: t})
+	}
+	// Новые архивы первыми
+	sort.Slice(backups, func(i, j int) bool { return backups[i].time.After(backups[j].time) })
+
+	var errs []error
+	for i, b := range backups {
+		expired := f.cfg.MaxAgeDays > 0 && time.Since(b.time) > time.Duration(f.cfg.MaxAgeDays)*24*time.Hour
+		if expired || (f.cfg.MaxBackups > 0 && i >= f.cfg.MaxBackups) {
+			if err := os.Remove(b.path); err != nil {
+				errs = append(errs, err)
+			}
+			continue
+		}
+		if f.cfg.Compress && !strings.HasSuffix(b.path, ".gz") {
+			if err := compressFile(b.path); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// backupNameParts возвращает части имени архива: "dir/app-" и ".log"
+func (f *rotatingFile) backupNameParts() (string, string) {
+	ext := filepath.Ext(f.cfg.Path)
+	return strings.TrimSuffix(f.cfg.Path, ext) + "-", ext
+}
+
+func compressFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+
+	gz := gzip.NewWriter(dst)
+	if _, err := io.Copy(gz, src); err != nil {
+		dst.Close()
+		os.Remove(path + ".gz")
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		dst.Close()
+		os.Remove(path + ".gz")
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
@@ -0,0 +1,344 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// sentryQueueSize события сверх очереди отбрасываются: логирование не должно ждать сеть
+const sentryQueueSize = 100
+
+// sentryTimeout ограничивает отправку одного события
+const sentryTimeout = 5 * time.Second
+
+// sentryTagKeys поля записи, которые становятся тегами события (по ним ищут в Sentry)
+var sentryTagKeys = map[string]bool{
+	"request_id":      true,
+	"user_id":         true,
+	"acting_admin_id": true,
+	"tenant_id":       true,
+	"method":          true,
+}
+
+// ErrorReportingConfig отправка записей Error и Fatal в Sentry-совместимый сервис
+type ErrorReportingConfig struct {
+	DSN         string // https://<key>@<host>/<project>
+	Environment string
+	Release     string
+	ServerName  string // По умолчанию имя хоста
+}
+
+// sentryEndpoint адрес и ключ, разобранные из DSN
+type sentryEndpoint struct {
+	storeURL string
+	auth     string
+}
+
+func parseSentryDSN(dsn string) (*sentryEndpoint, error) {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sentry DSN: %w", err)
+	}
+	if u.User == nil || u.User.Username() == "" {
+		return nil, fmt.Errorf("invalid sentry DSN: public key is missing")
+	}
+	project := strings.TrimPrefix(u.Path, "/")
+	if project == "" {
+		return nil, fmt.Errorf("invalid sentry DSN: project id is missing")
+	}
+
+	// Проект - последний сегмент пути, все до него - префикс сервера
+	prefix := ""
+	if i := strings.LastIndex(project, "/"); i >= 0 {
+		prefix, project = "/"+project[:i], project[i+1:]
+	}
+
+	auth := "Sentry sentry_version=7, sentry_client=dolgova-logger/1.0, sentry_key=" + u.User.Username()
+	if secret, ok := u.User.Password(); ok {
+		auth += ", sentry_secret=" + secret
+	}
+	return &sentryEndpoint{
+		storeURL: fmt.Sprintf("%s://%s%s/api/%s/store/", u.Scheme, u.Host, prefix, project),
+		auth:     auth,
+	}, nil
+}
+
+// sentryCore отправляет записи уровня Error и выше в Sentry. Error отправляются в фоне,
+// Fatal - синхронно, т.к. после записи процесс завершается.
+type sentryCore struct {
+	client *sentryClient
+	fields []zapcore.Field
+}
+
+func newSentryCore(cfg ErrorReportingConfig) (*sentryCore, error) {
+	endpoint, err := parseSentryDSN(cfg.DSN)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.ServerName == "" {
+		cfg.ServerName, _ = os.Hostname()
+	}
+
+	client := &sentryClient{
+		cfg:      cfg,
+		endpoint: endpoint,
+		http:     &http.Client{Timeout: sentryTimeout},
+		queue:    make(chan []byte, sentryQueueSize),
+	}
+	go client.run()
+	return &sentryCore{client: client}, nil
+}
+
+func (c *sentryCore) Enabled(level zapcore.Level) bool {
+	return level >= zapcore.ErrorLevel
+}
+
+func (c *sentryCore) With(fields []zapcore.Field) zapcore.Core {
+	combined := make([]zapcore.Field, 0, len(c.fields)+len(fields))
+	combined = append(append(combined, c.fields...), fields...)
+	return &sentryCore{client: c.client, fields: combined}
+}
+
+func (c *sentryCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+func (c *sentryCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	all := make([]zapcore.Field, 0, len(c.fields)+len(fields))
+	all = append(append(all, c.fields...), fields...)
+
+	event, err := c.client.event(entry, all)
+	if err != nil {
+		return err
+	}
+	if entry.Level >= zapcore.DPanicLevel {
+		return c.client.send(event)
+	}
+	c.client.enqueue(event)
+	return nil
+}
+
+// Sync ждет отправки событий, уже стоящих в очереди, но не дольше sentryTimeout
+func (c *sentryCore) Sync() error {
+	done := make(chan struct{})
+	go func() {
+		c.client.pending.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-time.After(sentryTimeout):
+		return fmt.Errorf("timed out flushing sentry events")
+	}
+}
+
+type sentryClient struct {
+	cfg      ErrorReportingConfig
+	endpoint *sentryEndpoint
+	http     *http.Client
+	queue    chan []byte
+	pending  sync.WaitGroup
+}
+
+func (s *sentryClient) enqueue(event []byte) {
+	s.pending.Add(1)
+	select {
+	case s.queue <- event:
+	default:
+		s.pending.Done()
+		fmt.Fprintln(os.Stderr, "sentry queue is full, event dropped")
+	}
+}
+
+func (s *sentryClient) run() {
+	for event := range s.queue {
+		if err := s.send(event); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to send event to sentry: %v\n", err)
+		}
+		s.pending.Done()
+	}
+}
+
+func (s *sentryClient) send(event []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), sentryTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint.storeURL, bytes.NewReader(event))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Sentry-Auth", s.endpoint.auth)
+
+	resp, err := s.http.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("sentry responded with %s", resp.Status)
+	}
+	return nil
+}
+
+type sentryFrame struct {
+	Function string `json:"function"`
+	AbsPath  string `json:"abs_path"`
+	Lineno   int    `json:"lineno"`
+	InApp    bool   `json:"in_app"`
+}
+
+type sentryException struct {
+	Type       string `json:"type"`
+	Value      string `json:"value"`
+	Stacktrace *struct {
+		Frames []sentryFrame `json:"frames"`
+	} `json:"stacktrace,omitempty"`
+}
+
+type sentryEvent struct {
+	EventID     string                 `json:"event_id"`
+	Timestamp   string                 `json:"timestamp"`
+	Level       string                 `json:"level"`
+	Logger      string                 `json:"logger,omitempty"`
+	Platform    string                 `json:"platform"`
+	Message     string                 `json:"message"`
+	Environment string                 `json:"environment,omitempty"`
+	Release     string                 `json:"release,omitempty"`
+	ServerName  string                 `json:"server_name,omitempty"`
+	Culprit     string                 `json:"culprit,omitempty"`
+	Tags        map[string]string      `json:"tags,omitempty"`
+	Extra       map[string]interface{} `json:"extra,omitempty"`
+	User        map[string]string      `json:"user,omitempty"`
+	Exception   []sentryException      `json:"exception,omitempty"`
+}
+
+// event собирает событие Sentry: теги из полей запроса, остальные поля - в extra,
+// стек вызовов из записи zap - в exception
+func (s *sentryClient) event(entry zapcore.Entry, fields []zapcore.Field) ([]byte, error) {
+	enc := zapcore.NewMapObjectEncoder()
+	for _, field := range fields {
+		field.AddTo(enc)
+	}
+
+	id := make([]byte, 16)
+	rand.Read(id)
+
+	event := sentryEvent{
+		EventID:     hex.EncodeToString(id),
+		Timestamp:   entry.Time.UTC().Format(time.RFC3339Nano),
+		Level:       sentryLevel(entry.Level),
+		Logger:      entry.LoggerName,
+		Platform:    "go",
+		Message:     entry.Message,
+		Environment: s.cfg.Environment,
+		Release:     s.cfg.Release,
+		ServerName:  s.cfg.ServerName,
+		Tags:        map[string]string{},
+		Extra:       map[string]interface{}{},
+	}
+	if entry.Caller.Defined {
+		event.Culprit = entry.Caller.TrimmedPath()
+	}
+
+	errorText := ""
+	for key, value := range enc.Fields {
+		switch {
+		case key == "error":
+			errorText = fmt.Sprint(value)
+			event.Extra[key] = value
+		case sentryTagKeys[key]:
+			event.Tags[key] = fmt.Sprint(value)
+		default:
+			event.Extra[key] = value
+		}
+	}
+	if userID := event.Tags["user_id"]; userID != "" {
+		event.User = map[string]string{"id": userID}
+	}
+
+	exception := sentryException{Type: entry.Message, Value: errorText}
+	if exception.Value == "" {
+		exception.Value = entry.Message
+	}
+	if frames := parseStack(entry.Stack); len(frames) > 0 {
+		exception.Stacktrace = &struct {
+			Frames []sentryFrame `json:"frames"`
+		}{Frames: frames}
+	}
+	event.Exception = []sentryException{exception}
+
+	return json.Marshal(event)
+}
+
+// parseStack разбирает стек zap ("функция\n\tфайл:строка\n...") в кадры Sentry,
+// которые ожидаются в порядке от внешнего вызова к месту ошибки
+func parseStack(stack string) []sentryFrame {
+	lines := strings.Split(strings.TrimSpace(stack), "\n")
+	var frames []sentryFrame
+	for i := 0; i+1 < len(lines); i += 2 {
+		location := strings.TrimSpace(lines[i+1])
+		file, line := location, 0
+		if j := strings.LastIndex(location, ":"); j >= 0 {
+			file = location[:j]
+			fmt.Sscanf(location[j+1:], "%d", &line)
+		}
+		function := strings.TrimSpace(lines[i])
+		frames = append(frames, sentryFrame{
+			Function: function,
+			AbsPath:  file,
+			Lineno:   line,
+			InApp:    inApp(function),
+		})
+	}
+
+	for i, j := 0, len(frames)-1; i < j; i, j = i+1, j-1 {
+		frames[i], frames[j] = frames[j], frames[i]
+	}
+	return frames
+}
+
+// inApp отделяет код сервиса от стандартной библиотеки, зависимостей и самого логгера
+func inApp(function string) bool {
+	switch {
+	case strings.HasPrefix(function, "runtime."),
+		strings.Contains(function, "go.uber.org/"),
+		strings.Contains(function, "google.golang.org/"),
+		strings.Contains(function, "/pkg/logger."),
+		strings.HasPrefix(function, "net/http."):
+		return false
+	}
+	return true
+}
+
+func sentryLevel(level zapcore.Level) string {
+	switch level {
+	case zapcore.DebugLevel:
+		return "debug"
+	case zapcore.InfoLevel:
+		return "info"
+	case zapcore.WarnLevel:
+		return "warning"
+	case zapcore.ErrorLevel:
+		return "error"
+	default:
+		return "fatal"
+	}
+}
@@ -0,0 +1,167 @@
+package mailer
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/kprf42/dolgova/pkg/logger"
+)
+
+var (
+	// ErrQueueFull очередь писем переполнена; письмо не принято
+	ErrQueueFull = errors.New("mailer: queue is full")
+	// ErrClosed AsyncSender остановлен
+	ErrClosed = errors.New("mailer: sender is closed")
+)
+
+// AsyncConfig настройки фоновой отправки. Нулевые значения заменяются значениями по умолчанию.
+type AsyncConfig struct {
+	Workers     int           // Параллельных отправок; по умолчанию 2
+	QueueSize   int           // Писем в очереди; по умолчанию 100
+	MaxAttempts int           // Попыток на письмо; по умолчанию 5
+	Backoff     time.Duration // Пауза перед второй попыткой, далее удваивается; по умолчанию 2s
+	Timeout     time.Duration // Таймаут одной попытки; по умолчанию 30s
+}
+
+// AsyncSender отправляет письма в фоне: Send ставит письмо в очередь и сразу возвращается,
+// а воркеры доставляют его через sender, повторяя временные ошибки с растущей паузой.
+// Очередь хранится в памяти, поэтому письма, не отправленные к остановке процесса, теряются.
+type AsyncSender struct {
+	sender Sender
+	cfg    AsyncConfig
+	log    *logger.Logger
+
+	mu     sync.RWMutex
+	closed bool
+	queue  chan *Message
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewAsyncSender(sender Sender, cfg AsyncConfig, log *logger.Logger) *AsyncSender {
+	if cfg.Workers <= 0 {
+		cfg.Workers = 2
+	}
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = 100
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 5
+	}
+	if cfg.Backoff <= 0 {
+		cfg.Backoff = 2 * time.Second
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 30 * time.Second
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &AsyncSender{
+		sender: sender,
+		cfg:    cfg,
+		log:    log,
+		queue:  make(chan *Message, cfg.QueueSize),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+	for range cfg.Workers {
+		s.wg.Add(1)
+		go s.worker()
+	}
+	return s
+}
+
+// Send ставит письмо в очередь. Ошибка означает, что письмо не принято;
+// об ошибках самой доставки только пишется в лог.
+func (s *AsyncSender) Send(ctx context.Context, msg *Message) error {
+	if err := validate(msg); err != nil {
+		return err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return ErrClosed
+	}
+
+	select {
+	case s.queue <- msg:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+// Close перестает принимать письма и ждет отправки очереди до дедлайна ctx,
+// после чего прерывает оставшиеся попытки
+func (s *AsyncSender) Close(ctx context.Context) error {
+	s.mu.Lock()
+	if !s.closed {
+		s.closed = true
+		close(s.queue)
+	}
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		s.cancel()
+		return nil
+	case <-ctx.Done():
+		s.cancel()
+		<-done
+		return ctx.Err()
+	}
+}
+
+func (s *AsyncSender) worker() {
+	defer s.wg.Done()
+	for msg := range s.queue {
+		s.deliver(msg)
+	}
+}
+
+func (s *AsyncSender) deliver(msg *Message) {
+	backoff := s.cfg.Backoff
+	for attempt := 1; ; attempt++ {
+		ctx, cancel := context.WithTimeout(s.ctx, s.cfg.Timeout)
+		err := s.sender.Send(ctx, msg)
+		cancel()
+		if err == nil {
+			return
+		}
+
+		if IsPermanent(err) || attempt >= s.cfg.MaxAttempts {
+			s.log.Error("Failed to send email",
+				logger.Strings("to", msg.To),
+				logger.String("subject", msg.Subject),
+				logger.Int("attempts", attempt),
+				logger.Error(err))
+			return
+		}
+
+		s.log.Warn("Failed to send email, retrying",
+			logger.Strings("to", msg.To),
+			logger.Int("attempt", attempt),
+			logger.Error(err))
+
+		select {
+		case <-time.After(backoff):
+			backoff *= 2
+		case <-s.ctx.Done():
+			s.log.Error("Email dropped on shutdown",
+				logger.Strings("to", msg.To),
+				logger.String("subject", msg.Subject))
+			return
+		}
+	}
+}
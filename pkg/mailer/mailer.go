@@ -0,0 +1,160 @@
+// Package mailer отправляет письма через SMTP или AWS SES. Письма собираются из шаблонов,
+// а AsyncSender отправляет их в фоне с повторными попытками, не задерживая ответ на запрос.
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"strings"
+	texttemplate "text/template"
+)
+
+// Message письмо. Должен быть задан хотя бы один из Text и HTML.
+type Message struct {
+	To      []string
+	Subject string
+	Text    string // Текстовая версия
+	HTML    string // HTML версия; почтовые клиенты показывают ее вместо текстовой
+}
+
+// Sender доставляет письма
+type Sender interface {
+	Send(ctx context.Context, msg *Message) error
+}
+
+// permanentError ошибка, при которой повторная отправка бессмысленна (неверный адрес, отказ в доступе)
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent помечает ошибку отправки как постоянную: AsyncSender не будет повторять такое письмо
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// IsPermanent сообщает, помечена ли ошибка через Permanent
+func IsPermanent(err error) bool {
+	var perm *permanentError
+	return errors.As(err, &perm)
+}
+
+func validate(msg *Message) error {
+	if len(msg.To) == 0 {
+		return Permanent(errors.New("mailer: no recipients"))
+	}
+	if msg.Text == "" && msg.HTML == "" {
+		return Permanent(errors.New("mailer: empty message body"))
+	}
+	return nil
+}
+
+// Templates шаблоны писем. Письмо name состоит из файлов name.subject.tmpl (тема, одна строка),
+// name.txt.tmpl и/или name.html.tmpl; HTML шаблон экранирует подставляемые значения.
+type Templates struct {
+	subjects map[string]*texttemplate.Template
+	texts    map[string]*texttemplate.Template
+	htmls    map[string]*htmltemplate.Template
+}
+
+// ParseTemplates загружает шаблоны писем из корня fsys
+func ParseTemplates(fsys fs.FS) (*Templates, error) {
+	t := &Templates{
+		subjects: map[string]*texttemplate.Template{},
+		texts:    map[string]*texttemplate.Template{},
+		htmls:    map[string]*htmltemplate.Template{},
+	}
+
+	files, err := fs.Glob(fsys, "*.tmpl")
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		data, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, err
+		}
+
+		base := strings.TrimSuffix(file, ".tmpl")
+		switch {
+		case strings.HasSuffix(base, ".subject"):
+			err = parseText(t.subjects, strings.TrimSuffix(base, ".subject"), data)
+		case strings.HasSuffix(base, ".txt"):
+			err = parseText(t.texts, strings.TrimSuffix(base, ".txt"), data)
+		case strings.HasSuffix(base, ".html"):
+			name := strings.TrimSuffix(base, ".html")
+			t.htmls[name], err = htmltemplate.New(name).Parse(string(data))
+		default:
+			err = errors.New("expected .subject.tmpl, .txt.tmpl or .html.tmpl suffix")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("mail template %s: %w", file, err)
+		}
+	}
+
+	for name := range t.subjects {
+		if t.texts[name] == nil && t.htmls[name] == nil {
+			return nil, fmt.Errorf("mail template %s: no body template", name)
+		}
+	}
+	for name := range t.texts {
+		if t.subjects[name] == nil {
+			return nil, fmt.Errorf("mail template %s: no subject template", name)
+		}
+	}
+	for name := range t.htmls {
+		if t.subjects[name] == nil {
+			return nil, fmt.Errorf("mail template %s: no subject template", name)
+		}
+	}
+	return t, nil
+}
+
+func parseText(set map[string]*texttemplate.Template, name string, data []byte) error {
+	tmpl, err := texttemplate.New(name).Option("missingkey=error").Parse(string(data))
+	if err != nil {
+		return err
+	}
+	set[name] = tmpl
+	return nil
+}
+
+// Render собирает письмо name для получателя to, подставляя data в шаблоны
+func (t *Templates) Render(name string, to string, data any) (*Message, error) {
+	subject, ok := t.subjects[name]
+	if !ok {
+		return nil, fmt.Errorf("mail template %s not found", name)
+	}
+
+	msg := &Message{To: []string{to}}
+	var buf bytes.Buffer
+	if err := subject.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("mail template %s: %w", name, err)
+	}
+	msg.Subject = strings.TrimSpace(buf.String())
+
+	if text, ok := t.texts[name]; ok {
+		buf.Reset()
+		if err := text.Execute(&buf, data); err != nil {
+			return nil, fmt.Errorf("mail template %s: %w", name, err)
+		}
+		msg.Text = buf.String()
+	}
+	if html, ok := t.htmls[name]; ok {
+		buf.Reset()
+		if err := html.Execute(&buf, data); err != nil {
+			return nil, fmt.Errorf("mail template %s: %w", name, err)
+		}
+		msg.HTML = buf.String()
+	}
+	return msg, nil
+}
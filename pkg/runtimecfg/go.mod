@@ -0,0 +1,12 @@
+module github.com/kprf42/dolgova/pkg/runtimecfg
+
+go 1.24.2
+
+require github.com/kprf42/dolgova/pkg/logger v0.0.0-00010101000000-000000000000
+
+require (
+	go.uber.org/multierr v1.10.0 // indirect
+	go.uber.org/zap v1.27.0 // indirect
+)
+
+replace github.com/kprf42/dolgova/pkg/logger => ../logger
@@ -0,0 +1,134 @@
+// Package runtimecfg перечитывает JSON файл настроек, которые можно менять без перезапуска сервиса.
+// Состав настроек задает сервис: пакет только следит за файлом и уведомляет подписчиков.
+package runtimecfg
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/kprf42/dolgova/pkg/logger"
+)
+
+// Watcher следит за файлом настроек T и применяет изменения на лету
+type Watcher[T any] struct {
+	path     string
+	defaults T
+	validate func(T) error
+	log      *logger.Logger
+
+	mu          sync.RWMutex
+	current     T
+	modTime     time.Time
+	subscribers []func(T)
+}
+
+// New загружает начальные настройки из файла (если он есть). Отсутствующие в файле поля
+// берутся из defaults. validate может быть nil, тогда прочитанные настройки не проверяются.
+func New[T any](path string, defaults T, validate func(T) error, log *logger.Logger) (*Watcher[T], error) {
+	w := &Watcher[T]{
+		path:     path,
+		defaults: defaults,
+		validate: validate,
+		current:  defaults,
+		log:      log,
+	}
+
+	if _, err := w.reload(); err != nil {
+		return nil, err
+	}
+
+	return w, nil
+}
+
+// Current возвращает текущие настройки
+func (w *Watcher[T]) Current() T {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.current
+}
+
+// OnChange регистрирует обработчик, вызываемый сразу и после каждого изменения настроек
+func (w *Watcher[T]) OnChange(fn func(T)) {
+	w.mu.Lock()
+	w.subscribers = append(w.subscribers, fn)
+	current := w.current
+	w.mu.Unlock()
+
+	fn(current)
+}
+
+// Watch периодически проверяет файл и перечитывает его при изменении
+func (w *Watcher[T]) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			changed, err := w.reload()
+			if err != nil {
+				// Невалидный файл не применяем, продолжаем работать со старыми настройками
+				w.log.Error("Failed to reload runtime config",
+					logger.String("path", w.path),
+					logger.Error(err))
+				continue
+			}
+			if changed {
+				w.log.Info("Runtime config reloaded",
+					logger.String("path", w.path))
+			}
+		}
+	}
+}
+
+// reload перечитывает файл, если он изменился, и уведомляет подписчиков
+func (w *Watcher[T]) reload() (bool, error) {
+	info, err := os.Stat(w.path)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	w.mu.RLock()
+	unchanged := info.ModTime().Equal(w.modTime)
+	w.mu.RUnlock()
+	if unchanged {
+		return false, nil
+	}
+
+	data, err := os.ReadFile(w.path)
+	if err != nil {
+		return false, err
+	}
+
+	// Отсутствующие в файле поля берутся из значений по умолчанию
+	next := w.defaults
+	if err := json.Unmarshal(data, &next); err != nil {
+		return false, fmt.Errorf("failed to parse runtime config: %w", err)
+	}
+	if w.validate != nil {
+		if err := w.validate(next); err != nil {
+			return false, fmt.Errorf("invalid runtime config: %w", err)
+		}
+	}
+
+	w.mu.Lock()
+	w.current = next
+	w.modTime = info.ModTime()
+	subscribers := append([]func(T){}, w.subscribers...)
+	w.mu.Unlock()
+
+	for _, fn := range subscribers {
+		fn(next)
+	}
+
+	return true, nil
+}
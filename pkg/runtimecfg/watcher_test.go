@@ -0,0 +1,120 @@
+package runtimecfg
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/kprf42/dolgova/pkg/logger"
+)
+
+type settings struct {
+	Level   string   `json:"level"`
+	Origins []string `json:"origins"`
+}
+
+func TestWatcherReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "runtime.json")
+	defaults := settings{Level: "info", Origins: []string{"http://localhost:3000"}}
+	validate := func(s settings) error {
+		if s.Level == "" {
+			return errors.New("level is required")
+		}
+		return nil
+	}
+
+	// Без файла действуют значения по умолчанию
+	w, err := New(path, defaults, validate, newTestLogger(t))
+	if err != nil {
+		t.Fatalf("new watcher: %v", err)
+	}
+	var notified []settings
+	w.OnChange(func(s settings) { notified = append(notified, s) })
+
+	tests := []struct {
+		name    string
+		content string
+		wantErr bool
+		want    settings
+	}{
+		{
+			name:    "missing fields keep defaults",
+			content: `{"level": "debug"}`,
+			want:    settings{Level: "debug", Origins: []string{"http://localhost:3000"}},
+		},
+		{
+			name:    "invalid json keeps previous",
+			content: `{"level": `,
+			wantErr: true,
+			want:    settings{Level: "debug", Origins: []string{"http://localhost:3000"}},
+		},
+		{
+			name:    "validation error keeps previous",
+			content: `{"level": ""}`,
+			wantErr: true,
+			want:    settings{Level: "debug", Origins: []string{"http://localhost:3000"}},
+		},
+		{
+			name:    "all fields",
+			content: `{"level": "warn", "origins": ["https://forum.example.com"]}`,
+			want:    settings{Level: "warn", Origins: []string{"https://forum.example.com"}},
+		},
+	}
+
+	modTime := time.Now()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Время изменения явно сдвигается: запись в ту же секунду могла бы его не поменять
+			modTime = modTime.Add(time.Second)
+			if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+				t.Fatalf("write config: %v", err)
+			}
+			if err := os.Chtimes(path, modTime, modTime); err != nil {
+				t.Fatalf("touch config: %v", err)
+			}
+
+			changed, err := w.reload()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("reload error %v, want error %v", err, tt.wantErr)
+			}
+			if changed == tt.wantErr {
+				t.Fatalf("reload changed %v", changed)
+			}
+			if got := w.Current(); got.Level != tt.want.Level || !slices.Equal(got.Origins, tt.want.Origins) {
+				t.Fatalf("current %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	// Подписчик получает настройки при подписке и после каждого примененного изменения
+	if len(notified) != 3 || notified[0].Level != "info" || notified[2].Level != "warn" {
+		t.Fatalf("notified %+v", notified)
+	}
+
+	// Неизмененный файл не перечитывается
+	if changed, err := w.reload(); err != nil || changed {
+		t.Fatalf("reload of unchanged file: changed %v, error %v", changed, err)
+	}
+}
+
+func TestNewInvalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "runtime.json")
+	if err := os.WriteFile(path, []byte(`not json`), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	if _, err := New(path, settings{}, nil, newTestLogger(t)); err == nil {
+		t.Fatal("invalid config accepted")
+	}
+}
+
+func newTestLogger(t *testing.T) *logger.Logger {
+	t.Helper()
+	log, err := logger.NewWithConfig(logger.LogConfig{Level: "error", OutputPath: "stdout"})
+	if err != nil {
+		t.Fatalf("create logger: %v", err)
+	}
+	return log
+}
@@ -0,0 +1,225 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseField(t *testing.T) {
+	tests := []struct {
+		name     string
+		field    string
+		min, max int
+		want     []int
+		wantErr  bool
+	}{
+		{name: "any", field: "*", min: 1, max: 12, want: []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}},
+		{name: "value", field: "5", min: 0, max: 59, want: []int{5}},
+		{name: "list", field: "1,15", min: 1, max: 31, want: []int{1, 15}},
+		{name: "range", field: "1-5", min: 0, max: 7, want: []int{1, 2, 3, 4, 5}},
+		{name: "step over any", field: "*/10", min: 0, max: 59, want: []int{0, 10, 20, 30, 40, 50}},
+		{name: "step over range", field: "0-30/5", min: 0, max: 59, want: []int{0, 5, 10, 15, 20, 25, 30}},
+		// Значение с шагом означает диапазон от значения до конца поля
+		{name: "step from value", field: "5/15", min: 0, max: 59, want: []int{5, 20, 35, 50}},
+		{name: "list of steps", field: "0-10/5,50-59/9", min: 0, max: 59, want: []int{0, 5, 10, 50, 59}},
+		{name: "zero step", field: "*/0", min: 0, max: 59, wantErr: true},
+		{name: "bad step", field: "*/x", min: 0, max: 59, wantErr: true},
+		{name: "above max", field: "60", min: 0, max: 59, wantErr: true},
+		{name: "below min", field: "0", min: 1, max: 31, wantErr: true},
+		{name: "reversed range", field: "5-1", min: 0, max: 59, wantErr: true},
+		{name: "bad range", field: "1-x", min: 0, max: 59, wantErr: true},
+		{name: "not a number", field: "mon", min: 0, max: 7, wantErr: true},
+		{name: "empty list item", field: "1,", min: 0, max: 59, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseField(tt.field, tt.min, tt.max)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseField(%q) error %v, want error %v", tt.field, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			var want uint64
+			for _, v := range tt.want {
+				want |= 1 << uint(v)
+			}
+			if got != want {
+				t.Fatalf("parseField(%q) = %b, want %b", tt.field, got, want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"@yearly",
+		"@every",
+		"@every soon",
+		"@every 0s",
+		"@every -5m",
+	}
+
+	for _, spec := range tests {
+		t.Run(spec, func(t *testing.T) {
+			if _, err := Parse(spec); err == nil {
+				t.Fatalf("Parse(%q) accepted invalid spec", spec)
+			}
+		})
+	}
+}
+
+func TestScheduleNext(t *testing.T) {
+	// Пятница, 16 октября 2026
+	from := time.Date(2026, time.October, 16, 10, 7, 30, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		spec string
+		from time.Time
+		want time.Time
+	}{
+		{
+			name: "every interval",
+			spec: "@every 90s",
+			from: from,
+			want: from.Add(90 * time.Second),
+		},
+		{
+			name: "every interval trims spaces",
+			spec: "  @every  1h ",
+			from: from,
+			want: from.Add(time.Hour),
+		},
+		{
+			name: "minute step",
+			spec: "*/10 * * * *",
+			from: from,
+			want: time.Date(2026, time.October, 16, 10, 10, 0, 0, time.UTC),
+		},
+		{
+			name: "strictly after matching minute",
+			spec: "*/10 * * * *",
+			from: time.Date(2026, time.October, 16, 10, 10, 0, 0, time.UTC),
+			want: time.Date(2026, time.October, 16, 10, 20, 0, 0, time.UTC),
+		},
+		{
+			name: "range step rolls over to next hour",
+			spec: "0-30/5 * * * *",
+			from: time.Date(2026, time.October, 16, 10, 31, 0, 0, time.UTC),
+			want: time.Date(2026, time.October, 16, 11, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "step from value",
+			spec: "5/15 * * * *",
+			from: from,
+			want: time.Date(2026, time.October, 16, 10, 20, 0, 0, time.UTC),
+		},
+		{
+			name: "hourly",
+			spec: "@hourly",
+			from: from,
+			want: time.Date(2026, time.October, 16, 11, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "daily",
+			spec: "@daily",
+			from: from,
+			want: time.Date(2026, time.October, 17, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "weekly on sunday",
+			spec: "@weekly",
+			from: from,
+			want: time.Date(2026, time.October, 18, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "monthly",
+			spec: "@monthly",
+			from: from,
+			want: time.Date(2026, time.November, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "sunday as 0",
+			spec: "30 6 * * 0",
+			from: from,
+			want: time.Date(2026, time.October, 18, 6, 30, 0, 0, time.UTC),
+		},
+		{
+			name: "sunday as 7",
+			spec: "30 6 * * 7",
+			from: from,
+			want: time.Date(2026, time.October, 18, 6, 30, 0, 0, time.UTC),
+		},
+		{
+			name: "weekday range ending with 7",
+			spec: "0 9 * * 6-7",
+			from: from,
+			want: time.Date(2026, time.October, 17, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "day of month only",
+			spec: "0 0 13 * *",
+			from: from,
+			want: time.Date(2026, time.November, 13, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "day of week only",
+			spec: "0 0 * * 1",
+			from: from,
+			want: time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC),
+		},
+		// Заданы и день месяца, и день недели: достаточно совпадения любого из них
+		{
+			name: "day of month or day of week, weekday first",
+			spec: "0 0 1 * 1",
+			from: from,
+			want: time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "day of month or day of week, day of month first",
+			spec: "0 0 1 * 1",
+			from: time.Date(2026, time.October, 31, 12, 0, 0, 0, time.UTC),
+			want: time.Date(2026, time.November, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "month rolls over year",
+			spec: "0 0 1 1 *",
+			from: from,
+			want: time.Date(2027, time.January, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "leap day",
+			spec: "0 0 29 2 *",
+			from: from,
+			want: time.Date(2028, time.February, 29, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "impossible date",
+			spec: "0 0 30 2 *",
+			from: from,
+			want: time.Time{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := Parse(tt.spec)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.spec, err)
+			}
+			if got := s.Next(tt.from); !got.Equal(tt.want) {
+				t.Fatalf("Next(%s) for %q = %s, want %s", tt.from, tt.spec, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,217 @@
+// Package scheduler запускает фоновые задачи по расписанию в стиле cron
+// со случайным смещением (jitter), метриками Prometheus и штатной остановкой
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/kprf42/dolgova/pkg/logger"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// JobFunc тело задачи. ctx отменяется при остановке планировщика.
+type JobFunc func(ctx context.Context) error
+
+type job struct {
+	name     string
+	schedule Schedule
+	jitter   time.Duration
+	fn       JobFunc
+}
+
+// Scheduler выполняет зарегистрированные задачи. Запуски одной задачи не перекрываются.
+type Scheduler struct {
+	log     *logger.Logger
+	metrics *metrics
+
+	mu      sync.Mutex
+	jobs    []*job
+	started bool
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// New создает планировщик. reg может быть nil, тогда метрики не регистрируются.
+func New(reg prometheus.Registerer, log *logger.Logger) *Scheduler {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Scheduler{
+		log:     log,
+		metrics: newMetrics(reg),
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+}
+
+// Add регистрирует задачу с расписанием в формате Parse.
+// Каждый запуск сдвигается на случайную величину от 0 до jitter,
+// чтобы реплики сервиса не выполняли задачу одновременно.
+func (s *Scheduler) Add(name, spec string, jitter time.Duration, fn JobFunc) error {
+	schedule, err := Parse(spec)
+	if err != nil {
+		return fmt.Errorf("job %s: %w", name, err)
+	}
+	return s.AddSchedule(name, schedule, jitter, fn)
+}
+
+// AddSchedule регистрирует задачу с готовым расписанием
+func (s *Scheduler) AddSchedule(name string, schedule Schedule, jitter time.Duration, fn JobFunc) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.started {
+		return errors.New("scheduler already started")
+	}
+	for _, j := range s.jobs {
+		if j.name == name {
+			return fmt.Errorf("job %s already registered", name)
+		}
+	}
+
+	s.jobs = append(s.jobs, &job{
+		name:     name,
+		schedule: schedule,
+		jitter:   jitter,
+		fn:       fn,
+	})
+	return nil
+}
+
+// Run запускает все задачи и блокируется до вызова Stop
+func (s *Scheduler) Run() {
+	s.mu.Lock()
+	s.started = true
+	jobs := append([]*job{}, s.jobs...)
+	s.mu.Unlock()
+
+	for _, j := range jobs {
+		s.log.Info("Scheduling job", logger.String("job", j.name))
+		s.wg.Add(1)
+		go s.loop(j)
+	}
+
+	<-s.ctx.Done()
+}
+
+// Stop отменяет контекст задач и ждет завершения текущих запусков не дольше дедлайна ctx
+func (s *Scheduler) Stop(ctx context.Context) error {
+	s.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("scheduler jobs did not finish: %w", ctx.Err())
+	}
+}
+
+func (s *Scheduler) loop(j *job) {
+	defer s.wg.Done()
+
+	for {
+		next := j.schedule.Next(time.Now())
+		if next.IsZero() {
+			s.log.Warn("Job has no future runs", logger.String("job", j.name))
+			return
+		}
+		if j.jitter > 0 {
+			next = next.Add(rand.N(j.jitter))
+		}
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-s.ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+			s.run(j)
+		}
+	}
+}
+
+func (s *Scheduler) run(j *job) {
+	start := time.Now()
+	err := s.safeRun(j)
+	duration := time.Since(start)
+
+	s.metrics.observe(j.name, duration, err)
+
+	if err != nil {
+		s.log.Error("Job failed",
+			logger.String("job", j.name),
+			logger.Duration("duration_seconds", duration.Seconds()),
+			logger.Error(err))
+		return
+	}
+
+	s.log.Info("Job completed",
+		logger.String("job", j.name),
+		logger.Duration("duration_seconds", duration.Seconds()))
+}
+
+// safeRun выполняет задачу, превращая панику в ошибку, чтобы не остановить остальные задачи
+func (s *Scheduler) safeRun(j *job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return j.fn(s.ctx)
+}
+
+type metrics struct {
+	runs        *prometheus.CounterVec
+	duration    *prometheus.HistogramVec
+	lastSuccess *prometheus.GaugeVec
+}
+
+func newMetrics(reg prometheus.Registerer) *metrics {
+	if reg == nil {
+		return nil
+	}
+
+	m := &metrics{
+		runs: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "scheduler_job_runs_total",
+			Help: "Total number of scheduled job runs, by job and status.",
+		}, []string{"job", "status"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "scheduler_job_duration_seconds",
+			Help:    "Histogram of scheduled job run duration, by job.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"job"}),
+		lastSuccess: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "scheduler_job_last_success_timestamp_seconds",
+			Help: "Unix time of the last successful run, by job.",
+		}, []string{"job"}),
+	}
+	reg.MustRegister(m.runs, m.duration, m.lastSuccess)
+	return m
+}
+
+func (m *metrics) observe(name string, duration time.Duration, err error) {
+	if m == nil {
+		return
+	}
+
+	status := "success"
+	if err != nil {
+		status = "error"
+	} else {
+		m.lastSuccess.WithLabelValues(name).SetToCurrentTime()
+	}
+	m.runs.WithLabelValues(name, status).Inc()
+	m.duration.WithLabelValues(name).Observe(duration.Seconds())
+}
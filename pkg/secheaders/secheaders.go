@@ -0,0 +1,83 @@
+// Package secheaders добавляет к HTTP ответам заголовки безопасности
+// (CSP, X-Content-Type-Options, X-Frame-Options, Referrer-Policy, HSTS)
+package secheaders
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Config значения заголовков. Пустое значение отключает заголовок.
+type Config struct {
+	ContentSecurityPolicy string
+	FrameOptions          string // DENY или SAMEORIGIN
+	ReferrerPolicy        string
+	NoSniff               bool          // X-Content-Type-Options: nosniff
+	HSTSMaxAge            time.Duration // 0 - Strict-Transport-Security не отправляется
+	HSTSIncludeSubdomains bool
+}
+
+// apiCSP политика для JSON API: ответы не должны ничего загружать и встраиваться в страницы
+const apiCSP = "default-src 'none'; frame-ancestors 'none'"
+
+// Development настройки для разработки: без HSTS, чтобы не закреплять HTTPS за localhost
+func Development() Config {
+	return Config{
+		ContentSecurityPolicy: apiCSP,
+		FrameOptions:          "DENY",
+		ReferrerPolicy:        "strict-origin-when-cross-origin",
+		NoSniff:               true,
+	}
+}
+
+// Production настройки для production: HSTS на год с поддоменами
+func Production() Config {
+	cfg := Development()
+	cfg.ReferrerPolicy = "no-referrer"
+	cfg.HSTSMaxAge = 365 * 24 * time.Hour
+	cfg.HSTSIncludeSubdomains = true
+	return cfg
+}
+
+// ForEnv возвращает настройки для окружения (production или development)
+func ForEnv(env string) Config {
+	if env == "production" {
+		return Production()
+	}
+	return Development()
+}
+
+// Middleware устанавливает заголовки на все ответы, в т.ч. на ошибки
+func Middleware(cfg Config) func(http.Handler) http.Handler {
+	hsts := ""
+	if cfg.HSTSMaxAge > 0 {
+		hsts = "max-age=" + strconv.FormatInt(int64(cfg.HSTSMaxAge/time.Second), 10)
+		if cfg.HSTSIncludeSubdomains {
+			hsts += "; includeSubDomains"
+		}
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			h := w.Header()
+			if cfg.ContentSecurityPolicy != "" {
+				h.Set("Content-Security-Policy", cfg.ContentSecurityPolicy)
+			}
+			if cfg.FrameOptions != "" {
+				h.Set("X-Frame-Options", cfg.FrameOptions)
+			}
+			if cfg.ReferrerPolicy != "" {
+				h.Set("Referrer-Policy", cfg.ReferrerPolicy)
+			}
+			if cfg.NoSniff {
+				h.Set("X-Content-Type-Options", "nosniff")
+			}
+			if hsts != "" {
+				h.Set("Strict-Transport-Security", hsts)
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
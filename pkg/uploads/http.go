@@ -0,0 +1,56 @@
+package uploads
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// cacheControl файлы неизменяемы (ключ - хеш содержимого), поэтому кешируются на год
+const cacheControl = "public, max-age=31536000, immutable"
+
+// Handler отдает файлы хранилища по пути prefix/<ключ>. Поддерживает ETag
+// (If-None-Match), If-Modified-Since и запросы диапазонов (Range).
+// Если redirectURL не пуст, вместо отдачи файла клиент перенаправляется на redirectURL/<ключ>.
+func Handler(storage Storage, prefix, redirectURL string) http.Handler {
+	redirectURL = strings.TrimSuffix(redirectURL, "/")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		key := strings.TrimPrefix(r.URL.Path, prefix+"/")
+		if !ValidKey(key) {
+			http.NotFound(w, r)
+			return
+		}
+
+		if redirectURL != "" {
+			w.Header().Set("Cache-Control", cacheControl)
+			http.Redirect(w, r, redirectURL+"/"+key, http.StatusFound)
+			return
+		}
+
+		f, obj, err := storage.Open(key)
+		if errors.Is(err, ErrNotFound) {
+			http.NotFound(w, r)
+			return
+		}
+		if err != nil {
+			http.Error(w, "internal server error", http.StatusInternalServerError)
+			return
+		}
+		defer f.Close()
+
+		w.Header().Set("ETag", fmt.Sprintf(`"%x-%x"`, obj.ModTime.UnixNano(), obj.Size))
+		w.Header().Set("Cache-Control", cacheControl)
+		// Загруженный пользователем файл не должен выполняться как страница нашего сайта
+		w.Header().Set("Content-Security-Policy", "default-src 'none'; sandbox")
+
+		http.ServeContent(w, r, key, obj.ModTime, f)
+	})
+}
@@ -0,0 +1,153 @@
+package uploads
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	_ "image/gif" // Регистрирует декодер GIF для image.Decode
+	"image/jpeg"
+	"image/png"
+	"io"
+)
+
+// MaxImagePixels защита от "декомпрессионных бомб": маленький файл с огромными размерами
+const MaxImagePixels = 40_000_000
+
+// ErrImageTooLarge размеры изображения превышают MaxImagePixels
+var ErrImageTooLarge = errors.New("image dimensions are too large")
+
+// imageTypes изображения, которые умеет декодировать стандартная библиотека, и расширения файлов
+var imageTypes = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+}
+
+// IsImage сообщает, можно ли построить уменьшенные копии для contentType
+func IsImage(contentType string) bool {
+	_, ok := imageTypes[contentType]
+	return ok
+}
+
+// DecodeImage декодирует JPEG, PNG или GIF (первый кадр), предварительно проверив размеры
+func DecodeImage(r io.Reader) (image.Image, string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, "", err
+	}
+
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to decode image: %w", err)
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > MaxImagePixels {
+		return nil, "", ErrImageTooLarge
+	}
+
+	img, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to decode image: %w", err)
+	}
+	return img, format, nil
+}
+
+// Fit возвращает размеры, в которые изображение w x h вписывается в maxW x maxH
+// с сохранением пропорций. Изображение не увеличивается.
+func Fit(w, h, maxW, maxH int) (int, int) {
+	if w <= maxW && h <= maxH {
+		return w, h
+	}
+	if w*maxH > h*maxW {
+		return maxW, max(1, h*maxW/w)
+	}
+	return max(1, w*maxH/h), maxH
+}
+
+// CropSquare вырезает из центра изображения квадрат по меньшей стороне
+func CropSquare(src image.Image) image.Image {
+	b := src.Bounds()
+	side := min(b.Dx(), b.Dy())
+	x0 := b.Min.X + (b.Dx()-side)/2
+	y0 := b.Min.Y + (b.Dy()-side)/2
+	rect := image.Rect(x0, y0, x0+side, y0+side)
+
+	if sub, ok := src.(interface {
+		SubImage(image.Rectangle) image.Image
+	}); ok {
+		return sub.SubImage(rect)
+	}
+	dst := image.NewNRGBA(image.Rect(0, 0, side, side))
+	draw.Draw(dst, dst.Bounds(), src, rect.Min, draw.Src)
+	return dst
+}
+
+// Resize масштабирует изображение до w x h усреднением пикселей (box filter).
+// Для уменьшения этого достаточно, внешние библиотеки не нужны.
+func Resize(src image.Image, w, h int) *image.NRGBA {
+	b := src.Bounds()
+	srcImg, ok := src.(*image.NRGBA)
+	if !ok || b.Min != (image.Point{}) {
+		srcImg = image.NewNRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+		draw.Draw(srcImg, srcImg.Bounds(), src, b.Min, draw.Src)
+	}
+	sw, sh := b.Dx(), b.Dy()
+
+	dst := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		y0 := y * sh / h
+		y1 := max(y0+1, (y+1)*sh/h)
+		for x := 0; x < w; x++ {
+			x0 := x * sw / w
+			x1 := max(x0+1, (x+1)*sw/w)
+
+			// Цвет усредняется с весом альфа-канала, чтобы прозрачные пиксели не темнили края
+			var r, g, bl, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				row := srcImg.Pix[sy*srcImg.Stride:]
+				for sx := x0; sx < x1; sx++ {
+					p := row[sx*4 : sx*4+4]
+					pa := uint64(p[3])
+					r += uint64(p[0]) * pa
+					g += uint64(p[1]) * pa
+					bl += uint64(p[2]) * pa
+					a += pa
+					n++
+				}
+			}
+
+			o := dst.PixOffset(x, y)
+			if a > 0 {
+				dst.Pix[o] = uint8(r / a)
+				dst.Pix[o+1] = uint8(g / a)
+				dst.Pix[o+2] = uint8(bl / a)
+			}
+			dst.Pix[o+3] = uint8(a / n)
+		}
+	}
+	return dst
+}
+
+// Encode кодирует изображение: JPEG для фотографий, PNG для остального (сохраняет прозрачность).
+// Возвращает данные и расширение файла.
+func Encode(img image.Image, format string) ([]byte, string, error) {
+	var buf bytes.Buffer
+	switch format {
+	case "jpeg":
+		if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 85}); err != nil {
+			return nil, "", err
+		}
+		return buf.Bytes(), ".jpg", nil
+	default:
+		if err := png.Encode(&buf, img); err != nil {
+			return nil, "", err
+		}
+		return buf.Bytes(), ".png", nil
+	}
+}
+
+// ImageExt расширение файла для типа изображения
+func ImageExt(contentType string) string {
+	return imageTypes[contentType]
+}
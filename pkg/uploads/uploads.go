@@ -0,0 +1,150 @@
+// Package uploads хранит загруженные пользователями файлы (вложения, аватары) и отдает их по HTTP.
+// Файлы адресуются хешем содержимого, поэтому однажды сохраненный файл не меняется
+// и может кешироваться клиентами и CDN без ограничения срока.
+package uploads
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// ErrNotFound файла с таким ключом нет
+var ErrNotFound = errors.New("upload not found")
+
+// Config настройки хранилища
+type Config struct {
+	Dir string // Каталог с файлами
+	// PublicURL внешний адрес файлов (бакет S3, CDN). Если задан, /static/uploads
+	// перенаправляет на него, а файлы в Dir синхронизируются с бакетом внешними средствами.
+	PublicURL string
+}
+
+// Object сохраненный файл
+type Object struct {
+	Key     string
+	Size    int64
+	ModTime time.Time
+}
+
+// Saver сохраняет файлы и выдает их адреса; реализуется всеми хранилищами, в том числе S3Storage
+type Saver interface {
+	// Save сохраняет содержимое и возвращает ключ: хеш содержимого и расширение ext (".png").
+	// Повторная загрузка того же содержимого возвращает тот же ключ.
+	Save(ctx context.Context, r io.Reader, ext string) (string, error)
+	// URL адрес, по которому файл доступен клиентам
+	URL(key string) string
+}
+
+// Storage хранилище загрузок, файлы которого можно отдавать через Handler
+type Storage interface {
+	Saver
+	// Open открывает файл для чтения; вызывающий закрывает его
+	Open(key string) (*os.File, *Object, error)
+}
+
+// keyPattern "ab/<sha256>.ext": первый байт хеша - подкаталог, чтобы не держать все файлы в одном каталоге
+var keyPattern = regexp.MustCompile(`^[0-9a-f]{2}/[0-9a-f]{64}\.[a-z0-9]{1,5}$`)
+
+// ValidKey проверяет формат ключа; защищает от выхода за пределы каталога хранилища
+func ValidKey(key string) bool {
+	return keyPattern.MatchString(key)
+}
+
+// contentKey ключ файла по хешу содержимого sum и расширению ext
+func contentKey(sum []byte, ext string) (string, error) {
+	ext = strings.ToLower(ext)
+	if !strings.HasPrefix(ext, ".") {
+		ext = "." + ext
+	}
+
+	hexSum := hex.EncodeToString(sum)
+	key := hexSum[:2] + "/" + hexSum + ext
+	if !ValidKey(key) {
+		return "", fmt.Errorf("invalid file extension %q", ext)
+	}
+	return key, nil
+}
+
+// LocalStorage хранит файлы в каталоге на диске
+type LocalStorage struct {
+	dir     string
+	baseURL string
+}
+
+// NewLocalStorage создает каталог dir, если его нет. baseURL - префикс адресов файлов
+// ("/static/uploads" или внешний PublicURL).
+func NewLocalStorage(dir, baseURL string) (*LocalStorage, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create uploads dir: %w", err)
+	}
+	return &LocalStorage{dir: dir, baseURL: strings.TrimSuffix(baseURL, "/")}, nil
+}
+
+func (s *LocalStorage) Save(ctx context.Context, r io.Reader, ext string) (string, error) {
+	// Пишем во временный файл, одновременно считая хеш, затем переносим под итоговым именем
+	tmp, err := os.CreateTemp(s.dir, ".upload-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	hash := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(tmp, hash), r); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("failed to write upload: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", fmt.Errorf("failed to write upload: %w", err)
+	}
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	key, err := contentKey(hash.Sum(nil), ext)
+	if err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(s.dir, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", fmt.Errorf("failed to create upload dir: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", fmt.Errorf("failed to store upload: %w", err)
+	}
+	return key, nil
+}
+
+func (s *LocalStorage) Open(key string) (*os.File, *Object, error) {
+	if !ValidKey(key) {
+		return nil, nil, ErrNotFound
+	}
+
+	f, err := os.Open(filepath.Join(s.dir, filepath.FromSlash(key)))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, nil, err
+	}
+	return f, &Object{Key: key, Size: info.Size(), ModTime: info.ModTime()}, nil
+}
+
+func (s *LocalStorage) URL(key string) string {
+	return s.baseURL + "/" + key
+}
@@ -0,0 +1,1977 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v6.30.2
+// source: proto/forum/v1/forum.proto
+
+// Версия v1: несовместимые изменения схемы вносятся в новый пакет forum.v2,
+// клиенты v1 продолжают работать без изменений.
+
+package forumv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	fieldmaskpb "google.golang.org/protobuf/types/known/fieldmaskpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// ===== Posts =====
+type CreatePostRequest struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Title      string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
+	Content    string                 `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"`
+	CategoryId string                 `protobuf:"bytes,3,opt,name=category_id,json=categoryId,proto3" json:"category_id,omitempty"`
+	// Автор берется из токена. Запросы с заполненным полем отклоняются (INVALID_ARGUMENT).
+	//
+	// Deprecated: Marked as deprecated in proto/forum/v1/forum.proto.
+	AuthorId      string `protobuf:"bytes,4,opt,name=author_id,json=authorId,proto3" json:"author_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreatePostRequest) Reset() {
+	*x = CreatePostRequest{}
+	mi := &file_proto_forum_v1_forum_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreatePostRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreatePostRequest) ProtoMessage() {}
+
+func (x *CreatePostRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_forum_v1_forum_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreatePostRequest.ProtoReflect.Descriptor instead.
+func (*CreatePostRequest) Descriptor() ([]byte, []int) {
+	return file_proto_forum_v1_forum_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *CreatePostRequest) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *CreatePostRequest) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *CreatePostRequest) GetCategoryId() string {
+	if x != nil {
+		return x.CategoryId
+	}
+	return ""
+}
+
+// Deprecated: Marked as deprecated in proto/forum/v1/forum.proto.
+func (x *CreatePostRequest) GetAuthorId() string {
+	if x != nil {
+		return x.AuthorId
+	}
+	return ""
+}
+
+type GetPostRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PostId        string                 `protobuf:"bytes,1,opt,name=post_id,json=postId,proto3" json:"post_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPostRequest) Reset() {
+	*x = GetPostRequest{}
+	mi := &file_proto_forum_v1_forum_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPostRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPostRequest) ProtoMessage() {}
+
+func (x *GetPostRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_forum_v1_forum_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPostRequest.ProtoReflect.Descriptor instead.
+func (*GetPostRequest) Descriptor() ([]byte, []int) {
+	return file_proto_forum_v1_forum_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GetPostRequest) GetPostId() string {
+	if x != nil {
+		return x.PostId
+	}
+	return ""
+}
+
+// Списки возвращаются страницами от новых к старым (AIP-158): page_token берется
+// из next_page_token предыдущего ответа вместе с теми же параметрами фильтра.
+type GetPostsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CategoryId    string                 `protobuf:"bytes,3,opt,name=category_id,json=categoryId,proto3" json:"category_id,omitempty"` // optional
+	PageSize      int32                  `protobuf:"varint,4,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`      // По умолчанию 20, не больше 100
+	PageToken     string                 `protobuf:"bytes,5,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPostsRequest) Reset() {
+	*x = GetPostsRequest{}
+	mi := &file_proto_forum_v1_forum_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPostsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPostsRequest) ProtoMessage() {}
+
+func (x *GetPostsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_forum_v1_forum_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPostsRequest.ProtoReflect.Descriptor instead.
+func (*GetPostsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_forum_v1_forum_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetPostsRequest) GetCategoryId() string {
+	if x != nil {
+		return x.CategoryId
+	}
+	return ""
+}
+
+func (x *GetPostsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *GetPostsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type StreamPostsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CategoryId    string                 `protobuf:"bytes,1,opt,name=category_id,json=categoryId,proto3" json:"category_id,omitempty"`          // optional
+	CreatedAfter  *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=created_after,json=createdAfter,proto3" json:"created_after,omitempty"`    // optional, включительно
+	CreatedBefore *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=created_before,json=createdBefore,proto3" json:"created_before,omitempty"` // optional, не включительно
+	BatchSize     int32                  `protobuf:"varint,4,opt,name=batch_size,json=batchSize,proto3" json:"batch_size,omitempty"`            // По умолчанию 100, не больше 500
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamPostsRequest) Reset() {
+	*x = StreamPostsRequest{}
+	mi := &file_proto_forum_v1_forum_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamPostsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamPostsRequest) ProtoMessage() {}
+
+func (x *StreamPostsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_forum_v1_forum_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamPostsRequest.ProtoReflect.Descriptor instead.
+func (*StreamPostsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_forum_v1_forum_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *StreamPostsRequest) GetCategoryId() string {
+	if x != nil {
+		return x.CategoryId
+	}
+	return ""
+}
+
+func (x *StreamPostsRequest) GetCreatedAfter() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAfter
+	}
+	return nil
+}
+
+func (x *StreamPostsRequest) GetCreatedBefore() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedBefore
+	}
+	return nil
+}
+
+func (x *StreamPostsRequest) GetBatchSize() int32 {
+	if x != nil {
+		return x.BatchSize
+	}
+	return 0
+}
+
+type StreamPostsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Posts         []*PostResponse        `protobuf:"bytes,1,rep,name=posts,proto3" json:"posts,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamPostsResponse) Reset() {
+	*x = StreamPostsResponse{}
+	mi := &file_proto_forum_v1_forum_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamPostsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamPostsResponse) ProtoMessage() {}
+
+func (x *StreamPostsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_forum_v1_forum_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamPostsResponse.ProtoReflect.Descriptor instead.
+func (*StreamPostsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_forum_v1_forum_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *StreamPostsResponse) GetPosts() []*PostResponse {
+	if x != nil {
+		return x.Posts
+	}
+	return nil
+}
+
+// Редактировать пост может только автор
+type UpdatePostRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	PostId  string                 `protobuf:"bytes,1,opt,name=post_id,json=postId,proto3" json:"post_id,omitempty"`
+	Title   string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Content string                 `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"`
+	// Изменяемые поля: title, content. Без маски меняются оба поля.
+	UpdateMask    *fieldmaskpb.FieldMask `protobuf:"bytes,4,opt,name=update_mask,json=updateMask,proto3" json:"update_mask,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdatePostRequest) Reset() {
+	*x = UpdatePostRequest{}
+	mi := &file_proto_forum_v1_forum_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdatePostRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdatePostRequest) ProtoMessage() {}
+
+func (x *UpdatePostRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_forum_v1_forum_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdatePostRequest.ProtoReflect.Descriptor instead.
+func (*UpdatePostRequest) Descriptor() ([]byte, []int) {
+	return file_proto_forum_v1_forum_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *UpdatePostRequest) GetPostId() string {
+	if x != nil {
+		return x.PostId
+	}
+	return ""
+}
+
+func (x *UpdatePostRequest) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *UpdatePostRequest) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *UpdatePostRequest) GetUpdateMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.UpdateMask
+	}
+	return nil
+}
+
+// Удалить пост может автор или модератор категории
+type DeletePostRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PostId        string                 `protobuf:"bytes,1,opt,name=post_id,json=postId,proto3" json:"post_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeletePostRequest) Reset() {
+	*x = DeletePostRequest{}
+	mi := &file_proto_forum_v1_forum_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeletePostRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeletePostRequest) ProtoMessage() {}
+
+func (x *DeletePostRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_forum_v1_forum_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeletePostRequest.ProtoReflect.Descriptor instead.
+func (*DeletePostRequest) Descriptor() ([]byte, []int) {
+	return file_proto_forum_v1_forum_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *DeletePostRequest) GetPostId() string {
+	if x != nil {
+		return x.PostId
+	}
+	return ""
+}
+
+type DeletePostResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeletePostResponse) Reset() {
+	*x = DeletePostResponse{}
+	mi := &file_proto_forum_v1_forum_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeletePostResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeletePostResponse) ProtoMessage() {}
+
+func (x *DeletePostResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_forum_v1_forum_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeletePostResponse.ProtoReflect.Descriptor instead.
+func (*DeletePostResponse) Descriptor() ([]byte, []int) {
+	return file_proto_forum_v1_forum_proto_rawDescGZIP(), []int{7}
+}
+
+type PostResponse struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Id         string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Title      string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Content    string                 `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"`
+	AuthorId   string                 `protobuf:"bytes,4,opt,name=author_id,json=authorId,proto3" json:"author_id,omitempty"`
+	CategoryId string                 `protobuf:"bytes,5,opt,name=category_id,json=categoryId,proto3" json:"category_id,omitempty"`
+	// Устарело: время создания строкой RFC3339, используйте created_at
+	//
+	// Deprecated: Marked as deprecated in proto/forum/v1/forum.proto.
+	CreatedAtRfc3339 string                 `protobuf:"bytes,6,opt,name=created_at_rfc3339,json=createdAtRfc3339,proto3" json:"created_at_rfc3339,omitempty"`
+	IsPinned         bool                   `protobuf:"varint,7,opt,name=is_pinned,json=isPinned,proto3" json:"is_pinned,omitempty"`
+	CreatedAt        *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	AuthorKarma      int32                  `protobuf:"varint,9,opt,name=author_karma,json=authorKarma,proto3" json:"author_karma,omitempty"` // Карма автора
+	IsUnread         bool                   `protobuf:"varint,10,opt,name=is_unread,json=isUnread,proto3" json:"is_unread,omitempty"`         // Новая активность после визита; только в списках для аутентифицированного читателя
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *PostResponse) Reset() {
+	*x = PostResponse{}
+	mi := &file_proto_forum_v1_forum_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PostResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PostResponse) ProtoMessage() {}
+
+func (x *PostResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_forum_v1_forum_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PostResponse.ProtoReflect.Descriptor instead.
+func (*PostResponse) Descriptor() ([]byte, []int) {
+	return file_proto_forum_v1_forum_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *PostResponse) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *PostResponse) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *PostResponse) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *PostResponse) GetAuthorId() string {
+	if x != nil {
+		return x.AuthorId
+	}
+	return ""
+}
+
+func (x *PostResponse) GetCategoryId() string {
+	if x != nil {
+		return x.CategoryId
+	}
+	return ""
+}
+
+// Deprecated: Marked as deprecated in proto/forum/v1/forum.proto.
+func (x *PostResponse) GetCreatedAtRfc3339() string {
+	if x != nil {
+		return x.CreatedAtRfc3339
+	}
+	return ""
+}
+
+func (x *PostResponse) GetIsPinned() bool {
+	if x != nil {
+		return x.IsPinned
+	}
+	return false
+}
+
+func (x *PostResponse) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *PostResponse) GetAuthorKarma() int32 {
+	if x != nil {
+		return x.AuthorKarma
+	}
+	return 0
+}
+
+func (x *PostResponse) GetIsUnread() bool {
+	if x != nil {
+		return x.IsUnread
+	}
+	return false
+}
+
+type GetPostsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Posts         []*PostResponse        `protobuf:"bytes,1,rep,name=posts,proto3" json:"posts,omitempty"`
+	Total         int32                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,3,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"` // Пустой на последней странице
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPostsResponse) Reset() {
+	*x = GetPostsResponse{}
+	mi := &file_proto_forum_v1_forum_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPostsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPostsResponse) ProtoMessage() {}
+
+func (x *GetPostsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_forum_v1_forum_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPostsResponse.ProtoReflect.Descriptor instead.
+func (*GetPostsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_forum_v1_forum_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetPostsResponse) GetPosts() []*PostResponse {
+	if x != nil {
+		return x.Posts
+	}
+	return nil
+}
+
+func (x *GetPostsResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *GetPostsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+// ===== Comments =====
+type CreateCommentRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	PostId  string                 `protobuf:"bytes,1,opt,name=post_id,json=postId,proto3" json:"post_id,omitempty"`
+	Content string                 `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"`
+	// Автор берется из токена. Запросы с заполненным полем отклоняются (INVALID_ARGUMENT).
+	//
+	// Deprecated: Marked as deprecated in proto/forum/v1/forum.proto.
+	AuthorId      string `protobuf:"bytes,3,opt,name=author_id,json=authorId,proto3" json:"author_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateCommentRequest) Reset() {
+	*x = CreateCommentRequest{}
+	mi := &file_proto_forum_v1_forum_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateCommentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateCommentRequest) ProtoMessage() {}
+
+func (x *CreateCommentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_forum_v1_forum_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateCommentRequest.ProtoReflect.Descriptor instead.
+func (*CreateCommentRequest) Descriptor() ([]byte, []int) {
+	return file_proto_forum_v1_forum_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *CreateCommentRequest) GetPostId() string {
+	if x != nil {
+		return x.PostId
+	}
+	return ""
+}
+
+func (x *CreateCommentRequest) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+// Deprecated: Marked as deprecated in proto/forum/v1/forum.proto.
+func (x *CreateCommentRequest) GetAuthorId() string {
+	if x != nil {
+		return x.AuthorId
+	}
+	return ""
+}
+
+// Редактировать комментарий может только автор
+type UpdateCommentRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	CommentId string                 `protobuf:"bytes,1,opt,name=comment_id,json=commentId,proto3" json:"comment_id,omitempty"`
+	Content   string                 `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"`
+	// Изменяемые поля: content
+	UpdateMask    *fieldmaskpb.FieldMask `protobuf:"bytes,3,opt,name=update_mask,json=updateMask,proto3" json:"update_mask,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateCommentRequest) Reset() {
+	*x = UpdateCommentRequest{}
+	mi := &file_proto_forum_v1_forum_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateCommentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateCommentRequest) ProtoMessage() {}
+
+func (x *UpdateCommentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_forum_v1_forum_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateCommentRequest.ProtoReflect.Descriptor instead.
+func (*UpdateCommentRequest) Descriptor() ([]byte, []int) {
+	return file_proto_forum_v1_forum_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *UpdateCommentRequest) GetCommentId() string {
+	if x != nil {
+		return x.CommentId
+	}
+	return ""
+}
+
+func (x *UpdateCommentRequest) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *UpdateCommentRequest) GetUpdateMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.UpdateMask
+	}
+	return nil
+}
+
+type GetCommentsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PostId        string                 `protobuf:"bytes,1,opt,name=post_id,json=postId,proto3" json:"post_id,omitempty"`
+	PageSize      int32                  `protobuf:"varint,4,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken     string                 `protobuf:"bytes,5,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCommentsRequest) Reset() {
+	*x = GetCommentsRequest{}
+	mi := &file_proto_forum_v1_forum_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCommentsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCommentsRequest) ProtoMessage() {}
+
+func (x *GetCommentsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_forum_v1_forum_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCommentsRequest.ProtoReflect.Descriptor instead.
+func (*GetCommentsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_forum_v1_forum_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *GetCommentsRequest) GetPostId() string {
+	if x != nil {
+		return x.PostId
+	}
+	return ""
+}
+
+func (x *GetCommentsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *GetCommentsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type CommentResponse struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Id       string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Content  string                 `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"`
+	PostId   string                 `protobuf:"bytes,3,opt,name=post_id,json=postId,proto3" json:"post_id,omitempty"`
+	AuthorId string                 `protobuf:"bytes,4,opt,name=author_id,json=authorId,proto3" json:"author_id,omitempty"`
+	// Устарело: время создания строкой RFC3339, используйте created_at
+	//
+	// Deprecated: Marked as deprecated in proto/forum/v1/forum.proto.
+	CreatedAtRfc3339 string                 `protobuf:"bytes,5,opt,name=created_at_rfc3339,json=createdAtRfc3339,proto3" json:"created_at_rfc3339,omitempty"`
+	CreatedAt        *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	AuthorKarma      int32                  `protobuf:"varint,7,opt,name=author_karma,json=authorKarma,proto3" json:"author_karma,omitempty"` // Карма автора
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *CommentResponse) Reset() {
+	*x = CommentResponse{}
+	mi := &file_proto_forum_v1_forum_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CommentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommentResponse) ProtoMessage() {}
+
+func (x *CommentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_forum_v1_forum_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommentResponse.ProtoReflect.Descriptor instead.
+func (*CommentResponse) Descriptor() ([]byte, []int) {
+	return file_proto_forum_v1_forum_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *CommentResponse) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *CommentResponse) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *CommentResponse) GetPostId() string {
+	if x != nil {
+		return x.PostId
+	}
+	return ""
+}
+
+func (x *CommentResponse) GetAuthorId() string {
+	if x != nil {
+		return x.AuthorId
+	}
+	return ""
+}
+
+// Deprecated: Marked as deprecated in proto/forum/v1/forum.proto.
+func (x *CommentResponse) GetCreatedAtRfc3339() string {
+	if x != nil {
+		return x.CreatedAtRfc3339
+	}
+	return ""
+}
+
+func (x *CommentResponse) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *CommentResponse) GetAuthorKarma() int32 {
+	if x != nil {
+		return x.AuthorKarma
+	}
+	return 0
+}
+
+type GetCommentsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Comments      []*CommentResponse     `protobuf:"bytes,1,rep,name=comments,proto3" json:"comments,omitempty"`
+	Total         int32                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,3,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCommentsResponse) Reset() {
+	*x = GetCommentsResponse{}
+	mi := &file_proto_forum_v1_forum_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCommentsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCommentsResponse) ProtoMessage() {}
+
+func (x *GetCommentsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_forum_v1_forum_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCommentsResponse.ProtoReflect.Descriptor instead.
+func (*GetCommentsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_forum_v1_forum_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *GetCommentsResponse) GetComments() []*CommentResponse {
+	if x != nil {
+		return x.Comments
+	}
+	return nil
+}
+
+func (x *GetCommentsResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *GetCommentsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+// ===== Chat =====
+type GetChatMessagesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PageSize      int32                  `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken     string                 `protobuf:"bytes,4,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetChatMessagesRequest) Reset() {
+	*x = GetChatMessagesRequest{}
+	mi := &file_proto_forum_v1_forum_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetChatMessagesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetChatMessagesRequest) ProtoMessage() {}
+
+func (x *GetChatMessagesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_forum_v1_forum_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetChatMessagesRequest.ProtoReflect.Descriptor instead.
+func (*GetChatMessagesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_forum_v1_forum_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *GetChatMessagesRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *GetChatMessagesRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+// Сообщение отправляется в чат сообщества от имени пользователя из токена
+// и рассылается WebSocket клиентам
+type SendChatMessageRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Text          string                 `protobuf:"bytes,1,opt,name=text,proto3" json:"text,omitempty"`
+	AttachmentIds []string               `protobuf:"bytes,2,rep,name=attachment_ids,json=attachmentIds,proto3" json:"attachment_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SendChatMessageRequest) Reset() {
+	*x = SendChatMessageRequest{}
+	mi := &file_proto_forum_v1_forum_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SendChatMessageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SendChatMessageRequest) ProtoMessage() {}
+
+func (x *SendChatMessageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_forum_v1_forum_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SendChatMessageRequest.ProtoReflect.Descriptor instead.
+func (*SendChatMessageRequest) Descriptor() ([]byte, []int) {
+	return file_proto_forum_v1_forum_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *SendChatMessageRequest) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *SendChatMessageRequest) GetAttachmentIds() []string {
+	if x != nil {
+		return x.AttachmentIds
+	}
+	return nil
+}
+
+type ChatMessage struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Id     string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserId string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Text   string                 `protobuf:"bytes,3,opt,name=text,proto3" json:"text,omitempty"`
+	// Устарело: время отправки строкой RFC3339, используйте created_at
+	//
+	// Deprecated: Marked as deprecated in proto/forum/v1/forum.proto.
+	CreatedAtRfc3339 string                 `protobuf:"bytes,4,opt,name=created_at_rfc3339,json=createdAtRfc3339,proto3" json:"created_at_rfc3339,omitempty"`
+	CreatedAt        *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *ChatMessage) Reset() {
+	*x = ChatMessage{}
+	mi := &file_proto_forum_v1_forum_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChatMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChatMessage) ProtoMessage() {}
+
+func (x *ChatMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_forum_v1_forum_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChatMessage.ProtoReflect.Descriptor instead.
+func (*ChatMessage) Descriptor() ([]byte, []int) {
+	return file_proto_forum_v1_forum_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ChatMessage) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ChatMessage) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ChatMessage) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+// Deprecated: Marked as deprecated in proto/forum/v1/forum.proto.
+func (x *ChatMessage) GetCreatedAtRfc3339() string {
+	if x != nil {
+		return x.CreatedAtRfc3339
+	}
+	return ""
+}
+
+func (x *ChatMessage) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type GetChatMessagesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Messages      []*ChatMessage         `protobuf:"bytes,1,rep,name=messages,proto3" json:"messages,omitempty"`
+	Total         int32                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,3,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetChatMessagesResponse) Reset() {
+	*x = GetChatMessagesResponse{}
+	mi := &file_proto_forum_v1_forum_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetChatMessagesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetChatMessagesResponse) ProtoMessage() {}
+
+func (x *GetChatMessagesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_forum_v1_forum_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetChatMessagesResponse.ProtoReflect.Descriptor instead.
+func (*GetChatMessagesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_forum_v1_forum_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *GetChatMessagesResponse) GetMessages() []*ChatMessage {
+	if x != nil {
+		return x.Messages
+	}
+	return nil
+}
+
+func (x *GetChatMessagesResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *GetChatMessagesResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+// ===== Users =====
+type AnonymizeUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AnonymizeUserRequest) Reset() {
+	*x = AnonymizeUserRequest{}
+	mi := &file_proto_forum_v1_forum_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AnonymizeUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AnonymizeUserRequest) ProtoMessage() {}
+
+func (x *AnonymizeUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_forum_v1_forum_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AnonymizeUserRequest.ProtoReflect.Descriptor instead.
+func (*AnonymizeUserRequest) Descriptor() ([]byte, []int) {
+	return file_proto_forum_v1_forum_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *AnonymizeUserRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+// Сколько записей переназначено удаленному пользователю
+type AnonymizeUserResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Posts         int64                  `protobuf:"varint,1,opt,name=posts,proto3" json:"posts,omitempty"`
+	Comments      int64                  `protobuf:"varint,2,opt,name=comments,proto3" json:"comments,omitempty"`
+	ChatMessages  int64                  `protobuf:"varint,3,opt,name=chat_messages,json=chatMessages,proto3" json:"chat_messages,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AnonymizeUserResponse) Reset() {
+	*x = AnonymizeUserResponse{}
+	mi := &file_proto_forum_v1_forum_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AnonymizeUserResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AnonymizeUserResponse) ProtoMessage() {}
+
+func (x *AnonymizeUserResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_forum_v1_forum_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AnonymizeUserResponse.ProtoReflect.Descriptor instead.
+func (*AnonymizeUserResponse) Descriptor() ([]byte, []int) {
+	return file_proto_forum_v1_forum_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *AnonymizeUserResponse) GetPosts() int64 {
+	if x != nil {
+		return x.Posts
+	}
+	return 0
+}
+
+func (x *AnonymizeUserResponse) GetComments() int64 {
+	if x != nil {
+		return x.Comments
+	}
+	return 0
+}
+
+func (x *AnonymizeUserResponse) GetChatMessages() int64 {
+	if x != nil {
+		return x.ChatMessages
+	}
+	return 0
+}
+
+type ExportUserContentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	BatchSize     int32                  `protobuf:"varint,2,opt,name=batch_size,json=batchSize,proto3" json:"batch_size,omitempty"` // По умолчанию 100, не больше 500
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportUserContentRequest) Reset() {
+	*x = ExportUserContentRequest{}
+	mi := &file_proto_forum_v1_forum_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportUserContentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportUserContentRequest) ProtoMessage() {}
+
+func (x *ExportUserContentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_forum_v1_forum_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportUserContentRequest.ProtoReflect.Descriptor instead.
+func (*ExportUserContentRequest) Descriptor() ([]byte, []int) {
+	return file_proto_forum_v1_forum_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *ExportUserContentRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ExportUserContentRequest) GetBatchSize() int32 {
+	if x != nil {
+		return x.BatchSize
+	}
+	return 0
+}
+
+// Пачка выгрузки; в одной пачке заполнен один из списков
+type ExportUserContentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Posts         []*ExportedPost        `protobuf:"bytes,1,rep,name=posts,proto3" json:"posts,omitempty"`
+	Comments      []*ExportedComment     `protobuf:"bytes,2,rep,name=comments,proto3" json:"comments,omitempty"`
+	ChatMessages  []*ExportedChatMessage `protobuf:"bytes,3,rep,name=chat_messages,json=chatMessages,proto3" json:"chat_messages,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportUserContentResponse) Reset() {
+	*x = ExportUserContentResponse{}
+	mi := &file_proto_forum_v1_forum_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportUserContentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportUserContentResponse) ProtoMessage() {}
+
+func (x *ExportUserContentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_forum_v1_forum_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportUserContentResponse.ProtoReflect.Descriptor instead.
+func (*ExportUserContentResponse) Descriptor() ([]byte, []int) {
+	return file_proto_forum_v1_forum_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *ExportUserContentResponse) GetPosts() []*ExportedPost {
+	if x != nil {
+		return x.Posts
+	}
+	return nil
+}
+
+func (x *ExportUserContentResponse) GetComments() []*ExportedComment {
+	if x != nil {
+		return x.Comments
+	}
+	return nil
+}
+
+func (x *ExportUserContentResponse) GetChatMessages() []*ExportedChatMessage {
+	if x != nil {
+		return x.ChatMessages
+	}
+	return nil
+}
+
+type ExportedPost struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ForumId       string                 `protobuf:"bytes,2,opt,name=forum_id,json=forumId,proto3" json:"forum_id,omitempty"` // Сообщество
+	Title         string                 `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"`
+	Content       string                 `protobuf:"bytes,4,opt,name=content,proto3" json:"content,omitempty"`
+	CategoryId    string                 `protobuf:"bytes,5,opt,name=category_id,json=categoryId,proto3" json:"category_id,omitempty"`
+	Status        string                 `protobuf:"bytes,6,opt,name=status,proto3" json:"status,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportedPost) Reset() {
+	*x = ExportedPost{}
+	mi := &file_proto_forum_v1_forum_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportedPost) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportedPost) ProtoMessage() {}
+
+func (x *ExportedPost) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_forum_v1_forum_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportedPost.ProtoReflect.Descriptor instead.
+func (*ExportedPost) Descriptor() ([]byte, []int) {
+	return file_proto_forum_v1_forum_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *ExportedPost) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ExportedPost) GetForumId() string {
+	if x != nil {
+		return x.ForumId
+	}
+	return ""
+}
+
+func (x *ExportedPost) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *ExportedPost) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *ExportedPost) GetCategoryId() string {
+	if x != nil {
+		return x.CategoryId
+	}
+	return ""
+}
+
+func (x *ExportedPost) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ExportedPost) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type ExportedComment struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ForumId       string                 `protobuf:"bytes,2,opt,name=forum_id,json=forumId,proto3" json:"forum_id,omitempty"`
+	PostId        string                 `protobuf:"bytes,3,opt,name=post_id,json=postId,proto3" json:"post_id,omitempty"`
+	Content       string                 `protobuf:"bytes,4,opt,name=content,proto3" json:"content,omitempty"`
+	Status        string                 `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportedComment) Reset() {
+	*x = ExportedComment{}
+	mi := &file_proto_forum_v1_forum_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportedComment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportedComment) ProtoMessage() {}
+
+func (x *ExportedComment) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_forum_v1_forum_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportedComment.ProtoReflect.Descriptor instead.
+func (*ExportedComment) Descriptor() ([]byte, []int) {
+	return file_proto_forum_v1_forum_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *ExportedComment) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ExportedComment) GetForumId() string {
+	if x != nil {
+		return x.ForumId
+	}
+	return ""
+}
+
+func (x *ExportedComment) GetPostId() string {
+	if x != nil {
+		return x.PostId
+	}
+	return ""
+}
+
+func (x *ExportedComment) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *ExportedComment) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ExportedComment) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type ExportedChatMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ForumId       string                 `protobuf:"bytes,2,opt,name=forum_id,json=forumId,proto3" json:"forum_id,omitempty"`
+	Text          string                 `protobuf:"bytes,3,opt,name=text,proto3" json:"text,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportedChatMessage) Reset() {
+	*x = ExportedChatMessage{}
+	mi := &file_proto_forum_v1_forum_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportedChatMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportedChatMessage) ProtoMessage() {}
+
+func (x *ExportedChatMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_forum_v1_forum_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportedChatMessage.ProtoReflect.Descriptor instead.
+func (*ExportedChatMessage) Descriptor() ([]byte, []int) {
+	return file_proto_forum_v1_forum_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *ExportedChatMessage) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ExportedChatMessage) GetForumId() string {
+	if x != nil {
+		return x.ForumId
+	}
+	return ""
+}
+
+func (x *ExportedChatMessage) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *ExportedChatMessage) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+var File_proto_forum_v1_forum_proto protoreflect.FileDescriptor
+
+const file_proto_forum_v1_forum_proto_rawDesc = "" +
+	"\n" +
+	"\x1aproto/forum/v1/forum.proto\x12\bforum.v1\x1a google/protobuf/field_mask.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\x85\x01\n" +
+	"\x11CreatePostRequest\x12\x14\n" +
+	"\x05title\x18\x01 \x01(\tR\x05title\x12\x18\n" +
+	"\acontent\x18\x02 \x01(\tR\acontent\x12\x1f\n" +
+	"\vcategory_id\x18\x03 \x01(\tR\n" +
+	"categoryId\x12\x1f\n" +
+	"\tauthor_id\x18\x04 \x01(\tB\x02\x18\x01R\bauthorId\")\n" +
+	"\x0eGetPostRequest\x12\x17\n" +
+	"\apost_id\x18\x01 \x01(\tR\x06postId\"\x89\x01\n" +
+	"\x0fGetPostsRequest\x12\x1f\n" +
+	"\vcategory_id\x18\x03 \x01(\tR\n" +
+	"categoryId\x12\x1b\n" +
+	"\tpage_size\x18\x04 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x05 \x01(\tR\tpageTokenJ\x04\b\x01\x10\x02J\x04\b\x02\x10\x03R\x05limitR\x06offset\"\xd8\x01\n" +
+	"\x12StreamPostsRequest\x12\x1f\n" +
+	"\vcategory_id\x18\x01 \x01(\tR\n" +
+	"categoryId\x12?\n" +
+	"\rcreated_after\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\fcreatedAfter\x12A\n" +
+	"\x0ecreated_before\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\rcreatedBefore\x12\x1d\n" +
+	"\n" +
+	"batch_size\x18\x04 \x01(\x05R\tbatchSize\"C\n" +
+	"\x13StreamPostsResponse\x12,\n" +
+	"\x05posts\x18\x01 \x03(\v2\x16.forum.v1.PostResponseR\x05posts\"\x99\x01\n" +
+	"\x11UpdatePostRequest\x12\x17\n" +
+	"\apost_id\x18\x01 \x01(\tR\x06postId\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12\x18\n" +
+	"\acontent\x18\x03 \x01(\tR\acontent\x12;\n" +
+	"\vupdate_mask\x18\x04 \x01(\v2\x1a.google.protobuf.FieldMaskR\n" +
+	"updateMask\",\n" +
+	"\x11DeletePostRequest\x12\x17\n" +
+	"\apost_id\x18\x01 \x01(\tR\x06postId\"\x14\n" +
+	"\x12DeletePostResponse\"\xd6\x02\n" +
+	"\fPostResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12\x18\n" +
+	"\acontent\x18\x03 \x01(\tR\acontent\x12\x1b\n" +
+	"\tauthor_id\x18\x04 \x01(\tR\bauthorId\x12\x1f\n" +
+	"\vcategory_id\x18\x05 \x01(\tR\n" +
+	"categoryId\x120\n" +
+	"\x12created_at_rfc3339\x18\x06 \x01(\tB\x02\x18\x01R\x10createdAtRfc3339\x12\x1b\n" +
+	"\tis_pinned\x18\a \x01(\bR\bisPinned\x129\n" +
+	"\n" +
+	"created_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12!\n" +
+	"\fauthor_karma\x18\t \x01(\x05R\vauthorKarma\x12\x1b\n" +
+	"\tis_unread\x18\n" +
+	" \x01(\bR\bisUnread\"~\n" +
+	"\x10GetPostsResponse\x12,\n" +
+	"\x05posts\x18\x01 \x03(\v2\x16.forum.v1.PostResponseR\x05posts\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x05R\x05total\x12&\n" +
+	"\x0fnext_page_token\x18\x03 \x01(\tR\rnextPageToken\"j\n" +
+	"\x14CreateCommentRequest\x12\x17\n" +
+	"\apost_id\x18\x01 \x01(\tR\x06postId\x12\x18\n" +
+	"\acontent\x18\x02 \x01(\tR\acontent\x12\x1f\n" +
+	"\tauthor_id\x18\x03 \x01(\tB\x02\x18\x01R\bauthorId\"\x8c\x01\n" +
+	"\x14UpdateCommentRequest\x12\x1d\n" +
+	"\n" +
+	"comment_id\x18\x01 \x01(\tR\tcommentId\x12\x18\n" +
+	"\acontent\x18\x02 \x01(\tR\acontent\x12;\n" +
+	"\vupdate_mask\x18\x03 \x01(\v2\x1a.google.protobuf.FieldMaskR\n" +
+	"updateMask\"\x84\x01\n" +
+	"\x12GetCommentsRequest\x12\x17\n" +
+	"\apost_id\x18\x01 \x01(\tR\x06postId\x12\x1b\n" +
+	"\tpage_size\x18\x04 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x05 \x01(\tR\tpageTokenJ\x04\b\x02\x10\x03J\x04\b\x03\x10\x04R\x05limitR\x06offset\"\x81\x02\n" +
+	"\x0fCommentResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x18\n" +
+	"\acontent\x18\x02 \x01(\tR\acontent\x12\x17\n" +
+	"\apost_id\x18\x03 \x01(\tR\x06postId\x12\x1b\n" +
+	"\tauthor_id\x18\x04 \x01(\tR\bauthorId\x120\n" +
+	"\x12created_at_rfc3339\x18\x05 \x01(\tB\x02\x18\x01R\x10createdAtRfc3339\x129\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12!\n" +
+	"\fauthor_karma\x18\a \x01(\x05R\vauthorKarma\"\x8a\x01\n" +
+	"\x13GetCommentsResponse\x125\n" +
+	"\bcomments\x18\x01 \x03(\v2\x19.forum.v1.CommentResponseR\bcomments\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x05R\x05total\x12&\n" +
+	"\x0fnext_page_token\x18\x03 \x01(\tR\rnextPageToken\"o\n" +
+	"\x16GetChatMessagesRequest\x12\x1b\n" +
+	"\tpage_size\x18\x03 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x04 \x01(\tR\tpageTokenJ\x04\b\x01\x10\x02J\x04\b\x02\x10\x03R\x05limitR\x06offset\"S\n" +
+	"\x16SendChatMessageRequest\x12\x12\n" +
+	"\x04text\x18\x01 \x01(\tR\x04text\x12%\n" +
+	"\x0eattachment_ids\x18\x02 \x03(\tR\rattachmentIds\"\xb7\x01\n" +
+	"\vChatMessage\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x12\n" +
+	"\x04text\x18\x03 \x01(\tR\x04text\x120\n" +
+	"\x12created_at_rfc3339\x18\x04 \x01(\tB\x02\x18\x01R\x10createdAtRfc3339\x129\n" +
+	"\n" +
+	"created_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\x8a\x01\n" +
+	"\x17GetChatMessagesResponse\x121\n" +
+	"\bmessages\x18\x01 \x03(\v2\x15.forum.v1.ChatMessageR\bmessages\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x05R\x05total\x12&\n" +
+	"\x0fnext_page_token\x18\x03 \x01(\tR\rnextPageToken\"/\n" +
+	"\x14AnonymizeUserRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"n\n" +
+	"\x15AnonymizeUserResponse\x12\x14\n" +
+	"\x05posts\x18\x01 \x01(\x03R\x05posts\x12\x1a\n" +
+	"\bcomments\x18\x02 \x01(\x03R\bcomments\x12#\n" +
+	"\rchat_messages\x18\x03 \x01(\x03R\fchatMessages\"R\n" +
+	"\x18ExportUserContentRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1d\n" +
+	"\n" +
+	"batch_size\x18\x02 \x01(\x05R\tbatchSize\"\xc4\x01\n" +
+	"\x19ExportUserContentResponse\x12,\n" +
+	"\x05posts\x18\x01 \x03(\v2\x16.forum.v1.ExportedPostR\x05posts\x125\n" +
+	"\bcomments\x18\x02 \x03(\v2\x19.forum.v1.ExportedCommentR\bcomments\x12B\n" +
+	"\rchat_messages\x18\x03 \x03(\v2\x1d.forum.v1.ExportedChatMessageR\fchatMessages\"\xdd\x01\n" +
+	"\fExportedPost\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x19\n" +
+	"\bforum_id\x18\x02 \x01(\tR\aforumId\x12\x14\n" +
+	"\x05title\x18\x03 \x01(\tR\x05title\x12\x18\n" +
+	"\acontent\x18\x04 \x01(\tR\acontent\x12\x1f\n" +
+	"\vcategory_id\x18\x05 \x01(\tR\n" +
+	"categoryId\x12\x16\n" +
+	"\x06status\x18\x06 \x01(\tR\x06status\x129\n" +
+	"\n" +
+	"created_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\xc2\x01\n" +
+	"\x0fExportedComment\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x19\n" +
+	"\bforum_id\x18\x02 \x01(\tR\aforumId\x12\x17\n" +
+	"\apost_id\x18\x03 \x01(\tR\x06postId\x12\x18\n" +
+	"\acontent\x18\x04 \x01(\tR\acontent\x12\x16\n" +
+	"\x06status\x18\x05 \x01(\tR\x06status\x129\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\x8f\x01\n" +
+	"\x13ExportedChatMessage\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x19\n" +
+	"\bforum_id\x18\x02 \x01(\tR\aforumId\x12\x12\n" +
+	"\x04text\x18\x03 \x01(\tR\x04text\x129\n" +
+	"\n" +
+	"created_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt2\xe5\a\n" +
+	"\fForumService\x12A\n" +
+	"\n" +
+	"CreatePost\x12\x1b.forum.v1.CreatePostRequest\x1a\x16.forum.v1.PostResponse\x12;\n" +
+	"\aGetPost\x12\x18.forum.v1.GetPostRequest\x1a\x16.forum.v1.PostResponse\x12A\n" +
+	"\bGetPosts\x12\x19.forum.v1.GetPostsRequest\x1a\x1a.forum.v1.GetPostsResponse\x12A\n" +
+	"\n" +
+	"UpdatePost\x12\x1b.forum.v1.UpdatePostRequest\x1a\x16.forum.v1.PostResponse\x12G\n" +
+	"\n" +
+	"DeletePost\x12\x1b.forum.v1.DeletePostRequest\x1a\x1c.forum.v1.DeletePostResponse\x12L\n" +
+	"\vStreamPosts\x12\x1c.forum.v1.StreamPostsRequest\x1a\x1d.forum.v1.StreamPostsResponse0\x01\x12J\n" +
+	"\rCreateComment\x12\x1e.forum.v1.CreateCommentRequest\x1a\x19.forum.v1.CommentResponse\x12J\n" +
+	"\vGetComments\x12\x1c.forum.v1.GetCommentsRequest\x1a\x1d.forum.v1.GetCommentsResponse\x12J\n" +
+	"\rUpdateComment\x12\x1e.forum.v1.UpdateCommentRequest\x1a\x19.forum.v1.CommentResponse\x12V\n" +
+	"\x0fGetChatMessages\x12 .forum.v1.GetChatMessagesRequest\x1a!.forum.v1.GetChatMessagesResponse\x12J\n" +
+	"\x0fSendChatMessage\x12 .forum.v1.SendChatMessageRequest\x1a\x15.forum.v1.ChatMessage\x12P\n" +
+	"\rAnonymizeUser\x12\x1e.forum.v1.AnonymizeUserRequest\x1a\x1f.forum.v1.AnonymizeUserResponse\x12^\n" +
+	"\x11ExportUserContent\x12\".forum.v1.ExportUserContentRequest\x1a#.forum.v1.ExportUserContentResponse0\x01B2Z0github.com/kprf42/dolgova/proto/forum/v1;forumv1b\x06proto3"
+
+var (
+	file_proto_forum_v1_forum_proto_rawDescOnce sync.Once
+	file_proto_forum_v1_forum_proto_rawDescData []byte
+)
+
+func file_proto_forum_v1_forum_proto_rawDescGZIP() []byte {
+	file_proto_forum_v1_forum_proto_rawDescOnce.Do(func() {
+		file_proto_forum_v1_forum_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_forum_v1_forum_proto_rawDesc), len(file_proto_forum_v1_forum_proto_rawDesc)))
+	})
+	return file_proto_forum_v1_forum_proto_rawDescData
+}
+
+var file_proto_forum_v1_forum_proto_msgTypes = make([]protoimpl.MessageInfo, 26)
+var file_proto_forum_v1_forum_proto_goTypes = []any{
+	(*CreatePostRequest)(nil),         // 0: forum.v1.CreatePostRequest
+	(*GetPostRequest)(nil),            // 1: forum.v1.GetPostRequest
+	(*GetPostsRequest)(nil),           // 2: forum.v1.GetPostsRequest
+	(*StreamPostsRequest)(nil),        // 3: forum.v1.StreamPostsRequest
+	(*StreamPostsResponse)(nil),       // 4: forum.v1.StreamPostsResponse
+	(*UpdatePostRequest)(nil),         // 5: forum.v1.UpdatePostRequest
+	(*DeletePostRequest)(nil),         // 6: forum.v1.DeletePostRequest
+	(*DeletePostResponse)(nil),        // 7: forum.v1.DeletePostResponse
+	(*PostResponse)(nil),              // 8: forum.v1.PostResponse
+	(*GetPostsResponse)(nil),          // 9: forum.v1.GetPostsResponse
+	(*CreateCommentRequest)(nil),      // 10: forum.v1.CreateCommentRequest
+	(*UpdateCommentRequest)(nil),      // 11: forum.v1.UpdateCommentRequest
+	(*GetCommentsRequest)(nil),        // 12: forum.v1.GetCommentsRequest
+	(*CommentResponse)(nil),           // 13: forum.v1.CommentResponse
+	(*GetCommentsResponse)(nil),       // 14: forum.v1.GetCommentsResponse
+	(*GetChatMessagesRequest)(nil),    // 15: forum.v1.GetChatMessagesRequest
+	(*SendChatMessageRequest)(nil),    // 16: forum.v1.SendChatMessageRequest
+	(*ChatMessage)(nil),               // 17: forum.v1.ChatMessage
+	(*GetChatMessagesResponse)(nil),   // 18: forum.v1.GetChatMessagesResponse
+	(*AnonymizeUserRequest)(nil),      // 19: forum.v1.AnonymizeUserRequest
+	(*AnonymizeUserResponse)(nil),     // 20: forum.v1.AnonymizeUserResponse
+	(*ExportUserContentRequest)(nil),  // 21: forum.v1.ExportUserContentRequest
+	(*ExportUserContentResponse)(nil), // 22: forum.v1.ExportUserContentResponse
+	(*ExportedPost)(nil),              // 23: forum.v1.ExportedPost
+	(*ExportedComment)(nil),           // 24: forum.v1.ExportedComment
+	(*ExportedChatMessage)(nil),       // 25: forum.v1.ExportedChatMessage
+	(*timestamppb.Timestamp)(nil),     // 26: google.protobuf.Timestamp
+	(*fieldmaskpb.FieldMask)(nil),     // 27: google.protobuf.FieldMask
+}
+var file_proto_forum_v1_forum_proto_depIdxs = []int32{
+	26, // 0: forum.v1.StreamPostsRequest.created_after:type_name -> google.protobuf.Timestamp
+	26, // 1: forum.v1.StreamPostsRequest.created_before:type_name -> google.protobuf.Timestamp
+	8,  // 2: forum.v1.StreamPostsResponse.posts:type_name -> forum.v1.PostResponse
+	27, // 3: forum.v1.UpdatePostRequest.update_mask:type_name -> google.protobuf.FieldMask
+	26, // 4: forum.v1.PostResponse.created_at:type_name -> google.protobuf.Timestamp
+	8,  // 5: forum.v1.GetPostsResponse.posts:type_name -> forum.v1.PostResponse
+	27, // 6: forum.v1.UpdateCommentRequest.update_mask:type_name -> google.protobuf.FieldMask
+	26, // 7: forum.v1.CommentResponse.created_at:type_name -> google.protobuf.Timestamp
+	13, // 8: forum.v1.GetCommentsResponse.comments:type_name -> forum.v1.CommentResponse
+	26, // 9: forum.v1.ChatMessage.created_at:type_name -> google.protobuf.Timestamp
+	17, // 10: forum.v1.GetChatMessagesResponse.messages:type_name -> forum.v1.ChatMessage
+	23, // 11: forum.v1.ExportUserContentResponse.posts:type_name -> forum.v1.ExportedPost
+	24, // 12: forum.v1.ExportUserContentResponse.comments:type_name -> forum.v1.ExportedComment
+	25, // 13: forum.v1.ExportUserContentResponse.chat_messages:type_name -> forum.v1.ExportedChatMessage
+	26, // 14: forum.v1.ExportedPost.created_at:type_name -> google.protobuf.Timestamp
+	26, // 15: forum.v1.ExportedComment.created_at:type_name -> google.protobuf.Timestamp
+	26, // 16: forum.v1.ExportedChatMessage.created_at:type_name -> google.protobuf.Timestamp
+	0,  // 17: forum.v1.ForumService.CreatePost:input_type -> forum.v1.CreatePostRequest
+	1,  // 18: forum.v1.ForumService.GetPost:input_type -> forum.v1.GetPostRequest
+	2,  // 19: forum.v1.ForumService.GetPosts:input_type -> forum.v1.GetPostsRequest
+	5,  // 20: forum.v1.ForumService.UpdatePost:input_type -> forum.v1.UpdatePostRequest
+	6,  // 21: forum.v1.ForumService.DeletePost:input_type -> forum.v1.DeletePostRequest
+	3,  // 22: forum.v1.ForumService.StreamPosts:input_type -> forum.v1.StreamPostsRequest
+	10, // 23: forum.v1.ForumService.CreateComment:input_type -> forum.v1.CreateCommentRequest
+	12, // 24: forum.v1.ForumService.GetComments:input_type -> forum.v1.GetCommentsRequest
+	11, // 25: forum.v1.ForumService.UpdateComment:input_type -> forum.v1.UpdateCommentRequest
+	15, // 26: forum.v1.ForumService.GetChatMessages:input_type -> forum.v1.GetChatMessagesRequest
+	16, // 27: forum.v1.ForumService.SendChatMessage:input_type -> forum.v1.SendChatMessageRequest
+	19, // 28: forum.v1.ForumService.AnonymizeUser:input_type -> forum.v1.AnonymizeUserRequest
+	21, // 29: forum.v1.ForumService.ExportUserContent:input_type -> forum.v1.ExportUserContentRequest
+	8,  // 30: forum.v1.ForumService.CreatePost:output_type -> forum.v1.PostResponse
+	8,  // 31: forum.v1.ForumService.GetPost:output_type -> forum.v1.PostResponse
+	9,  // 32: forum.v1.ForumService.GetPosts:output_type -> forum.v1.GetPostsResponse
+	8,  // 33: forum.v1.ForumService.UpdatePost:output_type -> forum.v1.PostResponse
+	7,  // 34: forum.v1.ForumService.DeletePost:output_type -> forum.v1.DeletePostResponse
+	4,  // 35: forum.v1.ForumService.StreamPosts:output_type -> forum.v1.StreamPostsResponse
+	13, // 36: forum.v1.ForumService.CreateComment:output_type -> forum.v1.CommentResponse
+	14, // 37: forum.v1.ForumService.GetComments:output_type -> forum.v1.GetCommentsResponse
+	13, // 38: forum.v1.ForumService.UpdateComment:output_type -> forum.v1.CommentResponse
+	18, // 39: forum.v1.ForumService.GetChatMessages:output_type -> forum.v1.GetChatMessagesResponse
+	17, // 40: forum.v1.ForumService.SendChatMessage:output_type -> forum.v1.ChatMessage
+	20, // 41: forum.v1.ForumService.AnonymizeUser:output_type -> forum.v1.AnonymizeUserResponse
+	22, // 42: forum.v1.ForumService.ExportUserContent:output_type -> forum.v1.ExportUserContentResponse
+	30, // [30:43] is the sub-list for method output_type
+	17, // [17:30] is the sub-list for method input_type
+	17, // [17:17] is the sub-list for extension type_name
+	17, // [17:17] is the sub-list for extension extendee
+	0,  // [0:17] is the sub-list for field type_name
+}
+
+func init() { file_proto_forum_v1_forum_proto_init() }
+func file_proto_forum_v1_forum_proto_init() {
+	if File_proto_forum_v1_forum_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_forum_v1_forum_proto_rawDesc), len(file_proto_forum_v1_forum_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   26,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_proto_forum_v1_forum_proto_goTypes,
+		DependencyIndexes: file_proto_forum_v1_forum_proto_depIdxs,
+		MessageInfos:      file_proto_forum_v1_forum_proto_msgTypes,
+	}.Build()
+	File_proto_forum_v1_forum_proto = out.File
+	file_proto_forum_v1_forum_proto_goTypes = nil
+	file_proto_forum_v1_forum_proto_depIdxs = nil
+}